@@ -11,6 +11,23 @@ type DecisionMakerConfig struct {
 	Logging LoggingConfig `mapstructure:"logging"`
 	Token   TokenConfig   `mapstructure:"token"`
 	MTLS    MTLSConfig    `mapstructure:"mtls"`
+
+	IntentExport  IntentExportConfig  `mapstructure:"intent_export"`
+	MetricHistory MetricHistoryConfig `mapstructure:"metric_history"`
+}
+
+// MetricHistoryConfig bounds the in-memory history of metric sets received from the scheduler
+type MetricHistoryConfig struct {
+	RetentionSec int `mapstructure:"retention_sec"` // samples older than this are dropped
+	MaxSamples   int `mapstructure:"max_samples"`   // capacity of the ring buffer
+}
+
+// IntentExportConfig controls publishing the resolved scheduling intents to a local
+// binary file (see pkg/intenttable) for the scheduler running on the same host
+type IntentExportConfig struct {
+	Enable             bool   `mapstructure:"enable"`
+	Path               string `mapstructure:"path"`
+	RefreshIntervalSec int    `mapstructure:"refresh_interval_sec"` // how often /proc is re-scanned to follow process churn
 }
 
 var (
@@ -51,4 +68,15 @@ func InitDMConfig(configName string, configPath string) (DecisionMakerConfig, er
 type TokenConfig struct {
 	RsaPrivateKeyPem SecretValue `mapstructure:"rsa_private_key_pem"`
 	TokenDurationHr  int         `mapstructure:"token_duration_hr"` // in hours
+	// Clients registers the identities allowed to request tokens. Each one authenticates with a client
+	// assertion signed by its private key and gets a token limited to its scopes. Without clients the
+	// public key of rsa_private_key_pem is accepted instead and grants every scope.
+	Clients []TokenClientConfig `mapstructure:"clients"`
+}
+
+// TokenClientConfig is a client identity of the decision maker token endpoint
+type TokenClientConfig struct {
+	ClientID     string   `mapstructure:"client_id"`
+	PublicKeyPem string   `mapstructure:"public_key_pem"`
+	Scopes       []string `mapstructure:"scopes"` // intents:read, intents:write, metrics:read, metrics:write, pods:read
 }
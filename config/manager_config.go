@@ -5,7 +5,9 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
+	"github.com/Gthulhu/api/pkg/certreload"
 	"github.com/spf13/viper"
 )
 
@@ -19,8 +21,59 @@ func (s SecretValue) Value() string {
 	return string(s)
 }
 
+// ServerConfig is the address a service listens on. The timeouts, body limit, TLS, CORS and
+// security headers are applied by the manager API, the decision maker is secured by MTLSConfig.
 type ServerConfig struct {
-	Host string `mapstructure:"host"`
+	Host                 string `mapstructure:"host"`
+	ReadTimeoutSec       int    `mapstructure:"read_timeout_sec"`
+	ReadHeaderTimeoutSec int    `mapstructure:"read_header_timeout_sec"`
+	WriteTimeoutSec      int    `mapstructure:"write_timeout_sec"`
+	IdleTimeoutSec       int    `mapstructure:"idle_timeout_sec"`
+	// BodyLimit caps request bodies, for example "4M"
+	BodyLimit string `mapstructure:"body_limit"`
+	// ContentSecurityPolicy is sent with every response when set
+	ContentSecurityPolicy string `mapstructure:"content_security_policy"`
+	// TrustedProxies lists the CIDRs of reverse proxies whose X-Forwarded-For header names the
+	// client IP, without any the peer address of the connection is the client IP
+	TrustedProxies []string        `mapstructure:"trusted_proxies"`
+	TLS            ServerTLSConfig `mapstructure:"tls"`
+	CORS           CORSConfig      `mapstructure:"cors"`
+}
+
+// ServerTLSConfig serves HTTPS with a certificate reloaded from CertFile and KeyFile every
+// ReloadIntervalSec. ClientAuth is "none", "verify_if_given" or "require", client certificates are
+// verified with the CA of ClientCAFile or ClientCAPem.
+type ServerTLSConfig struct {
+	Enable            bool        `mapstructure:"enable"`
+	CertPem           SecretValue `mapstructure:"cert_pem"`
+	KeyPem            SecretValue `mapstructure:"key_pem"`
+	CertFile          string      `mapstructure:"cert_file"`
+	KeyFile           string      `mapstructure:"key_file"`
+	ClientAuth        string      `mapstructure:"client_auth"`
+	ClientCAPem       SecretValue `mapstructure:"client_ca_pem"`
+	ClientCAFile      string      `mapstructure:"client_ca_file"`
+	ReloadIntervalSec int         `mapstructure:"reload_interval_sec"`
+}
+
+// CertSource returns where the certificate, key and client CA bundle are loaded from
+func (c ServerTLSConfig) CertSource() certreload.Source {
+	return certreload.Source{
+		CertPEM:    c.CertPem.Value(),
+		KeyPEM:     c.KeyPem.Value(),
+		CAPEM:      c.ClientCAPem.Value(),
+		CertFile:   c.CertFile,
+		KeyFile:    c.KeyFile,
+		CAFile:     c.ClientCAFile,
+		CAOptional: c.ClientAuth == "" || c.ClientAuth == "none",
+	}
+}
+
+// CORSConfig allows the web UI served from AllowOrigins to call the API, CORS is disabled without
+// origins
+type CORSConfig struct {
+	AllowOrigins     []string `mapstructure:"allow_origins"`
+	AllowCredentials bool     `mapstructure:"allow_credentials"`
+	MaxAgeSec        int      `mapstructure:"max_age_sec"`
 }
 
 type LoggingConfig struct {
@@ -30,24 +83,159 @@ type LoggingConfig struct {
 }
 
 type ManageConfig struct {
-	Server  ServerConfig  `mapstructure:"server"`
-	Logging LoggingConfig `mapstructure:"logging"`
-	MongoDB MongoDBConfig `mapstructure:"mongodb"`
-	Key     KeyConfig     `mapstructure:"key"`
-	Account AccountConfig `mapstructure:"account"`
-	K8S     K8SConfig     `mapstructure:"k8s"`
-	MTLS    MTLSConfig    `mapstructure:"mtls"`
+	Server          ServerConfig          `mapstructure:"server"`
+	Logging         LoggingConfig         `mapstructure:"logging"`
+	MongoDB         MongoDBConfig         `mapstructure:"mongodb"`
+	Key             KeyConfig             `mapstructure:"key"`
+	Account         AccountConfig         `mapstructure:"account"`
+	Auth            AuthConfig            `mapstructure:"auth"`
+	OIDC            OIDCConfig            `mapstructure:"oidc"`
+	PasswordPolicy  PasswordPolicyConfig  `mapstructure:"password_policy"`
+	LoginProtection LoginProtectionConfig `mapstructure:"login_protection"`
+	TwoFactor       TwoFactorConfig       `mapstructure:"two_factor"`
+	AuthCache       AuthCacheConfig       `mapstructure:"auth_cache"`
+	K8S             K8SConfig             `mapstructure:"k8s"`
+	MTLS            MTLSConfig            `mapstructure:"mtls"`
+	DMClient        DMClientConfig        `mapstructure:"dm_client"`
+	MetricStore     MetricStoreConfig     `mapstructure:"metric_store"`
+	Alerting        AlertingConfig        `mapstructure:"alerting"`
+	EventWebhook    EventWebhookConfig    `mapstructure:"event_webhook"`
+	AuditStream     AuditStreamConfig     `mapstructure:"audit_stream"`
+	Metrics         MetricsConfig         `mapstructure:"metrics"`
+}
+
+// MetricsConfig serves the Prometheus metrics of the manager on /metrics of a separate listener,
+// so that they aren't reachable through the API
+type MetricsConfig struct {
+	Enable bool   `mapstructure:"enable"`
+	Host   string `mapstructure:"host"`
 }
 
 // MTLSConfig holds the mutual TLS configuration used for Manager ↔ Decision Maker communication.
 // CertPem and KeyPem are the service's own certificate/key pair signed by the private CA.
 // CAPem is the private CA certificate used to verify the peer's certificate.
+// CertFile, KeyFile and CAFile take precedence over the PEM strings and are re-read every
+// ReloadIntervalSec, so that rotated certificates are used without a restart.
 type MTLSConfig struct {
-	Enable     bool        `mapstructure:"enable"`
-	ServerName string      `mapstructure:"server_name"`
-	CertPem    SecretValue `mapstructure:"cert_pem"`
-	KeyPem     SecretValue `mapstructure:"key_pem"`
-	CAPem      SecretValue `mapstructure:"ca_pem"`
+	Enable            bool        `mapstructure:"enable"`
+	ServerName        string      `mapstructure:"server_name"`
+	CertPem           SecretValue `mapstructure:"cert_pem"`
+	KeyPem            SecretValue `mapstructure:"key_pem"`
+	CAPem             SecretValue `mapstructure:"ca_pem"`
+	CertFile          string      `mapstructure:"cert_file"`
+	KeyFile           string      `mapstructure:"key_file"`
+	CAFile            string      `mapstructure:"ca_file"`
+	ReloadIntervalSec int         `mapstructure:"reload_interval_sec"`
+	// ExpiryWarningSec is how long before the certificate expires the health check warns about it
+	ExpiryWarningSec int `mapstructure:"expiry_warning_sec"`
+}
+
+// CertSource returns where the certificate, key and CA bundle are loaded from
+func (c MTLSConfig) CertSource() certreload.Source {
+	return certreload.Source{
+		CertPEM:  c.CertPem.Value(),
+		KeyPEM:   c.KeyPem.Value(),
+		CAPEM:    c.CAPem.Value(),
+		CertFile: c.CertFile,
+		KeyFile:  c.KeyFile,
+		CAFile:   c.CAFile,
+	}
+}
+
+// DMClientConfig controls the requests of the manager to the decision makers. Idempotent requests
+// failing with a network error, 429 or 5xx are retried with jittered exponential backoff, and a node
+// failing BreakerFailureThreshold requests in a row is not called for BreakerOpenSec.
+type DMClientConfig struct {
+	TimeoutSec              int `mapstructure:"timeout_sec"`
+	MaxAttempts             int `mapstructure:"max_attempts"`
+	InitialBackoffMs        int `mapstructure:"initial_backoff_ms"`
+	BreakerFailureThreshold int `mapstructure:"breaker_failure_threshold"`
+	BreakerOpenSec          int `mapstructure:"breaker_open_sec"`
+}
+
+// MetricStoreConfig controls persisting the scheduler metrics of every decision maker to the
+// metric_samples MongoDB time series collection
+type MetricStoreConfig struct {
+	Enable             bool `mapstructure:"enable"`
+	CollectIntervalSec int  `mapstructure:"collect_interval_sec"`
+	RetentionSec       int  `mapstructure:"retention_sec"`
+}
+
+// DefaultMetricCollectInterval is used when CollectIntervalSec is not set
+const DefaultMetricCollectInterval = 15 * time.Second
+
+// CollectInterval returns how often the metrics of the decision makers are stored
+func (c MetricStoreConfig) CollectInterval() time.Duration {
+	if c.CollectIntervalSec <= 0 {
+		return DefaultMetricCollectInterval
+	}
+	return time.Duration(c.CollectIntervalSec) * time.Second
+}
+
+// AlertingConfig controls the periodic evaluation of alert rules and the delivery of their webhooks
+type AlertingConfig struct {
+	Enable              bool `mapstructure:"enable"`
+	EvaluateIntervalSec int  `mapstructure:"evaluate_interval_sec"`
+	WebhookTimeoutSec   int  `mapstructure:"webhook_timeout_sec"`
+	WebhookMaxAttempts  int  `mapstructure:"webhook_max_attempts"`
+}
+
+// DefaultAlertEvaluateInterval is used when EvaluateIntervalSec is not set
+const DefaultAlertEvaluateInterval = 15 * time.Second
+
+// EvaluateInterval returns how often the alert rules are evaluated
+func (c AlertingConfig) EvaluateInterval() time.Duration {
+	if c.EvaluateIntervalSec <= 0 {
+		return DefaultAlertEvaluateInterval
+	}
+	return time.Duration(c.EvaluateIntervalSec) * time.Second
+}
+
+// EventWebhookConfig controls the delivery of strategy and intent lifecycle events to webhook subscriptions
+type EventWebhookConfig struct {
+	Enable      bool `mapstructure:"enable"`
+	TimeoutSec  int  `mapstructure:"timeout_sec"`
+	MaxAttempts int  `mapstructure:"max_attempts"`
+}
+
+// AuditStreamConfig controls streaming audit logs to external sinks in addition to MongoDB
+type AuditStreamConfig struct {
+	Enable bool `mapstructure:"enable"`
+	// SpoolDir keeps the audit logs not delivered yet, it should be a persistent volume so that
+	// they're delivered after a restart
+	SpoolDir        string                  `mapstructure:"spool_dir"`
+	SpoolSegmentMB  int                     `mapstructure:"spool_segment_mb"`
+	BatchSize       int                     `mapstructure:"batch_size"`
+	FlushIntervalMs int                     `mapstructure:"flush_interval_ms"`
+	File            AuditStreamFileConfig   `mapstructure:"file"`
+	Syslog          AuditStreamSyslogConfig `mapstructure:"syslog"`
+	HTTP            AuditStreamHTTPConfig   `mapstructure:"http"`
+}
+
+// AuditStreamFileConfig writes audit logs as JSON lines to a rotating file
+type AuditStreamFileConfig struct {
+	Enable     bool   `mapstructure:"enable"`
+	Path       string `mapstructure:"path"`
+	MaxSizeMB  int    `mapstructure:"max_size_mb"`
+	MaxBackups int    `mapstructure:"max_backups"`
+}
+
+// AuditStreamSyslogConfig sends audit logs as RFC 5424 messages to a syslog server over tcp or udp
+type AuditStreamSyslogConfig struct {
+	Enable     bool   `mapstructure:"enable"`
+	Network    string `mapstructure:"network"`
+	Address    string `mapstructure:"address"`
+	AppName    string `mapstructure:"app_name"`
+	Facility   string `mapstructure:"facility"`
+	TimeoutSec int    `mapstructure:"timeout_sec"`
+}
+
+// AuditStreamHTTPConfig posts batches of audit logs to an HTTP collector
+type AuditStreamHTTPConfig struct {
+	Enable     bool              `mapstructure:"enable"`
+	URL        string            `mapstructure:"url"`
+	Headers    map[string]string `mapstructure:"headers"`
+	TimeoutSec int               `mapstructure:"timeout_sec"`
 }
 
 type MongoDBConfig struct {
@@ -69,6 +257,15 @@ type KeyConfig struct {
 	RsaPrivateKeyPem SecretValue `mapstructure:"rsa_private_key_pem"`
 	DMPublicKeyPem   SecretValue `mapstructure:"dm_public_key_pem"`
 	ClientID         string      `mapstructure:"client_id"`
+	// DMClientPrivateKeyPem is the private key of ClientID registered with the decision makers, their
+	// tokens are requested with a client assertion signed by it instead of DMPublicKeyPem when set
+	DMClientPrivateKeyPem SecretValue `mapstructure:"dm_client_private_key_pem"`
+	// JWTKeyDir holds additional JWT signing keys named <kid>.pem and an optional active_kid file
+	// naming the key new tokens are signed with, rsa_private_key_pem signs when it's missing. Key IDs
+	// listed in an optional retired_kids file, rsa_private_key_pem's included, are no longer accepted.
+	// The directory is re-read every JWTKeyReloadIntervalSec so that keys rotate without a restart.
+	JWTKeyDir               string `mapstructure:"jwt_key_dir"`
+	JWTKeyReloadIntervalSec int    `mapstructure:"jwt_key_reload_interval_sec"`
 }
 
 type AccountConfig struct {
@@ -76,6 +273,86 @@ type AccountConfig struct {
 	AdminPassword SecretValue `mapstructure:"admin_password"`
 }
 
+// AuthConfig controls the lifetime of the tokens issued on login
+type AuthConfig struct {
+	AccessTokenTTLSec  int `mapstructure:"access_token_ttl_sec"`
+	RefreshTokenTTLSec int `mapstructure:"refresh_token_ttl_sec"`
+}
+
+// PasswordPolicyConfig constrains the passwords of local users, zero lengths use the defaults
+type PasswordPolicyConfig struct {
+	MinLength     int  `mapstructure:"min_length"`
+	MaxLength     int  `mapstructure:"max_length"`
+	RequireUpper  bool `mapstructure:"require_upper"`
+	RequireLower  bool `mapstructure:"require_lower"`
+	RequireDigit  bool `mapstructure:"require_digit"`
+	RequireSymbol bool `mapstructure:"require_symbol"`
+	// HistorySize is the number of recent passwords, including the current one, that can't be reused
+	HistorySize int `mapstructure:"history_size"`
+}
+
+// LoginProtectionConfig throttles failed logins per user and per client IP, zero values use the
+// defaults
+type LoginProtectionConfig struct {
+	// MaxFailedAttempts consecutive failures lock the user for LockoutDurationSec
+	MaxFailedAttempts  int `mapstructure:"max_failed_attempts"`
+	LockoutDurationSec int `mapstructure:"lockout_duration_sec"`
+	// MaxFailedAttemptsPerIP failures within IPWindowSec reject further logins of the client
+	MaxFailedAttemptsPerIP int `mapstructure:"max_failed_attempts_per_ip"`
+	IPWindowSec            int `mapstructure:"ip_window_sec"`
+}
+
+// AuthCacheConfig configures the in-process cache of the users and roles every authenticated request
+// is authorized with, zero values use the defaults
+type AuthCacheConfig struct {
+	Enable     bool `mapstructure:"enable"`
+	UserTTLSec int  `mapstructure:"user_ttl_sec"`
+	RoleTTLSec int  `mapstructure:"role_ttl_sec"`
+	// SyncIntervalSec is how often the shared cache version is polled, a change made by another
+	// manager replica flushes the cache
+	SyncIntervalSec int `mapstructure:"sync_interval_sec"`
+}
+
+// TwoFactorConfig configures TOTP two-factor authentication
+type TwoFactorConfig struct {
+	Enable bool `mapstructure:"enable"`
+	// Issuer names the manager in authenticator apps
+	Issuer string `mapstructure:"issuer"`
+	// EncryptionKey is the base64 encoded 32 byte AES key TOTP secrets are stored with, it's
+	// required when two-factor authentication is enabled
+	EncryptionKey SecretValue `mapstructure:"encryption_key"`
+}
+
+// OIDCConfig configures single sign-on with an OpenID provider using the authorization code flow
+// with PKCE. Users are provisioned on their first login and get the roles mapped from their claims
+// on every login.
+type OIDCConfig struct {
+	Enable       bool        `mapstructure:"enable"`
+	IssuerURL    string      `mapstructure:"issuer_url"`
+	ClientID     string      `mapstructure:"client_id"`
+	ClientSecret SecretValue `mapstructure:"client_secret"`
+	// RedirectURL is the manager callback registered at the provider, /api/v1/auth/oidc/callback
+	RedirectURL string   `mapstructure:"redirect_url"`
+	Scopes      []string `mapstructure:"scopes"`
+	// UsernameClaim names the claim used as username, the subject is used when it's missing
+	UsernameClaim string `mapstructure:"username_claim"`
+	// GroupsClaim is the default claim of RoleMappings
+	GroupsClaim  string            `mapstructure:"groups_claim"`
+	RoleMappings []OIDCRoleMapping `mapstructure:"role_mappings"`
+	DefaultRoles []string          `mapstructure:"default_roles"`
+	// PostLoginRedirectURL receives the access token in the token query parameter after a login,
+	// the token pair is returned as JSON when empty
+	PostLoginRedirectURL string `mapstructure:"post_login_redirect_url"`
+	LoginTimeoutSec      int    `mapstructure:"login_timeout_sec"`
+}
+
+// OIDCRoleMapping grants Roles to users whose Claim equals or contains Value
+type OIDCRoleMapping struct {
+	Claim string   `mapstructure:"claim"`
+	Value string   `mapstructure:"value"`
+	Roles []string `mapstructure:"roles"`
+}
+
 type K8SConfig struct {
 	KubeConfigPath string `mapstructure:"kube_config_path"`
 	IsInCluster    bool   `mapstructure:"in_cluster"`
@@ -0,0 +1,57 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/decisionmaker/service"
+	"github.com/Gthulhu/api/pkg/logger"
+	"go.uber.org/fx"
+)
+
+const defaultIntentExportRefreshInterval = 5 * time.Second
+
+// StartIntentExport periodically re-resolves the cached intents when the local intent export is enabled,
+// so the exported table is published at startup and tracks processes starting and exiting
+func StartIntentExport(lc fx.Lifecycle, cfg config.IntentExportConfig, svc *service.Service) {
+	if !cfg.Enable {
+		return
+	}
+	interval := time.Duration(cfg.RefreshIntervalSec) * time.Second
+	if interval <= 0 {
+		interval = defaultIntentExportRefreshInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(startCtx context.Context) error {
+			logger.Logger(startCtx).Info().Msgf("exporting scheduling intents to %s every %s", cfg.Path, interval)
+			go func() {
+				defer close(done)
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					if err := svc.RefreshIntentExport(ctx); err != nil {
+						logger.Logger(ctx).Warn().Err(err).Msg("failed to refresh exported scheduling intents")
+					}
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
@@ -22,12 +22,19 @@ func ConfigModule(cfg config.DecisionMakerConfig) (fx.Option, error) {
 		fx.Provide(func(dmCfg config.DecisionMakerConfig) config.MTLSConfig {
 			return dmCfg.MTLS
 		}),
+		fx.Provide(func(dmCfg config.DecisionMakerConfig) config.IntentExportConfig {
+			return dmCfg.IntentExport
+		}),
+		fx.Provide(func(dmCfg config.DecisionMakerConfig) config.MetricHistoryConfig {
+			return dmCfg.MetricHistory
+		}),
 	), nil
 }
 
 func ServiceModule() (fx.Option, error) {
 	return fx.Options(
 		fx.Provide(service.NewService),
+		fx.Invoke(StartIntentExport),
 	), nil
 }
 
@@ -35,6 +42,7 @@ func ServiceModule() (fx.Option, error) {
 func HandlerModule(opt fx.Option) (fx.Option, error) {
 	return fx.Options(
 		opt,
+		fx.Provide(NewMTLSCertificates),
 		fx.Provide(rest.NewHandler),
 	), nil
 }
@@ -0,0 +1,26 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/pkg/certreload"
+	"go.uber.org/fx"
+)
+
+// NewMTLSCertificates returns the server certificate the decision maker presents to the manager,
+// nil when mTLS is disabled. Certificate files are re-read every ReloadIntervalSec.
+func NewMTLSCertificates(lc fx.Lifecycle, cfg config.MTLSConfig) (*certreload.Reloader, error) {
+	if !cfg.Enable {
+		return nil, nil
+	}
+	certs, err := certreload.New("decision-maker", cfg.CertSource())
+	if err != nil {
+		return nil, fmt.Errorf("load mTLS server certificate: %w", err)
+	}
+	if err := certreload.RegisterAndRun(lc, certs, "mTLS", time.Duration(cfg.ReloadIntervalSec)*time.Second); err != nil {
+		return nil, err
+	}
+	return certs, nil
+}
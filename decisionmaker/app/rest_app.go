@@ -3,12 +3,12 @@ package app
 import (
 	"context"
 	"crypto/tls"
-	"crypto/x509"
 	"fmt"
 	"net"
 
 	"github.com/Gthulhu/api/config"
 	"github.com/Gthulhu/api/decisionmaker/rest"
+	"github.com/Gthulhu/api/pkg/certreload"
 	"github.com/Gthulhu/api/pkg/logger"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/fx"
@@ -36,7 +36,7 @@ func NewRestApp(configName string, configDirPath string) (*fx.App, error) {
 	return app, nil
 }
 
-func StartRestApp(lc fx.Lifecycle, cfg config.ServerConfig, mtlsCfg config.MTLSConfig, handler *rest.Handler) error {
+func StartRestApp(lc fx.Lifecycle, cfg config.ServerConfig, certs *certreload.Reloader, handler *rest.Handler) error {
 	engine := echo.New()
 	if err := handler.SetupRoutes(engine); err != nil {
 		return err
@@ -49,8 +49,8 @@ func StartRestApp(lc fx.Lifecycle, cfg config.ServerConfig, mtlsCfg config.MTLSC
 				serverHost = ":8082"
 			}
 			go func() {
-				if mtlsCfg.Enable {
-					if err := startTLSServer(ctx, engine, serverHost, mtlsCfg); err != nil {
+				if certs != nil {
+					if err := startTLSServer(ctx, engine, serverHost, certs); err != nil {
 						logger.Logger(ctx).Fatal().Err(err).Msgf("start dm rest server with mTLS fail on port %s", serverHost)
 					}
 				} else {
@@ -72,34 +72,14 @@ func StartRestApp(lc fx.Lifecycle, cfg config.ServerConfig, mtlsCfg config.MTLSC
 }
 
 // startTLSServer starts the Echo server with mTLS: the server presents its own certificate and
-// requires the connecting client (Manager) to present a certificate signed by the shared CA.
-func startTLSServer(ctx context.Context, engine *echo.Echo, addr string, mtlsCfg config.MTLSConfig) error {
-	cert, err := tls.X509KeyPair([]byte(mtlsCfg.CertPem.Value()), []byte(mtlsCfg.KeyPem.Value()))
-	if err != nil {
-		return fmt.Errorf("load mTLS server certificate: %w", err)
-	}
-
-	caPool := x509.NewCertPool()
-	caPEM := mtlsCfg.CAPem.Value()
-	if caPEM == "" {
-		return fmt.Errorf("mTLS server CA PEM is empty; cannot configure client certificate validation")
-	}
-	if !caPool.AppendCertsFromPEM([]byte(caPEM)) {
-		return fmt.Errorf("no CA certificates found in mTLS server CA PEM; failed to parse CA bundle")
-	}
-
-	tlsCfg := &tls.Config{
-		Certificates: []tls.Certificate{cert},
-		ClientAuth:   tls.RequireAndVerifyClientCert,
-		ClientCAs:    caPool,
-		MinVersion:   tls.VersionTLS12,
-	}
-
+// requires the connecting client (Manager) to present a certificate signed by the shared CA. Both
+// are taken from certs on every handshake, so that reloaded certificates apply to new connections.
+func startTLSServer(ctx context.Context, engine *echo.Echo, addr string, certs *certreload.Reloader) error {
 	ln, err := net.Listen("tcp", addr)
 	if err != nil {
 		return fmt.Errorf("create listener: %w", err)
 	}
-	tlsListener := tls.NewListener(ln, tlsCfg)
+	tlsListener := tls.NewListener(ln, certs.ServerTLSConfig(tls.RequireAndVerifyClientCert))
 	engine.Listener = tlsListener
 
 	logger.Logger(ctx).Info().Msgf("starting dm server with mTLS on port %s", addr)
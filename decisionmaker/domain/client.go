@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Scope is a permission a decision maker token grants to its client
+type Scope string
+
+const (
+	// ScopeIntentsRead reads the scheduling intents and their merkle root
+	ScopeIntentsRead Scope = "intents:read"
+	// ScopeIntentsWrite pushes and deletes scheduling intents
+	ScopeIntentsWrite Scope = "intents:write"
+	// ScopeMetricsRead reads the latest metrics and their history
+	ScopeMetricsRead Scope = "metrics:read"
+	// ScopeMetricsWrite posts the metrics of the scheduler
+	ScopeMetricsWrite Scope = "metrics:write"
+	// ScopePodsRead reads the processes of the pods on the node
+	ScopePodsRead Scope = "pods:read"
+)
+
+// AllScopes are granted to tokens of the legacy public key exchange
+var AllScopes = []Scope{ScopeIntentsRead, ScopeIntentsWrite, ScopeMetricsRead, ScopeMetricsWrite, ScopePodsRead}
+
+// ParseScope returns the scope named s
+func ParseScope(s string) (Scope, error) {
+	if !slices.Contains(AllScopes, Scope(s)) {
+		return "", fmt.Errorf("unknown scope %q", s)
+	}
+	return Scope(s), nil
+}
+
+const (
+	// ClientAssertionAudience is the audience of the client assertions registered clients request
+	// tokens with
+	ClientAssertionAudience = "gthulhu-decision-maker"
+	// MaxClientAssertionLifetime bounds how long a client assertion can be replayed
+	MaxClientAssertionLifetime = 5 * time.Minute
+)
+
+// Client is a registered identity that proves possession of the private key of PublicKey to get
+// tokens limited to Scopes
+type Client struct {
+	ID        string
+	PublicKey *rsa.PublicKey
+	Scopes    []Scope
+}
+
+// NewClientAssertion signs the assertion clientID requests a token with, a JWT issued by and about
+// the client for the decision maker
+func NewClientAssertion(clientID string, key *rsa.PrivateKey, now time.Time) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.RegisteredClaims{
+		Issuer:    clientID,
+		Subject:   clientID,
+		Audience:  jwt.ClaimStrings{ClientAssertionAudience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+	}).SignedString(key)
+}
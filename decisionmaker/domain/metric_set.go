@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 type MetricSet struct {
 	UserSchedLastRunAt uint64
 	NrQueued           uint64
@@ -12,4 +14,38 @@ type MetricSet struct {
 	NrBounceDispatches uint64
 	NrFailedDispatches uint64
 	NrSchedCongested   uint64
+
+	CPUs []CPUMetrics // optional per-CPU counters
+	Pods []PodMetrics // optional per-pod dispatch and queue stats
+}
+
+// CPUMetrics are the scheduler stats of a single CPU
+type CPUMetrics struct {
+	CPU                int
+	NrQueued           uint64
+	NrUserDispatches   uint64 // monotonic
+	NrKernelDispatches uint64 // monotonic
+}
+
+// PodMetrics are the scheduler stats of a pod. The scheduler identifies the pod by its UID
+// or by a cgroup path of one of its tasks; the remaining identity fields are resolved by the
+// decision maker from its intent cache.
+type PodMetrics struct {
+	PodUID     string
+	CgroupPath string
+
+	NrQueued     uint64
+	NrDispatches uint64 // monotonic
+	QueueWaitNs  uint64 // monotonic, total time tasks waited in the queue
+	RunTimeNs    uint64 // monotonic, total time tasks ran on a CPU
+
+	PodName      string
+	K8sNamespace string
+	StrategyID   string
+}
+
+// MetricSample is a MetricSet received from the scheduler at Timestamp
+type MetricSample struct {
+	Timestamp time.Time
+	MetricSet
 }
@@ -4,6 +4,8 @@ package domain
 type PodProcess struct {
 	PID         int    `json:"pid"`
 	Command     string `json:"command"`
+	Cmdline     string `json:"cmdline,omitempty"`
+	Exe         string `json:"exe,omitempty"`
 	PPID        int    `json:"ppid,omitempty"`
 	ContainerID string `json:"container_id,omitempty"`
 }
@@ -22,20 +24,80 @@ type Intent struct {
 	NodeID        string            `json:"nodeID,omitempty"`
 	K8sNamespace  string            `json:"k8sNamespace,omitempty"`
 	CommandRegex  string            `json:"commandRegex,omitempty"`
+	ThreadRegex   string            `json:"threadRegex,omitempty"` // If set, only threads whose comm matches are scheduled
 	Priority      int               `json:"priority,omitempty"`
 	ExecutionTime int64             `json:"executionTime,omitempty"`
 	PodLabels     map[string]string `json:"podLabels,omitempty"`
+	// CommandMatchTargets lists the process attributes CommandRegex is matched against
+	// (see the CommandMatch* constants); empty means DefaultCommandMatchTargets
+	CommandMatchTargets []string          `json:"commandMatchTargets,omitempty"`
+	ContainerNames      map[string]string `json:"containerNames,omitempty"` // container ID -> container name
+	StrategyID          string            `json:"strategyID,omitempty"`     // strategy the intent was created from, used to label metrics
 }
 
+const (
+	CommandMatchComm          = "comm"          // /proc/<pid>/comm, truncated to 15 characters
+	CommandMatchCmdline       = "cmdline"       // full /proc/<pid>/cmdline joined by spaces
+	CommandMatchExe           = "exe"           // target of the /proc/<pid>/exe symlink
+	CommandMatchContainerName = "containerName" // name of the container the process runs in
+)
+
+// DefaultCommandMatchTargets is used for intents that do not specify any match targets
+var DefaultCommandMatchTargets = []string{CommandMatchComm}
+
 type SchedulingIntents struct {
 	Priority      int             `json:"priority"`                // Priority value; higher value means higher priority
 	ExecutionTime uint64          `json:"execution_time"`          // Time slice for this process in nanoseconds
 	PID           int             `json:"pid,omitempty"`           // Process ID to apply this strategy to
+	TID           int             `json:"tid,omitempty"`           // Thread ID to apply this strategy to; when set only this thread of PID is targeted
 	Selectors     []LabelSelector `json:"selectors,omitempty"`     // Label selectors to match pods
 	CommandRegex  string          `json:"command_regex,omitempty"` // Regex to match process command
 }
 
+// ProcessThread represents a thread (task) of a process
+type ProcessThread struct {
+	TID     int    `json:"tid"`
+	Command string `json:"command"`
+}
+
 type LabelSelector struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
 }
+
+// ProcessOutcome describes how an intent was applied to a process
+type ProcessOutcome string
+
+const (
+	ProcessOutcomeMatched         ProcessOutcome = "matched"         // the process (or some of its threads) is scheduled
+	ProcessOutcomeSkippedPause    ProcessOutcome = "skippedPause"    // the pod sandbox pause process is never scheduled
+	ProcessOutcomeRegexMismatch   ProcessOutcome = "regexMismatch"   // CommandRegex matched none of the match targets
+	ProcessOutcomeNoThreadMatched ProcessOutcome = "noThreadMatched" // ThreadRegex matched none of the process threads
+)
+
+// ProcessExplanation reports the outcome of an intent for a single process
+type ProcessExplanation struct {
+	PID         int            `json:"pid"`
+	Command     string         `json:"command"`
+	ContainerID string         `json:"container_id,omitempty"`
+	Outcome     ProcessOutcome `json:"outcome"`
+	TIDs        []int          `json:"tids,omitempty"` // threads scheduled when the intent has a ThreadRegex
+}
+
+// IntentExplanation reports how a cached intent was resolved against the pod's processes
+type IntentExplanation struct {
+	CommandRegex  string               `json:"commandRegex,omitempty"`
+	ThreadRegex   string               `json:"threadRegex,omitempty"`
+	Priority      int                  `json:"priority"`
+	ExecutionTime int64                `json:"executionTime"`
+	Error         string               `json:"error,omitempty"` // set when the intent could not be evaluated, e.g. an invalid regex
+	Processes     []ProcessExplanation `json:"processes"`
+}
+
+// PodExplanation reports how the decision maker resolves the intents of a pod
+type PodExplanation struct {
+	PodID       string              `json:"podID"`
+	PodFound    bool                `json:"podFound"` // whether any process of the pod was found in /proc
+	ProcessPIDs []int               `json:"processPIDs"`
+	Intents     []IntentExplanation `json:"intents"`
+}
@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/decisionmaker/domain"
 	"github.com/Gthulhu/api/decisionmaker/service"
 	"github.com/Gthulhu/api/manager/errs"
+	"github.com/Gthulhu/api/pkg/certreload"
 	"github.com/Gthulhu/api/pkg/logger"
 	"github.com/Gthulhu/api/pkg/middleware"
 	"github.com/labstack/echo/v4"
@@ -34,9 +36,10 @@ type VersionResponse struct {
 
 // HealthResponse describes the health check payload.
 type HealthResponse struct {
-	Status    string `json:"status"`
-	Timestamp string `json:"timestamp"`
-	Service   string `json:"service"`
+	Status    string   `json:"status"`
+	Timestamp string   `json:"timestamp"`
+	Service   string   `json:"service"`
+	Warnings  []string `json:"warnings,omitempty"`
 }
 
 func NewSuccessResponse[T any](data *T) SuccessResponse[T] {
@@ -58,18 +61,25 @@ type Params struct {
 	fx.In
 	Service     *service.Service
 	TokenConfig config.TokenConfig
+	MTLSConfig  config.MTLSConfig
+	MTLSCerts   *certreload.Reloader `optional:"true"`
 }
 
 func NewHandler(params Params) (*Handler, error) {
 	return &Handler{
-		Service:     params.Service,
-		TokenConfig: params.TokenConfig,
+		Service:           params.Service,
+		TokenConfig:       params.TokenConfig,
+		MTLSCerts:         params.MTLSCerts,
+		MTLSExpiryWarning: time.Duration(params.MTLSConfig.ExpiryWarningSec) * time.Second,
 	}, nil
 }
 
 type Handler struct {
 	Service     *service.Service
 	TokenConfig config.TokenConfig
+	// MTLSCerts is the server certificate presented to the manager, nil without mTLS
+	MTLSCerts         *certreload.Reloader
+	MTLSExpiryWarning time.Duration
 }
 
 func (h *Handler) JSONResponse(ctx context.Context, w http.ResponseWriter, status int, data any) {
@@ -144,11 +154,17 @@ func (h *Handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
 		Service:   "BSS Metrics API Server",
 	}
+	if h.MTLSCerts != nil {
+		if warning := h.MTLSCerts.ExpiryWarning(time.Now(), h.MTLSExpiryWarning); warning != "" {
+			response.Status = "degraded"
+			response.Warnings = append(response.Warnings, warning)
+		}
+	}
 	h.JSONResponse(r.Context(), w, http.StatusOK, response)
 }
 
 func (h *Handler) SetupRoutes(engine *echo.Echo) error {
-	authMiddleware, err := GetJwtAuthMiddleware(h.TokenConfig)
+	requireScope, err := GetJwtAuthMiddleware(h.TokenConfig)
 	if err != nil {
 		return err
 	}
@@ -161,13 +177,16 @@ func (h *Handler) SetupRoutes(engine *echo.Echo) error {
 	{
 		apiV1 := api.Group("/v1")
 		// auth routes
-		apiV1.POST("/intents", h.echoHandler(h.HandleIntents), echo.WrapMiddleware(authMiddleware))
-		apiV1.GET("/intents/merkle", h.echoHandler(h.GetIntentMerkleRoot), echo.WrapMiddleware(authMiddleware))
-		apiV1.DELETE("/intents", h.echoHandler(h.DeleteIntent), echo.WrapMiddleware(authMiddleware))
-		apiV1.GET("/scheduling/strategies", h.echoHandler(h.ListIntents), echo.WrapMiddleware(authMiddleware))
-		apiV1.POST("/metrics", h.echoHandler(h.UpdateMetrics), echo.WrapMiddleware(authMiddleware))
+		apiV1.POST("/intents", h.echoHandler(h.HandleIntents), echo.WrapMiddleware(requireScope(domain.ScopeIntentsWrite)))
+		apiV1.GET("/intents/merkle", h.echoHandler(h.GetIntentMerkleRoot), echo.WrapMiddleware(requireScope(domain.ScopeIntentsRead)))
+		apiV1.DELETE("/intents", h.echoHandler(h.DeleteIntent), echo.WrapMiddleware(requireScope(domain.ScopeIntentsWrite)))
+		apiV1.GET("/scheduling/strategies", h.echoHandler(h.ListIntents), echo.WrapMiddleware(requireScope(domain.ScopeIntentsRead)))
+		apiV1.POST("/metrics", h.echoHandler(h.UpdateMetrics), echo.WrapMiddleware(requireScope(domain.ScopeMetricsWrite)))
+		apiV1.GET("/metrics", h.echoHandler(h.GetMetrics), echo.WrapMiddleware(requireScope(domain.ScopeMetricsRead)))
+		apiV1.GET("/metrics/history", h.echoHandler(h.GetMetricHistory), echo.WrapMiddleware(requireScope(domain.ScopeMetricsRead)))
 		// pod routes
-		apiV1.GET("/pods/pids", h.echoHandler(h.GetPodsPIDs), echo.WrapMiddleware(authMiddleware))
+		apiV1.GET("/pods/pids", h.echoHandler(h.GetPodsPIDs), echo.WrapMiddleware(requireScope(domain.ScopePodsRead)))
+		apiV1.GET("/pods/explain", h.echoHandler(h.ExplainPod), echo.WrapMiddleware(requireScope(domain.ScopePodsRead)))
 		// token routes
 		apiV1.POST("/auth/token", h.echoHandler(h.GenTokenHandler))
 	}
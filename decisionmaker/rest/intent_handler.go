@@ -21,6 +21,11 @@ type Intent struct {
 	Priority      int               `json:"priority,omitempty"`
 	ExecutionTime int64             `json:"executionTime,omitempty"`
 	PodLabels     map[string]string `json:"podLabels,omitempty"`
+
+	CommandMatchTargets []string          `json:"commandMatchTargets,omitempty"`
+	ContainerNames      map[string]string `json:"containerNames,omitempty"`
+	ThreadRegex         string            `json:"threadRegex,omitempty"`
+	StrategyID          string            `json:"strategyID,omitempty"`
 }
 
 func (h *Handler) HandleIntents(w http.ResponseWriter, r *http.Request) {
@@ -42,6 +47,11 @@ func (h *Handler) HandleIntents(w http.ResponseWriter, r *http.Request) {
 			Priority:      intent.Priority,
 			ExecutionTime: intent.ExecutionTime,
 			PodLabels:     intent.PodLabels,
+
+			CommandMatchTargets: intent.CommandMatchTargets,
+			ContainerNames:      intent.ContainerNames,
+			ThreadRegex:         intent.ThreadRegex,
+			StrategyID:          intent.StrategyID,
 		})
 	}
 	err = h.Service.ProcessIntents(r.Context(), intents)
@@ -57,6 +67,7 @@ type SchedulingIntents struct {
 	Priority      int             `json:"priority"`                // Priority value; higher value means higher priority
 	ExecutionTime uint64          `json:"execution_time"`          // Time slice for this process in nanoseconds
 	PID           int             `json:"pid,omitempty"`           // Process ID to apply this strategy to
+	TID           int             `json:"tid,omitempty"`           // Thread ID to apply this strategy to; when set only this thread of PID is targeted
 	Selectors     []LabelSelector `json:"selectors,omitempty"`     // Label selectors to match pods
 	CommandRegex  string          `json:"command_regex,omitempty"` // Regex to match process command
 }
@@ -88,6 +99,7 @@ func (h *Handler) ListIntents(w http.ResponseWriter, r *http.Request) {
 			Priority:      intent.Priority,
 			ExecutionTime: intent.ExecutionTime,
 			PID:           intent.PID,
+			TID:           intent.TID,
 			Selectors:     convertMapToLabelSelectors(intent.Selectors),
 			CommandRegex:  intent.CommandRegex,
 		})
@@ -2,8 +2,10 @@ package rest
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/Gthulhu/api/decisionmaker/domain"
+	"github.com/Gthulhu/api/pkg/util"
 )
 
 // UpdateMetricsRequest represents the payload for updating metrics.
@@ -19,6 +21,27 @@ type UpdateMetricsRequest struct {
 	Nr_bounce_dispatches  uint64 `json:"nr_bounce_dispatches"`  // Number of bounce dispatches
 	Nr_failed_dispatches  uint64 `json:"nr_failed_dispatches"`  // Number of failed dispatches
 	Nr_sched_congested    uint64 `json:"nr_sched_congested"`    // Number of times the scheduler was congested
+
+	CPUs []CPUMetrics `json:"cpus,omitempty"` // Optional per-CPU counters
+	Pods []PodMetrics `json:"pods,omitempty"` // Optional per-pod dispatch and queue stats
+}
+
+// CPUMetrics represents the scheduler stats of a single CPU.
+type CPUMetrics struct {
+	CPU                  int    `json:"cpu"`                  // CPU index
+	Nr_queued            uint64 `json:"nr_queued"`            // Number of tasks queued on this CPU
+	Nr_user_dispatches   uint64 `json:"nr_user_dispatches"`   // Number of user-space dispatches on this CPU
+	Nr_kernel_dispatches uint64 `json:"nr_kernel_dispatches"` // Number of kernel-space dispatches on this CPU
+}
+
+// PodMetrics represents the scheduler stats of a pod, identified by pod_uid or cgroup_path.
+type PodMetrics struct {
+	Pod_uid       string `json:"pod_uid,omitempty"`     // UID of the pod
+	Cgroup_path   string `json:"cgroup_path,omitempty"` // Cgroup path of a task of the pod, used when pod_uid is not known
+	Nr_queued     uint64 `json:"nr_queued"`             // Number of tasks of the pod queued in the userspace scheduler
+	Nr_dispatches uint64 `json:"nr_dispatches"`         // Number of dispatches of tasks of the pod
+	Queue_wait_ns uint64 `json:"queue_wait_ns"`         // Total time tasks of the pod waited in the queue, in nanoseconds
+	Run_time_ns   uint64 `json:"run_time_ns"`           // Total time tasks of the pod ran on a CPU, in nanoseconds
 }
 
 // UpdateMetrics handles the updating of metrics via a REST endpoint.
@@ -42,7 +65,123 @@ func (h *Handler) UpdateMetrics(w http.ResponseWriter, r *http.Request) {
 		NrBounceDispatches: req.Nr_bounce_dispatches,
 		NrFailedDispatches: req.Nr_failed_dispatches,
 		NrSchedCongested:   req.Nr_sched_congested,
+		CPUs:               make([]domain.CPUMetrics, 0, len(req.CPUs)),
+		Pods:               make([]domain.PodMetrics, 0, len(req.Pods)),
+	}
+	for _, cpu := range req.CPUs {
+		newMetricSet.CPUs = append(newMetricSet.CPUs, domain.CPUMetrics{
+			CPU:                cpu.CPU,
+			NrQueued:           cpu.Nr_queued,
+			NrUserDispatches:   cpu.Nr_user_dispatches,
+			NrKernelDispatches: cpu.Nr_kernel_dispatches,
+		})
+	}
+	for _, pod := range req.Pods {
+		if pod.Pod_uid == "" && pod.Cgroup_path == "" {
+			h.ErrorResponse(ctx, w, http.StatusBadRequest, "pod metrics require pod_uid or cgroup_path", nil)
+			return
+		}
+		newMetricSet.Pods = append(newMetricSet.Pods, domain.PodMetrics{
+			PodUID:       pod.Pod_uid,
+			CgroupPath:   pod.Cgroup_path,
+			NrQueued:     pod.Nr_queued,
+			NrDispatches: pod.Nr_dispatches,
+			QueueWaitNs:  pod.Queue_wait_ns,
+			RunTimeNs:    pod.Run_time_ns,
+		})
 	}
 	h.Service.UpdateMetrics(r.Context(), newMetricSet)
 	h.JSONResponse(ctx, w, http.StatusOK, NewSuccessResponse[EmptyResponse](nil))
 }
+
+// MetricSample represents a metric set received from the scheduler.
+type MetricSample struct {
+	Timestamp             string `json:"timestamp"` // RFC3339 time the metric set was received
+	Usersched_last_run_at uint64 `json:"usersched_last_run_at"`
+	Nr_queued             uint64 `json:"nr_queued"`
+	Nr_scheduled          uint64 `json:"nr_scheduled"`
+	Nr_running            uint64 `json:"nr_running"`
+	Nr_online_cpus        uint64 `json:"nr_online_cpus"`
+	Nr_user_dispatches    uint64 `json:"nr_user_dispatches"`
+	Nr_kernel_dispatches  uint64 `json:"nr_kernel_dispatches"`
+	Nr_cancel_dispatches  uint64 `json:"nr_cancel_dispatches"`
+	Nr_bounce_dispatches  uint64 `json:"nr_bounce_dispatches"`
+	Nr_failed_dispatches  uint64 `json:"nr_failed_dispatches"`
+	Nr_sched_congested    uint64 `json:"nr_sched_congested"`
+}
+
+// MetricHistoryResponse is the response structure for the GET /api/v1/metrics/history endpoint
+type MetricHistoryResponse struct {
+	Samples []MetricSample `json:"samples"`
+}
+
+// GetMetricHistory godoc
+// @Summary Get metric history
+// @Description Returns the retained metric sets received from the scheduler, optionally downsampled to the last sample per step.
+// @Tags Metrics
+// @Produce json
+// @Security BearerAuth
+// @Param since query string false "Only samples received at or after this time (RFC3339 or unix seconds)"
+// @Param step query string false "Downsampling step as a Go duration, e.g. 10s"
+// @Success 200 {object} SuccessResponse[MetricHistoryResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /api/v1/metrics/history [get]
+func (h *Handler) GetMetricHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	since, step, err := util.ParseMetricHistoryQuery(r.URL.Query())
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	samples := h.Service.QueryMetricHistory(ctx, since, step)
+	resp := MetricHistoryResponse{
+		Samples: make([]MetricSample, 0, len(samples)),
+	}
+	for _, sample := range samples {
+		resp.Samples = append(resp.Samples, newMetricSample(sample))
+	}
+	h.JSONResponse(ctx, w, http.StatusOK, NewSuccessResponse(&resp))
+}
+
+// GetMetricsResponse is the response structure for the GET /api/v1/metrics endpoint
+type GetMetricsResponse struct {
+	Sample *MetricSample `json:"sample,omitempty"` // absent until the scheduler reported metrics
+}
+
+// GetMetrics godoc
+// @Summary Get current metrics
+// @Description Returns the last metric set received from the scheduler.
+// @Tags Metrics
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} SuccessResponse[GetMetricsResponse]
+// @Failure 401 {object} ErrorResponse
+// @Router /api/v1/metrics [get]
+func (h *Handler) GetMetrics(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := GetMetricsResponse{}
+	if sample := h.Service.LatestMetrics(ctx); sample != nil {
+		metricSample := newMetricSample(*sample)
+		resp.Sample = &metricSample
+	}
+	h.JSONResponse(ctx, w, http.StatusOK, NewSuccessResponse(&resp))
+}
+
+func newMetricSample(sample domain.MetricSample) MetricSample {
+	return MetricSample{
+		Timestamp:             sample.Timestamp.UTC().Format(time.RFC3339Nano),
+		Usersched_last_run_at: sample.UserSchedLastRunAt,
+		Nr_queued:             sample.NrQueued,
+		Nr_scheduled:          sample.NrScheduled,
+		Nr_running:            sample.NrRunning,
+		Nr_online_cpus:        sample.NrOnlineCPUs,
+		Nr_user_dispatches:    sample.NrUserDispatches,
+		Nr_kernel_dispatches:  sample.NrKernelDispatches,
+		Nr_cancel_dispatches:  sample.NrCancelDispatches,
+		Nr_bounce_dispatches:  sample.NrBounceDispatches,
+		Nr_failed_dispatches:  sample.NrFailedDispatches,
+		Nr_sched_congested:    sample.NrSchedCongested,
+	}
+}
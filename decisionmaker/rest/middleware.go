@@ -5,84 +5,104 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/decisionmaker/domain"
 	"github.com/Gthulhu/api/pkg/logger"
 	"github.com/Gthulhu/api/pkg/util"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func GetJwtAuthMiddleware(tokenConfig config.TokenConfig) (func(next http.Handler) http.Handler, error) {
+// GetJwtAuthMiddleware returns the middleware factory requiring tokens granting a scope. Tokens
+// without scopes predate the client registry and are only accepted while no client is registered.
+func GetJwtAuthMiddleware(tokenConfig config.TokenConfig) (func(scope domain.Scope) func(next http.Handler) http.Handler, error) {
 	rasKey, err := util.InitRSAPrivateKey(string(tokenConfig.RsaPrivateKeyPem))
 	if err != nil {
 		return nil, err
 	}
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Skip auth for OPTIONS requests, health check, root endpoint, token endpoint, and static files
-			if r.Method == "OPTIONS" ||
-				r.URL.Path == "/health" ||
-				r.URL.Path == "/" ||
-				r.URL.Path == "/api/v1/auth/token" ||
-				strings.HasPrefix(r.URL.Path, "/static/") {
-				next.ServeHTTP(w, r)
-				return
-			}
+	legacy := len(tokenConfig.Clients) == 0
+	return func(scope domain.Scope) func(next http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// Skip auth for OPTIONS requests, health check, root endpoint, token endpoint, and static files
+				if r.Method == "OPTIONS" ||
+					r.URL.Path == "/health" ||
+					r.URL.Path == "/" ||
+					r.URL.Path == "/api/v1/auth/token" ||
+					strings.HasPrefix(r.URL.Path, "/static/") {
+					next.ServeHTTP(w, r)
+					return
+				}
 
-			// Extract token from Authorization header
-			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" {
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusUnauthorized)
-				if err := json.NewEncoder(w).Encode(ErrorResponse{
-					Success: false,
-					Error:   "Authorization header is required",
-				}); err != nil {
-					logger.Logger(r.Context()).Error().Err(err).Msg("Failed to write unauthorized response")
+				// Extract token from Authorization header
+				authHeader := r.Header.Get("Authorization")
+				if authHeader == "" {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusUnauthorized)
+					if err := json.NewEncoder(w).Encode(ErrorResponse{
+						Success: false,
+						Error:   "Authorization header is required",
+					}); err != nil {
+						logger.Logger(r.Context()).Error().Err(err).Msg("Failed to write unauthorized response")
+					}
+					return
 				}
-				return
-			}
 
-			// Check Bearer token format
-			const bearerSchema = "Bearer "
-			if !strings.HasPrefix(authHeader, bearerSchema) {
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusUnauthorized)
-				if err := json.NewEncoder(w).Encode(ErrorResponse{
-					Success: false,
-					Error:   "Authorization header must start with 'Bearer '",
-				}); err != nil {
-					logger.Logger(r.Context()).Error().Err(err).Msg("Failed to write unauthorized response")
+				// Check Bearer token format
+				const bearerSchema = "Bearer "
+				if !strings.HasPrefix(authHeader, bearerSchema) {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusUnauthorized)
+					if err := json.NewEncoder(w).Encode(ErrorResponse{
+						Success: false,
+						Error:   "Authorization header must start with 'Bearer '",
+					}); err != nil {
+						logger.Logger(r.Context()).Error().Err(err).Msg("Failed to write unauthorized response")
+					}
+					return
 				}
-				return
-			}
 
-			tokenString := authHeader[len(bearerSchema):]
+				tokenString := authHeader[len(bearerSchema):]
+
+				// Validate JWT token
+				claims, err := validateJWT(rasKey, tokenString)
+				if err != nil {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusUnauthorized)
+					if err := json.NewEncoder(w).Encode(ErrorResponse{
+						Success: false,
+						Error:   "Invalid or expired token: " + err.Error(),
+					}); err != nil {
+						logger.Logger(r.Context()).Error().Err(err).Msg("Failed to write unauthorized response")
+					}
+					return
+				}
 
-			// Validate JWT token
-			claims, err := validateJWT(rasKey, tokenString)
-			if err != nil {
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusUnauthorized)
-				if err := json.NewEncoder(w).Encode(ErrorResponse{
-					Success: false,
-					Error:   "Invalid or expired token: " + err.Error(),
-				}); err != nil {
-					logger.Logger(r.Context()).Error().Err(err).Msg("Failed to write unauthorized response")
+				if !(claims.Scope == "" && legacy) && !slices.Contains(strings.Fields(claims.Scope), string(scope)) {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusForbidden)
+					if err := json.NewEncoder(w).Encode(ErrorResponse{
+						Success: false,
+						Error:   fmt.Sprintf("Token lacks the %s scope", scope),
+					}); err != nil {
+						logger.Logger(r.Context()).Error().Err(err).Msg("Failed to write forbidden response")
+					}
+					return
 				}
-				return
-			}
 
-			logger.Logger(r.Context()).Info().Str("client_id", claims.ClientID).Msg("JWT token validated successfully")
-			next.ServeHTTP(w, r)
-		})
+				logger.Logger(r.Context()).Info().Str("client_id", claims.ClientID).Msg("JWT token validated successfully")
+				next.ServeHTTP(w, r)
+			})
+		}
 	}, nil
 }
 
 // Claims represents JWT token claims
 type Claims struct {
 	ClientID string `json:"client_id"`
+	Scope    string `json:"scope,omitempty"`
 	jwt.RegisteredClaims
 }
 
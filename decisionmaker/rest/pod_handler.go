@@ -12,6 +12,8 @@ import (
 type PodProcess struct {
 	PID         int    `json:"pid"`
 	Command     string `json:"command"`
+	Cmdline     string `json:"cmdline,omitempty"`
+	Exe         string `json:"exe,omitempty"`
 	PPID        int    `json:"ppid,omitempty"`
 	ContainerID string `json:"container_id,omitempty"`
 }
@@ -59,6 +61,8 @@ func (h *Handler) GetPodsPIDs(w http.ResponseWriter, r *http.Request) {
 			processes = append(processes, PodProcess{
 				PID:         proc.PID,
 				Command:     proc.Command,
+				Cmdline:     proc.Cmdline,
+				Exe:         proc.Exe,
 				PPID:        proc.PPID,
 				ContainerID: proc.ContainerID,
 			})
@@ -90,7 +94,101 @@ func convertDomainPodProcess(proc domain.PodProcess) PodProcess {
 	return PodProcess{
 		PID:         proc.PID,
 		Command:     proc.Command,
+		Cmdline:     proc.Cmdline,
+		Exe:         proc.Exe,
 		PPID:        proc.PPID,
 		ContainerID: proc.ContainerID,
 	}
 }
+
+// ProcessExplanation reports the outcome of an intent for a single process (for API response)
+type ProcessExplanation struct {
+	PID         int    `json:"pid"`
+	Command     string `json:"command"`
+	ContainerID string `json:"container_id,omitempty"`
+	Outcome     string `json:"outcome"`
+	TIDs        []int  `json:"tids,omitempty"`
+}
+
+// IntentExplanation reports how an intent was resolved against the pod's processes (for API response)
+type IntentExplanation struct {
+	CommandRegex  string               `json:"command_regex,omitempty"`
+	ThreadRegex   string               `json:"thread_regex,omitempty"`
+	Priority      int                  `json:"priority"`
+	ExecutionTime int64                `json:"execution_time"`
+	Error         string               `json:"error,omitempty"`
+	Processes     []ProcessExplanation `json:"processes"`
+}
+
+// ExplainPodResponse is the response structure for the GET /api/v1/pods/explain endpoint
+type ExplainPodResponse struct {
+	PodID       string              `json:"pod_id"`
+	PodFound    bool                `json:"pod_found"`
+	ProcessPIDs []int               `json:"process_pids"`
+	Intents     []IntentExplanation `json:"intents"`
+	NodeName    string              `json:"node_name"`
+}
+
+// ExplainPod godoc
+// @Summary Explain pod scheduling
+// @Description Reports how the cached intents of a pod resolve against its current processes: which PIDs were found, which processes were skipped and why, and the resulting priority and time slice.
+// @Tags Pods
+// @Produce json
+// @Security BearerAuth
+// @Param podID query string true "Pod UID"
+// @Success 200 {object} SuccessResponse[ExplainPodResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/pods/explain [get]
+func (h *Handler) ExplainPod(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	podID := r.URL.Query().Get("podID")
+	if podID == "" {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "podID is required", nil)
+		return
+	}
+
+	explanation, err := h.Service.ExplainPod(ctx, podID)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusInternalServerError, "Failed to explain pod", err)
+		return
+	}
+
+	intents := make([]IntentExplanation, 0, len(explanation.Intents))
+	for _, intent := range explanation.Intents {
+		processes := make([]ProcessExplanation, 0, len(intent.Processes))
+		for _, proc := range intent.Processes {
+			processes = append(processes, ProcessExplanation{
+				PID:         proc.PID,
+				Command:     proc.Command,
+				ContainerID: proc.ContainerID,
+				Outcome:     string(proc.Outcome),
+				TIDs:        proc.TIDs,
+			})
+		}
+		intents = append(intents, IntentExplanation{
+			CommandRegex:  intent.CommandRegex,
+			ThreadRegex:   intent.ThreadRegex,
+			Priority:      intent.Priority,
+			ExecutionTime: intent.ExecutionTime,
+			Error:         intent.Error,
+			Processes:     processes,
+		})
+	}
+
+	nodeName, _ := os.Hostname()
+	if envNodeName := os.Getenv("NODE_NAME"); envNodeName != "" {
+		nodeName = envNodeName
+	}
+
+	response := ExplainPodResponse{
+		PodID:       explanation.PodID,
+		PodFound:    explanation.PodFound,
+		ProcessPIDs: explanation.ProcessPIDs,
+		Intents:     intents,
+		NodeName:    nodeName,
+	}
+	h.JSONResponse(ctx, w, http.StatusOK, NewSuccessResponse(&response))
+}
@@ -6,8 +6,9 @@ import (
 
 // TokenResponse represents the response structure for JWT token generation
 type TokenResponse struct {
-	Token     string `json:"token,omitempty"`
-	ExpiredAt int64  `json:"expired_at,omitempty"`
+	Token     string   `json:"token,omitempty"`
+	ExpiredAt int64    `json:"expired_at,omitempty"`
+	Scopes    []string `json:"scopes,omitempty"`
 }
 
 // TokenRequest represents the request structure for JWT token generation
@@ -15,9 +16,11 @@ type TokenRequest struct {
 	PublicKey string `json:"public_key"` // PEM encoded public key
 	ClientID  string `json:"client_id"`  // Client identifier
 	ExpiredAt int64  `json:"expired_at"` // Expiration timestamp
+	// ClientAssertion is a JWT signed by the private key of a registered client, see domain.NewClientAssertion
+	ClientAssertion string `json:"client_assertion,omitempty"`
 }
 
-// GenTokenHandler handles JWT token generation upon public key verification
+// GenTokenHandler handles JWT token generation upon client authentication
 func (h Handler) GenTokenHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	var req TokenRequest
@@ -26,9 +29,9 @@ func (h Handler) GenTokenHandler(w http.ResponseWriter, r *http.Request) {
 		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request payload", err)
 		return
 	}
-	token, expiredAt, err := h.Service.VerifyAndGenerateToken(r.Context(), req.ClientID, req.PublicKey)
+	token, expiredAt, scopes, err := h.Service.VerifyAndGenerateToken(r.Context(), req.ClientID, req.PublicKey, req.ClientAssertion)
 	if err != nil {
-		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Public key verification failed ", err)
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Client authentication failed", err)
 		return
 	}
 
@@ -36,5 +39,8 @@ func (h Handler) GenTokenHandler(w http.ResponseWriter, r *http.Request) {
 		ExpiredAt: expiredAt,
 		Token:     token,
 	}
+	for _, scope := range scopes {
+		resp.Scopes = append(resp.Scopes, string(scope))
+	}
 	h.JSONResponse(ctx, w, http.StatusOK, NewSuccessResponse[TokenResponse](&resp))
 }
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/Gthulhu/api/decisionmaker/domain"
+)
+
+// ExplainPod re-evaluates the cached intents of podID against the pod's current processes
+// and reports the outcome for every process, mirroring resolveSchedulingIntents.
+func (svc *Service) ExplainPod(ctx context.Context, podID string) (*domain.PodExplanation, error) {
+	svc.intentCacheMu.RLock()
+	cachedIntents := svc.intentCache
+	svc.intentCacheMu.RUnlock()
+
+	podInfos, err := svc.GetAllPodInfos(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.PodExplanation{
+		PodID:       podID,
+		ProcessPIDs: []int{},
+		Intents:     []domain.IntentExplanation{},
+	}
+	podInfo := podInfos[podID]
+	if podInfo != nil {
+		result.PodFound = true
+		for _, process := range podInfo.Processes {
+			result.ProcessPIDs = append(result.ProcessPIDs, process.PID)
+		}
+	}
+
+	for _, intent := range cachedIntents {
+		if intent.PodID != podID {
+			continue
+		}
+		result.Intents = append(result.Intents, svc.explainIntent(intent, podInfo))
+	}
+	return result, nil
+}
+
+// explainIntent reports the outcome of intent for each process of podInfo
+func (svc *Service) explainIntent(intent *domain.Intent, podInfo *domain.PodInfo) domain.IntentExplanation {
+	explanation := domain.IntentExplanation{
+		CommandRegex:  intent.CommandRegex,
+		ThreadRegex:   intent.ThreadRegex,
+		Priority:      intent.Priority,
+		ExecutionTime: intent.ExecutionTime,
+		Processes:     []domain.ProcessExplanation{},
+	}
+	cmdRegex, err := regexp.Compile(intent.CommandRegex)
+	if err != nil {
+		explanation.Error = "invalid command regex: " + err.Error()
+		return explanation
+	}
+	var threadRegex *regexp.Regexp
+	if intent.ThreadRegex != "" {
+		threadRegex, err = regexp.Compile(intent.ThreadRegex)
+		if err != nil {
+			explanation.Error = "invalid thread regex: " + err.Error()
+			return explanation
+		}
+	}
+	if podInfo == nil {
+		return explanation
+	}
+
+	for _, process := range podInfo.Processes {
+		processExplanation := domain.ProcessExplanation{
+			PID:         process.PID,
+			Command:     process.Command,
+			ContainerID: process.ContainerID,
+		}
+		switch {
+		case process.Command == pauseCommand:
+			processExplanation.Outcome = domain.ProcessOutcomeSkippedPause
+		case !matchProcess(cmdRegex, intent, process):
+			processExplanation.Outcome = domain.ProcessOutcomeRegexMismatch
+		case threadRegex != nil:
+			processExplanation.Outcome = domain.ProcessOutcomeNoThreadMatched
+			threads, err := svc.getProcessThreads(svc.procRootDir(), process.PID)
+			if err != nil {
+				break
+			}
+			for _, thread := range threads {
+				if threadRegex.MatchString(thread.Command) {
+					processExplanation.TIDs = append(processExplanation.TIDs, thread.TID)
+				}
+			}
+			if len(processExplanation.TIDs) > 0 {
+				processExplanation.Outcome = domain.ProcessOutcomeMatched
+			}
+		default:
+			processExplanation.Outcome = domain.ProcessOutcomeMatched
+		}
+		explanation.Processes = append(explanation.Processes, processExplanation)
+	}
+	return explanation
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Gthulhu/api/decisionmaker/domain"
+	"github.com/Gthulhu/api/pkg/intenttable"
+	"github.com/Gthulhu/api/pkg/logger"
+)
+
+// intentExporter publishes the resolved scheduling intents to a local intenttable file,
+// rewriting it only when the resolved table changes
+type intentExporter struct {
+	path       string
+	mu         sync.Mutex
+	generation uint64
+	published  bool
+	entries    []intenttable.Entry
+}
+
+func newIntentExporter(path string) *intentExporter {
+	return &intentExporter{path: path}
+}
+
+// Export writes the table for intents if it differs from the last published one
+func (e *intentExporter) Export(intents []*domain.SchedulingIntents) error {
+	entries := make([]intenttable.Entry, 0, len(intents))
+	for _, intent := range intents {
+		entries = append(entries, intenttable.Entry{
+			PID:           int32(intent.PID),
+			TID:           int32(intent.TID),
+			Priority:      int32(intent.Priority),
+			ExecutionTime: intent.ExecutionTime,
+		})
+	}
+	intenttable.SortEntries(entries)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.published && slices.Equal(e.entries, entries) {
+		return nil
+	}
+	table := &intenttable.Table{
+		Generation: e.generation + 1,
+		UpdatedAt:  time.Now().UnixNano(),
+		Entries:    entries,
+	}
+	if err := intenttable.WriteFile(e.path, table); err != nil {
+		return err
+	}
+	e.generation = table.Generation
+	e.entries = entries
+	e.published = true
+	return nil
+}
+
+// exportSchedulingIntents publishes intents when the export is enabled
+func (svc *Service) exportSchedulingIntents(ctx context.Context, intents []*domain.SchedulingIntents) {
+	if svc.intentExporter == nil {
+		return
+	}
+	if err := svc.intentExporter.Export(intents); err != nil {
+		logger.Logger(ctx).Warn().Err(err).Msgf("failed to export scheduling intents to %s", svc.intentExporter.path)
+	}
+}
+
+// RefreshIntentExport re-resolves the cached intents against the current processes, so the
+// exported table follows process churn even when nothing polls the HTTP API
+func (svc *Service) RefreshIntentExport(ctx context.Context) error {
+	svc.resolveMu.Lock()
+	defer svc.resolveMu.Unlock()
+	svc.intentCacheMu.RLock()
+	cachedIntents := svc.intentCache
+	svc.intentCacheMu.RUnlock()
+
+	podInfos := map[string]*domain.PodInfo{}
+	if len(cachedIntents) > 0 {
+		var err error
+		podInfos, err = svc.GetAllPodInfos(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	svc.resolveSchedulingIntents(ctx, cachedIntents, podInfos)
+	return nil
+}
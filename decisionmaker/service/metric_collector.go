@@ -1,6 +1,7 @@
 package service
 
 import (
+	"strconv"
 	"sync/atomic"
 
 	"github.com/Gthulhu/api/decisionmaker/domain"
@@ -27,9 +28,31 @@ type MetricCollector struct {
 	NrFailedDispatchesMetric *prometheus.Desc
 	NrSchedCongestedMetric   *prometheus.Desc
 
+	// counter variants of the monotonic global stats above
+	UserDispatchesTotalMetric   *prometheus.Desc
+	KernelDispatchesTotalMetric *prometheus.Desc
+	CancelDispatchesTotalMetric *prometheus.Desc
+	BounceDispatchesTotalMetric *prometheus.Desc
+	FailedDispatchesTotalMetric *prometheus.Desc
+	SchedCongestedTotalMetric   *prometheus.Desc
+
+	CPUNrQueuedMetric              *prometheus.Desc
+	CPUUserDispatchesTotalMetric   *prometheus.Desc
+	CPUKernelDispatchesTotalMetric *prometheus.Desc
+
+	PodNrQueuedMetric              *prometheus.Desc
+	PodDispatchesTotalMetric       *prometheus.Desc
+	PodQueueWaitSecondsTotalMetric *prometheus.Desc
+	PodRunTimeSecondsTotalMetric   *prometheus.Desc
+
 	metricSet atomic.Pointer[domain.MetricSet]
 }
 
+var (
+	cpuLabels = []string{"cpu"}
+	podLabels = []string{"pod_uid", "pod", "namespace", "strategy_id"}
+)
+
 // // NewMetricCollector creates a new MetricCollector for a specific game server.
 func NewMetricCollector(machineID string) *MetricCollector {
 	constantLabels := prometheus.Labels{"machine_id": machineID}
@@ -101,6 +124,84 @@ func NewMetricCollector(machineID string) *MetricCollector {
 			nil,
 			constantLabels,
 		),
+		UserDispatchesTotalMetric: prometheus.NewDesc(
+			"user_dispatches_total",
+			"total number of user-space dispatches",
+			nil,
+			constantLabels,
+		),
+		KernelDispatchesTotalMetric: prometheus.NewDesc(
+			"kernel_dispatches_total",
+			"total number of kernel-space dispatches",
+			nil,
+			constantLabels,
+		),
+		CancelDispatchesTotalMetric: prometheus.NewDesc(
+			"cancel_dispatches_total",
+			"total number of canceled dispatches",
+			nil,
+			constantLabels,
+		),
+		BounceDispatchesTotalMetric: prometheus.NewDesc(
+			"bounce_dispatches_total",
+			"total number of bounced dispatches",
+			nil,
+			constantLabels,
+		),
+		FailedDispatchesTotalMetric: prometheus.NewDesc(
+			"failed_dispatches_total",
+			"total number of failed dispatches",
+			nil,
+			constantLabels,
+		),
+		SchedCongestedTotalMetric: prometheus.NewDesc(
+			"sched_congested_total",
+			"total number of times the scheduler was congested",
+			nil,
+			constantLabels,
+		),
+		CPUNrQueuedMetric: prometheus.NewDesc(
+			"cpu_nr_queued",
+			"number of tasks queued on the CPU",
+			cpuLabels,
+			constantLabels,
+		),
+		CPUUserDispatchesTotalMetric: prometheus.NewDesc(
+			"cpu_user_dispatches_total",
+			"total number of user-space dispatches on the CPU",
+			cpuLabels,
+			constantLabels,
+		),
+		CPUKernelDispatchesTotalMetric: prometheus.NewDesc(
+			"cpu_kernel_dispatches_total",
+			"total number of kernel-space dispatches on the CPU",
+			cpuLabels,
+			constantLabels,
+		),
+		PodNrQueuedMetric: prometheus.NewDesc(
+			"pod_nr_queued",
+			"number of tasks of the pod queued in the userspace scheduler",
+			podLabels,
+			constantLabels,
+		),
+		PodDispatchesTotalMetric: prometheus.NewDesc(
+			"pod_dispatches_total",
+			"total number of dispatches of tasks of the pod",
+			podLabels,
+			constantLabels,
+		),
+		PodQueueWaitSecondsTotalMetric: prometheus.NewDesc(
+			"pod_queue_wait_seconds_total",
+			"total time tasks of the pod waited in the scheduler queue",
+			podLabels,
+			constantLabels,
+		),
+		PodRunTimeSecondsTotalMetric: prometheus.NewDesc(
+			"pod_run_time_seconds_total",
+			"total time tasks of the pod ran on a CPU",
+			podLabels,
+			constantLabels,
+		),
 	}
 }
 
@@ -117,6 +218,19 @@ func (collector *MetricCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- collector.NrBounceDispatchesMetric
 	ch <- collector.NrFailedDispatchesMetric
 	ch <- collector.NrSchedCongestedMetric
+	ch <- collector.UserDispatchesTotalMetric
+	ch <- collector.KernelDispatchesTotalMetric
+	ch <- collector.CancelDispatchesTotalMetric
+	ch <- collector.BounceDispatchesTotalMetric
+	ch <- collector.FailedDispatchesTotalMetric
+	ch <- collector.SchedCongestedTotalMetric
+	ch <- collector.CPUNrQueuedMetric
+	ch <- collector.CPUUserDispatchesTotalMetric
+	ch <- collector.CPUKernelDispatchesTotalMetric
+	ch <- collector.PodNrQueuedMetric
+	ch <- collector.PodDispatchesTotalMetric
+	ch <- collector.PodQueueWaitSecondsTotalMetric
+	ch <- collector.PodRunTimeSecondsTotalMetric
 }
 
 // Collect is called by the Prometheus registry when collecting metrics.
@@ -137,6 +251,28 @@ func (collector *MetricCollector) Collect(ch chan<- prometheus.Metric) {
 	ch <- prometheus.MustNewConstMetric(collector.NrBounceDispatchesMetric, prometheus.GaugeValue, float64(metricSet.NrBounceDispatches))
 	ch <- prometheus.MustNewConstMetric(collector.NrFailedDispatchesMetric, prometheus.GaugeValue, float64(metricSet.NrFailedDispatches))
 	ch <- prometheus.MustNewConstMetric(collector.NrSchedCongestedMetric, prometheus.GaugeValue, float64(metricSet.NrSchedCongested))
+
+	ch <- prometheus.MustNewConstMetric(collector.UserDispatchesTotalMetric, prometheus.CounterValue, float64(metricSet.NrUserDispatches))
+	ch <- prometheus.MustNewConstMetric(collector.KernelDispatchesTotalMetric, prometheus.CounterValue, float64(metricSet.NrKernelDispatches))
+	ch <- prometheus.MustNewConstMetric(collector.CancelDispatchesTotalMetric, prometheus.CounterValue, float64(metricSet.NrCancelDispatches))
+	ch <- prometheus.MustNewConstMetric(collector.BounceDispatchesTotalMetric, prometheus.CounterValue, float64(metricSet.NrBounceDispatches))
+	ch <- prometheus.MustNewConstMetric(collector.FailedDispatchesTotalMetric, prometheus.CounterValue, float64(metricSet.NrFailedDispatches))
+	ch <- prometheus.MustNewConstMetric(collector.SchedCongestedTotalMetric, prometheus.CounterValue, float64(metricSet.NrSchedCongested))
+
+	for _, cpu := range metricSet.CPUs {
+		cpuLabel := strconv.Itoa(cpu.CPU)
+		ch <- prometheus.MustNewConstMetric(collector.CPUNrQueuedMetric, prometheus.GaugeValue, float64(cpu.NrQueued), cpuLabel)
+		ch <- prometheus.MustNewConstMetric(collector.CPUUserDispatchesTotalMetric, prometheus.CounterValue, float64(cpu.NrUserDispatches), cpuLabel)
+		ch <- prometheus.MustNewConstMetric(collector.CPUKernelDispatchesTotalMetric, prometheus.CounterValue, float64(cpu.NrKernelDispatches), cpuLabel)
+	}
+
+	for _, pod := range metricSet.Pods {
+		labelValues := []string{pod.PodUID, pod.PodName, pod.K8sNamespace, pod.StrategyID}
+		ch <- prometheus.MustNewConstMetric(collector.PodNrQueuedMetric, prometheus.GaugeValue, float64(pod.NrQueued), labelValues...)
+		ch <- prometheus.MustNewConstMetric(collector.PodDispatchesTotalMetric, prometheus.CounterValue, float64(pod.NrDispatches), labelValues...)
+		ch <- prometheus.MustNewConstMetric(collector.PodQueueWaitSecondsTotalMetric, prometheus.CounterValue, float64(pod.QueueWaitNs)/1e9, labelValues...)
+		ch <- prometheus.MustNewConstMetric(collector.PodRunTimeSecondsTotalMetric, prometheus.CounterValue, float64(pod.RunTimeNs)/1e9, labelValues...)
+	}
 }
 
 func (collector *MetricCollector) UpdateMetrics(newMetricSet *domain.MetricSet) {
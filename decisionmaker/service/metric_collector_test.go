@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Gthulhu/api/decisionmaker/domain"
+	"github.com/Gthulhu/api/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateMetricsPerCPUAndPod(t *testing.T) {
+	logger.InitLogger()
+	svc := &Service{
+		metricCollector: NewMetricCollector("machine-1"),
+		intentCache: []*domain.Intent{
+			{PodID: "20da609e-6973-4463-a1f9-2db9bcc5becc", PodName: "web-0", K8sNamespace: "default", StrategyID: "strategy-b"},
+			{PodID: "20da609e-6973-4463-a1f9-2db9bcc5becc", PodName: "web-0", K8sNamespace: "default", StrategyID: "strategy-a"},
+		},
+	}
+
+	svc.UpdateMetrics(context.Background(), &domain.MetricSet{
+		NrUserDispatches: 42,
+		CPUs: []domain.CPUMetrics{
+			{CPU: 1, NrQueued: 1, NrUserDispatches: 10, NrKernelDispatches: 2},
+			{CPU: 0, NrQueued: 3, NrUserDispatches: 5, NrKernelDispatches: 1},
+			{CPU: 1, NrQueued: 2, NrUserDispatches: 11, NrKernelDispatches: 3},
+		},
+		Pods: []domain.PodMetrics{
+			{PodUID: "20da609e-6973-4463-a1f9-2db9bcc5becc", NrDispatches: 7, RunTimeNs: 1500000000},
+			{CgroupPath: "/kubepods/burstable/pod20da609e-6973-4463-a1f9-2db9bcc5becc/10ec3c89629f71226b227e6510b2d465168b24005bbdcc5d7940517080830635", NrDispatches: 3, RunTimeNs: 500000000},
+			{CgroupPath: "/system.slice/sshd.service", NrDispatches: 100},
+			{PodUID: "e52d4a2a-6e5f-44d9-a8b8-37ff3daa7413", NrDispatches: 1},
+		},
+	})
+
+	expected := `
+# HELP cpu_user_dispatches_total total number of user-space dispatches on the CPU
+# TYPE cpu_user_dispatches_total counter
+cpu_user_dispatches_total{cpu="0",machine_id="machine-1"} 5
+cpu_user_dispatches_total{cpu="1",machine_id="machine-1"} 11
+# HELP pod_dispatches_total total number of dispatches of tasks of the pod
+# TYPE pod_dispatches_total counter
+pod_dispatches_total{machine_id="machine-1",namespace="",pod="",pod_uid="e52d4a2a-6e5f-44d9-a8b8-37ff3daa7413",strategy_id=""} 1
+pod_dispatches_total{machine_id="machine-1",namespace="default",pod="web-0",pod_uid="20da609e-6973-4463-a1f9-2db9bcc5becc",strategy_id="strategy-a,strategy-b"} 10
+# HELP pod_run_time_seconds_total total time tasks of the pod ran on a CPU
+# TYPE pod_run_time_seconds_total counter
+pod_run_time_seconds_total{machine_id="machine-1",namespace="",pod="",pod_uid="e52d4a2a-6e5f-44d9-a8b8-37ff3daa7413",strategy_id=""} 0
+pod_run_time_seconds_total{machine_id="machine-1",namespace="default",pod="web-0",pod_uid="20da609e-6973-4463-a1f9-2db9bcc5becc",strategy_id="strategy-a,strategy-b"} 2
+# HELP user_dispatches_total total number of user-space dispatches
+# TYPE user_dispatches_total counter
+user_dispatches_total{machine_id="machine-1"} 42
+`
+	err := testutil.CollectAndCompare(svc.metricCollector, strings.NewReader(expected),
+		"cpu_user_dispatches_total", "pod_dispatches_total", "pod_run_time_seconds_total", "user_dispatches_total")
+	require.NoError(t, err)
+
+	latest := svc.LatestMetrics(context.Background())
+	require.NotNil(t, latest)
+	require.Equal(t, uint64(42), latest.NrUserDispatches)
+	require.False(t, latest.Timestamp.IsZero())
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Gthulhu/api/decisionmaker/domain"
+)
+
+const (
+	defaultMetricHistoryMaxSamples = 3600
+	defaultMetricHistoryRetention  = time.Hour
+)
+
+// metricHistory is a bounded ring buffer of the global stats of received metric sets.
+// Per-CPU and per-pod stats are not kept since their cardinality is unbounded.
+type metricHistory struct {
+	mu        sync.RWMutex
+	samples   []domain.MetricSample
+	start     int // index of the oldest sample
+	size      int
+	retention time.Duration
+}
+
+func newMetricHistory(maxSamples int, retention time.Duration) *metricHistory {
+	if maxSamples <= 0 {
+		maxSamples = defaultMetricHistoryMaxSamples
+	}
+	if retention <= 0 {
+		retention = defaultMetricHistoryRetention
+	}
+	return &metricHistory{
+		samples:   make([]domain.MetricSample, maxSamples),
+		retention: retention,
+	}
+}
+
+// Add records metricSet as received at ts, overwriting the oldest sample when the buffer is full
+func (h *metricHistory) Add(ts time.Time, metricSet *domain.MetricSet) {
+	sample := domain.MetricSample{Timestamp: ts, MetricSet: *metricSet}
+	sample.CPUs = nil
+	sample.Pods = nil
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.expire(ts)
+	if h.size == len(h.samples) {
+		h.samples[h.start] = sample
+		h.start = (h.start + 1) % len(h.samples)
+		return
+	}
+	h.samples[(h.start+h.size)%len(h.samples)] = sample
+	h.size++
+}
+
+// expire drops samples older than the retention, the caller must hold the write lock
+func (h *metricHistory) expire(now time.Time) {
+	cutoff := now.Add(-h.retention)
+	for h.size > 0 && h.samples[h.start].Timestamp.Before(cutoff) {
+		h.samples[h.start] = domain.MetricSample{}
+		h.start = (h.start + 1) % len(h.samples)
+		h.size--
+	}
+}
+
+// Query returns the retained samples received at or after since in chronological order.
+// When step is positive the samples are downsampled to the last sample of each step-aligned
+// bucket, which keeps gauges current and counters monotonic.
+func (h *metricHistory) Query(now, since time.Time, step time.Duration) []domain.MetricSample {
+	cutoff := now.Add(-h.retention)
+	if since.Before(cutoff) {
+		since = cutoff
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	results := make([]domain.MetricSample, 0, h.size)
+	for i := 0; i < h.size; i++ {
+		sample := h.samples[(h.start+i)%len(h.samples)]
+		if sample.Timestamp.Before(since) {
+			continue
+		}
+		if step > 0 && len(results) > 0 &&
+			results[len(results)-1].Timestamp.Truncate(step).Equal(sample.Timestamp.Truncate(step)) {
+			results[len(results)-1] = sample
+			continue
+		}
+		results = append(results, sample)
+	}
+	return results
+}
+
+// QueryMetricHistory returns the metric sets received since the given time, downsampled to step
+func (svc *Service) QueryMetricHistory(ctx context.Context, since time.Time, step time.Duration) []domain.MetricSample {
+	if svc.metricHistory == nil {
+		return []domain.MetricSample{}
+	}
+	return svc.metricHistory.Query(time.Now(), since, step)
+}
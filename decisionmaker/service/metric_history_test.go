@@ -0,0 +1,59 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Gthulhu/api/decisionmaker/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricHistoryRingBuffer(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := newMetricHistory(3, time.Hour)
+	for i := 0; i < 5; i++ {
+		history.Add(base.Add(time.Duration(i)*time.Second), &domain.MetricSet{
+			NrQueued: uint64(i),
+			CPUs:     []domain.CPUMetrics{{CPU: 0}},
+		})
+	}
+
+	samples := history.Query(base.Add(5*time.Second), time.Time{}, 0)
+	require.Len(t, samples, 3)
+	for i, sample := range samples {
+		require.Equal(t, uint64(i+2), sample.NrQueued)
+		require.Nil(t, sample.CPUs)
+	}
+
+	samples = history.Query(base.Add(5*time.Second), base.Add(4*time.Second), 0)
+	require.Len(t, samples, 1)
+	require.Equal(t, uint64(4), samples[0].NrQueued)
+}
+
+func TestMetricHistoryRetentionAndDownsampling(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := newMetricHistory(100, time.Minute)
+	for i := 0; i < 30; i++ {
+		history.Add(base.Add(time.Duration(i)*5*time.Second), &domain.MetricSet{NrQueued: uint64(i)})
+	}
+
+	// at base+145s the retention keeps samples from base+85s (i=17) onward
+	now := base.Add(145 * time.Second)
+	samples := history.Query(now, time.Time{}, 0)
+	require.Len(t, samples, 13)
+	require.Equal(t, uint64(17), samples[0].NrQueued)
+
+	// 20s buckets keep the last sample of each bucket: 95s, 115s, 135s, 145s
+	samples = history.Query(now, time.Time{}, 20*time.Second)
+	queued := make([]uint64, 0, len(samples))
+	for _, sample := range samples {
+		queued = append(queued, sample.NrQueued)
+	}
+	require.Equal(t, []uint64{19, 23, 27, 29}, queued)
+
+	// adding a sample later expires the old ones from the buffer itself
+	history.Add(base.Add(10*time.Minute), &domain.MetricSet{NrQueued: 99})
+	samples = history.Query(base.Add(10*time.Minute), time.Time{}, 0)
+	require.Len(t, samples, 1)
+	require.Equal(t, uint64(99), samples[0].NrQueued)
+}
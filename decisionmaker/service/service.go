@@ -7,10 +7,13 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/Gthulhu/api/config"
 	"github.com/Gthulhu/api/decisionmaker/domain"
@@ -22,7 +25,9 @@ import (
 
 type Params struct {
 	fx.In
-	TokenConfig config.TokenConfig
+	TokenConfig         config.TokenConfig
+	IntentExportConfig  config.IntentExportConfig
+	MetricHistoryConfig config.MetricHistoryConfig
 }
 
 func NewService(params Params) (*Service, error) {
@@ -30,10 +35,23 @@ func NewService(params Params) (*Service, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize JWT private key: %v", err)
 	}
+	clients, err := newClientRegistry(params.TokenConfig.Clients)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize token clients: %v", err)
+	}
 	svc := &Service{
 		schedulingIntentsMap: util.NewGenericMap[string, []*domain.SchedulingIntents](),
 		metricCollector:      NewMetricCollector(util.GetMachineID()),
 		jwtPrivateKey:        privateKey,
+		tokenConfig:          params.TokenConfig,
+		clients:              clients,
+		metricHistory: newMetricHistory(
+			params.MetricHistoryConfig.MaxSamples,
+			time.Duration(params.MetricHistoryConfig.RetentionSec)*time.Second,
+		),
+	}
+	if params.IntentExportConfig.Enable {
+		svc.intentExporter = newIntentExporter(params.IntentExportConfig.Path)
 	}
 
 	err = prometheus.Register(svc.metricCollector)
@@ -48,10 +66,23 @@ type Service struct {
 	metricCollector      *MetricCollector
 	jwtPrivateKey        *rsa.PrivateKey
 	tokenConfig          config.TokenConfig
-	intentCacheMu        sync.RWMutex
-	intentCache          []*domain.Intent
+	// clients are the registered token clients by client ID, empty allows the legacy public key
+	clients map[string]*domain.Client
+	// resolveMu serialises resolving intents, which rebuilds schedulingIntentsMap and the export
+	resolveMu     sync.Mutex
+	intentCacheMu sync.RWMutex
+	intentCache   []*domain.Intent
+	// deletedProcesses holds the <podID>-<pid> keys deleted since the last ProcessIntents
+	deletedProcesses     map[string]struct{}
 	intentMerkleRoot     *util.MerkleNode
 	intentMerkleRootHash string
+	// procRoot overrides procDir, used by tests with fixture /proc trees
+	procRoot string
+	// intentExporter publishes resolved intents to a local file, nil when disabled
+	intentExporter *intentExporter
+	metricHistory  *metricHistory
+	// latestMetrics is the last metric set received from the scheduler
+	latestMetrics atomic.Pointer[domain.MetricSample]
 }
 
 const (
@@ -62,6 +93,8 @@ const (
 // ListAllSchedulingIntents re-scans /proc and recalculates scheduling intents
 // from the cached domain.Intent list, since pod processes may change over time.
 func (svc *Service) ListAllSchedulingIntents(ctx context.Context) ([]*domain.SchedulingIntents, error) {
+	svc.resolveMu.Lock()
+	defer svc.resolveMu.Unlock()
 	svc.intentCacheMu.RLock()
 	cachedIntents := svc.intentCache
 	svc.intentCacheMu.RUnlock()
@@ -93,8 +126,11 @@ func (svc *Service) ProcessIntents(ctx context.Context, intents []*domain.Intent
 		leafHashes = append(leafHashes, hashIntent(intent))
 	}
 	root := util.BuildMerkleTree(leafHashes)
+	svc.resolveMu.Lock()
+	defer svc.resolveMu.Unlock()
 	svc.intentCacheMu.Lock()
 	svc.intentCache = normalizedIntents
+	svc.deletedProcesses = nil
 	svc.intentMerkleRoot = root
 	if root != nil {
 		svc.intentMerkleRootHash = root.Hash
@@ -109,12 +145,16 @@ func (svc *Service) ProcessIntents(ctx context.Context, intents []*domain.Intent
 
 // resolveSchedulingIntents converts domain.Intents + PodInfos into SchedulingIntents,
 // updates the schedulingIntentsMap and returns all resolved scheduling intents.
+// Callers hold resolveMu.
 func (svc *Service) resolveSchedulingIntents(ctx context.Context, intents []*domain.Intent, podInfos map[string]*domain.PodInfo) []*domain.SchedulingIntents {
+	svc.intentCacheMu.RLock()
+	deletedProcesses := svc.deletedProcesses
+	svc.intentCacheMu.RUnlock()
 	svc.schedulingIntentsMap.Clear()
 	var allSchedulingIntents []*domain.SchedulingIntents
 	for _, intent := range intents {
 		podInfo := podInfos[intent.PodID]
-		logger.Logger(ctx).Info().Msgf("Processing intent for PodName:%s PodID: %s on NodeID: %s, Process:%+v", intent.PodName, intent.PodID, intent.NodeID, podInfo)
+		logger.Logger(ctx).Debug().Msgf("Processing intent for PodName:%s PodID: %s on NodeID: %s, Process:%+v", intent.PodName, intent.PodID, intent.NodeID, podInfo)
 		labels := []domain.LabelSelector{}
 		for key, value := range intent.PodLabels {
 			labels = append(labels, domain.LabelSelector{
@@ -122,12 +162,36 @@ func (svc *Service) resolveSchedulingIntents(ctx context.Context, intents []*dom
 				Value: value,
 			})
 		}
+		cmdRegex, err := regexp.Compile(intent.CommandRegex)
+		if err != nil {
+			logger.Logger(ctx).Warn().Err(err).Msgf("invalid command regex %q for PodID: %s", intent.CommandRegex, intent.PodID)
+			continue
+		}
+		var threadRegex *regexp.Regexp
+		if intent.ThreadRegex != "" {
+			threadRegex, err = regexp.Compile(intent.ThreadRegex)
+			if err != nil {
+				logger.Logger(ctx).Warn().Err(err).Msgf("invalid thread regex %q for PodID: %s", intent.ThreadRegex, intent.PodID)
+				continue
+			}
+		}
 		if podInfo != nil && len(podInfo.Processes) > 0 {
 			for _, process := range podInfo.Processes {
 				if process.Command == pauseCommand {
 					continue
 				}
-				if !regexp.MustCompile(intent.CommandRegex).MatchString(process.Command) {
+				if !matchProcess(cmdRegex, intent, process) {
+					continue
+				}
+				if _, ok := deletedProcesses[fmt.Sprintf("%s-%d", intent.PodID, process.PID)]; ok {
+					continue
+				}
+				if threadRegex != nil {
+					threadIntents := svc.resolveThreadSchedulingIntents(ctx, intent, process.PID, threadRegex, labels)
+					if len(threadIntents) > 0 {
+						svc.schedulingIntentsMap.Store(fmt.Sprintf("%s-%d", intent.PodID, process.PID), threadIntents)
+						allSchedulingIntents = append(allSchedulingIntents, threadIntents...)
+					}
 					continue
 				}
 				schedulingIntent := &domain.SchedulingIntents{
@@ -137,18 +201,102 @@ func (svc *Service) resolveSchedulingIntents(ctx context.Context, intents []*dom
 					CommandRegex:  intent.CommandRegex,
 					Selectors:     labels,
 				}
-				logger.Logger(ctx).Info().Msgf("Created SchedulingIntent: %+v for Process PID: %d", schedulingIntent, process.PID)
+				logger.Logger(ctx).Debug().Msgf("Created SchedulingIntent: %+v for Process PID: %d", schedulingIntent, process.PID)
 				svc.schedulingIntentsMap.Store(fmt.Sprintf("%s-%d", intent.PodID, process.PID), []*domain.SchedulingIntents{schedulingIntent})
 				allSchedulingIntents = append(allSchedulingIntents, schedulingIntent)
 			}
 		}
 	}
+	svc.exportSchedulingIntents(ctx, allSchedulingIntents)
 	return allSchedulingIntents
 }
 
+// resolveThreadSchedulingIntents creates one SchedulingIntents per thread of pid whose comm matches threadRegex
+func (svc *Service) resolveThreadSchedulingIntents(ctx context.Context, intent *domain.Intent, pid int, threadRegex *regexp.Regexp, labels []domain.LabelSelector) []*domain.SchedulingIntents {
+	threads, err := svc.getProcessThreads(svc.procRootDir(), pid)
+	if err != nil {
+		logger.Logger(ctx).Warn().Err(err).Msgf("failed to read threads for pid %d", pid)
+		return nil
+	}
+	var results []*domain.SchedulingIntents
+	for _, thread := range threads {
+		if !threadRegex.MatchString(thread.Command) {
+			continue
+		}
+		schedulingIntent := &domain.SchedulingIntents{
+			Priority:      intent.Priority,
+			ExecutionTime: uint64(intent.ExecutionTime),
+			PID:           pid,
+			TID:           thread.TID,
+			CommandRegex:  intent.CommandRegex,
+			Selectors:     labels,
+		}
+		logger.Logger(ctx).Debug().Msgf("Created SchedulingIntent: %+v for Thread TID: %d (%s) of PID: %d", schedulingIntent, thread.TID, thread.Command, pid)
+		results = append(results, schedulingIntent)
+	}
+	return results
+}
+
+// getProcessThreads reads the threads of a process from /proc/<pid>/task/<tid>/comm
+func (svc *Service) getProcessThreads(rootDir string, pid int) ([]domain.ProcessThread, error) {
+	entries, err := os.ReadDir(fmt.Sprintf("%s/%d/task", rootDir, pid))
+	if err != nil {
+		return nil, err
+	}
+	threads := make([]domain.ProcessThread, 0, len(entries))
+	for _, entry := range entries {
+		tid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		// threads may exit while scanning, skip them
+		data, err := os.ReadFile(fmt.Sprintf("%s/%d/task/%d/comm", rootDir, pid, tid))
+		if err != nil {
+			continue
+		}
+		threads = append(threads, domain.ProcessThread{
+			TID:     tid,
+			Command: strings.TrimSpace(string(data)),
+		})
+	}
+	return threads, nil
+}
+
+// matchProcess reports whether cmdRegex matches any of the intent's match targets for the process
+func matchProcess(cmdRegex *regexp.Regexp, intent *domain.Intent, process domain.PodProcess) bool {
+	targets := intent.CommandMatchTargets
+	if len(targets) == 0 {
+		targets = domain.DefaultCommandMatchTargets
+	}
+	for _, target := range targets {
+		var value string
+		switch target {
+		case domain.CommandMatchComm:
+			value = process.Command
+		case domain.CommandMatchCmdline:
+			value = process.Cmdline
+		case domain.CommandMatchExe:
+			value = process.Exe
+		case domain.CommandMatchContainerName:
+			value = intent.ContainerNames[process.ContainerID]
+		}
+		if value != "" && cmdRegex.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetAllPodInfos retrieves all pod information by scanning the /proc filesystem
 func (svc *Service) GetAllPodInfos(ctx context.Context) (map[string]*domain.PodInfo, error) {
-	return svc.FindPodInfoFrom(ctx, procDir)
+	return svc.FindPodInfoFrom(ctx, svc.procRootDir())
+}
+
+func (svc *Service) procRootDir() string {
+	if svc.procRoot != "" {
+		return svc.procRoot
+	}
+	return procDir
 }
 
 // FindPodInfoFrom scans the given rootDir (e.g., /proc) to find pod information
@@ -173,60 +321,79 @@ func (svc *Service) FindPodInfoFrom(ctx context.Context, rootDir string) (map[st
 			continue
 		}
 
-		// Read cgroup information for this process
+		// Read cgroup information for this process. On cgroup v1 hosts every
+		// controller hierarchy contributes a kubepods line, so collect the pod
+		// and container IDs across all of them and record the process once.
 		cgroupPath := fmt.Sprintf("%s/%d/cgroup", rootDir, pid)
 		file, err := os.Open(cgroupPath)
 		if err != nil {
 			logger.Logger(ctx).Warn().Err(err).Msgf("failed to open cgroup file for pid %d", pid)
 			continue
 		}
+		var podUID, containerID string
 		scanner := bufio.NewScanner(file)
 		for scanner.Scan() {
 			line := scanner.Text()
 			logger.Logger(ctx).Debug().Msgf("cgroup line for pid %d: %s", pid, line)
-			if strings.Contains(line, "kubepods") {
-				err = svc.parseCgroupToPodInfo(rootDir, line, pid, podMap)
-				if err != nil {
-					logger.Logger(ctx).Warn().Err(err).Msgf("failed to parse cgroup line for pid %d, line:%s", pid, line)
-					break
-				}
+			if !strings.Contains(line, "kubepods") {
+				continue
+			}
+			lineUID, lineContainerID, err := svc.parseCgroupLine(line)
+			if err != nil {
+				logger.Logger(ctx).Debug().Err(err).Msgf("failed to parse cgroup line for pid %d, line:%s", pid, line)
+				continue
+			}
+			if podUID == "" {
+				podUID = lineUID
+			}
+			if containerID == "" {
+				containerID = lineContainerID
+			}
+			if containerID != "" {
+				break
 			}
 		}
 		if err := scanner.Err(); err != nil {
+			logger.Logger(ctx).Warn().Err(err).Msgf("failed to read cgroup file for pid %d", pid)
 		}
 		_ = file.Close()
+		if podUID == "" {
+			continue
+		}
+
+		err = svc.addProcessToPodInfo(rootDir, pid, podUID, containerID, podMap)
+		if err != nil {
+			logger.Logger(ctx).Warn().Err(err).Msgf("failed to read process info for pid %d", pid)
+		}
 	}
 
 	return podMap, nil
 }
 
-// parseCgroupToPodInfo parses a cgroup line (e.g // 0::/kubelet.slice/kubelet-kubepods.slice/kubelet-kubepods-pod20da609e_6973_4463_a1f9_2db9bcc5becc.slice/cri-containerd-10ec3c89629f71226b227e6510b2d465168b24005bbdcc5d7940517080830635.scope) to extract pod info and updates the podInfoMap
-func (svc *Service) parseCgroupToPodInfo(rootDir string, line string, pid int, podInfoMap map[string]*domain.PodInfo) error {
-	parts := strings.Split(line, ":")
-	if len(parts) >= 3 {
-		cgroupHierarchy := parts[2]
+// parseCgroupLine parses a cgroup line (e.g // 0::/kubelet.slice/kubelet-kubepods.slice/kubelet-kubepods-pod20da609e_6973_4463_a1f9_2db9bcc5becc.slice/cri-containerd-10ec3c89629f71226b227e6510b2d465168b24005bbdcc5d7940517080830635.scope) and returns the pod UID and container ID
+func (svc *Service) parseCgroupLine(line string) (podUID string, containerID string, err error) {
+	// hierarchy-ID:controller-list:cgroup-path, the path itself never contains ':'
+	parts := strings.SplitN(line, ":", 3)
+	if len(parts) < 3 {
+		return "", "", fmt.Errorf("malformed cgroup line")
+	}
+	return svc.getPodInfoFromCgroup(parts[2])
+}
 
-		// Extract pod information
-		podUID, containerID, err := svc.getPodInfoFromCgroup(cgroupHierarchy)
-		if err != nil {
-			return err
-		}
+// addProcessToPodInfo reads the process information of pid and appends it to the pod entry of podUID
+func (svc *Service) addProcessToPodInfo(rootDir string, pid int, podUID, containerID string, podInfoMap map[string]*domain.PodInfo) error {
+	process, err := svc.getProcessInfo(rootDir, pid)
+	if err != nil {
+		return err
+	}
+	process.ContainerID = containerID
 
-		// Get process information
-		process, err := svc.getProcessInfo(rootDir, pid)
-		if err != nil {
-			return err
-		}
-		process.ContainerID = containerID
-
-		// Create or update pod info
-		if podInfo, exists := podInfoMap[podUID]; exists {
-			podInfo.Processes = append(podInfo.Processes, process)
-		} else {
-			podInfoMap[podUID] = &domain.PodInfo{
-				PodUID:    podUID,
-				Processes: []domain.PodProcess{process},
-			}
+	if podInfo, exists := podInfoMap[podUID]; exists {
+		podInfo.Processes = append(podInfo.Processes, process)
+	} else {
+		podInfoMap[podUID] = &domain.PodInfo{
+			PodUID:    podUID,
+			Processes: []domain.PodProcess{process},
 		}
 	}
 	return nil
@@ -237,19 +404,36 @@ func (svc *Service) parseCgroupToPodInfo(rootDir string, line string, pid int, p
 // - cgroupfs: /kubepods/burstable/pod31e4e721-a5a0-421a-ae1d-b7971ae30d6e/ (dashes)
 var podRegex = regexp.MustCompile(`pod([0-9a-fA-F]{8}[-_][0-9a-fA-F]{4}[-_][0-9a-fA-F]{4}[-_][0-9a-fA-F]{4}[-_][0-9a-fA-F]{12})`)
 
+// containerIDRegex matches a bare container ID as used by cgroupfs drivers
+// (e.g. /kubepods/burstable/pod<uid>/<container-id>)
+var containerIDRegex = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
+// containerScopePrefixes lists the systemd scope prefixes used by the supported container runtimes
+var containerScopePrefixes = []string{
+	"cri-containerd-",
+	"crio-",
+	"docker-",
+}
+
 // getPodInfoFromCgroup extracts pod information from cgroup path
 func (svc *Service) getPodInfoFromCgroup(cgroupPath string) (podUID string, containerID string, err error) {
 	// Parse cgroup path to extract pod information
 	// 0::/kubelet.slice/kubelet-kubepods.slice/kubelet-kubepods-pod20da609e_6973_4463_a1f9_2db9bcc5becc.slice/cri-containerd-10ec3c89629f71226b227e6510b2d465168b24005bbdcc5d7940517080830635.scope
+	// 0::/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod<uid>.slice/crio-<id>.scope
+	// 4:cpu,cpuacct:/kubepods/burstable/pod<uid>/<id>
 	parts := strings.Split(cgroupPath, "/")
-	for _, part := range parts {
+	for i, part := range parts {
 		if podRegex.MatchString(part) {
 			podUID = podRegex.FindStringSubmatch(part)[1]
 			podUID = strings.ReplaceAll(podUID, "_", "-")
+			// cgroupfs driver: the container directory directly follows the pod directory
+			if i+1 < len(parts) && containerIDRegex.MatchString(parts[i+1]) {
+				containerID = parts[i+1]
+			}
+			continue
 		}
-		if strings.HasPrefix(part, "cri-containerd-") && strings.HasSuffix(part, ".scope") {
-			containerID = strings.TrimPrefix(part, "cri-containerd-")
-			containerID = strings.TrimSuffix(containerID, ".scope")
+		if id := containerIDFromScope(part); id != "" {
+			containerID = id
 		}
 	}
 
@@ -260,6 +444,24 @@ func (svc *Service) getPodInfoFromCgroup(cgroupPath string) (podUID string, cont
 	return podUID, containerID, nil
 }
 
+// containerIDFromScope extracts the container ID from a systemd scope unit name
+// such as cri-containerd-<id>.scope, crio-<id>.scope or docker-<id>.scope
+func containerIDFromScope(part string) string {
+	if !strings.HasSuffix(part, ".scope") {
+		return ""
+	}
+	// CRI-O places conmon in its own crio-conmon-<id>.scope, which is not a container process
+	if strings.HasPrefix(part, "crio-conmon-") {
+		return ""
+	}
+	for _, prefix := range containerScopePrefixes {
+		if strings.HasPrefix(part, prefix) {
+			return strings.TrimSuffix(strings.TrimPrefix(part, prefix), ".scope")
+		}
+	}
+	return ""
+}
+
 // getProcessInfo reads process information from /proc/<pid>/
 func (svc *Service) getProcessInfo(rootDir string, pid int) (domain.PodProcess, error) {
 	process := domain.PodProcess{PID: pid}
@@ -270,6 +472,19 @@ func (svc *Service) getProcessInfo(rootDir string, pid int) (domain.PodProcess,
 		process.Command = strings.TrimSpace(string(data))
 	}
 
+	// Read the full command line from /proc/<pid>/cmdline, arguments are NUL separated
+	cmdlinePath := fmt.Sprintf("/%s/%d/cmdline", rootDir, pid)
+	if data, err := os.ReadFile(cmdlinePath); err == nil {
+		args := strings.Split(strings.TrimRight(string(data), "\x00"), "\x00")
+		process.Cmdline = strings.TrimSpace(strings.Join(args, " "))
+	}
+
+	// Resolve the executable path from /proc/<pid>/exe, this needs ptrace access to the process
+	exePath := fmt.Sprintf("/%s/%d/exe", rootDir, pid)
+	if exe, err := os.Readlink(exePath); err == nil {
+		process.Exe = exe
+	}
+
 	// Read PPID from /proc/<pid>/stat
 	statPath := fmt.Sprintf("/%s/%d/stat", rootDir, pid)
 	if data, err := os.ReadFile(statPath); err == nil {
@@ -285,7 +500,86 @@ func (svc *Service) getProcessInfo(rootDir string, pid int) (domain.PodProcess,
 }
 
 func (svc *Service) UpdateMetrics(ctx context.Context, newMetricSet *domain.MetricSet) {
+	newMetricSet.CPUs = dedupeCPUMetrics(newMetricSet.CPUs)
+	newMetricSet.Pods = svc.resolvePodMetrics(ctx, newMetricSet.Pods)
 	svc.metricCollector.UpdateMetrics(newMetricSet)
+	now := time.Now()
+	svc.latestMetrics.Store(&domain.MetricSample{Timestamp: now, MetricSet: *newMetricSet})
+	if svc.metricHistory != nil {
+		svc.metricHistory.Add(now, newMetricSet)
+	}
+}
+
+// LatestMetrics returns the last metric set received from the scheduler, nil if none was received yet
+func (svc *Service) LatestMetrics(ctx context.Context) *domain.MetricSample {
+	return svc.latestMetrics.Load()
+}
+
+// dedupeCPUMetrics keeps the last entry reported for each CPU, sorted by CPU,
+// since duplicate label sets would make the whole Prometheus scrape fail
+func dedupeCPUMetrics(cpus []domain.CPUMetrics) []domain.CPUMetrics {
+	byCPU := make(map[int]domain.CPUMetrics, len(cpus))
+	for _, cpu := range cpus {
+		byCPU[cpu.CPU] = cpu
+	}
+	results := make([]domain.CPUMetrics, 0, len(byCPU))
+	for _, cpu := range byCPU {
+		results = append(results, cpu)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].CPU < results[j].CPU
+	})
+	return results
+}
+
+// resolvePodMetrics resolves the pod UID of entries reported by cgroup path, merges entries of
+// the same pod and labels them with the pod name, namespace and strategy IDs from the intent cache
+func (svc *Service) resolvePodMetrics(ctx context.Context, pods []domain.PodMetrics) []domain.PodMetrics {
+	if len(pods) == 0 {
+		return nil
+	}
+	svc.intentCacheMu.RLock()
+	cachedIntents := svc.intentCache
+	svc.intentCacheMu.RUnlock()
+	intentsByPod := make(map[string][]*domain.Intent)
+	for _, intent := range cachedIntents {
+		intentsByPod[intent.PodID] = append(intentsByPod[intent.PodID], intent)
+	}
+
+	indexByPod := make(map[string]int, len(pods))
+	results := make([]domain.PodMetrics, 0, len(pods))
+	for _, pod := range pods {
+		if pod.PodUID == "" {
+			podUID, _, err := svc.getPodInfoFromCgroup(pod.CgroupPath)
+			if err != nil {
+				logger.Logger(ctx).Warn().Err(err).Msgf("failed to resolve pod of cgroup %s, dropping its metrics", pod.CgroupPath)
+				continue
+			}
+			pod.PodUID = podUID
+		}
+		pod.CgroupPath = ""
+		if i, ok := indexByPod[pod.PodUID]; ok {
+			existing := &results[i]
+			existing.NrQueued += pod.NrQueued
+			existing.NrDispatches += pod.NrDispatches
+			existing.QueueWaitNs += pod.QueueWaitNs
+			existing.RunTimeNs += pod.RunTimeNs
+			continue
+		}
+		strategyIDs := make([]string, 0, 1)
+		for _, intent := range intentsByPod[pod.PodUID] {
+			pod.PodName = intent.PodName
+			pod.K8sNamespace = intent.K8sNamespace
+			if intent.StrategyID != "" && !slices.Contains(strategyIDs, intent.StrategyID) {
+				strategyIDs = append(strategyIDs, intent.StrategyID)
+			}
+		}
+		sort.Strings(strategyIDs)
+		pod.StrategyID = strings.Join(strategyIDs, ",")
+		indexByPod[pod.PodUID] = len(results)
+		results = append(results, pod)
+	}
+	return results
 }
 
 func normalizeIntentInputs(intents []*domain.Intent) []*domain.Intent {
@@ -324,6 +618,27 @@ func hashIntent(intent *domain.Intent) string {
 		"executionTime=" + strconv.FormatInt(intent.ExecutionTime, 10),
 		"podLabels=" + strings.Join(labels, ","),
 	}, "|")
+	// Match options are only part of the hash when set, so intents created before
+	// they existed keep their hash and do not trigger a resync.
+	if intent.ThreadRegex != "" {
+		serialized += "|threadRegex=" + intent.ThreadRegex
+	}
+	// Intents cached by decision makers before the strategy ID was sent hash differently
+	// and are resent once, which gives the decision maker the ID to label metrics with.
+	if intent.StrategyID != "" {
+		serialized += "|strategyID=" + intent.StrategyID
+	}
+	if len(intent.CommandMatchTargets) > 0 {
+		serialized += "|commandMatchTargets=" + strings.Join(intent.CommandMatchTargets, ",")
+	}
+	if len(intent.ContainerNames) > 0 {
+		containers := make([]string, 0, len(intent.ContainerNames))
+		for id, name := range intent.ContainerNames {
+			containers = append(containers, id+"="+name)
+		}
+		sort.Strings(containers)
+		serialized += "|containerNames=" + strings.Join(containers, ",")
+	}
 	return util.HashStringSHA256Hex(serialized)
 }
 
@@ -380,6 +695,18 @@ func (svc *Service) refreshIntentMerkleTreeIfNeeded() {
 
 // DeleteIntentByPodID deletes all scheduling intents for a specific pod ID
 func (svc *Service) DeleteIntentByPodID(ctx context.Context, podID string) error {
+	svc.resolveMu.Lock()
+	defer svc.resolveMu.Unlock()
+	svc.intentCacheMu.Lock()
+	intents := make([]*domain.Intent, 0, len(svc.intentCache))
+	for _, intent := range svc.intentCache {
+		if intent.PodID != podID {
+			intents = append(intents, intent)
+		}
+	}
+	svc.setIntentCacheLocked(intents)
+	svc.intentCacheMu.Unlock()
+
 	keysToDelete := []string{}
 	svc.schedulingIntentsMap.Range(func(key string, value []*domain.SchedulingIntents) bool {
 		if strings.HasPrefix(key, podID+"-") {
@@ -390,20 +717,40 @@ func (svc *Service) DeleteIntentByPodID(ctx context.Context, podID string) error
 	for _, key := range keysToDelete {
 		svc.schedulingIntentsMap.Delete(key)
 	}
+	svc.exportSchedulingIntents(ctx, svc.storedSchedulingIntents())
 	logger.Logger(ctx).Info().Msgf("Deleted %d scheduling intents for pod ID: %s", len(keysToDelete), podID)
 	return nil
 }
 
-// DeleteIntentByPID deletes a specific scheduling intent by pod ID and PID
+// DeleteIntentByPID deletes a specific scheduling intent by pod ID and PID, the process stays
+// excluded from the intents of its pod until the next ProcessIntents
 func (svc *Service) DeleteIntentByPID(ctx context.Context, podID string, pid int) error {
+	svc.resolveMu.Lock()
+	defer svc.resolveMu.Unlock()
 	key := fmt.Sprintf("%s-%d", podID, pid)
+	svc.intentCacheMu.Lock()
+	deletedProcesses := make(map[string]struct{}, len(svc.deletedProcesses)+1)
+	for deleted := range svc.deletedProcesses {
+		deletedProcesses[deleted] = struct{}{}
+	}
+	deletedProcesses[key] = struct{}{}
+	svc.deletedProcesses = deletedProcesses
+	svc.intentCacheMu.Unlock()
+
 	svc.schedulingIntentsMap.Delete(key)
+	svc.exportSchedulingIntents(ctx, svc.storedSchedulingIntents())
 	logger.Logger(ctx).Info().Msgf("Deleted scheduling intent for key: %s", key)
 	return nil
 }
 
 // DeleteAllIntents clears all scheduling intents
 func (svc *Service) DeleteAllIntents(ctx context.Context) error {
+	svc.resolveMu.Lock()
+	defer svc.resolveMu.Unlock()
+	svc.intentCacheMu.Lock()
+	svc.setIntentCacheLocked(nil)
+	svc.intentCacheMu.Unlock()
+
 	keysToDelete := []string{}
 	svc.schedulingIntentsMap.Range(func(key string, value []*domain.SchedulingIntents) bool {
 		keysToDelete = append(keysToDelete, key)
@@ -413,7 +760,29 @@ func (svc *Service) DeleteAllIntents(ctx context.Context) error {
 	for _, key := range keysToDelete {
 		svc.schedulingIntentsMap.Delete(key)
 	}
+	svc.exportSchedulingIntents(ctx, nil)
 
 	logger.Logger(ctx).Info().Msgf("Deleted all %d scheduling intents", len(keysToDelete))
 	return nil
 }
+
+// setIntentCacheLocked replaces the cached intents after a delete, the merkle tree is rebuilt on
+// the next traversal. Callers hold intentCacheMu.
+func (svc *Service) setIntentCacheLocked(intents []*domain.Intent) {
+	svc.intentCache = intents
+	svc.intentMerkleRoot = nil
+	svc.intentMerkleRootHash = ""
+	if len(intents) == 0 {
+		svc.deletedProcesses = nil
+	}
+}
+
+// storedSchedulingIntents returns the resolved scheduling intents of schedulingIntentsMap
+func (svc *Service) storedSchedulingIntents() []*domain.SchedulingIntents {
+	var intents []*domain.SchedulingIntents
+	svc.schedulingIntentsMap.Range(func(key string, value []*domain.SchedulingIntents) bool {
+		intents = append(intents, value...)
+		return true
+	})
+	return intents
+}
@@ -6,7 +6,10 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/Gthulhu/api/decisionmaker/domain"
+	"github.com/Gthulhu/api/pkg/intenttable"
 	"github.com/Gthulhu/api/pkg/logger"
+	"github.com/Gthulhu/api/pkg/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -85,3 +88,318 @@ func TestFindPodInfoFrom(t *testing.T) {
 	require.Len(t, p2.Processes, 1, "should have one process")
 	assert.EqualValues(t, p2.Processes[0].Command, "busybox", "unexpected command")
 }
+
+// writeFakeProcess creates /<root>/<pid>/{cgroup,comm,stat} for a fake process
+func writeFakeProcess(t *testing.T, root, pid, cgroupContent, comm string) {
+	pidDir := filepath.Join(root, pid)
+	require.NoError(t, os.Mkdir(pidDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pidDir, "cgroup"), []byte(cgroupContent), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(pidDir, "comm"), []byte(comm+"\n"), 0644))
+	statLine := pid + " (" + comm + ") S 1 2 3 4 5 0 0 0 0 0 0 0 0 0 0 0 0 0 0"
+	require.NoError(t, os.WriteFile(filepath.Join(pidDir, "stat"), []byte(statLine), 0644))
+}
+
+// TestFindPodInfoFromContainerRuntimes tests the cgroup layouts of CRI-O, Docker and cgroup v1 hosts
+func TestFindPodInfoFromContainerRuntimes(t *testing.T) {
+	logger.InitLogger()
+	const (
+		containerID = "10ec3c89629f71226b227e6510b2d465168b24005bbdcc5d7940517080830635"
+		podUID      = "20da609e-6973-4463-a1f9-2db9bcc5becc"
+	)
+
+	testCases := []struct {
+		name                string
+		cgroupContent       string
+		expectedContainerID string
+	}{
+		{
+			name:                "cri-o systemd",
+			cgroupContent:       "0::/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod20da609e_6973_4463_a1f9_2db9bcc5becc.slice/crio-" + containerID + ".scope\n",
+			expectedContainerID: containerID,
+		},
+		{
+			name:                "cri-o conmon",
+			cgroupContent:       "0::/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod20da609e_6973_4463_a1f9_2db9bcc5becc.slice/crio-conmon-" + containerID + ".scope\n",
+			expectedContainerID: "",
+		},
+		{
+			name:                "docker systemd",
+			cgroupContent:       "0::/kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod20da609e_6973_4463_a1f9_2db9bcc5becc.slice/docker-" + containerID + ".scope\n",
+			expectedContainerID: containerID,
+		},
+		{
+			name:                "cgroupfs v2",
+			cgroupContent:       "0::/kubepods/burstable/pod20da609e-6973-4463-a1f9-2db9bcc5becc/" + containerID + "\n",
+			expectedContainerID: containerID,
+		},
+		{
+			name: "cgroupfs v1 with multiple hierarchies",
+			cgroupContent: "12:pids:/kubepods/burstable/pod20da609e-6973-4463-a1f9-2db9bcc5becc/" + containerID + "\n" +
+				"11:memory:/kubepods/burstable/pod20da609e-6973-4463-a1f9-2db9bcc5becc/" + containerID + "\n" +
+				"4:cpu,cpuacct:/kubepods/burstable/pod20da609e-6973-4463-a1f9-2db9bcc5becc/" + containerID + "\n" +
+				"1:name=systemd:/kubepods/burstable/pod20da609e-6973-4463-a1f9-2db9bcc5becc/" + containerID + "\n" +
+				"0::/\n",
+			expectedContainerID: containerID,
+		},
+		{
+			name: "systemd v1 with pod-level hierarchy first",
+			cgroupContent: "10:devices:/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod20da609e_6973_4463_a1f9_2db9bcc5becc.slice\n" +
+				"4:cpu,cpuacct:/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod20da609e_6973_4463_a1f9_2db9bcc5becc.slice/cri-containerd-" + containerID + ".scope\n",
+			expectedContainerID: containerID,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeFakeProcess(t, root, "1234", tc.cgroupContent, "java")
+			writeFakeProcess(t, root, "4321", "0::/system.slice/sshd.service\n", "sshd")
+			svc := &Service{}
+
+			pods, err := svc.FindPodInfoFrom(context.Background(), root)
+			require.NoError(t, err)
+			require.Len(t, pods, 1)
+			p := pods[podUID]
+			require.NotNil(t, p)
+			require.Len(t, p.Processes, 1, "process should be recorded once")
+			assert.Equal(t, 1234, p.Processes[0].PID)
+			assert.Equal(t, "java", p.Processes[0].Command)
+			assert.Equal(t, tc.expectedContainerID, p.Processes[0].ContainerID)
+		})
+	}
+}
+
+// TestResolveSchedulingIntentsCommandMatchTargets tests matching CommandRegex against comm, cmdline, exe and container name
+func TestResolveSchedulingIntentsCommandMatchTargets(t *testing.T) {
+	logger.InitLogger()
+	const containerID = "10ec3c89629f71226b227e6510b2d465168b24005bbdcc5d7940517080830635"
+	root := t.TempDir()
+	writeFakeProcess(t, root, "1234", "0::/kubepods/burstable/pod20da609e-6973-4463-a1f9-2db9bcc5becc/"+containerID+"\n", "java")
+	require.NoError(t, os.WriteFile(filepath.Join(root, "1234", "cmdline"), []byte("java\x00-jar\x00payments.jar\x00"), 0644))
+	require.NoError(t, os.Symlink("/usr/lib/jvm/bin/java", filepath.Join(root, "1234", "exe")))
+
+	svc := &Service{schedulingIntentsMap: util.NewGenericMap[string, []*domain.SchedulingIntents]()}
+	podInfos, err := svc.FindPodInfoFrom(context.Background(), root)
+	require.NoError(t, err)
+	p := podInfos["20da609e-6973-4463-a1f9-2db9bcc5becc"]
+	require.NotNil(t, p)
+	require.Len(t, p.Processes, 1)
+	assert.Equal(t, "java -jar payments.jar", p.Processes[0].Cmdline)
+	assert.Equal(t, "/usr/lib/jvm/bin/java", p.Processes[0].Exe)
+
+	testCases := []struct {
+		name          string
+		commandRegex  string
+		targets       []string
+		expectMatched bool
+	}{
+		{name: "default targets match comm", commandRegex: "^java$", expectMatched: true},
+		{name: "default targets ignore cmdline", commandRegex: "payments", expectMatched: false},
+		{name: "comm only", commandRegex: "payments", targets: []string{domain.CommandMatchComm}, expectMatched: false},
+		{name: "cmdline only", commandRegex: `-jar payments\.jar`, targets: []string{domain.CommandMatchCmdline}, expectMatched: true},
+		{name: "exe", commandRegex: "^/usr/lib/jvm/", targets: []string{domain.CommandMatchExe}, expectMatched: true},
+		{name: "container name", commandRegex: "^payments-api$", targets: []string{domain.CommandMatchContainerName}, expectMatched: true},
+		{name: "invalid regex", commandRegex: "(", expectMatched: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			intent := &domain.Intent{
+				PodID:               "20da609e-6973-4463-a1f9-2db9bcc5becc",
+				CommandRegex:        tc.commandRegex,
+				CommandMatchTargets: tc.targets,
+				ContainerNames:      map[string]string{containerID: "payments-api"},
+			}
+			resolved := svc.resolveSchedulingIntents(context.Background(), []*domain.Intent{intent}, podInfos)
+			if tc.expectMatched {
+				require.Len(t, resolved, 1)
+				assert.Equal(t, 1234, resolved[0].PID)
+			} else {
+				assert.Empty(t, resolved)
+			}
+		})
+	}
+}
+
+// TestResolveSchedulingIntentsThreadRegex tests that a thread regex resolves per-thread scheduling intents
+func TestResolveSchedulingIntentsThreadRegex(t *testing.T) {
+	logger.InitLogger()
+	root := t.TempDir()
+	writeFakeProcess(t, root, "1234", "0::/kubepods/burstable/pod20da609e-6973-4463-a1f9-2db9bcc5becc/\n", "envoy")
+	for tid, comm := range map[string]string{"1234": "envoy", "1240": "wrk:worker_0", "1241": "wrk:worker_1", "1250": "GrpcGoogClient"} {
+		taskDir := filepath.Join(root, "1234", "task", tid)
+		require.NoError(t, os.MkdirAll(taskDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(taskDir, "comm"), []byte(comm+"\n"), 0644))
+	}
+
+	svc := &Service{
+		schedulingIntentsMap: util.NewGenericMap[string, []*domain.SchedulingIntents](),
+		procRoot:             root,
+	}
+	podInfos, err := svc.GetAllPodInfos(context.Background())
+	require.NoError(t, err)
+
+	intent := &domain.Intent{
+		PodID:         "20da609e-6973-4463-a1f9-2db9bcc5becc",
+		CommandRegex:  "envoy",
+		ThreadRegex:   "^wrk:worker_",
+		Priority:      10,
+		ExecutionTime: 20000,
+	}
+	resolved := svc.resolveSchedulingIntents(context.Background(), []*domain.Intent{intent}, podInfos)
+	require.Len(t, resolved, 2)
+	tids := []int{resolved[0].TID, resolved[1].TID}
+	assert.ElementsMatch(t, []int{1240, 1241}, tids)
+	for _, r := range resolved {
+		assert.Equal(t, 1234, r.PID)
+		assert.Equal(t, 10, r.Priority)
+	}
+
+	stored, ok := svc.schedulingIntentsMap.Load("20da609e-6973-4463-a1f9-2db9bcc5becc-1234")
+	require.True(t, ok)
+	assert.Len(t, stored, 2)
+}
+
+func TestExplainPod(t *testing.T) {
+	logger.InitLogger()
+	const podID = "20da609e-6973-4463-a1f9-2db9bcc5becc"
+	root := t.TempDir()
+	cgroupContent := "0::/kubepods/burstable/pod" + podID + "/\n"
+	writeFakeProcess(t, root, "100", cgroupContent, "pause")
+	writeFakeProcess(t, root, "200", cgroupContent, "envoy")
+	writeFakeProcess(t, root, "300", cgroupContent, "sidecar")
+	for tid, comm := range map[string]string{"200": "envoy", "201": "wrk:worker_0"} {
+		taskDir := filepath.Join(root, "200", "task", tid)
+		require.NoError(t, os.MkdirAll(taskDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(taskDir, "comm"), []byte(comm+"\n"), 0644))
+	}
+
+	svc := &Service{
+		schedulingIntentsMap: util.NewGenericMap[string, []*domain.SchedulingIntents](),
+		procRoot:             root,
+		intentCache: []*domain.Intent{
+			{PodID: podID, CommandRegex: "envoy", Priority: 10, ExecutionTime: 20000},
+			{PodID: podID, CommandRegex: "envoy", ThreadRegex: "^wrk:", Priority: 5},
+			{PodID: podID, CommandRegex: "("},
+			{PodID: "other-pod", CommandRegex: ".*"},
+		},
+	}
+
+	explanation, err := svc.ExplainPod(context.Background(), podID)
+	require.NoError(t, err)
+	assert.True(t, explanation.PodFound)
+	assert.ElementsMatch(t, []int{100, 200, 300}, explanation.ProcessPIDs)
+	require.Len(t, explanation.Intents, 3)
+
+	outcomes := func(intent domain.IntentExplanation) map[int]domain.ProcessOutcome {
+		result := make(map[int]domain.ProcessOutcome, len(intent.Processes))
+		for _, process := range intent.Processes {
+			result[process.PID] = process.Outcome
+		}
+		return result
+	}
+	assert.Equal(t, map[int]domain.ProcessOutcome{
+		100: domain.ProcessOutcomeSkippedPause,
+		200: domain.ProcessOutcomeMatched,
+		300: domain.ProcessOutcomeRegexMismatch,
+	}, outcomes(explanation.Intents[0]))
+	assert.Equal(t, 10, explanation.Intents[0].Priority)
+	assert.Equal(t, int64(20000), explanation.Intents[0].ExecutionTime)
+
+	assert.Equal(t, domain.ProcessOutcomeMatched, outcomes(explanation.Intents[1])[200])
+	for _, process := range explanation.Intents[1].Processes {
+		if process.PID == 200 {
+			assert.Equal(t, []int{201}, process.TIDs)
+		}
+	}
+
+	assert.NotEmpty(t, explanation.Intents[2].Error)
+	assert.Empty(t, explanation.Intents[2].Processes)
+
+	missing, err := svc.ExplainPod(context.Background(), "missing-pod")
+	require.NoError(t, err)
+	assert.False(t, missing.PodFound)
+	assert.Empty(t, missing.Intents)
+}
+
+func TestResolveSchedulingIntentsExport(t *testing.T) {
+	logger.InitLogger()
+	root := t.TempDir()
+	writeFakeProcess(t, root, "1234", "0::/kubepods/burstable/pod20da609e-6973-4463-a1f9-2db9bcc5becc/\n", "nginx")
+	exportPath := filepath.Join(t.TempDir(), "intents.bin")
+
+	svc := &Service{
+		schedulingIntentsMap: util.NewGenericMap[string, []*domain.SchedulingIntents](),
+		procRoot:             root,
+		intentExporter:       newIntentExporter(exportPath),
+	}
+
+	// an empty table is published before any intent arrives
+	require.NoError(t, svc.RefreshIntentExport(context.Background()))
+	table, err := intenttable.ReadFile(exportPath)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), table.Generation)
+	assert.Empty(t, table.Entries)
+
+	intent := &domain.Intent{
+		PodID:         "20da609e-6973-4463-a1f9-2db9bcc5becc",
+		CommandRegex:  "nginx",
+		Priority:      10,
+		ExecutionTime: 20000,
+	}
+	require.NoError(t, svc.ProcessIntents(context.Background(), []*domain.Intent{intent}))
+	table, err = intenttable.ReadFile(exportPath)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), table.Generation)
+	assert.Equal(t, []intenttable.Entry{{PID: 1234, Priority: 10, ExecutionTime: 20000}}, table.Entries)
+
+	// an unchanged resolution does not rewrite the table
+	require.NoError(t, svc.RefreshIntentExport(context.Background()))
+	table, err = intenttable.ReadFile(exportPath)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), table.Generation)
+}
+
+func TestDeleteIntentsPruneExport(t *testing.T) {
+	logger.InitLogger()
+	root := t.TempDir()
+	cgroup := "0::/kubepods/burstable/pod20da609e-6973-4463-a1f9-2db9bcc5becc/\n"
+	writeFakeProcess(t, root, "1234", cgroup, "nginx")
+	writeFakeProcess(t, root, "1235", cgroup, "nginx")
+	exportPath := filepath.Join(t.TempDir(), "intents.bin")
+	svc := &Service{
+		schedulingIntentsMap: util.NewGenericMap[string, []*domain.SchedulingIntents](),
+		procRoot:             root,
+		intentExporter:       newIntentExporter(exportPath),
+	}
+	ctx := context.Background()
+	podID := "20da609e-6973-4463-a1f9-2db9bcc5becc"
+	intents := []*domain.Intent{{PodID: podID, CommandRegex: "nginx", Priority: 10}}
+	// deletes outlast the periodic refresh, both in the listing and the exported table
+	exported := func() []int32 {
+		t.Helper()
+		require.NoError(t, svc.RefreshIntentExport(ctx))
+		table, err := intenttable.ReadFile(exportPath)
+		require.NoError(t, err)
+		pids := []int32{}
+		for _, entry := range table.Entries {
+			pids = append(pids, entry.PID)
+		}
+		listed, err := svc.ListAllSchedulingIntents(ctx)
+		require.NoError(t, err)
+		assert.Len(t, listed, len(pids))
+		return pids
+	}
+
+	require.NoError(t, svc.ProcessIntents(ctx, intents))
+	assert.Equal(t, []int32{1234, 1235}, exported())
+	require.NoError(t, svc.DeleteIntentByPID(ctx, podID, 1235))
+	assert.Equal(t, []int32{1234}, exported())
+	require.NoError(t, svc.DeleteIntentByPodID(ctx, podID))
+	assert.Empty(t, exported())
+
+	// intents sent again replace the deletions
+	require.NoError(t, svc.ProcessIntents(ctx, intents))
+	assert.Equal(t, []int32{1234, 1235}, exported())
+	require.NoError(t, svc.DeleteAllIntents(ctx))
+	assert.Empty(t, exported())
+}
@@ -2,25 +2,70 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/decisionmaker/domain"
 	"github.com/Gthulhu/api/pkg/logger"
 	"github.com/Gthulhu/api/pkg/util"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-// VerifyAndGenerateToken verifies the provided public key and generates a JWT token if valid
-func (svc *Service) VerifyAndGenerateToken(ctx context.Context, clientID string, publicKey string) (string, int64, error) {
-	err := svc.VerifyPublicKey(publicKey)
+// VerifyAndGenerateToken authenticates a client and generates a JWT token limited to its scopes.
+// Registered clients present a client assertion, the public key of the decision maker is only
+// accepted while no client is registered.
+func (svc *Service) VerifyAndGenerateToken(ctx context.Context, clientID, publicKey, clientAssertion string) (string, int64, []domain.Scope, error) {
+	scopes, err := svc.authenticateClient(clientID, publicKey, clientAssertion)
 	if err != nil {
-		return "", 0, fmt.Errorf("public key verification failed: %v", err)
+		return "", 0, nil, fmt.Errorf("client authentication failed: %v", err)
 	}
-	token, claims, err := svc.generateJWT(ctx, clientID)
+	token, claims, err := svc.generateJWT(ctx, clientID, scopes)
 	if err != nil {
-		return "", 0, fmt.Errorf("JWT generation failed: %v", err)
+		return "", 0, nil, fmt.Errorf("JWT generation failed: %v", err)
 	}
-	return token, claims.ExpiresAt.Unix(), nil
+	return token, claims.ExpiresAt.Unix(), scopes, nil
+}
+
+func (svc *Service) authenticateClient(clientID, publicKey, clientAssertion string) ([]domain.Scope, error) {
+	if clientAssertion != "" {
+		return svc.verifyClientAssertion(clientID, clientAssertion)
+	}
+	if len(svc.clients) > 0 {
+		return nil, errors.New("registered clients must authenticate with a client assertion")
+	}
+	if err := svc.VerifyPublicKey(publicKey); err != nil {
+		return nil, err
+	}
+	return domain.AllScopes, nil
+}
+
+// verifyClientAssertion checks that the assertion of clientID is signed by its registered key
+func (svc *Service) verifyClientAssertion(clientID, clientAssertion string) ([]domain.Scope, error) {
+	client, ok := svc.clients[clientID]
+	if !ok {
+		return nil, fmt.Errorf("client %q is not registered", clientID)
+	}
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(clientAssertion, claims, func(token *jwt.Token) (any, error) {
+		return client.PublicKey, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithAudience(domain.ClientAssertionAudience),
+		jwt.WithIssuer(clientID),
+		jwt.WithSubject(clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client assertion: %v", err)
+	}
+	if time.Until(claims.ExpiresAt.Time) > domain.MaxClientAssertionLifetime {
+		return nil, fmt.Errorf("client assertion expires later than %s", domain.MaxClientAssertionLifetime)
+	}
+	return client.Scopes, nil
 }
 
 // verifyPublicKey verifies if the provided public key matches our private key
@@ -38,15 +83,20 @@ func (svc *Service) VerifyPublicKey(publicKeyPEM string) error {
 }
 
 // generateJWT generates a JWT token for authenticated client
-func (svc *Service) generateJWT(ctx context.Context, clientID string) (string, Claims, error) {
+func (svc *Service) generateJWT(ctx context.Context, clientID string, scopes []domain.Scope) (string, Claims, error) {
 	expireHr := svc.tokenConfig.TokenDurationHr
 	if expireHr <= 0 {
 		logger.Logger(ctx).Warn().Msgf("invalid token duration hr %d, defaulting to 24 hours", expireHr)
 		expireHr = 24 // default to 24 hours
 	}
 
+	scopeNames := make([]string, len(scopes))
+	for i, scope := range scopes {
+		scopeNames[i] = string(scope)
+	}
 	claims := Claims{
 		ClientID: clientID,
+		Scope:    strings.Join(scopeNames, " "),
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(expireHr) * time.Hour)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -67,5 +117,34 @@ func (svc *Service) generateJWT(ctx context.Context, clientID string) (string, C
 // Claims represents JWT token claims
 type Claims struct {
 	ClientID string `json:"client_id"`
+	// Scope lists the granted scopes separated by spaces
+	Scope string `json:"scope,omitempty"`
 	jwt.RegisteredClaims
 }
+
+// newClientRegistry parses the registered clients by client ID
+func newClientRegistry(clientConfigs []config.TokenClientConfig) (map[string]*domain.Client, error) {
+	clients := make(map[string]*domain.Client, len(clientConfigs))
+	for _, clientCfg := range clientConfigs {
+		if clientCfg.ClientID == "" {
+			return nil, errors.New("client_id is required")
+		}
+		if _, ok := clients[clientCfg.ClientID]; ok {
+			return nil, fmt.Errorf("duplicate client %q", clientCfg.ClientID)
+		}
+		publicKey, err := util.PEMToRSAPublicKey(clientCfg.PublicKeyPem)
+		if err != nil {
+			return nil, fmt.Errorf("parse public key of client %q: %v", clientCfg.ClientID, err)
+		}
+		client := &domain.Client{ID: clientCfg.ClientID, PublicKey: publicKey}
+		for _, name := range clientCfg.Scopes {
+			scope, err := domain.ParseScope(name)
+			if err != nil {
+				return nil, fmt.Errorf("client %q: %v", clientCfg.ClientID, err)
+			}
+			client.Scopes = append(client.Scopes, scope)
+		}
+		clients[client.ID] = client
+	}
+	return clients, nil
+}
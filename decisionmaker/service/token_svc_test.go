@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/decisionmaker/domain"
+	"github.com/Gthulhu/api/pkg/util"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key
+}
+
+func testPublicKeyPEM(t *testing.T, key *rsa.PrivateKey) string {
+	t.Helper()
+	pemBytes, err := util.RSAPublicKeyToPEM(&key.PublicKey)
+	require.NoError(t, err)
+	return string(pemBytes)
+}
+
+func tokenScopes(t *testing.T, svc *Service, token string) []string {
+	t.Helper()
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) {
+		return &svc.jwtPrivateKey.PublicKey, nil
+	})
+	require.NoError(t, err)
+	return strings.Fields(claims.Scope)
+}
+
+func TestVerifyAndGenerateTokenClientAssertion(t *testing.T) {
+	ctx := context.Background()
+	schedulerKey := testRSAKey(t)
+	clients, err := newClientRegistry([]config.TokenClientConfig{{
+		ClientID:     "scheduler",
+		PublicKeyPem: testPublicKeyPEM(t, schedulerKey),
+		Scopes:       []string{"intents:read", "metrics:write"},
+	}})
+	require.NoError(t, err)
+	svc := &Service{jwtPrivateKey: testRSAKey(t), clients: clients}
+
+	assertion, err := domain.NewClientAssertion("scheduler", schedulerKey, time.Now())
+	require.NoError(t, err)
+	token, _, scopes, err := svc.VerifyAndGenerateToken(ctx, "scheduler", "", assertion)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Scope{domain.ScopeIntentsRead, domain.ScopeMetricsWrite}, scopes)
+	assert.Equal(t, []string{"intents:read", "metrics:write"}, tokenScopes(t, svc, token))
+
+	// the assertion must be signed by the registered key of the client it names
+	forged, err := domain.NewClientAssertion("scheduler", testRSAKey(t), time.Now())
+	require.NoError(t, err)
+	_, _, _, err = svc.VerifyAndGenerateToken(ctx, "scheduler", "", forged)
+	require.Error(t, err)
+	_, _, _, err = svc.VerifyAndGenerateToken(ctx, "unknown", "", assertion)
+	require.Error(t, err)
+
+	expired, err := domain.NewClientAssertion("scheduler", schedulerKey, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	_, _, _, err = svc.VerifyAndGenerateToken(ctx, "scheduler", "", expired)
+	require.Error(t, err)
+
+	longLived, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.RegisteredClaims{
+		Issuer:    "scheduler",
+		Subject:   "scheduler",
+		Audience:  jwt.ClaimStrings{domain.ClientAssertionAudience},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).SignedString(schedulerKey)
+	require.NoError(t, err)
+	_, _, _, err = svc.VerifyAndGenerateToken(ctx, "scheduler", "", longLived)
+	require.Error(t, err)
+
+	// the shared public key exchange is refused once clients are registered
+	_, _, _, err = svc.VerifyAndGenerateToken(ctx, "scheduler", testPublicKeyPEM(t, svc.jwtPrivateKey), "")
+	require.Error(t, err)
+}
+
+func TestVerifyAndGenerateTokenLegacyPublicKey(t *testing.T) {
+	svc := &Service{jwtPrivateKey: testRSAKey(t)}
+
+	token, _, scopes, err := svc.VerifyAndGenerateToken(context.Background(), "manager", testPublicKeyPEM(t, svc.jwtPrivateKey), "")
+	require.NoError(t, err)
+	assert.Equal(t, domain.AllScopes, scopes)
+	assert.Len(t, tokenScopes(t, svc, token), len(domain.AllScopes))
+
+	_, _, _, err = svc.VerifyAndGenerateToken(context.Background(), "manager", testPublicKeyPEM(t, testRSAKey(t)), "")
+	require.Error(t, err)
+}
+
+func TestNewClientRegistryValidation(t *testing.T) {
+	publicKey := testPublicKeyPEM(t, testRSAKey(t))
+	tests := []struct {
+		name    string
+		clients []config.TokenClientConfig
+	}{
+		{name: "missing client id", clients: []config.TokenClientConfig{{PublicKeyPem: publicKey}}},
+		{name: "duplicate client", clients: []config.TokenClientConfig{
+			{ClientID: "scheduler", PublicKeyPem: publicKey},
+			{ClientID: "scheduler", PublicKeyPem: publicKey},
+		}},
+		{name: "invalid public key", clients: []config.TokenClientConfig{{ClientID: "scheduler", PublicKeyPem: "invalid"}}},
+		{name: "unknown scope", clients: []config.TokenClientConfig{{ClientID: "scheduler", PublicKeyPem: publicKey, Scopes: []string{"intents:admin"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newClientRegistry(tt.clients)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/pkg/logger"
+	"go.uber.org/fx"
+)
+
+// StartAlertEvaluator periodically evaluates the alert rules when alerting is enabled
+func StartAlertEvaluator(lc fx.Lifecycle, cfg config.AlertingConfig, svc domain.Service) error {
+	if !cfg.Enable {
+		return nil
+	}
+	interval := cfg.EvaluateInterval()
+	stopCh := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go func() {
+				bgCtx := context.Background()
+				logger.Logger(bgCtx).Info().Msgf("alert evaluator starting, interval %s", interval)
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ticker.C:
+						if err := svc.EvaluateAlertRules(bgCtx); err != nil {
+							logger.Logger(bgCtx).Warn().Err(err).Msg("alert rule evaluation failed")
+						}
+					case <-stopCh:
+						logger.Logger(bgCtx).Info().Msg("alert evaluator stopped")
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stopCh)
+			return nil
+		},
+	})
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/pkg/auditstream"
+	"github.com/Gthulhu/api/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/fx"
+)
+
+// NewAuditStreamer returns the streamer delivering audit logs to the configured sinks, nil when
+// audit streaming is disabled
+func NewAuditStreamer(lc fx.Lifecycle, cfg config.AuditStreamConfig) (*auditstream.Streamer, error) {
+	if !cfg.Enable {
+		return nil, nil
+	}
+	var sinks []auditstream.Sink
+	closeSinks := func() {
+		for _, sink := range sinks {
+			_ = sink.Close()
+		}
+	}
+	if cfg.File.Enable {
+		sink, err := auditstream.NewFileSink(cfg.File.Path, int64(cfg.File.MaxSizeMB)<<20, cfg.File.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	if cfg.Syslog.Enable {
+		sink, err := auditstream.NewSyslogSink(cfg.Syslog.Network, cfg.Syslog.Address, cfg.Syslog.Facility, cfg.Syslog.AppName, time.Duration(cfg.Syslog.TimeoutSec)*time.Second)
+		if err != nil {
+			closeSinks()
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	if cfg.HTTP.Enable {
+		sink, err := auditstream.NewHTTPSink(cfg.HTTP.URL, cfg.HTTP.Headers, time.Duration(cfg.HTTP.TimeoutSec)*time.Second)
+		if err != nil {
+			closeSinks()
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	if len(sinks) == 0 {
+		logger.Logger(context.Background()).Warn().Msg("audit streaming is enabled without any sink")
+		return nil, nil
+	}
+
+	streamer, err := auditstream.NewStreamer(auditstream.Options{
+		SpoolDir:      cfg.SpoolDir,
+		SegmentBytes:  int64(cfg.SpoolSegmentMB) << 20,
+		BatchSize:     cfg.BatchSize,
+		FlushInterval: time.Duration(cfg.FlushIntervalMs) * time.Millisecond,
+	}, sinks...)
+	if err != nil {
+		closeSinks()
+		return nil, err
+	}
+	if err := prometheus.Register(streamer); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if !errors.As(err, &alreadyRegistered) {
+			_ = streamer.Close(context.Background())
+			return nil, err
+		}
+	}
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			prometheus.Unregister(streamer)
+			return streamer.Close(ctx)
+		},
+	})
+	return streamer, nil
+}
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/manager/service"
+	"github.com/Gthulhu/api/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/fx"
+)
+
+const defaultAuthCacheSyncInterval = 5 * time.Second
+
+// NewAuthCache returns the cache of the users and roles requests are authorized with, nil when
+// the cache is disabled
+func NewAuthCache(lc fx.Lifecycle, cfg config.AuthCacheConfig) (*service.AuthCache, error) {
+	cache := service.NewAuthCache(cfg)
+	if cache == nil {
+		return nil, nil
+	}
+	if err := prometheus.Register(cache); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if !errors.As(err, &alreadyRegistered) {
+			return nil, err
+		}
+	}
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			prometheus.Unregister(cache)
+			return nil
+		},
+	})
+	return cache, nil
+}
+
+// StartAuthCacheSync periodically flushes the authorization cache when another replica changed
+// users or roles
+func StartAuthCacheSync(lc fx.Lifecycle, cfg config.AuthCacheConfig, svc domain.Service) error {
+	if !cfg.Enable {
+		return nil
+	}
+	interval := time.Duration(cfg.SyncIntervalSec) * time.Second
+	if interval <= 0 {
+		interval = defaultAuthCacheSyncInterval
+	}
+	stopCh := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go func() {
+				bgCtx := context.Background()
+				logger.Logger(bgCtx).Info().Msgf("authorization cache sync starting, interval %s", interval)
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ticker.C:
+						if err := svc.SyncAuthCache(bgCtx); err != nil {
+							logger.Logger(bgCtx).Warn().Err(err).Msg("authorization cache sync failed")
+						}
+					case <-stopCh:
+						logger.Logger(bgCtx).Info().Msg("authorization cache sync stopped")
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stopCh)
+			return nil
+		},
+	})
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package app
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/manager/rest"
+	"github.com/Gthulhu/api/pkg/certreload"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/labstack/gommon/bytes"
+)
+
+const (
+	defaultReadTimeout       = 30 * time.Second
+	defaultReadHeaderTimeout = 10 * time.Second
+	defaultWriteTimeout      = 60 * time.Second
+	defaultIdleTimeout       = 120 * time.Second
+	defaultBodyLimit         = "4M"
+	hstsMaxAgeSec            = 365 * 24 * 3600
+)
+
+// hardenServer applies the timeouts, body limit, security headers and CORS policy of cfg to engine
+func hardenServer(engine *echo.Echo, cfg config.ServerConfig) error {
+	engine.Server.ReadTimeout = durationOr(cfg.ReadTimeoutSec, defaultReadTimeout)
+	engine.Server.ReadHeaderTimeout = durationOr(cfg.ReadHeaderTimeoutSec, defaultReadHeaderTimeout)
+	engine.Server.WriteTimeout = durationOr(cfg.WriteTimeoutSec, defaultWriteTimeout)
+	engine.Server.IdleTimeout = durationOr(cfg.IdleTimeoutSec, defaultIdleTimeout)
+
+	bodyLimit := cfg.BodyLimit
+	if bodyLimit == "" {
+		bodyLimit = defaultBodyLimit
+	}
+	if _, err := bytes.Parse(bodyLimit); err != nil {
+		return fmt.Errorf("invalid server body_limit %q: %w", bodyLimit, err)
+	}
+	engine.Use(middleware.BodyLimit(bodyLimit))
+
+	ipExtractor, err := newIPExtractor(cfg.TrustedProxies)
+	if err != nil {
+		return err
+	}
+	engine.IPExtractor = ipExtractor
+
+	secure := middleware.SecureConfig{
+		ContentTypeNosniff:    "nosniff",
+		XFrameOptions:         "SAMEORIGIN",
+		ReferrerPolicy:        "strict-origin-when-cross-origin",
+		ContentSecurityPolicy: cfg.ContentSecurityPolicy,
+	}
+	if cfg.TLS.Enable {
+		// only sent on TLS connections, a plain HTTP listener never pins browsers to HTTPS
+		secure.HSTSMaxAge = hstsMaxAgeSec
+	}
+	engine.Use(middleware.SecureWithConfig(secure))
+
+	if len(cfg.CORS.AllowOrigins) > 0 {
+		for _, origin := range cfg.CORS.AllowOrigins {
+			if origin == "*" && cfg.CORS.AllowCredentials {
+				return errors.New("server CORS can't allow credentials from every origin")
+			}
+		}
+		engine.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+			AllowOrigins:     cfg.CORS.AllowOrigins,
+			AllowMethods:     []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete},
+			AllowHeaders:     []string{echo.HeaderAuthorization, echo.HeaderContentType, rest.APIKeyHeader},
+			AllowCredentials: cfg.CORS.AllowCredentials,
+			MaxAge:           cfg.CORS.MaxAgeSec,
+		}))
+	}
+	return nil
+}
+
+// newIPExtractor returns how the client IP of login throttling and audit logs is determined. The
+// forwarding headers can be set by anyone, so they're only read from the listed proxies.
+func newIPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, proxy := range trustedProxies {
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid server trusted proxy %q: %w", proxy, err)
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
+
+func durationOr(sec int, fallback time.Duration) time.Duration {
+	if sec <= 0 {
+		return fallback
+	}
+	return time.Duration(sec) * time.Second
+}
+
+// parseClientAuth returns how the API verifies client certificates
+func parseClientAuth(clientAuth string) (tls.ClientAuthType, error) {
+	switch clientAuth {
+	case "", "none":
+		return tls.NoClientCert, nil
+	case "verify_if_given":
+		return tls.VerifyClientCertIfGiven, nil
+	case "require":
+		return tls.RequireAndVerifyClientCert, nil
+	}
+	return tls.NoClientCert, fmt.Errorf("invalid server TLS client_auth %q", clientAuth)
+}
+
+// newServerTLSConfig loads the certificate of the API and returns the TLS configuration serving it,
+// the certificate is exported as tls_certificate_expiry_timestamp_seconds{name="manager-api"}
+func newServerTLSConfig(cfg config.ServerTLSConfig) (*certreload.Reloader, *tls.Config, error) {
+	clientAuth, err := parseClientAuth(cfg.ClientAuth)
+	if err != nil {
+		return nil, nil, err
+	}
+	certs, err := certreload.New("manager-api", cfg.CertSource())
+	if err != nil {
+		return nil, nil, fmt.Errorf("load server TLS certificate: %w", err)
+	}
+	return certs, certs.ServerTLSConfig(clientAuth), nil
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIPExtractor(t *testing.T) {
+	direct, err := newIPExtractor(nil)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", nil)
+	req.RemoteAddr = "203.0.113.7:51234"
+	req.Header.Set(echo.HeaderXForwardedFor, "198.51.100.1")
+	req.Header.Set(echo.HeaderXRealIP, "198.51.100.2")
+	assert.Equal(t, "203.0.113.7", direct(req), "forwarding headers are ignored without trusted proxies")
+
+	proxied, err := newIPExtractor([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+	assert.Equal(t, "203.0.113.7", proxied(req), "headers of an untrusted peer are ignored")
+	req.RemoteAddr = "10.1.2.3:51234"
+	req.Header.Set(echo.HeaderXForwardedFor, "198.51.100.1, 203.0.113.9")
+	assert.Equal(t, "203.0.113.9", proxied(req), "the first address the trusted proxy didn't add is the client")
+	req.Header.Set(echo.HeaderXForwardedFor, "198.51.100.1, 10.9.9.9")
+	assert.Equal(t, "198.51.100.1", proxied(req))
+
+	_, err = newIPExtractor([]string{"10.0.0.0"})
+	require.Error(t, err)
+}
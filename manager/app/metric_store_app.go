@@ -0,0 +1,55 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/pkg/logger"
+	"go.uber.org/fx"
+)
+
+// StartMetricSampleCollector periodically stores the metrics of every decision maker in the
+// metric time series when the metric store is enabled
+func StartMetricSampleCollector(lc fx.Lifecycle, cfg config.MetricStoreConfig, svc domain.Service) error {
+	if !cfg.Enable {
+		return nil
+	}
+	interval := cfg.CollectInterval()
+	stopCh := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if cfg.RetentionSec > 0 {
+				if err := svc.SetMetricSampleRetention(ctx, time.Duration(cfg.RetentionSec)*time.Second); err != nil {
+					logger.Logger(ctx).Warn().Err(err).Msg("failed to apply metric sample retention")
+				}
+			}
+			go func() {
+				bgCtx := context.Background()
+				logger.Logger(bgCtx).Info().Msgf("metric sample collector starting, interval %s", interval)
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ticker.C:
+						if err := svc.CollectMetricSamples(bgCtx); err != nil {
+							logger.Logger(bgCtx).Warn().Err(err).Msg("metric sample collection failed")
+						}
+					case <-stopCh:
+						logger.Logger(bgCtx).Info().Msg("metric sample collector stopped")
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stopCh)
+			return nil
+		},
+	})
+
+	return nil
+}
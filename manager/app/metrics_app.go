@@ -0,0 +1,62 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/fx"
+)
+
+const defaultMetricsHost = ":9090"
+
+// newMetricsHandler serves the collectors of the Prometheus default registry, among them the
+// audit stream, authorization cache and TLS certificate metrics
+func newMetricsHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	return mux
+}
+
+// StartMetricsServer serves /metrics on the listener of cfg, apart from the API
+func StartMetricsServer(lc fx.Lifecycle, cfg config.MetricsConfig) error {
+	if !cfg.Enable {
+		return nil
+	}
+	host := cfg.Host
+	if host == "" {
+		host = defaultMetricsHost
+	}
+	server := &http.Server{
+		Handler:           newMetricsHandler(),
+		ReadHeaderTimeout: defaultReadHeaderTimeout,
+		WriteTimeout:      defaultWriteTimeout,
+		IdleTimeout:       defaultIdleTimeout,
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			ln, err := net.Listen("tcp", host)
+			if err != nil {
+				return fmt.Errorf("create metrics listener: %w", err)
+			}
+			go func() {
+				logger.Logger(ctx).Info().Msgf("starting metrics server on port %s", host)
+				if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					logger.Logger(ctx).Error().Err(err).Msgf("metrics server on port %s failed", host)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			logger.Logger(ctx).Info().Msg("shutting down metrics server")
+			return server.Shutdown(ctx)
+		},
+	})
+	return nil
+}
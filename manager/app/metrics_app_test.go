@@ -0,0 +1,50 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestMetricsHandlerServesAuditStreamMetrics(t *testing.T) {
+	lc := fxtest.NewLifecycle(t)
+	dir := t.TempDir()
+	streamer, err := NewAuditStreamer(lc, config.AuditStreamConfig{
+		Enable:   true,
+		SpoolDir: filepath.Join(dir, "spool"),
+		File:     config.AuditStreamFileConfig{Enable: true, Path: filepath.Join(dir, "audit.log")},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, streamer)
+	lc.RequireStart()
+	defer lc.RequireStop()
+
+	rec := httptest.NewRecorder()
+	newMetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	for _, name := range []string{"audit_stream_buffered_records", "audit_stream_delivered_records_total", "audit_stream_healthy"} {
+		assert.Contains(t, rec.Body.String(), name)
+	}
+}
+
+func TestMetricsHandlerServesAuthCacheMetrics(t *testing.T) {
+	lc := fxtest.NewLifecycle(t)
+	cache, err := NewAuthCache(lc, config.AuthCacheConfig{Enable: true})
+	require.NoError(t, err)
+	require.NotNil(t, cache)
+	lc.RequireStart()
+	defer lc.RequireStop()
+
+	rec := httptest.NewRecorder()
+	newMetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	for _, series := range []string{`auth_cache_hits_total{cache="token"}`, `auth_cache_misses_total{cache="user"}`} {
+		assert.Contains(t, rec.Body.String(), series)
+	}
+}
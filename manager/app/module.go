@@ -34,12 +34,48 @@ func ConfigModule(cfg config.ManageConfig) (fx.Option, error) {
 		fx.Provide(func(managerCfg config.ManageConfig) config.AccountConfig {
 			return managerCfg.Account
 		}),
+		fx.Provide(func(managerCfg config.ManageConfig) config.AuthConfig {
+			return managerCfg.Auth
+		}),
+		fx.Provide(func(managerCfg config.ManageConfig) config.OIDCConfig {
+			return managerCfg.OIDC
+		}),
+		fx.Provide(func(managerCfg config.ManageConfig) config.PasswordPolicyConfig {
+			return managerCfg.PasswordPolicy
+		}),
+		fx.Provide(func(managerCfg config.ManageConfig) config.LoginProtectionConfig {
+			return managerCfg.LoginProtection
+		}),
+		fx.Provide(func(managerCfg config.ManageConfig) config.TwoFactorConfig {
+			return managerCfg.TwoFactor
+		}),
+		fx.Provide(func(managerCfg config.ManageConfig) config.AuthCacheConfig {
+			return managerCfg.AuthCache
+		}),
 		fx.Provide(func(managerCfg config.ManageConfig) config.K8SConfig {
 			return managerCfg.K8S
 		}),
 		fx.Provide(func(managerCfg config.ManageConfig) config.MTLSConfig {
 			return managerCfg.MTLS
 		}),
+		fx.Provide(func(managerCfg config.ManageConfig) config.DMClientConfig {
+			return managerCfg.DMClient
+		}),
+		fx.Provide(func(managerCfg config.ManageConfig) config.MetricStoreConfig {
+			return managerCfg.MetricStore
+		}),
+		fx.Provide(func(managerCfg config.ManageConfig) config.AlertingConfig {
+			return managerCfg.Alerting
+		}),
+		fx.Provide(func(managerCfg config.ManageConfig) config.EventWebhookConfig {
+			return managerCfg.EventWebhook
+		}),
+		fx.Provide(func(managerCfg config.ManageConfig) config.MetricsConfig {
+			return managerCfg.Metrics
+		}),
+		fx.Provide(func(managerCfg config.ManageConfig) config.AuditStreamConfig {
+			return managerCfg.AuditStream
+		}),
 	), nil
 }
 
@@ -58,6 +94,7 @@ func AdapterModule() (fx.Option, error) {
 				InCluster:      k8sConfig.IsInCluster,
 			})
 		}),
+		fx.Provide(NewMTLSCertificates),
 		fx.Provide(client.NewDecisionMakerClient),
 	), nil
 }
@@ -80,6 +117,8 @@ func ServiceModule(adapterModule, repoModule fx.Option) (fx.Option, error) {
 	return fx.Options(
 		adapterModule,
 		repoModule,
+		fx.Provide(NewAuditStreamer),
+		fx.Provide(NewAuthCache),
 		fx.Provide(service.NewService),
 	), nil
 }
@@ -2,12 +2,16 @@ package app
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
 	"time"
 
 	"github.com/Gthulhu/api/config"
 	"github.com/Gthulhu/api/manager/domain"
 	"github.com/Gthulhu/api/manager/migration"
 	"github.com/Gthulhu/api/manager/rest"
+	"github.com/Gthulhu/api/pkg/certreload"
 	"github.com/Gthulhu/api/pkg/logger"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/fx"
@@ -48,24 +52,56 @@ func NewRestApp(configName string, configDirPath string) (*fx.App, error) {
 		handlerModule,
 		fx.Invoke(migration.RunMongoMigration),
 		fx.Invoke(StartRestApp),
+		fx.Invoke(StartMetricsServer),
 		fx.Invoke(StartIntentReconciler),
+		fx.Invoke(StartMetricSampleCollector),
+		fx.Invoke(StartAlertEvaluator),
+		fx.Invoke(StartAuthCacheSync),
+		fx.Invoke(StartSigningKeyReload),
 	)
 	return app, nil
 }
 
 func StartRestApp(lc fx.Lifecycle, cfg config.ServerConfig, handler *rest.Handler) error {
 	engine := echo.New()
+	if err := hardenServer(engine, cfg); err != nil {
+		return err
+	}
 	handler.SetupRoutes(engine)
 	rest.RegisterFrontend(engine)
 
+	var tlsCfg *tls.Config
+	if cfg.TLS.Enable {
+		certs, serverTLSCfg, err := newServerTLSConfig(cfg.TLS)
+		if err != nil {
+			return err
+		}
+		interval := time.Duration(cfg.TLS.ReloadIntervalSec) * time.Second
+		if err := certreload.RegisterAndRun(lc, certs, "server TLS", interval); err != nil {
+			return err
+		}
+		tlsCfg = serverTLSCfg
+	}
+
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
 			serverHost := cfg.Host
 			if serverHost == "" {
 				serverHost = ":8080"
 			}
+			if tlsCfg != nil {
+				ln, err := net.Listen("tcp", serverHost)
+				if err != nil {
+					return fmt.Errorf("create listener: %w", err)
+				}
+				engine.Listener = tls.NewListener(ln, tlsCfg)
+			}
 			go func() {
-				logger.Logger(ctx).Info().Msgf("starting rest server on port %s", serverHost)
+				if tlsCfg != nil {
+					logger.Logger(ctx).Info().Msgf("starting rest server with TLS on port %s", serverHost)
+				} else {
+					logger.Logger(ctx).Info().Msgf("starting rest server on port %s", serverHost)
+				}
 				if err := engine.Start(serverHost); err != nil {
 					logger.Logger(ctx).Fatal().Err(err).Msgf("start rest server fail on port %s", serverHost)
 				}
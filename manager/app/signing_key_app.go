@@ -0,0 +1,55 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/pkg/logger"
+	"go.uber.org/fx"
+)
+
+const defaultJWTKeyReloadInterval = 30 * time.Second
+
+// StartSigningKeyReload periodically re-reads the JWT key directory so that signing keys rotate
+// without a restart
+func StartSigningKeyReload(lc fx.Lifecycle, cfg config.KeyConfig, svc domain.Service) error {
+	if cfg.JWTKeyDir == "" {
+		return nil
+	}
+	interval := time.Duration(cfg.JWTKeyReloadIntervalSec) * time.Second
+	if interval <= 0 {
+		interval = defaultJWTKeyReloadInterval
+	}
+	stopCh := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go func() {
+				bgCtx := context.Background()
+				logger.Logger(bgCtx).Info().Msgf("JWT signing key reload starting, directory %s, interval %s", cfg.JWTKeyDir, interval)
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ticker.C:
+						if err := svc.ReloadSigningKeys(bgCtx); err != nil {
+							logger.Logger(bgCtx).Warn().Err(err).Msg("JWT signing key reload failed, keeping the current keys")
+						}
+					case <-stopCh:
+						logger.Logger(bgCtx).Info().Msg("JWT signing key reload stopped")
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stopCh)
+			return nil
+		},
+	})
+
+	return nil
+}
@@ -3,41 +3,48 @@ package client
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
-	"crypto/x509"
+	"crypto/rsa"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"sync"
 	"time"
 
 	cache "github.com/Code-Hex/go-generics-cache"
 	"github.com/Gthulhu/api/config"
+	dmdomain "github.com/Gthulhu/api/decisionmaker/domain"
 	dmrest "github.com/Gthulhu/api/decisionmaker/rest"
 	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/pkg/certreload"
 	"github.com/Gthulhu/api/pkg/logger"
+	"github.com/Gthulhu/api/pkg/util"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
-func NewDecisionMakerClient(keyConfig config.KeyConfig, mtlsCfg config.MTLSConfig) (domain.DecisionMakerAdapter, error) {
-	httpClient := http.DefaultClient
+// NewDecisionMakerClient returns the client of the decision makers, certs presents the mTLS client
+// certificate and is loaded from mtlsCfg when nil
+func NewDecisionMakerClient(keyConfig config.KeyConfig, mtlsCfg config.MTLSConfig, clientCfg config.DMClientConfig, certs *certreload.Reloader) (domain.DecisionMakerAdapter, error) {
+	timeout := time.Duration(clientCfg.TimeoutSec) * time.Second
+	if timeout <= 0 {
+		timeout = defaultDMClientTimeout
+	}
+	httpClient := &http.Client{Timeout: timeout}
 
 	if mtlsCfg.Enable {
-		cert, err := tls.X509KeyPair([]byte(mtlsCfg.CertPem.Value()), []byte(mtlsCfg.KeyPem.Value()))
-		if err != nil {
-			return nil, fmt.Errorf("load mTLS client certificate: %w", err)
-		}
-
-		caPool := x509.NewCertPool()
-		if !caPool.AppendCertsFromPEM([]byte(mtlsCfg.CAPem.Value())) {
-			return nil, fmt.Errorf("parse mTLS CA certificate")
-		}
-
-		tlsCfg := &tls.Config{
-			Certificates: []tls.Certificate{cert},
-			RootCAs:      caPool,
-			MinVersion:   tls.VersionTLS12,
-			ServerName:   mtlsCfg.ServerName,
+		if certs == nil {
+			var err error
+			certs, err = certreload.New("manager", mtlsCfg.CertSource())
+			if errors.Is(err, certreload.ErrInvalidCA) {
+				return nil, fmt.Errorf("parse mTLS CA certificate: %w", err)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("load mTLS client certificate: %w", err)
+			}
 		}
+		tlsCfg := certs.ClientTLSConfig(mtlsCfg.ServerName)
 
 		defaultTransport, ok := http.DefaultTransport.(*http.Transport)
 		if !ok {
@@ -48,15 +55,48 @@ func NewDecisionMakerClient(keyConfig config.KeyConfig, mtlsCfg config.MTLSConfi
 
 		httpClient = &http.Client{
 			Transport: mtlsTransport,
+			Timeout:   timeout,
+		}
+	}
+
+	var clientKey *rsa.PrivateKey
+	if pemStr := keyConfig.DMClientPrivateKeyPem.Value(); pemStr != "" {
+		key, err := util.InitRSAPrivateKey(pemStr)
+		if err != nil {
+			return nil, fmt.Errorf("load decision maker client key: %w", err)
 		}
+		clientKey = key
+	}
+
+	retry := retryPolicy{
+		maxAttempts:    clientCfg.MaxAttempts,
+		initialBackoff: time.Duration(clientCfg.InitialBackoffMs) * time.Millisecond,
+	}
+	if retry.maxAttempts <= 0 {
+		retry.maxAttempts = defaultDMClientMaxAttempts
+	}
+	if retry.initialBackoff <= 0 {
+		retry.initialBackoff = defaultDMClientInitialBackoff
+	}
+	breakerThreshold := clientCfg.BreakerFailureThreshold
+	if breakerThreshold <= 0 {
+		breakerThreshold = defaultBreakerFailureThreshold
+	}
+	breakerOpenFor := time.Duration(clientCfg.BreakerOpenSec) * time.Second
+	if breakerOpenFor <= 0 {
+		breakerOpenFor = defaultBreakerOpenDuration
 	}
 
 	return &DecisionMakerClient{
-		Client:         httpClient,
-		mtlsEnabled:    mtlsCfg.Enable,
-		tokenPublicKey: keyConfig.DMPublicKeyPem.Value(),
-		clientID:       keyConfig.ClientID,
-		tokenCache:     cache.New[string, string](),
+		Client:           httpClient,
+		mtlsEnabled:      mtlsCfg.Enable,
+		tokenPublicKey:   keyConfig.DMPublicKeyPem.Value(),
+		clientKey:        clientKey,
+		clientID:         keyConfig.ClientID,
+		tokenCache:       cache.New[string, string](),
+		retry:            retry,
+		breakerThreshold: breakerThreshold,
+		breakerOpenFor:   breakerOpenFor,
 	}, nil
 }
 
@@ -65,8 +105,17 @@ type DecisionMakerClient struct {
 
 	mtlsEnabled    bool
 	tokenPublicKey string
-	clientID       string
-	tokenCache     *cache.Cache[string, string]
+	// clientKey signs the client assertions of a client registered with the decision makers, the
+	// manager needs the intents:read, intents:write, metrics:read and pods:read scopes
+	clientKey  *rsa.PrivateKey
+	clientID   string
+	tokenCache *cache.Cache[string, string]
+
+	retry            retryPolicy
+	breakerThreshold int
+	breakerOpenFor   time.Duration
+	nodesMu          sync.Mutex
+	nodes            map[string]*nodeState
 }
 
 // scheme returns "https" when mTLS is enabled, "http" otherwise.
@@ -77,12 +126,11 @@ func (dm *DecisionMakerClient) scheme() string {
 	return "http"
 }
 
-func (dm *DecisionMakerClient) SendSchedulingIntent(ctx context.Context, decisionMaker *domain.DecisionMakerPod, intents []*domain.ScheduleIntent) error {
-	token, err := dm.GetToken(ctx, decisionMaker)
-	if err != nil {
-		return err
-	}
+func (dm *DecisionMakerClient) endpoint(decisionMaker *domain.DecisionMakerPod, path string) string {
+	return dm.scheme() + "://" + decisionMaker.Host + ":" + strconv.Itoa(decisionMaker.Port) + path
+}
 
+func (dm *DecisionMakerClient) SendSchedulingIntent(ctx context.Context, decisionMaker *domain.DecisionMakerPod, intents []*domain.ScheduleIntent) error {
 	logger.Logger(ctx).Debug().Msgf("Sending %d scheduling intents to decision maker pod (host:%s nodeID:%s port:%d)", len(intents), decisionMaker.Host, decisionMaker.NodeID, decisionMaker.Port)
 
 	reqPayload := dmrest.HandleIntentsRequest{
@@ -98,6 +146,11 @@ func (dm *DecisionMakerClient) SendSchedulingIntent(ctx context.Context, decisio
 			Priority:      intent.Priority,
 			ExecutionTime: intent.ExecutionTime,
 			PodLabels:     intent.PodLabels,
+
+			CommandMatchTargets: commandMatchTargetsToStrings(intent.CommandMatchTargets),
+			ContainerNames:      intent.ContainerNames,
+			ThreadRegex:         intent.ThreadRegex,
+			StrategyID:          strategyIDToString(intent.StrategyID),
 		})
 	}
 
@@ -105,14 +158,8 @@ func (dm *DecisionMakerClient) SendSchedulingIntent(ctx context.Context, decisio
 	if err != nil {
 		return err
 	}
-	endpoint := dm.scheme() + "://" + decisionMaker.Host + ":" + strconv.Itoa(decisionMaker.Port) + "/api/v1/intents"
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+token)
-	resp, err := dm.Client.Do(req)
+	// the intents replace the whole set of the node, so sending them again is harmless
+	resp, err := dm.do(ctx, decisionMaker, dmRequest{method: http.MethodPost, path: "/api/v1/intents", body: jsonBody, idempotent: true})
 	if err != nil {
 		return err
 	}
@@ -124,18 +171,8 @@ func (dm *DecisionMakerClient) SendSchedulingIntent(ctx context.Context, decisio
 }
 
 func (dm *DecisionMakerClient) GetIntentMerkleRoot(ctx context.Context, decisionMaker *domain.DecisionMakerPod) (string, error) {
-	token, err := dm.GetToken(ctx, decisionMaker)
-	if err != nil {
-		return "", err
-	}
-
-	endpoint := dm.scheme() + "://" + decisionMaker.Host + ":" + strconv.Itoa(decisionMaker.Port) + "/api/v1/intents/merkle"
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
-	if err != nil {
-		return "", err
-	}
-	req.Header.Set("Authorization", "Bearer "+token)
-	resp, err := dm.Client.Do(req)
+	path := "/api/v1/intents/merkle"
+	resp, err := dm.do(ctx, decisionMaker, dmRequest{method: http.MethodGet, path: path, idempotent: true})
 	if err != nil {
 		return "", err
 	}
@@ -155,21 +192,29 @@ func (dm *DecisionMakerClient) GetIntentMerkleRoot(ctx context.Context, decision
 	return merkleResp.Data.RootHash, nil
 }
 
+// GetToken returns the cached token of the node of decisionMaker or requests a new one
 func (dm *DecisionMakerClient) GetToken(ctx context.Context, decisionMaker *domain.DecisionMakerPod) (string, error) {
 	if token, ok := dm.tokenCache.Get(decisionMaker.NodeID); ok {
 		return token, nil
 	}
 
 	req := dmrest.TokenRequest{
-		PublicKey: dm.tokenPublicKey,
-		ClientID:  dm.clientID,
+		ClientID: dm.clientID,
+	}
+	if dm.clientKey != nil {
+		assertion, err := dmdomain.NewClientAssertion(dm.clientID, dm.clientKey, time.Now())
+		if err != nil {
+			return "", fmt.Errorf("sign client assertion: %w", err)
+		}
+		req.ClientAssertion = assertion
+	} else {
+		req.PublicKey = dm.tokenPublicKey
 	}
 	jsonBody, err := json.Marshal(req)
 	if err != nil {
 		return "", err
 	}
-	endpoint := dm.scheme() + "://" + decisionMaker.Host + ":" + strconv.Itoa(decisionMaker.Port) + "/api/v1/auth/token"
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(jsonBody))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, dm.endpoint(decisionMaker, "/api/v1/auth/token"), bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return "", err
 	}
@@ -180,7 +225,7 @@ func (dm *DecisionMakerClient) GetToken(ctx context.Context, decisionMaker *doma
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("decision maker %s returned non-OK status: %s", decisionMaker, resp.Status)
+		return "", &statusError{decisionMaker: decisionMaker, statusCode: resp.StatusCode, status: resp.Status}
 	}
 	var tokenResp dmrest.SuccessResponse[dmrest.TokenResponse]
 	decoder := json.NewDecoder(resp.Body)
@@ -188,20 +233,19 @@ func (dm *DecisionMakerClient) GetToken(ctx context.Context, decisionMaker *doma
 	if err != nil {
 		return "", err
 	}
+	if tokenResp.Data == nil || tokenResp.Data.Token == "" {
+		return "", fmt.Errorf("decision maker %s returned empty token", decisionMaker)
+	}
 
-	ttl := time.Now().Unix() - tokenResp.Data.ExpiredAt - 60
-
-	dm.tokenCache.Set(decisionMaker.NodeID, tokenResp.Data.Token, cache.WithExpiration(time.Duration(ttl)*time.Second))
+	// refresh a minute before the token expires, tokens expiring sooner aren't cached
+	ttl := time.Until(time.Unix(tokenResp.Data.ExpiredAt, 0)) - time.Minute
+	if ttl > 0 {
+		dm.tokenCache.Set(decisionMaker.NodeID, tokenResp.Data.Token, cache.WithExpiration(ttl))
+	}
 	return tokenResp.Data.Token, nil
-
 }
 
 func (dm *DecisionMakerClient) DeleteSchedulingIntents(ctx context.Context, decisionMaker *domain.DecisionMakerPod, req *domain.DeleteIntentsRequest) error {
-	token, err := dm.GetToken(ctx, decisionMaker)
-	if err != nil {
-		return err
-	}
-
 	logger.Logger(ctx).Debug().Msgf("Deleting scheduling intents from decision maker pod (host:%s nodeID:%s port:%d)", decisionMaker.Host, decisionMaker.NodeID, decisionMaker.Port)
 
 	// If All is true, delete all intents; otherwise delete by PodIDs one by one
@@ -213,14 +257,7 @@ func (dm *DecisionMakerClient) DeleteSchedulingIntents(ctx context.Context, deci
 		if err != nil {
 			return err
 		}
-		endpoint := dm.scheme() + "://" + decisionMaker.Host + ":" + strconv.Itoa(decisionMaker.Port) + "/api/v1/intents"
-		httpReq, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint, bytes.NewBuffer(jsonBody))
-		if err != nil {
-			return err
-		}
-		httpReq.Header.Set("Content-Type", "application/json")
-		httpReq.Header.Set("Authorization", "Bearer "+token)
-		resp, err := dm.Client.Do(httpReq)
+		resp, err := dm.do(ctx, decisionMaker, dmRequest{method: http.MethodDelete, path: "/api/v1/intents", body: jsonBody, idempotent: true})
 		if err != nil {
 			return err
 		}
@@ -240,14 +277,7 @@ func (dm *DecisionMakerClient) DeleteSchedulingIntents(ctx context.Context, deci
 		if err != nil {
 			return err
 		}
-		endpoint := dm.scheme() + "://" + decisionMaker.Host + ":" + strconv.Itoa(decisionMaker.Port) + "/api/v1/intents"
-		httpReq, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint, bytes.NewBuffer(jsonBody))
-		if err != nil {
-			return err
-		}
-		httpReq.Header.Set("Content-Type", "application/json")
-		httpReq.Header.Set("Authorization", "Bearer "+token)
-		resp, err := dm.Client.Do(httpReq)
+		resp, err := dm.do(ctx, decisionMaker, dmRequest{method: http.MethodDelete, path: "/api/v1/intents", body: jsonBody, idempotent: true})
 		if err != nil {
 			return err
 		}
@@ -261,18 +291,8 @@ func (dm *DecisionMakerClient) DeleteSchedulingIntents(ctx context.Context, deci
 }
 
 func (dm *DecisionMakerClient) GetPodPIDMapping(ctx context.Context, decisionMaker *domain.DecisionMakerPod) (*domain.PodPIDMappingResponse, error) {
-	token, err := dm.GetToken(ctx, decisionMaker)
-	if err != nil {
-		return nil, err
-	}
-
-	endpoint := dm.scheme() + "://" + decisionMaker.Host + ":" + strconv.Itoa(decisionMaker.Port) + "/api/v1/pods/pids"
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Authorization", "Bearer "+token)
-	resp, err := dm.Client.Do(req)
+	path := "/api/v1/pods/pids"
+	resp, err := dm.do(ctx, decisionMaker, dmRequest{method: http.MethodGet, path: path, idempotent: true})
 	if err != nil {
 		return nil, err
 	}
@@ -307,6 +327,8 @@ func (dm *DecisionMakerClient) GetPodPIDMapping(ctx context.Context, decisionMak
 			podInfo.Processes = append(podInfo.Processes, domain.PodProcess{
 				PID:         proc.PID,
 				Command:     proc.Command,
+				Cmdline:     proc.Cmdline,
+				Exe:         proc.Exe,
 				PPID:        proc.PPID,
 				ContainerID: proc.ContainerID,
 			})
@@ -316,3 +338,160 @@ func (dm *DecisionMakerClient) GetPodPIDMapping(ctx context.Context, decisionMak
 
 	return result, nil
 }
+
+func (dm *DecisionMakerClient) ExplainPod(ctx context.Context, decisionMaker *domain.DecisionMakerPod, podID string) (*domain.DecisionMakerPodExplanation, error) {
+	path := "/api/v1/pods/explain?podID=" + url.QueryEscape(podID)
+	resp, err := dm.do(ctx, decisionMaker, dmRequest{method: http.MethodGet, path: path, idempotent: true})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("decision maker %s returned non-OK status: %s", decisionMaker, resp.Status)
+	}
+
+	var explainResp dmrest.SuccessResponse[dmrest.ExplainPodResponse]
+	decoder := json.NewDecoder(resp.Body)
+	if err := decoder.Decode(&explainResp); err != nil {
+		return nil, err
+	}
+	if explainResp.Data == nil {
+		return nil, fmt.Errorf("decision maker %s returned empty pod explanation", decisionMaker)
+	}
+
+	// Convert dmrest types to domain types
+	result := &domain.DecisionMakerPodExplanation{
+		PodFound:    explainResp.Data.PodFound,
+		ProcessPIDs: explainResp.Data.ProcessPIDs,
+		Intents:     make([]domain.DecisionMakerIntentExplanation, 0, len(explainResp.Data.Intents)),
+	}
+	for _, intent := range explainResp.Data.Intents {
+		intentExplanation := domain.DecisionMakerIntentExplanation{
+			CommandRegex:  intent.CommandRegex,
+			ThreadRegex:   intent.ThreadRegex,
+			Priority:      intent.Priority,
+			ExecutionTime: intent.ExecutionTime,
+			Error:         intent.Error,
+			Processes:     make([]domain.ProcessExplanation, 0, len(intent.Processes)),
+		}
+		for _, proc := range intent.Processes {
+			intentExplanation.Processes = append(intentExplanation.Processes, domain.ProcessExplanation{
+				PID:         proc.PID,
+				Command:     proc.Command,
+				ContainerID: proc.ContainerID,
+				Outcome:     proc.Outcome,
+				TIDs:        proc.TIDs,
+			})
+		}
+		result.Intents = append(result.Intents, intentExplanation)
+	}
+
+	return result, nil
+}
+
+func (dm *DecisionMakerClient) GetMetricHistory(ctx context.Context, decisionMaker *domain.DecisionMakerPod, since time.Time, step time.Duration) ([]domain.MetricSample, error) {
+	query := url.Values{}
+	if !since.IsZero() {
+		query.Set("since", since.UTC().Format(time.RFC3339))
+	}
+	if step > 0 {
+		query.Set("step", step.String())
+	}
+	path := "/api/v1/metrics/history"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	resp, err := dm.do(ctx, decisionMaker, dmRequest{method: http.MethodGet, path: path, idempotent: true})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("decision maker %s returned non-OK status: %s", decisionMaker, resp.Status)
+	}
+
+	var historyResp dmrest.SuccessResponse[dmrest.MetricHistoryResponse]
+	decoder := json.NewDecoder(resp.Body)
+	if err := decoder.Decode(&historyResp); err != nil {
+		return nil, err
+	}
+	if historyResp.Data == nil {
+		return nil, fmt.Errorf("decision maker %s returned empty metric history", decisionMaker)
+	}
+
+	// Convert dmrest types to domain types
+	samples := make([]domain.MetricSample, 0, len(historyResp.Data.Samples))
+	for _, sample := range historyResp.Data.Samples {
+		metricSample, err := toDomainMetricSample(sample)
+		if err != nil {
+			return nil, fmt.Errorf("decision maker %s returned invalid metric sample: %w", decisionMaker, err)
+		}
+		samples = append(samples, *metricSample)
+	}
+	return samples, nil
+}
+
+func (dm *DecisionMakerClient) GetMetrics(ctx context.Context, decisionMaker *domain.DecisionMakerPod) (*domain.MetricSample, error) {
+	path := "/api/v1/metrics"
+	resp, err := dm.do(ctx, decisionMaker, dmRequest{method: http.MethodGet, path: path, idempotent: true})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("decision maker %s returned non-OK status: %s", decisionMaker, resp.Status)
+	}
+
+	var metricsResp dmrest.SuccessResponse[dmrest.GetMetricsResponse]
+	decoder := json.NewDecoder(resp.Body)
+	if err := decoder.Decode(&metricsResp); err != nil {
+		return nil, err
+	}
+	if metricsResp.Data == nil || metricsResp.Data.Sample == nil {
+		return nil, nil
+	}
+	sample, err := toDomainMetricSample(*metricsResp.Data.Sample)
+	if err != nil {
+		return nil, fmt.Errorf("decision maker %s returned invalid metric sample: %w", decisionMaker, err)
+	}
+	return sample, nil
+}
+
+func toDomainMetricSample(sample dmrest.MetricSample) (*domain.MetricSample, error) {
+	timestamp, err := time.Parse(time.RFC3339Nano, sample.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("parse timestamp %q: %w", sample.Timestamp, err)
+	}
+	return &domain.MetricSample{
+		Timestamp:          timestamp,
+		UserSchedLastRunAt: sample.Usersched_last_run_at,
+		NrQueued:           sample.Nr_queued,
+		NrScheduled:        sample.Nr_scheduled,
+		NrRunning:          sample.Nr_running,
+		NrOnlineCPUs:       sample.Nr_online_cpus,
+		NrUserDispatches:   sample.Nr_user_dispatches,
+		NrKernelDispatches: sample.Nr_kernel_dispatches,
+		NrCancelDispatches: sample.Nr_cancel_dispatches,
+		NrBounceDispatches: sample.Nr_bounce_dispatches,
+		NrFailedDispatches: sample.Nr_failed_dispatches,
+		NrSchedCongested:   sample.Nr_sched_congested,
+	}, nil
+}
+
+func strategyIDToString(id bson.ObjectID) string {
+	if id.IsZero() {
+		return ""
+	}
+	return id.Hex()
+}
+
+func commandMatchTargetsToStrings(targets []domain.CommandMatchTarget) []string {
+	if len(targets) == 0 {
+		return nil
+	}
+	results := make([]string, 0, len(targets))
+	for _, target := range targets {
+		results = append(results, string(target))
+	}
+	return results
+}
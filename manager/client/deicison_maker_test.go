@@ -104,7 +104,7 @@ func TestNewDecisionMakerClientMTLSDisabled(t *testing.T) {
 	keyConfig := config.KeyConfig{}
 	mtlsCfg := config.MTLSConfig{Enable: false}
 
-	c, err := NewDecisionMakerClient(keyConfig, mtlsCfg)
+	c, err := NewDecisionMakerClient(keyConfig, mtlsCfg, config.DMClientConfig{}, nil)
 	require.NoError(t, err)
 	require.NotNil(t, c)
 
@@ -120,7 +120,7 @@ func TestNewDecisionMakerClientMTLSBadCert(t *testing.T) {
 		KeyPem:  "not-valid-pem",
 		CAPem:   "not-valid-pem",
 	}
-	_, err := NewDecisionMakerClient(config.KeyConfig{}, mtlsCfg)
+	_, err := NewDecisionMakerClient(config.KeyConfig{}, mtlsCfg, config.DMClientConfig{}, nil)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "load mTLS client certificate")
 }
@@ -134,7 +134,7 @@ func TestNewDecisionMakerClientMTLSBadCA(t *testing.T) {
 		KeyPem:  config.SecretValue(certs.keyPEM),
 		CAPem:   config.SecretValue("not-a-valid-ca-pem"),
 	}
-	_, err := NewDecisionMakerClient(config.KeyConfig{}, mtlsCfg)
+	_, err := NewDecisionMakerClient(config.KeyConfig{}, mtlsCfg, config.DMClientConfig{}, nil)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "parse mTLS CA certificate")
 }
@@ -148,7 +148,7 @@ func TestDecisionMakerClientMTLSEnabled(t *testing.T) {
 		KeyPem:  config.SecretValue(certs.keyPEM),
 		CAPem:   config.SecretValue(certs.caPEM),
 	}
-	c, err := NewDecisionMakerClient(config.KeyConfig{}, mtlsCfg)
+	c, err := NewDecisionMakerClient(config.KeyConfig{}, mtlsCfg, config.DMClientConfig{}, nil)
 	require.NoError(t, err)
 	require.NotNil(t, c)
 
@@ -189,7 +189,7 @@ func TestDecisionMakerClientMTLSEndToEnd(t *testing.T) {
 		KeyPem:  config.SecretValue(certs.keyPEM),
 		CAPem:   config.SecretValue(certs.caPEM),
 	}
-	c, err := NewDecisionMakerClient(config.KeyConfig{}, mtlsCfg)
+	c, err := NewDecisionMakerClient(config.KeyConfig{}, mtlsCfg, config.DMClientConfig{}, nil)
 	require.NoError(t, err)
 
 	dm := newDecisionMakerPodFromServerURL(t, server.URL)
@@ -0,0 +1,226 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/pkg/logger"
+)
+
+const (
+	defaultDMClientTimeout         = 10 * time.Second
+	defaultDMClientMaxAttempts     = 3
+	defaultDMClientInitialBackoff  = 200 * time.Millisecond
+	defaultBreakerFailureThreshold = 5
+	defaultBreakerOpenDuration     = 30 * time.Second
+	maxDMClientBackoff             = 5 * time.Second
+)
+
+// ErrCircuitOpen is returned without calling a decision maker whose recent requests kept failing
+var ErrCircuitOpen = errors.New("decision maker circuit breaker is open")
+
+// statusError is a non-OK response of a decision maker
+type statusError struct {
+	decisionMaker *domain.DecisionMakerPod
+	statusCode    int
+	status        string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("decision maker %s returned non-OK status: %s", e.decisionMaker, e.status)
+}
+
+// retryPolicy controls how often a failed idempotent request is sent again
+type retryPolicy struct {
+	maxAttempts    int
+	initialBackoff time.Duration
+}
+
+// backoff returns the delay before the retry following attempt, jittered between half and all of
+// the exponential backoff so that the requests to a recovering node don't arrive in bursts
+func (p retryPolicy) backoff(attempt int) time.Duration {
+	backoff := min(p.initialBackoff<<(attempt-1), maxDMClientBackoff)
+	if backoff <= 0 {
+		return 0
+	}
+	return backoff/2 + rand.N(backoff/2+1)
+}
+
+// unhealthyStatus reports whether a decision maker answering code is overloaded or failing
+func unhealthyStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// failed reports whether a request ending with resp and err points at an unhealthy decision maker,
+// the cancellation of the caller doesn't
+func failed(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		var statusErr *statusError
+		if errors.As(err, &statusErr) {
+			return unhealthyStatus(statusErr.statusCode)
+		}
+		return ctx.Err() == nil && !errors.Is(err, ErrCircuitOpen)
+	}
+	return unhealthyStatus(resp.StatusCode)
+}
+
+// circuitBreaker stops calling a node after threshold consecutive failures, once openFor elapsed a
+// single probe request is let through and its outcome closes or reopens the breaker
+type circuitBreaker struct {
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+func (b *circuitBreaker) allow(now time.Time, threshold int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if threshold <= 0 || b.failures < threshold {
+		return true
+	}
+	if now.Before(b.openUntil) || b.probing {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// record counts the outcome of an allowed request and reports whether it opened the breaker
+func (b *circuitBreaker) record(failed bool, now time.Time, threshold int, openFor time.Duration) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if !failed {
+		b.failures = 0
+		return false
+	}
+	b.failures++
+	if threshold <= 0 || b.failures < threshold {
+		return false
+	}
+	b.openUntil = now.Add(openFor)
+	return true
+}
+
+// release lets another request probe the node after an allowed request ended without telling
+// anything about the node, e.g. because the caller cancelled it
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// nodeState is what the client tracks about the decision maker of a node
+type nodeState struct {
+	address string
+	breaker *circuitBreaker
+}
+
+// node returns the state of the node of decisionMaker. A decision maker answering on another
+// address is a new pod, possibly with another signing key, so its cached token and breaker are
+// dropped.
+func (dm *DecisionMakerClient) node(ctx context.Context, decisionMaker *domain.DecisionMakerPod) *nodeState {
+	address := decisionMaker.Host + ":" + strconv.Itoa(decisionMaker.Port)
+	dm.nodesMu.Lock()
+	defer dm.nodesMu.Unlock()
+	if dm.nodes == nil {
+		dm.nodes = map[string]*nodeState{}
+	}
+	state, ok := dm.nodes[decisionMaker.NodeID]
+	if ok && state.address == address {
+		return state
+	}
+	if ok {
+		logger.Logger(ctx).Info().Msgf("decision maker of node %s moved from %s to %s, dropping its token", decisionMaker.NodeID, state.address, address)
+		dm.tokenCache.Delete(decisionMaker.NodeID)
+	}
+	state = &nodeState{address: address, breaker: &circuitBreaker{}}
+	dm.nodes[decisionMaker.NodeID] = state
+	return state
+}
+
+// dmRequest is a call to the API of a decision maker
+type dmRequest struct {
+	method string
+	// path is the request URI, including the query
+	path string
+	body []byte
+	// idempotent requests are retried after network errors, 429 and 5xx responses
+	idempotent bool
+}
+
+// do sends req to decisionMaker with its token. A token rejected with 401 is re-acquired once,
+// and the response is returned for the caller to check its status.
+func (dm *DecisionMakerClient) do(ctx context.Context, decisionMaker *domain.DecisionMakerPod, req dmRequest) (*http.Response, error) {
+	state := dm.node(ctx, decisionMaker)
+	if !state.breaker.allow(time.Now(), dm.breakerThreshold) {
+		return nil, fmt.Errorf("%w for node %s", ErrCircuitOpen, decisionMaker.NodeID)
+	}
+	resp, err := dm.doWithRetry(ctx, decisionMaker, req)
+	if ctx.Err() != nil {
+		// neither a success nor a failure of the decision maker
+		state.breaker.release()
+		return resp, err
+	}
+	if state.breaker.record(failed(ctx, resp, err), time.Now(), dm.breakerThreshold, dm.breakerOpenFor) {
+		logger.Logger(ctx).Warn().Err(err).Msgf("decision maker of node %s kept failing, not calling it for %s", decisionMaker.NodeID, dm.breakerOpenFor)
+	}
+	return resp, err
+}
+
+func (dm *DecisionMakerClient) doWithRetry(ctx context.Context, decisionMaker *domain.DecisionMakerPod, req dmRequest) (*http.Response, error) {
+	maxAttempts := 1
+	if req.idempotent {
+		maxAttempts = max(dm.retry.maxAttempts, 1)
+	}
+	refreshed := false
+	for attempt := 1; ; attempt++ {
+		resp, err := dm.send(ctx, decisionMaker, req)
+		if err == nil && resp.StatusCode == http.StatusUnauthorized && !refreshed {
+			// the decision maker restarted with another key or forgot the token, the request was
+			// rejected before any side effect
+			resp.Body.Close()
+			dm.tokenCache.Delete(decisionMaker.NodeID)
+			refreshed = true
+			attempt--
+			continue
+		}
+		if attempt >= maxAttempts || !failed(ctx, resp, err) {
+			return resp, err
+		}
+		if err == nil {
+			resp.Body.Close()
+		}
+		logger.Logger(ctx).Debug().Err(err).Msgf("%s %s on node %s failed, attempt %d of %d", req.method, req.path, decisionMaker.NodeID, attempt, maxAttempts)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(dm.retry.backoff(attempt)):
+		}
+	}
+}
+
+func (dm *DecisionMakerClient) send(ctx context.Context, decisionMaker *domain.DecisionMakerPod, req dmRequest) (*http.Response, error) {
+	token, err := dm.GetToken(ctx, decisionMaker)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, dm.endpoint(decisionMaker, req.path), bytes.NewReader(req.body))
+	if err != nil {
+		return nil, err
+	}
+	if req.body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	return dm.Client.Do(httpReq)
+}
@@ -0,0 +1,219 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const merkleRootBody = `{"success":true,"data":{"rootHash":"root"},"timestamp":"2026-01-01T00:00:00Z"}`
+
+func newResilientTestClient(t *testing.T, cfg config.DMClientConfig) *DecisionMakerClient {
+	t.Helper()
+	if cfg.InitialBackoffMs == 0 {
+		cfg.InitialBackoffMs = 1
+	}
+	c, err := NewDecisionMakerClient(config.KeyConfig{ClientID: "manager"}, config.MTLSConfig{}, cfg, nil)
+	require.NoError(t, err)
+	return c.(*DecisionMakerClient)
+}
+
+// tokenHandler issues token-<n> for the nth token request and passes the other requests to next
+func tokenHandler(tokens *atomic.Int32, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/auth/token" {
+			next(w, r)
+			return
+		}
+		n := tokens.Add(1)
+		expiredAt := time.Now().Add(time.Hour).Unix()
+		_, _ = w.Write([]byte(`{"success":true,"data":{"token":"token-` + strconv.Itoa(int(n)) + `","expired_at":` + strconv.FormatInt(expiredAt, 10) + `}}`))
+	}
+}
+
+func TestDecisionMakerClientRetriesIdempotentRequests(t *testing.T) {
+	var tokens, calls atomic.Int32
+	server := httptest.NewServer(tokenHandler(&tokens, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(merkleRootBody))
+	}))
+	defer server.Close()
+
+	c := newResilientTestClient(t, config.DMClientConfig{MaxAttempts: 3})
+	got, err := c.GetIntentMerkleRoot(context.Background(), newDecisionMakerPodFromServerURL(t, server.URL))
+	require.NoError(t, err)
+	assert.Equal(t, "root", got)
+	assert.EqualValues(t, 3, calls.Load())
+	// the token is cached across attempts
+	assert.EqualValues(t, 1, tokens.Load())
+
+	calls.Store(0)
+	c = newResilientTestClient(t, config.DMClientConfig{MaxAttempts: 2})
+	_, err = c.GetIntentMerkleRoot(context.Background(), newDecisionMakerPodFromServerURL(t, server.URL))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "returned non-OK status")
+	assert.EqualValues(t, 2, calls.Load())
+}
+
+func TestDecisionMakerClientDoesNotRetryClientErrors(t *testing.T) {
+	var tokens, calls atomic.Int32
+	server := httptest.NewServer(tokenHandler(&tokens, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	c := newResilientTestClient(t, config.DMClientConfig{MaxAttempts: 3})
+	_, err := c.GetIntentMerkleRoot(context.Background(), newDecisionMakerPodFromServerURL(t, server.URL))
+	require.Error(t, err)
+	assert.EqualValues(t, 1, calls.Load())
+}
+
+func TestDecisionMakerClientRefreshesRejectedToken(t *testing.T) {
+	var tokens atomic.Int32
+	server := httptest.NewServer(tokenHandler(&tokens, func(w http.ResponseWriter, r *http.Request) {
+		// the decision maker restarted and only accepts the tokens it issued since
+		if r.Header.Get("Authorization") != "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(merkleRootBody))
+	}))
+	defer server.Close()
+
+	dm := newDecisionMakerPodFromServerURL(t, server.URL)
+	c := newResilientTestClient(t, config.DMClientConfig{MaxAttempts: 1})
+	c.tokenCache.Set(dm.NodeID, "stale-token")
+	got, err := c.GetIntentMerkleRoot(context.Background(), dm)
+	require.NoError(t, err)
+	assert.Equal(t, "root", got)
+	assert.EqualValues(t, 1, tokens.Load())
+
+	// a token rejected again is only re-acquired once per request
+	c.tokenCache.Set(dm.NodeID, "stale-token")
+	tokens.Store(5)
+	_, err = c.GetIntentMerkleRoot(context.Background(), dm)
+	require.Error(t, err)
+	assert.EqualValues(t, 6, tokens.Load())
+}
+
+func TestDecisionMakerClientCircuitBreaker(t *testing.T) {
+	var tokens, calls atomic.Int32
+	var healthy atomic.Bool
+	server := httptest.NewServer(tokenHandler(&tokens, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(merkleRootBody))
+	}))
+	defer server.Close()
+
+	dm := newDecisionMakerPodFromServerURL(t, server.URL)
+	c := newResilientTestClient(t, config.DMClientConfig{MaxAttempts: 1, BreakerFailureThreshold: 2, BreakerOpenSec: 60})
+	for range 2 {
+		_, err := c.GetIntentMerkleRoot(context.Background(), dm)
+		require.Error(t, err)
+	}
+	_, err := c.GetIntentMerkleRoot(context.Background(), dm)
+	require.True(t, errors.Is(err, ErrCircuitOpen))
+	assert.EqualValues(t, 2, calls.Load())
+
+	// once the breaker timed out a single probe closes it again
+	healthy.Store(true)
+	c.node(context.Background(), dm).breaker.openUntil = time.Now()
+	_, err = c.GetIntentMerkleRoot(context.Background(), dm)
+	require.NoError(t, err)
+	_, err = c.GetIntentMerkleRoot(context.Background(), dm)
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, calls.Load())
+}
+
+func TestDecisionMakerClientCircuitBreakerIgnoresCancelledRequests(t *testing.T) {
+	var tokens, calls atomic.Int32
+	server := httptest.NewServer(tokenHandler(&tokens, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = w.Write([]byte(merkleRootBody))
+	}))
+	defer server.Close()
+
+	dm := newDecisionMakerPodFromServerURL(t, server.URL)
+	c := newResilientTestClient(t, config.DMClientConfig{MaxAttempts: 1, BreakerFailureThreshold: 2, BreakerOpenSec: 60})
+	breaker := c.node(context.Background(), dm).breaker
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// a cancelled request does not reset the failures of a closed breaker
+	breaker.failures = 1
+	_, err := c.GetIntentMerkleRoot(cancelled, dm)
+	require.Error(t, err)
+	assert.Equal(t, 1, breaker.failures)
+
+	// nor does a cancelled probe close an open breaker, the next request probes again
+	breaker.failures = 2
+	breaker.openUntil = time.Now()
+	_, err = c.GetIntentMerkleRoot(cancelled, dm)
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, ErrCircuitOpen))
+	assert.Equal(t, 2, breaker.failures)
+	assert.False(t, breaker.probing)
+
+	_, err = c.GetIntentMerkleRoot(context.Background(), dm)
+	require.NoError(t, err)
+	assert.Equal(t, 0, breaker.failures)
+	assert.EqualValues(t, 1, calls.Load())
+}
+
+func TestDecisionMakerClientDropsTokenOfMovedNode(t *testing.T) {
+	var tokens atomic.Int32
+	server := httptest.NewServer(tokenHandler(&tokens, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(merkleRootBody))
+	}))
+	defer server.Close()
+
+	dm := newDecisionMakerPodFromServerURL(t, server.URL)
+	c := newResilientTestClient(t, config.DMClientConfig{})
+	moved := &domain.DecisionMakerPod{NodeID: dm.NodeID, Host: "10.0.0.1", Port: dm.Port}
+	c.node(context.Background(), moved)
+	c.tokenCache.Set(dm.NodeID, "token-of-old-pod")
+
+	_, err := c.GetIntentMerkleRoot(context.Background(), dm)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, tokens.Load())
+	token, ok := c.tokenCache.Get(dm.NodeID)
+	require.True(t, ok)
+	assert.Equal(t, "token-1", token)
+}
+
+func TestDecisionMakerClientTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	dm := newDecisionMakerPodFromServerURL(t, server.URL)
+	c := newResilientTestClient(t, config.DMClientConfig{MaxAttempts: 1})
+	c.Client.Timeout = 50 * time.Millisecond
+	c.tokenCache.Set(dm.NodeID, "token")
+
+	start := time.Now()
+	_, err := c.GetIntentMerkleRoot(context.Background(), dm)
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
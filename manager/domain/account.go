@@ -17,6 +17,26 @@ type User struct {
 	Status         UserStatus        `bson:"status,omitempty"`
 	Roles          []string          `bson:"roles,omitempty"`
 	PermissionKeys []string          `bson:"permissionKeys,omitempty"`
+	// SessionVersion is embedded in issued JWTs, incrementing it invalidates every session of the user
+	SessionVersion int `bson:"sessionVersion,omitempty"`
+	// IdentityProvider and ExternalID are the issuer and subject of users provisioned by single
+	// sign-on, such users can't log in with a password
+	IdentityProvider string `bson:"identityProvider,omitempty"`
+	ExternalID       string `bson:"externalID,omitempty"`
+	// PasswordHistory holds the hashes of previous passwords, newest first
+	PasswordHistory  []EncryptedPassword `bson:"passwordHistory,omitempty"`
+	LastLoginTime    int64               `bson:"lastLoginTime,omitempty"`
+	LastLoginIP      string              `bson:"lastLoginIP,omitempty"`
+	FailedLoginCount int                 `bson:"failedLoginCount,omitempty"`
+	// LockedUntil is the unix time in milliseconds a lockout after failed logins ends at
+	LockedUntil  int64          `bson:"lockedUntil,omitempty"`
+	LoginHistory []LoginAttempt `bson:"loginHistory,omitempty"`
+	TwoFactor    *TwoFactor     `bson:"twoFactor,omitempty"`
+}
+
+// IsExternal reports whether the user authenticates at an external identity provider
+func (u *User) IsExternal() bool {
+	return u.IdentityProvider != ""
 }
 
 type Role struct {
@@ -24,12 +44,15 @@ type Role struct {
 	Name        string       `bson:"name,omitempty"`
 	Description string       `bson:"description,omitempty"`
 	Policies    []RolePolicy `bson:"policies,omitempty"`
+	// RequireTwoFactor restricts local users with the role to two-factor enrollment until they enable it
+	RequireTwoFactor bool `bson:"requireTwoFactor,omitempty"`
 }
 
 type UpdateRoleOptions struct {
-	Name        *string       `bson:"name,omitempty"`
-	Description *string       `bson:"description,omitempty"`
-	Policies    *[]RolePolicy `bson:"policies,omitempty"`
+	Name             *string       `bson:"name,omitempty"`
+	Description      *string       `bson:"description,omitempty"`
+	Policies         *[]RolePolicy `bson:"policies,omitempty"`
+	RequireTwoFactor *bool         `bson:"requireTwoFactor,omitempty"`
 }
 
 type RolePolicy struct {
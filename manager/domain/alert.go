@@ -0,0 +1,170 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// AlertMetric is a decision maker metric an alert rule is evaluated on
+type AlertMetric string
+
+const (
+	AlertMetricNrQueued           AlertMetric = "nr_queued"
+	AlertMetricNrScheduled        AlertMetric = "nr_scheduled"
+	AlertMetricNrRunning          AlertMetric = "nr_running"
+	AlertMetricNrOnlineCPUs       AlertMetric = "nr_online_cpus"
+	AlertMetricNrUserDispatches   AlertMetric = "nr_user_dispatches"
+	AlertMetricNrKernelDispatches AlertMetric = "nr_kernel_dispatches"
+	AlertMetricNrCancelDispatches AlertMetric = "nr_cancel_dispatches"
+	AlertMetricNrBounceDispatches AlertMetric = "nr_bounce_dispatches"
+	AlertMetricNrFailedDispatches AlertMetric = "nr_failed_dispatches"
+	AlertMetricNrSchedCongested   AlertMetric = "nr_sched_congested"
+	// AlertMetricUserSchedIdleSeconds is how long usersched_last_run_at has not advanced
+	AlertMetricUserSchedIdleSeconds AlertMetric = "usersched_idle_seconds"
+)
+
+var alertMetrics = []AlertMetric{
+	AlertMetricNrQueued, AlertMetricNrScheduled, AlertMetricNrRunning, AlertMetricNrOnlineCPUs,
+	AlertMetricNrUserDispatches, AlertMetricNrKernelDispatches, AlertMetricNrCancelDispatches,
+	AlertMetricNrBounceDispatches, AlertMetricNrFailedDispatches, AlertMetricNrSchedCongested,
+	AlertMetricUserSchedIdleSeconds,
+}
+
+// Value returns the value of the metric in sample; AlertMetricUserSchedIdleSeconds is not part
+// of a sample and reports false
+func (m AlertMetric) Value(sample *MetricSample) (float64, bool) {
+	switch m {
+	case AlertMetricNrQueued:
+		return float64(sample.NrQueued), true
+	case AlertMetricNrScheduled:
+		return float64(sample.NrScheduled), true
+	case AlertMetricNrRunning:
+		return float64(sample.NrRunning), true
+	case AlertMetricNrOnlineCPUs:
+		return float64(sample.NrOnlineCPUs), true
+	case AlertMetricNrUserDispatches:
+		return float64(sample.NrUserDispatches), true
+	case AlertMetricNrKernelDispatches:
+		return float64(sample.NrKernelDispatches), true
+	case AlertMetricNrCancelDispatches:
+		return float64(sample.NrCancelDispatches), true
+	case AlertMetricNrBounceDispatches:
+		return float64(sample.NrBounceDispatches), true
+	case AlertMetricNrFailedDispatches:
+		return float64(sample.NrFailedDispatches), true
+	case AlertMetricNrSchedCongested:
+		return float64(sample.NrSchedCongested), true
+	}
+	return 0, false
+}
+
+// AlertComparator compares the evaluated value against the rule threshold
+type AlertComparator string
+
+const (
+	AlertComparatorGreater      AlertComparator = ">"
+	AlertComparatorGreaterEqual AlertComparator = ">="
+	AlertComparatorLess         AlertComparator = "<"
+	AlertComparatorLessEqual    AlertComparator = "<="
+)
+
+// AlertRule fires for a node when the metric (or its per-second rate) compared to Threshold has
+// held for ForSec seconds, e.g. "nr_sched_congested rate > 10 for 300s" or
+// "usersched_idle_seconds > 30"
+type AlertRule struct {
+	BaseEntity  `bson:",inline"`
+	Name        string          `bson:"name,omitempty"`
+	Description string          `bson:"description,omitempty"`
+	Metric      AlertMetric     `bson:"metric,omitempty"`
+	Rate        bool            `bson:"rate,omitempty"`
+	Comparator  AlertComparator `bson:"comparator,omitempty"`
+	Threshold   float64         `bson:"threshold"`
+	ForSec      int64           `bson:"forSec,omitempty"`
+	// NodeIDs limits the rule to these nodes, empty means every node
+	NodeIDs     []string `bson:"nodeIDs,omitempty"`
+	WebhookURLs []string `bson:"webhookURLs,omitempty"`
+	Disabled    bool     `bson:"disabled,omitempty"`
+}
+
+// Validate checks the rule is complete and its metric, comparator and webhooks are valid
+func (r *AlertRule) Validate() error {
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+	if !slices.Contains(alertMetrics, r.Metric) {
+		return fmt.Errorf("unsupported metric %q", r.Metric)
+	}
+	if r.Rate && r.Metric == AlertMetricUserSchedIdleSeconds {
+		return fmt.Errorf("rate is not supported for %s", r.Metric)
+	}
+	switch r.Comparator {
+	case AlertComparatorGreater, AlertComparatorGreaterEqual, AlertComparatorLess, AlertComparatorLessEqual:
+	default:
+		return fmt.Errorf("unsupported comparator %q", r.Comparator)
+	}
+	if r.ForSec < 0 {
+		return errors.New("forSec must not be negative")
+	}
+	for _, webhookURL := range r.WebhookURLs {
+		u, err := url.Parse(webhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid webhook URL %q", webhookURL)
+		}
+	}
+	return nil
+}
+
+// AppliesTo reports whether the rule is evaluated on nodeID
+func (r *AlertRule) AppliesTo(nodeID string) bool {
+	return len(r.NodeIDs) == 0 || slices.Contains(r.NodeIDs, nodeID)
+}
+
+// Breached reports whether value violates the rule threshold
+func (r *AlertRule) Breached(value float64) bool {
+	switch r.Comparator {
+	case AlertComparatorGreater:
+		return value > r.Threshold
+	case AlertComparatorGreaterEqual:
+		return value >= r.Threshold
+	case AlertComparatorLess:
+		return value < r.Threshold
+	case AlertComparatorLessEqual:
+		return value <= r.Threshold
+	}
+	return false
+}
+
+type AlertState string
+
+const (
+	AlertStateFiring   AlertState = "firing"
+	AlertStateResolved AlertState = "resolved"
+)
+
+// Alert is a firing or resolved occurrence of an alert rule on a node
+type Alert struct {
+	ID         bson.ObjectID `bson:"_id,omitempty"`
+	RuleID     bson.ObjectID `bson:"ruleID,omitempty"`
+	RuleName   string        `bson:"ruleName,omitempty"`
+	NodeID     string        `bson:"nodeID,omitempty"`
+	State      AlertState    `bson:"state,omitempty"`
+	Value      float64       `bson:"value"`
+	StartsAt   int64         `bson:"startsAt,omitempty"`   // unix milliseconds
+	ResolvedAt int64         `bson:"resolvedAt,omitempty"` // unix milliseconds
+}
+
+// AlertNodeState is what the alert evaluation remembers about a node between two evaluations. It is
+// stored rather than kept in memory so that another manager replica can take the evaluation over.
+type AlertNodeState struct {
+	NodeID string `bson:"_id"`
+	// Previous is the sample of the previous evaluation, rates are computed against it
+	Previous             *MetricSample `bson:"previous,omitempty"`
+	UserSchedLastRunAt   uint64        `bson:"userSchedLastRunAt"`
+	UserSchedLastRunSeen int64         `bson:"userSchedLastRunSeen"` // unix milliseconds
+	// Pending holds since when each breached rule, by hex ID, has been breached in unix milliseconds
+	Pending map[string]int64 `bson:"pending,omitempty"`
+}
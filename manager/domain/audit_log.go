@@ -2,6 +2,7 @@ package domain
 
 import "go.mongodb.org/mongo-driver/v2/bson"
 
+// AuditLog records a mutating API call
 type AuditLog struct {
 	ID        bson.ObjectID `bson:"_id,omitempty"`
 	UserID    bson.ObjectID `bson:"user_id,omitempty"`
@@ -9,4 +10,20 @@ type AuditLog struct {
 	RequestID string        `bson:"request_id,omitempty"`
 	Timestamp int64         `bson:"timestamp,omitempty"`
 	IP        string        `bson:"ip,omitempty"`
+	Method    string        `bson:"method,omitempty"`
+	Path      string        `bson:"path,omitempty"`
+	// ResourceID identifies the resource the call acted on, comma separated when there are several
+	ResourceID string `bson:"resource_id,omitempty"`
+	// Status is the HTTP status code of the response
+	Status int `bson:"status,omitempty"`
+	// Changes is the before/after diff of the resource, recorded for strategies and roles
+	Changes []AuditChange `bson:"changes,omitempty"`
+}
+
+// AuditChange is a changed top-level field of an audited resource, Before and After hold the
+// JSON encoding of the value and are empty when the field did not exist
+type AuditChange struct {
+	Field  string `bson:"field" json:"field"`
+	Before string `bson:"before,omitempty" json:"before,omitempty"`
+	After  string `bson:"after,omitempty" json:"after,omitempty"`
 }
@@ -9,6 +9,11 @@ import (
 type Claims struct {
 	UID                string `json:"uid"`
 	NeedChangePassword bool   `json:"needChangePassword"`
+	// ServiceAccount is set when the request was authenticated with an API key, UID is then the
+	// ID of the service account
+	ServiceAccount bool `json:"serviceAccount,omitempty"`
+	// SessionVersion must match the session version of the user for the token to be valid
+	SessionVersion int `json:"sv,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -3,22 +3,46 @@ package domain
 type PermissionKey string
 
 const (
-	CreateUser             PermissionKey = "user.create"
-	UserRead               PermissionKey = "user.read"
-	ChangeUserPermission   PermissionKey = "user.permission.update"
-	ResetUserPassword      PermissionKey = "user.password.reset"
-	RoleCrete              PermissionKey = "role.create"
-	RoleRead               PermissionKey = "role.read"
-	RoleUpdate             PermissionKey = "role.update"
-	RoleDelete             PermissionKey = "role.delete"
-	PermissionRead         PermissionKey = "permission.read"
-	ScheduleStrategyCreate PermissionKey = "schedule_strategy.create"
-	ScheduleStrategyRead   PermissionKey = "schedule_strategy.read"
-	ScheduleStrategyUpdate PermissionKey = "schedule_strategy.update"
-	ScheduleStrategyDelete PermissionKey = "schedule_strategy.delete"
-	ScheduleIntentRead     PermissionKey = "schedule_intent.read"
-	ScheduleIntentDelete   PermissionKey = "schedule_intent.delete"
-	PodPIDMappingRead      PermissionKey = "pod_pid_mapping.read"
+	CreateUser                PermissionKey = "user.create"
+	UserRead                  PermissionKey = "user.read"
+	ChangeUserPermission      PermissionKey = "user.permission.update"
+	ResetUserPassword         PermissionKey = "user.password.reset"
+	UserSessionRevoke         PermissionKey = "user.session.revoke"
+	UserUnlock                PermissionKey = "user.unlock"
+	UserTwoFactorReset        PermissionKey = "user.2fa.reset"
+	RoleCrete                 PermissionKey = "role.create"
+	RoleRead                  PermissionKey = "role.read"
+	RoleUpdate                PermissionKey = "role.update"
+	RoleDelete                PermissionKey = "role.delete"
+	PermissionRead            PermissionKey = "permission.read"
+	ScheduleStrategyCreate    PermissionKey = "schedule_strategy.create"
+	ScheduleStrategyRead      PermissionKey = "schedule_strategy.read"
+	ScheduleStrategyUpdate    PermissionKey = "schedule_strategy.update"
+	ScheduleStrategyDelete    PermissionKey = "schedule_strategy.delete"
+	ScheduleStrategyTransfer  PermissionKey = "schedule_strategy.transfer"
+	ScheduleIntentRead        PermissionKey = "schedule_intent.read"
+	ScheduleIntentDelete      PermissionKey = "schedule_intent.delete"
+	PodPIDMappingRead         PermissionKey = "pod_pid_mapping.read"
+	PodExplain                PermissionKey = "pod.explain"
+	NodeMetricsRead           PermissionKey = "node_metrics.read"
+	AlertRuleCreate           PermissionKey = "alert_rule.create"
+	AlertRuleRead             PermissionKey = "alert_rule.read"
+	AlertRuleUpdate           PermissionKey = "alert_rule.update"
+	AlertRuleDelete           PermissionKey = "alert_rule.delete"
+	AlertRead                 PermissionKey = "alert.read"
+	WebhookSubscriptionCreate PermissionKey = "webhook_subscription.create"
+	WebhookSubscriptionRead   PermissionKey = "webhook_subscription.read"
+	WebhookSubscriptionUpdate PermissionKey = "webhook_subscription.update"
+	WebhookSubscriptionDelete PermissionKey = "webhook_subscription.delete"
+	AuditLogRead              PermissionKey = "audit_log.read"
+	ServiceAccountCreate      PermissionKey = "service_account.create"
+	ServiceAccountRead        PermissionKey = "service_account.read"
+	ServiceAccountUpdate      PermissionKey = "service_account.update"
+	ServiceAccountDelete      PermissionKey = "service_account.delete"
+	TeamCreate                PermissionKey = "team.create"
+	TeamRead                  PermissionKey = "team.read"
+	TeamUpdate                PermissionKey = "team.update"
+	TeamDelete                PermissionKey = "team.delete"
 )
 
 const (
@@ -40,3 +64,30 @@ const (
 	IntentStateInitialized
 	IntentStateSent
 )
+
+// CommandMatchTarget selects which process attribute a strategy's CommandRegex is matched against
+type CommandMatchTarget string
+
+const (
+	// CommandMatchComm matches /proc/<pid>/comm, which the kernel truncates to 15 characters
+	CommandMatchComm CommandMatchTarget = "comm"
+	// CommandMatchCmdline matches the full space-joined /proc/<pid>/cmdline
+	CommandMatchCmdline CommandMatchTarget = "cmdline"
+	// CommandMatchExe matches the target of the /proc/<pid>/exe symlink
+	CommandMatchExe CommandMatchTarget = "exe"
+	// CommandMatchContainerName matches the name of the container the process runs in
+	CommandMatchContainerName CommandMatchTarget = "containerName"
+)
+
+// DefaultCommandMatchTargets is used to select pods when a strategy does not specify any match
+// targets, it keeps matching the joined command and args as before match targets existed
+var DefaultCommandMatchTargets = []CommandMatchTarget{CommandMatchCmdline}
+
+func (t CommandMatchTarget) IsValid() bool {
+	switch t {
+	case CommandMatchComm, CommandMatchCmdline, CommandMatchExe, CommandMatchContainerName:
+		return true
+	default:
+		return false
+	}
+}
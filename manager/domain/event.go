@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+)
+
+// EventType identifies a strategy or intent lifecycle event delivered to webhook subscriptions
+type EventType string
+
+const (
+	EventStrategyCreated EventType = "strategy.created"
+	EventStrategyUpdated EventType = "strategy.updated"
+	EventStrategyDeleted EventType = "strategy.deleted"
+	// EventIntentsSent is emitted once per decision maker that accepted intents
+	EventIntentsSent EventType = "intents.sent"
+	// EventIntentsFailed is emitted once per decision maker that could not be sent intents
+	EventIntentsFailed EventType = "intents.failed"
+	// EventDMResyncTriggered is emitted when reconciliation found a decision maker out of sync
+	EventDMResyncTriggered EventType = "dm.resync_triggered"
+	// EventStaleIntentsRemoved is emitted when reconciliation removed intents of pods that no longer exist
+	EventStaleIntentsRemoved EventType = "intents.stale_removed"
+)
+
+var EventTypes = []EventType{
+	EventStrategyCreated,
+	EventStrategyUpdated,
+	EventStrategyDeleted,
+	EventIntentsSent,
+	EventIntentsFailed,
+	EventDMResyncTriggered,
+	EventStaleIntentsRemoved,
+}
+
+// WebhookSubscription receives the events matching Events, signed with Secret
+type WebhookSubscription struct {
+	BaseEntity `bson:",inline"`
+	Name       string `bson:"name,omitempty"`
+	URL        string `bson:"url,omitempty"`
+	// Secret is the HMAC-SHA256 key of the delivery signatures
+	Secret string `bson:"secret,omitempty"`
+	// Events limits the subscription to these event types, empty means every event
+	Events   []EventType `bson:"events,omitempty"`
+	Disabled bool        `bson:"disabled,omitempty"`
+}
+
+// Validate checks the subscription has a name, an http(s) URL and known event types
+func (s *WebhookSubscription) Validate() error {
+	if s.Name == "" {
+		return errors.New("name is required")
+	}
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook URL %q", s.URL)
+	}
+	for _, eventType := range s.Events {
+		if !slices.Contains(EventTypes, eventType) {
+			return fmt.Errorf("unsupported event type %q", eventType)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether events of eventType are delivered to the subscription
+func (s *WebhookSubscription) Matches(eventType EventType) bool {
+	return !s.Disabled && (len(s.Events) == 0 || slices.Contains(s.Events, eventType))
+}
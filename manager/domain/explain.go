@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// StrategyMatchExplanation reports whether a strategy's selectors match a pod and why not
+type StrategyMatchExplanation struct {
+	StrategyID       bson.ObjectID
+	Matched          bool
+	NamespaceMatched bool
+	LabelsMatched    bool
+	CommandMatched   bool
+	// MatchedContainers lists the containers whose spec matches the strategy's CommandRegex
+	MatchedContainers []string
+	// Reasons describes every selector that failed to match
+	Reasons []string
+}
+
+// ExplainMatch evaluates the strategy's namespace, label and command selectors against pod,
+// following the same rules K8SAdapter.QueryPods applies when the strategy is created
+func (s *ScheduleStrategy) ExplainMatch(pod *Pod) StrategyMatchExplanation {
+	result := StrategyMatchExplanation{
+		StrategyID:       s.ID,
+		NamespaceMatched: len(s.K8sNamespace) == 0,
+		LabelsMatched:    true,
+	}
+
+	for _, namespace := range s.K8sNamespace {
+		if namespace == pod.K8SNamespace {
+			result.NamespaceMatched = true
+			break
+		}
+	}
+	if !result.NamespaceMatched {
+		result.Reasons = append(result.Reasons, fmt.Sprintf("namespace %q is not in %v", pod.K8SNamespace, s.K8sNamespace))
+	}
+
+	for _, selector := range s.LabelSelectors {
+		if selector.Key == "" {
+			continue
+		}
+		value, exists := pod.Labels[selector.Key]
+		switch {
+		case !exists:
+			result.LabelsMatched = false
+			result.Reasons = append(result.Reasons, fmt.Sprintf("label %q is missing", selector.Key))
+		case selector.Value != "" && value != selector.Value:
+			result.LabelsMatched = false
+			result.Reasons = append(result.Reasons, fmt.Sprintf("label %q is %q, want %q", selector.Key, value, selector.Value))
+		}
+	}
+
+	if s.CommandRegex == "" {
+		result.CommandMatched = true
+	} else if cmdRegex, err := regexp.Compile(s.CommandRegex); err != nil {
+		result.Reasons = append(result.Reasons, fmt.Sprintf("invalid command regex %q: %v", s.CommandRegex, err))
+	} else {
+		for _, container := range pod.Containers {
+			if container.MatchCommand(cmdRegex, s.CommandMatchTargets) {
+				result.MatchedContainers = append(result.MatchedContainers, container.Name)
+			}
+		}
+		result.CommandMatched = len(result.MatchedContainers) > 0
+		if !result.CommandMatched {
+			targets := s.CommandMatchTargets
+			if len(targets) == 0 {
+				targets = DefaultCommandMatchTargets
+			}
+			result.Reasons = append(result.Reasons, fmt.Sprintf("command regex %q matches no container on %v", s.CommandRegex, targets))
+		}
+	}
+
+	result.Matched = result.NamespaceMatched && result.LabelsMatched && result.CommandMatched
+	return result
+}
+
+// ProcessExplanation reports the decision maker's outcome of an intent for a single process
+type ProcessExplanation struct {
+	PID         int
+	Command     string
+	ContainerID string
+	// Outcome is one of matched, skippedPause, regexMismatch or noThreadMatched
+	Outcome string
+	TIDs    []int
+}
+
+// DecisionMakerIntentExplanation reports how the decision maker resolved one intent of a pod
+type DecisionMakerIntentExplanation struct {
+	CommandRegex  string
+	ThreadRegex   string
+	Priority      int
+	ExecutionTime int64
+	Error         string
+	Processes     []ProcessExplanation
+}
+
+// DecisionMakerPodExplanation is the decision maker's view of a pod's intents
+type DecisionMakerPodExplanation struct {
+	PodFound    bool
+	ProcessPIDs []int
+	Intents     []DecisionMakerIntentExplanation
+}
+
+// DecisionMakerExplanation identifies the decision maker responsible for a pod and its outcome
+type DecisionMakerExplanation struct {
+	NodeID string
+	Host   string
+	State  NodeState
+	// Error is set when the decision maker could not be found or queried
+	Error  string
+	Result *DecisionMakerPodExplanation
+}
+
+// PodExplanation combines everything that decides whether a pod is scheduled
+type PodExplanation struct {
+	Pod           *Pod
+	Strategies    []StrategyMatchExplanation
+	Intents       []*ScheduleIntent
+	DecisionMaker DecisionMakerExplanation
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
@@ -9,7 +10,11 @@ import (
 type QueryUserOptions struct {
 	IDs       []bson.ObjectID
 	UserNames []string
-	Result    []*User
+	// ExternalIDs matches the subjects of users provisioned by single sign-on
+	ExternalIDs []string
+	// Roles matches users holding any of the role names
+	Roles  []string
+	Result []*User
 }
 
 type QueryRoleOptions struct {
@@ -29,14 +34,67 @@ type QueryAuditLogOptions struct {
 	TimestampGTE int64
 	TimestampLTE int64
 	UserIDs      []bson.ObjectID
+	Actions      []string
+	Limit        int64 // zero for no limit
 	Result       []*AuditLog
 }
 
+type QueryMetricSampleOptions struct {
+	NodeIDs []string
+	From    time.Time // inclusive, zero for no lower bound
+	To      time.Time // inclusive, zero for no upper bound
+	Limit   int64     // keeps the most recent samples, zero for no limit
+	Result  []*NodeMetricSample
+}
+
+type QueryAlertRuleOptions struct {
+	IDs    []bson.ObjectID
+	Result []*AlertRule
+}
+
+type QueryAlertOptions struct {
+	RuleIDs []bson.ObjectID
+	NodeIDs []string
+	States  []AlertState
+	Limit   int64 // zero for no limit
+	Result  []*Alert
+}
+
+type QueryWebhookSubscriptionOptions struct {
+	IDs    []bson.ObjectID
+	Result []*WebhookSubscription
+}
+
+type QueryServiceAccountOptions struct {
+	IDs   []bson.ObjectID
+	Names []string
+	// Roles matches service accounts holding any of the role names
+	Roles  []string
+	Result []*ServiceAccount
+}
+
+type QueryTeamOptions struct {
+	IDs       []bson.ObjectID
+	Names     []string
+	MemberIDs []bson.ObjectID
+	Result    []*Team
+}
+
+type QueryAPIKeyOptions struct {
+	IDs               []bson.ObjectID
+	KeyIDs            []string
+	ServiceAccountIDs []string
+	Result            []*APIKey
+}
+
 type QueryStrategyOptions struct {
 	IDs           []bson.ObjectID
 	K8SNamespaces []string
 	Result        []*ScheduleStrategy
 	CreatorIDs    []bson.ObjectID
+	// TeamIDs matches strategies owned by one of the teams, together with CreatorIDs a strategy
+	// matching either of them is returned
+	TeamIDs []bson.ObjectID
 }
 
 type QueryIntentOptions struct {
@@ -47,20 +105,76 @@ type QueryIntentOptions struct {
 	PodIDs        []string
 	Result        []*ScheduleIntent
 	CreatorIDs    []bson.ObjectID
+	// TeamIDs matches intents of strategies owned by one of the teams, together with CreatorIDs an
+	// intent matching either of them is returned
+	TeamIDs []bson.ObjectID
 }
 
 type Repository interface {
 	CreateUser(ctx context.Context, user *User) error
 	UpdateUser(ctx context.Context, user *User) error
 	QueryUsers(ctx context.Context, opt *QueryUserOptions) error
+	// RecordLoginSuccess resets the failed login count of the user and appends attempt to its history
+	RecordLoginSuccess(ctx context.Context, id bson.ObjectID, attempt LoginAttempt) error
+	// RecordLoginFailure increments the failed login count of the user, appends attempt to its history
+	// and applies lockout, the updated user is returned
+	RecordLoginFailure(ctx context.Context, id bson.ObjectID, attempt LoginAttempt, lockout LoginLockout) (*User, error)
+	// UseTwoFactorStep marks the TOTP step of an accepted code used, it reports false when the step
+	// or a later one was used before
+	UseTwoFactorStep(ctx context.Context, id bson.ObjectID, step int64) (bool, error)
+	// UseRecoveryCode consumes the recovery code with codeHash, it reports false when there's no such code
+	UseRecoveryCode(ctx context.Context, id bson.ObjectID, codeHash string) (bool, error)
 	CreateRole(ctx context.Context, role *Role) error
 	UpdateRole(ctx context.Context, role *Role) error
+	DeleteRole(ctx context.Context, roleID bson.ObjectID) error
 	QueryRoles(ctx context.Context, opt *QueryRoleOptions) error
 	CreatePermission(ctx context.Context, permission *Permission) error
 	UpdatePermission(ctx context.Context, permission *Permission) error
 	QueryPermissions(ctx context.Context, opt *QueryPermissionOptions) error
 	CreateAuditLog(ctx context.Context, log *AuditLog) error
 	QueryAuditLogs(ctx context.Context, opt *QueryAuditLogOptions) error
+	InsertMetricSamples(ctx context.Context, samples []*NodeMetricSample) error
+	QueryMetricSamples(ctx context.Context, opt *QueryMetricSampleOptions) error
+	SetMetricSampleRetention(ctx context.Context, retention time.Duration) error
+	CreateAlertRule(ctx context.Context, rule *AlertRule) error
+	UpdateAlertRule(ctx context.Context, rule *AlertRule) error
+	DeleteAlertRule(ctx context.Context, ruleID bson.ObjectID) error
+	QueryAlertRules(ctx context.Context, opt *QueryAlertRuleOptions) error
+	SaveAlert(ctx context.Context, alert *Alert) error
+	QueryAlerts(ctx context.Context, opt *QueryAlertOptions) error
+	ListAlertNodeStates(ctx context.Context) ([]*AlertNodeState, error)
+	SaveAlertNodeState(ctx context.Context, state *AlertNodeState) error
+	DeleteAlertNodeStates(ctx context.Context, nodeIDs []string) error
+	// AcquireLease takes or renews the lease called name for holder until ttl from now and reports
+	// false when another holder has a lease that did not expire yet
+	AcquireLease(ctx context.Context, name string, holder string, ttl time.Duration) (bool, error)
+	CreateWebhookSubscription(ctx context.Context, sub *WebhookSubscription) error
+	UpdateWebhookSubscription(ctx context.Context, sub *WebhookSubscription) error
+	DeleteWebhookSubscription(ctx context.Context, subID bson.ObjectID) error
+	QueryWebhookSubscriptions(ctx context.Context, opt *QueryWebhookSubscriptionOptions) error
+	CreateRefreshToken(ctx context.Context, token *RefreshToken) error
+	// UseRefreshToken marks the token with tokenHash revoked and returns it as it was before
+	UseRefreshToken(ctx context.Context, tokenHash string, usedAt int64) (*RefreshToken, error)
+	RevokeRefreshTokens(ctx context.Context, opt RevokeRefreshTokensOptions, revokedAt int64) error
+	RevokeAccessToken(ctx context.Context, token *RevokedToken) error
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)
+	CreateServiceAccount(ctx context.Context, account *ServiceAccount) error
+	UpdateServiceAccount(ctx context.Context, account *ServiceAccount) error
+	DeleteServiceAccount(ctx context.Context, accountID bson.ObjectID) error
+	QueryServiceAccounts(ctx context.Context, opt *QueryServiceAccountOptions) error
+	CreateAPIKey(ctx context.Context, key *APIKey) error
+	RevokeAPIKey(ctx context.Context, keyID bson.ObjectID, revokedAt int64) error
+	TouchAPIKey(ctx context.Context, keyID bson.ObjectID, usedAt int64) error
+	DeleteAPIKeysByServiceAccountID(ctx context.Context, accountID string) error
+	QueryAPIKeys(ctx context.Context, opt *QueryAPIKeyOptions) error
+	// IncrementCacheVersion bumps the shared version of the cache called name and returns it, other
+	// manager replicas flush their copy of the cache when they see it change
+	IncrementCacheVersion(ctx context.Context, name string) (int64, error)
+	GetCacheVersion(ctx context.Context, name string) (int64, error)
+	CreateTeam(ctx context.Context, team *Team) error
+	UpdateTeam(ctx context.Context, team *Team) error
+	DeleteTeam(ctx context.Context, teamID bson.ObjectID) error
+	QueryTeams(ctx context.Context, opt *QueryTeamOptions) error
 
 	InsertStrategyAndIntents(ctx context.Context, strategy *ScheduleStrategy, intents []*ScheduleIntent) error
 	InsertIntents(ctx context.Context, intents []*ScheduleIntent) error
@@ -71,16 +185,38 @@ type Repository interface {
 	DeleteStrategy(ctx context.Context, strategyID bson.ObjectID) error
 	DeleteIntents(ctx context.Context, intentIDs []bson.ObjectID) error
 	DeleteIntentsByStrategyID(ctx context.Context, strategyID bson.ObjectID) error
+	// UpdateIntentsOwner moves the intents of the strategy to the creator and team of the strategy
+	UpdateIntentsOwner(ctx context.Context, strategy *ScheduleStrategy) error
 }
 
 type Service interface {
 	CreateNewUser(ctx context.Context, operator *Claims, username, password string) error
 	CreateAdminUserIfNotExists(ctx context.Context, username, password string) error
-	Login(ctx context.Context, email, password string) (token string, err error)
+	Login(ctx context.Context, email, password, clientIP string) (*LoginResult, error)
+	VerifyTwoFactorLogin(ctx context.Context, twoFactorToken, code, clientIP string) (*TokenPair, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*TokenPair, error)
+	Logout(ctx context.Context, claims *Claims, refreshToken string) error
+	RevokeUserSessions(ctx context.Context, operator *Claims, id string) error
+	UnlockUser(ctx context.Context, operator *Claims, id string) error
+	BeginTwoFactorEnrollment(ctx context.Context, user *Claims) (*TwoFactorEnrollment, error)
+	// ConfirmTwoFactorEnrollment enables two-factor authentication and returns the recovery codes
+	ConfirmTwoFactorEnrollment(ctx context.Context, user *Claims, code string) ([]string, error)
+	DisableTwoFactor(ctx context.Context, user *Claims, password, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, user *Claims, code string) ([]string, error)
+	ResetTwoFactor(ctx context.Context, operator *Claims, id string) error
+	BeginOIDCLogin(ctx context.Context) (*OIDCAuthRequest, error)
+	CompleteOIDCLogin(ctx context.Context, callback OIDCCallback) (*OIDCLogin, error)
 	ChangePassword(ctx context.Context, user *Claims, oldPassword, newPassword string) error
 	ResetPassword(ctx context.Context, operator *Claims, id, newPassword string) error
 	UpdateUserPermissions(ctx context.Context, operator *Claims, id string, opt UpdateUserPermissionsOptions) error
 	VerifyJWTToken(ctx context.Context, tokenString string, permissionKey PermissionKey) (Claims, RolePolicy, error)
+	VerifyAPIKey(ctx context.Context, key string, permissionKey PermissionKey) (Claims, RolePolicy, error)
+	// SyncAuthCache flushes the authorization cache when another manager replica changed a user or role
+	SyncAuthCache(ctx context.Context) error
+	// JSONWebKeySet returns the public keys of the JWT signing keys, ReloadSigningKeys re-reads the
+	// key directory so that keys rotate without a restart
+	JSONWebKeySet(ctx context.Context) (*JSONWebKeySet, error)
+	ReloadSigningKeys(ctx context.Context) error
 	QueryUsers(ctx context.Context, opt *QueryUserOptions) error
 
 	CreateRole(ctx context.Context, operator *Claims, role *Role) error
@@ -95,15 +231,46 @@ type Service interface {
 	UpdateScheduleStrategy(ctx context.Context, operator *Claims, strategyID string, strategy *ScheduleStrategy) error
 	DeleteScheduleStrategy(ctx context.Context, operator *Claims, strategyID string) error
 	DeleteScheduleIntents(ctx context.Context, operator *Claims, intentIDs []string) error
+	TransferScheduleStrategy(ctx context.Context, operator *Claims, strategyID string, opt TransferStrategyOptions) error
+	CreateTeam(ctx context.Context, operator *Claims, team *Team) error
+	UpdateTeam(ctx context.Context, operator *Claims, teamID string, opt UpdateTeamOptions) error
+	DeleteTeam(ctx context.Context, operator *Claims, teamID string) error
+	QueryTeams(ctx context.Context, opt *QueryTeamOptions) error
 	GetPodPIDMapping(ctx context.Context, nodeID string) (*PodPIDMappingResponse, error)
 	ListNodes(ctx context.Context) ([]*Node, error)
+	ExplainPod(ctx context.Context, namespace, name string) (*PodExplanation, error)
+	GetNodeMetricHistory(ctx context.Context, nodeID string, since time.Time, step time.Duration) (*NodeMetricHistory, error)
+	GetClusterMetrics(ctx context.Context, staleAfter time.Duration) (*ClusterMetrics, error)
+	CollectMetricSamples(ctx context.Context) error
+	QueryMetricSamples(ctx context.Context, opt *QueryMetricSampleOptions) error
+	SetMetricSampleRetention(ctx context.Context, retention time.Duration) error
+	CreateAlertRule(ctx context.Context, operator *Claims, rule *AlertRule) error
+	UpdateAlertRule(ctx context.Context, operator *Claims, ruleID string, rule *AlertRule) error
+	DeleteAlertRule(ctx context.Context, operator *Claims, ruleID string) error
+	ListAlertRules(ctx context.Context, opt *QueryAlertRuleOptions) error
+	ListAlerts(ctx context.Context, opt *QueryAlertOptions) error
+	EvaluateAlertRules(ctx context.Context) error
+	CreateAuditLog(ctx context.Context, log *AuditLog) error
+	ListAuditLogs(ctx context.Context, opt *QueryAuditLogOptions) error
+	CreateWebhookSubscription(ctx context.Context, operator *Claims, sub *WebhookSubscription) error
+	UpdateWebhookSubscription(ctx context.Context, operator *Claims, subID string, sub *WebhookSubscription) error
+	DeleteWebhookSubscription(ctx context.Context, operator *Claims, subID string) error
+	ListWebhookSubscriptions(ctx context.Context, opt *QueryWebhookSubscriptionOptions) error
+	CreateServiceAccount(ctx context.Context, operator *Claims, account *ServiceAccount) error
+	UpdateServiceAccount(ctx context.Context, operator *Claims, accountID string, opt UpdateServiceAccountOptions) error
+	DeleteServiceAccount(ctx context.Context, operator *Claims, accountID string) error
+	QueryServiceAccounts(ctx context.Context, opt *QueryServiceAccountOptions) error
+	CreateAPIKey(ctx context.Context, operator *Claims, key *APIKey) (plainKey string, err error)
+	RevokeAPIKey(ctx context.Context, operator *Claims, keyID string) error
+	QueryAPIKeys(ctx context.Context, opt *QueryAPIKeyOptions) error
 	ReconcileIntents(ctx context.Context) error
 }
 
 type QueryPodsOptions struct {
-	K8SNamespace   []string
-	LabelSelectors []LabelSelector
-	CommandRegex   string
+	K8SNamespace        []string
+	LabelSelectors      []LabelSelector
+	CommandRegex        string
+	CommandMatchTargets []CommandMatchTarget
 }
 
 type QueryDecisionMakerPodsOptions struct {
@@ -114,6 +281,8 @@ type QueryDecisionMakerPodsOptions struct {
 
 type K8SAdapter interface {
 	QueryPods(ctx context.Context, opt *QueryPodsOptions) ([]*Pod, error)
+	// GetPod returns ErrNotFound when there is no pod called name in namespace
+	GetPod(ctx context.Context, namespace, name string) (*Pod, error)
 	QueryDecisionMakerPods(ctx context.Context, opt *QueryDecisionMakerPodsOptions) ([]*DecisionMakerPod, error)
 	ListNodes(ctx context.Context) ([]*Node, error)
 }
@@ -128,4 +297,7 @@ type DecisionMakerAdapter interface {
 	GetIntentMerkleRoot(ctx context.Context, decisionMaker *DecisionMakerPod) (string, error)
 	DeleteSchedulingIntents(ctx context.Context, decisionMaker *DecisionMakerPod, req *DeleteIntentsRequest) error
 	GetPodPIDMapping(ctx context.Context, decisionMaker *DecisionMakerPod) (*PodPIDMappingResponse, error)
+	ExplainPod(ctx context.Context, decisionMaker *DecisionMakerPod, podID string) (*DecisionMakerPodExplanation, error)
+	GetMetricHistory(ctx context.Context, decisionMaker *DecisionMakerPod, since time.Time, step time.Duration) ([]MetricSample, error)
+	GetMetrics(ctx context.Context, decisionMaker *DecisionMakerPod) (*MetricSample, error)
 }
@@ -1,5 +1,12 @@
 package domain
 
+import (
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
 // Node represents a Kubernetes node
 type Node struct {
 	Name   string            `json:"name"`
@@ -38,16 +45,82 @@ func (p *Pod) LabelsToSelectors() []LabelSelector {
 	return selectors
 }
 
+// ContainerNames returns the pod's containers keyed by their runtime container ID
+func (p *Pod) ContainerNames() map[string]string {
+	if len(p.Containers) == 0 {
+		return nil
+	}
+	names := make(map[string]string, len(p.Containers))
+	for _, container := range p.Containers {
+		id := TrimContainerIDScheme(container.ContainerID)
+		if id == "" {
+			continue
+		}
+		names[id] = container.Name
+	}
+	return names
+}
+
+// TrimContainerIDScheme strips the runtime scheme from a container status ID (e.g. containerd://<id> -> <id>)
+func TrimContainerIDScheme(containerID string) string {
+	if _, id, found := strings.Cut(containerID, "://"); found {
+		return id
+	}
+	return containerID
+}
+
 type Container struct {
 	ContainerID string
 	Name        string
-	Command     []string
+	// Command is the container command, empty when the image entrypoint is used
+	Command []string
+	Args    []string
+}
+
+// taskCommLen is the length /proc/<pid>/comm is truncated to (TASK_COMM_LEN - 1)
+const taskCommLen = 15
+
+// MatchCommand reports whether cmdRegex matches any of the targets, approximating the
+// process attributes the decision maker sees from the container spec: exe is the first
+// command element, comm its truncated basename and cmdline the joined command and args.
+// Exe and comm are unknown for containers running the image entrypoint.
+// Empty targets means DefaultCommandMatchTargets.
+func (c *Container) MatchCommand(cmdRegex *regexp.Regexp, targets []CommandMatchTarget) bool {
+	if len(targets) == 0 {
+		targets = DefaultCommandMatchTargets
+	}
+	for _, target := range targets {
+		var value string
+		switch target {
+		case CommandMatchContainerName:
+			value = c.Name
+		case CommandMatchCmdline:
+			value = strings.Join(slices.Concat(c.Command, c.Args), " ")
+		case CommandMatchExe:
+			if len(c.Command) > 0 {
+				value = c.Command[0]
+			}
+		case CommandMatchComm:
+			if len(c.Command) > 0 {
+				value = path.Base(c.Command[0])
+				if len(value) > taskCommLen {
+					value = value[:taskCommLen]
+				}
+			}
+		}
+		if value != "" && cmdRegex.MatchString(value) {
+			return true
+		}
+	}
+	return false
 }
 
 // PodProcess represents a process information within a pod
 type PodProcess struct {
 	PID         int    `json:"pid"`
 	Command     string `json:"command"`
+	Cmdline     string `json:"cmdline,omitempty"`
+	Exe         string `json:"exe,omitempty"`
 	PPID        int    `json:"ppid,omitempty"`
 	ContainerID string `json:"container_id,omitempty"`
 }
@@ -0,0 +1,90 @@
+package domain
+
+import "time"
+
+// MetricSample is a metric set a decision maker received from its scheduler
+type MetricSample struct {
+	Timestamp          time.Time `bson:"timestamp"`
+	UserSchedLastRunAt uint64    `bson:"userSchedLastRunAt"`
+	NrQueued           uint64    `bson:"nrQueued"`
+	NrScheduled        uint64    `bson:"nrScheduled"`
+	NrRunning          uint64    `bson:"nrRunning"`
+	NrOnlineCPUs       uint64    `bson:"nrOnlineCPUs"`
+	NrUserDispatches   uint64    `bson:"nrUserDispatches"`
+	NrKernelDispatches uint64    `bson:"nrKernelDispatches"`
+	NrCancelDispatches uint64    `bson:"nrCancelDispatches"`
+	NrBounceDispatches uint64    `bson:"nrBounceDispatches"`
+	NrFailedDispatches uint64    `bson:"nrFailedDispatches"`
+	NrSchedCongested   uint64    `bson:"nrSchedCongested"`
+}
+
+// NodeMetricSample is a MetricSample persisted in the metric time series, NodeID is the series key
+type NodeMetricSample struct {
+	NodeID       string `bson:"nodeID"`
+	MetricSample `bson:",inline"`
+	// StrategyIDs are the hex IDs of the strategies with intents sent to the node when the sample was collected
+	StrategyIDs []string `bson:"strategyIDs,omitempty"`
+}
+
+// NodeMetricHistory is the metric history retained by the decision maker of a node
+type NodeMetricHistory struct {
+	NodeID  string
+	Samples []MetricSample
+}
+
+// Outlier reasons reported for a node in ClusterMetrics
+const (
+	OutlierUserSchedStale           = "userSchedStale"           // the user scheduler has not run or reported within the staleness window
+	OutlierFailedDispatchesClimbing = "failedDispatchesClimbing" // nr_failed_dispatches increased since the previous collection
+	OutlierNoMetrics                = "noMetrics"                // the decision maker has not received metrics from its scheduler
+	OutlierUnreachable              = "unreachable"              // the decision maker is missing, not online or did not respond
+)
+
+// NodeMetrics are the current scheduler metrics of a node joined with the node and its decision maker
+type NodeMetrics struct {
+	Node               *Node
+	DecisionMakerHost  string
+	DecisionMakerState NodeState
+	Error              string
+	Sample             *MetricSample
+	// FailedDispatchesDelta is the increase of NrFailedDispatches since the previous collection
+	FailedDispatchesDelta uint64
+	Outliers              []string
+}
+
+// MetricTotals are the scheduler metrics summed over the nodes that reported them
+type MetricTotals struct {
+	Nodes              int
+	NrQueued           uint64
+	NrScheduled        uint64
+	NrRunning          uint64
+	NrOnlineCPUs       uint64
+	NrUserDispatches   uint64
+	NrKernelDispatches uint64
+	NrCancelDispatches uint64
+	NrBounceDispatches uint64
+	NrFailedDispatches uint64
+	NrSchedCongested   uint64
+}
+
+// Add accumulates sample into the totals
+func (t *MetricTotals) Add(sample *MetricSample) {
+	t.Nodes++
+	t.NrQueued += sample.NrQueued
+	t.NrScheduled += sample.NrScheduled
+	t.NrRunning += sample.NrRunning
+	t.NrOnlineCPUs += sample.NrOnlineCPUs
+	t.NrUserDispatches += sample.NrUserDispatches
+	t.NrKernelDispatches += sample.NrKernelDispatches
+	t.NrCancelDispatches += sample.NrCancelDispatches
+	t.NrBounceDispatches += sample.NrBounceDispatches
+	t.NrFailedDispatches += sample.NrFailedDispatches
+	t.NrSchedCongested += sample.NrSchedCongested
+}
+
+// ClusterMetrics is a fleet view of the scheduler metrics of every node
+type ClusterMetrics struct {
+	CollectedAt time.Time
+	Nodes       []NodeMetrics
+	Totals      MetricTotals
+}
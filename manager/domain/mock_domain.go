@@ -6,6 +6,7 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	mock "github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -38,6 +39,84 @@ func (_m *MockRepository) EXPECT() *MockRepository_Expecter {
 	return &MockRepository_Expecter{mock: &_m.Mock}
 }
 
+// AcquireLease provides a mock function for the type MockRepository
+func (_mock *MockRepository) AcquireLease(ctx context.Context, name string, holder string, ttl time.Duration) (bool, error) {
+	ret := _mock.Called(ctx, name, holder, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AcquireLease")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) (bool, error)); ok {
+		return returnFunc(ctx, name, holder, ttl)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) bool); ok {
+		r0 = returnFunc(ctx, name, holder, ttl)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, time.Duration) error); ok {
+		r1 = returnFunc(ctx, name, holder, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_AcquireLease_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AcquireLease'
+type MockRepository_AcquireLease_Call struct {
+	*mock.Call
+}
+
+// AcquireLease is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - holder string
+//   - ttl time.Duration
+func (_e *MockRepository_Expecter) AcquireLease(ctx interface{}, name interface{}, holder interface{}, ttl interface{}) *MockRepository_AcquireLease_Call {
+	return &MockRepository_AcquireLease_Call{Call: _e.mock.On("AcquireLease", ctx, name, holder, ttl)}
+}
+
+func (_c *MockRepository_AcquireLease_Call) Run(run func(ctx context.Context, name string, holder string, ttl time.Duration)) *MockRepository_AcquireLease_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 time.Duration
+		if args[3] != nil {
+			arg3 = args[3].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_AcquireLease_Call) Return(b bool, err error) *MockRepository_AcquireLease_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockRepository_AcquireLease_Call) RunAndReturn(run func(ctx context.Context, name string, holder string, ttl time.Duration) (bool, error)) *MockRepository_AcquireLease_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // BatchUpdateIntentsState provides a mock function for the type MockRepository
 func (_mock *MockRepository) BatchUpdateIntentsState(ctx context.Context, intentIDs []bson.ObjectID, newState IntentState) error {
 	ret := _mock.Called(ctx, intentIDs, newState)
@@ -101,44 +180,44 @@ func (_c *MockRepository_BatchUpdateIntentsState_Call) RunAndReturn(run func(ctx
 	return _c
 }
 
-// CreateAuditLog provides a mock function for the type MockRepository
-func (_mock *MockRepository) CreateAuditLog(ctx context.Context, log *AuditLog) error {
-	ret := _mock.Called(ctx, log)
+// CreateAPIKey provides a mock function for the type MockRepository
+func (_mock *MockRepository) CreateAPIKey(ctx context.Context, key *APIKey) error {
+	ret := _mock.Called(ctx, key)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateAuditLog")
+		panic("no return value specified for CreateAPIKey")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *AuditLog) error); ok {
-		r0 = returnFunc(ctx, log)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *APIKey) error); ok {
+		r0 = returnFunc(ctx, key)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_CreateAuditLog_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAuditLog'
-type MockRepository_CreateAuditLog_Call struct {
+// MockRepository_CreateAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAPIKey'
+type MockRepository_CreateAPIKey_Call struct {
 	*mock.Call
 }
 
-// CreateAuditLog is a helper method to define mock.On call
+// CreateAPIKey is a helper method to define mock.On call
 //   - ctx context.Context
-//   - log *AuditLog
-func (_e *MockRepository_Expecter) CreateAuditLog(ctx interface{}, log interface{}) *MockRepository_CreateAuditLog_Call {
-	return &MockRepository_CreateAuditLog_Call{Call: _e.mock.On("CreateAuditLog", ctx, log)}
+//   - key *APIKey
+func (_e *MockRepository_Expecter) CreateAPIKey(ctx interface{}, key interface{}) *MockRepository_CreateAPIKey_Call {
+	return &MockRepository_CreateAPIKey_Call{Call: _e.mock.On("CreateAPIKey", ctx, key)}
 }
 
-func (_c *MockRepository_CreateAuditLog_Call) Run(run func(ctx context.Context, log *AuditLog)) *MockRepository_CreateAuditLog_Call {
+func (_c *MockRepository_CreateAPIKey_Call) Run(run func(ctx context.Context, key *APIKey)) *MockRepository_CreateAPIKey_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *AuditLog
+		var arg1 *APIKey
 		if args[1] != nil {
-			arg1 = args[1].(*AuditLog)
+			arg1 = args[1].(*APIKey)
 		}
 		run(
 			arg0,
@@ -148,54 +227,54 @@ func (_c *MockRepository_CreateAuditLog_Call) Run(run func(ctx context.Context,
 	return _c
 }
 
-func (_c *MockRepository_CreateAuditLog_Call) Return(err error) *MockRepository_CreateAuditLog_Call {
+func (_c *MockRepository_CreateAPIKey_Call) Return(err error) *MockRepository_CreateAPIKey_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_CreateAuditLog_Call) RunAndReturn(run func(ctx context.Context, log *AuditLog) error) *MockRepository_CreateAuditLog_Call {
+func (_c *MockRepository_CreateAPIKey_Call) RunAndReturn(run func(ctx context.Context, key *APIKey) error) *MockRepository_CreateAPIKey_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreatePermission provides a mock function for the type MockRepository
-func (_mock *MockRepository) CreatePermission(ctx context.Context, permission *Permission) error {
-	ret := _mock.Called(ctx, permission)
+// CreateAlertRule provides a mock function for the type MockRepository
+func (_mock *MockRepository) CreateAlertRule(ctx context.Context, rule *AlertRule) error {
+	ret := _mock.Called(ctx, rule)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreatePermission")
+		panic("no return value specified for CreateAlertRule")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *Permission) error); ok {
-		r0 = returnFunc(ctx, permission)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *AlertRule) error); ok {
+		r0 = returnFunc(ctx, rule)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_CreatePermission_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreatePermission'
-type MockRepository_CreatePermission_Call struct {
+// MockRepository_CreateAlertRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAlertRule'
+type MockRepository_CreateAlertRule_Call struct {
 	*mock.Call
 }
 
-// CreatePermission is a helper method to define mock.On call
+// CreateAlertRule is a helper method to define mock.On call
 //   - ctx context.Context
-//   - permission *Permission
-func (_e *MockRepository_Expecter) CreatePermission(ctx interface{}, permission interface{}) *MockRepository_CreatePermission_Call {
-	return &MockRepository_CreatePermission_Call{Call: _e.mock.On("CreatePermission", ctx, permission)}
+//   - rule *AlertRule
+func (_e *MockRepository_Expecter) CreateAlertRule(ctx interface{}, rule interface{}) *MockRepository_CreateAlertRule_Call {
+	return &MockRepository_CreateAlertRule_Call{Call: _e.mock.On("CreateAlertRule", ctx, rule)}
 }
 
-func (_c *MockRepository_CreatePermission_Call) Run(run func(ctx context.Context, permission *Permission)) *MockRepository_CreatePermission_Call {
+func (_c *MockRepository_CreateAlertRule_Call) Run(run func(ctx context.Context, rule *AlertRule)) *MockRepository_CreateAlertRule_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *Permission
+		var arg1 *AlertRule
 		if args[1] != nil {
-			arg1 = args[1].(*Permission)
+			arg1 = args[1].(*AlertRule)
 		}
 		run(
 			arg0,
@@ -205,54 +284,54 @@ func (_c *MockRepository_CreatePermission_Call) Run(run func(ctx context.Context
 	return _c
 }
 
-func (_c *MockRepository_CreatePermission_Call) Return(err error) *MockRepository_CreatePermission_Call {
+func (_c *MockRepository_CreateAlertRule_Call) Return(err error) *MockRepository_CreateAlertRule_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_CreatePermission_Call) RunAndReturn(run func(ctx context.Context, permission *Permission) error) *MockRepository_CreatePermission_Call {
+func (_c *MockRepository_CreateAlertRule_Call) RunAndReturn(run func(ctx context.Context, rule *AlertRule) error) *MockRepository_CreateAlertRule_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreateRole provides a mock function for the type MockRepository
-func (_mock *MockRepository) CreateRole(ctx context.Context, role *Role) error {
-	ret := _mock.Called(ctx, role)
+// CreateAuditLog provides a mock function for the type MockRepository
+func (_mock *MockRepository) CreateAuditLog(ctx context.Context, log *AuditLog) error {
+	ret := _mock.Called(ctx, log)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateRole")
+		panic("no return value specified for CreateAuditLog")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *Role) error); ok {
-		r0 = returnFunc(ctx, role)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *AuditLog) error); ok {
+		r0 = returnFunc(ctx, log)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_CreateRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRole'
-type MockRepository_CreateRole_Call struct {
+// MockRepository_CreateAuditLog_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAuditLog'
+type MockRepository_CreateAuditLog_Call struct {
 	*mock.Call
 }
 
-// CreateRole is a helper method to define mock.On call
+// CreateAuditLog is a helper method to define mock.On call
 //   - ctx context.Context
-//   - role *Role
-func (_e *MockRepository_Expecter) CreateRole(ctx interface{}, role interface{}) *MockRepository_CreateRole_Call {
-	return &MockRepository_CreateRole_Call{Call: _e.mock.On("CreateRole", ctx, role)}
+//   - log *AuditLog
+func (_e *MockRepository_Expecter) CreateAuditLog(ctx interface{}, log interface{}) *MockRepository_CreateAuditLog_Call {
+	return &MockRepository_CreateAuditLog_Call{Call: _e.mock.On("CreateAuditLog", ctx, log)}
 }
 
-func (_c *MockRepository_CreateRole_Call) Run(run func(ctx context.Context, role *Role)) *MockRepository_CreateRole_Call {
+func (_c *MockRepository_CreateAuditLog_Call) Run(run func(ctx context.Context, log *AuditLog)) *MockRepository_CreateAuditLog_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *Role
+		var arg1 *AuditLog
 		if args[1] != nil {
-			arg1 = args[1].(*Role)
+			arg1 = args[1].(*AuditLog)
 		}
 		run(
 			arg0,
@@ -262,54 +341,54 @@ func (_c *MockRepository_CreateRole_Call) Run(run func(ctx context.Context, role
 	return _c
 }
 
-func (_c *MockRepository_CreateRole_Call) Return(err error) *MockRepository_CreateRole_Call {
+func (_c *MockRepository_CreateAuditLog_Call) Return(err error) *MockRepository_CreateAuditLog_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_CreateRole_Call) RunAndReturn(run func(ctx context.Context, role *Role) error) *MockRepository_CreateRole_Call {
+func (_c *MockRepository_CreateAuditLog_Call) RunAndReturn(run func(ctx context.Context, log *AuditLog) error) *MockRepository_CreateAuditLog_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreateUser provides a mock function for the type MockRepository
-func (_mock *MockRepository) CreateUser(ctx context.Context, user *User) error {
-	ret := _mock.Called(ctx, user)
+// CreatePermission provides a mock function for the type MockRepository
+func (_mock *MockRepository) CreatePermission(ctx context.Context, permission *Permission) error {
+	ret := _mock.Called(ctx, permission)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateUser")
+		panic("no return value specified for CreatePermission")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *User) error); ok {
-		r0 = returnFunc(ctx, user)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Permission) error); ok {
+		r0 = returnFunc(ctx, permission)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_CreateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateUser'
-type MockRepository_CreateUser_Call struct {
+// MockRepository_CreatePermission_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreatePermission'
+type MockRepository_CreatePermission_Call struct {
 	*mock.Call
 }
 
-// CreateUser is a helper method to define mock.On call
+// CreatePermission is a helper method to define mock.On call
 //   - ctx context.Context
-//   - user *User
-func (_e *MockRepository_Expecter) CreateUser(ctx interface{}, user interface{}) *MockRepository_CreateUser_Call {
-	return &MockRepository_CreateUser_Call{Call: _e.mock.On("CreateUser", ctx, user)}
+//   - permission *Permission
+func (_e *MockRepository_Expecter) CreatePermission(ctx interface{}, permission interface{}) *MockRepository_CreatePermission_Call {
+	return &MockRepository_CreatePermission_Call{Call: _e.mock.On("CreatePermission", ctx, permission)}
 }
 
-func (_c *MockRepository_CreateUser_Call) Run(run func(ctx context.Context, user *User)) *MockRepository_CreateUser_Call {
+func (_c *MockRepository_CreatePermission_Call) Run(run func(ctx context.Context, permission *Permission)) *MockRepository_CreatePermission_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *User
+		var arg1 *Permission
 		if args[1] != nil {
-			arg1 = args[1].(*User)
+			arg1 = args[1].(*Permission)
 		}
 		run(
 			arg0,
@@ -319,54 +398,54 @@ func (_c *MockRepository_CreateUser_Call) Run(run func(ctx context.Context, user
 	return _c
 }
 
-func (_c *MockRepository_CreateUser_Call) Return(err error) *MockRepository_CreateUser_Call {
+func (_c *MockRepository_CreatePermission_Call) Return(err error) *MockRepository_CreatePermission_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_CreateUser_Call) RunAndReturn(run func(ctx context.Context, user *User) error) *MockRepository_CreateUser_Call {
+func (_c *MockRepository_CreatePermission_Call) RunAndReturn(run func(ctx context.Context, permission *Permission) error) *MockRepository_CreatePermission_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteIntents provides a mock function for the type MockRepository
-func (_mock *MockRepository) DeleteIntents(ctx context.Context, intentIDs []bson.ObjectID) error {
-	ret := _mock.Called(ctx, intentIDs)
+// CreateRefreshToken provides a mock function for the type MockRepository
+func (_mock *MockRepository) CreateRefreshToken(ctx context.Context, token *RefreshToken) error {
+	ret := _mock.Called(ctx, token)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteIntents")
+		panic("no return value specified for CreateRefreshToken")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []bson.ObjectID) error); ok {
-		r0 = returnFunc(ctx, intentIDs)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *RefreshToken) error); ok {
+		r0 = returnFunc(ctx, token)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_DeleteIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteIntents'
-type MockRepository_DeleteIntents_Call struct {
+// MockRepository_CreateRefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRefreshToken'
+type MockRepository_CreateRefreshToken_Call struct {
 	*mock.Call
 }
 
-// DeleteIntents is a helper method to define mock.On call
+// CreateRefreshToken is a helper method to define mock.On call
 //   - ctx context.Context
-//   - intentIDs []bson.ObjectID
-func (_e *MockRepository_Expecter) DeleteIntents(ctx interface{}, intentIDs interface{}) *MockRepository_DeleteIntents_Call {
-	return &MockRepository_DeleteIntents_Call{Call: _e.mock.On("DeleteIntents", ctx, intentIDs)}
+//   - token *RefreshToken
+func (_e *MockRepository_Expecter) CreateRefreshToken(ctx interface{}, token interface{}) *MockRepository_CreateRefreshToken_Call {
+	return &MockRepository_CreateRefreshToken_Call{Call: _e.mock.On("CreateRefreshToken", ctx, token)}
 }
 
-func (_c *MockRepository_DeleteIntents_Call) Run(run func(ctx context.Context, intentIDs []bson.ObjectID)) *MockRepository_DeleteIntents_Call {
+func (_c *MockRepository_CreateRefreshToken_Call) Run(run func(ctx context.Context, token *RefreshToken)) *MockRepository_CreateRefreshToken_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 []bson.ObjectID
+		var arg1 *RefreshToken
 		if args[1] != nil {
-			arg1 = args[1].([]bson.ObjectID)
+			arg1 = args[1].(*RefreshToken)
 		}
 		run(
 			arg0,
@@ -376,54 +455,54 @@ func (_c *MockRepository_DeleteIntents_Call) Run(run func(ctx context.Context, i
 	return _c
 }
 
-func (_c *MockRepository_DeleteIntents_Call) Return(err error) *MockRepository_DeleteIntents_Call {
+func (_c *MockRepository_CreateRefreshToken_Call) Return(err error) *MockRepository_CreateRefreshToken_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_DeleteIntents_Call) RunAndReturn(run func(ctx context.Context, intentIDs []bson.ObjectID) error) *MockRepository_DeleteIntents_Call {
+func (_c *MockRepository_CreateRefreshToken_Call) RunAndReturn(run func(ctx context.Context, token *RefreshToken) error) *MockRepository_CreateRefreshToken_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteIntentsByStrategyID provides a mock function for the type MockRepository
-func (_mock *MockRepository) DeleteIntentsByStrategyID(ctx context.Context, strategyID bson.ObjectID) error {
-	ret := _mock.Called(ctx, strategyID)
+// CreateRole provides a mock function for the type MockRepository
+func (_mock *MockRepository) CreateRole(ctx context.Context, role *Role) error {
+	ret := _mock.Called(ctx, role)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteIntentsByStrategyID")
+		panic("no return value specified for CreateRole")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, bson.ObjectID) error); ok {
-		r0 = returnFunc(ctx, strategyID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Role) error); ok {
+		r0 = returnFunc(ctx, role)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_DeleteIntentsByStrategyID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteIntentsByStrategyID'
-type MockRepository_DeleteIntentsByStrategyID_Call struct {
+// MockRepository_CreateRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRole'
+type MockRepository_CreateRole_Call struct {
 	*mock.Call
 }
 
-// DeleteIntentsByStrategyID is a helper method to define mock.On call
+// CreateRole is a helper method to define mock.On call
 //   - ctx context.Context
-//   - strategyID bson.ObjectID
-func (_e *MockRepository_Expecter) DeleteIntentsByStrategyID(ctx interface{}, strategyID interface{}) *MockRepository_DeleteIntentsByStrategyID_Call {
-	return &MockRepository_DeleteIntentsByStrategyID_Call{Call: _e.mock.On("DeleteIntentsByStrategyID", ctx, strategyID)}
+//   - role *Role
+func (_e *MockRepository_Expecter) CreateRole(ctx interface{}, role interface{}) *MockRepository_CreateRole_Call {
+	return &MockRepository_CreateRole_Call{Call: _e.mock.On("CreateRole", ctx, role)}
 }
 
-func (_c *MockRepository_DeleteIntentsByStrategyID_Call) Run(run func(ctx context.Context, strategyID bson.ObjectID)) *MockRepository_DeleteIntentsByStrategyID_Call {
+func (_c *MockRepository_CreateRole_Call) Run(run func(ctx context.Context, role *Role)) *MockRepository_CreateRole_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 bson.ObjectID
+		var arg1 *Role
 		if args[1] != nil {
-			arg1 = args[1].(bson.ObjectID)
+			arg1 = args[1].(*Role)
 		}
 		run(
 			arg0,
@@ -433,54 +512,54 @@ func (_c *MockRepository_DeleteIntentsByStrategyID_Call) Run(run func(ctx contex
 	return _c
 }
 
-func (_c *MockRepository_DeleteIntentsByStrategyID_Call) Return(err error) *MockRepository_DeleteIntentsByStrategyID_Call {
+func (_c *MockRepository_CreateRole_Call) Return(err error) *MockRepository_CreateRole_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_DeleteIntentsByStrategyID_Call) RunAndReturn(run func(ctx context.Context, strategyID bson.ObjectID) error) *MockRepository_DeleteIntentsByStrategyID_Call {
+func (_c *MockRepository_CreateRole_Call) RunAndReturn(run func(ctx context.Context, role *Role) error) *MockRepository_CreateRole_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteStrategy provides a mock function for the type MockRepository
-func (_mock *MockRepository) DeleteStrategy(ctx context.Context, strategyID bson.ObjectID) error {
-	ret := _mock.Called(ctx, strategyID)
+// CreateServiceAccount provides a mock function for the type MockRepository
+func (_mock *MockRepository) CreateServiceAccount(ctx context.Context, account *ServiceAccount) error {
+	ret := _mock.Called(ctx, account)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteStrategy")
+		panic("no return value specified for CreateServiceAccount")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, bson.ObjectID) error); ok {
-		r0 = returnFunc(ctx, strategyID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *ServiceAccount) error); ok {
+		r0 = returnFunc(ctx, account)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_DeleteStrategy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteStrategy'
-type MockRepository_DeleteStrategy_Call struct {
+// MockRepository_CreateServiceAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateServiceAccount'
+type MockRepository_CreateServiceAccount_Call struct {
 	*mock.Call
 }
 
-// DeleteStrategy is a helper method to define mock.On call
+// CreateServiceAccount is a helper method to define mock.On call
 //   - ctx context.Context
-//   - strategyID bson.ObjectID
-func (_e *MockRepository_Expecter) DeleteStrategy(ctx interface{}, strategyID interface{}) *MockRepository_DeleteStrategy_Call {
-	return &MockRepository_DeleteStrategy_Call{Call: _e.mock.On("DeleteStrategy", ctx, strategyID)}
+//   - account *ServiceAccount
+func (_e *MockRepository_Expecter) CreateServiceAccount(ctx interface{}, account interface{}) *MockRepository_CreateServiceAccount_Call {
+	return &MockRepository_CreateServiceAccount_Call{Call: _e.mock.On("CreateServiceAccount", ctx, account)}
 }
 
-func (_c *MockRepository_DeleteStrategy_Call) Run(run func(ctx context.Context, strategyID bson.ObjectID)) *MockRepository_DeleteStrategy_Call {
+func (_c *MockRepository_CreateServiceAccount_Call) Run(run func(ctx context.Context, account *ServiceAccount)) *MockRepository_CreateServiceAccount_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 bson.ObjectID
+		var arg1 *ServiceAccount
 		if args[1] != nil {
-			arg1 = args[1].(bson.ObjectID)
+			arg1 = args[1].(*ServiceAccount)
 		}
 		run(
 			arg0,
@@ -490,54 +569,54 @@ func (_c *MockRepository_DeleteStrategy_Call) Run(run func(ctx context.Context,
 	return _c
 }
 
-func (_c *MockRepository_DeleteStrategy_Call) Return(err error) *MockRepository_DeleteStrategy_Call {
+func (_c *MockRepository_CreateServiceAccount_Call) Return(err error) *MockRepository_CreateServiceAccount_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_DeleteStrategy_Call) RunAndReturn(run func(ctx context.Context, strategyID bson.ObjectID) error) *MockRepository_DeleteStrategy_Call {
+func (_c *MockRepository_CreateServiceAccount_Call) RunAndReturn(run func(ctx context.Context, account *ServiceAccount) error) *MockRepository_CreateServiceAccount_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// InsertIntents provides a mock function for the type MockRepository
-func (_mock *MockRepository) InsertIntents(ctx context.Context, intents []*ScheduleIntent) error {
-	ret := _mock.Called(ctx, intents)
+// CreateTeam provides a mock function for the type MockRepository
+func (_mock *MockRepository) CreateTeam(ctx context.Context, team *Team) error {
+	ret := _mock.Called(ctx, team)
 
 	if len(ret) == 0 {
-		panic("no return value specified for InsertIntents")
+		panic("no return value specified for CreateTeam")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []*ScheduleIntent) error); ok {
-		r0 = returnFunc(ctx, intents)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Team) error); ok {
+		r0 = returnFunc(ctx, team)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_InsertIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertIntents'
-type MockRepository_InsertIntents_Call struct {
+// MockRepository_CreateTeam_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateTeam'
+type MockRepository_CreateTeam_Call struct {
 	*mock.Call
 }
 
-// InsertIntents is a helper method to define mock.On call
+// CreateTeam is a helper method to define mock.On call
 //   - ctx context.Context
-//   - intents []*ScheduleIntent
-func (_e *MockRepository_Expecter) InsertIntents(ctx interface{}, intents interface{}) *MockRepository_InsertIntents_Call {
-	return &MockRepository_InsertIntents_Call{Call: _e.mock.On("InsertIntents", ctx, intents)}
+//   - team *Team
+func (_e *MockRepository_Expecter) CreateTeam(ctx interface{}, team interface{}) *MockRepository_CreateTeam_Call {
+	return &MockRepository_CreateTeam_Call{Call: _e.mock.On("CreateTeam", ctx, team)}
 }
 
-func (_c *MockRepository_InsertIntents_Call) Run(run func(ctx context.Context, intents []*ScheduleIntent)) *MockRepository_InsertIntents_Call {
+func (_c *MockRepository_CreateTeam_Call) Run(run func(ctx context.Context, team *Team)) *MockRepository_CreateTeam_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 []*ScheduleIntent
+		var arg1 *Team
 		if args[1] != nil {
-			arg1 = args[1].([]*ScheduleIntent)
+			arg1 = args[1].(*Team)
 		}
 		run(
 			arg0,
@@ -547,117 +626,111 @@ func (_c *MockRepository_InsertIntents_Call) Run(run func(ctx context.Context, i
 	return _c
 }
 
-func (_c *MockRepository_InsertIntents_Call) Return(err error) *MockRepository_InsertIntents_Call {
+func (_c *MockRepository_CreateTeam_Call) Return(err error) *MockRepository_CreateTeam_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_InsertIntents_Call) RunAndReturn(run func(ctx context.Context, intents []*ScheduleIntent) error) *MockRepository_InsertIntents_Call {
+func (_c *MockRepository_CreateTeam_Call) RunAndReturn(run func(ctx context.Context, team *Team) error) *MockRepository_CreateTeam_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// InsertStrategyAndIntents provides a mock function for the type MockRepository
-func (_mock *MockRepository) InsertStrategyAndIntents(ctx context.Context, strategy *ScheduleStrategy, intents []*ScheduleIntent) error {
-	ret := _mock.Called(ctx, strategy, intents)
+// CreateUser provides a mock function for the type MockRepository
+func (_mock *MockRepository) CreateUser(ctx context.Context, user *User) error {
+	ret := _mock.Called(ctx, user)
 
 	if len(ret) == 0 {
-		panic("no return value specified for InsertStrategyAndIntents")
+		panic("no return value specified for CreateUser")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *ScheduleStrategy, []*ScheduleIntent) error); ok {
-		r0 = returnFunc(ctx, strategy, intents)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *User) error); ok {
+		r0 = returnFunc(ctx, user)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_InsertStrategyAndIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertStrategyAndIntents'
-type MockRepository_InsertStrategyAndIntents_Call struct {
+// MockRepository_CreateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateUser'
+type MockRepository_CreateUser_Call struct {
 	*mock.Call
 }
 
-// InsertStrategyAndIntents is a helper method to define mock.On call
+// CreateUser is a helper method to define mock.On call
 //   - ctx context.Context
-//   - strategy *ScheduleStrategy
-//   - intents []*ScheduleIntent
-func (_e *MockRepository_Expecter) InsertStrategyAndIntents(ctx interface{}, strategy interface{}, intents interface{}) *MockRepository_InsertStrategyAndIntents_Call {
-	return &MockRepository_InsertStrategyAndIntents_Call{Call: _e.mock.On("InsertStrategyAndIntents", ctx, strategy, intents)}
+//   - user *User
+func (_e *MockRepository_Expecter) CreateUser(ctx interface{}, user interface{}) *MockRepository_CreateUser_Call {
+	return &MockRepository_CreateUser_Call{Call: _e.mock.On("CreateUser", ctx, user)}
 }
 
-func (_c *MockRepository_InsertStrategyAndIntents_Call) Run(run func(ctx context.Context, strategy *ScheduleStrategy, intents []*ScheduleIntent)) *MockRepository_InsertStrategyAndIntents_Call {
+func (_c *MockRepository_CreateUser_Call) Run(run func(ctx context.Context, user *User)) *MockRepository_CreateUser_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *ScheduleStrategy
+		var arg1 *User
 		if args[1] != nil {
-			arg1 = args[1].(*ScheduleStrategy)
-		}
-		var arg2 []*ScheduleIntent
-		if args[2] != nil {
-			arg2 = args[2].([]*ScheduleIntent)
+			arg1 = args[1].(*User)
 		}
 		run(
 			arg0,
 			arg1,
-			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockRepository_InsertStrategyAndIntents_Call) Return(err error) *MockRepository_InsertStrategyAndIntents_Call {
+func (_c *MockRepository_CreateUser_Call) Return(err error) *MockRepository_CreateUser_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_InsertStrategyAndIntents_Call) RunAndReturn(run func(ctx context.Context, strategy *ScheduleStrategy, intents []*ScheduleIntent) error) *MockRepository_InsertStrategyAndIntents_Call {
+func (_c *MockRepository_CreateUser_Call) RunAndReturn(run func(ctx context.Context, user *User) error) *MockRepository_CreateUser_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// QueryAuditLogs provides a mock function for the type MockRepository
-func (_mock *MockRepository) QueryAuditLogs(ctx context.Context, opt *QueryAuditLogOptions) error {
-	ret := _mock.Called(ctx, opt)
+// CreateWebhookSubscription provides a mock function for the type MockRepository
+func (_mock *MockRepository) CreateWebhookSubscription(ctx context.Context, sub *WebhookSubscription) error {
+	ret := _mock.Called(ctx, sub)
 
 	if len(ret) == 0 {
-		panic("no return value specified for QueryAuditLogs")
+		panic("no return value specified for CreateWebhookSubscription")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryAuditLogOptions) error); ok {
-		r0 = returnFunc(ctx, opt)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *WebhookSubscription) error); ok {
+		r0 = returnFunc(ctx, sub)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_QueryAuditLogs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryAuditLogs'
-type MockRepository_QueryAuditLogs_Call struct {
+// MockRepository_CreateWebhookSubscription_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateWebhookSubscription'
+type MockRepository_CreateWebhookSubscription_Call struct {
 	*mock.Call
 }
 
-// QueryAuditLogs is a helper method to define mock.On call
+// CreateWebhookSubscription is a helper method to define mock.On call
 //   - ctx context.Context
-//   - opt *QueryAuditLogOptions
-func (_e *MockRepository_Expecter) QueryAuditLogs(ctx interface{}, opt interface{}) *MockRepository_QueryAuditLogs_Call {
-	return &MockRepository_QueryAuditLogs_Call{Call: _e.mock.On("QueryAuditLogs", ctx, opt)}
+//   - sub *WebhookSubscription
+func (_e *MockRepository_Expecter) CreateWebhookSubscription(ctx interface{}, sub interface{}) *MockRepository_CreateWebhookSubscription_Call {
+	return &MockRepository_CreateWebhookSubscription_Call{Call: _e.mock.On("CreateWebhookSubscription", ctx, sub)}
 }
 
-func (_c *MockRepository_QueryAuditLogs_Call) Run(run func(ctx context.Context, opt *QueryAuditLogOptions)) *MockRepository_QueryAuditLogs_Call {
+func (_c *MockRepository_CreateWebhookSubscription_Call) Run(run func(ctx context.Context, sub *WebhookSubscription)) *MockRepository_CreateWebhookSubscription_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *QueryAuditLogOptions
+		var arg1 *WebhookSubscription
 		if args[1] != nil {
-			arg1 = args[1].(*QueryAuditLogOptions)
+			arg1 = args[1].(*WebhookSubscription)
 		}
 		run(
 			arg0,
@@ -667,54 +740,54 @@ func (_c *MockRepository_QueryAuditLogs_Call) Run(run func(ctx context.Context,
 	return _c
 }
 
-func (_c *MockRepository_QueryAuditLogs_Call) Return(err error) *MockRepository_QueryAuditLogs_Call {
+func (_c *MockRepository_CreateWebhookSubscription_Call) Return(err error) *MockRepository_CreateWebhookSubscription_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_QueryAuditLogs_Call) RunAndReturn(run func(ctx context.Context, opt *QueryAuditLogOptions) error) *MockRepository_QueryAuditLogs_Call {
+func (_c *MockRepository_CreateWebhookSubscription_Call) RunAndReturn(run func(ctx context.Context, sub *WebhookSubscription) error) *MockRepository_CreateWebhookSubscription_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// QueryIntents provides a mock function for the type MockRepository
-func (_mock *MockRepository) QueryIntents(ctx context.Context, opt *QueryIntentOptions) error {
-	ret := _mock.Called(ctx, opt)
+// DeleteAPIKeysByServiceAccountID provides a mock function for the type MockRepository
+func (_mock *MockRepository) DeleteAPIKeysByServiceAccountID(ctx context.Context, accountID string) error {
+	ret := _mock.Called(ctx, accountID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for QueryIntents")
+		panic("no return value specified for DeleteAPIKeysByServiceAccountID")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryIntentOptions) error); ok {
-		r0 = returnFunc(ctx, opt)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, accountID)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_QueryIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryIntents'
-type MockRepository_QueryIntents_Call struct {
+// MockRepository_DeleteAPIKeysByServiceAccountID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAPIKeysByServiceAccountID'
+type MockRepository_DeleteAPIKeysByServiceAccountID_Call struct {
 	*mock.Call
 }
 
-// QueryIntents is a helper method to define mock.On call
+// DeleteAPIKeysByServiceAccountID is a helper method to define mock.On call
 //   - ctx context.Context
-//   - opt *QueryIntentOptions
-func (_e *MockRepository_Expecter) QueryIntents(ctx interface{}, opt interface{}) *MockRepository_QueryIntents_Call {
-	return &MockRepository_QueryIntents_Call{Call: _e.mock.On("QueryIntents", ctx, opt)}
+//   - accountID string
+func (_e *MockRepository_Expecter) DeleteAPIKeysByServiceAccountID(ctx interface{}, accountID interface{}) *MockRepository_DeleteAPIKeysByServiceAccountID_Call {
+	return &MockRepository_DeleteAPIKeysByServiceAccountID_Call{Call: _e.mock.On("DeleteAPIKeysByServiceAccountID", ctx, accountID)}
 }
 
-func (_c *MockRepository_QueryIntents_Call) Run(run func(ctx context.Context, opt *QueryIntentOptions)) *MockRepository_QueryIntents_Call {
+func (_c *MockRepository_DeleteAPIKeysByServiceAccountID_Call) Run(run func(ctx context.Context, accountID string)) *MockRepository_DeleteAPIKeysByServiceAccountID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *QueryIntentOptions
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].(*QueryIntentOptions)
+			arg1 = args[1].(string)
 		}
 		run(
 			arg0,
@@ -724,54 +797,54 @@ func (_c *MockRepository_QueryIntents_Call) Run(run func(ctx context.Context, op
 	return _c
 }
 
-func (_c *MockRepository_QueryIntents_Call) Return(err error) *MockRepository_QueryIntents_Call {
+func (_c *MockRepository_DeleteAPIKeysByServiceAccountID_Call) Return(err error) *MockRepository_DeleteAPIKeysByServiceAccountID_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_QueryIntents_Call) RunAndReturn(run func(ctx context.Context, opt *QueryIntentOptions) error) *MockRepository_QueryIntents_Call {
+func (_c *MockRepository_DeleteAPIKeysByServiceAccountID_Call) RunAndReturn(run func(ctx context.Context, accountID string) error) *MockRepository_DeleteAPIKeysByServiceAccountID_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// QueryPermissions provides a mock function for the type MockRepository
-func (_mock *MockRepository) QueryPermissions(ctx context.Context, opt *QueryPermissionOptions) error {
-	ret := _mock.Called(ctx, opt)
+// DeleteAlertNodeStates provides a mock function for the type MockRepository
+func (_mock *MockRepository) DeleteAlertNodeStates(ctx context.Context, nodeIDs []string) error {
+	ret := _mock.Called(ctx, nodeIDs)
 
 	if len(ret) == 0 {
-		panic("no return value specified for QueryPermissions")
+		panic("no return value specified for DeleteAlertNodeStates")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryPermissionOptions) error); ok {
-		r0 = returnFunc(ctx, opt)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) error); ok {
+		r0 = returnFunc(ctx, nodeIDs)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_QueryPermissions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryPermissions'
-type MockRepository_QueryPermissions_Call struct {
+// MockRepository_DeleteAlertNodeStates_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAlertNodeStates'
+type MockRepository_DeleteAlertNodeStates_Call struct {
 	*mock.Call
 }
 
-// QueryPermissions is a helper method to define mock.On call
+// DeleteAlertNodeStates is a helper method to define mock.On call
 //   - ctx context.Context
-//   - opt *QueryPermissionOptions
-func (_e *MockRepository_Expecter) QueryPermissions(ctx interface{}, opt interface{}) *MockRepository_QueryPermissions_Call {
-	return &MockRepository_QueryPermissions_Call{Call: _e.mock.On("QueryPermissions", ctx, opt)}
+//   - nodeIDs []string
+func (_e *MockRepository_Expecter) DeleteAlertNodeStates(ctx interface{}, nodeIDs interface{}) *MockRepository_DeleteAlertNodeStates_Call {
+	return &MockRepository_DeleteAlertNodeStates_Call{Call: _e.mock.On("DeleteAlertNodeStates", ctx, nodeIDs)}
 }
 
-func (_c *MockRepository_QueryPermissions_Call) Run(run func(ctx context.Context, opt *QueryPermissionOptions)) *MockRepository_QueryPermissions_Call {
+func (_c *MockRepository_DeleteAlertNodeStates_Call) Run(run func(ctx context.Context, nodeIDs []string)) *MockRepository_DeleteAlertNodeStates_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *QueryPermissionOptions
+		var arg1 []string
 		if args[1] != nil {
-			arg1 = args[1].(*QueryPermissionOptions)
+			arg1 = args[1].([]string)
 		}
 		run(
 			arg0,
@@ -781,54 +854,54 @@ func (_c *MockRepository_QueryPermissions_Call) Run(run func(ctx context.Context
 	return _c
 }
 
-func (_c *MockRepository_QueryPermissions_Call) Return(err error) *MockRepository_QueryPermissions_Call {
+func (_c *MockRepository_DeleteAlertNodeStates_Call) Return(err error) *MockRepository_DeleteAlertNodeStates_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_QueryPermissions_Call) RunAndReturn(run func(ctx context.Context, opt *QueryPermissionOptions) error) *MockRepository_QueryPermissions_Call {
+func (_c *MockRepository_DeleteAlertNodeStates_Call) RunAndReturn(run func(ctx context.Context, nodeIDs []string) error) *MockRepository_DeleteAlertNodeStates_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// QueryRoles provides a mock function for the type MockRepository
-func (_mock *MockRepository) QueryRoles(ctx context.Context, opt *QueryRoleOptions) error {
-	ret := _mock.Called(ctx, opt)
+// DeleteAlertRule provides a mock function for the type MockRepository
+func (_mock *MockRepository) DeleteAlertRule(ctx context.Context, ruleID bson.ObjectID) error {
+	ret := _mock.Called(ctx, ruleID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for QueryRoles")
+		panic("no return value specified for DeleteAlertRule")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryRoleOptions) error); ok {
-		r0 = returnFunc(ctx, opt)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bson.ObjectID) error); ok {
+		r0 = returnFunc(ctx, ruleID)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_QueryRoles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryRoles'
-type MockRepository_QueryRoles_Call struct {
+// MockRepository_DeleteAlertRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAlertRule'
+type MockRepository_DeleteAlertRule_Call struct {
 	*mock.Call
 }
 
-// QueryRoles is a helper method to define mock.On call
+// DeleteAlertRule is a helper method to define mock.On call
 //   - ctx context.Context
-//   - opt *QueryRoleOptions
-func (_e *MockRepository_Expecter) QueryRoles(ctx interface{}, opt interface{}) *MockRepository_QueryRoles_Call {
-	return &MockRepository_QueryRoles_Call{Call: _e.mock.On("QueryRoles", ctx, opt)}
+//   - ruleID bson.ObjectID
+func (_e *MockRepository_Expecter) DeleteAlertRule(ctx interface{}, ruleID interface{}) *MockRepository_DeleteAlertRule_Call {
+	return &MockRepository_DeleteAlertRule_Call{Call: _e.mock.On("DeleteAlertRule", ctx, ruleID)}
 }
 
-func (_c *MockRepository_QueryRoles_Call) Run(run func(ctx context.Context, opt *QueryRoleOptions)) *MockRepository_QueryRoles_Call {
+func (_c *MockRepository_DeleteAlertRule_Call) Run(run func(ctx context.Context, ruleID bson.ObjectID)) *MockRepository_DeleteAlertRule_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *QueryRoleOptions
+		var arg1 bson.ObjectID
 		if args[1] != nil {
-			arg1 = args[1].(*QueryRoleOptions)
+			arg1 = args[1].(bson.ObjectID)
 		}
 		run(
 			arg0,
@@ -838,54 +911,54 @@ func (_c *MockRepository_QueryRoles_Call) Run(run func(ctx context.Context, opt
 	return _c
 }
 
-func (_c *MockRepository_QueryRoles_Call) Return(err error) *MockRepository_QueryRoles_Call {
+func (_c *MockRepository_DeleteAlertRule_Call) Return(err error) *MockRepository_DeleteAlertRule_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_QueryRoles_Call) RunAndReturn(run func(ctx context.Context, opt *QueryRoleOptions) error) *MockRepository_QueryRoles_Call {
+func (_c *MockRepository_DeleteAlertRule_Call) RunAndReturn(run func(ctx context.Context, ruleID bson.ObjectID) error) *MockRepository_DeleteAlertRule_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// QueryStrategies provides a mock function for the type MockRepository
-func (_mock *MockRepository) QueryStrategies(ctx context.Context, opt *QueryStrategyOptions) error {
-	ret := _mock.Called(ctx, opt)
+// DeleteIntents provides a mock function for the type MockRepository
+func (_mock *MockRepository) DeleteIntents(ctx context.Context, intentIDs []bson.ObjectID) error {
+	ret := _mock.Called(ctx, intentIDs)
 
 	if len(ret) == 0 {
-		panic("no return value specified for QueryStrategies")
+		panic("no return value specified for DeleteIntents")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryStrategyOptions) error); ok {
-		r0 = returnFunc(ctx, opt)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []bson.ObjectID) error); ok {
+		r0 = returnFunc(ctx, intentIDs)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_QueryStrategies_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryStrategies'
-type MockRepository_QueryStrategies_Call struct {
+// MockRepository_DeleteIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteIntents'
+type MockRepository_DeleteIntents_Call struct {
 	*mock.Call
 }
 
-// QueryStrategies is a helper method to define mock.On call
+// DeleteIntents is a helper method to define mock.On call
 //   - ctx context.Context
-//   - opt *QueryStrategyOptions
-func (_e *MockRepository_Expecter) QueryStrategies(ctx interface{}, opt interface{}) *MockRepository_QueryStrategies_Call {
-	return &MockRepository_QueryStrategies_Call{Call: _e.mock.On("QueryStrategies", ctx, opt)}
+//   - intentIDs []bson.ObjectID
+func (_e *MockRepository_Expecter) DeleteIntents(ctx interface{}, intentIDs interface{}) *MockRepository_DeleteIntents_Call {
+	return &MockRepository_DeleteIntents_Call{Call: _e.mock.On("DeleteIntents", ctx, intentIDs)}
 }
 
-func (_c *MockRepository_QueryStrategies_Call) Run(run func(ctx context.Context, opt *QueryStrategyOptions)) *MockRepository_QueryStrategies_Call {
+func (_c *MockRepository_DeleteIntents_Call) Run(run func(ctx context.Context, intentIDs []bson.ObjectID)) *MockRepository_DeleteIntents_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *QueryStrategyOptions
+		var arg1 []bson.ObjectID
 		if args[1] != nil {
-			arg1 = args[1].(*QueryStrategyOptions)
+			arg1 = args[1].([]bson.ObjectID)
 		}
 		run(
 			arg0,
@@ -895,54 +968,54 @@ func (_c *MockRepository_QueryStrategies_Call) Run(run func(ctx context.Context,
 	return _c
 }
 
-func (_c *MockRepository_QueryStrategies_Call) Return(err error) *MockRepository_QueryStrategies_Call {
+func (_c *MockRepository_DeleteIntents_Call) Return(err error) *MockRepository_DeleteIntents_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_QueryStrategies_Call) RunAndReturn(run func(ctx context.Context, opt *QueryStrategyOptions) error) *MockRepository_QueryStrategies_Call {
+func (_c *MockRepository_DeleteIntents_Call) RunAndReturn(run func(ctx context.Context, intentIDs []bson.ObjectID) error) *MockRepository_DeleteIntents_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// QueryUsers provides a mock function for the type MockRepository
-func (_mock *MockRepository) QueryUsers(ctx context.Context, opt *QueryUserOptions) error {
-	ret := _mock.Called(ctx, opt)
+// DeleteIntentsByStrategyID provides a mock function for the type MockRepository
+func (_mock *MockRepository) DeleteIntentsByStrategyID(ctx context.Context, strategyID bson.ObjectID) error {
+	ret := _mock.Called(ctx, strategyID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for QueryUsers")
+		panic("no return value specified for DeleteIntentsByStrategyID")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryUserOptions) error); ok {
-		r0 = returnFunc(ctx, opt)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bson.ObjectID) error); ok {
+		r0 = returnFunc(ctx, strategyID)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_QueryUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryUsers'
-type MockRepository_QueryUsers_Call struct {
+// MockRepository_DeleteIntentsByStrategyID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteIntentsByStrategyID'
+type MockRepository_DeleteIntentsByStrategyID_Call struct {
 	*mock.Call
 }
 
-// QueryUsers is a helper method to define mock.On call
+// DeleteIntentsByStrategyID is a helper method to define mock.On call
 //   - ctx context.Context
-//   - opt *QueryUserOptions
-func (_e *MockRepository_Expecter) QueryUsers(ctx interface{}, opt interface{}) *MockRepository_QueryUsers_Call {
-	return &MockRepository_QueryUsers_Call{Call: _e.mock.On("QueryUsers", ctx, opt)}
+//   - strategyID bson.ObjectID
+func (_e *MockRepository_Expecter) DeleteIntentsByStrategyID(ctx interface{}, strategyID interface{}) *MockRepository_DeleteIntentsByStrategyID_Call {
+	return &MockRepository_DeleteIntentsByStrategyID_Call{Call: _e.mock.On("DeleteIntentsByStrategyID", ctx, strategyID)}
 }
 
-func (_c *MockRepository_QueryUsers_Call) Run(run func(ctx context.Context, opt *QueryUserOptions)) *MockRepository_QueryUsers_Call {
+func (_c *MockRepository_DeleteIntentsByStrategyID_Call) Run(run func(ctx context.Context, strategyID bson.ObjectID)) *MockRepository_DeleteIntentsByStrategyID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *QueryUserOptions
+		var arg1 bson.ObjectID
 		if args[1] != nil {
-			arg1 = args[1].(*QueryUserOptions)
+			arg1 = args[1].(bson.ObjectID)
 		}
 		run(
 			arg0,
@@ -952,54 +1025,54 @@ func (_c *MockRepository_QueryUsers_Call) Run(run func(ctx context.Context, opt
 	return _c
 }
 
-func (_c *MockRepository_QueryUsers_Call) Return(err error) *MockRepository_QueryUsers_Call {
+func (_c *MockRepository_DeleteIntentsByStrategyID_Call) Return(err error) *MockRepository_DeleteIntentsByStrategyID_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_QueryUsers_Call) RunAndReturn(run func(ctx context.Context, opt *QueryUserOptions) error) *MockRepository_QueryUsers_Call {
+func (_c *MockRepository_DeleteIntentsByStrategyID_Call) RunAndReturn(run func(ctx context.Context, strategyID bson.ObjectID) error) *MockRepository_DeleteIntentsByStrategyID_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdatePermission provides a mock function for the type MockRepository
-func (_mock *MockRepository) UpdatePermission(ctx context.Context, permission *Permission) error {
-	ret := _mock.Called(ctx, permission)
+// DeleteRole provides a mock function for the type MockRepository
+func (_mock *MockRepository) DeleteRole(ctx context.Context, roleID bson.ObjectID) error {
+	ret := _mock.Called(ctx, roleID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdatePermission")
+		panic("no return value specified for DeleteRole")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *Permission) error); ok {
-		r0 = returnFunc(ctx, permission)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bson.ObjectID) error); ok {
+		r0 = returnFunc(ctx, roleID)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_UpdatePermission_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePermission'
-type MockRepository_UpdatePermission_Call struct {
+// MockRepository_DeleteRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRole'
+type MockRepository_DeleteRole_Call struct {
 	*mock.Call
 }
 
-// UpdatePermission is a helper method to define mock.On call
+// DeleteRole is a helper method to define mock.On call
 //   - ctx context.Context
-//   - permission *Permission
-func (_e *MockRepository_Expecter) UpdatePermission(ctx interface{}, permission interface{}) *MockRepository_UpdatePermission_Call {
-	return &MockRepository_UpdatePermission_Call{Call: _e.mock.On("UpdatePermission", ctx, permission)}
+//   - roleID bson.ObjectID
+func (_e *MockRepository_Expecter) DeleteRole(ctx interface{}, roleID interface{}) *MockRepository_DeleteRole_Call {
+	return &MockRepository_DeleteRole_Call{Call: _e.mock.On("DeleteRole", ctx, roleID)}
 }
 
-func (_c *MockRepository_UpdatePermission_Call) Run(run func(ctx context.Context, permission *Permission)) *MockRepository_UpdatePermission_Call {
+func (_c *MockRepository_DeleteRole_Call) Run(run func(ctx context.Context, roleID bson.ObjectID)) *MockRepository_DeleteRole_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *Permission
+		var arg1 bson.ObjectID
 		if args[1] != nil {
-			arg1 = args[1].(*Permission)
+			arg1 = args[1].(bson.ObjectID)
 		}
 		run(
 			arg0,
@@ -1009,54 +1082,54 @@ func (_c *MockRepository_UpdatePermission_Call) Run(run func(ctx context.Context
 	return _c
 }
 
-func (_c *MockRepository_UpdatePermission_Call) Return(err error) *MockRepository_UpdatePermission_Call {
+func (_c *MockRepository_DeleteRole_Call) Return(err error) *MockRepository_DeleteRole_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_UpdatePermission_Call) RunAndReturn(run func(ctx context.Context, permission *Permission) error) *MockRepository_UpdatePermission_Call {
+func (_c *MockRepository_DeleteRole_Call) RunAndReturn(run func(ctx context.Context, roleID bson.ObjectID) error) *MockRepository_DeleteRole_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateRole provides a mock function for the type MockRepository
-func (_mock *MockRepository) UpdateRole(ctx context.Context, role *Role) error {
-	ret := _mock.Called(ctx, role)
+// DeleteServiceAccount provides a mock function for the type MockRepository
+func (_mock *MockRepository) DeleteServiceAccount(ctx context.Context, accountID bson.ObjectID) error {
+	ret := _mock.Called(ctx, accountID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateRole")
+		panic("no return value specified for DeleteServiceAccount")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *Role) error); ok {
-		r0 = returnFunc(ctx, role)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bson.ObjectID) error); ok {
+		r0 = returnFunc(ctx, accountID)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_UpdateRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateRole'
-type MockRepository_UpdateRole_Call struct {
+// MockRepository_DeleteServiceAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteServiceAccount'
+type MockRepository_DeleteServiceAccount_Call struct {
 	*mock.Call
 }
 
-// UpdateRole is a helper method to define mock.On call
+// DeleteServiceAccount is a helper method to define mock.On call
 //   - ctx context.Context
-//   - role *Role
-func (_e *MockRepository_Expecter) UpdateRole(ctx interface{}, role interface{}) *MockRepository_UpdateRole_Call {
-	return &MockRepository_UpdateRole_Call{Call: _e.mock.On("UpdateRole", ctx, role)}
+//   - accountID bson.ObjectID
+func (_e *MockRepository_Expecter) DeleteServiceAccount(ctx interface{}, accountID interface{}) *MockRepository_DeleteServiceAccount_Call {
+	return &MockRepository_DeleteServiceAccount_Call{Call: _e.mock.On("DeleteServiceAccount", ctx, accountID)}
 }
 
-func (_c *MockRepository_UpdateRole_Call) Run(run func(ctx context.Context, role *Role)) *MockRepository_UpdateRole_Call {
+func (_c *MockRepository_DeleteServiceAccount_Call) Run(run func(ctx context.Context, accountID bson.ObjectID)) *MockRepository_DeleteServiceAccount_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *Role
+		var arg1 bson.ObjectID
 		if args[1] != nil {
-			arg1 = args[1].(*Role)
+			arg1 = args[1].(bson.ObjectID)
 		}
 		run(
 			arg0,
@@ -1066,54 +1139,54 @@ func (_c *MockRepository_UpdateRole_Call) Run(run func(ctx context.Context, role
 	return _c
 }
 
-func (_c *MockRepository_UpdateRole_Call) Return(err error) *MockRepository_UpdateRole_Call {
+func (_c *MockRepository_DeleteServiceAccount_Call) Return(err error) *MockRepository_DeleteServiceAccount_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_UpdateRole_Call) RunAndReturn(run func(ctx context.Context, role *Role) error) *MockRepository_UpdateRole_Call {
+func (_c *MockRepository_DeleteServiceAccount_Call) RunAndReturn(run func(ctx context.Context, accountID bson.ObjectID) error) *MockRepository_DeleteServiceAccount_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateStrategy provides a mock function for the type MockRepository
-func (_mock *MockRepository) UpdateStrategy(ctx context.Context, strategy *ScheduleStrategy) error {
-	ret := _mock.Called(ctx, strategy)
+// DeleteStrategy provides a mock function for the type MockRepository
+func (_mock *MockRepository) DeleteStrategy(ctx context.Context, strategyID bson.ObjectID) error {
+	ret := _mock.Called(ctx, strategyID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateStrategy")
+		panic("no return value specified for DeleteStrategy")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *ScheduleStrategy) error); ok {
-		r0 = returnFunc(ctx, strategy)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bson.ObjectID) error); ok {
+		r0 = returnFunc(ctx, strategyID)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_UpdateStrategy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateStrategy'
-type MockRepository_UpdateStrategy_Call struct {
+// MockRepository_DeleteStrategy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteStrategy'
+type MockRepository_DeleteStrategy_Call struct {
 	*mock.Call
 }
 
-// UpdateStrategy is a helper method to define mock.On call
+// DeleteStrategy is a helper method to define mock.On call
 //   - ctx context.Context
-//   - strategy *ScheduleStrategy
-func (_e *MockRepository_Expecter) UpdateStrategy(ctx interface{}, strategy interface{}) *MockRepository_UpdateStrategy_Call {
-	return &MockRepository_UpdateStrategy_Call{Call: _e.mock.On("UpdateStrategy", ctx, strategy)}
+//   - strategyID bson.ObjectID
+func (_e *MockRepository_Expecter) DeleteStrategy(ctx interface{}, strategyID interface{}) *MockRepository_DeleteStrategy_Call {
+	return &MockRepository_DeleteStrategy_Call{Call: _e.mock.On("DeleteStrategy", ctx, strategyID)}
 }
 
-func (_c *MockRepository_UpdateStrategy_Call) Run(run func(ctx context.Context, strategy *ScheduleStrategy)) *MockRepository_UpdateStrategy_Call {
+func (_c *MockRepository_DeleteStrategy_Call) Run(run func(ctx context.Context, strategyID bson.ObjectID)) *MockRepository_DeleteStrategy_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *ScheduleStrategy
+		var arg1 bson.ObjectID
 		if args[1] != nil {
-			arg1 = args[1].(*ScheduleStrategy)
+			arg1 = args[1].(bson.ObjectID)
 		}
 		run(
 			arg0,
@@ -1123,54 +1196,54 @@ func (_c *MockRepository_UpdateStrategy_Call) Run(run func(ctx context.Context,
 	return _c
 }
 
-func (_c *MockRepository_UpdateStrategy_Call) Return(err error) *MockRepository_UpdateStrategy_Call {
+func (_c *MockRepository_DeleteStrategy_Call) Return(err error) *MockRepository_DeleteStrategy_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_UpdateStrategy_Call) RunAndReturn(run func(ctx context.Context, strategy *ScheduleStrategy) error) *MockRepository_UpdateStrategy_Call {
+func (_c *MockRepository_DeleteStrategy_Call) RunAndReturn(run func(ctx context.Context, strategyID bson.ObjectID) error) *MockRepository_DeleteStrategy_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateUser provides a mock function for the type MockRepository
-func (_mock *MockRepository) UpdateUser(ctx context.Context, user *User) error {
-	ret := _mock.Called(ctx, user)
+// DeleteTeam provides a mock function for the type MockRepository
+func (_mock *MockRepository) DeleteTeam(ctx context.Context, teamID bson.ObjectID) error {
+	ret := _mock.Called(ctx, teamID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateUser")
+		panic("no return value specified for DeleteTeam")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *User) error); ok {
-		r0 = returnFunc(ctx, user)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bson.ObjectID) error); ok {
+		r0 = returnFunc(ctx, teamID)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_UpdateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUser'
-type MockRepository_UpdateUser_Call struct {
+// MockRepository_DeleteTeam_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTeam'
+type MockRepository_DeleteTeam_Call struct {
 	*mock.Call
 }
 
-// UpdateUser is a helper method to define mock.On call
+// DeleteTeam is a helper method to define mock.On call
 //   - ctx context.Context
-//   - user *User
-func (_e *MockRepository_Expecter) UpdateUser(ctx interface{}, user interface{}) *MockRepository_UpdateUser_Call {
-	return &MockRepository_UpdateUser_Call{Call: _e.mock.On("UpdateUser", ctx, user)}
+//   - teamID bson.ObjectID
+func (_e *MockRepository_Expecter) DeleteTeam(ctx interface{}, teamID interface{}) *MockRepository_DeleteTeam_Call {
+	return &MockRepository_DeleteTeam_Call{Call: _e.mock.On("DeleteTeam", ctx, teamID)}
 }
 
-func (_c *MockRepository_UpdateUser_Call) Run(run func(ctx context.Context, user *User)) *MockRepository_UpdateUser_Call {
+func (_c *MockRepository_DeleteTeam_Call) Run(run func(ctx context.Context, teamID bson.ObjectID)) *MockRepository_DeleteTeam_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *User
+		var arg1 bson.ObjectID
 		if args[1] != nil {
-			arg1 = args[1].(*User)
+			arg1 = args[1].(bson.ObjectID)
 		}
 		run(
 			arg0,
@@ -1180,143 +1253,178 @@ func (_c *MockRepository_UpdateUser_Call) Run(run func(ctx context.Context, user
 	return _c
 }
 
-func (_c *MockRepository_UpdateUser_Call) Return(err error) *MockRepository_UpdateUser_Call {
+func (_c *MockRepository_DeleteTeam_Call) Return(err error) *MockRepository_DeleteTeam_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_UpdateUser_Call) RunAndReturn(run func(ctx context.Context, user *User) error) *MockRepository_UpdateUser_Call {
+func (_c *MockRepository_DeleteTeam_Call) RunAndReturn(run func(ctx context.Context, teamID bson.ObjectID) error) *MockRepository_DeleteTeam_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// NewMockService creates a new instance of MockService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
-// The first argument is typically a *testing.T value.
-func NewMockService(t interface {
-	mock.TestingT
-	Cleanup(func())
-}) *MockService {
-	mock := &MockService{}
-	mock.Mock.Test(t)
+// DeleteWebhookSubscription provides a mock function for the type MockRepository
+func (_mock *MockRepository) DeleteWebhookSubscription(ctx context.Context, subID bson.ObjectID) error {
+	ret := _mock.Called(ctx, subID)
 
-	t.Cleanup(func() { mock.AssertExpectations(t) })
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWebhookSubscription")
+	}
 
-	return mock
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bson.ObjectID) error); ok {
+		r0 = returnFunc(ctx, subID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
 }
 
-// MockService is an autogenerated mock type for the Service type
-type MockService struct {
-	mock.Mock
+// MockRepository_DeleteWebhookSubscription_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteWebhookSubscription'
+type MockRepository_DeleteWebhookSubscription_Call struct {
+	*mock.Call
 }
 
-type MockService_Expecter struct {
-	mock *mock.Mock
+// DeleteWebhookSubscription is a helper method to define mock.On call
+//   - ctx context.Context
+//   - subID bson.ObjectID
+func (_e *MockRepository_Expecter) DeleteWebhookSubscription(ctx interface{}, subID interface{}) *MockRepository_DeleteWebhookSubscription_Call {
+	return &MockRepository_DeleteWebhookSubscription_Call{Call: _e.mock.On("DeleteWebhookSubscription", ctx, subID)}
 }
 
-func (_m *MockService) EXPECT() *MockService_Expecter {
-	return &MockService_Expecter{mock: &_m.Mock}
+func (_c *MockRepository_DeleteWebhookSubscription_Call) Run(run func(ctx context.Context, subID bson.ObjectID)) *MockRepository_DeleteWebhookSubscription_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 bson.ObjectID
+		if args[1] != nil {
+			arg1 = args[1].(bson.ObjectID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
 }
 
-// ChangePassword provides a mock function for the type MockService
-func (_mock *MockService) ChangePassword(ctx context.Context, user *Claims, oldPassword string, newPassword string) error {
-	ret := _mock.Called(ctx, user, oldPassword, newPassword)
+func (_c *MockRepository_DeleteWebhookSubscription_Call) Return(err error) *MockRepository_DeleteWebhookSubscription_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_DeleteWebhookSubscription_Call) RunAndReturn(run func(ctx context.Context, subID bson.ObjectID) error) *MockRepository_DeleteWebhookSubscription_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCacheVersion provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetCacheVersion(ctx context.Context, name string) (int64, error) {
+	ret := _mock.Called(ctx, name)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ChangePassword")
+		panic("no return value specified for GetCacheVersion")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, string) error); ok {
-		r0 = returnFunc(ctx, user, oldPassword, newPassword)
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return returnFunc(ctx, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = returnFunc(ctx, name)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(int64)
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockService_ChangePassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ChangePassword'
-type MockService_ChangePassword_Call struct {
+// MockRepository_GetCacheVersion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCacheVersion'
+type MockRepository_GetCacheVersion_Call struct {
 	*mock.Call
 }
 
-// ChangePassword is a helper method to define mock.On call
+// GetCacheVersion is a helper method to define mock.On call
 //   - ctx context.Context
-//   - user *Claims
-//   - oldPassword string
-//   - newPassword string
-func (_e *MockService_Expecter) ChangePassword(ctx interface{}, user interface{}, oldPassword interface{}, newPassword interface{}) *MockService_ChangePassword_Call {
-	return &MockService_ChangePassword_Call{Call: _e.mock.On("ChangePassword", ctx, user, oldPassword, newPassword)}
+//   - name string
+func (_e *MockRepository_Expecter) GetCacheVersion(ctx interface{}, name interface{}) *MockRepository_GetCacheVersion_Call {
+	return &MockRepository_GetCacheVersion_Call{Call: _e.mock.On("GetCacheVersion", ctx, name)}
 }
 
-func (_c *MockService_ChangePassword_Call) Run(run func(ctx context.Context, user *Claims, oldPassword string, newPassword string)) *MockService_ChangePassword_Call {
+func (_c *MockRepository_GetCacheVersion_Call) Run(run func(ctx context.Context, name string)) *MockRepository_GetCacheVersion_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *Claims
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].(*Claims)
-		}
-		var arg2 string
-		if args[2] != nil {
-			arg2 = args[2].(string)
-		}
-		var arg3 string
-		if args[3] != nil {
-			arg3 = args[3].(string)
+			arg1 = args[1].(string)
 		}
 		run(
 			arg0,
 			arg1,
-			arg2,
-			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_ChangePassword_Call) Return(err error) *MockService_ChangePassword_Call {
-	_c.Call.Return(err)
+func (_c *MockRepository_GetCacheVersion_Call) Return(i int64, err error) *MockRepository_GetCacheVersion_Call {
+	_c.Call.Return(i, err)
 	return _c
 }
 
-func (_c *MockService_ChangePassword_Call) RunAndReturn(run func(ctx context.Context, user *Claims, oldPassword string, newPassword string) error) *MockService_ChangePassword_Call {
+func (_c *MockRepository_GetCacheVersion_Call) RunAndReturn(run func(ctx context.Context, name string) (int64, error)) *MockRepository_GetCacheVersion_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreateAdminUserIfNotExists provides a mock function for the type MockService
-func (_mock *MockService) CreateAdminUserIfNotExists(ctx context.Context, username string, password string) error {
-	ret := _mock.Called(ctx, username, password)
+// IncrementCacheVersion provides a mock function for the type MockRepository
+func (_mock *MockRepository) IncrementCacheVersion(ctx context.Context, name string) (int64, error) {
+	ret := _mock.Called(ctx, name)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateAdminUserIfNotExists")
+		panic("no return value specified for IncrementCacheVersion")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
-		r0 = returnFunc(ctx, username, password)
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return returnFunc(ctx, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = returnFunc(ctx, name)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(int64)
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockService_CreateAdminUserIfNotExists_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAdminUserIfNotExists'
-type MockService_CreateAdminUserIfNotExists_Call struct {
+// MockRepository_IncrementCacheVersion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncrementCacheVersion'
+type MockRepository_IncrementCacheVersion_Call struct {
 	*mock.Call
 }
 
-// CreateAdminUserIfNotExists is a helper method to define mock.On call
+// IncrementCacheVersion is a helper method to define mock.On call
 //   - ctx context.Context
-//   - username string
-//   - password string
-func (_e *MockService_Expecter) CreateAdminUserIfNotExists(ctx interface{}, username interface{}, password interface{}) *MockService_CreateAdminUserIfNotExists_Call {
-	return &MockService_CreateAdminUserIfNotExists_Call{Call: _e.mock.On("CreateAdminUserIfNotExists", ctx, username, password)}
+//   - name string
+func (_e *MockRepository_Expecter) IncrementCacheVersion(ctx interface{}, name interface{}) *MockRepository_IncrementCacheVersion_Call {
+	return &MockRepository_IncrementCacheVersion_Call{Call: _e.mock.On("IncrementCacheVersion", ctx, name)}
 }
 
-func (_c *MockService_CreateAdminUserIfNotExists_Call) Run(run func(ctx context.Context, username string, password string)) *MockService_CreateAdminUserIfNotExists_Call {
+func (_c *MockRepository_IncrementCacheVersion_Call) Run(run func(ctx context.Context, name string)) *MockRepository_IncrementCacheVersion_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -1326,204 +1434,181 @@ func (_c *MockService_CreateAdminUserIfNotExists_Call) Run(run func(ctx context.
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
-		var arg2 string
-		if args[2] != nil {
-			arg2 = args[2].(string)
-		}
 		run(
 			arg0,
 			arg1,
-			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_CreateAdminUserIfNotExists_Call) Return(err error) *MockService_CreateAdminUserIfNotExists_Call {
-	_c.Call.Return(err)
+func (_c *MockRepository_IncrementCacheVersion_Call) Return(i int64, err error) *MockRepository_IncrementCacheVersion_Call {
+	_c.Call.Return(i, err)
 	return _c
 }
 
-func (_c *MockService_CreateAdminUserIfNotExists_Call) RunAndReturn(run func(ctx context.Context, username string, password string) error) *MockService_CreateAdminUserIfNotExists_Call {
+func (_c *MockRepository_IncrementCacheVersion_Call) RunAndReturn(run func(ctx context.Context, name string) (int64, error)) *MockRepository_IncrementCacheVersion_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreateNewUser provides a mock function for the type MockService
-func (_mock *MockService) CreateNewUser(ctx context.Context, operator *Claims, username string, password string) error {
-	ret := _mock.Called(ctx, operator, username, password)
+// InsertIntents provides a mock function for the type MockRepository
+func (_mock *MockRepository) InsertIntents(ctx context.Context, intents []*ScheduleIntent) error {
+	ret := _mock.Called(ctx, intents)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateNewUser")
+		panic("no return value specified for InsertIntents")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, string) error); ok {
-		r0 = returnFunc(ctx, operator, username, password)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []*ScheduleIntent) error); ok {
+		r0 = returnFunc(ctx, intents)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockService_CreateNewUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateNewUser'
-type MockService_CreateNewUser_Call struct {
+// MockRepository_InsertIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertIntents'
+type MockRepository_InsertIntents_Call struct {
 	*mock.Call
 }
 
-// CreateNewUser is a helper method to define mock.On call
+// InsertIntents is a helper method to define mock.On call
 //   - ctx context.Context
-//   - operator *Claims
-//   - username string
-//   - password string
-func (_e *MockService_Expecter) CreateNewUser(ctx interface{}, operator interface{}, username interface{}, password interface{}) *MockService_CreateNewUser_Call {
-	return &MockService_CreateNewUser_Call{Call: _e.mock.On("CreateNewUser", ctx, operator, username, password)}
+//   - intents []*ScheduleIntent
+func (_e *MockRepository_Expecter) InsertIntents(ctx interface{}, intents interface{}) *MockRepository_InsertIntents_Call {
+	return &MockRepository_InsertIntents_Call{Call: _e.mock.On("InsertIntents", ctx, intents)}
 }
 
-func (_c *MockService_CreateNewUser_Call) Run(run func(ctx context.Context, operator *Claims, username string, password string)) *MockService_CreateNewUser_Call {
+func (_c *MockRepository_InsertIntents_Call) Run(run func(ctx context.Context, intents []*ScheduleIntent)) *MockRepository_InsertIntents_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *Claims
+		var arg1 []*ScheduleIntent
 		if args[1] != nil {
-			arg1 = args[1].(*Claims)
-		}
-		var arg2 string
-		if args[2] != nil {
-			arg2 = args[2].(string)
-		}
-		var arg3 string
-		if args[3] != nil {
-			arg3 = args[3].(string)
+			arg1 = args[1].([]*ScheduleIntent)
 		}
 		run(
 			arg0,
 			arg1,
-			arg2,
-			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_CreateNewUser_Call) Return(err error) *MockService_CreateNewUser_Call {
+func (_c *MockRepository_InsertIntents_Call) Return(err error) *MockRepository_InsertIntents_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_CreateNewUser_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, username string, password string) error) *MockService_CreateNewUser_Call {
+func (_c *MockRepository_InsertIntents_Call) RunAndReturn(run func(ctx context.Context, intents []*ScheduleIntent) error) *MockRepository_InsertIntents_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreateRole provides a mock function for the type MockService
-func (_mock *MockService) CreateRole(ctx context.Context, operator *Claims, role *Role) error {
-	ret := _mock.Called(ctx, operator, role)
+// InsertMetricSamples provides a mock function for the type MockRepository
+func (_mock *MockRepository) InsertMetricSamples(ctx context.Context, samples []*NodeMetricSample) error {
+	ret := _mock.Called(ctx, samples)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateRole")
+		panic("no return value specified for InsertMetricSamples")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, *Role) error); ok {
-		r0 = returnFunc(ctx, operator, role)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []*NodeMetricSample) error); ok {
+		r0 = returnFunc(ctx, samples)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockService_CreateRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRole'
-type MockService_CreateRole_Call struct {
+// MockRepository_InsertMetricSamples_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertMetricSamples'
+type MockRepository_InsertMetricSamples_Call struct {
 	*mock.Call
 }
 
-// CreateRole is a helper method to define mock.On call
+// InsertMetricSamples is a helper method to define mock.On call
 //   - ctx context.Context
-//   - operator *Claims
-//   - role *Role
-func (_e *MockService_Expecter) CreateRole(ctx interface{}, operator interface{}, role interface{}) *MockService_CreateRole_Call {
-	return &MockService_CreateRole_Call{Call: _e.mock.On("CreateRole", ctx, operator, role)}
+//   - samples []*NodeMetricSample
+func (_e *MockRepository_Expecter) InsertMetricSamples(ctx interface{}, samples interface{}) *MockRepository_InsertMetricSamples_Call {
+	return &MockRepository_InsertMetricSamples_Call{Call: _e.mock.On("InsertMetricSamples", ctx, samples)}
 }
 
-func (_c *MockService_CreateRole_Call) Run(run func(ctx context.Context, operator *Claims, role *Role)) *MockService_CreateRole_Call {
+func (_c *MockRepository_InsertMetricSamples_Call) Run(run func(ctx context.Context, samples []*NodeMetricSample)) *MockRepository_InsertMetricSamples_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *Claims
+		var arg1 []*NodeMetricSample
 		if args[1] != nil {
-			arg1 = args[1].(*Claims)
-		}
-		var arg2 *Role
-		if args[2] != nil {
-			arg2 = args[2].(*Role)
+			arg1 = args[1].([]*NodeMetricSample)
 		}
 		run(
 			arg0,
 			arg1,
-			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_CreateRole_Call) Return(err error) *MockService_CreateRole_Call {
+func (_c *MockRepository_InsertMetricSamples_Call) Return(err error) *MockRepository_InsertMetricSamples_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_CreateRole_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, role *Role) error) *MockService_CreateRole_Call {
+func (_c *MockRepository_InsertMetricSamples_Call) RunAndReturn(run func(ctx context.Context, samples []*NodeMetricSample) error) *MockRepository_InsertMetricSamples_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreateScheduleStrategy provides a mock function for the type MockService
-func (_mock *MockService) CreateScheduleStrategy(ctx context.Context, operator *Claims, strategy *ScheduleStrategy) error {
-	ret := _mock.Called(ctx, operator, strategy)
+// InsertStrategyAndIntents provides a mock function for the type MockRepository
+func (_mock *MockRepository) InsertStrategyAndIntents(ctx context.Context, strategy *ScheduleStrategy, intents []*ScheduleIntent) error {
+	ret := _mock.Called(ctx, strategy, intents)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateScheduleStrategy")
+		panic("no return value specified for InsertStrategyAndIntents")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, *ScheduleStrategy) error); ok {
-		r0 = returnFunc(ctx, operator, strategy)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *ScheduleStrategy, []*ScheduleIntent) error); ok {
+		r0 = returnFunc(ctx, strategy, intents)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockService_CreateScheduleStrategy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateScheduleStrategy'
-type MockService_CreateScheduleStrategy_Call struct {
+// MockRepository_InsertStrategyAndIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertStrategyAndIntents'
+type MockRepository_InsertStrategyAndIntents_Call struct {
 	*mock.Call
 }
 
-// CreateScheduleStrategy is a helper method to define mock.On call
+// InsertStrategyAndIntents is a helper method to define mock.On call
 //   - ctx context.Context
-//   - operator *Claims
 //   - strategy *ScheduleStrategy
-func (_e *MockService_Expecter) CreateScheduleStrategy(ctx interface{}, operator interface{}, strategy interface{}) *MockService_CreateScheduleStrategy_Call {
-	return &MockService_CreateScheduleStrategy_Call{Call: _e.mock.On("CreateScheduleStrategy", ctx, operator, strategy)}
+//   - intents []*ScheduleIntent
+func (_e *MockRepository_Expecter) InsertStrategyAndIntents(ctx interface{}, strategy interface{}, intents interface{}) *MockRepository_InsertStrategyAndIntents_Call {
+	return &MockRepository_InsertStrategyAndIntents_Call{Call: _e.mock.On("InsertStrategyAndIntents", ctx, strategy, intents)}
 }
 
-func (_c *MockService_CreateScheduleStrategy_Call) Run(run func(ctx context.Context, operator *Claims, strategy *ScheduleStrategy)) *MockService_CreateScheduleStrategy_Call {
+func (_c *MockRepository_InsertStrategyAndIntents_Call) Run(run func(ctx context.Context, strategy *ScheduleStrategy, intents []*ScheduleIntent)) *MockRepository_InsertStrategyAndIntents_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *Claims
+		var arg1 *ScheduleStrategy
 		if args[1] != nil {
-			arg1 = args[1].(*Claims)
+			arg1 = args[1].(*ScheduleStrategy)
 		}
-		var arg2 *ScheduleStrategy
+		var arg2 []*ScheduleIntent
 		if args[2] != nil {
-			arg2 = args[2].(*ScheduleStrategy)
+			arg2 = args[2].([]*ScheduleIntent)
 		}
 		run(
 			arg0,
@@ -1534,47 +1619,5437 @@ func (_c *MockService_CreateScheduleStrategy_Call) Run(run func(ctx context.Cont
 	return _c
 }
 
-func (_c *MockService_CreateScheduleStrategy_Call) Return(err error) *MockService_CreateScheduleStrategy_Call {
+func (_c *MockRepository_InsertStrategyAndIntents_Call) Return(err error) *MockRepository_InsertStrategyAndIntents_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_CreateScheduleStrategy_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, strategy *ScheduleStrategy) error) *MockService_CreateScheduleStrategy_Call {
+func (_c *MockRepository_InsertStrategyAndIntents_Call) RunAndReturn(run func(ctx context.Context, strategy *ScheduleStrategy, intents []*ScheduleIntent) error) *MockRepository_InsertStrategyAndIntents_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteRole provides a mock function for the type MockService
-func (_mock *MockService) DeleteRole(ctx context.Context, operator *Claims, roleID string) error {
-	ret := _mock.Called(ctx, operator, roleID)
+// IsAccessTokenRevoked provides a mock function for the type MockRepository
+func (_mock *MockRepository) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	ret := _mock.Called(ctx, jti)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteRole")
+		panic("no return value specified for IsAccessTokenRevoked")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string) error); ok {
-		r0 = returnFunc(ctx, operator, roleID)
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return returnFunc(ctx, jti)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = returnFunc(ctx, jti)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(bool)
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, jti)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockService_DeleteRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRole'
-type MockService_DeleteRole_Call struct {
+// MockRepository_IsAccessTokenRevoked_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsAccessTokenRevoked'
+type MockRepository_IsAccessTokenRevoked_Call struct {
 	*mock.Call
 }
 
-// DeleteRole is a helper method to define mock.On call
+// IsAccessTokenRevoked is a helper method to define mock.On call
 //   - ctx context.Context
-//   - operator *Claims
-//   - roleID string
+//   - jti string
+func (_e *MockRepository_Expecter) IsAccessTokenRevoked(ctx interface{}, jti interface{}) *MockRepository_IsAccessTokenRevoked_Call {
+	return &MockRepository_IsAccessTokenRevoked_Call{Call: _e.mock.On("IsAccessTokenRevoked", ctx, jti)}
+}
+
+func (_c *MockRepository_IsAccessTokenRevoked_Call) Run(run func(ctx context.Context, jti string)) *MockRepository_IsAccessTokenRevoked_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_IsAccessTokenRevoked_Call) Return(b bool, err error) *MockRepository_IsAccessTokenRevoked_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockRepository_IsAccessTokenRevoked_Call) RunAndReturn(run func(ctx context.Context, jti string) (bool, error)) *MockRepository_IsAccessTokenRevoked_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAlertNodeStates provides a mock function for the type MockRepository
+func (_mock *MockRepository) ListAlertNodeStates(ctx context.Context) ([]*AlertNodeState, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAlertNodeStates")
+	}
+
+	var r0 []*AlertNodeState
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]*AlertNodeState, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []*AlertNodeState); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*AlertNodeState)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_ListAlertNodeStates_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAlertNodeStates'
+type MockRepository_ListAlertNodeStates_Call struct {
+	*mock.Call
+}
+
+// ListAlertNodeStates is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockRepository_Expecter) ListAlertNodeStates(ctx interface{}) *MockRepository_ListAlertNodeStates_Call {
+	return &MockRepository_ListAlertNodeStates_Call{Call: _e.mock.On("ListAlertNodeStates", ctx)}
+}
+
+func (_c *MockRepository_ListAlertNodeStates_Call) Run(run func(ctx context.Context)) *MockRepository_ListAlertNodeStates_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_ListAlertNodeStates_Call) Return(alertNodeStates []*AlertNodeState, err error) *MockRepository_ListAlertNodeStates_Call {
+	_c.Call.Return(alertNodeStates, err)
+	return _c
+}
+
+func (_c *MockRepository_ListAlertNodeStates_Call) RunAndReturn(run func(ctx context.Context) ([]*AlertNodeState, error)) *MockRepository_ListAlertNodeStates_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryAPIKeys provides a mock function for the type MockRepository
+func (_mock *MockRepository) QueryAPIKeys(ctx context.Context, opt *QueryAPIKeyOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryAPIKeys")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryAPIKeyOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_QueryAPIKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryAPIKeys'
+type MockRepository_QueryAPIKeys_Call struct {
+	*mock.Call
+}
+
+// QueryAPIKeys is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryAPIKeyOptions
+func (_e *MockRepository_Expecter) QueryAPIKeys(ctx interface{}, opt interface{}) *MockRepository_QueryAPIKeys_Call {
+	return &MockRepository_QueryAPIKeys_Call{Call: _e.mock.On("QueryAPIKeys", ctx, opt)}
+}
+
+func (_c *MockRepository_QueryAPIKeys_Call) Run(run func(ctx context.Context, opt *QueryAPIKeyOptions)) *MockRepository_QueryAPIKeys_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryAPIKeyOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryAPIKeyOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_QueryAPIKeys_Call) Return(err error) *MockRepository_QueryAPIKeys_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_QueryAPIKeys_Call) RunAndReturn(run func(ctx context.Context, opt *QueryAPIKeyOptions) error) *MockRepository_QueryAPIKeys_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryAlertRules provides a mock function for the type MockRepository
+func (_mock *MockRepository) QueryAlertRules(ctx context.Context, opt *QueryAlertRuleOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryAlertRules")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryAlertRuleOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_QueryAlertRules_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryAlertRules'
+type MockRepository_QueryAlertRules_Call struct {
+	*mock.Call
+}
+
+// QueryAlertRules is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryAlertRuleOptions
+func (_e *MockRepository_Expecter) QueryAlertRules(ctx interface{}, opt interface{}) *MockRepository_QueryAlertRules_Call {
+	return &MockRepository_QueryAlertRules_Call{Call: _e.mock.On("QueryAlertRules", ctx, opt)}
+}
+
+func (_c *MockRepository_QueryAlertRules_Call) Run(run func(ctx context.Context, opt *QueryAlertRuleOptions)) *MockRepository_QueryAlertRules_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryAlertRuleOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryAlertRuleOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_QueryAlertRules_Call) Return(err error) *MockRepository_QueryAlertRules_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_QueryAlertRules_Call) RunAndReturn(run func(ctx context.Context, opt *QueryAlertRuleOptions) error) *MockRepository_QueryAlertRules_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryAlerts provides a mock function for the type MockRepository
+func (_mock *MockRepository) QueryAlerts(ctx context.Context, opt *QueryAlertOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryAlerts")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryAlertOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_QueryAlerts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryAlerts'
+type MockRepository_QueryAlerts_Call struct {
+	*mock.Call
+}
+
+// QueryAlerts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryAlertOptions
+func (_e *MockRepository_Expecter) QueryAlerts(ctx interface{}, opt interface{}) *MockRepository_QueryAlerts_Call {
+	return &MockRepository_QueryAlerts_Call{Call: _e.mock.On("QueryAlerts", ctx, opt)}
+}
+
+func (_c *MockRepository_QueryAlerts_Call) Run(run func(ctx context.Context, opt *QueryAlertOptions)) *MockRepository_QueryAlerts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryAlertOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryAlertOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_QueryAlerts_Call) Return(err error) *MockRepository_QueryAlerts_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_QueryAlerts_Call) RunAndReturn(run func(ctx context.Context, opt *QueryAlertOptions) error) *MockRepository_QueryAlerts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryAuditLogs provides a mock function for the type MockRepository
+func (_mock *MockRepository) QueryAuditLogs(ctx context.Context, opt *QueryAuditLogOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryAuditLogs")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryAuditLogOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_QueryAuditLogs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryAuditLogs'
+type MockRepository_QueryAuditLogs_Call struct {
+	*mock.Call
+}
+
+// QueryAuditLogs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryAuditLogOptions
+func (_e *MockRepository_Expecter) QueryAuditLogs(ctx interface{}, opt interface{}) *MockRepository_QueryAuditLogs_Call {
+	return &MockRepository_QueryAuditLogs_Call{Call: _e.mock.On("QueryAuditLogs", ctx, opt)}
+}
+
+func (_c *MockRepository_QueryAuditLogs_Call) Run(run func(ctx context.Context, opt *QueryAuditLogOptions)) *MockRepository_QueryAuditLogs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryAuditLogOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryAuditLogOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_QueryAuditLogs_Call) Return(err error) *MockRepository_QueryAuditLogs_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_QueryAuditLogs_Call) RunAndReturn(run func(ctx context.Context, opt *QueryAuditLogOptions) error) *MockRepository_QueryAuditLogs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryIntents provides a mock function for the type MockRepository
+func (_mock *MockRepository) QueryIntents(ctx context.Context, opt *QueryIntentOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryIntents")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryIntentOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_QueryIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryIntents'
+type MockRepository_QueryIntents_Call struct {
+	*mock.Call
+}
+
+// QueryIntents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryIntentOptions
+func (_e *MockRepository_Expecter) QueryIntents(ctx interface{}, opt interface{}) *MockRepository_QueryIntents_Call {
+	return &MockRepository_QueryIntents_Call{Call: _e.mock.On("QueryIntents", ctx, opt)}
+}
+
+func (_c *MockRepository_QueryIntents_Call) Run(run func(ctx context.Context, opt *QueryIntentOptions)) *MockRepository_QueryIntents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryIntentOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryIntentOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_QueryIntents_Call) Return(err error) *MockRepository_QueryIntents_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_QueryIntents_Call) RunAndReturn(run func(ctx context.Context, opt *QueryIntentOptions) error) *MockRepository_QueryIntents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryMetricSamples provides a mock function for the type MockRepository
+func (_mock *MockRepository) QueryMetricSamples(ctx context.Context, opt *QueryMetricSampleOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryMetricSamples")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryMetricSampleOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_QueryMetricSamples_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryMetricSamples'
+type MockRepository_QueryMetricSamples_Call struct {
+	*mock.Call
+}
+
+// QueryMetricSamples is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryMetricSampleOptions
+func (_e *MockRepository_Expecter) QueryMetricSamples(ctx interface{}, opt interface{}) *MockRepository_QueryMetricSamples_Call {
+	return &MockRepository_QueryMetricSamples_Call{Call: _e.mock.On("QueryMetricSamples", ctx, opt)}
+}
+
+func (_c *MockRepository_QueryMetricSamples_Call) Run(run func(ctx context.Context, opt *QueryMetricSampleOptions)) *MockRepository_QueryMetricSamples_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryMetricSampleOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryMetricSampleOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_QueryMetricSamples_Call) Return(err error) *MockRepository_QueryMetricSamples_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_QueryMetricSamples_Call) RunAndReturn(run func(ctx context.Context, opt *QueryMetricSampleOptions) error) *MockRepository_QueryMetricSamples_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryPermissions provides a mock function for the type MockRepository
+func (_mock *MockRepository) QueryPermissions(ctx context.Context, opt *QueryPermissionOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryPermissions")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryPermissionOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_QueryPermissions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryPermissions'
+type MockRepository_QueryPermissions_Call struct {
+	*mock.Call
+}
+
+// QueryPermissions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryPermissionOptions
+func (_e *MockRepository_Expecter) QueryPermissions(ctx interface{}, opt interface{}) *MockRepository_QueryPermissions_Call {
+	return &MockRepository_QueryPermissions_Call{Call: _e.mock.On("QueryPermissions", ctx, opt)}
+}
+
+func (_c *MockRepository_QueryPermissions_Call) Run(run func(ctx context.Context, opt *QueryPermissionOptions)) *MockRepository_QueryPermissions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryPermissionOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryPermissionOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_QueryPermissions_Call) Return(err error) *MockRepository_QueryPermissions_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_QueryPermissions_Call) RunAndReturn(run func(ctx context.Context, opt *QueryPermissionOptions) error) *MockRepository_QueryPermissions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryRoles provides a mock function for the type MockRepository
+func (_mock *MockRepository) QueryRoles(ctx context.Context, opt *QueryRoleOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryRoles")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryRoleOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_QueryRoles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryRoles'
+type MockRepository_QueryRoles_Call struct {
+	*mock.Call
+}
+
+// QueryRoles is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryRoleOptions
+func (_e *MockRepository_Expecter) QueryRoles(ctx interface{}, opt interface{}) *MockRepository_QueryRoles_Call {
+	return &MockRepository_QueryRoles_Call{Call: _e.mock.On("QueryRoles", ctx, opt)}
+}
+
+func (_c *MockRepository_QueryRoles_Call) Run(run func(ctx context.Context, opt *QueryRoleOptions)) *MockRepository_QueryRoles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryRoleOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryRoleOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_QueryRoles_Call) Return(err error) *MockRepository_QueryRoles_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_QueryRoles_Call) RunAndReturn(run func(ctx context.Context, opt *QueryRoleOptions) error) *MockRepository_QueryRoles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryServiceAccounts provides a mock function for the type MockRepository
+func (_mock *MockRepository) QueryServiceAccounts(ctx context.Context, opt *QueryServiceAccountOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryServiceAccounts")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryServiceAccountOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_QueryServiceAccounts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryServiceAccounts'
+type MockRepository_QueryServiceAccounts_Call struct {
+	*mock.Call
+}
+
+// QueryServiceAccounts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryServiceAccountOptions
+func (_e *MockRepository_Expecter) QueryServiceAccounts(ctx interface{}, opt interface{}) *MockRepository_QueryServiceAccounts_Call {
+	return &MockRepository_QueryServiceAccounts_Call{Call: _e.mock.On("QueryServiceAccounts", ctx, opt)}
+}
+
+func (_c *MockRepository_QueryServiceAccounts_Call) Run(run func(ctx context.Context, opt *QueryServiceAccountOptions)) *MockRepository_QueryServiceAccounts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryServiceAccountOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryServiceAccountOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_QueryServiceAccounts_Call) Return(err error) *MockRepository_QueryServiceAccounts_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_QueryServiceAccounts_Call) RunAndReturn(run func(ctx context.Context, opt *QueryServiceAccountOptions) error) *MockRepository_QueryServiceAccounts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryStrategies provides a mock function for the type MockRepository
+func (_mock *MockRepository) QueryStrategies(ctx context.Context, opt *QueryStrategyOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryStrategies")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryStrategyOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_QueryStrategies_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryStrategies'
+type MockRepository_QueryStrategies_Call struct {
+	*mock.Call
+}
+
+// QueryStrategies is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryStrategyOptions
+func (_e *MockRepository_Expecter) QueryStrategies(ctx interface{}, opt interface{}) *MockRepository_QueryStrategies_Call {
+	return &MockRepository_QueryStrategies_Call{Call: _e.mock.On("QueryStrategies", ctx, opt)}
+}
+
+func (_c *MockRepository_QueryStrategies_Call) Run(run func(ctx context.Context, opt *QueryStrategyOptions)) *MockRepository_QueryStrategies_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryStrategyOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryStrategyOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_QueryStrategies_Call) Return(err error) *MockRepository_QueryStrategies_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_QueryStrategies_Call) RunAndReturn(run func(ctx context.Context, opt *QueryStrategyOptions) error) *MockRepository_QueryStrategies_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryTeams provides a mock function for the type MockRepository
+func (_mock *MockRepository) QueryTeams(ctx context.Context, opt *QueryTeamOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryTeams")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryTeamOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_QueryTeams_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryTeams'
+type MockRepository_QueryTeams_Call struct {
+	*mock.Call
+}
+
+// QueryTeams is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryTeamOptions
+func (_e *MockRepository_Expecter) QueryTeams(ctx interface{}, opt interface{}) *MockRepository_QueryTeams_Call {
+	return &MockRepository_QueryTeams_Call{Call: _e.mock.On("QueryTeams", ctx, opt)}
+}
+
+func (_c *MockRepository_QueryTeams_Call) Run(run func(ctx context.Context, opt *QueryTeamOptions)) *MockRepository_QueryTeams_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryTeamOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryTeamOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_QueryTeams_Call) Return(err error) *MockRepository_QueryTeams_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_QueryTeams_Call) RunAndReturn(run func(ctx context.Context, opt *QueryTeamOptions) error) *MockRepository_QueryTeams_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryUsers provides a mock function for the type MockRepository
+func (_mock *MockRepository) QueryUsers(ctx context.Context, opt *QueryUserOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryUsers")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryUserOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_QueryUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryUsers'
+type MockRepository_QueryUsers_Call struct {
+	*mock.Call
+}
+
+// QueryUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryUserOptions
+func (_e *MockRepository_Expecter) QueryUsers(ctx interface{}, opt interface{}) *MockRepository_QueryUsers_Call {
+	return &MockRepository_QueryUsers_Call{Call: _e.mock.On("QueryUsers", ctx, opt)}
+}
+
+func (_c *MockRepository_QueryUsers_Call) Run(run func(ctx context.Context, opt *QueryUserOptions)) *MockRepository_QueryUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryUserOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryUserOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_QueryUsers_Call) Return(err error) *MockRepository_QueryUsers_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_QueryUsers_Call) RunAndReturn(run func(ctx context.Context, opt *QueryUserOptions) error) *MockRepository_QueryUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryWebhookSubscriptions provides a mock function for the type MockRepository
+func (_mock *MockRepository) QueryWebhookSubscriptions(ctx context.Context, opt *QueryWebhookSubscriptionOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryWebhookSubscriptions")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryWebhookSubscriptionOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_QueryWebhookSubscriptions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryWebhookSubscriptions'
+type MockRepository_QueryWebhookSubscriptions_Call struct {
+	*mock.Call
+}
+
+// QueryWebhookSubscriptions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryWebhookSubscriptionOptions
+func (_e *MockRepository_Expecter) QueryWebhookSubscriptions(ctx interface{}, opt interface{}) *MockRepository_QueryWebhookSubscriptions_Call {
+	return &MockRepository_QueryWebhookSubscriptions_Call{Call: _e.mock.On("QueryWebhookSubscriptions", ctx, opt)}
+}
+
+func (_c *MockRepository_QueryWebhookSubscriptions_Call) Run(run func(ctx context.Context, opt *QueryWebhookSubscriptionOptions)) *MockRepository_QueryWebhookSubscriptions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryWebhookSubscriptionOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryWebhookSubscriptionOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_QueryWebhookSubscriptions_Call) Return(err error) *MockRepository_QueryWebhookSubscriptions_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_QueryWebhookSubscriptions_Call) RunAndReturn(run func(ctx context.Context, opt *QueryWebhookSubscriptionOptions) error) *MockRepository_QueryWebhookSubscriptions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordLoginFailure provides a mock function for the type MockRepository
+func (_mock *MockRepository) RecordLoginFailure(ctx context.Context, id bson.ObjectID, attempt LoginAttempt, lockout LoginLockout) (*User, error) {
+	ret := _mock.Called(ctx, id, attempt, lockout)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordLoginFailure")
+	}
+
+	var r0 *User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bson.ObjectID, LoginAttempt, LoginLockout) (*User, error)); ok {
+		return returnFunc(ctx, id, attempt, lockout)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bson.ObjectID, LoginAttempt, LoginLockout) *User); ok {
+		r0 = returnFunc(ctx, id, attempt, lockout)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, bson.ObjectID, LoginAttempt, LoginLockout) error); ok {
+		r1 = returnFunc(ctx, id, attempt, lockout)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_RecordLoginFailure_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordLoginFailure'
+type MockRepository_RecordLoginFailure_Call struct {
+	*mock.Call
+}
+
+// RecordLoginFailure is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id bson.ObjectID
+//   - attempt LoginAttempt
+//   - lockout LoginLockout
+func (_e *MockRepository_Expecter) RecordLoginFailure(ctx interface{}, id interface{}, attempt interface{}, lockout interface{}) *MockRepository_RecordLoginFailure_Call {
+	return &MockRepository_RecordLoginFailure_Call{Call: _e.mock.On("RecordLoginFailure", ctx, id, attempt, lockout)}
+}
+
+func (_c *MockRepository_RecordLoginFailure_Call) Run(run func(ctx context.Context, id bson.ObjectID, attempt LoginAttempt, lockout LoginLockout)) *MockRepository_RecordLoginFailure_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 bson.ObjectID
+		if args[1] != nil {
+			arg1 = args[1].(bson.ObjectID)
+		}
+		var arg2 LoginAttempt
+		if args[2] != nil {
+			arg2 = args[2].(LoginAttempt)
+		}
+		var arg3 LoginLockout
+		if args[3] != nil {
+			arg3 = args[3].(LoginLockout)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_RecordLoginFailure_Call) Return(user *User, err error) *MockRepository_RecordLoginFailure_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockRepository_RecordLoginFailure_Call) RunAndReturn(run func(ctx context.Context, id bson.ObjectID, attempt LoginAttempt, lockout LoginLockout) (*User, error)) *MockRepository_RecordLoginFailure_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordLoginSuccess provides a mock function for the type MockRepository
+func (_mock *MockRepository) RecordLoginSuccess(ctx context.Context, id bson.ObjectID, attempt LoginAttempt) error {
+	ret := _mock.Called(ctx, id, attempt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordLoginSuccess")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bson.ObjectID, LoginAttempt) error); ok {
+		r0 = returnFunc(ctx, id, attempt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_RecordLoginSuccess_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordLoginSuccess'
+type MockRepository_RecordLoginSuccess_Call struct {
+	*mock.Call
+}
+
+// RecordLoginSuccess is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id bson.ObjectID
+//   - attempt LoginAttempt
+func (_e *MockRepository_Expecter) RecordLoginSuccess(ctx interface{}, id interface{}, attempt interface{}) *MockRepository_RecordLoginSuccess_Call {
+	return &MockRepository_RecordLoginSuccess_Call{Call: _e.mock.On("RecordLoginSuccess", ctx, id, attempt)}
+}
+
+func (_c *MockRepository_RecordLoginSuccess_Call) Run(run func(ctx context.Context, id bson.ObjectID, attempt LoginAttempt)) *MockRepository_RecordLoginSuccess_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 bson.ObjectID
+		if args[1] != nil {
+			arg1 = args[1].(bson.ObjectID)
+		}
+		var arg2 LoginAttempt
+		if args[2] != nil {
+			arg2 = args[2].(LoginAttempt)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_RecordLoginSuccess_Call) Return(err error) *MockRepository_RecordLoginSuccess_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_RecordLoginSuccess_Call) RunAndReturn(run func(ctx context.Context, id bson.ObjectID, attempt LoginAttempt) error) *MockRepository_RecordLoginSuccess_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeAPIKey provides a mock function for the type MockRepository
+func (_mock *MockRepository) RevokeAPIKey(ctx context.Context, keyID bson.ObjectID, revokedAt int64) error {
+	ret := _mock.Called(ctx, keyID, revokedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAPIKey")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bson.ObjectID, int64) error); ok {
+		r0 = returnFunc(ctx, keyID, revokedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_RevokeAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeAPIKey'
+type MockRepository_RevokeAPIKey_Call struct {
+	*mock.Call
+}
+
+// RevokeAPIKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - keyID bson.ObjectID
+//   - revokedAt int64
+func (_e *MockRepository_Expecter) RevokeAPIKey(ctx interface{}, keyID interface{}, revokedAt interface{}) *MockRepository_RevokeAPIKey_Call {
+	return &MockRepository_RevokeAPIKey_Call{Call: _e.mock.On("RevokeAPIKey", ctx, keyID, revokedAt)}
+}
+
+func (_c *MockRepository_RevokeAPIKey_Call) Run(run func(ctx context.Context, keyID bson.ObjectID, revokedAt int64)) *MockRepository_RevokeAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 bson.ObjectID
+		if args[1] != nil {
+			arg1 = args[1].(bson.ObjectID)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_RevokeAPIKey_Call) Return(err error) *MockRepository_RevokeAPIKey_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_RevokeAPIKey_Call) RunAndReturn(run func(ctx context.Context, keyID bson.ObjectID, revokedAt int64) error) *MockRepository_RevokeAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeAccessToken provides a mock function for the type MockRepository
+func (_mock *MockRepository) RevokeAccessToken(ctx context.Context, token *RevokedToken) error {
+	ret := _mock.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAccessToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *RevokedToken) error); ok {
+		r0 = returnFunc(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_RevokeAccessToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeAccessToken'
+type MockRepository_RevokeAccessToken_Call struct {
+	*mock.Call
+}
+
+// RevokeAccessToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token *RevokedToken
+func (_e *MockRepository_Expecter) RevokeAccessToken(ctx interface{}, token interface{}) *MockRepository_RevokeAccessToken_Call {
+	return &MockRepository_RevokeAccessToken_Call{Call: _e.mock.On("RevokeAccessToken", ctx, token)}
+}
+
+func (_c *MockRepository_RevokeAccessToken_Call) Run(run func(ctx context.Context, token *RevokedToken)) *MockRepository_RevokeAccessToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *RevokedToken
+		if args[1] != nil {
+			arg1 = args[1].(*RevokedToken)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_RevokeAccessToken_Call) Return(err error) *MockRepository_RevokeAccessToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_RevokeAccessToken_Call) RunAndReturn(run func(ctx context.Context, token *RevokedToken) error) *MockRepository_RevokeAccessToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeRefreshTokens provides a mock function for the type MockRepository
+func (_mock *MockRepository) RevokeRefreshTokens(ctx context.Context, opt RevokeRefreshTokensOptions, revokedAt int64) error {
+	ret := _mock.Called(ctx, opt, revokedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeRefreshTokens")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, RevokeRefreshTokensOptions, int64) error); ok {
+		r0 = returnFunc(ctx, opt, revokedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_RevokeRefreshTokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeRefreshTokens'
+type MockRepository_RevokeRefreshTokens_Call struct {
+	*mock.Call
+}
+
+// RevokeRefreshTokens is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt RevokeRefreshTokensOptions
+//   - revokedAt int64
+func (_e *MockRepository_Expecter) RevokeRefreshTokens(ctx interface{}, opt interface{}, revokedAt interface{}) *MockRepository_RevokeRefreshTokens_Call {
+	return &MockRepository_RevokeRefreshTokens_Call{Call: _e.mock.On("RevokeRefreshTokens", ctx, opt, revokedAt)}
+}
+
+func (_c *MockRepository_RevokeRefreshTokens_Call) Run(run func(ctx context.Context, opt RevokeRefreshTokensOptions, revokedAt int64)) *MockRepository_RevokeRefreshTokens_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 RevokeRefreshTokensOptions
+		if args[1] != nil {
+			arg1 = args[1].(RevokeRefreshTokensOptions)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_RevokeRefreshTokens_Call) Return(err error) *MockRepository_RevokeRefreshTokens_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_RevokeRefreshTokens_Call) RunAndReturn(run func(ctx context.Context, opt RevokeRefreshTokensOptions, revokedAt int64) error) *MockRepository_RevokeRefreshTokens_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveAlert provides a mock function for the type MockRepository
+func (_mock *MockRepository) SaveAlert(ctx context.Context, alert *Alert) error {
+	ret := _mock.Called(ctx, alert)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveAlert")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Alert) error); ok {
+		r0 = returnFunc(ctx, alert)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_SaveAlert_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveAlert'
+type MockRepository_SaveAlert_Call struct {
+	*mock.Call
+}
+
+// SaveAlert is a helper method to define mock.On call
+//   - ctx context.Context
+//   - alert *Alert
+func (_e *MockRepository_Expecter) SaveAlert(ctx interface{}, alert interface{}) *MockRepository_SaveAlert_Call {
+	return &MockRepository_SaveAlert_Call{Call: _e.mock.On("SaveAlert", ctx, alert)}
+}
+
+func (_c *MockRepository_SaveAlert_Call) Run(run func(ctx context.Context, alert *Alert)) *MockRepository_SaveAlert_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Alert
+		if args[1] != nil {
+			arg1 = args[1].(*Alert)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_SaveAlert_Call) Return(err error) *MockRepository_SaveAlert_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_SaveAlert_Call) RunAndReturn(run func(ctx context.Context, alert *Alert) error) *MockRepository_SaveAlert_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveAlertNodeState provides a mock function for the type MockRepository
+func (_mock *MockRepository) SaveAlertNodeState(ctx context.Context, state *AlertNodeState) error {
+	ret := _mock.Called(ctx, state)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveAlertNodeState")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *AlertNodeState) error); ok {
+		r0 = returnFunc(ctx, state)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_SaveAlertNodeState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveAlertNodeState'
+type MockRepository_SaveAlertNodeState_Call struct {
+	*mock.Call
+}
+
+// SaveAlertNodeState is a helper method to define mock.On call
+//   - ctx context.Context
+//   - state *AlertNodeState
+func (_e *MockRepository_Expecter) SaveAlertNodeState(ctx interface{}, state interface{}) *MockRepository_SaveAlertNodeState_Call {
+	return &MockRepository_SaveAlertNodeState_Call{Call: _e.mock.On("SaveAlertNodeState", ctx, state)}
+}
+
+func (_c *MockRepository_SaveAlertNodeState_Call) Run(run func(ctx context.Context, state *AlertNodeState)) *MockRepository_SaveAlertNodeState_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *AlertNodeState
+		if args[1] != nil {
+			arg1 = args[1].(*AlertNodeState)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_SaveAlertNodeState_Call) Return(err error) *MockRepository_SaveAlertNodeState_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_SaveAlertNodeState_Call) RunAndReturn(run func(ctx context.Context, state *AlertNodeState) error) *MockRepository_SaveAlertNodeState_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetMetricSampleRetention provides a mock function for the type MockRepository
+func (_mock *MockRepository) SetMetricSampleRetention(ctx context.Context, retention time.Duration) error {
+	ret := _mock.Called(ctx, retention)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetMetricSampleRetention")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Duration) error); ok {
+		r0 = returnFunc(ctx, retention)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_SetMetricSampleRetention_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetMetricSampleRetention'
+type MockRepository_SetMetricSampleRetention_Call struct {
+	*mock.Call
+}
+
+// SetMetricSampleRetention is a helper method to define mock.On call
+//   - ctx context.Context
+//   - retention time.Duration
+func (_e *MockRepository_Expecter) SetMetricSampleRetention(ctx interface{}, retention interface{}) *MockRepository_SetMetricSampleRetention_Call {
+	return &MockRepository_SetMetricSampleRetention_Call{Call: _e.mock.On("SetMetricSampleRetention", ctx, retention)}
+}
+
+func (_c *MockRepository_SetMetricSampleRetention_Call) Run(run func(ctx context.Context, retention time.Duration)) *MockRepository_SetMetricSampleRetention_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Duration
+		if args[1] != nil {
+			arg1 = args[1].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_SetMetricSampleRetention_Call) Return(err error) *MockRepository_SetMetricSampleRetention_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_SetMetricSampleRetention_Call) RunAndReturn(run func(ctx context.Context, retention time.Duration) error) *MockRepository_SetMetricSampleRetention_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TouchAPIKey provides a mock function for the type MockRepository
+func (_mock *MockRepository) TouchAPIKey(ctx context.Context, keyID bson.ObjectID, usedAt int64) error {
+	ret := _mock.Called(ctx, keyID, usedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TouchAPIKey")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bson.ObjectID, int64) error); ok {
+		r0 = returnFunc(ctx, keyID, usedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_TouchAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TouchAPIKey'
+type MockRepository_TouchAPIKey_Call struct {
+	*mock.Call
+}
+
+// TouchAPIKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - keyID bson.ObjectID
+//   - usedAt int64
+func (_e *MockRepository_Expecter) TouchAPIKey(ctx interface{}, keyID interface{}, usedAt interface{}) *MockRepository_TouchAPIKey_Call {
+	return &MockRepository_TouchAPIKey_Call{Call: _e.mock.On("TouchAPIKey", ctx, keyID, usedAt)}
+}
+
+func (_c *MockRepository_TouchAPIKey_Call) Run(run func(ctx context.Context, keyID bson.ObjectID, usedAt int64)) *MockRepository_TouchAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 bson.ObjectID
+		if args[1] != nil {
+			arg1 = args[1].(bson.ObjectID)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_TouchAPIKey_Call) Return(err error) *MockRepository_TouchAPIKey_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_TouchAPIKey_Call) RunAndReturn(run func(ctx context.Context, keyID bson.ObjectID, usedAt int64) error) *MockRepository_TouchAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateAlertRule provides a mock function for the type MockRepository
+func (_mock *MockRepository) UpdateAlertRule(ctx context.Context, rule *AlertRule) error {
+	ret := _mock.Called(ctx, rule)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAlertRule")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *AlertRule) error); ok {
+		r0 = returnFunc(ctx, rule)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_UpdateAlertRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateAlertRule'
+type MockRepository_UpdateAlertRule_Call struct {
+	*mock.Call
+}
+
+// UpdateAlertRule is a helper method to define mock.On call
+//   - ctx context.Context
+//   - rule *AlertRule
+func (_e *MockRepository_Expecter) UpdateAlertRule(ctx interface{}, rule interface{}) *MockRepository_UpdateAlertRule_Call {
+	return &MockRepository_UpdateAlertRule_Call{Call: _e.mock.On("UpdateAlertRule", ctx, rule)}
+}
+
+func (_c *MockRepository_UpdateAlertRule_Call) Run(run func(ctx context.Context, rule *AlertRule)) *MockRepository_UpdateAlertRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *AlertRule
+		if args[1] != nil {
+			arg1 = args[1].(*AlertRule)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_UpdateAlertRule_Call) Return(err error) *MockRepository_UpdateAlertRule_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_UpdateAlertRule_Call) RunAndReturn(run func(ctx context.Context, rule *AlertRule) error) *MockRepository_UpdateAlertRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateIntentsOwner provides a mock function for the type MockRepository
+func (_mock *MockRepository) UpdateIntentsOwner(ctx context.Context, strategy *ScheduleStrategy) error {
+	ret := _mock.Called(ctx, strategy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateIntentsOwner")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *ScheduleStrategy) error); ok {
+		r0 = returnFunc(ctx, strategy)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_UpdateIntentsOwner_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateIntentsOwner'
+type MockRepository_UpdateIntentsOwner_Call struct {
+	*mock.Call
+}
+
+// UpdateIntentsOwner is a helper method to define mock.On call
+//   - ctx context.Context
+//   - strategy *ScheduleStrategy
+func (_e *MockRepository_Expecter) UpdateIntentsOwner(ctx interface{}, strategy interface{}) *MockRepository_UpdateIntentsOwner_Call {
+	return &MockRepository_UpdateIntentsOwner_Call{Call: _e.mock.On("UpdateIntentsOwner", ctx, strategy)}
+}
+
+func (_c *MockRepository_UpdateIntentsOwner_Call) Run(run func(ctx context.Context, strategy *ScheduleStrategy)) *MockRepository_UpdateIntentsOwner_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *ScheduleStrategy
+		if args[1] != nil {
+			arg1 = args[1].(*ScheduleStrategy)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_UpdateIntentsOwner_Call) Return(err error) *MockRepository_UpdateIntentsOwner_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_UpdateIntentsOwner_Call) RunAndReturn(run func(ctx context.Context, strategy *ScheduleStrategy) error) *MockRepository_UpdateIntentsOwner_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdatePermission provides a mock function for the type MockRepository
+func (_mock *MockRepository) UpdatePermission(ctx context.Context, permission *Permission) error {
+	ret := _mock.Called(ctx, permission)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePermission")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Permission) error); ok {
+		r0 = returnFunc(ctx, permission)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_UpdatePermission_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePermission'
+type MockRepository_UpdatePermission_Call struct {
+	*mock.Call
+}
+
+// UpdatePermission is a helper method to define mock.On call
+//   - ctx context.Context
+//   - permission *Permission
+func (_e *MockRepository_Expecter) UpdatePermission(ctx interface{}, permission interface{}) *MockRepository_UpdatePermission_Call {
+	return &MockRepository_UpdatePermission_Call{Call: _e.mock.On("UpdatePermission", ctx, permission)}
+}
+
+func (_c *MockRepository_UpdatePermission_Call) Run(run func(ctx context.Context, permission *Permission)) *MockRepository_UpdatePermission_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Permission
+		if args[1] != nil {
+			arg1 = args[1].(*Permission)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_UpdatePermission_Call) Return(err error) *MockRepository_UpdatePermission_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_UpdatePermission_Call) RunAndReturn(run func(ctx context.Context, permission *Permission) error) *MockRepository_UpdatePermission_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateRole provides a mock function for the type MockRepository
+func (_mock *MockRepository) UpdateRole(ctx context.Context, role *Role) error {
+	ret := _mock.Called(ctx, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRole")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Role) error); ok {
+		r0 = returnFunc(ctx, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_UpdateRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateRole'
+type MockRepository_UpdateRole_Call struct {
+	*mock.Call
+}
+
+// UpdateRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - role *Role
+func (_e *MockRepository_Expecter) UpdateRole(ctx interface{}, role interface{}) *MockRepository_UpdateRole_Call {
+	return &MockRepository_UpdateRole_Call{Call: _e.mock.On("UpdateRole", ctx, role)}
+}
+
+func (_c *MockRepository_UpdateRole_Call) Run(run func(ctx context.Context, role *Role)) *MockRepository_UpdateRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Role
+		if args[1] != nil {
+			arg1 = args[1].(*Role)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_UpdateRole_Call) Return(err error) *MockRepository_UpdateRole_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_UpdateRole_Call) RunAndReturn(run func(ctx context.Context, role *Role) error) *MockRepository_UpdateRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateServiceAccount provides a mock function for the type MockRepository
+func (_mock *MockRepository) UpdateServiceAccount(ctx context.Context, account *ServiceAccount) error {
+	ret := _mock.Called(ctx, account)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateServiceAccount")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *ServiceAccount) error); ok {
+		r0 = returnFunc(ctx, account)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_UpdateServiceAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateServiceAccount'
+type MockRepository_UpdateServiceAccount_Call struct {
+	*mock.Call
+}
+
+// UpdateServiceAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - account *ServiceAccount
+func (_e *MockRepository_Expecter) UpdateServiceAccount(ctx interface{}, account interface{}) *MockRepository_UpdateServiceAccount_Call {
+	return &MockRepository_UpdateServiceAccount_Call{Call: _e.mock.On("UpdateServiceAccount", ctx, account)}
+}
+
+func (_c *MockRepository_UpdateServiceAccount_Call) Run(run func(ctx context.Context, account *ServiceAccount)) *MockRepository_UpdateServiceAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *ServiceAccount
+		if args[1] != nil {
+			arg1 = args[1].(*ServiceAccount)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_UpdateServiceAccount_Call) Return(err error) *MockRepository_UpdateServiceAccount_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_UpdateServiceAccount_Call) RunAndReturn(run func(ctx context.Context, account *ServiceAccount) error) *MockRepository_UpdateServiceAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateStrategy provides a mock function for the type MockRepository
+func (_mock *MockRepository) UpdateStrategy(ctx context.Context, strategy *ScheduleStrategy) error {
+	ret := _mock.Called(ctx, strategy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateStrategy")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *ScheduleStrategy) error); ok {
+		r0 = returnFunc(ctx, strategy)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_UpdateStrategy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateStrategy'
+type MockRepository_UpdateStrategy_Call struct {
+	*mock.Call
+}
+
+// UpdateStrategy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - strategy *ScheduleStrategy
+func (_e *MockRepository_Expecter) UpdateStrategy(ctx interface{}, strategy interface{}) *MockRepository_UpdateStrategy_Call {
+	return &MockRepository_UpdateStrategy_Call{Call: _e.mock.On("UpdateStrategy", ctx, strategy)}
+}
+
+func (_c *MockRepository_UpdateStrategy_Call) Run(run func(ctx context.Context, strategy *ScheduleStrategy)) *MockRepository_UpdateStrategy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *ScheduleStrategy
+		if args[1] != nil {
+			arg1 = args[1].(*ScheduleStrategy)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_UpdateStrategy_Call) Return(err error) *MockRepository_UpdateStrategy_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_UpdateStrategy_Call) RunAndReturn(run func(ctx context.Context, strategy *ScheduleStrategy) error) *MockRepository_UpdateStrategy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateTeam provides a mock function for the type MockRepository
+func (_mock *MockRepository) UpdateTeam(ctx context.Context, team *Team) error {
+	ret := _mock.Called(ctx, team)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTeam")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Team) error); ok {
+		r0 = returnFunc(ctx, team)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_UpdateTeam_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateTeam'
+type MockRepository_UpdateTeam_Call struct {
+	*mock.Call
+}
+
+// UpdateTeam is a helper method to define mock.On call
+//   - ctx context.Context
+//   - team *Team
+func (_e *MockRepository_Expecter) UpdateTeam(ctx interface{}, team interface{}) *MockRepository_UpdateTeam_Call {
+	return &MockRepository_UpdateTeam_Call{Call: _e.mock.On("UpdateTeam", ctx, team)}
+}
+
+func (_c *MockRepository_UpdateTeam_Call) Run(run func(ctx context.Context, team *Team)) *MockRepository_UpdateTeam_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Team
+		if args[1] != nil {
+			arg1 = args[1].(*Team)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_UpdateTeam_Call) Return(err error) *MockRepository_UpdateTeam_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_UpdateTeam_Call) RunAndReturn(run func(ctx context.Context, team *Team) error) *MockRepository_UpdateTeam_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateUser provides a mock function for the type MockRepository
+func (_mock *MockRepository) UpdateUser(ctx context.Context, user *User) error {
+	ret := _mock.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUser")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *User) error); ok {
+		r0 = returnFunc(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_UpdateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUser'
+type MockRepository_UpdateUser_Call struct {
+	*mock.Call
+}
+
+// UpdateUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user *User
+func (_e *MockRepository_Expecter) UpdateUser(ctx interface{}, user interface{}) *MockRepository_UpdateUser_Call {
+	return &MockRepository_UpdateUser_Call{Call: _e.mock.On("UpdateUser", ctx, user)}
+}
+
+func (_c *MockRepository_UpdateUser_Call) Run(run func(ctx context.Context, user *User)) *MockRepository_UpdateUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *User
+		if args[1] != nil {
+			arg1 = args[1].(*User)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_UpdateUser_Call) Return(err error) *MockRepository_UpdateUser_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_UpdateUser_Call) RunAndReturn(run func(ctx context.Context, user *User) error) *MockRepository_UpdateUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateWebhookSubscription provides a mock function for the type MockRepository
+func (_mock *MockRepository) UpdateWebhookSubscription(ctx context.Context, sub *WebhookSubscription) error {
+	ret := _mock.Called(ctx, sub)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateWebhookSubscription")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *WebhookSubscription) error); ok {
+		r0 = returnFunc(ctx, sub)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_UpdateWebhookSubscription_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateWebhookSubscription'
+type MockRepository_UpdateWebhookSubscription_Call struct {
+	*mock.Call
+}
+
+// UpdateWebhookSubscription is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sub *WebhookSubscription
+func (_e *MockRepository_Expecter) UpdateWebhookSubscription(ctx interface{}, sub interface{}) *MockRepository_UpdateWebhookSubscription_Call {
+	return &MockRepository_UpdateWebhookSubscription_Call{Call: _e.mock.On("UpdateWebhookSubscription", ctx, sub)}
+}
+
+func (_c *MockRepository_UpdateWebhookSubscription_Call) Run(run func(ctx context.Context, sub *WebhookSubscription)) *MockRepository_UpdateWebhookSubscription_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *WebhookSubscription
+		if args[1] != nil {
+			arg1 = args[1].(*WebhookSubscription)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_UpdateWebhookSubscription_Call) Return(err error) *MockRepository_UpdateWebhookSubscription_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_UpdateWebhookSubscription_Call) RunAndReturn(run func(ctx context.Context, sub *WebhookSubscription) error) *MockRepository_UpdateWebhookSubscription_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UseRecoveryCode provides a mock function for the type MockRepository
+func (_mock *MockRepository) UseRecoveryCode(ctx context.Context, id bson.ObjectID, codeHash string) (bool, error) {
+	ret := _mock.Called(ctx, id, codeHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseRecoveryCode")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bson.ObjectID, string) (bool, error)); ok {
+		return returnFunc(ctx, id, codeHash)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bson.ObjectID, string) bool); ok {
+		r0 = returnFunc(ctx, id, codeHash)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, bson.ObjectID, string) error); ok {
+		r1 = returnFunc(ctx, id, codeHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_UseRecoveryCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UseRecoveryCode'
+type MockRepository_UseRecoveryCode_Call struct {
+	*mock.Call
+}
+
+// UseRecoveryCode is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id bson.ObjectID
+//   - codeHash string
+func (_e *MockRepository_Expecter) UseRecoveryCode(ctx interface{}, id interface{}, codeHash interface{}) *MockRepository_UseRecoveryCode_Call {
+	return &MockRepository_UseRecoveryCode_Call{Call: _e.mock.On("UseRecoveryCode", ctx, id, codeHash)}
+}
+
+func (_c *MockRepository_UseRecoveryCode_Call) Run(run func(ctx context.Context, id bson.ObjectID, codeHash string)) *MockRepository_UseRecoveryCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 bson.ObjectID
+		if args[1] != nil {
+			arg1 = args[1].(bson.ObjectID)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_UseRecoveryCode_Call) Return(b bool, err error) *MockRepository_UseRecoveryCode_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockRepository_UseRecoveryCode_Call) RunAndReturn(run func(ctx context.Context, id bson.ObjectID, codeHash string) (bool, error)) *MockRepository_UseRecoveryCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UseRefreshToken provides a mock function for the type MockRepository
+func (_mock *MockRepository) UseRefreshToken(ctx context.Context, tokenHash string, usedAt int64) (*RefreshToken, error) {
+	ret := _mock.Called(ctx, tokenHash, usedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseRefreshToken")
+	}
+
+	var r0 *RefreshToken
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64) (*RefreshToken, error)); ok {
+		return returnFunc(ctx, tokenHash, usedAt)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64) *RefreshToken); ok {
+		r0 = returnFunc(ctx, tokenHash, usedAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*RefreshToken)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = returnFunc(ctx, tokenHash, usedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_UseRefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UseRefreshToken'
+type MockRepository_UseRefreshToken_Call struct {
+	*mock.Call
+}
+
+// UseRefreshToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tokenHash string
+//   - usedAt int64
+func (_e *MockRepository_Expecter) UseRefreshToken(ctx interface{}, tokenHash interface{}, usedAt interface{}) *MockRepository_UseRefreshToken_Call {
+	return &MockRepository_UseRefreshToken_Call{Call: _e.mock.On("UseRefreshToken", ctx, tokenHash, usedAt)}
+}
+
+func (_c *MockRepository_UseRefreshToken_Call) Run(run func(ctx context.Context, tokenHash string, usedAt int64)) *MockRepository_UseRefreshToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_UseRefreshToken_Call) Return(refreshToken *RefreshToken, err error) *MockRepository_UseRefreshToken_Call {
+	_c.Call.Return(refreshToken, err)
+	return _c
+}
+
+func (_c *MockRepository_UseRefreshToken_Call) RunAndReturn(run func(ctx context.Context, tokenHash string, usedAt int64) (*RefreshToken, error)) *MockRepository_UseRefreshToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UseTwoFactorStep provides a mock function for the type MockRepository
+func (_mock *MockRepository) UseTwoFactorStep(ctx context.Context, id bson.ObjectID, step int64) (bool, error) {
+	ret := _mock.Called(ctx, id, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseTwoFactorStep")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bson.ObjectID, int64) (bool, error)); ok {
+		return returnFunc(ctx, id, step)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bson.ObjectID, int64) bool); ok {
+		r0 = returnFunc(ctx, id, step)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, bson.ObjectID, int64) error); ok {
+		r1 = returnFunc(ctx, id, step)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_UseTwoFactorStep_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UseTwoFactorStep'
+type MockRepository_UseTwoFactorStep_Call struct {
+	*mock.Call
+}
+
+// UseTwoFactorStep is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id bson.ObjectID
+//   - step int64
+func (_e *MockRepository_Expecter) UseTwoFactorStep(ctx interface{}, id interface{}, step interface{}) *MockRepository_UseTwoFactorStep_Call {
+	return &MockRepository_UseTwoFactorStep_Call{Call: _e.mock.On("UseTwoFactorStep", ctx, id, step)}
+}
+
+func (_c *MockRepository_UseTwoFactorStep_Call) Run(run func(ctx context.Context, id bson.ObjectID, step int64)) *MockRepository_UseTwoFactorStep_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 bson.ObjectID
+		if args[1] != nil {
+			arg1 = args[1].(bson.ObjectID)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_UseTwoFactorStep_Call) Return(b bool, err error) *MockRepository_UseTwoFactorStep_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockRepository_UseTwoFactorStep_Call) RunAndReturn(run func(ctx context.Context, id bson.ObjectID, step int64) (bool, error)) *MockRepository_UseTwoFactorStep_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockService creates a new instance of MockService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockService {
+	mock := &MockService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockService is an autogenerated mock type for the Service type
+type MockService struct {
+	mock.Mock
+}
+
+type MockService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockService) EXPECT() *MockService_Expecter {
+	return &MockService_Expecter{mock: &_m.Mock}
+}
+
+// BeginOIDCLogin provides a mock function for the type MockService
+func (_mock *MockService) BeginOIDCLogin(ctx context.Context) (*OIDCAuthRequest, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BeginOIDCLogin")
+	}
+
+	var r0 *OIDCAuthRequest
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (*OIDCAuthRequest, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) *OIDCAuthRequest); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*OIDCAuthRequest)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_BeginOIDCLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BeginOIDCLogin'
+type MockService_BeginOIDCLogin_Call struct {
+	*mock.Call
+}
+
+// BeginOIDCLogin is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockService_Expecter) BeginOIDCLogin(ctx interface{}) *MockService_BeginOIDCLogin_Call {
+	return &MockService_BeginOIDCLogin_Call{Call: _e.mock.On("BeginOIDCLogin", ctx)}
+}
+
+func (_c *MockService_BeginOIDCLogin_Call) Run(run func(ctx context.Context)) *MockService_BeginOIDCLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_BeginOIDCLogin_Call) Return(oIDCAuthRequest *OIDCAuthRequest, err error) *MockService_BeginOIDCLogin_Call {
+	_c.Call.Return(oIDCAuthRequest, err)
+	return _c
+}
+
+func (_c *MockService_BeginOIDCLogin_Call) RunAndReturn(run func(ctx context.Context) (*OIDCAuthRequest, error)) *MockService_BeginOIDCLogin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// BeginTwoFactorEnrollment provides a mock function for the type MockService
+func (_mock *MockService) BeginTwoFactorEnrollment(ctx context.Context, user *Claims) (*TwoFactorEnrollment, error) {
+	ret := _mock.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BeginTwoFactorEnrollment")
+	}
+
+	var r0 *TwoFactorEnrollment
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims) (*TwoFactorEnrollment, error)); ok {
+		return returnFunc(ctx, user)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims) *TwoFactorEnrollment); ok {
+		r0 = returnFunc(ctx, user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*TwoFactorEnrollment)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *Claims) error); ok {
+		r1 = returnFunc(ctx, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_BeginTwoFactorEnrollment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BeginTwoFactorEnrollment'
+type MockService_BeginTwoFactorEnrollment_Call struct {
+	*mock.Call
+}
+
+// BeginTwoFactorEnrollment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user *Claims
+func (_e *MockService_Expecter) BeginTwoFactorEnrollment(ctx interface{}, user interface{}) *MockService_BeginTwoFactorEnrollment_Call {
+	return &MockService_BeginTwoFactorEnrollment_Call{Call: _e.mock.On("BeginTwoFactorEnrollment", ctx, user)}
+}
+
+func (_c *MockService_BeginTwoFactorEnrollment_Call) Run(run func(ctx context.Context, user *Claims)) *MockService_BeginTwoFactorEnrollment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_BeginTwoFactorEnrollment_Call) Return(twoFactorEnrollment *TwoFactorEnrollment, err error) *MockService_BeginTwoFactorEnrollment_Call {
+	_c.Call.Return(twoFactorEnrollment, err)
+	return _c
+}
+
+func (_c *MockService_BeginTwoFactorEnrollment_Call) RunAndReturn(run func(ctx context.Context, user *Claims) (*TwoFactorEnrollment, error)) *MockService_BeginTwoFactorEnrollment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ChangePassword provides a mock function for the type MockService
+func (_mock *MockService) ChangePassword(ctx context.Context, user *Claims, oldPassword string, newPassword string) error {
+	ret := _mock.Called(ctx, user, oldPassword, newPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePassword")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, string) error); ok {
+		r0 = returnFunc(ctx, user, oldPassword, newPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_ChangePassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ChangePassword'
+type MockService_ChangePassword_Call struct {
+	*mock.Call
+}
+
+// ChangePassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user *Claims
+//   - oldPassword string
+//   - newPassword string
+func (_e *MockService_Expecter) ChangePassword(ctx interface{}, user interface{}, oldPassword interface{}, newPassword interface{}) *MockService_ChangePassword_Call {
+	return &MockService_ChangePassword_Call{Call: _e.mock.On("ChangePassword", ctx, user, oldPassword, newPassword)}
+}
+
+func (_c *MockService_ChangePassword_Call) Run(run func(ctx context.Context, user *Claims, oldPassword string, newPassword string)) *MockService_ChangePassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_ChangePassword_Call) Return(err error) *MockService_ChangePassword_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_ChangePassword_Call) RunAndReturn(run func(ctx context.Context, user *Claims, oldPassword string, newPassword string) error) *MockService_ChangePassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CollectMetricSamples provides a mock function for the type MockService
+func (_mock *MockService) CollectMetricSamples(ctx context.Context) error {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CollectMetricSamples")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_CollectMetricSamples_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CollectMetricSamples'
+type MockService_CollectMetricSamples_Call struct {
+	*mock.Call
+}
+
+// CollectMetricSamples is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockService_Expecter) CollectMetricSamples(ctx interface{}) *MockService_CollectMetricSamples_Call {
+	return &MockService_CollectMetricSamples_Call{Call: _e.mock.On("CollectMetricSamples", ctx)}
+}
+
+func (_c *MockService_CollectMetricSamples_Call) Run(run func(ctx context.Context)) *MockService_CollectMetricSamples_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_CollectMetricSamples_Call) Return(err error) *MockService_CollectMetricSamples_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_CollectMetricSamples_Call) RunAndReturn(run func(ctx context.Context) error) *MockService_CollectMetricSamples_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CompleteOIDCLogin provides a mock function for the type MockService
+func (_mock *MockService) CompleteOIDCLogin(ctx context.Context, callback OIDCCallback) (*OIDCLogin, error) {
+	ret := _mock.Called(ctx, callback)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteOIDCLogin")
+	}
+
+	var r0 *OIDCLogin
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, OIDCCallback) (*OIDCLogin, error)); ok {
+		return returnFunc(ctx, callback)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, OIDCCallback) *OIDCLogin); ok {
+		r0 = returnFunc(ctx, callback)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*OIDCLogin)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, OIDCCallback) error); ok {
+		r1 = returnFunc(ctx, callback)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_CompleteOIDCLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompleteOIDCLogin'
+type MockService_CompleteOIDCLogin_Call struct {
+	*mock.Call
+}
+
+// CompleteOIDCLogin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - callback OIDCCallback
+func (_e *MockService_Expecter) CompleteOIDCLogin(ctx interface{}, callback interface{}) *MockService_CompleteOIDCLogin_Call {
+	return &MockService_CompleteOIDCLogin_Call{Call: _e.mock.On("CompleteOIDCLogin", ctx, callback)}
+}
+
+func (_c *MockService_CompleteOIDCLogin_Call) Run(run func(ctx context.Context, callback OIDCCallback)) *MockService_CompleteOIDCLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 OIDCCallback
+		if args[1] != nil {
+			arg1 = args[1].(OIDCCallback)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_CompleteOIDCLogin_Call) Return(oIDCLogin *OIDCLogin, err error) *MockService_CompleteOIDCLogin_Call {
+	_c.Call.Return(oIDCLogin, err)
+	return _c
+}
+
+func (_c *MockService_CompleteOIDCLogin_Call) RunAndReturn(run func(ctx context.Context, callback OIDCCallback) (*OIDCLogin, error)) *MockService_CompleteOIDCLogin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ConfirmTwoFactorEnrollment provides a mock function for the type MockService
+func (_mock *MockService) ConfirmTwoFactorEnrollment(ctx context.Context, user *Claims, code string) ([]string, error) {
+	ret := _mock.Called(ctx, user, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmTwoFactorEnrollment")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string) ([]string, error)); ok {
+		return returnFunc(ctx, user, code)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string) []string); ok {
+		r0 = returnFunc(ctx, user, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *Claims, string) error); ok {
+		r1 = returnFunc(ctx, user, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_ConfirmTwoFactorEnrollment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConfirmTwoFactorEnrollment'
+type MockService_ConfirmTwoFactorEnrollment_Call struct {
+	*mock.Call
+}
+
+// ConfirmTwoFactorEnrollment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user *Claims
+//   - code string
+func (_e *MockService_Expecter) ConfirmTwoFactorEnrollment(ctx interface{}, user interface{}, code interface{}) *MockService_ConfirmTwoFactorEnrollment_Call {
+	return &MockService_ConfirmTwoFactorEnrollment_Call{Call: _e.mock.On("ConfirmTwoFactorEnrollment", ctx, user, code)}
+}
+
+func (_c *MockService_ConfirmTwoFactorEnrollment_Call) Run(run func(ctx context.Context, user *Claims, code string)) *MockService_ConfirmTwoFactorEnrollment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_ConfirmTwoFactorEnrollment_Call) Return(s []string, err error) *MockService_ConfirmTwoFactorEnrollment_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockService_ConfirmTwoFactorEnrollment_Call) RunAndReturn(run func(ctx context.Context, user *Claims, code string) ([]string, error)) *MockService_ConfirmTwoFactorEnrollment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateAPIKey provides a mock function for the type MockService
+func (_mock *MockService) CreateAPIKey(ctx context.Context, operator *Claims, key *APIKey) (string, error) {
+	ret := _mock.Called(ctx, operator, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAPIKey")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, *APIKey) (string, error)); ok {
+		return returnFunc(ctx, operator, key)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, *APIKey) string); ok {
+		r0 = returnFunc(ctx, operator, key)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *Claims, *APIKey) error); ok {
+		r1 = returnFunc(ctx, operator, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_CreateAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAPIKey'
+type MockService_CreateAPIKey_Call struct {
+	*mock.Call
+}
+
+// CreateAPIKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operator *Claims
+//   - key *APIKey
+func (_e *MockService_Expecter) CreateAPIKey(ctx interface{}, operator interface{}, key interface{}) *MockService_CreateAPIKey_Call {
+	return &MockService_CreateAPIKey_Call{Call: _e.mock.On("CreateAPIKey", ctx, operator, key)}
+}
+
+func (_c *MockService_CreateAPIKey_Call) Run(run func(ctx context.Context, operator *Claims, key *APIKey)) *MockService_CreateAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 *APIKey
+		if args[2] != nil {
+			arg2 = args[2].(*APIKey)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_CreateAPIKey_Call) Return(plainKey string, err error) *MockService_CreateAPIKey_Call {
+	_c.Call.Return(plainKey, err)
+	return _c
+}
+
+func (_c *MockService_CreateAPIKey_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, key *APIKey) (string, error)) *MockService_CreateAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateAdminUserIfNotExists provides a mock function for the type MockService
+func (_mock *MockService) CreateAdminUserIfNotExists(ctx context.Context, username string, password string) error {
+	ret := _mock.Called(ctx, username, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAdminUserIfNotExists")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, username, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_CreateAdminUserIfNotExists_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAdminUserIfNotExists'
+type MockService_CreateAdminUserIfNotExists_Call struct {
+	*mock.Call
+}
+
+// CreateAdminUserIfNotExists is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+//   - password string
+func (_e *MockService_Expecter) CreateAdminUserIfNotExists(ctx interface{}, username interface{}, password interface{}) *MockService_CreateAdminUserIfNotExists_Call {
+	return &MockService_CreateAdminUserIfNotExists_Call{Call: _e.mock.On("CreateAdminUserIfNotExists", ctx, username, password)}
+}
+
+func (_c *MockService_CreateAdminUserIfNotExists_Call) Run(run func(ctx context.Context, username string, password string)) *MockService_CreateAdminUserIfNotExists_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_CreateAdminUserIfNotExists_Call) Return(err error) *MockService_CreateAdminUserIfNotExists_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_CreateAdminUserIfNotExists_Call) RunAndReturn(run func(ctx context.Context, username string, password string) error) *MockService_CreateAdminUserIfNotExists_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateAlertRule provides a mock function for the type MockService
+func (_mock *MockService) CreateAlertRule(ctx context.Context, operator *Claims, rule *AlertRule) error {
+	ret := _mock.Called(ctx, operator, rule)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAlertRule")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, *AlertRule) error); ok {
+		r0 = returnFunc(ctx, operator, rule)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_CreateAlertRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAlertRule'
+type MockService_CreateAlertRule_Call struct {
+	*mock.Call
+}
+
+// CreateAlertRule is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operator *Claims
+//   - rule *AlertRule
+func (_e *MockService_Expecter) CreateAlertRule(ctx interface{}, operator interface{}, rule interface{}) *MockService_CreateAlertRule_Call {
+	return &MockService_CreateAlertRule_Call{Call: _e.mock.On("CreateAlertRule", ctx, operator, rule)}
+}
+
+func (_c *MockService_CreateAlertRule_Call) Run(run func(ctx context.Context, operator *Claims, rule *AlertRule)) *MockService_CreateAlertRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 *AlertRule
+		if args[2] != nil {
+			arg2 = args[2].(*AlertRule)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_CreateAlertRule_Call) Return(err error) *MockService_CreateAlertRule_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_CreateAlertRule_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, rule *AlertRule) error) *MockService_CreateAlertRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateAuditLog provides a mock function for the type MockService
+func (_mock *MockService) CreateAuditLog(ctx context.Context, log *AuditLog) error {
+	ret := _mock.Called(ctx, log)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAuditLog")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *AuditLog) error); ok {
+		r0 = returnFunc(ctx, log)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_CreateAuditLog_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAuditLog'
+type MockService_CreateAuditLog_Call struct {
+	*mock.Call
+}
+
+// CreateAuditLog is a helper method to define mock.On call
+//   - ctx context.Context
+//   - log *AuditLog
+func (_e *MockService_Expecter) CreateAuditLog(ctx interface{}, log interface{}) *MockService_CreateAuditLog_Call {
+	return &MockService_CreateAuditLog_Call{Call: _e.mock.On("CreateAuditLog", ctx, log)}
+}
+
+func (_c *MockService_CreateAuditLog_Call) Run(run func(ctx context.Context, log *AuditLog)) *MockService_CreateAuditLog_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *AuditLog
+		if args[1] != nil {
+			arg1 = args[1].(*AuditLog)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_CreateAuditLog_Call) Return(err error) *MockService_CreateAuditLog_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_CreateAuditLog_Call) RunAndReturn(run func(ctx context.Context, log *AuditLog) error) *MockService_CreateAuditLog_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateNewUser provides a mock function for the type MockService
+func (_mock *MockService) CreateNewUser(ctx context.Context, operator *Claims, username string, password string) error {
+	ret := _mock.Called(ctx, operator, username, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateNewUser")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, string) error); ok {
+		r0 = returnFunc(ctx, operator, username, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_CreateNewUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateNewUser'
+type MockService_CreateNewUser_Call struct {
+	*mock.Call
+}
+
+// CreateNewUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operator *Claims
+//   - username string
+//   - password string
+func (_e *MockService_Expecter) CreateNewUser(ctx interface{}, operator interface{}, username interface{}, password interface{}) *MockService_CreateNewUser_Call {
+	return &MockService_CreateNewUser_Call{Call: _e.mock.On("CreateNewUser", ctx, operator, username, password)}
+}
+
+func (_c *MockService_CreateNewUser_Call) Run(run func(ctx context.Context, operator *Claims, username string, password string)) *MockService_CreateNewUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_CreateNewUser_Call) Return(err error) *MockService_CreateNewUser_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_CreateNewUser_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, username string, password string) error) *MockService_CreateNewUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateRole provides a mock function for the type MockService
+func (_mock *MockService) CreateRole(ctx context.Context, operator *Claims, role *Role) error {
+	ret := _mock.Called(ctx, operator, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRole")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, *Role) error); ok {
+		r0 = returnFunc(ctx, operator, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_CreateRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRole'
+type MockService_CreateRole_Call struct {
+	*mock.Call
+}
+
+// CreateRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operator *Claims
+//   - role *Role
+func (_e *MockService_Expecter) CreateRole(ctx interface{}, operator interface{}, role interface{}) *MockService_CreateRole_Call {
+	return &MockService_CreateRole_Call{Call: _e.mock.On("CreateRole", ctx, operator, role)}
+}
+
+func (_c *MockService_CreateRole_Call) Run(run func(ctx context.Context, operator *Claims, role *Role)) *MockService_CreateRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 *Role
+		if args[2] != nil {
+			arg2 = args[2].(*Role)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_CreateRole_Call) Return(err error) *MockService_CreateRole_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_CreateRole_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, role *Role) error) *MockService_CreateRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateScheduleStrategy provides a mock function for the type MockService
+func (_mock *MockService) CreateScheduleStrategy(ctx context.Context, operator *Claims, strategy *ScheduleStrategy) error {
+	ret := _mock.Called(ctx, operator, strategy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateScheduleStrategy")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, *ScheduleStrategy) error); ok {
+		r0 = returnFunc(ctx, operator, strategy)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_CreateScheduleStrategy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateScheduleStrategy'
+type MockService_CreateScheduleStrategy_Call struct {
+	*mock.Call
+}
+
+// CreateScheduleStrategy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operator *Claims
+//   - strategy *ScheduleStrategy
+func (_e *MockService_Expecter) CreateScheduleStrategy(ctx interface{}, operator interface{}, strategy interface{}) *MockService_CreateScheduleStrategy_Call {
+	return &MockService_CreateScheduleStrategy_Call{Call: _e.mock.On("CreateScheduleStrategy", ctx, operator, strategy)}
+}
+
+func (_c *MockService_CreateScheduleStrategy_Call) Run(run func(ctx context.Context, operator *Claims, strategy *ScheduleStrategy)) *MockService_CreateScheduleStrategy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 *ScheduleStrategy
+		if args[2] != nil {
+			arg2 = args[2].(*ScheduleStrategy)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_CreateScheduleStrategy_Call) Return(err error) *MockService_CreateScheduleStrategy_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_CreateScheduleStrategy_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, strategy *ScheduleStrategy) error) *MockService_CreateScheduleStrategy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateServiceAccount provides a mock function for the type MockService
+func (_mock *MockService) CreateServiceAccount(ctx context.Context, operator *Claims, account *ServiceAccount) error {
+	ret := _mock.Called(ctx, operator, account)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateServiceAccount")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, *ServiceAccount) error); ok {
+		r0 = returnFunc(ctx, operator, account)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_CreateServiceAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateServiceAccount'
+type MockService_CreateServiceAccount_Call struct {
+	*mock.Call
+}
+
+// CreateServiceAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operator *Claims
+//   - account *ServiceAccount
+func (_e *MockService_Expecter) CreateServiceAccount(ctx interface{}, operator interface{}, account interface{}) *MockService_CreateServiceAccount_Call {
+	return &MockService_CreateServiceAccount_Call{Call: _e.mock.On("CreateServiceAccount", ctx, operator, account)}
+}
+
+func (_c *MockService_CreateServiceAccount_Call) Run(run func(ctx context.Context, operator *Claims, account *ServiceAccount)) *MockService_CreateServiceAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 *ServiceAccount
+		if args[2] != nil {
+			arg2 = args[2].(*ServiceAccount)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_CreateServiceAccount_Call) Return(err error) *MockService_CreateServiceAccount_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_CreateServiceAccount_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, account *ServiceAccount) error) *MockService_CreateServiceAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateTeam provides a mock function for the type MockService
+func (_mock *MockService) CreateTeam(ctx context.Context, operator *Claims, team *Team) error {
+	ret := _mock.Called(ctx, operator, team)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTeam")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, *Team) error); ok {
+		r0 = returnFunc(ctx, operator, team)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_CreateTeam_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateTeam'
+type MockService_CreateTeam_Call struct {
+	*mock.Call
+}
+
+// CreateTeam is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operator *Claims
+//   - team *Team
+func (_e *MockService_Expecter) CreateTeam(ctx interface{}, operator interface{}, team interface{}) *MockService_CreateTeam_Call {
+	return &MockService_CreateTeam_Call{Call: _e.mock.On("CreateTeam", ctx, operator, team)}
+}
+
+func (_c *MockService_CreateTeam_Call) Run(run func(ctx context.Context, operator *Claims, team *Team)) *MockService_CreateTeam_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 *Team
+		if args[2] != nil {
+			arg2 = args[2].(*Team)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_CreateTeam_Call) Return(err error) *MockService_CreateTeam_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_CreateTeam_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, team *Team) error) *MockService_CreateTeam_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateWebhookSubscription provides a mock function for the type MockService
+func (_mock *MockService) CreateWebhookSubscription(ctx context.Context, operator *Claims, sub *WebhookSubscription) error {
+	ret := _mock.Called(ctx, operator, sub)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWebhookSubscription")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, *WebhookSubscription) error); ok {
+		r0 = returnFunc(ctx, operator, sub)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_CreateWebhookSubscription_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateWebhookSubscription'
+type MockService_CreateWebhookSubscription_Call struct {
+	*mock.Call
+}
+
+// CreateWebhookSubscription is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operator *Claims
+//   - sub *WebhookSubscription
+func (_e *MockService_Expecter) CreateWebhookSubscription(ctx interface{}, operator interface{}, sub interface{}) *MockService_CreateWebhookSubscription_Call {
+	return &MockService_CreateWebhookSubscription_Call{Call: _e.mock.On("CreateWebhookSubscription", ctx, operator, sub)}
+}
+
+func (_c *MockService_CreateWebhookSubscription_Call) Run(run func(ctx context.Context, operator *Claims, sub *WebhookSubscription)) *MockService_CreateWebhookSubscription_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 *WebhookSubscription
+		if args[2] != nil {
+			arg2 = args[2].(*WebhookSubscription)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_CreateWebhookSubscription_Call) Return(err error) *MockService_CreateWebhookSubscription_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_CreateWebhookSubscription_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, sub *WebhookSubscription) error) *MockService_CreateWebhookSubscription_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteAlertRule provides a mock function for the type MockService
+func (_mock *MockService) DeleteAlertRule(ctx context.Context, operator *Claims, ruleID string) error {
+	ret := _mock.Called(ctx, operator, ruleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAlertRule")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string) error); ok {
+		r0 = returnFunc(ctx, operator, ruleID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_DeleteAlertRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAlertRule'
+type MockService_DeleteAlertRule_Call struct {
+	*mock.Call
+}
+
+// DeleteAlertRule is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operator *Claims
+//   - ruleID string
+func (_e *MockService_Expecter) DeleteAlertRule(ctx interface{}, operator interface{}, ruleID interface{}) *MockService_DeleteAlertRule_Call {
+	return &MockService_DeleteAlertRule_Call{Call: _e.mock.On("DeleteAlertRule", ctx, operator, ruleID)}
+}
+
+func (_c *MockService_DeleteAlertRule_Call) Run(run func(ctx context.Context, operator *Claims, ruleID string)) *MockService_DeleteAlertRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_DeleteAlertRule_Call) Return(err error) *MockService_DeleteAlertRule_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_DeleteAlertRule_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, ruleID string) error) *MockService_DeleteAlertRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteRole provides a mock function for the type MockService
+func (_mock *MockService) DeleteRole(ctx context.Context, operator *Claims, roleID string) error {
+	ret := _mock.Called(ctx, operator, roleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRole")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string) error); ok {
+		r0 = returnFunc(ctx, operator, roleID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_DeleteRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRole'
+type MockService_DeleteRole_Call struct {
+	*mock.Call
+}
+
+// DeleteRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operator *Claims
+//   - roleID string
 func (_e *MockService_Expecter) DeleteRole(ctx interface{}, operator interface{}, roleID interface{}) *MockService_DeleteRole_Call {
 	return &MockService_DeleteRole_Call{Call: _e.mock.On("DeleteRole", ctx, operator, roleID)}
 }
 
-func (_c *MockService_DeleteRole_Call) Run(run func(ctx context.Context, operator *Claims, roleID string)) *MockService_DeleteRole_Call {
+func (_c *MockService_DeleteRole_Call) Run(run func(ctx context.Context, operator *Claims, roleID string)) *MockService_DeleteRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_DeleteRole_Call) Return(err error) *MockService_DeleteRole_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_DeleteRole_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, roleID string) error) *MockService_DeleteRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteScheduleIntents provides a mock function for the type MockService
+func (_mock *MockService) DeleteScheduleIntents(ctx context.Context, operator *Claims, intentIDs []string) error {
+	ret := _mock.Called(ctx, operator, intentIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteScheduleIntents")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, []string) error); ok {
+		r0 = returnFunc(ctx, operator, intentIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_DeleteScheduleIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteScheduleIntents'
+type MockService_DeleteScheduleIntents_Call struct {
+	*mock.Call
+}
+
+// DeleteScheduleIntents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operator *Claims
+//   - intentIDs []string
+func (_e *MockService_Expecter) DeleteScheduleIntents(ctx interface{}, operator interface{}, intentIDs interface{}) *MockService_DeleteScheduleIntents_Call {
+	return &MockService_DeleteScheduleIntents_Call{Call: _e.mock.On("DeleteScheduleIntents", ctx, operator, intentIDs)}
+}
+
+func (_c *MockService_DeleteScheduleIntents_Call) Run(run func(ctx context.Context, operator *Claims, intentIDs []string)) *MockService_DeleteScheduleIntents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 []string
+		if args[2] != nil {
+			arg2 = args[2].([]string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_DeleteScheduleIntents_Call) Return(err error) *MockService_DeleteScheduleIntents_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_DeleteScheduleIntents_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, intentIDs []string) error) *MockService_DeleteScheduleIntents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteScheduleStrategy provides a mock function for the type MockService
+func (_mock *MockService) DeleteScheduleStrategy(ctx context.Context, operator *Claims, strategyID string) error {
+	ret := _mock.Called(ctx, operator, strategyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteScheduleStrategy")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string) error); ok {
+		r0 = returnFunc(ctx, operator, strategyID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_DeleteScheduleStrategy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteScheduleStrategy'
+type MockService_DeleteScheduleStrategy_Call struct {
+	*mock.Call
+}
+
+// DeleteScheduleStrategy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operator *Claims
+//   - strategyID string
+func (_e *MockService_Expecter) DeleteScheduleStrategy(ctx interface{}, operator interface{}, strategyID interface{}) *MockService_DeleteScheduleStrategy_Call {
+	return &MockService_DeleteScheduleStrategy_Call{Call: _e.mock.On("DeleteScheduleStrategy", ctx, operator, strategyID)}
+}
+
+func (_c *MockService_DeleteScheduleStrategy_Call) Run(run func(ctx context.Context, operator *Claims, strategyID string)) *MockService_DeleteScheduleStrategy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_DeleteScheduleStrategy_Call) Return(err error) *MockService_DeleteScheduleStrategy_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_DeleteScheduleStrategy_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, strategyID string) error) *MockService_DeleteScheduleStrategy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteServiceAccount provides a mock function for the type MockService
+func (_mock *MockService) DeleteServiceAccount(ctx context.Context, operator *Claims, accountID string) error {
+	ret := _mock.Called(ctx, operator, accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteServiceAccount")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string) error); ok {
+		r0 = returnFunc(ctx, operator, accountID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_DeleteServiceAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteServiceAccount'
+type MockService_DeleteServiceAccount_Call struct {
+	*mock.Call
+}
+
+// DeleteServiceAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operator *Claims
+//   - accountID string
+func (_e *MockService_Expecter) DeleteServiceAccount(ctx interface{}, operator interface{}, accountID interface{}) *MockService_DeleteServiceAccount_Call {
+	return &MockService_DeleteServiceAccount_Call{Call: _e.mock.On("DeleteServiceAccount", ctx, operator, accountID)}
+}
+
+func (_c *MockService_DeleteServiceAccount_Call) Run(run func(ctx context.Context, operator *Claims, accountID string)) *MockService_DeleteServiceAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_DeleteServiceAccount_Call) Return(err error) *MockService_DeleteServiceAccount_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_DeleteServiceAccount_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, accountID string) error) *MockService_DeleteServiceAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteTeam provides a mock function for the type MockService
+func (_mock *MockService) DeleteTeam(ctx context.Context, operator *Claims, teamID string) error {
+	ret := _mock.Called(ctx, operator, teamID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTeam")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string) error); ok {
+		r0 = returnFunc(ctx, operator, teamID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_DeleteTeam_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTeam'
+type MockService_DeleteTeam_Call struct {
+	*mock.Call
+}
+
+// DeleteTeam is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operator *Claims
+//   - teamID string
+func (_e *MockService_Expecter) DeleteTeam(ctx interface{}, operator interface{}, teamID interface{}) *MockService_DeleteTeam_Call {
+	return &MockService_DeleteTeam_Call{Call: _e.mock.On("DeleteTeam", ctx, operator, teamID)}
+}
+
+func (_c *MockService_DeleteTeam_Call) Run(run func(ctx context.Context, operator *Claims, teamID string)) *MockService_DeleteTeam_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_DeleteTeam_Call) Return(err error) *MockService_DeleteTeam_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_DeleteTeam_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, teamID string) error) *MockService_DeleteTeam_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteWebhookSubscription provides a mock function for the type MockService
+func (_mock *MockService) DeleteWebhookSubscription(ctx context.Context, operator *Claims, subID string) error {
+	ret := _mock.Called(ctx, operator, subID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWebhookSubscription")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string) error); ok {
+		r0 = returnFunc(ctx, operator, subID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_DeleteWebhookSubscription_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteWebhookSubscription'
+type MockService_DeleteWebhookSubscription_Call struct {
+	*mock.Call
+}
+
+// DeleteWebhookSubscription is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operator *Claims
+//   - subID string
+func (_e *MockService_Expecter) DeleteWebhookSubscription(ctx interface{}, operator interface{}, subID interface{}) *MockService_DeleteWebhookSubscription_Call {
+	return &MockService_DeleteWebhookSubscription_Call{Call: _e.mock.On("DeleteWebhookSubscription", ctx, operator, subID)}
+}
+
+func (_c *MockService_DeleteWebhookSubscription_Call) Run(run func(ctx context.Context, operator *Claims, subID string)) *MockService_DeleteWebhookSubscription_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_DeleteWebhookSubscription_Call) Return(err error) *MockService_DeleteWebhookSubscription_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_DeleteWebhookSubscription_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, subID string) error) *MockService_DeleteWebhookSubscription_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DisableTwoFactor provides a mock function for the type MockService
+func (_mock *MockService) DisableTwoFactor(ctx context.Context, user *Claims, password string, code string) error {
+	ret := _mock.Called(ctx, user, password, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisableTwoFactor")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, string) error); ok {
+		r0 = returnFunc(ctx, user, password, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_DisableTwoFactor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DisableTwoFactor'
+type MockService_DisableTwoFactor_Call struct {
+	*mock.Call
+}
+
+// DisableTwoFactor is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user *Claims
+//   - password string
+//   - code string
+func (_e *MockService_Expecter) DisableTwoFactor(ctx interface{}, user interface{}, password interface{}, code interface{}) *MockService_DisableTwoFactor_Call {
+	return &MockService_DisableTwoFactor_Call{Call: _e.mock.On("DisableTwoFactor", ctx, user, password, code)}
+}
+
+func (_c *MockService_DisableTwoFactor_Call) Run(run func(ctx context.Context, user *Claims, password string, code string)) *MockService_DisableTwoFactor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_DisableTwoFactor_Call) Return(err error) *MockService_DisableTwoFactor_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_DisableTwoFactor_Call) RunAndReturn(run func(ctx context.Context, user *Claims, password string, code string) error) *MockService_DisableTwoFactor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EvaluateAlertRules provides a mock function for the type MockService
+func (_mock *MockService) EvaluateAlertRules(ctx context.Context) error {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EvaluateAlertRules")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_EvaluateAlertRules_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EvaluateAlertRules'
+type MockService_EvaluateAlertRules_Call struct {
+	*mock.Call
+}
+
+// EvaluateAlertRules is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockService_Expecter) EvaluateAlertRules(ctx interface{}) *MockService_EvaluateAlertRules_Call {
+	return &MockService_EvaluateAlertRules_Call{Call: _e.mock.On("EvaluateAlertRules", ctx)}
+}
+
+func (_c *MockService_EvaluateAlertRules_Call) Run(run func(ctx context.Context)) *MockService_EvaluateAlertRules_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_EvaluateAlertRules_Call) Return(err error) *MockService_EvaluateAlertRules_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_EvaluateAlertRules_Call) RunAndReturn(run func(ctx context.Context) error) *MockService_EvaluateAlertRules_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExplainPod provides a mock function for the type MockService
+func (_mock *MockService) ExplainPod(ctx context.Context, namespace string, name string) (*PodExplanation, error) {
+	ret := _mock.Called(ctx, namespace, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExplainPod")
+	}
+
+	var r0 *PodExplanation
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*PodExplanation, error)); ok {
+		return returnFunc(ctx, namespace, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *PodExplanation); ok {
+		r0 = returnFunc(ctx, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*PodExplanation)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_ExplainPod_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExplainPod'
+type MockService_ExplainPod_Call struct {
+	*mock.Call
+}
+
+// ExplainPod is a helper method to define mock.On call
+//   - ctx context.Context
+//   - namespace string
+//   - name string
+func (_e *MockService_Expecter) ExplainPod(ctx interface{}, namespace interface{}, name interface{}) *MockService_ExplainPod_Call {
+	return &MockService_ExplainPod_Call{Call: _e.mock.On("ExplainPod", ctx, namespace, name)}
+}
+
+func (_c *MockService_ExplainPod_Call) Run(run func(ctx context.Context, namespace string, name string)) *MockService_ExplainPod_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_ExplainPod_Call) Return(podExplanation *PodExplanation, err error) *MockService_ExplainPod_Call {
+	_c.Call.Return(podExplanation, err)
+	return _c
+}
+
+func (_c *MockService_ExplainPod_Call) RunAndReturn(run func(ctx context.Context, namespace string, name string) (*PodExplanation, error)) *MockService_ExplainPod_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetClusterMetrics provides a mock function for the type MockService
+func (_mock *MockService) GetClusterMetrics(ctx context.Context, staleAfter time.Duration) (*ClusterMetrics, error) {
+	ret := _mock.Called(ctx, staleAfter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetClusterMetrics")
+	}
+
+	var r0 *ClusterMetrics
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Duration) (*ClusterMetrics, error)); ok {
+		return returnFunc(ctx, staleAfter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Duration) *ClusterMetrics); ok {
+		r0 = returnFunc(ctx, staleAfter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ClusterMetrics)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Duration) error); ok {
+		r1 = returnFunc(ctx, staleAfter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_GetClusterMetrics_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetClusterMetrics'
+type MockService_GetClusterMetrics_Call struct {
+	*mock.Call
+}
+
+// GetClusterMetrics is a helper method to define mock.On call
+//   - ctx context.Context
+//   - staleAfter time.Duration
+func (_e *MockService_Expecter) GetClusterMetrics(ctx interface{}, staleAfter interface{}) *MockService_GetClusterMetrics_Call {
+	return &MockService_GetClusterMetrics_Call{Call: _e.mock.On("GetClusterMetrics", ctx, staleAfter)}
+}
+
+func (_c *MockService_GetClusterMetrics_Call) Run(run func(ctx context.Context, staleAfter time.Duration)) *MockService_GetClusterMetrics_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Duration
+		if args[1] != nil {
+			arg1 = args[1].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_GetClusterMetrics_Call) Return(clusterMetrics *ClusterMetrics, err error) *MockService_GetClusterMetrics_Call {
+	_c.Call.Return(clusterMetrics, err)
+	return _c
+}
+
+func (_c *MockService_GetClusterMetrics_Call) RunAndReturn(run func(ctx context.Context, staleAfter time.Duration) (*ClusterMetrics, error)) *MockService_GetClusterMetrics_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetNodeMetricHistory provides a mock function for the type MockService
+func (_mock *MockService) GetNodeMetricHistory(ctx context.Context, nodeID string, since time.Time, step time.Duration) (*NodeMetricHistory, error) {
+	ret := _mock.Called(ctx, nodeID, since, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNodeMetricHistory")
+	}
+
+	var r0 *NodeMetricHistory
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Duration) (*NodeMetricHistory, error)); ok {
+		return returnFunc(ctx, nodeID, since, step)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Duration) *NodeMetricHistory); ok {
+		r0 = returnFunc(ctx, nodeID, since, step)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*NodeMetricHistory)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Duration) error); ok {
+		r1 = returnFunc(ctx, nodeID, since, step)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_GetNodeMetricHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetNodeMetricHistory'
+type MockService_GetNodeMetricHistory_Call struct {
+	*mock.Call
+}
+
+// GetNodeMetricHistory is a helper method to define mock.On call
+//   - ctx context.Context
+//   - nodeID string
+//   - since time.Time
+//   - step time.Duration
+func (_e *MockService_Expecter) GetNodeMetricHistory(ctx interface{}, nodeID interface{}, since interface{}, step interface{}) *MockService_GetNodeMetricHistory_Call {
+	return &MockService_GetNodeMetricHistory_Call{Call: _e.mock.On("GetNodeMetricHistory", ctx, nodeID, since, step)}
+}
+
+func (_c *MockService_GetNodeMetricHistory_Call) Run(run func(ctx context.Context, nodeID string, since time.Time, step time.Duration)) *MockService_GetNodeMetricHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		var arg3 time.Duration
+		if args[3] != nil {
+			arg3 = args[3].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_GetNodeMetricHistory_Call) Return(nodeMetricHistory *NodeMetricHistory, err error) *MockService_GetNodeMetricHistory_Call {
+	_c.Call.Return(nodeMetricHistory, err)
+	return _c
+}
+
+func (_c *MockService_GetNodeMetricHistory_Call) RunAndReturn(run func(ctx context.Context, nodeID string, since time.Time, step time.Duration) (*NodeMetricHistory, error)) *MockService_GetNodeMetricHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPodPIDMapping provides a mock function for the type MockService
+func (_mock *MockService) GetPodPIDMapping(ctx context.Context, nodeID string) (*PodPIDMappingResponse, error) {
+	ret := _mock.Called(ctx, nodeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPodPIDMapping")
+	}
+
+	var r0 *PodPIDMappingResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*PodPIDMappingResponse, error)); ok {
+		return returnFunc(ctx, nodeID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *PodPIDMappingResponse); ok {
+		r0 = returnFunc(ctx, nodeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*PodPIDMappingResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, nodeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_GetPodPIDMapping_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPodPIDMapping'
+type MockService_GetPodPIDMapping_Call struct {
+	*mock.Call
+}
+
+// GetPodPIDMapping is a helper method to define mock.On call
+//   - ctx context.Context
+//   - nodeID string
+func (_e *MockService_Expecter) GetPodPIDMapping(ctx interface{}, nodeID interface{}) *MockService_GetPodPIDMapping_Call {
+	return &MockService_GetPodPIDMapping_Call{Call: _e.mock.On("GetPodPIDMapping", ctx, nodeID)}
+}
+
+func (_c *MockService_GetPodPIDMapping_Call) Run(run func(ctx context.Context, nodeID string)) *MockService_GetPodPIDMapping_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_GetPodPIDMapping_Call) Return(podPIDMappingResponse *PodPIDMappingResponse, err error) *MockService_GetPodPIDMapping_Call {
+	_c.Call.Return(podPIDMappingResponse, err)
+	return _c
+}
+
+func (_c *MockService_GetPodPIDMapping_Call) RunAndReturn(run func(ctx context.Context, nodeID string) (*PodPIDMappingResponse, error)) *MockService_GetPodPIDMapping_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// JSONWebKeySet provides a mock function for the type MockService
+func (_mock *MockService) JSONWebKeySet(ctx context.Context) (*JSONWebKeySet, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for JSONWebKeySet")
+	}
+
+	var r0 *JSONWebKeySet
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (*JSONWebKeySet, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) *JSONWebKeySet); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*JSONWebKeySet)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_JSONWebKeySet_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'JSONWebKeySet'
+type MockService_JSONWebKeySet_Call struct {
+	*mock.Call
+}
+
+// JSONWebKeySet is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockService_Expecter) JSONWebKeySet(ctx interface{}) *MockService_JSONWebKeySet_Call {
+	return &MockService_JSONWebKeySet_Call{Call: _e.mock.On("JSONWebKeySet", ctx)}
+}
+
+func (_c *MockService_JSONWebKeySet_Call) Run(run func(ctx context.Context)) *MockService_JSONWebKeySet_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_JSONWebKeySet_Call) Return(jSONWebKeySet *JSONWebKeySet, err error) *MockService_JSONWebKeySet_Call {
+	_c.Call.Return(jSONWebKeySet, err)
+	return _c
+}
+
+func (_c *MockService_JSONWebKeySet_Call) RunAndReturn(run func(ctx context.Context) (*JSONWebKeySet, error)) *MockService_JSONWebKeySet_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAlertRules provides a mock function for the type MockService
+func (_mock *MockService) ListAlertRules(ctx context.Context, opt *QueryAlertRuleOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAlertRules")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryAlertRuleOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_ListAlertRules_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAlertRules'
+type MockService_ListAlertRules_Call struct {
+	*mock.Call
+}
+
+// ListAlertRules is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryAlertRuleOptions
+func (_e *MockService_Expecter) ListAlertRules(ctx interface{}, opt interface{}) *MockService_ListAlertRules_Call {
+	return &MockService_ListAlertRules_Call{Call: _e.mock.On("ListAlertRules", ctx, opt)}
+}
+
+func (_c *MockService_ListAlertRules_Call) Run(run func(ctx context.Context, opt *QueryAlertRuleOptions)) *MockService_ListAlertRules_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryAlertRuleOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryAlertRuleOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_ListAlertRules_Call) Return(err error) *MockService_ListAlertRules_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_ListAlertRules_Call) RunAndReturn(run func(ctx context.Context, opt *QueryAlertRuleOptions) error) *MockService_ListAlertRules_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAlerts provides a mock function for the type MockService
+func (_mock *MockService) ListAlerts(ctx context.Context, opt *QueryAlertOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAlerts")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryAlertOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_ListAlerts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAlerts'
+type MockService_ListAlerts_Call struct {
+	*mock.Call
+}
+
+// ListAlerts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryAlertOptions
+func (_e *MockService_Expecter) ListAlerts(ctx interface{}, opt interface{}) *MockService_ListAlerts_Call {
+	return &MockService_ListAlerts_Call{Call: _e.mock.On("ListAlerts", ctx, opt)}
+}
+
+func (_c *MockService_ListAlerts_Call) Run(run func(ctx context.Context, opt *QueryAlertOptions)) *MockService_ListAlerts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryAlertOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryAlertOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_ListAlerts_Call) Return(err error) *MockService_ListAlerts_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_ListAlerts_Call) RunAndReturn(run func(ctx context.Context, opt *QueryAlertOptions) error) *MockService_ListAlerts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAuditLogs provides a mock function for the type MockService
+func (_mock *MockService) ListAuditLogs(ctx context.Context, opt *QueryAuditLogOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAuditLogs")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryAuditLogOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_ListAuditLogs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAuditLogs'
+type MockService_ListAuditLogs_Call struct {
+	*mock.Call
+}
+
+// ListAuditLogs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryAuditLogOptions
+func (_e *MockService_Expecter) ListAuditLogs(ctx interface{}, opt interface{}) *MockService_ListAuditLogs_Call {
+	return &MockService_ListAuditLogs_Call{Call: _e.mock.On("ListAuditLogs", ctx, opt)}
+}
+
+func (_c *MockService_ListAuditLogs_Call) Run(run func(ctx context.Context, opt *QueryAuditLogOptions)) *MockService_ListAuditLogs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryAuditLogOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryAuditLogOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_ListAuditLogs_Call) Return(err error) *MockService_ListAuditLogs_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_ListAuditLogs_Call) RunAndReturn(run func(ctx context.Context, opt *QueryAuditLogOptions) error) *MockService_ListAuditLogs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListNodes provides a mock function for the type MockService
+func (_mock *MockService) ListNodes(ctx context.Context) ([]*Node, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListNodes")
+	}
+
+	var r0 []*Node
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]*Node, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []*Node); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*Node)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_ListNodes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListNodes'
+type MockService_ListNodes_Call struct {
+	*mock.Call
+}
+
+// ListNodes is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockService_Expecter) ListNodes(ctx interface{}) *MockService_ListNodes_Call {
+	return &MockService_ListNodes_Call{Call: _e.mock.On("ListNodes", ctx)}
+}
+
+func (_c *MockService_ListNodes_Call) Run(run func(ctx context.Context)) *MockService_ListNodes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_ListNodes_Call) Return(nodes []*Node, err error) *MockService_ListNodes_Call {
+	_c.Call.Return(nodes, err)
+	return _c
+}
+
+func (_c *MockService_ListNodes_Call) RunAndReturn(run func(ctx context.Context) ([]*Node, error)) *MockService_ListNodes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListScheduleIntents provides a mock function for the type MockService
+func (_mock *MockService) ListScheduleIntents(ctx context.Context, filterOpts *QueryIntentOptions) error {
+	ret := _mock.Called(ctx, filterOpts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListScheduleIntents")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryIntentOptions) error); ok {
+		r0 = returnFunc(ctx, filterOpts)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_ListScheduleIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListScheduleIntents'
+type MockService_ListScheduleIntents_Call struct {
+	*mock.Call
+}
+
+// ListScheduleIntents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filterOpts *QueryIntentOptions
+func (_e *MockService_Expecter) ListScheduleIntents(ctx interface{}, filterOpts interface{}) *MockService_ListScheduleIntents_Call {
+	return &MockService_ListScheduleIntents_Call{Call: _e.mock.On("ListScheduleIntents", ctx, filterOpts)}
+}
+
+func (_c *MockService_ListScheduleIntents_Call) Run(run func(ctx context.Context, filterOpts *QueryIntentOptions)) *MockService_ListScheduleIntents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryIntentOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryIntentOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_ListScheduleIntents_Call) Return(err error) *MockService_ListScheduleIntents_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_ListScheduleIntents_Call) RunAndReturn(run func(ctx context.Context, filterOpts *QueryIntentOptions) error) *MockService_ListScheduleIntents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListScheduleStrategies provides a mock function for the type MockService
+func (_mock *MockService) ListScheduleStrategies(ctx context.Context, filterOpts *QueryStrategyOptions) error {
+	ret := _mock.Called(ctx, filterOpts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListScheduleStrategies")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryStrategyOptions) error); ok {
+		r0 = returnFunc(ctx, filterOpts)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_ListScheduleStrategies_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListScheduleStrategies'
+type MockService_ListScheduleStrategies_Call struct {
+	*mock.Call
+}
+
+// ListScheduleStrategies is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filterOpts *QueryStrategyOptions
+func (_e *MockService_Expecter) ListScheduleStrategies(ctx interface{}, filterOpts interface{}) *MockService_ListScheduleStrategies_Call {
+	return &MockService_ListScheduleStrategies_Call{Call: _e.mock.On("ListScheduleStrategies", ctx, filterOpts)}
+}
+
+func (_c *MockService_ListScheduleStrategies_Call) Run(run func(ctx context.Context, filterOpts *QueryStrategyOptions)) *MockService_ListScheduleStrategies_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryStrategyOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryStrategyOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_ListScheduleStrategies_Call) Return(err error) *MockService_ListScheduleStrategies_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_ListScheduleStrategies_Call) RunAndReturn(run func(ctx context.Context, filterOpts *QueryStrategyOptions) error) *MockService_ListScheduleStrategies_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListWebhookSubscriptions provides a mock function for the type MockService
+func (_mock *MockService) ListWebhookSubscriptions(ctx context.Context, opt *QueryWebhookSubscriptionOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWebhookSubscriptions")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryWebhookSubscriptionOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_ListWebhookSubscriptions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListWebhookSubscriptions'
+type MockService_ListWebhookSubscriptions_Call struct {
+	*mock.Call
+}
+
+// ListWebhookSubscriptions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryWebhookSubscriptionOptions
+func (_e *MockService_Expecter) ListWebhookSubscriptions(ctx interface{}, opt interface{}) *MockService_ListWebhookSubscriptions_Call {
+	return &MockService_ListWebhookSubscriptions_Call{Call: _e.mock.On("ListWebhookSubscriptions", ctx, opt)}
+}
+
+func (_c *MockService_ListWebhookSubscriptions_Call) Run(run func(ctx context.Context, opt *QueryWebhookSubscriptionOptions)) *MockService_ListWebhookSubscriptions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryWebhookSubscriptionOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryWebhookSubscriptionOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_ListWebhookSubscriptions_Call) Return(err error) *MockService_ListWebhookSubscriptions_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_ListWebhookSubscriptions_Call) RunAndReturn(run func(ctx context.Context, opt *QueryWebhookSubscriptionOptions) error) *MockService_ListWebhookSubscriptions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Login provides a mock function for the type MockService
+func (_mock *MockService) Login(ctx context.Context, email string, password string, clientIP string) (*LoginResult, error) {
+	ret := _mock.Called(ctx, email, password, clientIP)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Login")
+	}
+
+	var r0 *LoginResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) (*LoginResult, error)); ok {
+		return returnFunc(ctx, email, password, clientIP)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) *LoginResult); ok {
+		r0 = returnFunc(ctx, email, password, clientIP)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*LoginResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = returnFunc(ctx, email, password, clientIP)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_Login_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Login'
+type MockService_Login_Call struct {
+	*mock.Call
+}
+
+// Login is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - password string
+//   - clientIP string
+func (_e *MockService_Expecter) Login(ctx interface{}, email interface{}, password interface{}, clientIP interface{}) *MockService_Login_Call {
+	return &MockService_Login_Call{Call: _e.mock.On("Login", ctx, email, password, clientIP)}
+}
+
+func (_c *MockService_Login_Call) Run(run func(ctx context.Context, email string, password string, clientIP string)) *MockService_Login_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_Login_Call) Return(loginResult *LoginResult, err error) *MockService_Login_Call {
+	_c.Call.Return(loginResult, err)
+	return _c
+}
+
+func (_c *MockService_Login_Call) RunAndReturn(run func(ctx context.Context, email string, password string, clientIP string) (*LoginResult, error)) *MockService_Login_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Logout provides a mock function for the type MockService
+func (_mock *MockService) Logout(ctx context.Context, claims *Claims, refreshToken string) error {
+	ret := _mock.Called(ctx, claims, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Logout")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string) error); ok {
+		r0 = returnFunc(ctx, claims, refreshToken)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_Logout_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Logout'
+type MockService_Logout_Call struct {
+	*mock.Call
+}
+
+// Logout is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *Claims
+//   - refreshToken string
+func (_e *MockService_Expecter) Logout(ctx interface{}, claims interface{}, refreshToken interface{}) *MockService_Logout_Call {
+	return &MockService_Logout_Call{Call: _e.mock.On("Logout", ctx, claims, refreshToken)}
+}
+
+func (_c *MockService_Logout_Call) Run(run func(ctx context.Context, claims *Claims, refreshToken string)) *MockService_Logout_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_Logout_Call) Return(err error) *MockService_Logout_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_Logout_Call) RunAndReturn(run func(ctx context.Context, claims *Claims, refreshToken string) error) *MockService_Logout_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryAPIKeys provides a mock function for the type MockService
+func (_mock *MockService) QueryAPIKeys(ctx context.Context, opt *QueryAPIKeyOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryAPIKeys")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryAPIKeyOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_QueryAPIKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryAPIKeys'
+type MockService_QueryAPIKeys_Call struct {
+	*mock.Call
+}
+
+// QueryAPIKeys is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryAPIKeyOptions
+func (_e *MockService_Expecter) QueryAPIKeys(ctx interface{}, opt interface{}) *MockService_QueryAPIKeys_Call {
+	return &MockService_QueryAPIKeys_Call{Call: _e.mock.On("QueryAPIKeys", ctx, opt)}
+}
+
+func (_c *MockService_QueryAPIKeys_Call) Run(run func(ctx context.Context, opt *QueryAPIKeyOptions)) *MockService_QueryAPIKeys_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryAPIKeyOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryAPIKeyOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_QueryAPIKeys_Call) Return(err error) *MockService_QueryAPIKeys_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_QueryAPIKeys_Call) RunAndReturn(run func(ctx context.Context, opt *QueryAPIKeyOptions) error) *MockService_QueryAPIKeys_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryMetricSamples provides a mock function for the type MockService
+func (_mock *MockService) QueryMetricSamples(ctx context.Context, opt *QueryMetricSampleOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryMetricSamples")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryMetricSampleOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_QueryMetricSamples_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryMetricSamples'
+type MockService_QueryMetricSamples_Call struct {
+	*mock.Call
+}
+
+// QueryMetricSamples is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryMetricSampleOptions
+func (_e *MockService_Expecter) QueryMetricSamples(ctx interface{}, opt interface{}) *MockService_QueryMetricSamples_Call {
+	return &MockService_QueryMetricSamples_Call{Call: _e.mock.On("QueryMetricSamples", ctx, opt)}
+}
+
+func (_c *MockService_QueryMetricSamples_Call) Run(run func(ctx context.Context, opt *QueryMetricSampleOptions)) *MockService_QueryMetricSamples_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryMetricSampleOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryMetricSampleOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_QueryMetricSamples_Call) Return(err error) *MockService_QueryMetricSamples_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_QueryMetricSamples_Call) RunAndReturn(run func(ctx context.Context, opt *QueryMetricSampleOptions) error) *MockService_QueryMetricSamples_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryPermissions provides a mock function for the type MockService
+func (_mock *MockService) QueryPermissions(ctx context.Context, opt *QueryPermissionOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryPermissions")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryPermissionOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_QueryPermissions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryPermissions'
+type MockService_QueryPermissions_Call struct {
+	*mock.Call
+}
+
+// QueryPermissions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryPermissionOptions
+func (_e *MockService_Expecter) QueryPermissions(ctx interface{}, opt interface{}) *MockService_QueryPermissions_Call {
+	return &MockService_QueryPermissions_Call{Call: _e.mock.On("QueryPermissions", ctx, opt)}
+}
+
+func (_c *MockService_QueryPermissions_Call) Run(run func(ctx context.Context, opt *QueryPermissionOptions)) *MockService_QueryPermissions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryPermissionOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryPermissionOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_QueryPermissions_Call) Return(err error) *MockService_QueryPermissions_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_QueryPermissions_Call) RunAndReturn(run func(ctx context.Context, opt *QueryPermissionOptions) error) *MockService_QueryPermissions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryRoles provides a mock function for the type MockService
+func (_mock *MockService) QueryRoles(ctx context.Context, opt *QueryRoleOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryRoles")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryRoleOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_QueryRoles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryRoles'
+type MockService_QueryRoles_Call struct {
+	*mock.Call
+}
+
+// QueryRoles is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryRoleOptions
+func (_e *MockService_Expecter) QueryRoles(ctx interface{}, opt interface{}) *MockService_QueryRoles_Call {
+	return &MockService_QueryRoles_Call{Call: _e.mock.On("QueryRoles", ctx, opt)}
+}
+
+func (_c *MockService_QueryRoles_Call) Run(run func(ctx context.Context, opt *QueryRoleOptions)) *MockService_QueryRoles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryRoleOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryRoleOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_QueryRoles_Call) Return(err error) *MockService_QueryRoles_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_QueryRoles_Call) RunAndReturn(run func(ctx context.Context, opt *QueryRoleOptions) error) *MockService_QueryRoles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryServiceAccounts provides a mock function for the type MockService
+func (_mock *MockService) QueryServiceAccounts(ctx context.Context, opt *QueryServiceAccountOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryServiceAccounts")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryServiceAccountOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_QueryServiceAccounts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryServiceAccounts'
+type MockService_QueryServiceAccounts_Call struct {
+	*mock.Call
+}
+
+// QueryServiceAccounts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryServiceAccountOptions
+func (_e *MockService_Expecter) QueryServiceAccounts(ctx interface{}, opt interface{}) *MockService_QueryServiceAccounts_Call {
+	return &MockService_QueryServiceAccounts_Call{Call: _e.mock.On("QueryServiceAccounts", ctx, opt)}
+}
+
+func (_c *MockService_QueryServiceAccounts_Call) Run(run func(ctx context.Context, opt *QueryServiceAccountOptions)) *MockService_QueryServiceAccounts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryServiceAccountOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryServiceAccountOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_QueryServiceAccounts_Call) Return(err error) *MockService_QueryServiceAccounts_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_QueryServiceAccounts_Call) RunAndReturn(run func(ctx context.Context, opt *QueryServiceAccountOptions) error) *MockService_QueryServiceAccounts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryTeams provides a mock function for the type MockService
+func (_mock *MockService) QueryTeams(ctx context.Context, opt *QueryTeamOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryTeams")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryTeamOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_QueryTeams_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryTeams'
+type MockService_QueryTeams_Call struct {
+	*mock.Call
+}
+
+// QueryTeams is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryTeamOptions
+func (_e *MockService_Expecter) QueryTeams(ctx interface{}, opt interface{}) *MockService_QueryTeams_Call {
+	return &MockService_QueryTeams_Call{Call: _e.mock.On("QueryTeams", ctx, opt)}
+}
+
+func (_c *MockService_QueryTeams_Call) Run(run func(ctx context.Context, opt *QueryTeamOptions)) *MockService_QueryTeams_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryTeamOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryTeamOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_QueryTeams_Call) Return(err error) *MockService_QueryTeams_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_QueryTeams_Call) RunAndReturn(run func(ctx context.Context, opt *QueryTeamOptions) error) *MockService_QueryTeams_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryUsers provides a mock function for the type MockService
+func (_mock *MockService) QueryUsers(ctx context.Context, opt *QueryUserOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryUsers")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryUserOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_QueryUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryUsers'
+type MockService_QueryUsers_Call struct {
+	*mock.Call
+}
+
+// QueryUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryUserOptions
+func (_e *MockService_Expecter) QueryUsers(ctx interface{}, opt interface{}) *MockService_QueryUsers_Call {
+	return &MockService_QueryUsers_Call{Call: _e.mock.On("QueryUsers", ctx, opt)}
+}
+
+func (_c *MockService_QueryUsers_Call) Run(run func(ctx context.Context, opt *QueryUserOptions)) *MockService_QueryUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryUserOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryUserOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_QueryUsers_Call) Return(err error) *MockService_QueryUsers_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_QueryUsers_Call) RunAndReturn(run func(ctx context.Context, opt *QueryUserOptions) error) *MockService_QueryUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReconcileIntents provides a mock function for the type MockService
+func (_mock *MockService) ReconcileIntents(ctx context.Context) error {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReconcileIntents")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_ReconcileIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReconcileIntents'
+type MockService_ReconcileIntents_Call struct {
+	*mock.Call
+}
+
+// ReconcileIntents is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockService_Expecter) ReconcileIntents(ctx interface{}) *MockService_ReconcileIntents_Call {
+	return &MockService_ReconcileIntents_Call{Call: _e.mock.On("ReconcileIntents", ctx)}
+}
+
+func (_c *MockService_ReconcileIntents_Call) Run(run func(ctx context.Context)) *MockService_ReconcileIntents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_ReconcileIntents_Call) Return(err error) *MockService_ReconcileIntents_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_ReconcileIntents_Call) RunAndReturn(run func(ctx context.Context) error) *MockService_ReconcileIntents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RefreshToken provides a mock function for the type MockService
+func (_mock *MockService) RefreshToken(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	ret := _mock.Called(ctx, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefreshToken")
+	}
+
+	var r0 *TokenPair
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*TokenPair, error)); ok {
+		return returnFunc(ctx, refreshToken)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *TokenPair); ok {
+		r0 = returnFunc(ctx, refreshToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*TokenPair)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, refreshToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_RefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RefreshToken'
+type MockService_RefreshToken_Call struct {
+	*mock.Call
+}
+
+// RefreshToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - refreshToken string
+func (_e *MockService_Expecter) RefreshToken(ctx interface{}, refreshToken interface{}) *MockService_RefreshToken_Call {
+	return &MockService_RefreshToken_Call{Call: _e.mock.On("RefreshToken", ctx, refreshToken)}
+}
+
+func (_c *MockService_RefreshToken_Call) Run(run func(ctx context.Context, refreshToken string)) *MockService_RefreshToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_RefreshToken_Call) Return(tokenPair *TokenPair, err error) *MockService_RefreshToken_Call {
+	_c.Call.Return(tokenPair, err)
+	return _c
+}
+
+func (_c *MockService_RefreshToken_Call) RunAndReturn(run func(ctx context.Context, refreshToken string) (*TokenPair, error)) *MockService_RefreshToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RegenerateRecoveryCodes provides a mock function for the type MockService
+func (_mock *MockService) RegenerateRecoveryCodes(ctx context.Context, user *Claims, code string) ([]string, error) {
+	ret := _mock.Called(ctx, user, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegenerateRecoveryCodes")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string) ([]string, error)); ok {
+		return returnFunc(ctx, user, code)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string) []string); ok {
+		r0 = returnFunc(ctx, user, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *Claims, string) error); ok {
+		r1 = returnFunc(ctx, user, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_RegenerateRecoveryCodes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RegenerateRecoveryCodes'
+type MockService_RegenerateRecoveryCodes_Call struct {
+	*mock.Call
+}
+
+// RegenerateRecoveryCodes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user *Claims
+//   - code string
+func (_e *MockService_Expecter) RegenerateRecoveryCodes(ctx interface{}, user interface{}, code interface{}) *MockService_RegenerateRecoveryCodes_Call {
+	return &MockService_RegenerateRecoveryCodes_Call{Call: _e.mock.On("RegenerateRecoveryCodes", ctx, user, code)}
+}
+
+func (_c *MockService_RegenerateRecoveryCodes_Call) Run(run func(ctx context.Context, user *Claims, code string)) *MockService_RegenerateRecoveryCodes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_RegenerateRecoveryCodes_Call) Return(s []string, err error) *MockService_RegenerateRecoveryCodes_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockService_RegenerateRecoveryCodes_Call) RunAndReturn(run func(ctx context.Context, user *Claims, code string) ([]string, error)) *MockService_RegenerateRecoveryCodes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReloadSigningKeys provides a mock function for the type MockService
+func (_mock *MockService) ReloadSigningKeys(ctx context.Context) error {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReloadSigningKeys")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_ReloadSigningKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReloadSigningKeys'
+type MockService_ReloadSigningKeys_Call struct {
+	*mock.Call
+}
+
+// ReloadSigningKeys is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockService_Expecter) ReloadSigningKeys(ctx interface{}) *MockService_ReloadSigningKeys_Call {
+	return &MockService_ReloadSigningKeys_Call{Call: _e.mock.On("ReloadSigningKeys", ctx)}
+}
+
+func (_c *MockService_ReloadSigningKeys_Call) Run(run func(ctx context.Context)) *MockService_ReloadSigningKeys_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_ReloadSigningKeys_Call) Return(err error) *MockService_ReloadSigningKeys_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_ReloadSigningKeys_Call) RunAndReturn(run func(ctx context.Context) error) *MockService_ReloadSigningKeys_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetPassword provides a mock function for the type MockService
+func (_mock *MockService) ResetPassword(ctx context.Context, operator *Claims, id string, newPassword string) error {
+	ret := _mock.Called(ctx, operator, id, newPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, string) error); ok {
+		r0 = returnFunc(ctx, operator, id, newPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_ResetPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetPassword'
+type MockService_ResetPassword_Call struct {
+	*mock.Call
+}
+
+// ResetPassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operator *Claims
+//   - id string
+//   - newPassword string
+func (_e *MockService_Expecter) ResetPassword(ctx interface{}, operator interface{}, id interface{}, newPassword interface{}) *MockService_ResetPassword_Call {
+	return &MockService_ResetPassword_Call{Call: _e.mock.On("ResetPassword", ctx, operator, id, newPassword)}
+}
+
+func (_c *MockService_ResetPassword_Call) Run(run func(ctx context.Context, operator *Claims, id string, newPassword string)) *MockService_ResetPassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_ResetPassword_Call) Return(err error) *MockService_ResetPassword_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_ResetPassword_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, id string, newPassword string) error) *MockService_ResetPassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetTwoFactor provides a mock function for the type MockService
+func (_mock *MockService) ResetTwoFactor(ctx context.Context, operator *Claims, id string) error {
+	ret := _mock.Called(ctx, operator, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetTwoFactor")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string) error); ok {
+		r0 = returnFunc(ctx, operator, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_ResetTwoFactor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetTwoFactor'
+type MockService_ResetTwoFactor_Call struct {
+	*mock.Call
+}
+
+// ResetTwoFactor is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operator *Claims
+//   - id string
+func (_e *MockService_Expecter) ResetTwoFactor(ctx interface{}, operator interface{}, id interface{}) *MockService_ResetTwoFactor_Call {
+	return &MockService_ResetTwoFactor_Call{Call: _e.mock.On("ResetTwoFactor", ctx, operator, id)}
+}
+
+func (_c *MockService_ResetTwoFactor_Call) Run(run func(ctx context.Context, operator *Claims, id string)) *MockService_ResetTwoFactor_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -1597,47 +7072,47 @@ func (_c *MockService_DeleteRole_Call) Run(run func(ctx context.Context, operato
 	return _c
 }
 
-func (_c *MockService_DeleteRole_Call) Return(err error) *MockService_DeleteRole_Call {
+func (_c *MockService_ResetTwoFactor_Call) Return(err error) *MockService_ResetTwoFactor_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_DeleteRole_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, roleID string) error) *MockService_DeleteRole_Call {
+func (_c *MockService_ResetTwoFactor_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, id string) error) *MockService_ResetTwoFactor_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteScheduleIntents provides a mock function for the type MockService
-func (_mock *MockService) DeleteScheduleIntents(ctx context.Context, operator *Claims, intentIDs []string) error {
-	ret := _mock.Called(ctx, operator, intentIDs)
+// RevokeAPIKey provides a mock function for the type MockService
+func (_mock *MockService) RevokeAPIKey(ctx context.Context, operator *Claims, keyID string) error {
+	ret := _mock.Called(ctx, operator, keyID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteScheduleIntents")
+		panic("no return value specified for RevokeAPIKey")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, []string) error); ok {
-		r0 = returnFunc(ctx, operator, intentIDs)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string) error); ok {
+		r0 = returnFunc(ctx, operator, keyID)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockService_DeleteScheduleIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteScheduleIntents'
-type MockService_DeleteScheduleIntents_Call struct {
+// MockService_RevokeAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeAPIKey'
+type MockService_RevokeAPIKey_Call struct {
 	*mock.Call
 }
 
-// DeleteScheduleIntents is a helper method to define mock.On call
+// RevokeAPIKey is a helper method to define mock.On call
 //   - ctx context.Context
 //   - operator *Claims
-//   - intentIDs []string
-func (_e *MockService_Expecter) DeleteScheduleIntents(ctx interface{}, operator interface{}, intentIDs interface{}) *MockService_DeleteScheduleIntents_Call {
-	return &MockService_DeleteScheduleIntents_Call{Call: _e.mock.On("DeleteScheduleIntents", ctx, operator, intentIDs)}
+//   - keyID string
+func (_e *MockService_Expecter) RevokeAPIKey(ctx interface{}, operator interface{}, keyID interface{}) *MockService_RevokeAPIKey_Call {
+	return &MockService_RevokeAPIKey_Call{Call: _e.mock.On("RevokeAPIKey", ctx, operator, keyID)}
 }
 
-func (_c *MockService_DeleteScheduleIntents_Call) Run(run func(ctx context.Context, operator *Claims, intentIDs []string)) *MockService_DeleteScheduleIntents_Call {
+func (_c *MockService_RevokeAPIKey_Call) Run(run func(ctx context.Context, operator *Claims, keyID string)) *MockService_RevokeAPIKey_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -1647,9 +7122,9 @@ func (_c *MockService_DeleteScheduleIntents_Call) Run(run func(ctx context.Conte
 		if args[1] != nil {
 			arg1 = args[1].(*Claims)
 		}
-		var arg2 []string
+		var arg2 string
 		if args[2] != nil {
-			arg2 = args[2].([]string)
+			arg2 = args[2].(string)
 		}
 		run(
 			arg0,
@@ -1660,47 +7135,47 @@ func (_c *MockService_DeleteScheduleIntents_Call) Run(run func(ctx context.Conte
 	return _c
 }
 
-func (_c *MockService_DeleteScheduleIntents_Call) Return(err error) *MockService_DeleteScheduleIntents_Call {
+func (_c *MockService_RevokeAPIKey_Call) Return(err error) *MockService_RevokeAPIKey_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_DeleteScheduleIntents_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, intentIDs []string) error) *MockService_DeleteScheduleIntents_Call {
+func (_c *MockService_RevokeAPIKey_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, keyID string) error) *MockService_RevokeAPIKey_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteScheduleStrategy provides a mock function for the type MockService
-func (_mock *MockService) DeleteScheduleStrategy(ctx context.Context, operator *Claims, strategyID string) error {
-	ret := _mock.Called(ctx, operator, strategyID)
+// RevokeUserSessions provides a mock function for the type MockService
+func (_mock *MockService) RevokeUserSessions(ctx context.Context, operator *Claims, id string) error {
+	ret := _mock.Called(ctx, operator, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteScheduleStrategy")
+		panic("no return value specified for RevokeUserSessions")
 	}
 
 	var r0 error
 	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string) error); ok {
-		r0 = returnFunc(ctx, operator, strategyID)
+		r0 = returnFunc(ctx, operator, id)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockService_DeleteScheduleStrategy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteScheduleStrategy'
-type MockService_DeleteScheduleStrategy_Call struct {
+// MockService_RevokeUserSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeUserSessions'
+type MockService_RevokeUserSessions_Call struct {
 	*mock.Call
 }
 
-// DeleteScheduleStrategy is a helper method to define mock.On call
+// RevokeUserSessions is a helper method to define mock.On call
 //   - ctx context.Context
 //   - operator *Claims
-//   - strategyID string
-func (_e *MockService_Expecter) DeleteScheduleStrategy(ctx interface{}, operator interface{}, strategyID interface{}) *MockService_DeleteScheduleStrategy_Call {
-	return &MockService_DeleteScheduleStrategy_Call{Call: _e.mock.On("DeleteScheduleStrategy", ctx, operator, strategyID)}
+//   - id string
+func (_e *MockService_Expecter) RevokeUserSessions(ctx interface{}, operator interface{}, id interface{}) *MockService_RevokeUserSessions_Call {
+	return &MockService_RevokeUserSessions_Call{Call: _e.mock.On("RevokeUserSessions", ctx, operator, id)}
 }
 
-func (_c *MockService_DeleteScheduleStrategy_Call) Run(run func(ctx context.Context, operator *Claims, strategyID string)) *MockService_DeleteScheduleStrategy_Call {
+func (_c *MockService_RevokeUserSessions_Call) Run(run func(ctx context.Context, operator *Claims, id string)) *MockService_RevokeUserSessions_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -1723,65 +7198,54 @@ func (_c *MockService_DeleteScheduleStrategy_Call) Run(run func(ctx context.Cont
 	return _c
 }
 
-func (_c *MockService_DeleteScheduleStrategy_Call) Return(err error) *MockService_DeleteScheduleStrategy_Call {
+func (_c *MockService_RevokeUserSessions_Call) Return(err error) *MockService_RevokeUserSessions_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_DeleteScheduleStrategy_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, strategyID string) error) *MockService_DeleteScheduleStrategy_Call {
+func (_c *MockService_RevokeUserSessions_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, id string) error) *MockService_RevokeUserSessions_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetPodPIDMapping provides a mock function for the type MockService
-func (_mock *MockService) GetPodPIDMapping(ctx context.Context, nodeID string) (*PodPIDMappingResponse, error) {
-	ret := _mock.Called(ctx, nodeID)
+// SetMetricSampleRetention provides a mock function for the type MockService
+func (_mock *MockService) SetMetricSampleRetention(ctx context.Context, retention time.Duration) error {
+	ret := _mock.Called(ctx, retention)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetPodPIDMapping")
+		panic("no return value specified for SetMetricSampleRetention")
 	}
 
-	var r0 *PodPIDMappingResponse
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*PodPIDMappingResponse, error)); ok {
-		return returnFunc(ctx, nodeID)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *PodPIDMappingResponse); ok {
-		r0 = returnFunc(ctx, nodeID)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*PodPIDMappingResponse)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, nodeID)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Duration) error); ok {
+		r0 = returnFunc(ctx, retention)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockService_GetPodPIDMapping_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPodPIDMapping'
-type MockService_GetPodPIDMapping_Call struct {
+// MockService_SetMetricSampleRetention_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetMetricSampleRetention'
+type MockService_SetMetricSampleRetention_Call struct {
 	*mock.Call
 }
 
-// GetPodPIDMapping is a helper method to define mock.On call
+// SetMetricSampleRetention is a helper method to define mock.On call
 //   - ctx context.Context
-//   - nodeID string
-func (_e *MockService_Expecter) GetPodPIDMapping(ctx interface{}, nodeID interface{}) *MockService_GetPodPIDMapping_Call {
-	return &MockService_GetPodPIDMapping_Call{Call: _e.mock.On("GetPodPIDMapping", ctx, nodeID)}
+//   - retention time.Duration
+func (_e *MockService_Expecter) SetMetricSampleRetention(ctx interface{}, retention interface{}) *MockService_SetMetricSampleRetention_Call {
+	return &MockService_SetMetricSampleRetention_Call{Call: _e.mock.On("SetMetricSampleRetention", ctx, retention)}
 }
 
-func (_c *MockService_GetPodPIDMapping_Call) Run(run func(ctx context.Context, nodeID string)) *MockService_GetPodPIDMapping_Call {
+func (_c *MockService_SetMetricSampleRetention_Call) Run(run func(ctx context.Context, retention time.Duration)) *MockService_SetMetricSampleRetention_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
+		var arg1 time.Duration
 		if args[1] != nil {
-			arg1 = args[1].(string)
+			arg1 = args[1].(time.Duration)
 		}
 		run(
 			arg0,
@@ -1791,56 +7255,45 @@ func (_c *MockService_GetPodPIDMapping_Call) Run(run func(ctx context.Context, n
 	return _c
 }
 
-func (_c *MockService_GetPodPIDMapping_Call) Return(podPIDMappingResponse *PodPIDMappingResponse, err error) *MockService_GetPodPIDMapping_Call {
-	_c.Call.Return(podPIDMappingResponse, err)
+func (_c *MockService_SetMetricSampleRetention_Call) Return(err error) *MockService_SetMetricSampleRetention_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_GetPodPIDMapping_Call) RunAndReturn(run func(ctx context.Context, nodeID string) (*PodPIDMappingResponse, error)) *MockService_GetPodPIDMapping_Call {
+func (_c *MockService_SetMetricSampleRetention_Call) RunAndReturn(run func(ctx context.Context, retention time.Duration) error) *MockService_SetMetricSampleRetention_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListNodes provides a mock function for the type MockService
-func (_mock *MockService) ListNodes(ctx context.Context) ([]*Node, error) {
+// SyncAuthCache provides a mock function for the type MockService
+func (_mock *MockService) SyncAuthCache(ctx context.Context) error {
 	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListNodes")
+		panic("no return value specified for SyncAuthCache")
 	}
 
-	var r0 []*Node
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]*Node, error)); ok {
-		return returnFunc(ctx)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) []*Node); ok {
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
 		r0 = returnFunc(ctx)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*Node)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = returnFunc(ctx)
-	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockService_ListNodes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListNodes'
-type MockService_ListNodes_Call struct {
+// MockService_SyncAuthCache_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SyncAuthCache'
+type MockService_SyncAuthCache_Call struct {
 	*mock.Call
 }
 
-// ListNodes is a helper method to define mock.On call
+// SyncAuthCache is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *MockService_Expecter) ListNodes(ctx interface{}) *MockService_ListNodes_Call {
-	return &MockService_ListNodes_Call{Call: _e.mock.On("ListNodes", ctx)}
+func (_e *MockService_Expecter) SyncAuthCache(ctx interface{}) *MockService_SyncAuthCache_Call {
+	return &MockService_SyncAuthCache_Call{Call: _e.mock.On("SyncAuthCache", ctx)}
 }
 
-func (_c *MockService_ListNodes_Call) Run(run func(ctx context.Context)) *MockService_ListNodes_Call {
+func (_c *MockService_SyncAuthCache_Call) Run(run func(ctx context.Context)) *MockService_SyncAuthCache_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -1853,456 +7306,525 @@ func (_c *MockService_ListNodes_Call) Run(run func(ctx context.Context)) *MockSe
 	return _c
 }
 
-func (_c *MockService_ListNodes_Call) Return(nodes []*Node, err error) *MockService_ListNodes_Call {
-	_c.Call.Return(nodes, err)
+func (_c *MockService_SyncAuthCache_Call) Return(err error) *MockService_SyncAuthCache_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_ListNodes_Call) RunAndReturn(run func(ctx context.Context) ([]*Node, error)) *MockService_ListNodes_Call {
+func (_c *MockService_SyncAuthCache_Call) RunAndReturn(run func(ctx context.Context) error) *MockService_SyncAuthCache_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListScheduleIntents provides a mock function for the type MockService
-func (_mock *MockService) ListScheduleIntents(ctx context.Context, filterOpts *QueryIntentOptions) error {
-	ret := _mock.Called(ctx, filterOpts)
+// TransferScheduleStrategy provides a mock function for the type MockService
+func (_mock *MockService) TransferScheduleStrategy(ctx context.Context, operator *Claims, strategyID string, opt TransferStrategyOptions) error {
+	ret := _mock.Called(ctx, operator, strategyID, opt)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListScheduleIntents")
+		panic("no return value specified for TransferScheduleStrategy")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryIntentOptions) error); ok {
-		r0 = returnFunc(ctx, filterOpts)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, TransferStrategyOptions) error); ok {
+		r0 = returnFunc(ctx, operator, strategyID, opt)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockService_ListScheduleIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListScheduleIntents'
-type MockService_ListScheduleIntents_Call struct {
+// MockService_TransferScheduleStrategy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TransferScheduleStrategy'
+type MockService_TransferScheduleStrategy_Call struct {
 	*mock.Call
 }
 
-// ListScheduleIntents is a helper method to define mock.On call
+// TransferScheduleStrategy is a helper method to define mock.On call
 //   - ctx context.Context
-//   - filterOpts *QueryIntentOptions
-func (_e *MockService_Expecter) ListScheduleIntents(ctx interface{}, filterOpts interface{}) *MockService_ListScheduleIntents_Call {
-	return &MockService_ListScheduleIntents_Call{Call: _e.mock.On("ListScheduleIntents", ctx, filterOpts)}
+//   - operator *Claims
+//   - strategyID string
+//   - opt TransferStrategyOptions
+func (_e *MockService_Expecter) TransferScheduleStrategy(ctx interface{}, operator interface{}, strategyID interface{}, opt interface{}) *MockService_TransferScheduleStrategy_Call {
+	return &MockService_TransferScheduleStrategy_Call{Call: _e.mock.On("TransferScheduleStrategy", ctx, operator, strategyID, opt)}
 }
 
-func (_c *MockService_ListScheduleIntents_Call) Run(run func(ctx context.Context, filterOpts *QueryIntentOptions)) *MockService_ListScheduleIntents_Call {
+func (_c *MockService_TransferScheduleStrategy_Call) Run(run func(ctx context.Context, operator *Claims, strategyID string, opt TransferStrategyOptions)) *MockService_TransferScheduleStrategy_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *QueryIntentOptions
+		var arg1 *Claims
 		if args[1] != nil {
-			arg1 = args[1].(*QueryIntentOptions)
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 TransferStrategyOptions
+		if args[3] != nil {
+			arg3 = args[3].(TransferStrategyOptions)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
+			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_ListScheduleIntents_Call) Return(err error) *MockService_ListScheduleIntents_Call {
+func (_c *MockService_TransferScheduleStrategy_Call) Return(err error) *MockService_TransferScheduleStrategy_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_ListScheduleIntents_Call) RunAndReturn(run func(ctx context.Context, filterOpts *QueryIntentOptions) error) *MockService_ListScheduleIntents_Call {
+func (_c *MockService_TransferScheduleStrategy_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, strategyID string, opt TransferStrategyOptions) error) *MockService_TransferScheduleStrategy_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListScheduleStrategies provides a mock function for the type MockService
-func (_mock *MockService) ListScheduleStrategies(ctx context.Context, filterOpts *QueryStrategyOptions) error {
-	ret := _mock.Called(ctx, filterOpts)
+// UnlockUser provides a mock function for the type MockService
+func (_mock *MockService) UnlockUser(ctx context.Context, operator *Claims, id string) error {
+	ret := _mock.Called(ctx, operator, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListScheduleStrategies")
+		panic("no return value specified for UnlockUser")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryStrategyOptions) error); ok {
-		r0 = returnFunc(ctx, filterOpts)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string) error); ok {
+		r0 = returnFunc(ctx, operator, id)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockService_ListScheduleStrategies_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListScheduleStrategies'
-type MockService_ListScheduleStrategies_Call struct {
+// MockService_UnlockUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UnlockUser'
+type MockService_UnlockUser_Call struct {
 	*mock.Call
 }
 
-// ListScheduleStrategies is a helper method to define mock.On call
+// UnlockUser is a helper method to define mock.On call
 //   - ctx context.Context
-//   - filterOpts *QueryStrategyOptions
-func (_e *MockService_Expecter) ListScheduleStrategies(ctx interface{}, filterOpts interface{}) *MockService_ListScheduleStrategies_Call {
-	return &MockService_ListScheduleStrategies_Call{Call: _e.mock.On("ListScheduleStrategies", ctx, filterOpts)}
+//   - operator *Claims
+//   - id string
+func (_e *MockService_Expecter) UnlockUser(ctx interface{}, operator interface{}, id interface{}) *MockService_UnlockUser_Call {
+	return &MockService_UnlockUser_Call{Call: _e.mock.On("UnlockUser", ctx, operator, id)}
 }
 
-func (_c *MockService_ListScheduleStrategies_Call) Run(run func(ctx context.Context, filterOpts *QueryStrategyOptions)) *MockService_ListScheduleStrategies_Call {
+func (_c *MockService_UnlockUser_Call) Run(run func(ctx context.Context, operator *Claims, id string)) *MockService_UnlockUser_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *QueryStrategyOptions
+		var arg1 *Claims
 		if args[1] != nil {
-			arg1 = args[1].(*QueryStrategyOptions)
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_ListScheduleStrategies_Call) Return(err error) *MockService_ListScheduleStrategies_Call {
+func (_c *MockService_UnlockUser_Call) Return(err error) *MockService_UnlockUser_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_ListScheduleStrategies_Call) RunAndReturn(run func(ctx context.Context, filterOpts *QueryStrategyOptions) error) *MockService_ListScheduleStrategies_Call {
+func (_c *MockService_UnlockUser_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, id string) error) *MockService_UnlockUser_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Login provides a mock function for the type MockService
-func (_mock *MockService) Login(ctx context.Context, email string, password string) (string, error) {
-	ret := _mock.Called(ctx, email, password)
+// UpdateAlertRule provides a mock function for the type MockService
+func (_mock *MockService) UpdateAlertRule(ctx context.Context, operator *Claims, ruleID string, rule *AlertRule) error {
+	ret := _mock.Called(ctx, operator, ruleID, rule)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Login")
+		panic("no return value specified for UpdateAlertRule")
 	}
 
-	var r0 string
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
-		return returnFunc(ctx, email, password)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
-		r0 = returnFunc(ctx, email, password)
-	} else {
-		r0 = ret.Get(0).(string)
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = returnFunc(ctx, email, password)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, *AlertRule) error); ok {
+		r0 = returnFunc(ctx, operator, ruleID, rule)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockService_Login_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Login'
-type MockService_Login_Call struct {
+// MockService_UpdateAlertRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateAlertRule'
+type MockService_UpdateAlertRule_Call struct {
 	*mock.Call
 }
 
-// Login is a helper method to define mock.On call
+// UpdateAlertRule is a helper method to define mock.On call
 //   - ctx context.Context
-//   - email string
-//   - password string
-func (_e *MockService_Expecter) Login(ctx interface{}, email interface{}, password interface{}) *MockService_Login_Call {
-	return &MockService_Login_Call{Call: _e.mock.On("Login", ctx, email, password)}
+//   - operator *Claims
+//   - ruleID string
+//   - rule *AlertRule
+func (_e *MockService_Expecter) UpdateAlertRule(ctx interface{}, operator interface{}, ruleID interface{}, rule interface{}) *MockService_UpdateAlertRule_Call {
+	return &MockService_UpdateAlertRule_Call{Call: _e.mock.On("UpdateAlertRule", ctx, operator, ruleID, rule)}
 }
 
-func (_c *MockService_Login_Call) Run(run func(ctx context.Context, email string, password string)) *MockService_Login_Call {
+func (_c *MockService_UpdateAlertRule_Call) Run(run func(ctx context.Context, operator *Claims, ruleID string, rule *AlertRule)) *MockService_UpdateAlertRule_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
+		var arg1 *Claims
 		if args[1] != nil {
-			arg1 = args[1].(string)
+			arg1 = args[1].(*Claims)
 		}
 		var arg2 string
 		if args[2] != nil {
 			arg2 = args[2].(string)
 		}
+		var arg3 *AlertRule
+		if args[3] != nil {
+			arg3 = args[3].(*AlertRule)
+		}
 		run(
 			arg0,
 			arg1,
 			arg2,
+			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_Login_Call) Return(token string, err error) *MockService_Login_Call {
-	_c.Call.Return(token, err)
+func (_c *MockService_UpdateAlertRule_Call) Return(err error) *MockService_UpdateAlertRule_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_Login_Call) RunAndReturn(run func(ctx context.Context, email string, password string) (string, error)) *MockService_Login_Call {
+func (_c *MockService_UpdateAlertRule_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, ruleID string, rule *AlertRule) error) *MockService_UpdateAlertRule_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// QueryPermissions provides a mock function for the type MockService
-func (_mock *MockService) QueryPermissions(ctx context.Context, opt *QueryPermissionOptions) error {
-	ret := _mock.Called(ctx, opt)
+// UpdateRole provides a mock function for the type MockService
+func (_mock *MockService) UpdateRole(ctx context.Context, operator *Claims, roleID string, opt UpdateRoleOptions) error {
+	ret := _mock.Called(ctx, operator, roleID, opt)
 
 	if len(ret) == 0 {
-		panic("no return value specified for QueryPermissions")
+		panic("no return value specified for UpdateRole")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryPermissionOptions) error); ok {
-		r0 = returnFunc(ctx, opt)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, UpdateRoleOptions) error); ok {
+		r0 = returnFunc(ctx, operator, roleID, opt)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockService_QueryPermissions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryPermissions'
-type MockService_QueryPermissions_Call struct {
+// MockService_UpdateRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateRole'
+type MockService_UpdateRole_Call struct {
 	*mock.Call
 }
 
-// QueryPermissions is a helper method to define mock.On call
+// UpdateRole is a helper method to define mock.On call
 //   - ctx context.Context
-//   - opt *QueryPermissionOptions
-func (_e *MockService_Expecter) QueryPermissions(ctx interface{}, opt interface{}) *MockService_QueryPermissions_Call {
-	return &MockService_QueryPermissions_Call{Call: _e.mock.On("QueryPermissions", ctx, opt)}
+//   - operator *Claims
+//   - roleID string
+//   - opt UpdateRoleOptions
+func (_e *MockService_Expecter) UpdateRole(ctx interface{}, operator interface{}, roleID interface{}, opt interface{}) *MockService_UpdateRole_Call {
+	return &MockService_UpdateRole_Call{Call: _e.mock.On("UpdateRole", ctx, operator, roleID, opt)}
 }
 
-func (_c *MockService_QueryPermissions_Call) Run(run func(ctx context.Context, opt *QueryPermissionOptions)) *MockService_QueryPermissions_Call {
+func (_c *MockService_UpdateRole_Call) Run(run func(ctx context.Context, operator *Claims, roleID string, opt UpdateRoleOptions)) *MockService_UpdateRole_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *QueryPermissionOptions
+		var arg1 *Claims
 		if args[1] != nil {
-			arg1 = args[1].(*QueryPermissionOptions)
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 UpdateRoleOptions
+		if args[3] != nil {
+			arg3 = args[3].(UpdateRoleOptions)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
+			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_QueryPermissions_Call) Return(err error) *MockService_QueryPermissions_Call {
+func (_c *MockService_UpdateRole_Call) Return(err error) *MockService_UpdateRole_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_QueryPermissions_Call) RunAndReturn(run func(ctx context.Context, opt *QueryPermissionOptions) error) *MockService_QueryPermissions_Call {
+func (_c *MockService_UpdateRole_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, roleID string, opt UpdateRoleOptions) error) *MockService_UpdateRole_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// QueryRoles provides a mock function for the type MockService
-func (_mock *MockService) QueryRoles(ctx context.Context, opt *QueryRoleOptions) error {
-	ret := _mock.Called(ctx, opt)
+// UpdateScheduleStrategy provides a mock function for the type MockService
+func (_mock *MockService) UpdateScheduleStrategy(ctx context.Context, operator *Claims, strategyID string, strategy *ScheduleStrategy) error {
+	ret := _mock.Called(ctx, operator, strategyID, strategy)
 
 	if len(ret) == 0 {
-		panic("no return value specified for QueryRoles")
+		panic("no return value specified for UpdateScheduleStrategy")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryRoleOptions) error); ok {
-		r0 = returnFunc(ctx, opt)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, *ScheduleStrategy) error); ok {
+		r0 = returnFunc(ctx, operator, strategyID, strategy)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockService_QueryRoles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryRoles'
-type MockService_QueryRoles_Call struct {
+// MockService_UpdateScheduleStrategy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateScheduleStrategy'
+type MockService_UpdateScheduleStrategy_Call struct {
 	*mock.Call
 }
 
-// QueryRoles is a helper method to define mock.On call
+// UpdateScheduleStrategy is a helper method to define mock.On call
 //   - ctx context.Context
-//   - opt *QueryRoleOptions
-func (_e *MockService_Expecter) QueryRoles(ctx interface{}, opt interface{}) *MockService_QueryRoles_Call {
-	return &MockService_QueryRoles_Call{Call: _e.mock.On("QueryRoles", ctx, opt)}
+//   - operator *Claims
+//   - strategyID string
+//   - strategy *ScheduleStrategy
+func (_e *MockService_Expecter) UpdateScheduleStrategy(ctx interface{}, operator interface{}, strategyID interface{}, strategy interface{}) *MockService_UpdateScheduleStrategy_Call {
+	return &MockService_UpdateScheduleStrategy_Call{Call: _e.mock.On("UpdateScheduleStrategy", ctx, operator, strategyID, strategy)}
 }
 
-func (_c *MockService_QueryRoles_Call) Run(run func(ctx context.Context, opt *QueryRoleOptions)) *MockService_QueryRoles_Call {
+func (_c *MockService_UpdateScheduleStrategy_Call) Run(run func(ctx context.Context, operator *Claims, strategyID string, strategy *ScheduleStrategy)) *MockService_UpdateScheduleStrategy_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *QueryRoleOptions
+		var arg1 *Claims
 		if args[1] != nil {
-			arg1 = args[1].(*QueryRoleOptions)
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 *ScheduleStrategy
+		if args[3] != nil {
+			arg3 = args[3].(*ScheduleStrategy)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
+			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_QueryRoles_Call) Return(err error) *MockService_QueryRoles_Call {
+func (_c *MockService_UpdateScheduleStrategy_Call) Return(err error) *MockService_UpdateScheduleStrategy_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_QueryRoles_Call) RunAndReturn(run func(ctx context.Context, opt *QueryRoleOptions) error) *MockService_QueryRoles_Call {
+func (_c *MockService_UpdateScheduleStrategy_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, strategyID string, strategy *ScheduleStrategy) error) *MockService_UpdateScheduleStrategy_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// QueryUsers provides a mock function for the type MockService
-func (_mock *MockService) QueryUsers(ctx context.Context, opt *QueryUserOptions) error {
-	ret := _mock.Called(ctx, opt)
+// UpdateServiceAccount provides a mock function for the type MockService
+func (_mock *MockService) UpdateServiceAccount(ctx context.Context, operator *Claims, accountID string, opt UpdateServiceAccountOptions) error {
+	ret := _mock.Called(ctx, operator, accountID, opt)
 
 	if len(ret) == 0 {
-		panic("no return value specified for QueryUsers")
+		panic("no return value specified for UpdateServiceAccount")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryUserOptions) error); ok {
-		r0 = returnFunc(ctx, opt)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, UpdateServiceAccountOptions) error); ok {
+		r0 = returnFunc(ctx, operator, accountID, opt)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockService_QueryUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryUsers'
-type MockService_QueryUsers_Call struct {
+// MockService_UpdateServiceAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateServiceAccount'
+type MockService_UpdateServiceAccount_Call struct {
 	*mock.Call
 }
 
-// QueryUsers is a helper method to define mock.On call
+// UpdateServiceAccount is a helper method to define mock.On call
 //   - ctx context.Context
-//   - opt *QueryUserOptions
-func (_e *MockService_Expecter) QueryUsers(ctx interface{}, opt interface{}) *MockService_QueryUsers_Call {
-	return &MockService_QueryUsers_Call{Call: _e.mock.On("QueryUsers", ctx, opt)}
+//   - operator *Claims
+//   - accountID string
+//   - opt UpdateServiceAccountOptions
+func (_e *MockService_Expecter) UpdateServiceAccount(ctx interface{}, operator interface{}, accountID interface{}, opt interface{}) *MockService_UpdateServiceAccount_Call {
+	return &MockService_UpdateServiceAccount_Call{Call: _e.mock.On("UpdateServiceAccount", ctx, operator, accountID, opt)}
 }
 
-func (_c *MockService_QueryUsers_Call) Run(run func(ctx context.Context, opt *QueryUserOptions)) *MockService_QueryUsers_Call {
+func (_c *MockService_UpdateServiceAccount_Call) Run(run func(ctx context.Context, operator *Claims, accountID string, opt UpdateServiceAccountOptions)) *MockService_UpdateServiceAccount_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *QueryUserOptions
+		var arg1 *Claims
 		if args[1] != nil {
-			arg1 = args[1].(*QueryUserOptions)
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 UpdateServiceAccountOptions
+		if args[3] != nil {
+			arg3 = args[3].(UpdateServiceAccountOptions)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
+			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_QueryUsers_Call) Return(err error) *MockService_QueryUsers_Call {
+func (_c *MockService_UpdateServiceAccount_Call) Return(err error) *MockService_UpdateServiceAccount_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_QueryUsers_Call) RunAndReturn(run func(ctx context.Context, opt *QueryUserOptions) error) *MockService_QueryUsers_Call {
+func (_c *MockService_UpdateServiceAccount_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, accountID string, opt UpdateServiceAccountOptions) error) *MockService_UpdateServiceAccount_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ReconcileIntents provides a mock function for the type MockService
-func (_mock *MockService) ReconcileIntents(ctx context.Context) error {
-	ret := _mock.Called(ctx)
+// UpdateTeam provides a mock function for the type MockService
+func (_mock *MockService) UpdateTeam(ctx context.Context, operator *Claims, teamID string, opt UpdateTeamOptions) error {
+	ret := _mock.Called(ctx, operator, teamID, opt)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ReconcileIntents")
+		panic("no return value specified for UpdateTeam")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
-		r0 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, UpdateTeamOptions) error); ok {
+		r0 = returnFunc(ctx, operator, teamID, opt)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockService_ReconcileIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReconcileIntents'
-type MockService_ReconcileIntents_Call struct {
+// MockService_UpdateTeam_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateTeam'
+type MockService_UpdateTeam_Call struct {
 	*mock.Call
 }
 
-// ReconcileIntents is a helper method to define mock.On call
+// UpdateTeam is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *MockService_Expecter) ReconcileIntents(ctx interface{}) *MockService_ReconcileIntents_Call {
-	return &MockService_ReconcileIntents_Call{Call: _e.mock.On("ReconcileIntents", ctx)}
+//   - operator *Claims
+//   - teamID string
+//   - opt UpdateTeamOptions
+func (_e *MockService_Expecter) UpdateTeam(ctx interface{}, operator interface{}, teamID interface{}, opt interface{}) *MockService_UpdateTeam_Call {
+	return &MockService_UpdateTeam_Call{Call: _e.mock.On("UpdateTeam", ctx, operator, teamID, opt)}
 }
 
-func (_c *MockService_ReconcileIntents_Call) Run(run func(ctx context.Context)) *MockService_ReconcileIntents_Call {
+func (_c *MockService_UpdateTeam_Call) Run(run func(ctx context.Context, operator *Claims, teamID string, opt UpdateTeamOptions)) *MockService_UpdateTeam_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 UpdateTeamOptions
+		if args[3] != nil {
+			arg3 = args[3].(UpdateTeamOptions)
+		}
 		run(
 			arg0,
+			arg1,
+			arg2,
+			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_ReconcileIntents_Call) Return(err error) *MockService_ReconcileIntents_Call {
+func (_c *MockService_UpdateTeam_Call) Return(err error) *MockService_UpdateTeam_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_ReconcileIntents_Call) RunAndReturn(run func(ctx context.Context) error) *MockService_ReconcileIntents_Call {
+func (_c *MockService_UpdateTeam_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, teamID string, opt UpdateTeamOptions) error) *MockService_UpdateTeam_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ResetPassword provides a mock function for the type MockService
-func (_mock *MockService) ResetPassword(ctx context.Context, operator *Claims, id string, newPassword string) error {
-	ret := _mock.Called(ctx, operator, id, newPassword)
+// UpdateUserPermissions provides a mock function for the type MockService
+func (_mock *MockService) UpdateUserPermissions(ctx context.Context, operator *Claims, id string, opt UpdateUserPermissionsOptions) error {
+	ret := _mock.Called(ctx, operator, id, opt)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ResetPassword")
+		panic("no return value specified for UpdateUserPermissions")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, string) error); ok {
-		r0 = returnFunc(ctx, operator, id, newPassword)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, UpdateUserPermissionsOptions) error); ok {
+		r0 = returnFunc(ctx, operator, id, opt)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockService_ResetPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetPassword'
-type MockService_ResetPassword_Call struct {
+// MockService_UpdateUserPermissions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUserPermissions'
+type MockService_UpdateUserPermissions_Call struct {
 	*mock.Call
 }
 
-// ResetPassword is a helper method to define mock.On call
+// UpdateUserPermissions is a helper method to define mock.On call
 //   - ctx context.Context
 //   - operator *Claims
 //   - id string
-//   - newPassword string
-func (_e *MockService_Expecter) ResetPassword(ctx interface{}, operator interface{}, id interface{}, newPassword interface{}) *MockService_ResetPassword_Call {
-	return &MockService_ResetPassword_Call{Call: _e.mock.On("ResetPassword", ctx, operator, id, newPassword)}
+//   - opt UpdateUserPermissionsOptions
+func (_e *MockService_Expecter) UpdateUserPermissions(ctx interface{}, operator interface{}, id interface{}, opt interface{}) *MockService_UpdateUserPermissions_Call {
+	return &MockService_UpdateUserPermissions_Call{Call: _e.mock.On("UpdateUserPermissions", ctx, operator, id, opt)}
 }
 
-func (_c *MockService_ResetPassword_Call) Run(run func(ctx context.Context, operator *Claims, id string, newPassword string)) *MockService_ResetPassword_Call {
+func (_c *MockService_UpdateUserPermissions_Call) Run(run func(ctx context.Context, operator *Claims, id string, opt UpdateUserPermissionsOptions)) *MockService_UpdateUserPermissions_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -2316,9 +7838,9 @@ func (_c *MockService_ResetPassword_Call) Run(run func(ctx context.Context, oper
 		if args[2] != nil {
 			arg2 = args[2].(string)
 		}
-		var arg3 string
+		var arg3 UpdateUserPermissionsOptions
 		if args[3] != nil {
-			arg3 = args[3].(string)
+			arg3 = args[3].(UpdateUserPermissionsOptions)
 		}
 		run(
 			arg0,
@@ -2330,48 +7852,48 @@ func (_c *MockService_ResetPassword_Call) Run(run func(ctx context.Context, oper
 	return _c
 }
 
-func (_c *MockService_ResetPassword_Call) Return(err error) *MockService_ResetPassword_Call {
+func (_c *MockService_UpdateUserPermissions_Call) Return(err error) *MockService_UpdateUserPermissions_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_ResetPassword_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, id string, newPassword string) error) *MockService_ResetPassword_Call {
+func (_c *MockService_UpdateUserPermissions_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, id string, opt UpdateUserPermissionsOptions) error) *MockService_UpdateUserPermissions_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateRole provides a mock function for the type MockService
-func (_mock *MockService) UpdateRole(ctx context.Context, operator *Claims, roleID string, opt UpdateRoleOptions) error {
-	ret := _mock.Called(ctx, operator, roleID, opt)
+// UpdateWebhookSubscription provides a mock function for the type MockService
+func (_mock *MockService) UpdateWebhookSubscription(ctx context.Context, operator *Claims, subID string, sub *WebhookSubscription) error {
+	ret := _mock.Called(ctx, operator, subID, sub)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateRole")
+		panic("no return value specified for UpdateWebhookSubscription")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, UpdateRoleOptions) error); ok {
-		r0 = returnFunc(ctx, operator, roleID, opt)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, *WebhookSubscription) error); ok {
+		r0 = returnFunc(ctx, operator, subID, sub)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockService_UpdateRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateRole'
-type MockService_UpdateRole_Call struct {
+// MockService_UpdateWebhookSubscription_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateWebhookSubscription'
+type MockService_UpdateWebhookSubscription_Call struct {
 	*mock.Call
 }
 
-// UpdateRole is a helper method to define mock.On call
+// UpdateWebhookSubscription is a helper method to define mock.On call
 //   - ctx context.Context
 //   - operator *Claims
-//   - roleID string
-//   - opt UpdateRoleOptions
-func (_e *MockService_Expecter) UpdateRole(ctx interface{}, operator interface{}, roleID interface{}, opt interface{}) *MockService_UpdateRole_Call {
-	return &MockService_UpdateRole_Call{Call: _e.mock.On("UpdateRole", ctx, operator, roleID, opt)}
+//   - subID string
+//   - sub *WebhookSubscription
+func (_e *MockService_Expecter) UpdateWebhookSubscription(ctx interface{}, operator interface{}, subID interface{}, sub interface{}) *MockService_UpdateWebhookSubscription_Call {
+	return &MockService_UpdateWebhookSubscription_Call{Call: _e.mock.On("UpdateWebhookSubscription", ctx, operator, subID, sub)}
 }
 
-func (_c *MockService_UpdateRole_Call) Run(run func(ctx context.Context, operator *Claims, roleID string, opt UpdateRoleOptions)) *MockService_UpdateRole_Call {
+func (_c *MockService_UpdateWebhookSubscription_Call) Run(run func(ctx context.Context, operator *Claims, subID string, sub *WebhookSubscription)) *MockService_UpdateWebhookSubscription_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -2385,9 +7907,9 @@ func (_c *MockService_UpdateRole_Call) Run(run func(ctx context.Context, operato
 		if args[2] != nil {
 			arg2 = args[2].(string)
 		}
-		var arg3 UpdateRoleOptions
+		var arg3 *WebhookSubscription
 		if args[3] != nil {
-			arg3 = args[3].(UpdateRoleOptions)
+			arg3 = args[3].(*WebhookSubscription)
 		}
 		run(
 			arg0,
@@ -2399,200 +7921,215 @@ func (_c *MockService_UpdateRole_Call) Run(run func(ctx context.Context, operato
 	return _c
 }
 
-func (_c *MockService_UpdateRole_Call) Return(err error) *MockService_UpdateRole_Call {
+func (_c *MockService_UpdateWebhookSubscription_Call) Return(err error) *MockService_UpdateWebhookSubscription_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_UpdateRole_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, roleID string, opt UpdateRoleOptions) error) *MockService_UpdateRole_Call {
+func (_c *MockService_UpdateWebhookSubscription_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, subID string, sub *WebhookSubscription) error) *MockService_UpdateWebhookSubscription_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateScheduleStrategy provides a mock function for the type MockService
-func (_mock *MockService) UpdateScheduleStrategy(ctx context.Context, operator *Claims, strategyID string, strategy *ScheduleStrategy) error {
-	ret := _mock.Called(ctx, operator, strategyID, strategy)
+// VerifyAPIKey provides a mock function for the type MockService
+func (_mock *MockService) VerifyAPIKey(ctx context.Context, key string, permissionKey PermissionKey) (Claims, RolePolicy, error) {
+	ret := _mock.Called(ctx, key, permissionKey)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateScheduleStrategy")
+		panic("no return value specified for VerifyAPIKey")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, *ScheduleStrategy) error); ok {
-		r0 = returnFunc(ctx, operator, strategyID, strategy)
+	var r0 Claims
+	var r1 RolePolicy
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, PermissionKey) (Claims, RolePolicy, error)); ok {
+		return returnFunc(ctx, key, permissionKey)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, PermissionKey) Claims); ok {
+		r0 = returnFunc(ctx, key, permissionKey)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(Claims)
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, PermissionKey) RolePolicy); ok {
+		r1 = returnFunc(ctx, key, permissionKey)
+	} else {
+		r1 = ret.Get(1).(RolePolicy)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, string, PermissionKey) error); ok {
+		r2 = returnFunc(ctx, key, permissionKey)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
 }
 
-// MockService_UpdateScheduleStrategy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateScheduleStrategy'
-type MockService_UpdateScheduleStrategy_Call struct {
+// MockService_VerifyAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyAPIKey'
+type MockService_VerifyAPIKey_Call struct {
 	*mock.Call
 }
 
-// UpdateScheduleStrategy is a helper method to define mock.On call
+// VerifyAPIKey is a helper method to define mock.On call
 //   - ctx context.Context
-//   - operator *Claims
-//   - strategyID string
-//   - strategy *ScheduleStrategy
-func (_e *MockService_Expecter) UpdateScheduleStrategy(ctx interface{}, operator interface{}, strategyID interface{}, strategy interface{}) *MockService_UpdateScheduleStrategy_Call {
-	return &MockService_UpdateScheduleStrategy_Call{Call: _e.mock.On("UpdateScheduleStrategy", ctx, operator, strategyID, strategy)}
+//   - key string
+//   - permissionKey PermissionKey
+func (_e *MockService_Expecter) VerifyAPIKey(ctx interface{}, key interface{}, permissionKey interface{}) *MockService_VerifyAPIKey_Call {
+	return &MockService_VerifyAPIKey_Call{Call: _e.mock.On("VerifyAPIKey", ctx, key, permissionKey)}
 }
 
-func (_c *MockService_UpdateScheduleStrategy_Call) Run(run func(ctx context.Context, operator *Claims, strategyID string, strategy *ScheduleStrategy)) *MockService_UpdateScheduleStrategy_Call {
+func (_c *MockService_VerifyAPIKey_Call) Run(run func(ctx context.Context, key string, permissionKey PermissionKey)) *MockService_VerifyAPIKey_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *Claims
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].(*Claims)
+			arg1 = args[1].(string)
 		}
-		var arg2 string
+		var arg2 PermissionKey
 		if args[2] != nil {
-			arg2 = args[2].(string)
-		}
-		var arg3 *ScheduleStrategy
-		if args[3] != nil {
-			arg3 = args[3].(*ScheduleStrategy)
+			arg2 = args[2].(PermissionKey)
 		}
 		run(
 			arg0,
 			arg1,
 			arg2,
-			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_UpdateScheduleStrategy_Call) Return(err error) *MockService_UpdateScheduleStrategy_Call {
-	_c.Call.Return(err)
+func (_c *MockService_VerifyAPIKey_Call) Return(claims Claims, rolePolicy RolePolicy, err error) *MockService_VerifyAPIKey_Call {
+	_c.Call.Return(claims, rolePolicy, err)
 	return _c
 }
 
-func (_c *MockService_UpdateScheduleStrategy_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, strategyID string, strategy *ScheduleStrategy) error) *MockService_UpdateScheduleStrategy_Call {
+func (_c *MockService_VerifyAPIKey_Call) RunAndReturn(run func(ctx context.Context, key string, permissionKey PermissionKey) (Claims, RolePolicy, error)) *MockService_VerifyAPIKey_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateUserPermissions provides a mock function for the type MockService
-func (_mock *MockService) UpdateUserPermissions(ctx context.Context, operator *Claims, id string, opt UpdateUserPermissionsOptions) error {
-	ret := _mock.Called(ctx, operator, id, opt)
+// VerifyJWTToken provides a mock function for the type MockService
+func (_mock *MockService) VerifyJWTToken(ctx context.Context, tokenString string, permissionKey PermissionKey) (Claims, RolePolicy, error) {
+	ret := _mock.Called(ctx, tokenString, permissionKey)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateUserPermissions")
+		panic("no return value specified for VerifyJWTToken")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, UpdateUserPermissionsOptions) error); ok {
-		r0 = returnFunc(ctx, operator, id, opt)
+	var r0 Claims
+	var r1 RolePolicy
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, PermissionKey) (Claims, RolePolicy, error)); ok {
+		return returnFunc(ctx, tokenString, permissionKey)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, PermissionKey) Claims); ok {
+		r0 = returnFunc(ctx, tokenString, permissionKey)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(Claims)
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, PermissionKey) RolePolicy); ok {
+		r1 = returnFunc(ctx, tokenString, permissionKey)
+	} else {
+		r1 = ret.Get(1).(RolePolicy)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, string, PermissionKey) error); ok {
+		r2 = returnFunc(ctx, tokenString, permissionKey)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
 }
 
-// MockService_UpdateUserPermissions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUserPermissions'
-type MockService_UpdateUserPermissions_Call struct {
+// MockService_VerifyJWTToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyJWTToken'
+type MockService_VerifyJWTToken_Call struct {
 	*mock.Call
 }
 
-// UpdateUserPermissions is a helper method to define mock.On call
+// VerifyJWTToken is a helper method to define mock.On call
 //   - ctx context.Context
-//   - operator *Claims
-//   - id string
-//   - opt UpdateUserPermissionsOptions
-func (_e *MockService_Expecter) UpdateUserPermissions(ctx interface{}, operator interface{}, id interface{}, opt interface{}) *MockService_UpdateUserPermissions_Call {
-	return &MockService_UpdateUserPermissions_Call{Call: _e.mock.On("UpdateUserPermissions", ctx, operator, id, opt)}
+//   - tokenString string
+//   - permissionKey PermissionKey
+func (_e *MockService_Expecter) VerifyJWTToken(ctx interface{}, tokenString interface{}, permissionKey interface{}) *MockService_VerifyJWTToken_Call {
+	return &MockService_VerifyJWTToken_Call{Call: _e.mock.On("VerifyJWTToken", ctx, tokenString, permissionKey)}
 }
 
-func (_c *MockService_UpdateUserPermissions_Call) Run(run func(ctx context.Context, operator *Claims, id string, opt UpdateUserPermissionsOptions)) *MockService_UpdateUserPermissions_Call {
+func (_c *MockService_VerifyJWTToken_Call) Run(run func(ctx context.Context, tokenString string, permissionKey PermissionKey)) *MockService_VerifyJWTToken_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *Claims
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].(*Claims)
+			arg1 = args[1].(string)
 		}
-		var arg2 string
+		var arg2 PermissionKey
 		if args[2] != nil {
-			arg2 = args[2].(string)
-		}
-		var arg3 UpdateUserPermissionsOptions
-		if args[3] != nil {
-			arg3 = args[3].(UpdateUserPermissionsOptions)
+			arg2 = args[2].(PermissionKey)
 		}
 		run(
 			arg0,
 			arg1,
 			arg2,
-			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_UpdateUserPermissions_Call) Return(err error) *MockService_UpdateUserPermissions_Call {
-	_c.Call.Return(err)
+func (_c *MockService_VerifyJWTToken_Call) Return(claims Claims, rolePolicy RolePolicy, err error) *MockService_VerifyJWTToken_Call {
+	_c.Call.Return(claims, rolePolicy, err)
 	return _c
 }
 
-func (_c *MockService_UpdateUserPermissions_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, id string, opt UpdateUserPermissionsOptions) error) *MockService_UpdateUserPermissions_Call {
+func (_c *MockService_VerifyJWTToken_Call) RunAndReturn(run func(ctx context.Context, tokenString string, permissionKey PermissionKey) (Claims, RolePolicy, error)) *MockService_VerifyJWTToken_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// VerifyJWTToken provides a mock function for the type MockService
-func (_mock *MockService) VerifyJWTToken(ctx context.Context, tokenString string, permissionKey PermissionKey) (Claims, RolePolicy, error) {
-	ret := _mock.Called(ctx, tokenString, permissionKey)
+// VerifyTwoFactorLogin provides a mock function for the type MockService
+func (_mock *MockService) VerifyTwoFactorLogin(ctx context.Context, twoFactorToken string, code string, clientIP string) (*TokenPair, error) {
+	ret := _mock.Called(ctx, twoFactorToken, code, clientIP)
 
 	if len(ret) == 0 {
-		panic("no return value specified for VerifyJWTToken")
+		panic("no return value specified for VerifyTwoFactorLogin")
 	}
 
-	var r0 Claims
-	var r1 RolePolicy
-	var r2 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, PermissionKey) (Claims, RolePolicy, error)); ok {
-		return returnFunc(ctx, tokenString, permissionKey)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, PermissionKey) Claims); ok {
-		r0 = returnFunc(ctx, tokenString, permissionKey)
-	} else {
-		r0 = ret.Get(0).(Claims)
+	var r0 *TokenPair
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) (*TokenPair, error)); ok {
+		return returnFunc(ctx, twoFactorToken, code, clientIP)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string, PermissionKey) RolePolicy); ok {
-		r1 = returnFunc(ctx, tokenString, permissionKey)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) *TokenPair); ok {
+		r0 = returnFunc(ctx, twoFactorToken, code, clientIP)
 	} else {
-		r1 = ret.Get(1).(RolePolicy)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*TokenPair)
+		}
 	}
-	if returnFunc, ok := ret.Get(2).(func(context.Context, string, PermissionKey) error); ok {
-		r2 = returnFunc(ctx, tokenString, permissionKey)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = returnFunc(ctx, twoFactorToken, code, clientIP)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
-	return r0, r1, r2
+	return r0, r1
 }
 
-// MockService_VerifyJWTToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyJWTToken'
-type MockService_VerifyJWTToken_Call struct {
+// MockService_VerifyTwoFactorLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyTwoFactorLogin'
+type MockService_VerifyTwoFactorLogin_Call struct {
 	*mock.Call
 }
 
-// VerifyJWTToken is a helper method to define mock.On call
+// VerifyTwoFactorLogin is a helper method to define mock.On call
 //   - ctx context.Context
-//   - tokenString string
-//   - permissionKey PermissionKey
-func (_e *MockService_Expecter) VerifyJWTToken(ctx interface{}, tokenString interface{}, permissionKey interface{}) *MockService_VerifyJWTToken_Call {
-	return &MockService_VerifyJWTToken_Call{Call: _e.mock.On("VerifyJWTToken", ctx, tokenString, permissionKey)}
+//   - twoFactorToken string
+//   - code string
+//   - clientIP string
+func (_e *MockService_Expecter) VerifyTwoFactorLogin(ctx interface{}, twoFactorToken interface{}, code interface{}, clientIP interface{}) *MockService_VerifyTwoFactorLogin_Call {
+	return &MockService_VerifyTwoFactorLogin_Call{Call: _e.mock.On("VerifyTwoFactorLogin", ctx, twoFactorToken, code, clientIP)}
 }
 
-func (_c *MockService_VerifyJWTToken_Call) Run(run func(ctx context.Context, tokenString string, permissionKey PermissionKey)) *MockService_VerifyJWTToken_Call {
+func (_c *MockService_VerifyTwoFactorLogin_Call) Run(run func(ctx context.Context, twoFactorToken string, code string, clientIP string)) *MockService_VerifyTwoFactorLogin_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -2602,25 +8139,30 @@ func (_c *MockService_VerifyJWTToken_Call) Run(run func(ctx context.Context, tok
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
-		var arg2 PermissionKey
+		var arg2 string
 		if args[2] != nil {
-			arg2 = args[2].(PermissionKey)
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
 		}
 		run(
 			arg0,
 			arg1,
 			arg2,
+			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_VerifyJWTToken_Call) Return(claims Claims, rolePolicy RolePolicy, err error) *MockService_VerifyJWTToken_Call {
-	_c.Call.Return(claims, rolePolicy, err)
+func (_c *MockService_VerifyTwoFactorLogin_Call) Return(tokenPair *TokenPair, err error) *MockService_VerifyTwoFactorLogin_Call {
+	_c.Call.Return(tokenPair, err)
 	return _c
 }
 
-func (_c *MockService_VerifyJWTToken_Call) RunAndReturn(run func(ctx context.Context, tokenString string, permissionKey PermissionKey) (Claims, RolePolicy, error)) *MockService_VerifyJWTToken_Call {
+func (_c *MockService_VerifyTwoFactorLogin_Call) RunAndReturn(run func(ctx context.Context, twoFactorToken string, code string, clientIP string) (*TokenPair, error)) *MockService_VerifyTwoFactorLogin_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -2652,6 +8194,80 @@ func (_m *MockK8SAdapter) EXPECT() *MockK8SAdapter_Expecter {
 	return &MockK8SAdapter_Expecter{mock: &_m.Mock}
 }
 
+// GetPod provides a mock function for the type MockK8SAdapter
+func (_mock *MockK8SAdapter) GetPod(ctx context.Context, namespace string, name string) (*Pod, error) {
+	ret := _mock.Called(ctx, namespace, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPod")
+	}
+
+	var r0 *Pod
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*Pod, error)); ok {
+		return returnFunc(ctx, namespace, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *Pod); ok {
+		r0 = returnFunc(ctx, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*Pod)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockK8SAdapter_GetPod_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPod'
+type MockK8SAdapter_GetPod_Call struct {
+	*mock.Call
+}
+
+// GetPod is a helper method to define mock.On call
+//   - ctx context.Context
+//   - namespace string
+//   - name string
+func (_e *MockK8SAdapter_Expecter) GetPod(ctx interface{}, namespace interface{}, name interface{}) *MockK8SAdapter_GetPod_Call {
+	return &MockK8SAdapter_GetPod_Call{Call: _e.mock.On("GetPod", ctx, namespace, name)}
+}
+
+func (_c *MockK8SAdapter_GetPod_Call) Run(run func(ctx context.Context, namespace string, name string)) *MockK8SAdapter_GetPod_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockK8SAdapter_GetPod_Call) Return(pod *Pod, err error) *MockK8SAdapter_GetPod_Call {
+	_c.Call.Return(pod, err)
+	return _c
+}
+
+func (_c *MockK8SAdapter_GetPod_Call) RunAndReturn(run func(ctx context.Context, namespace string, name string) (*Pod, error)) *MockK8SAdapter_GetPod_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListNodes provides a mock function for the type MockK8SAdapter
 func (_mock *MockK8SAdapter) ListNodes(ctx context.Context) ([]*Node, error) {
 	ret := _mock.Called(ctx)
@@ -2940,6 +8556,80 @@ func (_c *MockDecisionMakerAdapter_DeleteSchedulingIntents_Call) RunAndReturn(ru
 	return _c
 }
 
+// ExplainPod provides a mock function for the type MockDecisionMakerAdapter
+func (_mock *MockDecisionMakerAdapter) ExplainPod(ctx context.Context, decisionMaker *DecisionMakerPod, podID string) (*DecisionMakerPodExplanation, error) {
+	ret := _mock.Called(ctx, decisionMaker, podID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExplainPod")
+	}
+
+	var r0 *DecisionMakerPodExplanation
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *DecisionMakerPod, string) (*DecisionMakerPodExplanation, error)); ok {
+		return returnFunc(ctx, decisionMaker, podID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *DecisionMakerPod, string) *DecisionMakerPodExplanation); ok {
+		r0 = returnFunc(ctx, decisionMaker, podID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*DecisionMakerPodExplanation)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *DecisionMakerPod, string) error); ok {
+		r1 = returnFunc(ctx, decisionMaker, podID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockDecisionMakerAdapter_ExplainPod_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExplainPod'
+type MockDecisionMakerAdapter_ExplainPod_Call struct {
+	*mock.Call
+}
+
+// ExplainPod is a helper method to define mock.On call
+//   - ctx context.Context
+//   - decisionMaker *DecisionMakerPod
+//   - podID string
+func (_e *MockDecisionMakerAdapter_Expecter) ExplainPod(ctx interface{}, decisionMaker interface{}, podID interface{}) *MockDecisionMakerAdapter_ExplainPod_Call {
+	return &MockDecisionMakerAdapter_ExplainPod_Call{Call: _e.mock.On("ExplainPod", ctx, decisionMaker, podID)}
+}
+
+func (_c *MockDecisionMakerAdapter_ExplainPod_Call) Run(run func(ctx context.Context, decisionMaker *DecisionMakerPod, podID string)) *MockDecisionMakerAdapter_ExplainPod_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *DecisionMakerPod
+		if args[1] != nil {
+			arg1 = args[1].(*DecisionMakerPod)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockDecisionMakerAdapter_ExplainPod_Call) Return(decisionMakerPodExplanation *DecisionMakerPodExplanation, err error) *MockDecisionMakerAdapter_ExplainPod_Call {
+	_c.Call.Return(decisionMakerPodExplanation, err)
+	return _c
+}
+
+func (_c *MockDecisionMakerAdapter_ExplainPod_Call) RunAndReturn(run func(ctx context.Context, decisionMaker *DecisionMakerPod, podID string) (*DecisionMakerPodExplanation, error)) *MockDecisionMakerAdapter_ExplainPod_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetIntentMerkleRoot provides a mock function for the type MockDecisionMakerAdapter
 func (_mock *MockDecisionMakerAdapter) GetIntentMerkleRoot(ctx context.Context, decisionMaker *DecisionMakerPod) (string, error) {
 	ret := _mock.Called(ctx, decisionMaker)
@@ -3006,6 +8696,154 @@ func (_c *MockDecisionMakerAdapter_GetIntentMerkleRoot_Call) RunAndReturn(run fu
 	return _c
 }
 
+// GetMetricHistory provides a mock function for the type MockDecisionMakerAdapter
+func (_mock *MockDecisionMakerAdapter) GetMetricHistory(ctx context.Context, decisionMaker *DecisionMakerPod, since time.Time, step time.Duration) ([]MetricSample, error) {
+	ret := _mock.Called(ctx, decisionMaker, since, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMetricHistory")
+	}
+
+	var r0 []MetricSample
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *DecisionMakerPod, time.Time, time.Duration) ([]MetricSample, error)); ok {
+		return returnFunc(ctx, decisionMaker, since, step)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *DecisionMakerPod, time.Time, time.Duration) []MetricSample); ok {
+		r0 = returnFunc(ctx, decisionMaker, since, step)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]MetricSample)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *DecisionMakerPod, time.Time, time.Duration) error); ok {
+		r1 = returnFunc(ctx, decisionMaker, since, step)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockDecisionMakerAdapter_GetMetricHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMetricHistory'
+type MockDecisionMakerAdapter_GetMetricHistory_Call struct {
+	*mock.Call
+}
+
+// GetMetricHistory is a helper method to define mock.On call
+//   - ctx context.Context
+//   - decisionMaker *DecisionMakerPod
+//   - since time.Time
+//   - step time.Duration
+func (_e *MockDecisionMakerAdapter_Expecter) GetMetricHistory(ctx interface{}, decisionMaker interface{}, since interface{}, step interface{}) *MockDecisionMakerAdapter_GetMetricHistory_Call {
+	return &MockDecisionMakerAdapter_GetMetricHistory_Call{Call: _e.mock.On("GetMetricHistory", ctx, decisionMaker, since, step)}
+}
+
+func (_c *MockDecisionMakerAdapter_GetMetricHistory_Call) Run(run func(ctx context.Context, decisionMaker *DecisionMakerPod, since time.Time, step time.Duration)) *MockDecisionMakerAdapter_GetMetricHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *DecisionMakerPod
+		if args[1] != nil {
+			arg1 = args[1].(*DecisionMakerPod)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		var arg3 time.Duration
+		if args[3] != nil {
+			arg3 = args[3].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockDecisionMakerAdapter_GetMetricHistory_Call) Return(metricSamples []MetricSample, err error) *MockDecisionMakerAdapter_GetMetricHistory_Call {
+	_c.Call.Return(metricSamples, err)
+	return _c
+}
+
+func (_c *MockDecisionMakerAdapter_GetMetricHistory_Call) RunAndReturn(run func(ctx context.Context, decisionMaker *DecisionMakerPod, since time.Time, step time.Duration) ([]MetricSample, error)) *MockDecisionMakerAdapter_GetMetricHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMetrics provides a mock function for the type MockDecisionMakerAdapter
+func (_mock *MockDecisionMakerAdapter) GetMetrics(ctx context.Context, decisionMaker *DecisionMakerPod) (*MetricSample, error) {
+	ret := _mock.Called(ctx, decisionMaker)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMetrics")
+	}
+
+	var r0 *MetricSample
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *DecisionMakerPod) (*MetricSample, error)); ok {
+		return returnFunc(ctx, decisionMaker)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *DecisionMakerPod) *MetricSample); ok {
+		r0 = returnFunc(ctx, decisionMaker)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*MetricSample)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *DecisionMakerPod) error); ok {
+		r1 = returnFunc(ctx, decisionMaker)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockDecisionMakerAdapter_GetMetrics_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMetrics'
+type MockDecisionMakerAdapter_GetMetrics_Call struct {
+	*mock.Call
+}
+
+// GetMetrics is a helper method to define mock.On call
+//   - ctx context.Context
+//   - decisionMaker *DecisionMakerPod
+func (_e *MockDecisionMakerAdapter_Expecter) GetMetrics(ctx interface{}, decisionMaker interface{}) *MockDecisionMakerAdapter_GetMetrics_Call {
+	return &MockDecisionMakerAdapter_GetMetrics_Call{Call: _e.mock.On("GetMetrics", ctx, decisionMaker)}
+}
+
+func (_c *MockDecisionMakerAdapter_GetMetrics_Call) Run(run func(ctx context.Context, decisionMaker *DecisionMakerPod)) *MockDecisionMakerAdapter_GetMetrics_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *DecisionMakerPod
+		if args[1] != nil {
+			arg1 = args[1].(*DecisionMakerPod)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockDecisionMakerAdapter_GetMetrics_Call) Return(metricSample *MetricSample, err error) *MockDecisionMakerAdapter_GetMetrics_Call {
+	_c.Call.Return(metricSample, err)
+	return _c
+}
+
+func (_c *MockDecisionMakerAdapter_GetMetrics_Call) RunAndReturn(run func(ctx context.Context, decisionMaker *DecisionMakerPod) (*MetricSample, error)) *MockDecisionMakerAdapter_GetMetrics_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetPodPIDMapping provides a mock function for the type MockDecisionMakerAdapter
 func (_mock *MockDecisionMakerAdapter) GetPodPIDMapping(ctx context.Context, decisionMaker *DecisionMakerPod) (*PodPIDMappingResponse, error) {
 	ret := _mock.Called(ctx, decisionMaker)
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/Gthulhu/api/pkg/oidc"
+)
+
+// OIDCAuthRequest starts a single sign-on login. The user agent is redirected to AuthURL and
+// must present Session together with the callback, it binds the callback to this request.
+type OIDCAuthRequest struct {
+	AuthURL   string
+	Session   string
+	ExpiresAt time.Time
+}
+
+// OIDCCallback is the redirect of the identity provider back to the manager
+type OIDCCallback struct {
+	Session          string
+	State            string
+	Code             string
+	Error            string
+	ErrorDescription string
+}
+
+// OIDCLogin is the result of a completed single sign-on login, RedirectURL is where the user
+// agent continues with the access token, empty when the tokens are returned directly
+type OIDCLogin struct {
+	Tokens      *TokenPair
+	RedirectURL string
+}
+
+// JSONWebKeySet holds the public keys manager-issued JWTs are verified with, the signing key first
+type JSONWebKeySet struct {
+	Keys []oidc.JSONWebKey
+}
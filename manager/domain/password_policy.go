@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	DefaultPasswordMinLength = 8
+	DefaultPasswordMaxLength = 128
+	// MaxLoginHistory is the number of login attempts kept on a user
+	MaxLoginHistory = 20
+)
+
+// PasswordPolicy constrains the passwords users choose
+type PasswordPolicy struct {
+	MinLength     int
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// HistorySize is the number of previous passwords, including the current one, that can't be reused
+	HistorySize int
+}
+
+// Violations returns the rules password breaks, zero limits use the defaults
+func (p PasswordPolicy) Violations(password string) []string {
+	minLength, maxLength := p.MinLength, p.MaxLength
+	if minLength <= 0 {
+		minLength = DefaultPasswordMinLength
+	}
+	if maxLength <= 0 {
+		maxLength = DefaultPasswordMaxLength
+	}
+	var violations []string
+	length := utf8.RuneCountInString(password)
+	if length < minLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters", minLength))
+	}
+	if length > maxLength {
+		violations = append(violations, fmt.Sprintf("must be at most %d characters", maxLength))
+	}
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+	if p.RequireUpper && !upper {
+		violations = append(violations, "must contain an uppercase letter")
+	}
+	if p.RequireLower && !lower {
+		violations = append(violations, "must contain a lowercase letter")
+	}
+	if p.RequireDigit && !digit {
+		violations = append(violations, "must contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		violations = append(violations, "must contain a symbol")
+	}
+	return violations
+}
+
+// LoginAttempt is an entry of the login history of a user
+type LoginAttempt struct {
+	Time    int64  `bson:"time"`
+	IP      string `bson:"ip,omitempty"`
+	Success bool   `bson:"success"`
+	// Reason tells why a failed attempt was rejected
+	Reason string `bson:"reason,omitempty"`
+}
+
+// LoginLockout locks a user until LockedUntil once the failed login count reaches MaxFailedAttempts
+type LoginLockout struct {
+	MaxFailedAttempts int
+	LockedUntil       int64
+}
+
+// Locked reports whether the user is locked out at now
+func (u *User) Locked(now time.Time) bool {
+	return u.LockedUntil > now.UnixMilli()
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// APIKeyPrefix starts every API key, which is formatted as gth_<KeyID>_<secret>
+const APIKeyPrefix = "gth_"
+
+// ServiceAccount is a non-interactive principal for automation, authenticated with API keys and
+// authorized by its role bindings like a user
+type ServiceAccount struct {
+	BaseEntity  `bson:",inline"`
+	Name        string   `bson:"name,omitempty"`
+	Description string   `bson:"description,omitempty"`
+	Roles       []string `bson:"roles,omitempty"`
+	// Disabled rejects every API key of the service account
+	Disabled bool `bson:"disabled,omitempty"`
+}
+
+type UpdateServiceAccountOptions struct {
+	Description *string
+	Roles       *[]string
+	Disabled    *bool
+}
+
+// APIKey authenticates a service account. Only the argon2 hash of the secret is stored, KeyID is
+// the public part of the key used to look it up.
+type APIKey struct {
+	BaseEntity       `bson:",inline"`
+	ServiceAccountID string            `bson:"serviceAccountID,omitempty"`
+	Name             string            `bson:"name,omitempty"`
+	KeyID            string            `bson:"keyID,omitempty"`
+	SecretHash       EncryptedPassword `bson:"secretHash,omitempty"`
+	// Scopes restricts the key to these permissions on top of the role bindings of the service
+	// account, empty means every permission of its roles
+	Scopes     []PermissionKey `bson:"scopes,omitempty"`
+	ExpiresAt  int64           `bson:"expiresAt,omitempty"`
+	RevokedAt  int64           `bson:"revokedAt,omitempty"`
+	LastUsedAt int64           `bson:"lastUsedAt,omitempty"`
+}
+
+// Active reports whether the key is neither revoked nor expired at now
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == 0 && (k.ExpiresAt == 0 || now.UnixMilli() < k.ExpiresAt)
+}
+
+// Allows reports whether the scopes of the key cover permissionKey
+func (k *APIKey) Allows(permissionKey PermissionKey) bool {
+	if len(k.Scopes) == 0 {
+		return true
+	}
+	for _, scope := range k.Scopes {
+		if scope == permissionKey {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatAPIKey returns the API key handed out to the client
+func FormatAPIKey(keyID, secret string) string {
+	return APIKeyPrefix + keyID + "_" + secret
+}
+
+// ParseAPIKey splits an API key into its key ID and secret
+func ParseAPIKey(key string) (keyID, secret string, err error) {
+	rest, ok := strings.CutPrefix(key, APIKeyPrefix)
+	if !ok {
+		return "", "", errors.New("not an API key")
+	}
+	keyID, secret, ok = strings.Cut(rest, "_")
+	if !ok || keyID == "" || secret == "" {
+		return "", "", fmt.Errorf("malformed API key")
+	}
+	return keyID, secret, nil
+}
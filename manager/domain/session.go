@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// TokenPair is issued on login and on every refresh
+type TokenPair struct {
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+}
+
+// RefreshToken is a single-use token exchanged for a new TokenPair. Only the SHA-256 hash of the
+// token is stored.
+type RefreshToken struct {
+	ID        bson.ObjectID `bson:"_id,omitempty"`
+	UserID    bson.ObjectID `bson:"userID"`
+	TokenHash string        `bson:"tokenHash"`
+	// FamilyID is shared by every token rotated from the same login, presenting a rotated token
+	// again revokes the whole family
+	FamilyID string `bson:"familyID"`
+	// AccessTokenID is the jti of the access token issued together with this token
+	AccessTokenID string `bson:"accessTokenID,omitempty"`
+	CreatedTime   int64  `bson:"createdTime"`
+	// ExpiresAt is a date so that a TTL index removes expired tokens
+	ExpiresAt time.Time `bson:"expiresAt"`
+	// RevokedAt is set once the token was rotated, used to log out or revoked
+	RevokedAt int64 `bson:"revokedAt,omitempty"`
+}
+
+// RevokeRefreshTokensOptions selects the refresh tokens to revoke, the fields are combined with AND
+type RevokeRefreshTokensOptions struct {
+	UserID         bson.ObjectID
+	FamilyIDs      []string
+	AccessTokenIDs []string
+	TokenHashes    []string
+}
+
+// RevokedToken is an access token revoked before its expiry, kept until it expires
+type RevokedToken struct {
+	JTI       string        `bson:"_id"`
+	UserID    bson.ObjectID `bson:"userID,omitempty"`
+	RevokedAt int64         `bson:"revokedAt"`
+	ExpiresAt time.Time     `bson:"expiresAt"`
+}
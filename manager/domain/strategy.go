@@ -1,6 +1,9 @@
 package domain
 
 import (
+	"maps"
+	"slices"
+
 	"github.com/Gthulhu/api/pkg/util"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
@@ -11,14 +14,24 @@ type ScheduleStrategy struct {
 	LabelSelectors    []LabelSelector `bson:"labelSelectors,omitempty"`
 	K8sNamespace      []string        `bson:"k8sNamespace,omitempty"`
 	CommandRegex      string          `bson:"commandRegex,omitempty"`
-	Priority          int             `bson:"priority,omitempty"`
-	ExecutionTime     int64           `bson:"executionTime,omitempty"`
+	// CommandMatchTargets lists the process attributes CommandRegex is matched against;
+	// a process matches when any of them matches. Empty means DefaultCommandMatchTargets.
+	CommandMatchTargets []CommandMatchTarget `bson:"commandMatchTargets,omitempty"`
+	// ThreadRegex selects individual threads of the matched processes by their comm;
+	// empty means the whole process is scheduled.
+	ThreadRegex   string `bson:"threadRegex,omitempty"`
+	Priority      int    `bson:"priority,omitempty"`
+	ExecutionTime int64  `bson:"executionTime,omitempty"`
+	// TeamID shares the strategy with the members of the team, zero for strategies only their
+	// creator manages
+	TeamID bson.ObjectID `bson:"teamID,omitempty"`
 }
 
 func NewScheduleIntent(strategy *ScheduleStrategy, pod *Pod) ScheduleIntent {
-	return ScheduleIntent{
+	intent := ScheduleIntent{
 		BaseEntity:    NewBaseEntity(util.Ptr(strategy.CreatorID), util.Ptr(strategy.UpdaterID)),
 		StrategyID:    strategy.ID,
+		TeamID:        strategy.TeamID,
 		PodID:         pod.PodID,
 		NodeID:        pod.NodeID,
 		K8sNamespace:  pod.K8SNamespace,
@@ -28,12 +41,30 @@ func NewScheduleIntent(strategy *ScheduleStrategy, pod *Pod) ScheduleIntent {
 		PodLabels:     pod.Labels,
 		State:         IntentStateInitialized,
 		PodName:       pod.Name,
+
+		CommandMatchTargets: strategy.CommandMatchTargets,
+		ThreadRegex:         strategy.ThreadRegex,
 	}
+	if strategy.HasCommandMatchTarget(CommandMatchContainerName) {
+		intent.ContainerNames = pod.ContainerNames()
+	}
+	return intent
+}
+
+// ManageableBy reports whether the user with userID, member of teamIDs, owns the strategy
+func (s *ScheduleStrategy) ManageableBy(userID bson.ObjectID, teamIDs []bson.ObjectID) bool {
+	return s.CreatorID == userID || (!s.TeamID.IsZero() && slices.Contains(teamIDs, s.TeamID))
+}
+
+// HasCommandMatchTarget reports whether the strategy matches CommandRegex against target
+func (s *ScheduleStrategy) HasCommandMatchTarget(target CommandMatchTarget) bool {
+	return slices.Contains(s.CommandMatchTargets, target)
 }
 
 type ScheduleIntent struct {
 	BaseEntity    `bson:",inline"`
 	StrategyID    bson.ObjectID     `bson:"strategyID,omitempty"`
+	TeamID        bson.ObjectID     `bson:"teamID,omitempty"`
 	PodID         string            `bson:"podID,omitempty"`
 	PodName       string            `bson:"podName,omitempty"`
 	NodeID        string            `bson:"nodeID,omitempty"`
@@ -43,6 +74,27 @@ type ScheduleIntent struct {
 	ExecutionTime int64             `bson:"executionTime,omitempty"`
 	PodLabels     map[string]string `bson:"podLabels,omitempty"`
 	State         IntentState       `bson:"state,omitempty"`
+
+	CommandMatchTargets []CommandMatchTarget `bson:"commandMatchTargets,omitempty"`
+	ThreadRegex         string               `bson:"threadRegex,omitempty"`
+	// ContainerNames maps runtime container IDs (without the runtime scheme) to container names,
+	// so the decision maker can evaluate CommandMatchContainerName against its /proc view.
+	// It is only populated when the strategy matches on container names.
+	ContainerNames map[string]string `bson:"containerNames,omitempty"`
+}
+
+// ManageableBy reports whether the user with userID, member of teamIDs, owns the intent
+func (intent *ScheduleIntent) ManageableBy(userID bson.ObjectID, teamIDs []bson.ObjectID) bool {
+	return intent.CreatorID == userID || (!intent.TeamID.IsZero() && slices.Contains(teamIDs, intent.TeamID))
+}
+
+// ContainersChanged reports whether the pod's container IDs no longer match the ones
+// recorded on the intent, e.g. after a container restart
+func (intent *ScheduleIntent) ContainersChanged(pod *Pod) bool {
+	if !slices.Contains(intent.CommandMatchTargets, CommandMatchContainerName) {
+		return false
+	}
+	return !maps.Equal(intent.ContainerNames, pod.ContainerNames())
 }
 
 type LabelSelector struct {
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"slices"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Team groups users sharing the ownership of schedule strategies, every member can manage the
+// strategies of the team within the permissions of its roles
+type Team struct {
+	BaseEntity  `bson:",inline"`
+	Name        string          `bson:"name,omitempty"`
+	Description string          `bson:"description,omitempty"`
+	MemberIDs   []bson.ObjectID `bson:"memberIDs,omitempty"`
+}
+
+// HasMember reports whether the user with id belongs to the team
+func (t *Team) HasMember(id bson.ObjectID) bool {
+	return slices.Contains(t.MemberIDs, id)
+}
+
+type UpdateTeamOptions struct {
+	Name        *string
+	Description *string
+	MemberIDs   *[]bson.ObjectID
+}
+
+// TransferStrategyOptions names the new owners of a strategy, TeamID is required and a zero
+// CreatorID keeps the current creator
+type TransferStrategyOptions struct {
+	CreatorID bson.ObjectID
+	TeamID    bson.ObjectID
+}
@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// TwoFactor is the TOTP authenticator of a user
+type TwoFactor struct {
+	Enabled bool `bson:"enabled,omitempty"`
+	// Secret is the TOTP secret encrypted with AES-GCM, PendingSecret holds the secret of an
+	// enrollment until it's confirmed with a code
+	Secret        string `bson:"secret,omitempty"`
+	PendingSecret string `bson:"pendingSecret,omitempty"`
+	// RecoveryCodeHashes are the SHA-256 hashes of the unused single-use recovery codes
+	RecoveryCodeHashes []string `bson:"recoveryCodeHashes,omitempty"`
+	// LastUsedStep is the TOTP time step of the last accepted code, codes can't be replayed
+	LastUsedStep int64 `bson:"lastUsedStep,omitempty"`
+	EnabledTime  int64 `bson:"enabledTime,omitempty"`
+}
+
+// TwoFactorEnabled reports whether logins of the user need a second factor
+func (u *User) TwoFactorEnabled() bool {
+	return u.TwoFactor != nil && u.TwoFactor.Enabled
+}
+
+// LoginResult is the outcome of a password login. Users with two-factor authentication get a
+// TwoFactorToken instead of Tokens, VerifyTwoFactorLogin exchanges it and a code for Tokens.
+type LoginResult struct {
+	Tokens                  *TokenPair
+	TwoFactorToken          string
+	TwoFactorTokenExpiresAt time.Time
+	// TwoFactorEnrollmentRequired is set when a role of the user requires two-factor
+	// authentication the user hasn't enabled, Tokens only allow enrolling until then
+	TwoFactorEnrollmentRequired bool
+}
+
+// TwoFactorEnrollment is a TOTP secret to add to an authenticator app, URI is its otpauth:// key URI
+type TwoFactorEnrollment struct {
+	Secret string
+	URI    string
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/Gthulhu/api/manager/domain"
 	"github.com/Gthulhu/api/pkg/logger"
 	apiv1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/informers"
@@ -167,26 +168,54 @@ func (a *Adapter) QueryPods(ctx context.Context, opt *domain.QueryPodsOptions) (
 	results := make([]*domain.Pod, 0, len(pods))
 
 	for _, pod := range pods {
-		containers := buildContainers(pod, cmdRegex)
+		containers := buildContainers(pod, cmdRegex, opt.CommandMatchTargets)
 		if cmdRegex != nil && len(containers) == 0 {
 			continue
 		}
 
-		podLabels := copyLabels(pod.Labels)
-		result := &domain.Pod{
-			Name:         pod.Name,
-			K8SNamespace: pod.Namespace,
-			Labels:       podLabels,
-			PodID:        string(pod.UID),
-			NodeID:       pod.Spec.NodeName,
-			Containers:   containers,
-		}
-		results = append(results, result)
+		results = append(results, toDomainPod(pod, containers))
 	}
 
 	return results, nil
 }
 
+// GetPod returns the pod called name in namespace from the pod cache, or from the API server
+// while the cache is not synced, and domain.ErrNotFound when there is no such pod
+func (a *Adapter) GetPod(ctx context.Context, namespace, name string) (*domain.Pod, error) {
+	if a == nil || a.client == nil {
+		return nil, domain.ErrNoClient
+	}
+
+	if a.cacheHasSynced.Load() {
+		pod, ok := a.podFromCache(namespace, name)
+		if !ok {
+			return nil, domain.ErrNotFound
+		}
+		return toDomainPod(pod, buildContainers(pod, nil, nil)), nil
+	}
+
+	pod, err := a.client.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get pod %s/%s: %w", namespace, name, err)
+	}
+	a.setPodCache(*pod)
+	return toDomainPod(*pod, buildContainers(*pod, nil, nil)), nil
+}
+
+func toDomainPod(pod apiv1.Pod, containers []domain.Container) *domain.Pod {
+	return &domain.Pod{
+		Name:         pod.Name,
+		K8SNamespace: pod.Namespace,
+		Labels:       copyLabels(pod.Labels),
+		PodID:        string(pod.UID),
+		NodeID:       pod.Spec.NodeName,
+		Containers:   containers,
+	}
+}
+
 func (a *Adapter) QueryDecisionMakerPods(ctx context.Context, opt *domain.QueryDecisionMakerPodsOptions) ([]*domain.DecisionMakerPod, error) {
 	if opt == nil {
 		return nil, domain.ErrNilQueryInput
@@ -273,6 +302,18 @@ func (a *Adapter) podsFromCache(namespaces []string, selector labels.Selector) [
 	return pods
 }
 
+func (a *Adapter) podFromCache(namespace, name string) (apiv1.Pod, bool) {
+	a.podCacheMu.RLock()
+	defer a.podCacheMu.RUnlock()
+
+	for _, pod := range a.podCache {
+		if pod.Namespace == namespace && pod.Name == name {
+			return pod, true
+		}
+	}
+	return apiv1.Pod{}, false
+}
+
 func (a *Adapter) listPodsLive(ctx context.Context, namespaces []string, labelSelector string) ([]apiv1.Pod, error) {
 	results := make([]apiv1.Pod, 0)
 	for _, ns := range namespaces {
@@ -317,26 +358,23 @@ func buildLabelSelector(selectors []domain.LabelSelector) string {
 	return strings.Join(labels, ",")
 }
 
-func buildContainers(pod apiv1.Pod, cmdRegex *regexp.Regexp) []domain.Container {
+func buildContainers(pod apiv1.Pod, cmdRegex *regexp.Regexp, targets []domain.CommandMatchTarget) []domain.Container {
 	statusByName := make(map[string]string, len(pod.Status.ContainerStatuses))
 	for _, status := range pod.Status.ContainerStatuses {
 		statusByName[status.Name] = status.ContainerID
 	}
-
 	result := make([]domain.Container, 0, len(pod.Spec.Containers))
 	for _, container := range pod.Spec.Containers {
-		command := append([]string{}, container.Command...)
-		command = append(command, container.Args...)
-
-		if cmdRegex != nil && !cmdRegex.MatchString(strings.Join(command, " ")) {
-			continue
-		}
-
-		result = append(result, domain.Container{
+		c := domain.Container{
 			ContainerID: statusByName[container.Name],
 			Name:        container.Name,
-			Command:     command,
-		})
+			Command:     container.Command,
+			Args:        container.Args,
+		}
+		if cmdRegex != nil && !c.MatchCommand(cmdRegex, targets) {
+			continue
+		}
+		result = append(result, c)
 	}
 	return result
 }
@@ -5,6 +5,9 @@ package k8sadapter
 
 import (
 	"context"
+	"errors"
+	"regexp"
+	"slices"
 	"testing"
 	"time"
 
@@ -219,6 +222,47 @@ func TestQueryPodsUsesCache(t *testing.T) {
 	}
 }
 
+func TestGetPod(t *testing.T) {
+	t.Parallel()
+
+	pod := &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-0",
+			Namespace: "ns1",
+			UID:       "uid-1",
+		},
+		Spec: apiv1.PodSpec{NodeName: "node-1"},
+	}
+	adapter := &Adapter{
+		client:   fake.NewSimpleClientset(pod),
+		podCache: make(map[string]apiv1.Pod),
+	}
+
+	// the cache is not synced yet, the pod is read from the API server
+	got, err := adapter.GetPod(context.Background(), "ns1", "web-0")
+	if err != nil {
+		t.Fatalf("GetPod returned error: %v", err)
+	}
+	if got.PodID != "uid-1" || got.NodeID != "node-1" {
+		t.Fatalf("unexpected pod %+v", got)
+	}
+	if _, err := adapter.GetPod(context.Background(), "ns2", "web-0"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	adapter.cacheHasSynced.Store(true)
+	got, err = adapter.GetPod(context.Background(), "ns1", "web-0")
+	if err != nil {
+		t.Fatalf("GetPod returned error: %v", err)
+	}
+	if got.PodID != "uid-1" {
+		t.Fatalf("unexpected PodID %q", got.PodID)
+	}
+	if _, err := adapter.GetPod(context.Background(), "ns1", "web-1"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
 func TestQueryDecisionMakerPodsUsesCache(t *testing.T) {
 	t.Parallel()
 
@@ -285,3 +329,60 @@ func TestQueryDecisionMakerPodsUsesCache(t *testing.T) {
 		t.Fatalf("unexpected state %v", got.State)
 	}
 }
+
+func TestBuildContainersCommandMatchTargets(t *testing.T) {
+	t.Parallel()
+
+	pod := apiv1.Pod{
+		Spec: apiv1.PodSpec{
+			Containers: []apiv1.Container{
+				{
+					Name:    "payments-api",
+					Command: []string{"/usr/lib/jvm/bin/java"},
+					Args:    []string{"-jar", "payments.jar"},
+				},
+				{
+					Name:    "istio-proxy",
+					Command: []string{"/usr/local/bin/pilot-agent-with-a-long-name"},
+				},
+				{
+					Name: "worker",
+					Args: []string{"celery", "worker"},
+				},
+			},
+		},
+		Status: apiv1.PodStatus{
+			ContainerStatuses: []apiv1.ContainerStatus{
+				{Name: "payments-api", ContainerID: "containerd://abc"},
+				{Name: "istio-proxy", ContainerID: "containerd://def"},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		regex    string
+		targets  []domain.CommandMatchTarget
+		expected []string
+	}{
+		{name: "default targets match cmdline", regex: `payments\.jar`, expected: []string{"payments-api"}},
+		{name: "default targets match args without command", regex: `^celery worker$`, expected: []string{"worker"}},
+		{name: "default targets ignore comm", regex: `^pilot-agent-wit$`, expected: []string{}},
+		{name: "comm is truncated", regex: `^pilot-agent-wit$`, targets: []domain.CommandMatchTarget{domain.CommandMatchComm}, expected: []string{"istio-proxy"}},
+		{name: "comm ignores args", regex: `celery`, targets: []domain.CommandMatchTarget{domain.CommandMatchComm, domain.CommandMatchExe}, expected: []string{}},
+		{name: "exe", regex: `^/usr/lib/jvm/`, targets: []domain.CommandMatchTarget{domain.CommandMatchExe}, expected: []string{"payments-api"}},
+		{name: "container name", regex: `proxy`, targets: []domain.CommandMatchTarget{domain.CommandMatchContainerName}, expected: []string{"istio-proxy"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			containers := buildContainers(pod, regexp.MustCompile(tc.regex), tc.targets)
+			names := make([]string, 0, len(containers))
+			for _, container := range containers {
+				names = append(names, container.Name)
+			}
+			if !slices.Equal(names, tc.expected) {
+				t.Fatalf("expected containers %v, got %v", tc.expected, names)
+			}
+		})
+	}
+}
@@ -33,6 +33,7 @@ const (
 	labelCreatorID  = "gthulhu.io/creator-id"
 	labelStrategyID = "gthulhu.io/strategy-id"
 	labelState      = "gthulhu.io/state"
+	labelTeamID     = "gthulhu.io/team-id"
 )
 
 // ---------------------------------------------------------------------------
@@ -189,7 +190,7 @@ func (r *repo) QueryStrategies(ctx context.Context, opt *domain.QueryStrategyOpt
 	}
 
 	// Build label selector for list queries.
-	sel := buildLabelSelector(opt.CreatorIDs, labelCreatorID)
+	sel := buildOwnerLabelSelector(opt.CreatorIDs, opt.TeamIDs)
 	list, err := r.k8sDynamic.Resource(strategyGVR).Namespace(r.crNamespace).List(ctx, metav1.ListOptions{LabelSelector: sel})
 	if err != nil {
 		return err
@@ -234,7 +235,7 @@ func (r *repo) QueryIntents(ctx context.Context, opt *domain.QueryIntentOptions)
 
 	// Build label selector for common filters.
 	selParts := []string{}
-	if s := buildLabelSelector(opt.CreatorIDs, labelCreatorID); s != "" {
+	if s := buildOwnerLabelSelector(opt.CreatorIDs, opt.TeamIDs); s != "" {
 		selParts = append(selParts, s)
 	}
 	if s := buildLabelSelector(opt.StrategyIDs, labelStrategyID); s != "" {
@@ -313,6 +314,53 @@ func (r *repo) DeleteIntentsByStrategyID(ctx context.Context, strategyID bson.Ob
 	return nil
 }
 
+func (r *repo) UpdateIntentsOwner(ctx context.Context, strategy *domain.ScheduleStrategy) error {
+	if strategy == nil {
+		return errors.New("nil strategy")
+	}
+	now := time.Now().UnixMilli()
+	sel := labelStrategyID + "=" + strategy.ID.Hex()
+	list, err := r.k8sDynamic.Resource(intentGVR).Namespace(r.crNamespace).List(ctx, metav1.ListOptions{LabelSelector: sel})
+	if err != nil {
+		return err
+	}
+	for i := range list.Items {
+		obj := &list.Items[i]
+		spec, found, err := unstructured.NestedMap(obj.Object, "spec")
+		if err != nil {
+			return fmt.Errorf("read spec for intent CR %s: %w", obj.GetName(), err)
+		}
+		if !found {
+			return fmt.Errorf("spec not found for intent CR %s", obj.GetName())
+		}
+		spec["creatorID"] = strategy.CreatorID.Hex()
+		spec["updaterID"] = strategy.UpdaterID.Hex()
+		spec["updatedTime"] = now
+		delete(spec, "teamID")
+		if !strategy.TeamID.IsZero() {
+			spec["teamID"] = strategy.TeamID.Hex()
+		}
+		if err := unstructured.SetNestedField(obj.Object, spec, "spec"); err != nil {
+			return err
+		}
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[labelCreatorID] = strategy.CreatorID.Hex()
+		delete(labels, labelTeamID)
+		if !strategy.TeamID.IsZero() {
+			labels[labelTeamID] = strategy.TeamID.Hex()
+		}
+		obj.SetLabels(labels)
+
+		if _, err := r.k8sDynamic.Resource(intentGVR).Namespace(r.crNamespace).Update(ctx, obj, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("update intent CR %s: %w", obj.GetName(), err)
+		}
+	}
+	return nil
+}
+
 // ---------------------------------------------------------------------------
 // Conversion helpers
 // ---------------------------------------------------------------------------
@@ -329,6 +377,31 @@ func domainStrategyToUnstructured(s *domain.ScheduleStrategy, namespace string)
 	for i, ns := range s.K8sNamespace {
 		k8sNS[i] = ns
 	}
+	spec := map[string]interface{}{
+		"strategyNamespace": s.StrategyNamespace,
+		"labelSelectors":    labelSelectors,
+		"k8sNamespaces":     k8sNS,
+		"commandRegex":      s.CommandRegex,
+		"priority":          int64(s.Priority),
+		"executionTime":     s.ExecutionTime,
+		"creatorID":         s.CreatorID.Hex(),
+		"updaterID":         s.UpdaterID.Hex(),
+		"createdTime":       s.CreatedTime,
+		"updatedTime":       s.UpdatedTime,
+	}
+	if len(s.CommandMatchTargets) > 0 {
+		spec["commandMatchTargets"] = commandMatchTargetsToUnstructured(s.CommandMatchTargets)
+	}
+	if s.ThreadRegex != "" {
+		spec["threadRegex"] = s.ThreadRegex
+	}
+	labels := map[string]interface{}{
+		labelCreatorID: s.CreatorID.Hex(),
+	}
+	if !s.TeamID.IsZero() {
+		spec["teamID"] = s.TeamID.Hex()
+		labels[labelTeamID] = s.TeamID.Hex()
+	}
 	return &unstructured.Unstructured{
 		Object: map[string]interface{}{
 			"apiVersion": "gthulhu.io/v1alpha1",
@@ -336,22 +409,9 @@ func domainStrategyToUnstructured(s *domain.ScheduleStrategy, namespace string)
 			"metadata": map[string]interface{}{
 				"name":      s.ID.Hex(),
 				"namespace": namespace,
-				"labels": map[string]interface{}{
-					labelCreatorID: s.CreatorID.Hex(),
-				},
-			},
-			"spec": map[string]interface{}{
-				"strategyNamespace": s.StrategyNamespace,
-				"labelSelectors":    labelSelectors,
-				"k8sNamespaces":     k8sNS,
-				"commandRegex":      s.CommandRegex,
-				"priority":          int64(s.Priority),
-				"executionTime":     s.ExecutionTime,
-				"creatorID":         s.CreatorID.Hex(),
-				"updaterID":         s.UpdaterID.Hex(),
-				"createdTime":       s.CreatedTime,
-				"updatedTime":       s.UpdatedTime,
+				"labels":    labels,
 			},
+			"spec": spec,
 		},
 	}
 }
@@ -391,6 +451,12 @@ func unstructuredToDomainStrategy(obj *unstructured.Unstructured) (*domain.Sched
 	}
 	strategy.UpdaterID = updaterID
 
+	teamID, err := parseOptionalObjectIDField(spec, "teamID")
+	if err != nil {
+		return nil, fmt.Errorf("invalid teamID in strategy CR %s: %w", obj.GetName(), err)
+	}
+	strategy.TeamID = teamID
+
 	if raw, ok := spec["labelSelectors"]; ok {
 		if arr, ok := raw.([]interface{}); ok {
 			for _, item := range arr {
@@ -414,6 +480,8 @@ func unstructuredToDomainStrategy(obj *unstructured.Unstructured) (*domain.Sched
 			}
 		}
 	}
+	strategy.CommandMatchTargets = getCommandMatchTargets(spec, "commandMatchTargets")
+	strategy.ThreadRegex = getStr(spec, "threadRegex")
 	return strategy, nil
 }
 
@@ -422,6 +490,44 @@ func domainIntentToUnstructured(intent *domain.ScheduleIntent, namespace string)
 	for k, v := range intent.PodLabels {
 		podLabels[k] = v
 	}
+	spec := map[string]interface{}{
+		"strategyID":    intent.StrategyID.Hex(),
+		"podID":         intent.PodID,
+		"podName":       intent.PodName,
+		"nodeID":        intent.NodeID,
+		"k8sNamespace":  intent.K8sNamespace,
+		"commandRegex":  intent.CommandRegex,
+		"priority":      int64(intent.Priority),
+		"executionTime": intent.ExecutionTime,
+		"podLabels":     podLabels,
+		"state":         int64(intent.State),
+		"creatorID":     intent.CreatorID.Hex(),
+		"updaterID":     intent.UpdaterID.Hex(),
+		"createdTime":   intent.CreatedTime,
+		"updatedTime":   intent.UpdatedTime,
+	}
+	if len(intent.CommandMatchTargets) > 0 {
+		spec["commandMatchTargets"] = commandMatchTargetsToUnstructured(intent.CommandMatchTargets)
+	}
+	if intent.ThreadRegex != "" {
+		spec["threadRegex"] = intent.ThreadRegex
+	}
+	if len(intent.ContainerNames) > 0 {
+		containerNames := make(map[string]interface{}, len(intent.ContainerNames))
+		for k, v := range intent.ContainerNames {
+			containerNames[k] = v
+		}
+		spec["containerNames"] = containerNames
+	}
+	labels := map[string]interface{}{
+		labelCreatorID:  intent.CreatorID.Hex(),
+		labelStrategyID: intent.StrategyID.Hex(),
+		labelState:      strconv.Itoa(int(intent.State)),
+	}
+	if !intent.TeamID.IsZero() {
+		spec["teamID"] = intent.TeamID.Hex()
+		labels[labelTeamID] = intent.TeamID.Hex()
+	}
 	return &unstructured.Unstructured{
 		Object: map[string]interface{}{
 			"apiVersion": "gthulhu.io/v1alpha1",
@@ -429,28 +535,9 @@ func domainIntentToUnstructured(intent *domain.ScheduleIntent, namespace string)
 			"metadata": map[string]interface{}{
 				"name":      intent.ID.Hex(),
 				"namespace": namespace,
-				"labels": map[string]interface{}{
-					labelCreatorID:  intent.CreatorID.Hex(),
-					labelStrategyID: intent.StrategyID.Hex(),
-					labelState:      strconv.Itoa(int(intent.State)),
-				},
-			},
-			"spec": map[string]interface{}{
-				"strategyID":    intent.StrategyID.Hex(),
-				"podID":         intent.PodID,
-				"podName":       intent.PodName,
-				"nodeID":        intent.NodeID,
-				"k8sNamespace":  intent.K8sNamespace,
-				"commandRegex":  intent.CommandRegex,
-				"priority":      int64(intent.Priority),
-				"executionTime": intent.ExecutionTime,
-				"podLabels":     podLabels,
-				"state":         int64(intent.State),
-				"creatorID":     intent.CreatorID.Hex(),
-				"updaterID":     intent.UpdaterID.Hex(),
-				"createdTime":   intent.CreatedTime,
-				"updatedTime":   intent.UpdatedTime,
+				"labels":    labels,
 			},
+			"spec": spec,
 		},
 	}
 }
@@ -500,6 +587,12 @@ func unstructuredToDomainIntent(obj *unstructured.Unstructured) (*domain.Schedul
 	}
 	intent.StrategyID = strategyID
 
+	teamID, err := parseOptionalObjectIDField(spec, "teamID")
+	if err != nil {
+		return nil, fmt.Errorf("invalid teamID in intent CR %s: %w", obj.GetName(), err)
+	}
+	intent.TeamID = teamID
+
 	if raw, ok := spec["podLabels"]; ok {
 		if m, ok := raw.(map[string]interface{}); ok {
 			intent.PodLabels = make(map[string]string, len(m))
@@ -510,6 +603,18 @@ func unstructuredToDomainIntent(obj *unstructured.Unstructured) (*domain.Schedul
 			}
 		}
 	}
+	intent.CommandMatchTargets = getCommandMatchTargets(spec, "commandMatchTargets")
+	intent.ThreadRegex = getStr(spec, "threadRegex")
+	if raw, ok := spec["containerNames"]; ok {
+		if m, ok := raw.(map[string]interface{}); ok {
+			intent.ContainerNames = make(map[string]string, len(m))
+			for k, v := range m {
+				if s, ok := v.(string); ok {
+					intent.ContainerNames[k] = s
+				}
+			}
+		}
+	}
 	return intent, nil
 }
 
@@ -518,7 +623,7 @@ func unstructuredToDomainIntent(obj *unstructured.Unstructured) (*domain.Schedul
 // ---------------------------------------------------------------------------
 
 func matchesStrategyFilter(s *domain.ScheduleStrategy, opt *domain.QueryStrategyOptions) bool {
-	if len(opt.CreatorIDs) > 0 && !containsOID(opt.CreatorIDs, s.CreatorID) {
+	if !matchesOwner(opt.CreatorIDs, opt.TeamIDs, s.CreatorID, s.TeamID) {
 		return false
 	}
 	if len(opt.K8SNamespaces) > 0 {
@@ -530,7 +635,7 @@ func matchesStrategyFilter(s *domain.ScheduleStrategy, opt *domain.QueryStrategy
 }
 
 func matchesIntentFilter(intent *domain.ScheduleIntent, opt *domain.QueryIntentOptions) bool {
-	if len(opt.CreatorIDs) > 0 && !containsOID(opt.CreatorIDs, intent.CreatorID) {
+	if !matchesOwner(opt.CreatorIDs, opt.TeamIDs, intent.CreatorID, intent.TeamID) {
 		return false
 	}
 	if len(opt.StrategyIDs) > 0 && !containsOID(opt.StrategyIDs, intent.StrategyID) {
@@ -548,10 +653,31 @@ func matchesIntentFilter(intent *domain.ScheduleIntent, opt *domain.QueryIntentO
 	return true
 }
 
+// matchesOwner reports whether a resource created by creatorID for teamID was created by one of
+// creatorIDs or belongs to one of teamIDs, empty filters match every resource
+func matchesOwner(creatorIDs, teamIDs []bson.ObjectID, creatorID, teamID bson.ObjectID) bool {
+	if len(creatorIDs) == 0 && len(teamIDs) == 0 {
+		return true
+	}
+	return containsOID(creatorIDs, creatorID) || (!teamID.IsZero() && containsOID(teamIDs, teamID))
+}
+
 // ---------------------------------------------------------------------------
 // Utility helpers
 // ---------------------------------------------------------------------------
 
+// buildOwnerLabelSelector selects the resources of creatorIDs or teamIDs. Label selectors can't
+// express the union of both, it is left to matchesOwner then.
+func buildOwnerLabelSelector(creatorIDs, teamIDs []bson.ObjectID) string {
+	if len(creatorIDs) > 0 && len(teamIDs) > 0 {
+		return ""
+	}
+	if len(teamIDs) > 0 {
+		return buildLabelSelector(teamIDs, labelTeamID)
+	}
+	return buildLabelSelector(creatorIDs, labelCreatorID)
+}
+
 func buildLabelSelector(ids []bson.ObjectID, label string) string {
 	if len(ids) == 0 {
 		return ""
@@ -639,11 +765,41 @@ func parseObjectIDField(m map[string]interface{}, key string) (bson.ObjectID, er
 	return id, nil
 }
 
+// parseOptionalObjectIDField is parseObjectIDField for fields that may be missing, which yields the zero ID
+func parseOptionalObjectIDField(m map[string]interface{}, key string) (bson.ObjectID, error) {
+	if getStr(m, key) == "" {
+		return bson.ObjectID{}, nil
+	}
+	return parseObjectIDField(m, key)
+}
+
 func getStr(m map[string]interface{}, key string) string {
 	v, _ := m[key].(string)
 	return v
 }
 
+func commandMatchTargetsToUnstructured(targets []domain.CommandMatchTarget) []interface{} {
+	results := make([]interface{}, len(targets))
+	for i, target := range targets {
+		results[i] = string(target)
+	}
+	return results
+}
+
+func getCommandMatchTargets(m map[string]interface{}, key string) []domain.CommandMatchTarget {
+	arr, ok := m[key].([]interface{})
+	if !ok {
+		return nil
+	}
+	targets := make([]domain.CommandMatchTarget, 0, len(arr))
+	for _, item := range arr {
+		if s, ok := item.(string); ok {
+			targets = append(targets, domain.CommandMatchTarget(s))
+		}
+	}
+	return targets
+}
+
 func getInt64(m map[string]interface{}, key string) int64 {
 	switch v := m[key].(type) {
 	case int64:
@@ -121,6 +121,66 @@ func TestCRQueryStrategiesByCreator(t *testing.T) {
 	assert.Equal(t, s1.ID, opt.Result[0].ID)
 }
 
+func TestCRQueryStrategiesByCreatorOrTeam(t *testing.T) {
+	r := newTestCRRepo()
+	ctx := context.Background()
+
+	creator1 := bson.NewObjectID()
+	creator2 := bson.NewObjectID()
+	team := bson.NewObjectID()
+
+	personal := &domain.ScheduleStrategy{BaseEntity: domain.BaseEntity{CreatorID: creator1, UpdaterID: creator1}}
+	shared := &domain.ScheduleStrategy{BaseEntity: domain.BaseEntity{CreatorID: creator2, UpdaterID: creator2}, TeamID: team}
+	other := &domain.ScheduleStrategy{BaseEntity: domain.BaseEntity{CreatorID: creator2, UpdaterID: creator2}}
+	for _, s := range []*domain.ScheduleStrategy{personal, shared, other} {
+		require.NoError(t, r.InsertStrategyAndIntents(ctx, s, []*domain.ScheduleIntent{}))
+	}
+
+	opt := &domain.QueryStrategyOptions{TeamIDs: []bson.ObjectID{team}}
+	require.NoError(t, r.QueryStrategies(ctx, opt))
+	require.Len(t, opt.Result, 1)
+	assert.Equal(t, shared.ID, opt.Result[0].ID)
+	assert.Equal(t, team, opt.Result[0].TeamID)
+
+	// creators and teams are matched with OR
+	opt = &domain.QueryStrategyOptions{CreatorIDs: []bson.ObjectID{creator1}, TeamIDs: []bson.ObjectID{team}}
+	require.NoError(t, r.QueryStrategies(ctx, opt))
+	ids := []bson.ObjectID{}
+	for _, s := range opt.Result {
+		ids = append(ids, s.ID)
+	}
+	assert.ElementsMatch(t, []bson.ObjectID{personal.ID, shared.ID}, ids)
+}
+
+func TestCRUpdateIntentsOwner(t *testing.T) {
+	r := newTestCRRepo()
+	ctx := context.Background()
+
+	creator := bson.NewObjectID()
+	strategy := &domain.ScheduleStrategy{BaseEntity: domain.BaseEntity{CreatorID: creator, UpdaterID: creator}}
+	intents := []*domain.ScheduleIntent{
+		{BaseEntity: domain.BaseEntity{CreatorID: creator, UpdaterID: creator}, PodID: "p1", NodeID: "n1", State: domain.IntentStateInitialized},
+	}
+	require.NoError(t, r.InsertStrategyAndIntents(ctx, strategy, intents))
+
+	newCreator := bson.NewObjectID()
+	team := bson.NewObjectID()
+	strategy.CreatorID = newCreator
+	strategy.UpdaterID = newCreator
+	strategy.TeamID = team
+	require.NoError(t, r.UpdateIntentsOwner(ctx, strategy))
+
+	opt := &domain.QueryIntentOptions{TeamIDs: []bson.ObjectID{team}}
+	require.NoError(t, r.QueryIntents(ctx, opt))
+	require.Len(t, opt.Result, 1)
+	assert.Equal(t, newCreator, opt.Result[0].CreatorID)
+	assert.Equal(t, team, opt.Result[0].TeamID)
+
+	opt = &domain.QueryIntentOptions{CreatorIDs: []bson.ObjectID{creator}}
+	require.NoError(t, r.QueryIntents(ctx, opt))
+	assert.Empty(t, opt.Result)
+}
+
 func TestCRDeleteStrategy(t *testing.T) {
 	r := newTestCRRepo()
 	ctx := context.Background()
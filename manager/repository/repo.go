@@ -75,9 +75,22 @@ type repo struct {
 }
 
 const (
-	userCollection        = "users"
-	roleCollection        = "roles"
-	permissionCollection  = "permissions"
-	auditLogCollection    = "audit_logs"
-	defaultTimestampField = "timestamp"
+	userCollection         = "users"
+	roleCollection         = "roles"
+	permissionCollection   = "permissions"
+	auditLogCollection     = "audit_logs"
+	metricSampleCollection = "metric_samples"
+	alertRuleCollection    = "alert_rules"
+	alertCollection        = "alerts"
+	defaultTimestampField  = "timestamp"
+
+	webhookSubscriptionCollection = "webhook_subscriptions"
+	serviceAccountCollection      = "service_accounts"
+	apiKeyCollection              = "api_keys"
+	refreshTokenCollection        = "refresh_tokens"
+	revokedTokenCollection        = "revoked_tokens"
+	cacheVersionCollection        = "cache_versions"
+	teamCollection                = "teams"
+	alertNodeStateCollection      = "alert_node_states"
+	leaseCollection               = "leases"
 )
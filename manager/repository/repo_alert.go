@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func (r *repo) CreateAlertRule(ctx context.Context, rule *domain.AlertRule) error {
+	if rule == nil {
+		return errors.New("nil alert rule")
+	}
+
+	now := time.Now().UnixMilli()
+	if rule.ID.IsZero() {
+		rule.ID = bson.NewObjectID()
+	}
+	if rule.CreatedTime == 0 {
+		rule.CreatedTime = now
+	}
+	rule.UpdatedTime = now
+
+	res, err := r.db.Collection(alertRuleCollection).InsertOne(ctx, rule)
+	if err != nil {
+		return fmt.Errorf("create alert rule, err: %w", err)
+	}
+	if oid, ok := res.InsertedID.(bson.ObjectID); ok {
+		rule.ID = oid
+	}
+	return nil
+}
+
+func (r *repo) UpdateAlertRule(ctx context.Context, rule *domain.AlertRule) error {
+	if rule == nil {
+		return errors.New("nil alert rule")
+	}
+	if rule.ID.IsZero() {
+		return errors.New("alert rule id is required")
+	}
+
+	rule.UpdatedTime = time.Now().UnixMilli()
+	res, err := r.db.Collection(alertRuleCollection).ReplaceOne(ctx, bson.M{"_id": rule.ID}, rule)
+	if err != nil {
+		return fmt.Errorf("update alert rule, err: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *repo) DeleteAlertRule(ctx context.Context, ruleID bson.ObjectID) error {
+	res, err := r.db.Collection(alertRuleCollection).DeleteOne(ctx, bson.M{"_id": ruleID})
+	if err != nil {
+		return fmt.Errorf("delete alert rule, err: %w", err)
+	}
+	if res.DeletedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *repo) QueryAlertRules(ctx context.Context, opt *domain.QueryAlertRuleOptions) error {
+	if opt == nil {
+		return errors.New("nil query options")
+	}
+
+	filter := bson.M{}
+	if len(opt.IDs) > 0 {
+		filter["_id"] = bson.M{"$in": opt.IDs}
+	}
+
+	cursor, err := r.db.Collection(alertRuleCollection).Find(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("find alert rules, err: %w", err)
+	}
+
+	var result []*domain.AlertRule
+	if err := cursor.All(ctx, &result); err != nil {
+		return fmt.Errorf("decode alert rules, err: %w", err)
+	}
+	opt.Result = result
+	return nil
+}
+
+// SaveAlert inserts a new alert or replaces an existing one
+func (r *repo) SaveAlert(ctx context.Context, alert *domain.Alert) error {
+	if alert == nil {
+		return errors.New("nil alert")
+	}
+
+	if alert.ID.IsZero() {
+		alert.ID = bson.NewObjectID()
+		if _, err := r.db.Collection(alertCollection).InsertOne(ctx, alert); err != nil {
+			return fmt.Errorf("create alert, err: %w", err)
+		}
+		return nil
+	}
+	res, err := r.db.Collection(alertCollection).ReplaceOne(ctx, bson.M{"_id": alert.ID}, alert)
+	if err != nil {
+		return fmt.Errorf("update alert, err: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *repo) QueryAlerts(ctx context.Context, opt *domain.QueryAlertOptions) error {
+	if opt == nil {
+		return errors.New("nil query options")
+	}
+
+	filter := bson.M{}
+	if len(opt.RuleIDs) > 0 {
+		filter["ruleID"] = bson.M{"$in": opt.RuleIDs}
+	}
+	if len(opt.NodeIDs) > 0 {
+		filter["nodeID"] = bson.M{"$in": opt.NodeIDs}
+	}
+	if len(opt.States) > 0 {
+		filter["state"] = bson.M{"$in": opt.States}
+	}
+
+	findOpts := options.Find().SetSort(bson.D{{Key: "startsAt", Value: -1}})
+	if opt.Limit > 0 {
+		findOpts.SetLimit(opt.Limit)
+	}
+	cursor, err := r.db.Collection(alertCollection).Find(ctx, filter, findOpts)
+	if err != nil {
+		return fmt.Errorf("find alerts, err: %w", err)
+	}
+
+	var result []*domain.Alert
+	if err := cursor.All(ctx, &result); err != nil {
+		return fmt.Errorf("decode alerts, err: %w", err)
+	}
+	opt.Result = result
+	return nil
+}
+
+func (r *repo) ListAlertNodeStates(ctx context.Context) ([]*domain.AlertNodeState, error) {
+	cursor, err := r.db.Collection(alertNodeStateCollection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("find alert node states, err: %w", err)
+	}
+
+	var result []*domain.AlertNodeState
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, fmt.Errorf("decode alert node states, err: %w", err)
+	}
+	return result, nil
+}
+
+func (r *repo) SaveAlertNodeState(ctx context.Context, state *domain.AlertNodeState) error {
+	if state == nil {
+		return errors.New("nil alert node state")
+	}
+
+	_, err := r.db.Collection(alertNodeStateCollection).ReplaceOne(ctx, bson.M{"_id": state.NodeID}, state,
+		options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("save alert node state %s, err: %w", state.NodeID, err)
+	}
+	return nil
+}
+
+func (r *repo) DeleteAlertNodeStates(ctx context.Context, nodeIDs []string) error {
+	if len(nodeIDs) == 0 {
+		return nil
+	}
+
+	_, err := r.db.Collection(alertNodeStateCollection).DeleteMany(ctx, bson.M{"_id": bson.M{"$in": nodeIDs}})
+	if err != nil {
+		return fmt.Errorf("delete alert node states, err: %w", err)
+	}
+	return nil
+}
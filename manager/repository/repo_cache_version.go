@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type cacheVersion struct {
+	Name    string `bson:"_id"`
+	Version int64  `bson:"version"`
+}
+
+func (r *repo) IncrementCacheVersion(ctx context.Context, name string) (int64, error) {
+	res := r.db.Collection(cacheVersionCollection).FindOneAndUpdate(ctx, bson.M{"_id": name},
+		bson.M{"$inc": bson.M{"version": int64(1)}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After))
+	var version cacheVersion
+	if err := res.Decode(&version); err != nil {
+		return 0, fmt.Errorf("increment cache version %s, err: %w", name, err)
+	}
+	return version.Version, nil
+}
+
+func (r *repo) GetCacheVersion(ctx context.Context, name string) (int64, error) {
+	var version cacheVersion
+	err := r.db.Collection(cacheVersionCollection).FindOne(ctx, bson.M{"_id": name}).Decode(&version)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("get cache version %s, err: %w", name, err)
+	}
+	return version.Version, nil
+}
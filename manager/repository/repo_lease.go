@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type lease struct {
+	Name      string    `bson:"_id"`
+	Holder    string    `bson:"holder"`
+	ExpiresAt time.Time `bson:"expiresAt"`
+}
+
+// AcquireLease updates the lease when holder already has it or it expired. Otherwise the filter
+// matches nothing, the upsert collides with the existing lease and the lease is held by someone else.
+func (r *repo) AcquireLease(ctx context.Context, name string, holder string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	filter := bson.M{
+		"_id": name,
+		"$or": bson.A{
+			bson.M{"holder": holder},
+			bson.M{"expiresAt": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{"$set": lease{Name: name, Holder: holder, ExpiresAt: now.Add(ttl)}}
+	_, err := r.db.Collection(leaseCollection).UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("acquire lease %s, err: %w", name, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func (r *repo) RecordLoginSuccess(ctx context.Context, id bson.ObjectID, attempt domain.LoginAttempt) error {
+	res, err := r.db.Collection(userCollection).UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set":   bson.M{"lastLoginTime": attempt.Time, "lastLoginIP": attempt.IP},
+		"$unset": bson.M{"failedLoginCount": "", "lockedUntil": ""},
+		"$push":  bson.M{"loginHistory": bson.M{"$each": bson.A{attempt}, "$slice": -domain.MaxLoginHistory}},
+	})
+	if err != nil {
+		return fmt.Errorf("record login success, err: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// RecordLoginFailure updates the user with a pipeline so that concurrent attempts can't lose
+// increments, the count restarts once it locked the user
+func (r *repo) RecordLoginFailure(ctx context.Context, id bson.ObjectID, attempt domain.LoginAttempt, lockout domain.LoginLockout) (*domain.User, error) {
+	pipeline := []bson.M{{"$set": bson.M{
+		"failedLoginCount": bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$failedLoginCount", 0}}, 1}},
+		"loginHistory": bson.M{"$slice": bson.A{
+			bson.M{"$concatArrays": bson.A{bson.M{"$ifNull": bson.A{"$loginHistory", bson.A{}}}, bson.A{bson.M{"$literal": attempt}}}},
+			-domain.MaxLoginHistory,
+		}},
+	}}}
+	if lockout.MaxFailedAttempts > 0 {
+		reached := bson.M{"$gte": bson.A{"$failedLoginCount", lockout.MaxFailedAttempts}}
+		pipeline = append(pipeline, bson.M{"$set": bson.M{
+			"lockedUntil":      bson.M{"$cond": bson.A{reached, lockout.LockedUntil, bson.M{"$ifNull": bson.A{"$lockedUntil", "$$REMOVE"}}}},
+			"failedLoginCount": bson.M{"$cond": bson.A{reached, "$$REMOVE", "$failedLoginCount"}},
+		}})
+	}
+	res := r.db.Collection(userCollection).FindOneAndUpdate(ctx, bson.M{"_id": id}, pipeline,
+		options.FindOneAndUpdate().SetReturnDocument(options.After))
+	var user domain.User
+	if err := res.Decode(&user); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("record login failure, err: %w", err)
+	}
+	return &user, nil
+}
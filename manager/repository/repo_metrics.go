@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func (r *repo) InsertMetricSamples(ctx context.Context, samples []*domain.NodeMetricSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	docs := make([]any, 0, len(samples))
+	for _, sample := range samples {
+		docs = append(docs, sample)
+	}
+	if _, err := r.db.Collection(metricSampleCollection).InsertMany(ctx, docs); err != nil {
+		return fmt.Errorf("insert metric samples, err: %w", err)
+	}
+	return nil
+}
+
+func (r *repo) QueryMetricSamples(ctx context.Context, opt *domain.QueryMetricSampleOptions) error {
+	if opt == nil {
+		return errors.New("nil query options")
+	}
+
+	filter := bson.M{}
+	if len(opt.NodeIDs) > 0 {
+		filter["nodeID"] = bson.M{"$in": opt.NodeIDs}
+	}
+	if !opt.From.IsZero() || !opt.To.IsZero() {
+		timeFilter := bson.M{}
+		if !opt.From.IsZero() {
+			timeFilter["$gte"] = opt.From
+		}
+		if !opt.To.IsZero() {
+			timeFilter["$lte"] = opt.To
+		}
+		filter[defaultTimestampField] = timeFilter
+	}
+
+	// the limit keeps the most recent samples, they are put back in chronological order below
+	findOpts := options.Find().SetSort(bson.D{{Key: defaultTimestampField, Value: -1}, {Key: "nodeID", Value: -1}})
+	if opt.Limit > 0 {
+		findOpts.SetLimit(opt.Limit)
+	}
+	cursor, err := r.db.Collection(metricSampleCollection).Find(ctx, filter, findOpts)
+	if err != nil {
+		return fmt.Errorf("find metric samples, err: %w", err)
+	}
+
+	var result []*domain.NodeMetricSample
+	if err := cursor.All(ctx, &result); err != nil {
+		return fmt.Errorf("decode metric samples, err: %w", err)
+	}
+	slices.Reverse(result)
+	opt.Result = result
+	return nil
+}
+
+// SetMetricSampleRetention changes the TTL of the metric time series collection created by the migrations
+func (r *repo) SetMetricSampleRetention(ctx context.Context, retention time.Duration) error {
+	if retention < time.Second {
+		return fmt.Errorf("invalid metric sample retention %s", retention)
+	}
+	cmd := bson.D{
+		{Key: "collMod", Value: metricSampleCollection},
+		{Key: "expireAfterSeconds", Value: int64(retention / time.Second)},
+	}
+	if err := r.db.RunCommand(ctx, cmd).Err(); err != nil {
+		return fmt.Errorf("set metric sample retention, err: %w", err)
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/Gthulhu/api/manager/domain"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 func (r *repo) CreateUser(ctx context.Context, user *domain.User) error {
@@ -66,6 +67,12 @@ func (r *repo) QueryUsers(ctx context.Context, opt *domain.QueryUserOptions) err
 	if len(opt.UserNames) > 0 {
 		filter["username"] = bson.M{"$in": opt.UserNames}
 	}
+	if len(opt.ExternalIDs) > 0 {
+		filter["externalID"] = bson.M{"$in": opt.ExternalIDs}
+	}
+	if len(opt.Roles) > 0 {
+		filter["roles"] = bson.M{"$in": opt.Roles}
+	}
 
 	cursor, err := r.db.Collection(userCollection).Find(ctx, filter)
 	if err != nil {
@@ -123,6 +130,17 @@ func (r *repo) UpdateRole(ctx context.Context, role *domain.Role) error {
 	return nil
 }
 
+func (r *repo) DeleteRole(ctx context.Context, roleID bson.ObjectID) error {
+	res, err := r.db.Collection(roleCollection).DeleteOne(ctx, bson.M{"_id": roleID})
+	if err != nil {
+		return fmt.Errorf("delete role, err: %w", err)
+	}
+	if res.DeletedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
 func (r *repo) QueryRoles(ctx context.Context, opt *domain.QueryRoleOptions) error {
 	if opt == nil {
 		return errors.New("nil query options")
@@ -256,8 +274,15 @@ func (r *repo) QueryAuditLogs(ctx context.Context, opt *domain.QueryAuditLogOpti
 		}
 		filter[defaultTimestampField] = timeFilter
 	}
+	if len(opt.Actions) > 0 {
+		filter["action"] = bson.M{"$in": opt.Actions}
+	}
 
-	cursor, err := r.db.Collection(auditLogCollection).Find(ctx, filter)
+	findOpts := options.Find().SetSort(bson.D{{Key: defaultTimestampField, Value: -1}})
+	if opt.Limit > 0 {
+		findOpts.SetLimit(opt.Limit)
+	}
+	cursor, err := r.db.Collection(auditLogCollection).Find(ctx, filter, findOpts)
 	if err != nil {
 		return fmt.Errorf("find audit logs, err: %w", err)
 	}
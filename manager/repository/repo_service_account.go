@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func (r *repo) CreateServiceAccount(ctx context.Context, account *domain.ServiceAccount) error {
+	if account == nil {
+		return errors.New("nil service account")
+	}
+
+	now := time.Now().UnixMilli()
+	if account.ID.IsZero() {
+		account.ID = bson.NewObjectID()
+	}
+	if account.CreatedTime == 0 {
+		account.CreatedTime = now
+	}
+	account.UpdatedTime = now
+
+	res, err := r.db.Collection(serviceAccountCollection).InsertOne(ctx, account)
+	if err != nil {
+		return fmt.Errorf("create service account, err: %w", err)
+	}
+	if oid, ok := res.InsertedID.(bson.ObjectID); ok {
+		account.ID = oid
+	}
+	return nil
+}
+
+func (r *repo) UpdateServiceAccount(ctx context.Context, account *domain.ServiceAccount) error {
+	if account == nil {
+		return errors.New("nil service account")
+	}
+	if account.ID.IsZero() {
+		return errors.New("service account id is required")
+	}
+
+	account.UpdatedTime = time.Now().UnixMilli()
+	res, err := r.db.Collection(serviceAccountCollection).ReplaceOne(ctx, bson.M{"_id": account.ID}, account)
+	if err != nil {
+		return fmt.Errorf("update service account, err: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *repo) DeleteServiceAccount(ctx context.Context, accountID bson.ObjectID) error {
+	res, err := r.db.Collection(serviceAccountCollection).DeleteOne(ctx, bson.M{"_id": accountID})
+	if err != nil {
+		return fmt.Errorf("delete service account, err: %w", err)
+	}
+	if res.DeletedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *repo) QueryServiceAccounts(ctx context.Context, opt *domain.QueryServiceAccountOptions) error {
+	if opt == nil {
+		return errors.New("nil query options")
+	}
+
+	filter := bson.M{}
+	if len(opt.IDs) > 0 {
+		filter["_id"] = bson.M{"$in": opt.IDs}
+	}
+	if len(opt.Names) > 0 {
+		filter["name"] = bson.M{"$in": opt.Names}
+	}
+	if len(opt.Roles) > 0 {
+		filter["roles"] = bson.M{"$in": opt.Roles}
+	}
+
+	cursor, err := r.db.Collection(serviceAccountCollection).Find(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("find service accounts, err: %w", err)
+	}
+
+	var result []*domain.ServiceAccount
+	if err := cursor.All(ctx, &result); err != nil {
+		return fmt.Errorf("decode service accounts, err: %w", err)
+	}
+	opt.Result = result
+	return nil
+}
+
+func (r *repo) CreateAPIKey(ctx context.Context, key *domain.APIKey) error {
+	if key == nil {
+		return errors.New("nil api key")
+	}
+
+	now := time.Now().UnixMilli()
+	if key.ID.IsZero() {
+		key.ID = bson.NewObjectID()
+	}
+	if key.CreatedTime == 0 {
+		key.CreatedTime = now
+	}
+	key.UpdatedTime = now
+
+	res, err := r.db.Collection(apiKeyCollection).InsertOne(ctx, key)
+	if err != nil {
+		return fmt.Errorf("create api key, err: %w", err)
+	}
+	if oid, ok := res.InsertedID.(bson.ObjectID); ok {
+		key.ID = oid
+	}
+	return nil
+}
+
+// RevokeAPIKey marks the key revoked, a key already revoked keeps its original revocation time
+func (r *repo) RevokeAPIKey(ctx context.Context, keyID bson.ObjectID, revokedAt int64) error {
+	res, err := r.db.Collection(apiKeyCollection).UpdateOne(ctx,
+		bson.M{"_id": keyID},
+		[]bson.M{{"$set": bson.M{
+			"revokedAt":   bson.M{"$ifNull": bson.A{"$revokedAt", revokedAt}},
+			"updatedTime": revokedAt,
+		}}},
+	)
+	if err != nil {
+		return fmt.Errorf("revoke api key, err: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// TouchAPIKey records the last use of the key without touching the rest of the document
+func (r *repo) TouchAPIKey(ctx context.Context, keyID bson.ObjectID, usedAt int64) error {
+	_, err := r.db.Collection(apiKeyCollection).UpdateOne(ctx,
+		bson.M{"_id": keyID},
+		bson.M{"$max": bson.M{"lastUsedAt": usedAt}},
+	)
+	if err != nil {
+		return fmt.Errorf("touch api key, err: %w", err)
+	}
+	return nil
+}
+
+func (r *repo) DeleteAPIKeysByServiceAccountID(ctx context.Context, accountID string) error {
+	_, err := r.db.Collection(apiKeyCollection).DeleteMany(ctx, bson.M{"serviceAccountID": accountID})
+	if err != nil {
+		return fmt.Errorf("delete api keys of service account %s, err: %w", accountID, err)
+	}
+	return nil
+}
+
+func (r *repo) QueryAPIKeys(ctx context.Context, opt *domain.QueryAPIKeyOptions) error {
+	if opt == nil {
+		return errors.New("nil query options")
+	}
+
+	filter := bson.M{}
+	if len(opt.IDs) > 0 {
+		filter["_id"] = bson.M{"$in": opt.IDs}
+	}
+	if len(opt.KeyIDs) > 0 {
+		filter["keyID"] = bson.M{"$in": opt.KeyIDs}
+	}
+	if len(opt.ServiceAccountIDs) > 0 {
+		filter["serviceAccountID"] = bson.M{"$in": opt.ServiceAccountIDs}
+	}
+
+	cursor, err := r.db.Collection(apiKeyCollection).Find(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("find api keys, err: %w", err)
+	}
+
+	var result []*domain.APIKey
+	if err := cursor.All(ctx, &result); err != nil {
+		return fmt.Errorf("decode api keys, err: %w", err)
+	}
+	opt.Result = result
+	return nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func (r *repo) CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	if token == nil {
+		return errors.New("nil refresh token")
+	}
+	if token.ID.IsZero() {
+		token.ID = bson.NewObjectID()
+	}
+	if token.CreatedTime == 0 {
+		token.CreatedTime = time.Now().UnixMilli()
+	}
+
+	_, err := r.db.Collection(refreshTokenCollection).InsertOne(ctx, token)
+	if err != nil {
+		return fmt.Errorf("create refresh token, err: %w", err)
+	}
+	return nil
+}
+
+// UseRefreshToken atomically marks the token revoked and returns the token as it was before, a
+// non-zero RevokedAt in the result means the token had already been used
+func (r *repo) UseRefreshToken(ctx context.Context, tokenHash string, usedAt int64) (*domain.RefreshToken, error) {
+	res := r.db.Collection(refreshTokenCollection).FindOneAndUpdate(ctx,
+		bson.M{"tokenHash": tokenHash},
+		[]bson.M{{"$set": bson.M{"revokedAt": bson.M{"$ifNull": bson.A{"$revokedAt", usedAt}}}}},
+		options.FindOneAndUpdate().SetReturnDocument(options.Before),
+	)
+	var token domain.RefreshToken
+	if err := res.Decode(&token); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("use refresh token, err: %w", err)
+	}
+	return &token, nil
+}
+
+func (r *repo) RevokeRefreshTokens(ctx context.Context, opt domain.RevokeRefreshTokensOptions, revokedAt int64) error {
+	filter := bson.M{"revokedAt": bson.M{"$exists": false}}
+	if !opt.UserID.IsZero() {
+		filter["userID"] = opt.UserID
+	}
+	if len(opt.FamilyIDs) > 0 {
+		filter["familyID"] = bson.M{"$in": opt.FamilyIDs}
+	}
+	if len(opt.AccessTokenIDs) > 0 {
+		filter["accessTokenID"] = bson.M{"$in": opt.AccessTokenIDs}
+	}
+	if len(opt.TokenHashes) > 0 {
+		filter["tokenHash"] = bson.M{"$in": opt.TokenHashes}
+	}
+	if len(filter) == 1 {
+		return errors.New("revoking refresh tokens requires a filter")
+	}
+
+	_, err := r.db.Collection(refreshTokenCollection).UpdateMany(ctx, filter, bson.M{"$set": bson.M{"revokedAt": revokedAt}})
+	if err != nil {
+		return fmt.Errorf("revoke refresh tokens, err: %w", err)
+	}
+	return nil
+}
+
+func (r *repo) RevokeAccessToken(ctx context.Context, token *domain.RevokedToken) error {
+	if token == nil || token.JTI == "" {
+		return errors.New("revoked token jti is required")
+	}
+	_, err := r.db.Collection(revokedTokenCollection).ReplaceOne(ctx,
+		bson.M{"_id": token.JTI}, token, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("revoke access token, err: %w", err)
+	}
+	return nil
+}
+
+func (r *repo) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	count, err := r.db.Collection(revokedTokenCollection).CountDocuments(ctx, bson.M{"_id": jti}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, fmt.Errorf("query revoked token, err: %w", err)
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func (r *repo) CreateTeam(ctx context.Context, team *domain.Team) error {
+	if team == nil {
+		return errors.New("nil team")
+	}
+
+	now := time.Now().UnixMilli()
+	if team.ID.IsZero() {
+		team.ID = bson.NewObjectID()
+	}
+	if team.CreatedTime == 0 {
+		team.CreatedTime = now
+	}
+	team.UpdatedTime = now
+
+	res, err := r.db.Collection(teamCollection).InsertOne(ctx, team)
+	if err != nil {
+		return fmt.Errorf("create team, err: %w", err)
+	}
+	if oid, ok := res.InsertedID.(bson.ObjectID); ok {
+		team.ID = oid
+	}
+	return nil
+}
+
+func (r *repo) UpdateTeam(ctx context.Context, team *domain.Team) error {
+	if team == nil {
+		return errors.New("nil team")
+	}
+	if team.ID.IsZero() {
+		return errors.New("team id is required")
+	}
+
+	team.UpdatedTime = time.Now().UnixMilli()
+	res, err := r.db.Collection(teamCollection).ReplaceOne(ctx, bson.M{"_id": team.ID}, team)
+	if err != nil {
+		return fmt.Errorf("update team, err: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *repo) DeleteTeam(ctx context.Context, teamID bson.ObjectID) error {
+	res, err := r.db.Collection(teamCollection).DeleteOne(ctx, bson.M{"_id": teamID})
+	if err != nil {
+		return fmt.Errorf("delete team, err: %w", err)
+	}
+	if res.DeletedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *repo) QueryTeams(ctx context.Context, opt *domain.QueryTeamOptions) error {
+	if opt == nil {
+		return errors.New("nil query options")
+	}
+
+	filter := bson.M{}
+	if len(opt.IDs) > 0 {
+		filter["_id"] = bson.M{"$in": opt.IDs}
+	}
+	if len(opt.Names) > 0 {
+		filter["name"] = bson.M{"$in": opt.Names}
+	}
+	if len(opt.MemberIDs) > 0 {
+		filter["memberIDs"] = bson.M{"$in": opt.MemberIDs}
+	}
+
+	cursor, err := r.db.Collection(teamCollection).Find(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("find teams, err: %w", err)
+	}
+
+	var result []*domain.Team
+	if err := cursor.All(ctx, &result); err != nil {
+		return fmt.Errorf("decode teams, err: %w", err)
+	}
+	opt.Result = result
+	return nil
+}
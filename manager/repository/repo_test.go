@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/Gthulhu/api/config"
 	"github.com/Gthulhu/api/manager/domain"
@@ -147,3 +148,206 @@ func (suite *RepositoryTestSuite) TestCreateRoleAndPermission() {
 	suite.Len(permOpts.Result, 1, "expect one permission")
 	suite.Equal(perm.Description, permOpts.Result[0].Description, "permission description should match")
 }
+
+func (suite *RepositoryTestSuite) TestInsertAndQueryMetricSamples() {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	samples := []*domain.NodeMetricSample{}
+	for i := 0; i < 3; i++ {
+		for _, nodeID := range []string{"node-a", "node-b"} {
+			samples = append(samples, &domain.NodeMetricSample{
+				NodeID: nodeID,
+				MetricSample: domain.MetricSample{
+					Timestamp: start.Add(time.Duration(i) * time.Minute),
+					NrQueued:  uint64(i),
+				},
+			})
+		}
+	}
+	suite.Require().NoError(suite.repo.InsertMetricSamples(suite.ctx, samples))
+
+	opt := &domain.QueryMetricSampleOptions{
+		NodeIDs: []string{"node-b"},
+		From:    start.Add(time.Minute),
+	}
+	suite.Require().NoError(suite.repo.QueryMetricSamples(suite.ctx, opt))
+	suite.Require().Len(opt.Result, 2)
+	suite.Equal("node-b", opt.Result[0].NodeID)
+	suite.True(opt.Result[0].Timestamp.Equal(start.Add(time.Minute)))
+	suite.Equal(uint64(2), opt.Result[1].NrQueued)
+
+	// the limit keeps the most recent samples
+	opt = &domain.QueryMetricSampleOptions{Limit: 3}
+	suite.Require().NoError(suite.repo.QueryMetricSamples(suite.ctx, opt))
+	suite.Require().Len(opt.Result, 3)
+	suite.Equal("node-b", opt.Result[0].NodeID)
+	suite.True(opt.Result[0].Timestamp.Equal(start.Add(time.Minute)))
+	suite.Equal("node-a", opt.Result[1].NodeID)
+	suite.True(opt.Result[1].Timestamp.Equal(start.Add(2 * time.Minute)))
+	suite.Equal("node-b", opt.Result[2].NodeID)
+}
+
+func (suite *RepositoryTestSuite) TestAlertRulesAndAlerts() {
+	rule := &domain.AlertRule{
+		Name:       "congested",
+		Metric:     domain.AlertMetricNrSchedCongested,
+		Comparator: domain.AlertComparatorGreater,
+		Threshold:  10,
+	}
+	suite.Require().NoError(suite.repo.CreateAlertRule(suite.ctx, rule))
+	suite.Require().False(rule.ID.IsZero())
+
+	rule.Threshold = 20
+	suite.Require().NoError(suite.repo.UpdateAlertRule(suite.ctx, rule))
+	ruleOpt := &domain.QueryAlertRuleOptions{IDs: []bson.ObjectID{rule.ID}}
+	suite.Require().NoError(suite.repo.QueryAlertRules(suite.ctx, ruleOpt))
+	suite.Require().Len(ruleOpt.Result, 1)
+	suite.Equal(float64(20), ruleOpt.Result[0].Threshold)
+
+	alert := &domain.Alert{RuleID: rule.ID, RuleName: rule.Name, NodeID: "node-a", State: domain.AlertStateFiring, StartsAt: 1000}
+	suite.Require().NoError(suite.repo.SaveAlert(suite.ctx, alert))
+	suite.Require().False(alert.ID.IsZero())
+	alert.State = domain.AlertStateResolved
+	alert.ResolvedAt = 2000
+	suite.Require().NoError(suite.repo.SaveAlert(suite.ctx, alert))
+
+	alertOpt := &domain.QueryAlertOptions{States: []domain.AlertState{domain.AlertStateFiring}}
+	suite.Require().NoError(suite.repo.QueryAlerts(suite.ctx, alertOpt))
+	suite.Empty(alertOpt.Result)
+	alertOpt = &domain.QueryAlertOptions{RuleIDs: []bson.ObjectID{rule.ID}}
+	suite.Require().NoError(suite.repo.QueryAlerts(suite.ctx, alertOpt))
+	suite.Require().Len(alertOpt.Result, 1)
+	suite.Equal(int64(2000), alertOpt.Result[0].ResolvedAt)
+
+	suite.Require().NoError(suite.repo.DeleteAlertRule(suite.ctx, rule.ID))
+	suite.ErrorIs(suite.repo.DeleteAlertRule(suite.ctx, rule.ID), domain.ErrNotFound)
+}
+
+func (suite *RepositoryTestSuite) TestServiceAccountsAndAPIKeys() {
+	account := &domain.ServiceAccount{Name: "ci", Roles: []string{domain.AdminRole}}
+	suite.Require().NoError(suite.repo.CreateServiceAccount(suite.ctx, account))
+	suite.Require().False(account.ID.IsZero())
+
+	account.Disabled = true
+	suite.Require().NoError(suite.repo.UpdateServiceAccount(suite.ctx, account))
+	accountOpt := &domain.QueryServiceAccountOptions{Names: []string{"ci"}}
+	suite.Require().NoError(suite.repo.QueryServiceAccounts(suite.ctx, accountOpt))
+	suite.Require().Len(accountOpt.Result, 1)
+	suite.True(accountOpt.Result[0].Disabled)
+
+	key := &domain.APIKey{ServiceAccountID: account.ID.Hex(), KeyID: "0123456789abcdef", SecretHash: "secret", ExpiresAt: 5000}
+	suite.Require().NoError(suite.repo.CreateAPIKey(suite.ctx, key))
+	suite.Require().NoError(suite.repo.TouchAPIKey(suite.ctx, key.ID, 3000))
+	suite.Require().NoError(suite.repo.TouchAPIKey(suite.ctx, key.ID, 2000))
+	suite.Require().NoError(suite.repo.RevokeAPIKey(suite.ctx, key.ID, 4000))
+	suite.Require().NoError(suite.repo.RevokeAPIKey(suite.ctx, key.ID, 4500))
+	suite.ErrorIs(suite.repo.RevokeAPIKey(suite.ctx, bson.NewObjectID(), 4000), domain.ErrNotFound)
+
+	keyOpt := &domain.QueryAPIKeyOptions{KeyIDs: []string{"0123456789abcdef"}}
+	suite.Require().NoError(suite.repo.QueryAPIKeys(suite.ctx, keyOpt))
+	suite.Require().Len(keyOpt.Result, 1)
+	suite.Equal(int64(3000), keyOpt.Result[0].LastUsedAt)
+	suite.Equal(int64(4000), keyOpt.Result[0].RevokedAt)
+	ok, err := keyOpt.Result[0].SecretHash.Cmp("secret")
+	suite.Require().NoError(err)
+	suite.True(ok)
+
+	suite.Require().NoError(suite.repo.DeleteServiceAccount(suite.ctx, account.ID))
+	suite.Require().NoError(suite.repo.DeleteAPIKeysByServiceAccountID(suite.ctx, account.ID.Hex()))
+	keyOpt = &domain.QueryAPIKeyOptions{ServiceAccountIDs: []string{account.ID.Hex()}}
+	suite.Require().NoError(suite.repo.QueryAPIKeys(suite.ctx, keyOpt))
+	suite.Empty(keyOpt.Result)
+}
+
+func (suite *RepositoryTestSuite) TestRecordLoginAttempts() {
+	user := &domain.User{UserName: "login-user", Password: domain.EncryptedPassword("secret"), Status: domain.UserStatusActive}
+	suite.Require().NoError(suite.repo.CreateUser(suite.ctx, user))
+
+	lockout := domain.LoginLockout{MaxFailedAttempts: 2, LockedUntil: 9000}
+	updated, err := suite.repo.RecordLoginFailure(suite.ctx, user.ID, domain.LoginAttempt{Time: 1000, IP: "10.0.0.1", Reason: "$invalid"}, lockout)
+	suite.Require().NoError(err)
+	suite.Equal(1, updated.FailedLoginCount)
+	suite.Zero(updated.LockedUntil)
+	updated, err = suite.repo.RecordLoginFailure(suite.ctx, user.ID, domain.LoginAttempt{Time: 2000, IP: "10.0.0.1"}, lockout)
+	suite.Require().NoError(err)
+	suite.Zero(updated.FailedLoginCount, "the count restarts once the user is locked")
+	suite.Equal(int64(9000), updated.LockedUntil)
+	suite.Require().Len(updated.LoginHistory, 2)
+	suite.Equal("$invalid", updated.LoginHistory[0].Reason)
+
+	for i := 0; i < domain.MaxLoginHistory; i++ {
+		suite.Require().NoError(suite.repo.RecordLoginSuccess(suite.ctx, user.ID, domain.LoginAttempt{Time: int64(3000 + i), IP: "10.0.0.2", Success: true}))
+	}
+	opts := &domain.QueryUserOptions{IDs: []bson.ObjectID{user.ID}}
+	suite.Require().NoError(suite.repo.QueryUsers(suite.ctx, opts))
+	suite.Require().Len(opts.Result, 1)
+	suite.Zero(opts.Result[0].LockedUntil)
+	suite.Equal("10.0.0.2", opts.Result[0].LastLoginIP)
+	suite.Len(opts.Result[0].LoginHistory, domain.MaxLoginHistory)
+	suite.True(opts.Result[0].LoginHistory[0].Success)
+	suite.ErrorIs(suite.repo.RecordLoginSuccess(suite.ctx, bson.NewObjectID(), domain.LoginAttempt{}), domain.ErrNotFound)
+}
+
+func (suite *RepositoryTestSuite) TestUseTwoFactorCodes() {
+	user := &domain.User{
+		UserName:  "2fa-user",
+		Password:  domain.EncryptedPassword("secret"),
+		Status:    domain.UserStatusActive,
+		TwoFactor: &domain.TwoFactor{Enabled: true, Secret: "encrypted", RecoveryCodeHashes: []string{"a", "b"}, LastUsedStep: 10},
+	}
+	suite.Require().NoError(suite.repo.CreateUser(suite.ctx, user))
+
+	used, err := suite.repo.UseTwoFactorStep(suite.ctx, user.ID, 10)
+	suite.Require().NoError(err)
+	suite.False(used, "the step was already used")
+	used, err = suite.repo.UseTwoFactorStep(suite.ctx, user.ID, 11)
+	suite.Require().NoError(err)
+	suite.True(used)
+
+	used, err = suite.repo.UseRecoveryCode(suite.ctx, user.ID, "a")
+	suite.Require().NoError(err)
+	suite.True(used)
+	used, err = suite.repo.UseRecoveryCode(suite.ctx, user.ID, "a")
+	suite.Require().NoError(err)
+	suite.False(used)
+
+	opts := &domain.QueryUserOptions{IDs: []bson.ObjectID{user.ID}}
+	suite.Require().NoError(suite.repo.QueryUsers(suite.ctx, opts))
+	suite.Require().Len(opts.Result, 1)
+	suite.Equal(int64(11), opts.Result[0].TwoFactor.LastUsedStep)
+	suite.Equal([]string{"b"}, opts.Result[0].TwoFactor.RecoveryCodeHashes)
+}
+
+func (suite *RepositoryTestSuite) TestCacheVersion() {
+	version, err := suite.repo.GetCacheVersion(suite.ctx, "test-cache")
+	suite.Require().NoError(err)
+	suite.Zero(version)
+
+	for want := int64(1); want <= 2; want++ {
+		version, err = suite.repo.IncrementCacheVersion(suite.ctx, "test-cache")
+		suite.Require().NoError(err)
+		suite.Equal(want, version)
+	}
+	version, err = suite.repo.GetCacheVersion(suite.ctx, "test-cache")
+	suite.Require().NoError(err)
+	suite.Equal(int64(2), version)
+}
+
+func (suite *RepositoryTestSuite) TestTeams() {
+	member1, member2 := bson.NewObjectID(), bson.NewObjectID()
+	team := &domain.Team{Name: "platform", MemberIDs: []bson.ObjectID{member1}}
+	suite.Require().NoError(suite.repo.CreateTeam(suite.ctx, team))
+	suite.Require().False(team.ID.IsZero())
+
+	team.MemberIDs = []bson.ObjectID{member1, member2}
+	suite.Require().NoError(suite.repo.UpdateTeam(suite.ctx, team))
+	opt := &domain.QueryTeamOptions{MemberIDs: []bson.ObjectID{member2}}
+	suite.Require().NoError(suite.repo.QueryTeams(suite.ctx, opt))
+	suite.Require().Len(opt.Result, 1)
+	suite.Equal("platform", opt.Result[0].Name)
+
+	suite.Require().NoError(suite.repo.DeleteTeam(suite.ctx, team.ID))
+	suite.ErrorIs(suite.repo.DeleteTeam(suite.ctx, team.ID), domain.ErrNotFound)
+	opt = &domain.QueryTeamOptions{Names: []string{"platform"}}
+	suite.Require().NoError(suite.repo.QueryTeams(suite.ctx, opt))
+	suite.Empty(opt.Result)
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// UseTwoFactorStep records step as the last used TOTP step of the user unless a code of the same
+// or a later step was already accepted, the filter makes concurrent replays fail
+func (r *repo) UseTwoFactorStep(ctx context.Context, id bson.ObjectID, step int64) (bool, error) {
+	res, err := r.db.Collection(userCollection).UpdateOne(ctx, bson.M{
+		"_id":               id,
+		"twoFactor.enabled": true,
+		"$or": bson.A{
+			bson.M{"twoFactor.lastUsedStep": bson.M{"$lt": step}},
+			bson.M{"twoFactor.lastUsedStep": bson.M{"$exists": false}},
+		},
+	}, bson.M{"$set": bson.M{"twoFactor.lastUsedStep": step}})
+	if err != nil {
+		return false, fmt.Errorf("use two-factor step, err: %w", err)
+	}
+	return res.ModifiedCount > 0, nil
+}
+
+// UseRecoveryCode removes the recovery code with codeHash from the user, it reports false when
+// the code doesn't exist or was already used
+func (r *repo) UseRecoveryCode(ctx context.Context, id bson.ObjectID, codeHash string) (bool, error) {
+	res, err := r.db.Collection(userCollection).UpdateOne(ctx, bson.M{
+		"_id":                          id,
+		"twoFactor.enabled":            true,
+		"twoFactor.recoveryCodeHashes": codeHash,
+	}, bson.M{"$pull": bson.M{"twoFactor.recoveryCodeHashes": codeHash}})
+	if err != nil {
+		return false, fmt.Errorf("use recovery code, err: %w", err)
+	}
+	return res.ModifiedCount > 0, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func (r *repo) CreateWebhookSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	if sub == nil {
+		return errors.New("nil webhook subscription")
+	}
+
+	now := time.Now().UnixMilli()
+	if sub.ID.IsZero() {
+		sub.ID = bson.NewObjectID()
+	}
+	if sub.CreatedTime == 0 {
+		sub.CreatedTime = now
+	}
+	sub.UpdatedTime = now
+
+	res, err := r.db.Collection(webhookSubscriptionCollection).InsertOne(ctx, sub)
+	if err != nil {
+		return fmt.Errorf("create webhook subscription, err: %w", err)
+	}
+	if oid, ok := res.InsertedID.(bson.ObjectID); ok {
+		sub.ID = oid
+	}
+	return nil
+}
+
+func (r *repo) UpdateWebhookSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	if sub == nil {
+		return errors.New("nil webhook subscription")
+	}
+	if sub.ID.IsZero() {
+		return errors.New("webhook subscription id is required")
+	}
+
+	sub.UpdatedTime = time.Now().UnixMilli()
+	res, err := r.db.Collection(webhookSubscriptionCollection).ReplaceOne(ctx, bson.M{"_id": sub.ID}, sub)
+	if err != nil {
+		return fmt.Errorf("update webhook subscription, err: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *repo) DeleteWebhookSubscription(ctx context.Context, subID bson.ObjectID) error {
+	res, err := r.db.Collection(webhookSubscriptionCollection).DeleteOne(ctx, bson.M{"_id": subID})
+	if err != nil {
+		return fmt.Errorf("delete webhook subscription, err: %w", err)
+	}
+	if res.DeletedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *repo) QueryWebhookSubscriptions(ctx context.Context, opt *domain.QueryWebhookSubscriptionOptions) error {
+	if opt == nil {
+		return errors.New("nil query options")
+	}
+
+	filter := bson.M{}
+	if len(opt.IDs) > 0 {
+		filter["_id"] = bson.M{"$in": opt.IDs}
+	}
+
+	cursor, err := r.db.Collection(webhookSubscriptionCollection).Find(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("find webhook subscriptions, err: %w", err)
+	}
+
+	var result []*domain.WebhookSubscription
+	if err := cursor.All(ctx, &result); err != nil {
+		return fmt.Errorf("decode webhook subscriptions, err: %w", err)
+	}
+	opt.Result = result
+	return nil
+}
@@ -0,0 +1,336 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// AlertRuleSpec holds the configurable fields of an alert rule
+type AlertRuleSpec struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Metric is one of nr_queued, nr_scheduled, nr_running, nr_online_cpus, nr_user_dispatches,
+	// nr_kernel_dispatches, nr_cancel_dispatches, nr_bounce_dispatches, nr_failed_dispatches,
+	// nr_sched_congested or usersched_idle_seconds
+	Metric string `json:"metric"`
+	// Rate evaluates the per-second rate of the metric instead of its value
+	Rate       bool    `json:"rate,omitempty"`
+	Comparator string  `json:"comparator"` // >, >=, < or <=
+	Threshold  float64 `json:"threshold"`
+	// ForSec is how long the threshold must be breached before the alert fires
+	ForSec      int64    `json:"forSec,omitempty"`
+	NodeIDs     []string `json:"nodeIDs,omitempty"` // empty means every node
+	WebhookURLs []string `json:"webhookURLs,omitempty"`
+	Disabled    bool     `json:"disabled,omitempty"`
+}
+
+func (spec *AlertRuleSpec) toDomain() *domain.AlertRule {
+	return &domain.AlertRule{
+		Name:        spec.Name,
+		Description: spec.Description,
+		Metric:      domain.AlertMetric(spec.Metric),
+		Rate:        spec.Rate,
+		Comparator:  domain.AlertComparator(spec.Comparator),
+		Threshold:   spec.Threshold,
+		ForSec:      spec.ForSec,
+		NodeIDs:     spec.NodeIDs,
+		WebhookURLs: spec.WebhookURLs,
+		Disabled:    spec.Disabled,
+	}
+}
+
+type CreateAlertRuleRequest struct {
+	AlertRuleSpec
+}
+
+type UpdateAlertRuleRequest struct {
+	ID string `json:"id"`
+	AlertRuleSpec
+}
+
+type DeleteAlertRuleRequest struct {
+	ID string `json:"id"`
+}
+
+// AlertRule represents an alert rule (for API response)
+type AlertRule struct {
+	ID string `json:"id"`
+	AlertRuleSpec
+	CreatedTime int64 `json:"createdTime"`
+	UpdatedTime int64 `json:"updatedTime"`
+}
+
+type ListAlertRulesResponse struct {
+	Rules []AlertRule `json:"rules"`
+}
+
+// Alert represents a firing or resolved alert (for API response)
+type Alert struct {
+	ID         string  `json:"id"`
+	RuleID     string  `json:"ruleId"`
+	RuleName   string  `json:"ruleName"`
+	NodeID     string  `json:"nodeId"`
+	State      string  `json:"state"`
+	Value      float64 `json:"value"`
+	StartsAt   int64   `json:"startsAt"`
+	ResolvedAt int64   `json:"resolvedAt,omitempty"`
+}
+
+type ListAlertsResponse struct {
+	Alerts []Alert `json:"alerts"`
+}
+
+// CreateAlertRule godoc
+// @Summary Create alert rule
+// @Description Create an alert rule evaluated on the metrics of every decision maker, e.g. nr_sched_congested rate > 10 for 300s, or usersched_idle_seconds > 30.
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateAlertRuleRequest true "Alert rule payload"
+// @Success 200 {object} SuccessResponse[EmptyResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/alert-rules [post]
+func (h *Handler) CreateAlertRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req CreateAlertRuleRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	rule := req.toDomain()
+	err = h.Svc.CreateAlertRule(ctx, &claims, rule)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+	h.auditResource(ctx, rule.ID.Hex())
+
+	response := NewSuccessResponse[EmptyResponse](&EmptyResponse{})
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+// UpdateAlertRule godoc
+// @Summary Update alert rule
+// @Description Replace the configuration of an alert rule.
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdateAlertRuleRequest true "Alert rule payload"
+// @Success 200 {object} SuccessResponse[EmptyResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/alert-rules [put]
+func (h *Handler) UpdateAlertRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req UpdateAlertRuleRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if req.ID == "" {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Alert rule ID is required", nil)
+		return
+	}
+
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	h.auditResource(ctx, req.ID)
+	err = h.Svc.UpdateAlertRule(ctx, &claims, req.ID, req.toDomain())
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	response := NewSuccessResponse[EmptyResponse](&EmptyResponse{})
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+// DeleteAlertRule godoc
+// @Summary Delete alert rule
+// @Description Delete an alert rule, its firing alerts are resolved by the next evaluation.
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body DeleteAlertRuleRequest true "Alert rule payload"
+// @Success 200 {object} SuccessResponse[EmptyResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/alert-rules [delete]
+func (h *Handler) DeleteAlertRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req DeleteAlertRuleRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if req.ID == "" {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Alert rule ID is required", nil)
+		return
+	}
+
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	h.auditResource(ctx, req.ID)
+	err = h.Svc.DeleteAlertRule(ctx, &claims, req.ID)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	response := NewSuccessResponse[EmptyResponse](&EmptyResponse{})
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+// ListAlertRules godoc
+// @Summary List alert rules
+// @Description Retrieve all alert rules.
+// @Tags Alerts
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} SuccessResponse[ListAlertRulesResponse]
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/alert-rules [get]
+func (h *Handler) ListAlertRules(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	opt := &domain.QueryAlertRuleOptions{}
+	if err := h.Svc.ListAlertRules(ctx, opt); err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	resp := ListAlertRulesResponse{
+		Rules: make([]AlertRule, len(opt.Result)),
+	}
+	for i, rule := range opt.Result {
+		resp.Rules[i] = AlertRule{
+			ID: rule.ID.Hex(),
+			AlertRuleSpec: AlertRuleSpec{
+				Name:        rule.Name,
+				Description: rule.Description,
+				Metric:      string(rule.Metric),
+				Rate:        rule.Rate,
+				Comparator:  string(rule.Comparator),
+				Threshold:   rule.Threshold,
+				ForSec:      rule.ForSec,
+				NodeIDs:     rule.NodeIDs,
+				WebhookURLs: rule.WebhookURLs,
+				Disabled:    rule.Disabled,
+			},
+			CreatedTime: rule.CreatedTime,
+			UpdatedTime: rule.UpdatedTime,
+		}
+	}
+	h.JSONResponse(ctx, w, http.StatusOK, NewSuccessResponse(&resp))
+}
+
+const defaultAlertListLimit = 500
+
+// ListAlerts godoc
+// @Summary List alerts
+// @Description Retrieve firing and resolved alerts, most recent first.
+// @Tags Alerts
+// @Produce json
+// @Security BearerAuth
+// @Param state query string false "Only alerts in this state: firing or resolved"
+// @Param ruleId query string false "Only alerts of this rule"
+// @Param nodeId query string false "Only alerts of this node"
+// @Param limit query int false "Maximum number of alerts (default 500)"
+// @Success 200 {object} SuccessResponse[ListAlertsResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/alerts [get]
+func (h *Handler) ListAlerts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	opt := &domain.QueryAlertOptions{Limit: defaultAlertListLimit}
+	if state := query.Get("state"); state != "" {
+		switch domain.AlertState(strings.ToLower(state)) {
+		case domain.AlertStateFiring:
+			opt.States = []domain.AlertState{domain.AlertStateFiring}
+		case domain.AlertStateResolved:
+			opt.States = []domain.AlertState{domain.AlertStateResolved}
+		default:
+			h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid state, expected firing or resolved", nil)
+			return
+		}
+	}
+	if ruleID := query.Get("ruleId"); ruleID != "" {
+		ruleObjID, err := bson.ObjectIDFromHex(ruleID)
+		if err != nil {
+			h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid rule ID", err)
+			return
+		}
+		opt.RuleIDs = []bson.ObjectID{ruleObjID}
+	}
+	if nodeID := query.Get("nodeId"); nodeID != "" {
+		opt.NodeIDs = []string{nodeID}
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || limit <= 0 {
+			h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid limit", err)
+			return
+		}
+		opt.Limit = limit
+	}
+
+	if err := h.Svc.ListAlerts(ctx, opt); err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	resp := ListAlertsResponse{
+		Alerts: make([]Alert, len(opt.Result)),
+	}
+	for i, alert := range opt.Result {
+		resp.Alerts[i] = Alert{
+			ID:         alert.ID.Hex(),
+			RuleID:     alert.RuleID.Hex(),
+			RuleName:   alert.RuleName,
+			NodeID:     alert.NodeID,
+			State:      string(alert.State),
+			Value:      alert.Value,
+			StartsAt:   alert.StartsAt,
+			ResolvedAt: alert.ResolvedAt,
+		}
+	}
+	h.JSONResponse(ctx, w, http.StatusOK, NewSuccessResponse(&resp))
+}
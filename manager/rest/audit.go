@@ -0,0 +1,294 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/pkg/logger"
+	"github.com/Gthulhu/api/pkg/util"
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// auditEntry collects what the auth middleware and the handler learn about a mutating request
+type auditEntry struct {
+	userID     string
+	action     string
+	resourceID string
+	changes    []domain.AuditChange
+}
+
+type auditEntryKey struct{}
+
+func auditEntryFromContext(ctx context.Context) *auditEntry {
+	entry, _ := ctx.Value(auditEntryKey{}).(*auditEntry)
+	return entry
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// AuditMiddleware writes an audit log for every mutating request once it was served. The action
+// is the permission key checked by the auth middleware, or the method and route when there is none.
+func (h *Handler) AuditMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !isMutatingMethod(c.Request().Method) {
+				return next(c)
+			}
+			entry := &auditEntry{}
+			r := c.Request()
+			c.SetRequest(r.WithContext(context.WithValue(r.Context(), auditEntryKey{}, entry)))
+
+			err := next(c)
+
+			ctx := r.Context()
+			log := &domain.AuditLog{
+				Action:     entry.action,
+				RequestID:  RequestIDFromContext(ctx),
+				Timestamp:  time.Now().UnixMilli(),
+				IP:         c.RealIP(),
+				Method:     r.Method,
+				Path:       r.URL.Path,
+				ResourceID: entry.resourceID,
+				Status:     c.Response().Status,
+				Changes:    entry.changes,
+			}
+			if log.Action == "" {
+				log.Action = r.Method + " " + c.Path()
+			}
+			if httpErr, ok := err.(*echo.HTTPError); ok {
+				log.Status = httpErr.Code
+			}
+			if entry.userID != "" {
+				if userID, parseErr := bson.ObjectIDFromHex(entry.userID); parseErr == nil {
+					log.UserID = userID
+				}
+			}
+			if createErr := h.Svc.CreateAuditLog(context.WithoutCancel(ctx), log); createErr != nil {
+				logger.Logger(ctx).Error().Err(createErr).Msgf("failed to write audit log of %s %s", r.Method, r.URL.Path)
+			}
+			return err
+		}
+	}
+}
+
+// auditResource records the ID of the resource a mutating request acts on
+func (h *Handler) auditResource(ctx context.Context, resourceIDs ...string) {
+	if entry := auditEntryFromContext(ctx); entry != nil {
+		entry.resourceID = strings.Join(resourceIDs, ",")
+	}
+}
+
+// auditChanges records the fields that differ between the before and after representations of
+// the resource, nil stands for a resource that did not exist yet or no longer exists
+func (h *Handler) auditChanges(ctx context.Context, before, after any) {
+	entry := auditEntryFromContext(ctx)
+	if entry == nil {
+		return
+	}
+	changes, err := diffFields(before, after)
+	if err != nil {
+		logger.Logger(ctx).Warn().Err(err).Msg("failed to compute audit diff")
+		return
+	}
+	entry.changes = changes
+}
+
+// diffFields compares the top-level fields of the JSON encoding of before and after
+func diffFields(before, after any) ([]domain.AuditChange, error) {
+	beforeFields, err := jsonFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := jsonFields(after)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(beforeFields)+len(afterFields))
+	for name := range beforeFields {
+		names = append(names, name)
+	}
+	for name := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []domain.AuditChange
+	for _, name := range names {
+		beforeValue, afterValue := beforeFields[name], afterFields[name]
+		if reflect.DeepEqual(beforeValue, afterValue) {
+			continue
+		}
+		changes = append(changes, domain.AuditChange{
+			Field:  name,
+			Before: rawString(beforeValue),
+			After:  rawString(afterValue),
+		})
+	}
+	return changes, nil
+}
+
+func jsonFields(v any) (map[string]any, error) {
+	if v == nil || reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil() {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func rawString(v any) string {
+	if v == nil {
+		return ""
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// AuditChange is a changed field of an audited resource, values are JSON encoded
+type AuditChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// AuditLog represents an audit log (for API response)
+type AuditLog struct {
+	ID         string        `json:"id"`
+	UserID     string        `json:"userId,omitempty"`
+	Action     string        `json:"action"`
+	RequestID  string        `json:"requestId"`
+	Timestamp  int64         `json:"timestamp"`
+	IP         string        `json:"ip"`
+	Method     string        `json:"method"`
+	Path       string        `json:"path"`
+	ResourceID string        `json:"resourceId,omitempty"`
+	Status     int           `json:"status"`
+	Changes    []AuditChange `json:"changes,omitempty"`
+}
+
+type ListAuditLogsResponse struct {
+	AuditLogs []AuditLog `json:"auditLogs"`
+}
+
+const defaultAuditLogListLimit = 500
+
+// ListAuditLogs godoc
+// @Summary List audit logs
+// @Description Retrieve the audit logs of mutating API calls, most recent first.
+// @Tags Audit
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Only logs at or after this time (RFC3339 or unix seconds)"
+// @Param to query string false "Only logs at or before this time (RFC3339 or unix seconds)"
+// @Param userId query string false "Only logs of this user"
+// @Param action query string false "Only logs of this action, e.g. schedule_strategy.update"
+// @Param limit query int false "Maximum number of logs (default 500)"
+// @Success 200 {object} SuccessResponse[ListAuditLogsResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/audit-logs [get]
+func (h *Handler) ListAuditLogs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	opt := &domain.QueryAuditLogOptions{Limit: defaultAuditLogListLimit}
+	from, err := util.ParseTimeParam(query.Get("from"))
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid from", err)
+		return
+	}
+	to, err := util.ParseTimeParam(query.Get("to"))
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid to", err)
+		return
+	}
+	if !from.IsZero() {
+		opt.TimestampGTE = from.UnixMilli()
+	}
+	if !to.IsZero() {
+		opt.TimestampLTE = to.UnixMilli()
+	}
+	if userID := query.Get("userId"); userID != "" {
+		userObjID, err := bson.ObjectIDFromHex(userID)
+		if err != nil {
+			h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid user ID", err)
+			return
+		}
+		opt.UserIDs = []bson.ObjectID{userObjID}
+	}
+	if action := query.Get("action"); action != "" {
+		opt.Actions = []string{action}
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || limit <= 0 {
+			h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid limit", err)
+			return
+		}
+		opt.Limit = limit
+	}
+
+	if err := h.Svc.ListAuditLogs(ctx, opt); err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	resp := ListAuditLogsResponse{
+		AuditLogs: make([]AuditLog, len(opt.Result)),
+	}
+	for i, log := range opt.Result {
+		item := AuditLog{
+			ID:         log.ID.Hex(),
+			Action:     log.Action,
+			RequestID:  log.RequestID,
+			Timestamp:  log.Timestamp,
+			IP:         log.IP,
+			Method:     log.Method,
+			Path:       log.Path,
+			ResourceID: log.ResourceID,
+			Status:     log.Status,
+		}
+		if !log.UserID.IsZero() {
+			item.UserID = log.UserID.Hex()
+		}
+		for _, change := range log.Changes {
+			item.Changes = append(item.Changes, AuditChange{
+				Field:  change.Field,
+				Before: rawMessage(change.Before),
+				After:  rawMessage(change.After),
+			})
+		}
+		resp.AuditLogs[i] = item
+	}
+	h.JSONResponse(ctx, w, http.StatusOK, NewSuccessResponse(&resp))
+}
+
+func rawMessage(value string) json.RawMessage {
+	if value == "" {
+		return nil
+	}
+	return json.RawMessage(value)
+}
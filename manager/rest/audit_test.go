@@ -0,0 +1,159 @@
+package rest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/manager/errs"
+	"github.com/Gthulhu/api/manager/rest"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestAuditMiddlewareRecordsStrategyUpdate(t *testing.T) {
+	svc := domain.NewMockService(t)
+	h, err := rest.NewHandler(rest.Params{Svc: svc})
+	require.NoError(t, err)
+	engine := echo.New()
+	h.SetupRoutes(engine)
+
+	userID := bson.NewObjectID()
+	strategyID := bson.NewObjectID()
+	svc.EXPECT().VerifyJWTToken(mock.Anything, "token", domain.ScheduleStrategyUpdate).
+		Return(domain.Claims{UID: userID.Hex()}, domain.RolePolicy{}, nil).Once()
+	priority := 1
+	svc.EXPECT().ListScheduleStrategies(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt *domain.QueryStrategyOptions) {
+			opt.Result = []*domain.ScheduleStrategy{{
+				BaseEntity:   domain.BaseEntity{ID: strategyID},
+				K8sNamespace: []string{"default"},
+				Priority:     priority,
+			}}
+			priority = 5
+		}).
+		Return(nil).Twice()
+	svc.EXPECT().UpdateScheduleStrategy(mock.Anything, mock.Anything, strategyID.Hex(), mock.Anything).Return(nil).Once()
+
+	var audit *domain.AuditLog
+	svc.EXPECT().CreateAuditLog(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, log *domain.AuditLog) { audit = log }).
+		Return(nil).Once()
+
+	body, _ := json.Marshal(map[string]any{"strategyId": strategyID.Hex(), "k8sNamespace": []string{"default"}, "priority": 5})
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/strategies", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-ID", "req-1")
+	req.Header.Set("X-Real-IP", "10.1.2.3")
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, audit)
+	assert.Equal(t, userID, audit.UserID)
+	assert.Equal(t, string(domain.ScheduleStrategyUpdate), audit.Action)
+	assert.Equal(t, strategyID.Hex(), audit.ResourceID)
+	assert.Equal(t, "req-1", audit.RequestID)
+	assert.Equal(t, "10.1.2.3", audit.IP)
+	assert.Equal(t, http.StatusOK, audit.Status)
+	assert.Equal(t, []domain.AuditChange{{Field: "Priority", Before: "1", After: "5"}}, audit.Changes)
+}
+
+func TestAuditMiddlewareRecordsRejectedRequests(t *testing.T) {
+	svc := domain.NewMockService(t)
+	h, err := rest.NewHandler(rest.Params{Svc: svc})
+	require.NoError(t, err)
+	engine := echo.New()
+	h.SetupRoutes(engine)
+
+	var audit *domain.AuditLog
+	svc.EXPECT().CreateAuditLog(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, log *domain.AuditLog) { audit = log }).
+		Return(nil).Once()
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/roles", bytes.NewReader([]byte(`{"id":"x"}`)))
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	require.NotNil(t, audit)
+	assert.True(t, audit.UserID.IsZero())
+	assert.Equal(t, "DELETE /api/v1/roles", audit.Action)
+	assert.Equal(t, http.StatusUnauthorized, audit.Status)
+	assert.NotEmpty(t, audit.RequestID)
+	assert.Equal(t, audit.RequestID, rec.Header().Get("X-Request-ID"))
+}
+
+func TestAuditMiddlewareRecordsFailedLogin(t *testing.T) {
+	svc := domain.NewMockService(t)
+	h, err := rest.NewHandler(rest.Params{Svc: svc})
+	require.NoError(t, err)
+	engine := echo.New()
+	h.SetupRoutes(engine)
+
+	svc.EXPECT().Login(mock.Anything, "alice", "wrong", mock.Anything).
+		Return(nil, errs.NewHTTPStatusError(http.StatusUnauthorized, "invalid password", errors.New("password not match"))).Once()
+	var audit *domain.AuditLog
+	svc.EXPECT().CreateAuditLog(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, log *domain.AuditLog) { audit = log }).
+		Return(nil).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewReader([]byte(`{"username":"alice","password":"wrong"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	require.NotNil(t, audit)
+	assert.Equal(t, http.StatusUnauthorized, audit.Status)
+}
+
+func TestListAuditLogsFilters(t *testing.T) {
+	svc := domain.NewMockService(t)
+	h, err := rest.NewHandler(rest.Params{Svc: svc})
+	require.NoError(t, err)
+	engine := echo.New()
+	h.SetupRoutes(engine)
+
+	userID := bson.NewObjectID()
+	svc.EXPECT().VerifyJWTToken(mock.Anything, "token", domain.AuditLogRead).
+		Return(domain.Claims{UID: userID.Hex()}, domain.RolePolicy{}, nil).Once()
+	svc.EXPECT().ListAuditLogs(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt *domain.QueryAuditLogOptions) {
+			assert.Equal(t, int64(1700000000000), opt.TimestampGTE)
+			assert.Equal(t, []bson.ObjectID{userID}, opt.UserIDs)
+			assert.Equal(t, []string{"role.update"}, opt.Actions)
+			assert.Equal(t, int64(10), opt.Limit)
+			opt.Result = []*domain.AuditLog{{
+				ID:      bson.NewObjectID(),
+				UserID:  userID,
+				Action:  "role.update",
+				Status:  http.StatusOK,
+				Changes: []domain.AuditChange{{Field: "name", Before: `"old"`, After: `"new"`}},
+			}}
+		}).
+		Return(nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/audit-logs?from=1700000000&userId="+userID.Hex()+"&action=role.update&limit=10", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp rest.SuccessResponse[rest.ListAuditLogsResponse]
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Data.AuditLogs, 1)
+	change := resp.Data.AuditLogs[0].Changes[0]
+	assert.Equal(t, "name", change.Field)
+	assert.JSONEq(t, `"old"`, string(change.Before))
+	assert.JSONEq(t, `"new"`, string(change.After))
+}
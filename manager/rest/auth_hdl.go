@@ -42,6 +42,7 @@ func (h *Handler) CreateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.auditResource(ctx, req.UserName)
 	err = h.Svc.CreateNewUser(ctx, &claims, req.UserName, req.Password)
 	if err != nil {
 		h.HandleError(ctx, w, err)
@@ -59,11 +60,46 @@ type LoginRequest struct {
 
 type LoginResponse struct {
 	Token string `json:"token"`
+	// ExpiresAt is the unix time in milliseconds the access token expires at
+	ExpiresAt int64 `json:"expiresAt"`
+	// RefreshToken is exchanged for a new token pair at POST /api/v1/auth/refresh, once
+	RefreshToken          string `json:"refreshToken"`
+	RefreshTokenExpiresAt int64  `json:"refreshTokenExpiresAt"`
+	// TwoFactorRequired is set instead of the tokens for users with two-factor authentication, the
+	// login completes at POST /api/v1/auth/2fa with TwoFactorToken and a code
+	TwoFactorRequired       bool   `json:"twoFactorRequired,omitempty"`
+	TwoFactorToken          string `json:"twoFactorToken,omitempty"`
+	TwoFactorTokenExpiresAt int64  `json:"twoFactorTokenExpiresAt,omitempty"`
+	// TwoFactorEnrollmentRequired is set when a role of the user requires two-factor authentication,
+	// the token only allows enrolling until then
+	TwoFactorEnrollmentRequired bool `json:"twoFactorEnrollmentRequired,omitempty"`
+}
+
+func newLoginResponse(tokens *domain.TokenPair) *LoginResponse {
+	return &LoginResponse{
+		Token:                 tokens.AccessToken,
+		ExpiresAt:             tokens.AccessTokenExpiresAt.UnixMilli(),
+		RefreshToken:          tokens.RefreshToken,
+		RefreshTokenExpiresAt: tokens.RefreshTokenExpiresAt.UnixMilli(),
+	}
+}
+
+func newLoginResultResponse(result *domain.LoginResult) *LoginResponse {
+	if result.TwoFactorToken != "" {
+		return &LoginResponse{
+			TwoFactorRequired:       true,
+			TwoFactorToken:          result.TwoFactorToken,
+			TwoFactorTokenExpiresAt: result.TwoFactorTokenExpiresAt.UnixMilli(),
+		}
+	}
+	response := newLoginResponse(result.Tokens)
+	response.TwoFactorEnrollmentRequired = result.TwoFactorEnrollmentRequired
+	return response
 }
 
 // Login godoc
 // @Summary User login
-// @Description Authenticate user and return JWT token.
+// @Description Authenticate user and return a JWT access token with a refresh token. Users with two-factor authentication get a two-factor token instead, exchanged for the tokens at /api/v1/auth/2fa.
 // @Tags Auth
 // @Accept json
 // @Produce json
@@ -86,15 +122,190 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := h.Svc.Login(ctx, req.UserName, req.Password)
+	h.auditResource(ctx, req.UserName)
+	result, err := h.Svc.Login(ctx, req.UserName, req.Password, ClientIPFromContext(ctx))
 	if err != nil {
 		h.HandleError(ctx, w, err)
 		return
 	}
-	respData := LoginResponse{
-		Token: token,
+	response := NewSuccessResponse(newLoginResultResponse(result))
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// RefreshToken godoc
+// @Summary Refresh tokens
+// @Description Exchange a refresh token for a new access and refresh token. Every refresh token can only be used once, reusing one revokes all tokens rotated from the same login.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body RefreshTokenRequest true "Refresh payload"
+// @Success 200 {object} SuccessResponse[LoginResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/auth/refresh [post]
+func (h *Handler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req RefreshTokenRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	tokens, err := h.Svc.RefreshToken(ctx, req.RefreshToken)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
 	}
-	response := NewSuccessResponse(&respData)
+	response := NewSuccessResponse(newLoginResponse(tokens))
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refreshToken,omitempty"`
+}
+
+// Logout godoc
+// @Summary Logout
+// @Description Revoke the access token of the request and its refresh token.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body LogoutRequest false "Logout payload"
+// @Success 200 {object} SuccessResponse[EmptyResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/auth/logout [post]
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req LogoutRequest
+	if r.ContentLength != 0 {
+		err := h.JSONBind(r, &req)
+		if err != nil {
+			h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+			return
+		}
+	}
+
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", errors.New("claims not found"))
+		return
+	}
+
+	h.auditResource(ctx, claims.UID)
+	err := h.Svc.Logout(ctx, &claims, req.RefreshToken)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	response := NewSuccessResponse[EmptyResponse](&EmptyResponse{})
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+type RevokeUserSessionsRequest struct {
+	UserID string `json:"userID"`
+}
+
+// RevokeUserSessions godoc
+// @Summary Revoke user sessions
+// @Description Invalidate every access and refresh token of a user.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body RevokeUserSessionsRequest true "User payload"
+// @Success 200 {object} SuccessResponse[EmptyResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/sessions [delete]
+func (h *Handler) RevokeUserSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req RevokeUserSessionsRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", errors.New("claims not found"))
+		return
+	}
+	err = h.VerifyResourcePolicy(ctx, req.UserID)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	h.auditResource(ctx, req.UserID)
+	err = h.Svc.RevokeUserSessions(ctx, &claims, req.UserID)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	response := NewSuccessResponse[EmptyResponse](&EmptyResponse{})
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+type UnlockUserRequest struct {
+	UserID string `json:"userID"`
+}
+
+// UnlockUser godoc
+// @Summary Unlock user
+// @Description Lift the lockout of a user after too many failed logins and reset its failed login count.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UnlockUserRequest true "User payload"
+// @Success 200 {object} SuccessResponse[EmptyResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/unlock [put]
+func (h *Handler) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req UnlockUserRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", errors.New("claims not found"))
+		return
+	}
+	err = h.VerifyResourcePolicy(ctx, req.UserID)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	h.auditResource(ctx, req.UserID)
+	err = h.Svc.UnlockUser(ctx, &claims, req.UserID)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	response := NewSuccessResponse[EmptyResponse](&EmptyResponse{})
 	h.JSONResponse(ctx, w, http.StatusOK, response)
 }
 
@@ -135,6 +346,7 @@ func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.auditResource(ctx, claims.UID)
 	err = h.Svc.ChangePassword(ctx, &claims, req.OldPassword, req.NewPassword)
 	if err != nil {
 		h.HandleError(ctx, w, err)
@@ -184,6 +396,7 @@ func (h *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.auditResource(ctx, req.UserID)
 	err = h.Svc.ResetPassword(ctx, &claims, req.UserID, req.NewPassword)
 	if err != nil {
 		h.HandleError(ctx, w, err)
@@ -232,6 +445,7 @@ func (h *Handler) UpdateUserPermissions(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	h.auditResource(ctx, req.UserID)
 	err = h.Svc.UpdateUserPermissions(ctx, &claims, req.UserID, domain.UpdateUserPermissionsOptions{
 		Roles:  req.Roles,
 		Status: req.Status,
@@ -246,10 +460,14 @@ func (h *Handler) UpdateUserPermissions(w http.ResponseWriter, r *http.Request)
 
 type ListUsersResponse struct {
 	Users []struct {
-		ID       string            `json:"id"`
-		UserName string            `json:"username"`
-		Roles    []string          `json:"roles"`
-		Status   domain.UserStatus `json:"status"`
+		ID               string            `json:"id"`
+		UserName         string            `json:"username"`
+		Roles            []string          `json:"roles"`
+		Status           domain.UserStatus `json:"status"`
+		LastLoginTime    int64             `json:"lastLoginTime,omitempty"`
+		FailedLoginCount int               `json:"failedLoginCount,omitempty"`
+		LockedUntil      int64             `json:"lockedUntil,omitempty"`
+		TwoFactorEnabled bool              `json:"twoFactorEnabled"`
 	} `json:"users"`
 }
 
@@ -274,14 +492,22 @@ func (h *Handler) ListUsers(w http.ResponseWriter, r *http.Request) {
 	respData := ListUsersResponse{}
 	for _, user := range query.Result {
 		userInfo := struct {
-			ID       string            `json:"id"`
-			UserName string            `json:"username"`
-			Roles    []string          `json:"roles"`
-			Status   domain.UserStatus `json:"status"`
+			ID               string            `json:"id"`
+			UserName         string            `json:"username"`
+			Roles            []string          `json:"roles"`
+			Status           domain.UserStatus `json:"status"`
+			LastLoginTime    int64             `json:"lastLoginTime,omitempty"`
+			FailedLoginCount int               `json:"failedLoginCount,omitempty"`
+			LockedUntil      int64             `json:"lockedUntil,omitempty"`
+			TwoFactorEnabled bool              `json:"twoFactorEnabled"`
 		}{
-			ID:       user.ID.Hex(),
-			UserName: user.UserName,
-			Status:   user.Status,
+			ID:               user.ID.Hex(),
+			UserName:         user.UserName,
+			Status:           user.Status,
+			LastLoginTime:    user.LastLoginTime,
+			FailedLoginCount: user.FailedLoginCount,
+			LockedUntil:      user.LockedUntil,
+			TwoFactorEnabled: user.TwoFactorEnabled(),
 		}
 		for _, role := range user.Roles {
 			userInfo.Roles = append(userInfo.Roles, role)
@@ -297,6 +523,17 @@ type GetSelfUserResponse struct {
 	UserName string            `json:"username"`
 	Roles    []string          `json:"roles"`
 	Status   domain.UserStatus `json:"status"`
+	// TwoFactorEnabled is set once an authenticator was enrolled with POST /api/v1/users/self/2fa
+	TwoFactorEnabled bool `json:"twoFactorEnabled"`
+	// LoginHistory lists the recent login attempts, newest first, so users notice attempts they didn't make
+	LoginHistory []LoginAttemptResponse `json:"loginHistory,omitempty"`
+}
+
+type LoginAttemptResponse struct {
+	Time    int64  `json:"time"`
+	IP      string `json:"ip,omitempty"`
+	Success bool   `json:"success"`
+	Reason  string `json:"reason,omitempty"`
 }
 
 // GetSelfUser godoc
@@ -335,13 +572,18 @@ func (h *Handler) GetSelfUser(w http.ResponseWriter, r *http.Request) {
 	}
 	user := query.Result[0]
 	respData := GetSelfUserResponse{
-		ID:       user.ID.Hex(),
-		UserName: user.UserName,
-		Status:   user.Status,
+		ID:               user.ID.Hex(),
+		UserName:         user.UserName,
+		Status:           user.Status,
+		TwoFactorEnabled: user.TwoFactorEnabled(),
 	}
 	for _, role := range user.Roles {
 		respData.Roles = append(respData.Roles, role)
 	}
+	for i := len(user.LoginHistory) - 1; i >= 0; i-- {
+		attempt := user.LoginHistory[i]
+		respData.LoginHistory = append(respData.LoginHistory, LoginAttemptResponse(attempt))
+	}
 	response := NewSuccessResponse(&respData)
 	h.JSONResponse(ctx, w, http.StatusOK, response)
 }
@@ -7,8 +7,10 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/Gthulhu/api/config"
 	"github.com/Gthulhu/api/manager/domain"
 	"github.com/Gthulhu/api/manager/errs"
+	"github.com/Gthulhu/api/pkg/certreload"
 	"github.com/Gthulhu/api/pkg/logger"
 	"go.uber.org/fx"
 )
@@ -31,9 +33,10 @@ type VersionResponse struct {
 
 // HealthResponse describes the health check payload.
 type HealthResponse struct {
-	Status    string `json:"status"`
-	Timestamp string `json:"timestamp"`
-	Service   string `json:"service"`
+	Status    string   `json:"status"`
+	Timestamp string   `json:"timestamp"`
+	Service   string   `json:"service"`
+	Warnings  []string `json:"warnings,omitempty"`
 }
 
 func NewSuccessResponse[T any](data *T) SuccessResponse[T] {
@@ -53,17 +56,24 @@ type SuccessResponse[T any] struct {
 
 type Params struct {
 	fx.In
-	Svc domain.Service
+	Svc        domain.Service
+	MTLSCerts  *certreload.Reloader `optional:"true"`
+	MTLSConfig config.MTLSConfig    `optional:"true"`
 }
 
 func NewHandler(params Params) (*Handler, error) {
 	return &Handler{
-		Svc: params.Svc,
+		Svc:               params.Svc,
+		MTLSCerts:         params.MTLSCerts,
+		MTLSExpiryWarning: time.Duration(params.MTLSConfig.ExpiryWarningSec) * time.Second,
 	}, nil
 }
 
 type Handler struct {
 	Svc domain.Service
+	// MTLSCerts is the client certificate presented to the decision makers, nil without mTLS
+	MTLSCerts         *certreload.Reloader
+	MTLSExpiryWarning time.Duration
 }
 
 func (h *Handler) JSONResponse(ctx context.Context, w http.ResponseWriter, status int, data any) {
@@ -138,6 +148,12 @@ func (h *Handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
 		Service:   "BSS Metrics API Server",
 	}
+	if h.MTLSCerts != nil {
+		if warning := h.MTLSCerts.ExpiryWarning(time.Now(), h.MTLSExpiryWarning); warning != "" {
+			response.Status = "degraded"
+			response.Warnings = append(response.Warnings, warning)
+		}
+	}
 	h.JSONResponse(r.Context(), w, http.StatusOK, response)
 }
 
@@ -0,0 +1,36 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Gthulhu/api/pkg/oidc"
+)
+
+// jwksMaxAge is how long clients may cache the key set, a new signing key has to be published at
+// least this long before it's activated
+const jwksMaxAge = 5 * 60
+
+// JSONWebKeySet is the RFC 7517 key set of the manager signing keys
+type JSONWebKeySet struct {
+	Keys []oidc.JSONWebKey `json:"keys"`
+}
+
+// JSONWebKeySet godoc
+// @Summary JSON Web Key Set
+// @Description Public keys manager-issued JWTs are verified with, tokens name their key in the kid header.
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} JSONWebKeySet
+// @Failure 500 {object} ErrorResponse
+// @Router /.well-known/jwks.json [get]
+func (h *Handler) JSONWebKeySet(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	set, err := h.Svc.JSONWebKeySet(ctx)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", jwksMaxAge))
+	h.JSONResponse(ctx, w, http.StatusOK, &JSONWebKeySet{Keys: set.Keys})
+}
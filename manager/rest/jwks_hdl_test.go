@@ -0,0 +1,42 @@
+package rest_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/manager/rest"
+	"github.com/Gthulhu/api/pkg/oidc"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONWebKeySet(t *testing.T) {
+	svc := domain.NewMockService(t)
+	h, err := rest.NewHandler(rest.Params{Svc: svc})
+	require.NoError(t, err)
+	engine := echo.New()
+	h.SetupRoutes(engine)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	svc.EXPECT().JSONWebKeySet(mock.Anything).
+		Return(&domain.JSONWebKeySet{Keys: []oidc.JSONWebKey{oidc.NewRSAJSONWebKey("2026-10", &key.PublicKey)}}, nil).Once()
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Header().Get("Cache-Control"), "max-age=")
+
+	var set rest.JSONWebKeySet
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &set))
+	require.Len(t, set.Keys, 1)
+	assert.Equal(t, "2026-10", set.Keys[0].KeyID)
+	assert.Equal(t, &key.PublicKey, set.Keys[0].PublicKey())
+}
@@ -0,0 +1,315 @@
+package rest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/pkg/util"
+)
+
+// MetricSample represents a metric set a decision maker received from its scheduler (for API response)
+type MetricSample struct {
+	Timestamp          time.Time `json:"timestamp"`
+	UserSchedLastRunAt uint64    `json:"usersched_last_run_at"`
+	NrQueued           uint64    `json:"nr_queued"`
+	NrScheduled        uint64    `json:"nr_scheduled"`
+	NrRunning          uint64    `json:"nr_running"`
+	NrOnlineCPUs       uint64    `json:"nr_online_cpus"`
+	NrUserDispatches   uint64    `json:"nr_user_dispatches"`
+	NrKernelDispatches uint64    `json:"nr_kernel_dispatches"`
+	NrCancelDispatches uint64    `json:"nr_cancel_dispatches"`
+	NrBounceDispatches uint64    `json:"nr_bounce_dispatches"`
+	NrFailedDispatches uint64    `json:"nr_failed_dispatches"`
+	NrSchedCongested   uint64    `json:"nr_sched_congested"`
+}
+
+// GetNodeMetricHistoryResponse is the response structure for the GET /api/v1/nodes/:nodeID/metrics/history endpoint
+type GetNodeMetricHistoryResponse struct {
+	NodeID  string         `json:"node_id"`
+	Samples []MetricSample `json:"samples"`
+}
+
+// GetNodeMetricHistory godoc
+// @Summary Get metric history of a node
+// @Description Returns the metric history retained by the decision maker of the node, optionally downsampled to the last sample per step.
+// @Tags Nodes
+// @Produce json
+// @Security BearerAuth
+// @Param nodeID path string true "Node ID"
+// @Param since query string false "Only samples received at or after this time (RFC3339 or unix seconds)"
+// @Param step query string false "Downsampling step as a Go duration, e.g. 10s"
+// @Success 200 {object} SuccessResponse[GetNodeMetricHistoryResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/nodes/{nodeID}/metrics/history [get]
+func (h *Handler) GetNodeMetricHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	nodeID := h.GetPathParam(r, "nodeID")
+	if nodeID == "" {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Node ID is required", nil)
+		return
+	}
+
+	since, step, err := util.ParseMetricHistoryQuery(r.URL.Query())
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	result, err := h.Svc.GetNodeMetricHistory(ctx, nodeID, since, step)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	resp := GetNodeMetricHistoryResponse{
+		NodeID:  result.NodeID,
+		Samples: make([]MetricSample, len(result.Samples)),
+	}
+	for i := range result.Samples {
+		resp.Samples[i] = newMetricSample(&result.Samples[i])
+	}
+	h.JSONResponse(ctx, w, http.StatusOK, NewSuccessResponse(&resp))
+}
+
+// MetricTotals are the scheduler metrics summed over the nodes that reported them (for API response)
+type MetricTotals struct {
+	Nodes              int    `json:"nodes"`
+	NrQueued           uint64 `json:"nr_queued"`
+	NrScheduled        uint64 `json:"nr_scheduled"`
+	NrRunning          uint64 `json:"nr_running"`
+	NrOnlineCPUs       uint64 `json:"nr_online_cpus"`
+	NrUserDispatches   uint64 `json:"nr_user_dispatches"`
+	NrKernelDispatches uint64 `json:"nr_kernel_dispatches"`
+	NrCancelDispatches uint64 `json:"nr_cancel_dispatches"`
+	NrBounceDispatches uint64 `json:"nr_bounce_dispatches"`
+	NrFailedDispatches uint64 `json:"nr_failed_dispatches"`
+	NrSchedCongested   uint64 `json:"nr_sched_congested"`
+}
+
+// NodeMetrics represents the current scheduler metrics of a node (for API response)
+type NodeMetrics struct {
+	NodeID                string        `json:"node_id"`
+	NodeStatus            string        `json:"node_status,omitempty"`
+	DecisionMakerHost     string        `json:"decision_maker_host,omitempty"`
+	DecisionMakerOnline   bool          `json:"decision_maker_online"`
+	Error                 string        `json:"error,omitempty"`
+	Metrics               *MetricSample `json:"metrics,omitempty"`
+	FailedDispatchesDelta uint64        `json:"failed_dispatches_delta"`
+	Outliers              []string      `json:"outliers,omitempty"`
+}
+
+// GetClusterMetricsResponse is the response structure for the GET /api/v1/nodes/metrics endpoint
+type GetClusterMetricsResponse struct {
+	CollectedAt time.Time     `json:"collected_at"`
+	Nodes       []NodeMetrics `json:"nodes"`
+	Totals      MetricTotals  `json:"totals"`
+}
+
+// GetClusterMetrics godoc
+// @Summary Get cluster-wide scheduler metrics
+// @Description Collects the current scheduler metrics from every online decision maker and returns per-node values, cluster totals and outliers (userSchedStale, failedDispatchesClimbing, noMetrics, unreachable). Failed dispatches are compared with the previous call.
+// @Tags Nodes
+// @Produce json
+// @Security BearerAuth
+// @Param staleAfter query string false "Flag the user scheduler as stale when it has not run or reported for this Go duration (default 30s)"
+// @Success 200 {object} SuccessResponse[GetClusterMetricsResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/nodes/metrics [get]
+func (h *Handler) GetClusterMetrics(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var staleAfter time.Duration
+	if value := r.URL.Query().Get("staleAfter"); value != "" {
+		var err error
+		staleAfter, err = time.ParseDuration(value)
+		if err != nil || staleAfter <= 0 {
+			h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid staleAfter, expected a positive duration such as 30s", err)
+			return
+		}
+	}
+
+	result, err := h.Svc.GetClusterMetrics(ctx, staleAfter)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	resp := GetClusterMetricsResponse{
+		CollectedAt: result.CollectedAt,
+		Nodes:       make([]NodeMetrics, len(result.Nodes)),
+		Totals:      MetricTotals(result.Totals),
+	}
+	for i, node := range result.Nodes {
+		resp.Nodes[i] = NodeMetrics{
+			NodeID:                node.Node.Name,
+			NodeStatus:            node.Node.Status,
+			DecisionMakerHost:     node.DecisionMakerHost,
+			DecisionMakerOnline:   node.DecisionMakerState == domain.NodeStateOnline,
+			Error:                 node.Error,
+			FailedDispatchesDelta: node.FailedDispatchesDelta,
+			Outliers:              node.Outliers,
+		}
+		if node.Sample != nil {
+			sample := newMetricSample(node.Sample)
+			resp.Nodes[i].Metrics = &sample
+		}
+	}
+	h.JSONResponse(ctx, w, http.StatusOK, NewSuccessResponse(&resp))
+}
+
+func newMetricSample(sample *domain.MetricSample) MetricSample {
+	return MetricSample{
+		Timestamp:          sample.Timestamp,
+		UserSchedLastRunAt: sample.UserSchedLastRunAt,
+		NrQueued:           sample.NrQueued,
+		NrScheduled:        sample.NrScheduled,
+		NrRunning:          sample.NrRunning,
+		NrOnlineCPUs:       sample.NrOnlineCPUs,
+		NrUserDispatches:   sample.NrUserDispatches,
+		NrKernelDispatches: sample.NrKernelDispatches,
+		NrCancelDispatches: sample.NrCancelDispatches,
+		NrBounceDispatches: sample.NrBounceDispatches,
+		NrFailedDispatches: sample.NrFailedDispatches,
+		NrSchedCongested:   sample.NrSchedCongested,
+	}
+}
+
+const (
+	defaultMetricSampleLimit = 10000
+	maxMetricSampleLimit     = 100000
+)
+
+// StoredMetricSample is a persisted metric set with the strategies active on the node when it was collected (for API response)
+type StoredMetricSample struct {
+	MetricSample
+	StrategyIDs []string `json:"strategy_ids"`
+}
+
+// MetricSeries is the persisted metric time series of a node (for API response)
+type MetricSeries struct {
+	NodeID  string               `json:"node_id"`
+	Samples []StoredMetricSample `json:"samples"`
+}
+
+// ListMetricSamplesResponse is the response structure for the persisted metric time series endpoints
+type ListMetricSamplesResponse struct {
+	Series []MetricSeries `json:"series"`
+}
+
+// ListMetricSamples godoc
+// @Summary List persisted metric samples
+// @Description Returns the scheduler metric time series persisted by the manager, grouped by node and ordered by time with the strategies active on the node at every sample. The limit keeps the most recent samples.
+// @Tags Metrics
+// @Produce json
+// @Security BearerAuth
+// @Param nodeID query []string false "Node IDs, repeat for several nodes, all nodes when omitted" collectionFormat(multi)
+// @Param from query string false "Range start (RFC3339 or unix seconds)"
+// @Param to query string false "Range end (RFC3339 or unix seconds)"
+// @Param limit query int false "Maximum number of samples (default 10000, max 100000)"
+// @Success 200 {object} SuccessResponse[ListMetricSamplesResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/metrics/samples [get]
+func (h *Handler) ListMetricSamples(w http.ResponseWriter, r *http.Request) {
+	h.listMetricSamples(w, r, r.URL.Query()["nodeID"])
+}
+
+// ListNodeMetricSamples godoc
+// @Summary List persisted metric samples of a node
+// @Description Returns the scheduler metric time series of the node persisted by the manager, ordered by time with the strategies active on the node at every sample. The limit keeps the most recent samples.
+// @Tags Nodes
+// @Produce json
+// @Security BearerAuth
+// @Param nodeID path string true "Node ID"
+// @Param from query string false "Range start (RFC3339 or unix seconds)"
+// @Param to query string false "Range end (RFC3339 or unix seconds)"
+// @Param limit query int false "Maximum number of samples (default 10000, max 100000)"
+// @Success 200 {object} SuccessResponse[ListMetricSamplesResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/nodes/{nodeID}/metrics/samples [get]
+func (h *Handler) ListNodeMetricSamples(w http.ResponseWriter, r *http.Request) {
+	nodeID := h.GetPathParam(r, "nodeID")
+	if nodeID == "" {
+		h.ErrorResponse(r.Context(), w, http.StatusBadRequest, "Node ID is required", nil)
+		return
+	}
+	h.listMetricSamples(w, r, []string{nodeID})
+}
+
+func (h *Handler) listMetricSamples(w http.ResponseWriter, r *http.Request, nodeIDs []string) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	from, err := util.ParseTimeParam(query.Get("from"))
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid from, expected RFC3339 or unix seconds", err)
+		return
+	}
+	to, err := util.ParseTimeParam(query.Get("to"))
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid to, expected RFC3339 or unix seconds", err)
+		return
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "to must not be before from", nil)
+		return
+	}
+	limit := int64(defaultMetricSampleLimit)
+	if value := query.Get("limit"); value != "" {
+		limit, err = strconv.ParseInt(value, 10, 64)
+		if err != nil || limit <= 0 || limit > maxMetricSampleLimit {
+			h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid limit, expected 1 to 100000", err)
+			return
+		}
+	}
+
+	opt := &domain.QueryMetricSampleOptions{
+		NodeIDs: nodeIDs,
+		From:    from,
+		To:      to,
+		Limit:   limit,
+	}
+	if err := h.Svc.QueryMetricSamples(ctx, opt); err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	resp := ListMetricSamplesResponse{
+		Series: []MetricSeries{},
+	}
+	seriesIndex := map[string]int{}
+	for _, sample := range opt.Result {
+		i, ok := seriesIndex[sample.NodeID]
+		if !ok {
+			i = len(resp.Series)
+			seriesIndex[sample.NodeID] = i
+			resp.Series = append(resp.Series, MetricSeries{NodeID: sample.NodeID, Samples: []StoredMetricSample{}})
+		}
+		strategyIDs := sample.StrategyIDs
+		if strategyIDs == nil {
+			strategyIDs = []string{}
+		}
+		resp.Series[i].Samples = append(resp.Series[i].Samples, StoredMetricSample{
+			MetricSample: newMetricSample(&sample.MetricSample),
+			StrategyIDs:  strategyIDs,
+		})
+	}
+	sort.Slice(resp.Series, func(i, j int) bool {
+		return resp.Series[i].NodeID < resp.Series[j].NodeID
+	})
+	h.JSONResponse(ctx, w, http.StatusOK, NewSuccessResponse(&resp))
+}
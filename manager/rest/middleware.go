@@ -2,8 +2,10 @@ package rest
 
 import (
 	"bytes"
+	"context"
 	"net/http"
 	"runtime/debug"
+	"strings"
 	"time"
 
 	"github.com/Gthulhu/api/manager/domain"
@@ -11,30 +13,53 @@ import (
 	"github.com/rs/xid"
 )
 
+// APIKeyHeader carries a service account API key, as an alternative to "Authorization: Bearer <key>"
+const APIKeyHeader = "X-API-Key"
+
+// GetAuthMiddleware authenticates the request with a JWT or a service account API key and checks
+// the principal holds permissionKey
 func (h *Handler) GetAuthMiddleware(permissionKey domain.PermissionKey) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			ctx := r.Context()
 			tokenString := r.Header.Get("Authorization")
-			if tokenString == "" {
+			apiKey := r.Header.Get(APIKeyHeader)
+			if tokenString == "" && apiKey == "" {
 				h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Missing Authorization header", nil)
 				return
 			}
 
-			// parse bearer token
-			const bearerPrefix = "Bearer "
-			if len(tokenString) <= len(bearerPrefix) || tokenString[:len(bearerPrefix)] != bearerPrefix {
-				h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Invalid Authorization header format", nil)
-				return
+			var claims domain.Claims
+			var rolePolicy domain.RolePolicy
+			var err error
+			if apiKey != "" {
+				claims, rolePolicy, err = h.Svc.VerifyAPIKey(ctx, apiKey, permissionKey)
+			} else {
+				// parse bearer token
+				const bearerPrefix = "Bearer "
+				if len(tokenString) <= len(bearerPrefix) || tokenString[:len(bearerPrefix)] != bearerPrefix {
+					h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Invalid Authorization header format", nil)
+					return
+				}
+				tokenString = tokenString[len(bearerPrefix):]
+				// API keys are accepted as bearer tokens too
+				if strings.HasPrefix(tokenString, domain.APIKeyPrefix) {
+					claims, rolePolicy, err = h.Svc.VerifyAPIKey(ctx, tokenString, permissionKey)
+				} else {
+					claims, rolePolicy, err = h.Svc.VerifyJWTToken(ctx, tokenString, permissionKey)
+				}
 			}
-			tokenString = tokenString[len(bearerPrefix):]
-
-			claims, rolePolicy, err := h.Svc.VerifyJWTToken(ctx, tokenString, permissionKey)
 			if err != nil {
 				h.HandleError(ctx, w, err)
 				return
 			}
 
+			if entry := auditEntryFromContext(ctx); entry != nil {
+				entry.userID = claims.UID
+				if permissionKey != "" {
+					entry.action = string(permissionKey)
+				}
+			}
 			ctx = h.SetClaimsInContext(ctx, claims)
 			ctx = h.SetRolePolicyInContext(ctx, rolePolicy)
 			r = r.WithContext(ctx)
@@ -64,7 +89,9 @@ func LoggerMiddleware(next http.Handler) http.Handler {
 		}()
 
 		ctx = log.WithContext(ctx)
+		ctx = context.WithValue(ctx, requestIDKey{}, reqID)
 		r = r.WithContext(ctx)
+		w.Header().Set("X-Request-ID", reqID)
 		responseWriter := NewResponseWriter(w)
 		next.ServeHTTP(responseWriter, r)
 		cost := time.Since(start)
@@ -89,6 +116,22 @@ func LoggerMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+type requestIDKey struct{}
+
+type clientIPKey struct{}
+
+// ClientIPFromContext returns the client IP of the request, set by echoHandlerWithClientIP
+func ClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
+
+// RequestIDFromContext returns the X-Request-ID of the request, generated by LoggerMiddleware when the client sent none
+func RequestIDFromContext(ctx context.Context) string {
+	reqID, _ := ctx.Value(requestIDKey{}).(string)
+	return reqID
+}
+
 type responseWriter struct {
 	http.ResponseWriter
 	responseBody bytes.Buffer
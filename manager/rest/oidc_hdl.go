@@ -0,0 +1,117 @@
+package rest
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Gthulhu/api/manager/domain"
+)
+
+const (
+	// oidcSessionCookie binds the provider callback to the browser that started the login
+	oidcSessionCookie = "gthulhu_oidc_session"
+	oidcCookiePath    = "/api/v1/auth/oidc"
+)
+
+// OIDCLogin godoc
+// @Summary Single sign-on login
+// @Description Redirect to the OpenID provider to start an authorization code login with PKCE.
+// @Tags Auth
+// @Success 302
+// @Failure 404 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Router /api/v1/auth/oidc/login [get]
+func (h *Handler) OIDCLogin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	authRequest, err := h.Svc.BeginOIDCLogin(ctx)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcSessionCookie,
+		Value:    authRequest.Session,
+		Path:     oidcCookiePath,
+		Expires:  authRequest.ExpiresAt,
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		// Lax, the callback is a top-level navigation from the provider
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, authRequest.AuthURL, http.StatusFound)
+}
+
+// OIDCCallback godoc
+// @Summary Single sign-on callback
+// @Description Complete a single sign-on login. Redirects to the configured page with the token pair in the URL fragment (token, expiresAt, refreshToken, refreshTokenExpiresAt), or returns the tokens when no page is configured.
+// @Tags Auth
+// @Produce json
+// @Param code query string false "Authorization code"
+// @Param state query string true "Login state"
+// @Success 200 {object} SuccessResponse[LoginResponse]
+// @Success 302
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/auth/oidc/callback [get]
+func (h *Handler) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	callback := domain.OIDCCallback{
+		State:            query.Get("state"),
+		Code:             query.Get("code"),
+		Error:            query.Get("error"),
+		ErrorDescription: query.Get("error_description"),
+	}
+	if cookie, err := r.Cookie(oidcSessionCookie); err == nil {
+		callback.Session = cookie.Value
+	}
+	// the session is single use whatever the outcome
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcSessionCookie,
+		Path:     oidcCookiePath,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	login, err := h.Svc.CompleteOIDCLogin(ctx, callback)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+	if login.RedirectURL == "" {
+		response := NewSuccessResponse(newLoginResponse(login.Tokens))
+		h.JSONResponse(ctx, w, http.StatusOK, response)
+		return
+	}
+	redirectURL, err := url.Parse(login.RedirectURL)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusInternalServerError, "Invalid post login redirect URL", err)
+		return
+	}
+	// the fragment isn't sent to servers, so the tokens stay out of access logs and Referer headers
+	redirectURL.Fragment, redirectURL.RawFragment = "", ""
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	http.Redirect(w, r, redirectURL.String()+"#"+tokenFragment(login.Tokens), http.StatusFound)
+}
+
+// tokenFragment encodes tokens with the field names of LoginResponse
+func tokenFragment(tokens *domain.TokenPair) string {
+	fragment := url.Values{}
+	fragment.Set("token", tokens.AccessToken)
+	fragment.Set("expiresAt", strconv.FormatInt(tokens.AccessTokenExpiresAt.UnixMilli(), 10))
+	fragment.Set("refreshToken", tokens.RefreshToken)
+	fragment.Set("refreshTokenExpiresAt", strconv.FormatInt(tokens.RefreshTokenExpiresAt.UnixMilli(), 10))
+	return fragment.Encode()
+}
+
+func isHTTPS(r *http.Request) bool {
+	return r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
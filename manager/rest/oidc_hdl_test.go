@@ -0,0 +1,74 @@
+package rest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/manager/rest"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOIDCLoginRoundTrip(t *testing.T) {
+	svc := domain.NewMockService(t)
+	h, err := rest.NewHandler(rest.Params{Svc: svc})
+	require.NoError(t, err)
+	engine := echo.New()
+	h.SetupRoutes(engine)
+
+	svc.EXPECT().BeginOIDCLogin(mock.Anything).Return(&domain.OIDCAuthRequest{
+		AuthURL:   "https://idp.example/authorize?state=s1",
+		Session:   "session",
+		ExpiresAt: time.Now().Add(time.Minute),
+	}, nil).Once()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/oidc/login", nil)
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusFound, rec.Code, rec.Body.String())
+	assert.Equal(t, "https://idp.example/authorize?state=s1", rec.Header().Get("Location"))
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "session", cookies[0].Value)
+	assert.True(t, cookies[0].HttpOnly)
+
+	svc.EXPECT().CompleteOIDCLogin(mock.Anything, domain.OIDCCallback{Session: "session", State: "s1", Code: "c1"}).
+		Return(&domain.OIDCLogin{Tokens: &domain.TokenPair{AccessToken: "access", RefreshToken: "refresh"}, RedirectURL: "/?tab=home"}, nil).Once()
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/auth/oidc/callback?state=s1&code=c1", nil)
+	req.AddCookie(cookies[0])
+	rec = httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusFound, rec.Code, rec.Body.String())
+	location, err := url.Parse(rec.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, "home", location.Query().Get("tab"))
+	assert.Empty(t, location.Query().Get("token"), "tokens must not be sent to servers in the query")
+	fragment, err := url.ParseQuery(location.Fragment)
+	require.NoError(t, err)
+	assert.Equal(t, "access", fragment.Get("token"))
+	assert.Equal(t, "refresh", fragment.Get("refreshToken"))
+	assert.Equal(t, "no-referrer", rec.Header().Get("Referrer-Policy"))
+	require.Len(t, rec.Result().Cookies(), 1)
+	assert.Equal(t, -1, rec.Result().Cookies()[0].MaxAge)
+}
+
+func TestOIDCCallbackReturnsTokens(t *testing.T) {
+	svc := domain.NewMockService(t)
+	h, err := rest.NewHandler(rest.Params{Svc: svc})
+	require.NoError(t, err)
+	engine := echo.New()
+	h.SetupRoutes(engine)
+
+	svc.EXPECT().CompleteOIDCLogin(mock.Anything, domain.OIDCCallback{State: "s1", Code: "c1"}).
+		Return(&domain.OIDCLogin{Tokens: &domain.TokenPair{AccessToken: "access", RefreshToken: "refresh"}}, nil).Once()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/oidc/callback?state=s1&code=c1", nil)
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"refreshToken":"refresh"`)
+}
@@ -0,0 +1,150 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// StrategyMatchExplanation reports how a strategy's selectors evaluate against the pod (for API response)
+type StrategyMatchExplanation struct {
+	StrategyID        bson.ObjectID `json:"strategy_id"`
+	Matched           bool          `json:"matched"`
+	NamespaceMatched  bool          `json:"namespace_matched"`
+	LabelsMatched     bool          `json:"labels_matched"`
+	CommandMatched    bool          `json:"command_matched"`
+	MatchedContainers []string      `json:"matched_containers,omitempty"`
+	Reasons           []string      `json:"reasons,omitempty"`
+}
+
+// ProcessExplanation reports the decision maker's outcome of an intent for a process (for API response)
+type ProcessExplanation struct {
+	PID         int    `json:"pid"`
+	Command     string `json:"command"`
+	ContainerID string `json:"container_id,omitempty"`
+	Outcome     string `json:"outcome"`
+	TIDs        []int  `json:"tids,omitempty"`
+}
+
+// DecisionMakerIntentExplanation reports how the decision maker resolved an intent (for API response)
+type DecisionMakerIntentExplanation struct {
+	CommandRegex  string               `json:"command_regex,omitempty"`
+	ThreadRegex   string               `json:"thread_regex,omitempty"`
+	Priority      int                  `json:"priority"`
+	ExecutionTime int64                `json:"execution_time"`
+	Error         string               `json:"error,omitempty"`
+	Processes     []ProcessExplanation `json:"processes"`
+}
+
+// DecisionMakerExplanation describes the decision maker responsible for the pod (for API response)
+type DecisionMakerExplanation struct {
+	NodeID      string                           `json:"node_id"`
+	Host        string                           `json:"host,omitempty"`
+	Online      bool                             `json:"online"`
+	Error       string                           `json:"error,omitempty"`
+	PodFound    bool                             `json:"pod_found"`
+	ProcessPIDs []int                            `json:"process_pids,omitempty"`
+	Intents     []DecisionMakerIntentExplanation `json:"intents,omitempty"`
+}
+
+// ExplainPodResponse is the response structure for the GET /api/v1/pods/:namespace/:name/explain endpoint
+type ExplainPodResponse struct {
+	PodID         string                     `json:"pod_id"`
+	PodName       string                     `json:"pod_name"`
+	K8sNamespace  string                     `json:"k8s_namespace"`
+	NodeID        string                     `json:"node_id"`
+	Labels        map[string]string          `json:"labels,omitempty"`
+	Strategies    []StrategyMatchExplanation `json:"strategies"`
+	Intents       []*ScheduleIntent          `json:"intents"`
+	DecisionMaker DecisionMakerExplanation   `json:"decision_maker"`
+}
+
+// ExplainPod godoc
+// @Summary Explain pod scheduling
+// @Description Reports why a pod is or is not scheduled: which strategies' selectors matched or failed and why, which intents exist and their states, and how the pod's decision maker resolved them into PIDs, priorities and time slices.
+// @Tags Pods
+// @Produce json
+// @Security BearerAuth
+// @Param namespace path string true "Kubernetes namespace"
+// @Param name path string true "Pod name"
+// @Success 200 {object} SuccessResponse[ExplainPodResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/pods/{namespace}/{name}/explain [get]
+func (h *Handler) ExplainPod(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	namespace := h.GetPathParam(r, "namespace")
+	name := h.GetPathParam(r, "name")
+	if namespace == "" || name == "" {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Namespace and pod name are required", nil)
+		return
+	}
+
+	result, err := h.Svc.ExplainPod(ctx, namespace, name)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	resp := ExplainPodResponse{
+		PodID:        result.Pod.PodID,
+		PodName:      result.Pod.Name,
+		K8sNamespace: result.Pod.K8SNamespace,
+		NodeID:       result.Pod.NodeID,
+		Labels:       result.Pod.Labels,
+		Strategies:   make([]StrategyMatchExplanation, len(result.Strategies)),
+		Intents:      make([]*ScheduleIntent, len(result.Intents)),
+		DecisionMaker: DecisionMakerExplanation{
+			NodeID: result.DecisionMaker.NodeID,
+			Host:   result.DecisionMaker.Host,
+			Online: result.DecisionMaker.State == domain.NodeStateOnline,
+			Error:  result.DecisionMaker.Error,
+		},
+	}
+	for i, strategy := range result.Strategies {
+		resp.Strategies[i] = StrategyMatchExplanation{
+			StrategyID:        strategy.StrategyID,
+			Matched:           strategy.Matched,
+			NamespaceMatched:  strategy.NamespaceMatched,
+			LabelsMatched:     strategy.LabelsMatched,
+			CommandMatched:    strategy.CommandMatched,
+			MatchedContainers: strategy.MatchedContainers,
+			Reasons:           strategy.Reasons,
+		}
+	}
+	for i, intent := range result.Intents {
+		resp.Intents[i] = h.convertDomainIntentToResponseIntent(intent)
+	}
+	if dmResult := result.DecisionMaker.Result; dmResult != nil {
+		resp.DecisionMaker.PodFound = dmResult.PodFound
+		resp.DecisionMaker.ProcessPIDs = dmResult.ProcessPIDs
+		resp.DecisionMaker.Intents = make([]DecisionMakerIntentExplanation, len(dmResult.Intents))
+		for i, intent := range dmResult.Intents {
+			processes := make([]ProcessExplanation, len(intent.Processes))
+			for j, proc := range intent.Processes {
+				processes[j] = ProcessExplanation{
+					PID:         proc.PID,
+					Command:     proc.Command,
+					ContainerID: proc.ContainerID,
+					Outcome:     proc.Outcome,
+					TIDs:        proc.TIDs,
+				}
+			}
+			resp.DecisionMaker.Intents[i] = DecisionMakerIntentExplanation{
+				CommandRegex:  intent.CommandRegex,
+				ThreadRegex:   intent.ThreadRegex,
+				Priority:      intent.Priority,
+				ExecutionTime: intent.ExecutionTime,
+				Error:         intent.Error,
+				Processes:     processes,
+			}
+		}
+	}
+
+	response := NewSuccessResponse[ExplainPodResponse](&resp)
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
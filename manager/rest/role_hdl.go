@@ -1,10 +1,12 @@
 package rest
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
 	"github.com/Gthulhu/api/manager/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 type RolePolicy struct {
@@ -18,6 +20,8 @@ type CreateRoleRequest struct {
 	Name         string       `json:"name"`
 	Description  string       `json:"description"`
 	RolePolicies []RolePolicy `json:"rolePolicies"`
+	// RequireTwoFactor restricts users with the role to two-factor enrollment until they enable it
+	RequireTwoFactor bool `json:"requireTwoFactor,omitempty"`
 }
 
 // CreateRole godoc
@@ -49,8 +53,9 @@ func (h *Handler) CreateRole(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	role := domain.Role{
-		Name:        req.Name,
-		Description: req.Description,
+		Name:             req.Name,
+		Description:      req.Description,
+		RequireTwoFactor: req.RequireTwoFactor,
 	}
 	for _, rp := range req.RolePolicies {
 		role.Policies = append(role.Policies, domain.RolePolicy{
@@ -66,6 +71,8 @@ func (h *Handler) CreateRole(w http.ResponseWriter, r *http.Request) {
 		h.HandleError(ctx, w, err)
 		return
 	}
+	h.auditResource(ctx, role.ID.Hex())
+	h.auditChanges(ctx, nil, convertDomainRoleToResponseRole(&role))
 
 	response := NewSuccessResponse[string](nil)
 	h.JSONResponse(ctx, w, http.StatusOK, response)
@@ -76,6 +83,8 @@ type UpdateRoleRequest struct {
 	Name        *string       `json:"name,omitempty"`
 	Description *string       `json:"description,omitempty"`
 	RolePolicy  *[]RolePolicy `json:"rolePolicy,omitempty"`
+	// RequireTwoFactor restricts users with the role to two-factor enrollment until they enable it
+	RequireTwoFactor *bool `json:"requireTwoFactor,omitempty"`
 }
 
 // UpdateRole godoc
@@ -114,6 +123,9 @@ func (h *Handler) UpdateRole(w http.ResponseWriter, r *http.Request) {
 	if req.Description != nil {
 		updateOpts.Description = req.Description
 	}
+	if req.RequireTwoFactor != nil {
+		updateOpts.RequireTwoFactor = req.RequireTwoFactor
+	}
 	if req.RolePolicy != nil {
 		var policies []domain.RolePolicy
 		for _, rp := range *req.RolePolicy {
@@ -127,11 +139,14 @@ func (h *Handler) UpdateRole(w http.ResponseWriter, r *http.Request) {
 		updateOpts.Policies = &policies
 	}
 
+	h.auditResource(ctx, req.ID)
+	before := h.auditedRole(ctx, req.ID)
 	err = h.Svc.UpdateRole(ctx, &claims, req.ID, updateOpts)
 	if err != nil {
 		h.HandleError(ctx, w, err)
 		return
 	}
+	h.auditChanges(ctx, before, h.auditedRole(ctx, req.ID))
 
 	response := NewSuccessResponse[string](nil)
 	h.JSONResponse(ctx, w, http.StatusOK, response)
@@ -143,7 +158,7 @@ type DeleteRoleRequest struct {
 
 // DeleteRole godoc
 // @Summary Delete role
-// @Description Delete a role by ID.
+// @Description Delete a role by ID. A role still held by users or service accounts, or the admin role, cannot be deleted.
 // @Tags Roles
 // @Accept json
 // @Produce json
@@ -153,6 +168,8 @@ type DeleteRoleRequest struct {
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/roles [delete]
 func (h *Handler) DeleteRole(w http.ResponseWriter, r *http.Request) {
@@ -170,23 +187,67 @@ func (h *Handler) DeleteRole(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.auditResource(ctx, req.ID)
+	before := h.auditedRole(ctx, req.ID)
 	err = h.Svc.DeleteRole(ctx, &claims, req.ID)
 	if err != nil {
 		h.HandleError(ctx, w, err)
 		return
 	}
+	h.auditChanges(ctx, before, nil)
 
 	response := NewSuccessResponse[string](nil)
 	h.JSONResponse(ctx, w, http.StatusOK, response)
 }
 
+// Role represents a role with its policies (for API response)
+type Role struct {
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	RolePolicy  []RolePolicy `json:"rolePolicy"`
+	// RequireTwoFactor is set when users with the role must enable two-factor authentication
+	RequireTwoFactor bool `json:"requireTwoFactor"`
+}
+
 type ListRolesResponse struct {
-	Roles []struct {
-		ID          string       `json:"id"`
-		Name        string       `json:"name"`
-		Description string       `json:"description"`
-		RolePolicy  []RolePolicy `json:"rolePolicy"`
-	} `json:"roles"`
+	Roles []Role `json:"roles"`
+}
+
+func convertDomainRoleToResponseRole(role *domain.Role) Role {
+	r := Role{
+		ID:               role.ID.Hex(),
+		Name:             role.Name,
+		Description:      role.Description,
+		RequireTwoFactor: role.RequireTwoFactor,
+	}
+	for _, rp := range role.Policies {
+		r.RolePolicy = append(r.RolePolicy, RolePolicy{
+			PermissionKey:   rp.PermissionKey,
+			Self:            rp.Self,
+			K8SNamespace:    rp.K8SNamespace,
+			PolicyNamespace: rp.PolicyNamespace,
+		})
+	}
+	return r
+}
+
+// auditedRole loads the role for the audit diff of a mutating request, nil when the request is
+// not audited or the role is not found
+func (h *Handler) auditedRole(ctx context.Context, roleID string) *Role {
+	if auditEntryFromContext(ctx) == nil {
+		return nil
+	}
+	roleObjID, err := bson.ObjectIDFromHex(roleID)
+	if err != nil {
+		return nil
+	}
+	queryOpts := &domain.QueryRoleOptions{IDs: []bson.ObjectID{roleObjID}}
+	if err := h.Svc.QueryRoles(ctx, queryOpts); err != nil || len(queryOpts.Result) == 0 {
+		return nil
+	}
+	role := convertDomainRoleToResponseRole(queryOpts.Result[0])
+	return &role
 }
 
 // ListRoles godoc
@@ -210,25 +271,7 @@ func (h *Handler) ListRoles(w http.ResponseWriter, r *http.Request) {
 
 	var resp ListRolesResponse
 	for _, role := range queryOpts.Result {
-		r := struct {
-			ID          string       `json:"id"`
-			Name        string       `json:"name"`
-			Description string       `json:"description"`
-			RolePolicy  []RolePolicy `json:"rolePolicy"`
-		}{
-			ID:          role.ID.Hex(),
-			Name:        role.Name,
-			Description: role.Description,
-		}
-		for _, rp := range role.Policies {
-			r.RolePolicy = append(r.RolePolicy, RolePolicy{
-				PermissionKey:   rp.PermissionKey,
-				Self:            rp.Self,
-				K8SNamespace:    rp.K8SNamespace,
-				PolicyNamespace: rp.PolicyNamespace,
-			})
-		}
-		resp.Roles = append(resp.Roles, r)
+		resp.Roles = append(resp.Roles, convertDomainRoleToResponseRole(role))
 	}
 
 	response := NewSuccessResponse[ListRolesResponse](&resp)
@@ -13,23 +13,36 @@ import (
 func (h *Handler) SetupRoutes(engine *echo.Echo) {
 	engine.GET("/health", h.echoHandler(h.HealthCheck))
 	engine.GET("/version", h.echoHandler(h.Version))
+	engine.GET("/.well-known/jwks.json", h.echoHandler(h.JSONWebKeySet))
 	docs.SwaggerInfo.BasePath = "/"
 	engine.GET("/swagger/*", echoSwagger.WrapHandler)
 
 	api := engine.Group("/api", echo.WrapMiddleware(LoggerMiddleware))
 	// v1 routes
 	{
-		apiV1 := api.Group("/v1")
+		apiV1 := api.Group("/v1", h.AuditMiddleware())
 		// auth routes
-		apiV1.POST("/auth/login", h.echoHandler(h.Login))
+		apiV1.POST("/auth/login", h.echoHandlerWithClientIP(h.Login))
+		apiV1.POST("/auth/2fa", h.echoHandlerWithClientIP(h.VerifyTwoFactorLogin))
+		apiV1.POST("/auth/refresh", h.echoHandler(h.RefreshToken))
+		apiV1.GET("/auth/oidc/login", h.echoHandler(h.OIDCLogin))
+		apiV1.GET("/auth/oidc/callback", h.echoHandler(h.OIDCCallback))
+		apiV1.POST("/auth/logout", h.echoHandler(h.Logout), echo.WrapMiddleware(h.GetAuthMiddleware("")))
 
 		// users  routes
 		apiV1.POST("/users", h.echoHandler(h.CreateUser), echo.WrapMiddleware(h.GetAuthMiddleware(domain.CreateUser)))
 		apiV1.PUT("/users/password", h.echoHandler(h.ResetPassword), echo.WrapMiddleware(h.GetAuthMiddleware(domain.ResetUserPassword)))
 		apiV1.PUT("/users/permissions", h.echoHandler(h.UpdateUserPermissions), echo.WrapMiddleware(h.GetAuthMiddleware(domain.ChangeUserPermission)))
 		apiV1.GET("/users", h.echoHandler(h.ListUsers), echo.WrapMiddleware(h.GetAuthMiddleware(domain.UserRead)))
+		apiV1.DELETE("/users/sessions", h.echoHandler(h.RevokeUserSessions), echo.WrapMiddleware(h.GetAuthMiddleware(domain.UserSessionRevoke)))
+		apiV1.PUT("/users/unlock", h.echoHandler(h.UnlockUser), echo.WrapMiddleware(h.GetAuthMiddleware(domain.UserUnlock)))
+		apiV1.DELETE("/users/2fa", h.echoHandler(h.ResetTwoFactor), echo.WrapMiddleware(h.GetAuthMiddleware(domain.UserTwoFactorReset)))
 		apiV1.PUT("/users/self/password", h.echoHandler(h.ChangePassword), echo.WrapMiddleware(h.GetAuthMiddleware("")))
 		apiV1.GET("/users/self", h.echoHandler(h.GetSelfUser), echo.WrapMiddleware(h.GetAuthMiddleware("")))
+		apiV1.POST("/users/self/2fa", h.echoHandler(h.BeginTwoFactorEnrollment), echo.WrapMiddleware(h.GetAuthMiddleware("")))
+		apiV1.PUT("/users/self/2fa", h.echoHandler(h.ConfirmTwoFactorEnrollment), echo.WrapMiddleware(h.GetAuthMiddleware("")))
+		apiV1.DELETE("/users/self/2fa", h.echoHandler(h.DisableTwoFactor), echo.WrapMiddleware(h.GetAuthMiddleware("")))
+		apiV1.POST("/users/self/2fa/recovery-codes", h.echoHandler(h.RegenerateRecoveryCodes), echo.WrapMiddleware(h.GetAuthMiddleware("")))
 
 		// role routes
 		apiV1.POST("/roles", h.echoHandler(h.CreateRole), echo.WrapMiddleware(h.GetAuthMiddleware(domain.RoleCrete)))
@@ -43,12 +56,52 @@ func (h *Handler) SetupRoutes(engine *echo.Echo) {
 		apiV1.PUT("/strategies", h.echoHandler(h.UpdateScheduleStrategy), echo.WrapMiddleware(h.GetAuthMiddleware(domain.ScheduleStrategyUpdate)))
 		apiV1.GET("/strategies/self", h.echoHandler(h.ListSelfScheduleStrategies), echo.WrapMiddleware(h.GetAuthMiddleware(domain.ScheduleStrategyRead)))
 		apiV1.DELETE("/strategies", h.echoHandler(h.DeleteScheduleStrategy), echo.WrapMiddleware(h.GetAuthMiddleware(domain.ScheduleStrategyDelete)))
+		apiV1.PUT("/strategies/owner", h.echoHandler(h.TransferScheduleStrategy), echo.WrapMiddleware(h.GetAuthMiddleware(domain.ScheduleStrategyTransfer)))
 		apiV1.GET("/intents/self", h.echoHandler(h.ListSelfScheduleIntents), echo.WrapMiddleware(h.GetAuthMiddleware(domain.ScheduleIntentRead)))
 		apiV1.DELETE("/intents", h.echoHandler(h.DeleteScheduleIntents), echo.WrapMiddleware(h.GetAuthMiddleware(domain.ScheduleIntentDelete)))
 
 		// pod-pid mapping routes
 		apiV1.GET("/nodes", h.echoHandler(h.ListNodes), echo.WrapMiddleware(h.GetAuthMiddleware(domain.PodPIDMappingRead)))
 		apiV1.GET("/nodes/:nodeID/pods/pids", h.echoHandlerWithParams(h.GetNodePodPIDMapping), echo.WrapMiddleware(h.GetAuthMiddleware(domain.PodPIDMappingRead)))
+		apiV1.GET("/nodes/metrics", h.echoHandler(h.GetClusterMetrics), echo.WrapMiddleware(h.GetAuthMiddleware(domain.NodeMetricsRead)))
+		apiV1.GET("/nodes/:nodeID/metrics/history", h.echoHandlerWithParams(h.GetNodeMetricHistory), echo.WrapMiddleware(h.GetAuthMiddleware(domain.NodeMetricsRead)))
+		apiV1.GET("/nodes/:nodeID/metrics/samples", h.echoHandlerWithParams(h.ListNodeMetricSamples), echo.WrapMiddleware(h.GetAuthMiddleware(domain.NodeMetricsRead)))
+		apiV1.GET("/metrics/samples", h.echoHandler(h.ListMetricSamples), echo.WrapMiddleware(h.GetAuthMiddleware(domain.NodeMetricsRead)))
+
+		// alerting routes
+		apiV1.POST("/alert-rules", h.echoHandler(h.CreateAlertRule), echo.WrapMiddleware(h.GetAuthMiddleware(domain.AlertRuleCreate)))
+		apiV1.PUT("/alert-rules", h.echoHandler(h.UpdateAlertRule), echo.WrapMiddleware(h.GetAuthMiddleware(domain.AlertRuleUpdate)))
+		apiV1.DELETE("/alert-rules", h.echoHandler(h.DeleteAlertRule), echo.WrapMiddleware(h.GetAuthMiddleware(domain.AlertRuleDelete)))
+		apiV1.GET("/alert-rules", h.echoHandler(h.ListAlertRules), echo.WrapMiddleware(h.GetAuthMiddleware(domain.AlertRuleRead)))
+		apiV1.GET("/alerts", h.echoHandler(h.ListAlerts), echo.WrapMiddleware(h.GetAuthMiddleware(domain.AlertRead)))
+
+		// webhook subscription routes
+		apiV1.POST("/webhook-subscriptions", h.echoHandler(h.CreateWebhookSubscription), echo.WrapMiddleware(h.GetAuthMiddleware(domain.WebhookSubscriptionCreate)))
+		apiV1.PUT("/webhook-subscriptions", h.echoHandler(h.UpdateWebhookSubscription), echo.WrapMiddleware(h.GetAuthMiddleware(domain.WebhookSubscriptionUpdate)))
+		apiV1.DELETE("/webhook-subscriptions", h.echoHandler(h.DeleteWebhookSubscription), echo.WrapMiddleware(h.GetAuthMiddleware(domain.WebhookSubscriptionDelete)))
+		apiV1.GET("/webhook-subscriptions", h.echoHandler(h.ListWebhookSubscriptions), echo.WrapMiddleware(h.GetAuthMiddleware(domain.WebhookSubscriptionRead)))
+
+		// service account routes
+		apiV1.POST("/service-accounts", h.echoHandler(h.CreateServiceAccount), echo.WrapMiddleware(h.GetAuthMiddleware(domain.ServiceAccountCreate)))
+		apiV1.PUT("/service-accounts", h.echoHandler(h.UpdateServiceAccount), echo.WrapMiddleware(h.GetAuthMiddleware(domain.ServiceAccountUpdate)))
+		apiV1.DELETE("/service-accounts", h.echoHandler(h.DeleteServiceAccount), echo.WrapMiddleware(h.GetAuthMiddleware(domain.ServiceAccountDelete)))
+		apiV1.GET("/service-accounts", h.echoHandler(h.ListServiceAccounts), echo.WrapMiddleware(h.GetAuthMiddleware(domain.ServiceAccountRead)))
+		apiV1.POST("/api-keys", h.echoHandler(h.CreateAPIKey), echo.WrapMiddleware(h.GetAuthMiddleware(domain.ServiceAccountUpdate)))
+		apiV1.DELETE("/api-keys", h.echoHandler(h.RevokeAPIKey), echo.WrapMiddleware(h.GetAuthMiddleware(domain.ServiceAccountUpdate)))
+		apiV1.GET("/api-keys", h.echoHandler(h.ListAPIKeys), echo.WrapMiddleware(h.GetAuthMiddleware(domain.ServiceAccountRead)))
+
+		// team routes
+		apiV1.POST("/teams", h.echoHandler(h.CreateTeam), echo.WrapMiddleware(h.GetAuthMiddleware(domain.TeamCreate)))
+		apiV1.PUT("/teams", h.echoHandler(h.UpdateTeam), echo.WrapMiddleware(h.GetAuthMiddleware(domain.TeamUpdate)))
+		apiV1.DELETE("/teams", h.echoHandler(h.DeleteTeam), echo.WrapMiddleware(h.GetAuthMiddleware(domain.TeamDelete)))
+		apiV1.GET("/teams", h.echoHandler(h.ListTeams), echo.WrapMiddleware(h.GetAuthMiddleware(domain.TeamRead)))
+		apiV1.GET("/teams/self", h.echoHandler(h.ListSelfTeams), echo.WrapMiddleware(h.GetAuthMiddleware("")))
+
+		// audit routes
+		apiV1.GET("/audit-logs", h.echoHandler(h.ListAuditLogs), echo.WrapMiddleware(h.GetAuthMiddleware(domain.AuditLogRead)))
+
+		// pod routes
+		apiV1.GET("/pods/:namespace/:name/explain", h.echoHandlerWithParams(h.ExplainPod), echo.WrapMiddleware(h.GetAuthMiddleware(domain.PodExplain)))
 	}
 
 }
@@ -65,7 +118,17 @@ func (h *Handler) echoHandlerWithParams(handlerFunc func(w http.ResponseWriter,
 		for _, name := range c.ParamNames() {
 			r = r.WithContext(context.WithValue(r.Context(), pathParamKey(name), c.Param(name)))
 		}
-		handlerFunc(c.Response().Writer, r)
+		handlerFunc(c.Response(), r)
+		return nil
+	}
+}
+
+// echoHandlerWithClientIP wraps a handler function and injects the client IP into request context
+func (h *Handler) echoHandlerWithClientIP(handlerFunc func(w http.ResponseWriter, r *http.Request)) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		r := c.Request()
+		r = r.WithContext(context.WithValue(r.Context(), clientIPKey{}, c.RealIP()))
+		handlerFunc(c.Response(), r)
 		return nil
 	}
 }
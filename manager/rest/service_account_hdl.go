@@ -0,0 +1,406 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/Gthulhu/api/manager/domain"
+)
+
+type CreateServiceAccountRequest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
+}
+
+type CreateServiceAccountResponse struct {
+	ID string `json:"id"`
+}
+
+type UpdateServiceAccountRequest struct {
+	ID          string    `json:"id"`
+	Description *string   `json:"description,omitempty"`
+	Roles       *[]string `json:"roles,omitempty"`
+	// Disabled rejects every API key of the service account while set
+	Disabled *bool `json:"disabled,omitempty"`
+}
+
+type DeleteServiceAccountRequest struct {
+	ID string `json:"id"`
+}
+
+// ServiceAccount represents a service account (for API response)
+type ServiceAccount struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Roles       []string `json:"roles"`
+	Disabled    bool     `json:"disabled,omitempty"`
+	CreatedTime int64    `json:"createdTime"`
+	UpdatedTime int64    `json:"updatedTime"`
+}
+
+type ListServiceAccountsResponse struct {
+	ServiceAccounts []ServiceAccount `json:"serviceAccounts"`
+}
+
+type CreateAPIKeyRequest struct {
+	ServiceAccountID string `json:"serviceAccountId"`
+	Name             string `json:"name"`
+	// Scopes restricts the key to these permission keys, empty means every permission of the
+	// roles of the service account
+	Scopes []string `json:"scopes,omitempty"`
+	// ExpiresAt is the unix time in milliseconds the key expires at, 90 days from now when omitted
+	ExpiresAt int64 `json:"expiresAt,omitempty"`
+}
+
+type CreateAPIKeyResponse struct {
+	ID string `json:"id"`
+	// Key is only returned on create, send it as "Authorization: Bearer <key>" or "X-API-Key: <key>"
+	Key       string `json:"key"`
+	ExpiresAt int64  `json:"expiresAt"`
+}
+
+type RevokeAPIKeyRequest struct {
+	ID string `json:"id"`
+}
+
+// APIKey represents an API key without its secret (for API response)
+type APIKey struct {
+	ID               string   `json:"id"`
+	ServiceAccountID string   `json:"serviceAccountId"`
+	Name             string   `json:"name"`
+	Prefix           string   `json:"prefix"`
+	Scopes           []string `json:"scopes,omitempty"`
+	ExpiresAt        int64    `json:"expiresAt"`
+	RevokedAt        int64    `json:"revokedAt,omitempty"`
+	LastUsedAt       int64    `json:"lastUsedAt,omitempty"`
+	CreatedTime      int64    `json:"createdTime"`
+}
+
+type ListAPIKeysResponse struct {
+	APIKeys []APIKey `json:"apiKeys"`
+}
+
+// CreateServiceAccount godoc
+// @Summary Create service account
+// @Description Create a service account for automation, authorized by its roles like a user.
+// @Tags ServiceAccounts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateServiceAccountRequest true "Service account payload"
+// @Success 200 {object} SuccessResponse[CreateServiceAccountResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/service-accounts [post]
+func (h *Handler) CreateServiceAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req CreateServiceAccountRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	account := &domain.ServiceAccount{
+		Name:        req.Name,
+		Description: req.Description,
+		Roles:       req.Roles,
+	}
+	err = h.Svc.CreateServiceAccount(ctx, &claims, account)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+	h.auditResource(ctx, account.ID.Hex())
+
+	response := NewSuccessResponse(&CreateServiceAccountResponse{ID: account.ID.Hex()})
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+// UpdateServiceAccount godoc
+// @Summary Update service account
+// @Description Update the description, roles or disabled state of a service account.
+// @Tags ServiceAccounts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdateServiceAccountRequest true "Service account payload"
+// @Success 200 {object} SuccessResponse[EmptyResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/service-accounts [put]
+func (h *Handler) UpdateServiceAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req UpdateServiceAccountRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if req.ID == "" {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Service account ID is required", nil)
+		return
+	}
+
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	h.auditResource(ctx, req.ID)
+	err = h.Svc.UpdateServiceAccount(ctx, &claims, req.ID, domain.UpdateServiceAccountOptions{
+		Description: req.Description,
+		Roles:       req.Roles,
+		Disabled:    req.Disabled,
+	})
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	response := NewSuccessResponse[EmptyResponse](&EmptyResponse{})
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+// DeleteServiceAccount godoc
+// @Summary Delete service account
+// @Description Delete a service account together with its API keys.
+// @Tags ServiceAccounts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body DeleteServiceAccountRequest true "Service account payload"
+// @Success 200 {object} SuccessResponse[EmptyResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/service-accounts [delete]
+func (h *Handler) DeleteServiceAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req DeleteServiceAccountRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if req.ID == "" {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Service account ID is required", nil)
+		return
+	}
+
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	h.auditResource(ctx, req.ID)
+	err = h.Svc.DeleteServiceAccount(ctx, &claims, req.ID)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	response := NewSuccessResponse[EmptyResponse](&EmptyResponse{})
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+// ListServiceAccounts godoc
+// @Summary List service accounts
+// @Description Retrieve all service accounts.
+// @Tags ServiceAccounts
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} SuccessResponse[ListServiceAccountsResponse]
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/service-accounts [get]
+func (h *Handler) ListServiceAccounts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	opt := &domain.QueryServiceAccountOptions{}
+	if err := h.Svc.QueryServiceAccounts(ctx, opt); err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	resp := ListServiceAccountsResponse{
+		ServiceAccounts: make([]ServiceAccount, len(opt.Result)),
+	}
+	for i, account := range opt.Result {
+		roles := account.Roles
+		if roles == nil {
+			roles = []string{}
+		}
+		resp.ServiceAccounts[i] = ServiceAccount{
+			ID:          account.ID.Hex(),
+			Name:        account.Name,
+			Description: account.Description,
+			Roles:       roles,
+			Disabled:    account.Disabled,
+			CreatedTime: account.CreatedTime,
+			UpdatedTime: account.UpdatedTime,
+		}
+	}
+	h.JSONResponse(ctx, w, http.StatusOK, NewSuccessResponse(&resp))
+}
+
+// CreateAPIKey godoc
+// @Summary Create API key
+// @Description Issue an API key for a service account. The key is only returned once.
+// @Tags ServiceAccounts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateAPIKeyRequest true "API key payload"
+// @Success 200 {object} SuccessResponse[CreateAPIKeyResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/api-keys [post]
+func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req CreateAPIKeyRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if req.ServiceAccountID == "" {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Service account ID is required", nil)
+		return
+	}
+
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	key := &domain.APIKey{
+		ServiceAccountID: req.ServiceAccountID,
+		Name:             req.Name,
+		ExpiresAt:        req.ExpiresAt,
+	}
+	for _, scope := range req.Scopes {
+		key.Scopes = append(key.Scopes, domain.PermissionKey(scope))
+	}
+	plainKey, err := h.Svc.CreateAPIKey(ctx, &claims, key)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+	h.auditResource(ctx, key.ID.Hex(), req.ServiceAccountID)
+
+	response := NewSuccessResponse(&CreateAPIKeyResponse{ID: key.ID.Hex(), Key: plainKey, ExpiresAt: key.ExpiresAt})
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke API key
+// @Description Revoke an API key, requests authenticated with it are rejected from now on.
+// @Tags ServiceAccounts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body RevokeAPIKeyRequest true "API key payload"
+// @Success 200 {object} SuccessResponse[EmptyResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/api-keys [delete]
+func (h *Handler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req RevokeAPIKeyRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if req.ID == "" {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "API key ID is required", nil)
+		return
+	}
+
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	h.auditResource(ctx, req.ID)
+	err = h.Svc.RevokeAPIKey(ctx, &claims, req.ID)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	response := NewSuccessResponse[EmptyResponse](&EmptyResponse{})
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+// ListAPIKeys godoc
+// @Summary List API keys
+// @Description Retrieve the API keys, optionally of a single service account. Secrets are never returned.
+// @Tags ServiceAccounts
+// @Produce json
+// @Security BearerAuth
+// @Param serviceAccountId query string false "Service account ID"
+// @Success 200 {object} SuccessResponse[ListAPIKeysResponse]
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/api-keys [get]
+func (h *Handler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	opt := &domain.QueryAPIKeyOptions{}
+	if accountID := r.URL.Query().Get("serviceAccountId"); accountID != "" {
+		opt.ServiceAccountIDs = []string{accountID}
+	}
+	if err := h.Svc.QueryAPIKeys(ctx, opt); err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	resp := ListAPIKeysResponse{
+		APIKeys: make([]APIKey, len(opt.Result)),
+	}
+	for i, key := range opt.Result {
+		scopes := make([]string, len(key.Scopes))
+		for j, scope := range key.Scopes {
+			scopes[j] = string(scope)
+		}
+		resp.APIKeys[i] = APIKey{
+			ID:               key.ID.Hex(),
+			ServiceAccountID: key.ServiceAccountID,
+			Name:             key.Name,
+			Prefix:           domain.APIKeyPrefix + key.KeyID,
+			Scopes:           scopes,
+			ExpiresAt:        key.ExpiresAt,
+			RevokedAt:        key.RevokedAt,
+			LastUsedAt:       key.LastUsedAt,
+			CreatedTime:      key.CreatedTime,
+		}
+	}
+	h.JSONResponse(ctx, w, http.StatusOK, NewSuccessResponse(&resp))
+}
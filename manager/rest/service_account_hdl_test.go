@@ -0,0 +1,107 @@
+package rest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/manager/rest"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestAuthMiddlewareAcceptsAPIKeys(t *testing.T) {
+	accountID := bson.NewObjectID()
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		apiKey string
+	}{
+		{name: "bearer API key", header: "Authorization", value: "Bearer gth_abc_secret", apiKey: "gth_abc_secret"},
+		{name: "X-API-Key header", header: rest.APIKeyHeader, value: "gth_abc_secret", apiKey: "gth_abc_secret"},
+		{name: "bearer JWT", header: "Authorization", value: "Bearer eyJtoken"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := domain.NewMockService(t)
+			h, err := rest.NewHandler(rest.Params{Svc: svc})
+			require.NoError(t, err)
+			engine := echo.New()
+			h.SetupRoutes(engine)
+
+			claims := domain.Claims{UID: accountID.Hex(), ServiceAccount: tt.apiKey != ""}
+			if tt.apiKey != "" {
+				svc.EXPECT().VerifyAPIKey(mock.Anything, tt.apiKey, domain.ServiceAccountRead).Return(claims, domain.RolePolicy{}, nil).Once()
+			} else {
+				svc.EXPECT().VerifyJWTToken(mock.Anything, "eyJtoken", domain.ServiceAccountRead).Return(claims, domain.RolePolicy{}, nil).Once()
+			}
+			svc.EXPECT().QueryServiceAccounts(mock.Anything, mock.Anything).
+				Run(func(ctx context.Context, opt *domain.QueryServiceAccountOptions) {
+					opt.Result = []*domain.ServiceAccount{{BaseEntity: domain.BaseEntity{ID: accountID}, Name: "ci"}}
+				}).Return(nil).Once()
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/service-accounts", nil)
+			req.Header.Set(tt.header, tt.value)
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			var resp rest.SuccessResponse[rest.ListServiceAccountsResponse]
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			require.Len(t, resp.Data.ServiceAccounts, 1)
+			assert.Equal(t, "ci", resp.Data.ServiceAccounts[0].Name)
+			assert.Equal(t, []string{}, resp.Data.ServiceAccounts[0].Roles)
+		})
+	}
+}
+
+func TestCreateAPIKeyReturnsKeyOnce(t *testing.T) {
+	svc := domain.NewMockService(t)
+	h, err := rest.NewHandler(rest.Params{Svc: svc})
+	require.NoError(t, err)
+	engine := echo.New()
+	h.SetupRoutes(engine)
+
+	userID, accountID, keyID := bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()
+	svc.EXPECT().VerifyJWTToken(mock.Anything, "token", domain.ServiceAccountUpdate).
+		Return(domain.Claims{UID: userID.Hex()}, domain.RolePolicy{}, nil).Once()
+	svc.EXPECT().CreateAPIKey(mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, operator *domain.Claims, key *domain.APIKey) (string, error) {
+			assert.Equal(t, userID.Hex(), operator.UID)
+			assert.Equal(t, accountID.Hex(), key.ServiceAccountID)
+			assert.Equal(t, []domain.PermissionKey{domain.ScheduleStrategyRead}, key.Scopes)
+			key.ID = keyID
+			key.ExpiresAt = 1000
+			return "gth_abc_secret", nil
+		}).Once()
+	var audit *domain.AuditLog
+	svc.EXPECT().CreateAuditLog(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, log *domain.AuditLog) { audit = log }).
+		Return(nil).Once()
+
+	body, _ := json.Marshal(map[string]any{"serviceAccountId": accountID.Hex(), "name": "deploy", "scopes": []string{"schedule_strategy.read"}})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/api-keys", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp rest.SuccessResponse[rest.CreateAPIKeyResponse]
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, keyID.Hex(), resp.Data.ID)
+	assert.Equal(t, "gth_abc_secret", resp.Data.Key)
+	assert.Equal(t, int64(1000), resp.Data.ExpiresAt)
+	require.NotNil(t, audit)
+	assert.Equal(t, string(domain.ServiceAccountUpdate), audit.Action)
+	assert.Equal(t, keyID.Hex()+","+accountID.Hex(), audit.ResourceID)
+	assert.NotContains(t, rec.Body.String(), "secretHash")
+}
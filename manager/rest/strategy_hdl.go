@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/Gthulhu/api/manager/domain"
@@ -17,8 +18,15 @@ type CreateScheduleStrategyRequest struct {
 	LabelSelectors    []LabelSelector `json:"labelSelectors,omitempty"`
 	K8sNamespace      []string        `json:"k8sNamespace,omitempty"`
 	CommandRegex      string          `json:"commandRegex,omitempty"`
-	Priority          int             `json:"priority,omitempty"`
-	ExecutionTime     int64           `json:"executionTime,omitempty"`
+	// CommandMatchTargets lists what CommandRegex is matched against: comm, cmdline, exe or containerName.
+	// Defaults to cmdline for selecting pods, the decision maker matches their processes on comm.
+	CommandMatchTargets []string `json:"commandMatchTargets,omitempty"`
+	// ThreadRegex selects individual threads of the matched processes by thread name
+	ThreadRegex   string `json:"threadRegex,omitempty"`
+	Priority      int    `json:"priority,omitempty"`
+	ExecutionTime int64  `json:"executionTime,omitempty"`
+	// TeamID shares the strategy with the members of the team, the creator has to be one of them
+	TeamID string `json:"teamId,omitempty"`
 }
 
 type UpdateScheduleStrategyRequest struct {
@@ -27,8 +35,13 @@ type UpdateScheduleStrategyRequest struct {
 	LabelSelectors    []LabelSelector `json:"labelSelectors,omitempty"`
 	K8sNamespace      []string        `json:"k8sNamespace,omitempty"`
 	CommandRegex      string          `json:"commandRegex,omitempty"`
-	Priority          int             `json:"priority,omitempty"`
-	ExecutionTime     int64           `json:"executionTime,omitempty"`
+	// CommandMatchTargets lists what CommandRegex is matched against: comm, cmdline, exe or containerName.
+	// Defaults to cmdline for selecting pods, the decision maker matches their processes on comm.
+	CommandMatchTargets []string `json:"commandMatchTargets,omitempty"`
+	// ThreadRegex selects individual threads of the matched processes by thread name
+	ThreadRegex   string `json:"threadRegex,omitempty"`
+	Priority      int    `json:"priority,omitempty"`
+	ExecutionTime int64  `json:"executionTime,omitempty"`
 }
 
 // CreateScheduleStrategy godoc
@@ -61,6 +74,9 @@ func (h *Handler) CreateScheduleStrategy(w http.ResponseWriter, r *http.Request)
 		CommandRegex:      req.CommandRegex,
 		Priority:          req.Priority,
 		ExecutionTime:     req.ExecutionTime,
+
+		CommandMatchTargets: convertCommandMatchTargets(req.CommandMatchTargets),
+		ThreadRegex:         req.ThreadRegex,
 	}
 	for i, ls := range req.LabelSelectors {
 		strategy.LabelSelectors[i] = domain.LabelSelector{
@@ -68,6 +84,13 @@ func (h *Handler) CreateScheduleStrategy(w http.ResponseWriter, r *http.Request)
 			Value: ls.Value,
 		}
 	}
+	if req.TeamID != "" {
+		strategy.TeamID, err = bson.ObjectIDFromHex(req.TeamID)
+		if err != nil {
+			h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid team ID", err)
+			return
+		}
+	}
 
 	claims, ok := h.GetClaimsFromContext(ctx)
 	if !ok {
@@ -80,6 +103,8 @@ func (h *Handler) CreateScheduleStrategy(w http.ResponseWriter, r *http.Request)
 		h.HandleError(ctx, w, err)
 		return
 	}
+	h.auditResource(ctx, strategy.ID.Hex())
+	h.auditChanges(ctx, nil, h.convertDomainStrategyToResponseStrategy(strategy))
 
 	response := NewSuccessResponse[string](nil)
 	h.JSONResponse(ctx, w, http.StatusOK, response)
@@ -119,6 +144,9 @@ func (h *Handler) UpdateScheduleStrategy(w http.ResponseWriter, r *http.Request)
 		CommandRegex:      req.CommandRegex,
 		Priority:          req.Priority,
 		ExecutionTime:     req.ExecutionTime,
+
+		CommandMatchTargets: convertCommandMatchTargets(req.CommandMatchTargets),
+		ThreadRegex:         req.ThreadRegex,
 	}
 	for i, ls := range req.LabelSelectors {
 		strategy.LabelSelectors[i] = domain.LabelSelector{
@@ -133,10 +161,13 @@ func (h *Handler) UpdateScheduleStrategy(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	h.auditResource(ctx, req.StrategyID)
+	before := h.auditedStrategy(ctx, req.StrategyID)
 	if err := h.Svc.UpdateScheduleStrategy(ctx, &claims, req.StrategyID, strategy); err != nil {
 		h.HandleError(ctx, w, err)
 		return
 	}
+	h.auditChanges(ctx, before, h.auditedStrategy(ctx, req.StrategyID))
 
 	response := NewSuccessResponse[string](nil)
 	h.JSONResponse(ctx, w, http.StatusOK, response)
@@ -147,18 +178,22 @@ type ListSchedulerStrategiesResponse struct {
 }
 
 type ScheduleStrategy struct {
-	ID                bson.ObjectID   `bson:"_id,omitempty"`
-	StrategyNamespace string          `bson:"strategyNamespace,omitempty"`
-	LabelSelectors    []LabelSelector `bson:"labelSelectors,omitempty"`
-	K8sNamespace      []string        `bson:"k8sNamespace,omitempty"`
-	CommandRegex      string          `bson:"commandRegex,omitempty"`
-	Priority          int             `bson:"priority,omitempty"`
-	ExecutionTime     int64           `bson:"executionTime,omitempty"`
+	ID                  bson.ObjectID               `bson:"_id,omitempty"`
+	StrategyNamespace   string                      `bson:"strategyNamespace,omitempty"`
+	LabelSelectors      []LabelSelector             `bson:"labelSelectors,omitempty"`
+	K8sNamespace        []string                    `bson:"k8sNamespace,omitempty"`
+	CommandRegex        string                      `bson:"commandRegex,omitempty"`
+	CommandMatchTargets []domain.CommandMatchTarget `bson:"commandMatchTargets,omitempty"`
+	ThreadRegex         string                      `bson:"threadRegex,omitempty"`
+	Priority            int                         `bson:"priority,omitempty"`
+	ExecutionTime       int64                       `bson:"executionTime,omitempty"`
+	CreatorID           bson.ObjectID               `bson:"creatorID,omitempty"`
+	TeamID              bson.ObjectID               `bson:"teamID,omitempty"`
 }
 
 // ListSelfScheduleStrategies godoc
 // @Summary List self schedule strategies
-// @Description List schedule strategies created by the authenticated user.
+// @Description List schedule strategies created by the authenticated user or owned by one of their teams.
 // @Tags Strategies
 // @Accept json
 // @Produce json
@@ -182,8 +217,14 @@ func (h *Handler) ListSelfScheduleStrategies(w http.ResponseWriter, r *http.Requ
 		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid user ID in token", err)
 		return
 	}
+	teamIDs, err := h.selfTeamIDs(ctx, uid)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
 	queryOpt := &domain.QueryStrategyOptions{
 		CreatorIDs: []bson.ObjectID{uid},
+		TeamIDs:    teamIDs,
 	}
 
 	err = h.Svc.ListScheduleStrategies(ctx, queryOpt)
@@ -202,6 +243,23 @@ func (h *Handler) ListSelfScheduleStrategies(w http.ResponseWriter, r *http.Requ
 	h.JSONResponse(ctx, w, http.StatusOK, response)
 }
 
+// auditedStrategy loads the strategy for the audit diff of a mutating request, nil when the
+// request is not audited or the strategy is not found
+func (h *Handler) auditedStrategy(ctx context.Context, strategyID string) *ScheduleStrategy {
+	if auditEntryFromContext(ctx) == nil {
+		return nil
+	}
+	strategyObjID, err := bson.ObjectIDFromHex(strategyID)
+	if err != nil {
+		return nil
+	}
+	queryOpt := &domain.QueryStrategyOptions{IDs: []bson.ObjectID{strategyObjID}}
+	if err := h.Svc.ListScheduleStrategies(ctx, queryOpt); err != nil || len(queryOpt.Result) == 0 {
+		return nil
+	}
+	return h.convertDomainStrategyToResponseStrategy(queryOpt.Result[0])
+}
+
 func (h *Handler) convertDomainStrategyToResponseStrategy(domainStrategy *domain.ScheduleStrategy) *ScheduleStrategy {
 	return &ScheduleStrategy{
 		ID:                domainStrategy.ID,
@@ -211,7 +269,23 @@ func (h *Handler) convertDomainStrategyToResponseStrategy(domainStrategy *domain
 		CommandRegex:      domainStrategy.CommandRegex,
 		Priority:          domainStrategy.Priority,
 		ExecutionTime:     domainStrategy.ExecutionTime,
+
+		CommandMatchTargets: domainStrategy.CommandMatchTargets,
+		ThreadRegex:         domainStrategy.ThreadRegex,
+		CreatorID:           domainStrategy.CreatorID,
+		TeamID:              domainStrategy.TeamID,
+	}
+}
+
+func convertCommandMatchTargets(targets []string) []domain.CommandMatchTarget {
+	if len(targets) == 0 {
+		return nil
+	}
+	results := make([]domain.CommandMatchTarget, 0, len(targets))
+	for _, target := range targets {
+		results = append(results, domain.CommandMatchTarget(target))
 	}
+	return results
 }
 
 func convertDomainLabelSelectorsToResponseLabelSelectors(domainLabelSelectors []domain.LabelSelector) []LabelSelector {
@@ -232,6 +306,7 @@ type ListScheduleIntentsResponse struct {
 type ScheduleIntent struct {
 	ID            bson.ObjectID      `bson:"_id,omitempty"`
 	StrategyID    bson.ObjectID      `bson:"strategyID,omitempty"`
+	TeamID        bson.ObjectID      `bson:"teamID,omitempty"`
 	PodID         string             `bson:"podID,omitempty"`
 	NodeID        string             `bson:"nodeID,omitempty"`
 	K8sNamespace  string             `bson:"k8sNamespace,omitempty"`
@@ -240,11 +315,14 @@ type ScheduleIntent struct {
 	ExecutionTime int64              `bson:"executionTime,omitempty"`
 	PodLabels     map[string]string  `bson:"podLabels,omitempty"`
 	State         domain.IntentState `bson:"state,omitempty"`
+
+	CommandMatchTargets []domain.CommandMatchTarget `bson:"commandMatchTargets,omitempty"`
+	ThreadRegex         string                      `bson:"threadRegex,omitempty"`
 }
 
 // ListSelfScheduleIntents godoc
 // @Summary List self schedule intents
-// @Description List schedule intents created by the authenticated user.
+// @Description List schedule intents created by the authenticated user or owned by one of their teams.
 // @Tags Strategies
 // @Accept json
 // @Produce json
@@ -268,8 +346,14 @@ func (h *Handler) ListSelfScheduleIntents(w http.ResponseWriter, r *http.Request
 		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid user ID in token", err)
 		return
 	}
+	teamIDs, err := h.selfTeamIDs(ctx, uid)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
 	queryOpt := &domain.QueryIntentOptions{
 		CreatorIDs: []bson.ObjectID{uid},
+		TeamIDs:    teamIDs,
 	}
 
 	err = h.Svc.ListScheduleIntents(ctx, queryOpt)
@@ -292,6 +376,7 @@ func (h *Handler) convertDomainIntentToResponseIntent(domainIntent *domain.Sched
 	return &ScheduleIntent{
 		ID:            domainIntent.ID,
 		StrategyID:    domainIntent.StrategyID,
+		TeamID:        domainIntent.TeamID,
 		PodID:         domainIntent.PodID,
 		NodeID:        domainIntent.NodeID,
 		K8sNamespace:  domainIntent.K8sNamespace,
@@ -300,6 +385,9 @@ func (h *Handler) convertDomainIntentToResponseIntent(domainIntent *domain.Sched
 		ExecutionTime: domainIntent.ExecutionTime,
 		PodLabels:     domainIntent.PodLabels,
 		State:         domainIntent.State,
+
+		CommandMatchTargets: domainIntent.CommandMatchTargets,
+		ThreadRegex:         domainIntent.ThreadRegex,
 	}
 }
 
@@ -342,11 +430,14 @@ func (h *Handler) DeleteScheduleStrategy(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	h.auditResource(ctx, req.StrategyID)
+	before := h.auditedStrategy(ctx, req.StrategyID)
 	err = h.Svc.DeleteScheduleStrategy(ctx, &claims, req.StrategyID)
 	if err != nil {
 		h.HandleError(ctx, w, err)
 		return
 	}
+	h.auditChanges(ctx, before, nil)
 
 	response := NewSuccessResponse[EmptyResponse](&EmptyResponse{})
 	h.JSONResponse(ctx, w, http.StatusOK, response)
@@ -391,6 +482,7 @@ func (h *Handler) DeleteScheduleIntents(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	h.auditResource(ctx, req.IntentIDs...)
 	err = h.Svc.DeleteScheduleIntents(ctx, &claims, req.IntentIDs)
 	if err != nil {
 		h.HandleError(ctx, w, err)
@@ -420,6 +512,8 @@ type PodPIDInfo struct {
 type PodPIDProcess struct {
 	PID         int    `json:"pid"`
 	Command     string `json:"command"`
+	Cmdline     string `json:"cmdline,omitempty"`
+	Exe         string `json:"exe,omitempty"`
 	PPID        int    `json:"ppid,omitempty"`
 	ContainerID string `json:"container_id,omitempty"`
 }
@@ -465,6 +559,8 @@ func (h *Handler) GetNodePodPIDMapping(w http.ResponseWriter, r *http.Request) {
 			processes[j] = PodPIDProcess{
 				PID:         proc.PID,
 				Command:     proc.Command,
+				Cmdline:     proc.Cmdline,
+				Exe:         proc.Exe,
 				PPID:        proc.PPID,
 				ContainerID: proc.ContainerID,
 			}
@@ -0,0 +1,401 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type CreateTeamRequest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	MemberIDs   []string `json:"memberIds,omitempty"`
+}
+
+type CreateTeamResponse struct {
+	ID string `json:"id"`
+}
+
+type UpdateTeamRequest struct {
+	ID          string  `json:"id"`
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	// MemberIDs replaces the members of the team
+	MemberIDs *[]string `json:"memberIds,omitempty"`
+}
+
+type DeleteTeamRequest struct {
+	ID string `json:"id"`
+}
+
+// Team represents a team (for API response)
+type Team struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	MemberIDs   []string `json:"memberIds"`
+	CreatedTime int64    `json:"createdTime"`
+	UpdatedTime int64    `json:"updatedTime"`
+}
+
+type ListTeamsResponse struct {
+	Teams []Team `json:"teams"`
+}
+
+type TransferScheduleStrategyRequest struct {
+	StrategyID string `json:"strategyId"`
+	// CreatorID is the new creator of the strategy, the current creator is kept when omitted
+	CreatorID string `json:"creatorId,omitempty"`
+	// TeamID is the team owning the strategy from now on
+	TeamID string `json:"teamId"`
+}
+
+// CreateTeam godoc
+// @Summary Create team
+// @Description Create a team whose members share the ownership of its schedule strategies.
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateTeamRequest true "Team payload"
+// @Success 200 {object} SuccessResponse[CreateTeamResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/teams [post]
+func (h *Handler) CreateTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req CreateTeamRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	memberIDs, err := parseObjectIDs(req.MemberIDs)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid member ID", err)
+		return
+	}
+
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	team := &domain.Team{
+		Name:        req.Name,
+		Description: req.Description,
+		MemberIDs:   memberIDs,
+	}
+	err = h.Svc.CreateTeam(ctx, &claims, team)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+	h.auditResource(ctx, team.ID.Hex())
+
+	response := NewSuccessResponse(&CreateTeamResponse{ID: team.ID.Hex()})
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+// UpdateTeam godoc
+// @Summary Update team
+// @Description Update the name, description or members of a team.
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdateTeamRequest true "Team payload"
+// @Success 200 {object} SuccessResponse[EmptyResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/teams [put]
+func (h *Handler) UpdateTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req UpdateTeamRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if req.ID == "" {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Team ID is required", nil)
+		return
+	}
+	opt := domain.UpdateTeamOptions{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if req.MemberIDs != nil {
+		memberIDs, err := parseObjectIDs(*req.MemberIDs)
+		if err != nil {
+			h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid member ID", err)
+			return
+		}
+		opt.MemberIDs = &memberIDs
+	}
+
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	h.auditResource(ctx, req.ID)
+	before := h.auditedTeam(ctx, req.ID)
+	err = h.Svc.UpdateTeam(ctx, &claims, req.ID, opt)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+	h.auditChanges(ctx, before, h.auditedTeam(ctx, req.ID))
+
+	response := NewSuccessResponse[EmptyResponse](&EmptyResponse{})
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+// DeleteTeam godoc
+// @Summary Delete team
+// @Description Delete a team, its strategies have to be transferred first.
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body DeleteTeamRequest true "Team payload"
+// @Success 200 {object} SuccessResponse[EmptyResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/teams [delete]
+func (h *Handler) DeleteTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req DeleteTeamRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if req.ID == "" {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Team ID is required", nil)
+		return
+	}
+
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	h.auditResource(ctx, req.ID)
+	before := h.auditedTeam(ctx, req.ID)
+	err = h.Svc.DeleteTeam(ctx, &claims, req.ID)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+	h.auditChanges(ctx, before, nil)
+
+	response := NewSuccessResponse[EmptyResponse](&EmptyResponse{})
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+// ListTeams godoc
+// @Summary List teams
+// @Description Retrieve all teams.
+// @Tags Teams
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} SuccessResponse[ListTeamsResponse]
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/teams [get]
+func (h *Handler) ListTeams(w http.ResponseWriter, r *http.Request) {
+	h.listTeams(w, r, &domain.QueryTeamOptions{})
+}
+
+// ListSelfTeams godoc
+// @Summary List self teams
+// @Description Retrieve the teams the authenticated user is a member of.
+// @Tags Teams
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} SuccessResponse[ListTeamsResponse]
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/teams/self [get]
+func (h *Handler) ListSelfTeams(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	uid, err := claims.GetBsonObjectUID()
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", err)
+		return
+	}
+	h.listTeams(w, r, &domain.QueryTeamOptions{MemberIDs: []bson.ObjectID{uid}})
+}
+
+func (h *Handler) listTeams(w http.ResponseWriter, r *http.Request, opt *domain.QueryTeamOptions) {
+	ctx := r.Context()
+	if err := h.Svc.QueryTeams(ctx, opt); err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	resp := ListTeamsResponse{
+		Teams: make([]Team, len(opt.Result)),
+	}
+	for i, team := range opt.Result {
+		resp.Teams[i] = *convertDomainTeamToResponseTeam(team)
+	}
+	h.JSONResponse(ctx, w, http.StatusOK, NewSuccessResponse(&resp))
+}
+
+// TransferScheduleStrategy godoc
+// @Summary Transfer schedule strategy
+// @Description Hand a schedule strategy and its intents over to another creator and/or team.
+// @Tags Strategies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body TransferScheduleStrategyRequest true "Transfer payload"
+// @Success 200 {object} SuccessResponse[EmptyResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/strategies/owner [put]
+func (h *Handler) TransferScheduleStrategy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req TransferScheduleStrategyRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	strategyID, err := bson.ObjectIDFromHex(req.StrategyID)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid strategy ID", err)
+		return
+	}
+	var opt domain.TransferStrategyOptions
+	if req.CreatorID != "" {
+		opt.CreatorID, err = bson.ObjectIDFromHex(req.CreatorID)
+		if err != nil {
+			h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid creator ID", err)
+			return
+		}
+	}
+	opt.TeamID, err = bson.ObjectIDFromHex(req.TeamID)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid team ID", err)
+		return
+	}
+
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	queryOpt := &domain.QueryStrategyOptions{IDs: []bson.ObjectID{strategyID}}
+	if err := h.Svc.ListScheduleStrategies(ctx, queryOpt); err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+	if len(queryOpt.Result) == 0 {
+		h.ErrorResponse(ctx, w, http.StatusNotFound, "Strategy not found", nil)
+		return
+	}
+	// with a self policy only the creator can give a strategy away
+	if err := h.VerifyResourcePolicy(ctx, queryOpt.Result[0].CreatorID.Hex()); err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	h.auditResource(ctx, req.StrategyID)
+	before := h.convertDomainStrategyToResponseStrategy(queryOpt.Result[0])
+	err = h.Svc.TransferScheduleStrategy(ctx, &claims, req.StrategyID, opt)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+	h.auditChanges(ctx, before, h.auditedStrategy(ctx, req.StrategyID))
+
+	response := NewSuccessResponse[EmptyResponse](&EmptyResponse{})
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+// selfTeamIDs returns the teams the user with uid is a member of
+func (h *Handler) selfTeamIDs(ctx context.Context, uid bson.ObjectID) ([]bson.ObjectID, error) {
+	opt := &domain.QueryTeamOptions{MemberIDs: []bson.ObjectID{uid}}
+	if err := h.Svc.QueryTeams(ctx, opt); err != nil {
+		return nil, err
+	}
+	teamIDs := make([]bson.ObjectID, len(opt.Result))
+	for i, team := range opt.Result {
+		teamIDs[i] = team.ID
+	}
+	return teamIDs, nil
+}
+
+// auditedTeam returns the current representation of a team for the audit diff, nil when the request
+// isn't audited or the team can't be loaded
+func (h *Handler) auditedTeam(ctx context.Context, teamID string) *Team {
+	if auditEntryFromContext(ctx) == nil {
+		return nil
+	}
+	teamObjID, err := bson.ObjectIDFromHex(teamID)
+	if err != nil {
+		return nil
+	}
+	opt := &domain.QueryTeamOptions{IDs: []bson.ObjectID{teamObjID}}
+	if err := h.Svc.QueryTeams(ctx, opt); err != nil || len(opt.Result) == 0 {
+		return nil
+	}
+	return convertDomainTeamToResponseTeam(opt.Result[0])
+}
+
+func convertDomainTeamToResponseTeam(team *domain.Team) *Team {
+	memberIDs := make([]string, len(team.MemberIDs))
+	for i, id := range team.MemberIDs {
+		memberIDs[i] = id.Hex()
+	}
+	return &Team{
+		ID:          team.ID.Hex(),
+		Name:        team.Name,
+		Description: team.Description,
+		MemberIDs:   memberIDs,
+		CreatedTime: team.CreatedTime,
+		UpdatedTime: team.UpdatedTime,
+	}
+}
+
+func parseObjectIDs(hexIDs []string) ([]bson.ObjectID, error) {
+	ids := make([]bson.ObjectID, len(hexIDs))
+	for i, hexID := range hexIDs {
+		id, err := bson.ObjectIDFromHex(hexID)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
@@ -0,0 +1,262 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+)
+
+type VerifyTwoFactorLoginRequest struct {
+	TwoFactorToken string `json:"twoFactorToken"`
+	// Code is a TOTP code or one of the recovery codes
+	Code string `json:"code"`
+}
+
+// VerifyTwoFactorLogin godoc
+// @Summary Complete two-factor login
+// @Description Exchange the two-factor token of a login and a TOTP or recovery code for a JWT access token with a refresh token. Wrong codes count as failed logins.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body VerifyTwoFactorLoginRequest true "Two-factor payload"
+// @Success 200 {object} SuccessResponse[LoginResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 423 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/auth/2fa [post]
+func (h *Handler) VerifyTwoFactorLogin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req VerifyTwoFactorLoginRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if req.TwoFactorToken == "" || req.Code == "" {
+		h.ErrorResponse(ctx, w, http.StatusUnprocessableEntity, "Two-factor token and code are required", errors.New("two-factor token or code is empty"))
+		return
+	}
+
+	tokens, err := h.Svc.VerifyTwoFactorLogin(ctx, req.TwoFactorToken, req.Code, ClientIPFromContext(ctx))
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+	response := NewSuccessResponse(newLoginResponse(tokens))
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+type TwoFactorEnrollmentResponse struct {
+	// Secret is the base32 TOTP secret for manual entry, URI the otpauth:// URI to render as QR code
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// BeginTwoFactorEnrollment godoc
+// @Summary Enroll authenticator
+// @Description Generate a TOTP secret for the authenticated user. Two-factor authentication is enabled once a code of the secret is confirmed with PUT /api/v1/users/self/2fa.
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} SuccessResponse[TwoFactorEnrollmentResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/self/2fa [post]
+func (h *Handler) BeginTwoFactorEnrollment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", errors.New("claims not found"))
+		return
+	}
+
+	h.auditResource(ctx, claims.UID)
+	enrollment, err := h.Svc.BeginTwoFactorEnrollment(ctx, &claims)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+	response := NewSuccessResponse(&TwoFactorEnrollmentResponse{Secret: enrollment.Secret, URI: enrollment.URI})
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type RecoveryCodesResponse struct {
+	// RecoveryCodes are single-use codes for logins without the authenticator, they're only shown once
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// ConfirmTwoFactorEnrollment godoc
+// @Summary Confirm authenticator
+// @Description Enable two-factor authentication with a code of the enrolled TOTP secret and return the recovery codes.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body TwoFactorCodeRequest true "Code payload"
+// @Success 200 {object} SuccessResponse[RecoveryCodesResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/self/2fa [put]
+func (h *Handler) ConfirmTwoFactorEnrollment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req TwoFactorCodeRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", errors.New("claims not found"))
+		return
+	}
+
+	h.auditResource(ctx, claims.UID)
+	codes, err := h.Svc.ConfirmTwoFactorEnrollment(ctx, &claims, req.Code)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+	response := NewSuccessResponse(&RecoveryCodesResponse{RecoveryCodes: codes})
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+type DisableTwoFactorRequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+// DisableTwoFactor godoc
+// @Summary Disable two-factor authentication
+// @Description Remove the authenticator and recovery codes of the authenticated user, it takes the password and a TOTP or recovery code.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body DisableTwoFactorRequest true "Disable payload"
+// @Success 200 {object} SuccessResponse[EmptyResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/self/2fa [delete]
+func (h *Handler) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req DisableTwoFactorRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", errors.New("claims not found"))
+		return
+	}
+
+	h.auditResource(ctx, claims.UID)
+	err = h.Svc.DisableTwoFactor(ctx, &claims, req.Password, req.Code)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+	response := NewSuccessResponse[EmptyResponse](&EmptyResponse{})
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Regenerate recovery codes
+// @Description Replace the recovery codes of the authenticated user, the previous codes stop working.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body TwoFactorCodeRequest true "Code payload"
+// @Success 200 {object} SuccessResponse[RecoveryCodesResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/self/2fa/recovery-codes [post]
+func (h *Handler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req TwoFactorCodeRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", errors.New("claims not found"))
+		return
+	}
+
+	h.auditResource(ctx, claims.UID)
+	codes, err := h.Svc.RegenerateRecoveryCodes(ctx, &claims, req.Code)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+	response := NewSuccessResponse(&RecoveryCodesResponse{RecoveryCodes: codes})
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+type ResetTwoFactorRequest struct {
+	UserID string `json:"userID"`
+}
+
+// ResetTwoFactor godoc
+// @Summary Reset two-factor authentication
+// @Description Remove the authenticator and recovery codes of a user who lost them.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ResetTwoFactorRequest true "User payload"
+// @Success 200 {object} SuccessResponse[EmptyResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/2fa [delete]
+func (h *Handler) ResetTwoFactor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req ResetTwoFactorRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", errors.New("claims not found"))
+		return
+	}
+	err = h.VerifyResourcePolicy(ctx, req.UserID)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	h.auditResource(ctx, req.UserID)
+	err = h.Svc.ResetTwoFactor(ctx, &claims, req.UserID)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+	response := NewSuccessResponse[EmptyResponse](&EmptyResponse{})
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
@@ -0,0 +1,52 @@
+package rest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/manager/rest"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwoFactorLoginRoundTrip(t *testing.T) {
+	svc := domain.NewMockService(t)
+	h, err := rest.NewHandler(rest.Params{Svc: svc})
+	require.NoError(t, err)
+	engine := echo.New()
+	h.SetupRoutes(engine)
+	svc.EXPECT().CreateAuditLog(mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	svc.EXPECT().Login(mock.Anything, "alice", "password", mock.Anything).
+		Return(&domain.LoginResult{TwoFactorToken: "challenge", TwoFactorTokenExpiresAt: time.Now().Add(time.Minute)}, nil).Once()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader(`{"username":"alice","password":"password"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"twoFactorRequired":true`)
+	assert.Contains(t, rec.Body.String(), `"twoFactorToken":"challenge"`)
+	assert.Contains(t, rec.Body.String(), `"token":""`)
+
+	svc.EXPECT().VerifyTwoFactorLogin(mock.Anything, "challenge", "123456", mock.Anything).
+		Return(&domain.TokenPair{AccessToken: "access", RefreshToken: "refresh"}, nil).Once()
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/auth/2fa", strings.NewReader(`{"twoFactorToken":"challenge","code":"123456"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"token":"access"`)
+	assert.NotContains(t, rec.Body.String(), "twoFactorRequired")
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/auth/2fa", strings.NewReader(`{"twoFactorToken":"challenge"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
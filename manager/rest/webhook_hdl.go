@@ -0,0 +1,238 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/Gthulhu/api/manager/domain"
+)
+
+// WebhookSubscriptionSpec holds the configurable fields of a webhook subscription
+type WebhookSubscriptionSpec struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Secret signs the deliveries, a random secret is generated on create when empty and
+	// the current one is kept on update when empty
+	Secret string `json:"secret,omitempty"`
+	// Events is any of strategy.created, strategy.updated, strategy.deleted, intents.sent,
+	// intents.failed, dm.resync_triggered and intents.stale_removed, empty means every event
+	Events   []string `json:"events,omitempty"`
+	Disabled bool     `json:"disabled,omitempty"`
+}
+
+func (spec *WebhookSubscriptionSpec) toDomain() *domain.WebhookSubscription {
+	sub := &domain.WebhookSubscription{
+		Name:     spec.Name,
+		URL:      spec.URL,
+		Secret:   spec.Secret,
+		Disabled: spec.Disabled,
+	}
+	for _, event := range spec.Events {
+		sub.Events = append(sub.Events, domain.EventType(event))
+	}
+	return sub
+}
+
+type CreateWebhookSubscriptionRequest struct {
+	WebhookSubscriptionSpec
+}
+
+type CreateWebhookSubscriptionResponse struct {
+	ID string `json:"id"`
+	// Secret is only returned on create, keep it to verify the X-Gthulhu-Signature header
+	Secret string `json:"secret"`
+}
+
+type UpdateWebhookSubscriptionRequest struct {
+	ID string `json:"id"`
+	WebhookSubscriptionSpec
+}
+
+type DeleteWebhookSubscriptionRequest struct {
+	ID string `json:"id"`
+}
+
+// WebhookSubscription represents a webhook subscription without its secret (for API response)
+type WebhookSubscription struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	URL         string   `json:"url"`
+	Events      []string `json:"events,omitempty"`
+	Disabled    bool     `json:"disabled,omitempty"`
+	CreatedTime int64    `json:"createdTime"`
+	UpdatedTime int64    `json:"updatedTime"`
+}
+
+type ListWebhookSubscriptionsResponse struct {
+	Subscriptions []WebhookSubscription `json:"subscriptions"`
+}
+
+// CreateWebhookSubscription godoc
+// @Summary Create webhook subscription
+// @Description Subscribe a webhook to strategy and intent lifecycle events. Deliveries are signed with HMAC-SHA256 of "<X-Gthulhu-Timestamp>.<body>" in the X-Gthulhu-Signature header.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateWebhookSubscriptionRequest true "Webhook subscription payload"
+// @Success 200 {object} SuccessResponse[CreateWebhookSubscriptionResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/webhook-subscriptions [post]
+func (h *Handler) CreateWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req CreateWebhookSubscriptionRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	sub := req.toDomain()
+	err = h.Svc.CreateWebhookSubscription(ctx, &claims, sub)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+	h.auditResource(ctx, sub.ID.Hex())
+
+	response := NewSuccessResponse(&CreateWebhookSubscriptionResponse{ID: sub.ID.Hex(), Secret: sub.Secret})
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+// UpdateWebhookSubscription godoc
+// @Summary Update webhook subscription
+// @Description Replace the configuration of a webhook subscription, an empty secret keeps the current one.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdateWebhookSubscriptionRequest true "Webhook subscription payload"
+// @Success 200 {object} SuccessResponse[EmptyResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/webhook-subscriptions [put]
+func (h *Handler) UpdateWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req UpdateWebhookSubscriptionRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if req.ID == "" {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Webhook subscription ID is required", nil)
+		return
+	}
+
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	h.auditResource(ctx, req.ID)
+	err = h.Svc.UpdateWebhookSubscription(ctx, &claims, req.ID, req.toDomain())
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	response := NewSuccessResponse[EmptyResponse](&EmptyResponse{})
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+// DeleteWebhookSubscription godoc
+// @Summary Delete webhook subscription
+// @Description Delete a webhook subscription.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body DeleteWebhookSubscriptionRequest true "Webhook subscription payload"
+// @Success 200 {object} SuccessResponse[EmptyResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/webhook-subscriptions [delete]
+func (h *Handler) DeleteWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req DeleteWebhookSubscriptionRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if req.ID == "" {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Webhook subscription ID is required", nil)
+		return
+	}
+
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	h.auditResource(ctx, req.ID)
+	err = h.Svc.DeleteWebhookSubscription(ctx, &claims, req.ID)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	response := NewSuccessResponse[EmptyResponse](&EmptyResponse{})
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+// ListWebhookSubscriptions godoc
+// @Summary List webhook subscriptions
+// @Description Retrieve all webhook subscriptions, secrets are not returned.
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} SuccessResponse[ListWebhookSubscriptionsResponse]
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/webhook-subscriptions [get]
+func (h *Handler) ListWebhookSubscriptions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	opt := &domain.QueryWebhookSubscriptionOptions{}
+	if err := h.Svc.ListWebhookSubscriptions(ctx, opt); err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	resp := ListWebhookSubscriptionsResponse{
+		Subscriptions: make([]WebhookSubscription, len(opt.Result)),
+	}
+	for i, sub := range opt.Result {
+		events := make([]string, len(sub.Events))
+		for j, event := range sub.Events {
+			events[j] = string(event)
+		}
+		resp.Subscriptions[i] = WebhookSubscription{
+			ID:          sub.ID.Hex(),
+			Name:        sub.Name,
+			URL:         sub.URL,
+			Events:      events,
+			Disabled:    sub.Disabled,
+			CreatedTime: sub.CreatedTime,
+			UpdatedTime: sub.UpdatedTime,
+		}
+	}
+	h.JSONResponse(ctx, w, http.StatusOK, NewSuccessResponse(&resp))
+}
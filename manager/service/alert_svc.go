@@ -0,0 +1,384 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/manager/errs"
+	"github.com/Gthulhu/api/pkg/logger"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func (svc *Service) CreateAlertRule(ctx context.Context, operator *domain.Claims, rule *domain.AlertRule) error {
+	operatorID, err := operator.GetBsonObjectUID()
+	if err != nil {
+		return errs.NewHTTPStatusError(http.StatusUnauthorized, "unauthorized", fmt.Errorf("invalid user ID"))
+	}
+	if err := rule.Validate(); err != nil {
+		return errs.NewHTTPStatusError(http.StatusBadRequest, err.Error(), err)
+	}
+	rule.BaseEntity = domain.NewBaseEntity(&operatorID, &operatorID)
+	return svc.Repo.CreateAlertRule(ctx, rule)
+}
+
+func (svc *Service) UpdateAlertRule(ctx context.Context, operator *domain.Claims, ruleID string, rule *domain.AlertRule) error {
+	operatorID, err := operator.GetBsonObjectUID()
+	if err != nil {
+		return errs.NewHTTPStatusError(http.StatusUnauthorized, "unauthorized", fmt.Errorf("invalid user ID"))
+	}
+	ruleObjID, err := bson.ObjectIDFromHex(ruleID)
+	if err != nil {
+		return errs.NewHTTPStatusError(http.StatusBadRequest, "invalid alert rule ID", err)
+	}
+	if err := rule.Validate(); err != nil {
+		return errs.NewHTTPStatusError(http.StatusBadRequest, err.Error(), err)
+	}
+
+	queryOpt := &domain.QueryAlertRuleOptions{IDs: []bson.ObjectID{ruleObjID}}
+	if err := svc.Repo.QueryAlertRules(ctx, queryOpt); err != nil {
+		return err
+	}
+	if len(queryOpt.Result) == 0 {
+		return errs.NewHTTPStatusError(http.StatusNotFound, "alert rule not found", nil)
+	}
+	rule.BaseEntity = queryOpt.Result[0].BaseEntity
+	rule.UpdaterID = operatorID
+	return svc.Repo.UpdateAlertRule(ctx, rule)
+}
+
+// DeleteAlertRule deletes the rule, its firing alerts are resolved by the next evaluation
+func (svc *Service) DeleteAlertRule(ctx context.Context, operator *domain.Claims, ruleID string) error {
+	ruleObjID, err := bson.ObjectIDFromHex(ruleID)
+	if err != nil {
+		return errs.NewHTTPStatusError(http.StatusBadRequest, "invalid alert rule ID", err)
+	}
+	err = svc.Repo.DeleteAlertRule(ctx, ruleObjID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return errs.NewHTTPStatusError(http.StatusNotFound, "alert rule not found", err)
+	}
+	return err
+}
+
+func (svc *Service) ListAlertRules(ctx context.Context, opt *domain.QueryAlertRuleOptions) error {
+	return svc.Repo.QueryAlertRules(ctx, opt)
+}
+
+func (svc *Service) ListAlerts(ctx context.Context, opt *domain.QueryAlertOptions) error {
+	return svc.Repo.QueryAlerts(ctx, opt)
+}
+
+type alertKey struct {
+	ruleID bson.ObjectID
+	nodeID string
+}
+
+const (
+	// alertEvaluationLease is held by the manager replica evaluating the alert rules
+	alertEvaluationLease = "alert-evaluation"
+	alertNotifyWorkers   = 4
+	alertNotifyQueueSize = 256
+)
+
+// alertDelivery is a notification waiting for a webhook worker
+type alertDelivery struct {
+	url          string
+	notification AlertNotification
+}
+
+// alertEvaluator runs the evaluations of this replica. The state needed between evaluations is
+// stored per node, so only the lease holder evaluates and another replica continues where it
+// stopped. Notifications are delivered by a fixed number of workers, queueing blocks when they
+// fall behind.
+type alertEvaluator struct {
+	mu       sync.Mutex
+	holder   string
+	leaseTTL time.Duration
+
+	startWorkers sync.Once
+	deliveries   chan alertDelivery
+	// notifications tracks queued and in-flight webhook deliveries
+	notifications sync.WaitGroup
+}
+
+func newAlertEvaluator(cfg config.AlertingConfig) *alertEvaluator {
+	hostname, _ := os.Hostname()
+	return &alertEvaluator{
+		holder: hostname + "-" + bson.NewObjectID().Hex(),
+		// a lease outliving a couple of missed evaluations avoids flapping between replicas
+		leaseTTL:   3 * cfg.EvaluateInterval(),
+		deliveries: make(chan alertDelivery, alertNotifyQueueSize),
+	}
+}
+
+// observeUserSchedRun records the last run of the user scheduler in state and returns when it was
+// first observed at its current value
+func observeUserSchedRun(state *domain.AlertNodeState, sample *domain.MetricSample, now time.Time) time.Time {
+	if state.UserSchedLastRunSeen == 0 || state.UserSchedLastRunAt != sample.UserSchedLastRunAt {
+		state.UserSchedLastRunAt = sample.UserSchedLastRunAt
+		state.UserSchedLastRunSeen = now.UnixMilli()
+	}
+	return time.UnixMilli(state.UserSchedLastRunSeen)
+}
+
+// alertValue evaluates the rule metric on the node of state, reporting false when it is not known
+// yet, e.g. a rate without a newer sample than the previous evaluation or after a counter reset
+func alertValue(rule *domain.AlertRule, state *domain.AlertNodeState, sample *domain.MetricSample, lastRunSeen, now time.Time) (float64, bool) {
+	if rule.Metric == domain.AlertMetricUserSchedIdleSeconds {
+		return now.Sub(lastRunSeen).Seconds(), true
+	}
+	current, ok := rule.Metric.Value(sample)
+	if !ok || !rule.Rate {
+		return current, ok
+	}
+	prev := state.Previous
+	if prev == nil || !sample.Timestamp.After(prev.Timestamp) {
+		return 0, false
+	}
+	prevValue, _ := rule.Metric.Value(prev)
+	if current < prevValue {
+		return 0, false
+	}
+	return (current - prevValue) / sample.Timestamp.Sub(prev.Timestamp).Seconds(), true
+}
+
+// EvaluateAlertRules evaluates the enabled alert rules against the current metrics of every
+// online decision maker, records alerts that start firing or resolve and notifies the rule webhooks.
+// Only the manager replica holding the evaluation lease evaluates, the others return right away.
+func (svc *Service) EvaluateAlertRules(ctx context.Context) error {
+	if svc.K8SAdapter == nil {
+		return domain.ErrNoClient
+	}
+	evaluator := svc.alertEvaluator
+	if evaluator == nil {
+		return errors.New("alert evaluator is not initialized")
+	}
+	evaluator.mu.Lock()
+	defer evaluator.mu.Unlock()
+
+	leader, err := svc.Repo.AcquireLease(ctx, alertEvaluationLease, evaluator.holder, evaluator.leaseTTL)
+	if err != nil {
+		return fmt.Errorf("acquire alert evaluation lease: %w", err)
+	}
+	if !leader {
+		return nil
+	}
+
+	ruleOpt := &domain.QueryAlertRuleOptions{}
+	if err := svc.Repo.QueryAlertRules(ctx, ruleOpt); err != nil {
+		return fmt.Errorf("query alert rules: %w", err)
+	}
+	allRules := make(map[bson.ObjectID]*domain.AlertRule, len(ruleOpt.Result))
+	rules := make(map[bson.ObjectID]*domain.AlertRule, len(ruleOpt.Result))
+	for _, rule := range ruleOpt.Result {
+		allRules[rule.ID] = rule
+		if !rule.Disabled {
+			rules[rule.ID] = rule
+		}
+	}
+	firingOpt := &domain.QueryAlertOptions{States: []domain.AlertState{domain.AlertStateFiring}}
+	if err := svc.Repo.QueryAlerts(ctx, firingOpt); err != nil {
+		return fmt.Errorf("query firing alerts: %w", err)
+	}
+	firing := make(map[alertKey]*domain.Alert, len(firingOpt.Result))
+	for _, alert := range firingOpt.Result {
+		firing[alertKey{ruleID: alert.RuleID, nodeID: alert.NodeID}] = alert
+	}
+	storedStates, err := svc.Repo.ListAlertNodeStates(ctx)
+	if err != nil {
+		return fmt.Errorf("list alert node states: %w", err)
+	}
+	states := make(map[string]*domain.AlertNodeState, len(storedStates))
+	for _, state := range storedStates {
+		states[state.NodeID] = state
+	}
+
+	dms, err := svc.K8SAdapter.QueryDecisionMakerPods(ctx, &domain.QueryDecisionMakerPodsOptions{
+		DecisionMakerLabel: domain.LabelSelector{
+			Key:   "app",
+			Value: "decisionmaker",
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("query decision maker pods: %w", err)
+	}
+	dmByNode := decisionMakersByNode(dms)
+	online := make([]*domain.DecisionMakerPod, 0, len(dms))
+	for _, dm := range dmByNode {
+		if dm.State == domain.NodeStateOnline {
+			online = append(online, dm)
+		}
+	}
+	samples, _ := svc.fetchMetrics(ctx, online)
+
+	now := time.Now()
+	for i, dm := range online {
+		sample := samples[i]
+		if sample == nil {
+			continue
+		}
+		state := states[dm.NodeID]
+		if state == nil {
+			state = &domain.AlertNodeState{NodeID: dm.NodeID}
+		}
+		if state.Pending == nil {
+			state.Pending = map[string]int64{}
+		}
+		lastRunSeen := observeUserSchedRun(state, sample, now)
+		for _, rule := range rules {
+			if !rule.AppliesTo(dm.NodeID) {
+				continue
+			}
+			value, ok := alertValue(rule, state, sample, lastRunSeen, now)
+			if !ok {
+				continue
+			}
+			key := alertKey{ruleID: rule.ID, nodeID: dm.NodeID}
+			alert := firing[key]
+			if !rule.Breached(value) {
+				delete(state.Pending, rule.ID.Hex())
+				if alert != nil {
+					svc.resolveAlert(ctx, rule, alert, value, now)
+				}
+				continue
+			}
+			sinceMilli, ok := state.Pending[rule.ID.Hex()]
+			if !ok {
+				sinceMilli = now.UnixMilli()
+				state.Pending[rule.ID.Hex()] = sinceMilli
+			}
+			since := time.UnixMilli(sinceMilli)
+			if alert != nil || now.Sub(since) < time.Duration(rule.ForSec)*time.Second {
+				continue
+			}
+			alert = &domain.Alert{
+				RuleID:   rule.ID,
+				RuleName: rule.Name,
+				NodeID:   dm.NodeID,
+				State:    domain.AlertStateFiring,
+				Value:    value,
+				StartsAt: sinceMilli,
+			}
+			if err := svc.Repo.SaveAlert(ctx, alert); err != nil {
+				logger.Logger(ctx).Warn().Err(err).Msgf("failed to save alert of rule %s on node %s", rule.Name, dm.NodeID)
+				continue
+			}
+			logger.Logger(ctx).Info().Msgf("alert %s firing on node %s, value %g", rule.Name, dm.NodeID, value)
+			svc.notifyAlert(rule, alert)
+		}
+		if prev := state.Previous; prev == nil || sample.Timestamp.After(prev.Timestamp) {
+			state.Previous = sample
+		}
+		// rules deleted or disabled since they were breached
+		for ruleID := range state.Pending {
+			if oid, err := bson.ObjectIDFromHex(ruleID); err != nil || rules[oid] == nil {
+				delete(state.Pending, ruleID)
+			}
+		}
+		if err := svc.Repo.SaveAlertNodeState(ctx, state); err != nil {
+			logger.Logger(ctx).Warn().Err(err).Msgf("failed to save alert state of node %s", dm.NodeID)
+		}
+	}
+
+	// rules deleted or disabled and nodes whose decision maker is gone since the alert started firing
+	for key, alert := range firing {
+		_, enabled := rules[key.ruleID]
+		_, present := dmByNode[key.nodeID]
+		if enabled && present {
+			continue
+		}
+		svc.resolveAlert(ctx, allRules[key.ruleID], alert, alert.Value, now)
+	}
+	var vanished []string
+	for nodeID := range states {
+		if _, ok := dmByNode[nodeID]; !ok {
+			vanished = append(vanished, nodeID)
+		}
+	}
+	if err := svc.Repo.DeleteAlertNodeStates(ctx, vanished); err != nil {
+		logger.Logger(ctx).Warn().Err(err).Msg("failed to delete alert state of removed nodes")
+	}
+	return nil
+}
+
+func (svc *Service) resolveAlert(ctx context.Context, rule *domain.AlertRule, alert *domain.Alert, value float64, now time.Time) {
+	alert.State = domain.AlertStateResolved
+	alert.Value = value
+	alert.ResolvedAt = now.UnixMilli()
+	if err := svc.Repo.SaveAlert(ctx, alert); err != nil {
+		logger.Logger(ctx).Warn().Err(err).Msgf("failed to resolve alert %s on node %s", alert.RuleName, alert.NodeID)
+		return
+	}
+	logger.Logger(ctx).Info().Msgf("alert %s resolved on node %s", alert.RuleName, alert.NodeID)
+	if rule != nil {
+		svc.notifyAlert(rule, alert)
+	}
+}
+
+// AlertNotification is the JSON payload posted to the webhooks of an alert rule
+type AlertNotification struct {
+	Status     domain.AlertState      `json:"status"`
+	AlertID    string                 `json:"alert_id"`
+	RuleID     string                 `json:"rule_id"`
+	RuleName   string                 `json:"rule_name"`
+	NodeID     string                 `json:"node_id"`
+	Metric     domain.AlertMetric     `json:"metric"`
+	Rate       bool                   `json:"rate"`
+	Comparator domain.AlertComparator `json:"comparator"`
+	Threshold  float64                `json:"threshold"`
+	Value      float64                `json:"value"`
+	StartsAt   time.Time              `json:"starts_at"`
+	ResolvedAt *time.Time             `json:"resolved_at,omitempty"`
+}
+
+// notifyAlert queues the alert for delivery to the rule webhooks
+func (svc *Service) notifyAlert(rule *domain.AlertRule, alert *domain.Alert) {
+	if svc.webhookSender == nil || len(rule.WebhookURLs) == 0 {
+		return
+	}
+	notification := AlertNotification{
+		Status:     alert.State,
+		AlertID:    alert.ID.Hex(),
+		RuleID:     rule.ID.Hex(),
+		RuleName:   rule.Name,
+		NodeID:     alert.NodeID,
+		Metric:     rule.Metric,
+		Rate:       rule.Rate,
+		Comparator: rule.Comparator,
+		Threshold:  rule.Threshold,
+		Value:      alert.Value,
+		StartsAt:   time.UnixMilli(alert.StartsAt).UTC(),
+	}
+	if alert.ResolvedAt != 0 {
+		resolvedAt := time.UnixMilli(alert.ResolvedAt).UTC()
+		notification.ResolvedAt = &resolvedAt
+	}
+	evaluator := svc.alertEvaluator
+	evaluator.startWorkers.Do(func() {
+		for range alertNotifyWorkers {
+			go svc.deliverAlertNotifications()
+		}
+	})
+	for _, webhookURL := range rule.WebhookURLs {
+		evaluator.notifications.Add(1)
+		evaluator.deliveries <- alertDelivery{url: webhookURL, notification: notification}
+	}
+}
+
+// deliverAlertNotifications posts queued notifications until the process exits
+func (svc *Service) deliverAlertNotifications() {
+	ctx := context.Background()
+	for delivery := range svc.alertEvaluator.deliveries {
+		notification := delivery.notification
+		if err := svc.webhookSender.Send(ctx, delivery.url, notification, nil); err != nil {
+			logger.Logger(ctx).Warn().Err(err).Msgf("failed to notify alert %s %s to webhook", notification.RuleName, notification.Status)
+		}
+		svc.alertEvaluator.notifications.Done()
+	}
+}
@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/pkg/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type alertWebhookRecorder struct {
+	mu            sync.Mutex
+	notifications []AlertNotification
+}
+
+func (r *alertWebhookRecorder) server(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var notification AlertNotification
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&notification))
+		r.mu.Lock()
+		r.notifications = append(r.notifications, notification)
+		r.mu.Unlock()
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newAlertTestService(k8s domain.K8SAdapter, repo domain.Repository, dm domain.DecisionMakerAdapter) *Service {
+	sender := webhook.NewSender(time.Second)
+	sender.InitialBackoff = time.Millisecond
+	return &Service{
+		K8SAdapter:         k8s,
+		Repo:               repo,
+		DMAdapter:          dm,
+		nodeMetricsTracker: newNodeMetricsTracker(),
+		alertEvaluator:     newAlertEvaluator(config.AlertingConfig{}),
+		webhookSender:      sender,
+	}
+}
+
+// expectAlertStateStore grants the evaluation lease and keeps the stored alert node states in the
+// returned map
+func expectAlertStateStore(repo *domain.MockRepository) map[string]*domain.AlertNodeState {
+	states := map[string]*domain.AlertNodeState{}
+	repo.EXPECT().AcquireLease(mock.Anything, alertEvaluationLease, mock.Anything, mock.Anything).Return(true, nil).Maybe()
+	repo.EXPECT().ListAlertNodeStates(mock.Anything).
+		RunAndReturn(func(ctx context.Context) ([]*domain.AlertNodeState, error) {
+			result := make([]*domain.AlertNodeState, 0, len(states))
+			for _, state := range states {
+				result = append(result, state)
+			}
+			return result, nil
+		}).Maybe()
+	repo.EXPECT().SaveAlertNodeState(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, state *domain.AlertNodeState) {
+			states[state.NodeID] = state
+		}).
+		Return(nil).Maybe()
+	repo.EXPECT().DeleteAlertNodeStates(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, nodeIDs []string) {
+			for _, nodeID := range nodeIDs {
+				delete(states, nodeID)
+			}
+		}).
+		Return(nil).Maybe()
+	return states
+}
+
+func TestEvaluateAlertRulesFiresAndResolves(t *testing.T) {
+	mockK8S := domain.NewMockK8SAdapter(t)
+	mockRepo := domain.NewMockRepository(t)
+	mockDM := domain.NewMockDecisionMakerAdapter(t)
+	recorder := &alertWebhookRecorder{}
+	srv := recorder.server(t)
+	expectAlertStateStore(mockRepo)
+
+	dm := &domain.DecisionMakerPod{NodeID: "node-a", Host: "10.0.0.1", Port: 8080, State: domain.NodeStateOnline}
+	rule := &domain.AlertRule{
+		BaseEntity:  domain.BaseEntity{ID: bson.NewObjectID()},
+		Name:        "queue-too-long",
+		Metric:      domain.AlertMetricNrQueued,
+		Comparator:  domain.AlertComparatorGreater,
+		Threshold:   5,
+		WebhookURLs: []string{srv.URL},
+	}
+	now := time.Now()
+	breached := &domain.MetricSample{Timestamp: now, NrQueued: 10}
+	cleared := &domain.MetricSample{Timestamp: now.Add(time.Second), NrQueued: 1}
+
+	mockK8S.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).
+		Return([]*domain.DecisionMakerPod{dm}, nil).Twice()
+	mockRepo.EXPECT().QueryAlertRules(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt *domain.QueryAlertRuleOptions) {
+			opt.Result = []*domain.AlertRule{rule}
+		}).
+		Return(nil).Twice()
+	mockDM.EXPECT().GetMetrics(mock.Anything, dm).Return(breached, nil).Once()
+	mockDM.EXPECT().GetMetrics(mock.Anything, dm).Return(cleared, nil).Once()
+
+	var saved []domain.Alert
+	mockRepo.EXPECT().SaveAlert(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, alert *domain.Alert) {
+			if alert.ID.IsZero() {
+				alert.ID = bson.NewObjectID()
+			}
+			saved = append(saved, *alert)
+		}).
+		Return(nil).Twice()
+
+	// first evaluation: nothing firing yet
+	mockRepo.EXPECT().QueryAlerts(mock.Anything, mock.Anything).Return(nil).Once()
+	svc := newAlertTestService(mockK8S, mockRepo, mockDM)
+	require.NoError(t, svc.EvaluateAlertRules(context.Background()))
+	require.Len(t, saved, 1)
+	assert.Equal(t, domain.AlertStateFiring, saved[0].State)
+	assert.Equal(t, "node-a", saved[0].NodeID)
+	assert.Equal(t, float64(10), saved[0].Value)
+	svc.alertEvaluator.notifications.Wait()
+	require.Len(t, recorder.notifications, 1)
+	assert.Equal(t, domain.AlertStateFiring, recorder.notifications[0].Status)
+
+	// second evaluation: the alert is firing and the queue drained
+	firing := saved[0]
+	mockRepo.EXPECT().QueryAlerts(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt *domain.QueryAlertOptions) {
+			opt.Result = []*domain.Alert{&firing}
+		}).
+		Return(nil).Once()
+	require.NoError(t, svc.EvaluateAlertRules(context.Background()))
+	require.Len(t, saved, 2)
+	assert.Equal(t, domain.AlertStateResolved, saved[1].State)
+	assert.NotZero(t, saved[1].ResolvedAt)
+
+	svc.alertEvaluator.notifications.Wait()
+	require.Len(t, recorder.notifications, 2)
+	assert.Equal(t, domain.AlertStateResolved, recorder.notifications[1].Status)
+	assert.Equal(t, "queue-too-long", recorder.notifications[1].RuleName)
+	assert.NotNil(t, recorder.notifications[1].ResolvedAt)
+}
+
+func TestEvaluateAlertRulesWaitsForDuration(t *testing.T) {
+	mockK8S := domain.NewMockK8SAdapter(t)
+	mockRepo := domain.NewMockRepository(t)
+	mockDM := domain.NewMockDecisionMakerAdapter(t)
+	states := expectAlertStateStore(mockRepo)
+
+	dm := &domain.DecisionMakerPod{NodeID: "node-a", Host: "10.0.0.1", Port: 8080, State: domain.NodeStateOnline}
+	rule := &domain.AlertRule{
+		BaseEntity: domain.BaseEntity{ID: bson.NewObjectID()},
+		Name:       "congested",
+		Metric:     domain.AlertMetricNrSchedCongested,
+		Comparator: domain.AlertComparatorGreaterEqual,
+		Threshold:  1,
+		ForSec:     300,
+	}
+	mockK8S.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).
+		Return([]*domain.DecisionMakerPod{dm}, nil).Twice()
+	mockRepo.EXPECT().QueryAlertRules(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt *domain.QueryAlertRuleOptions) {
+			opt.Result = []*domain.AlertRule{rule}
+		}).
+		Return(nil).Twice()
+	mockRepo.EXPECT().QueryAlerts(mock.Anything, mock.Anything).Return(nil).Twice()
+	mockDM.EXPECT().GetMetrics(mock.Anything, dm).
+		Return(&domain.MetricSample{Timestamp: time.Now(), NrSchedCongested: 3}, nil).Twice()
+	mockRepo.EXPECT().SaveAlert(mock.Anything, mock.Anything).Return(nil).Once()
+
+	svc := newAlertTestService(mockK8S, mockRepo, mockDM)
+	require.NoError(t, svc.EvaluateAlertRules(context.Background()))
+	mockRepo.AssertNotCalled(t, "SaveAlert", mock.Anything, mock.Anything)
+
+	// pretend the condition has been breached for longer than ForSec, e.g. by another replica
+	require.Contains(t, states, "node-a")
+	require.Contains(t, states["node-a"].Pending, rule.ID.Hex())
+	states["node-a"].Pending[rule.ID.Hex()] = time.Now().Add(-301 * time.Second).UnixMilli()
+	require.NoError(t, newAlertTestService(mockK8S, mockRepo, mockDM).EvaluateAlertRules(context.Background()))
+}
+
+func TestEvaluateAlertRulesResolvesDeletedRule(t *testing.T) {
+	mockK8S := domain.NewMockK8SAdapter(t)
+	mockRepo := domain.NewMockRepository(t)
+	expectAlertStateStore(mockRepo)
+
+	firing := &domain.Alert{
+		ID:       bson.NewObjectID(),
+		RuleID:   bson.NewObjectID(),
+		RuleName: "deleted",
+		NodeID:   "node-a",
+		State:    domain.AlertStateFiring,
+		Value:    42,
+	}
+	mockRepo.EXPECT().QueryAlertRules(mock.Anything, mock.Anything).Return(nil).Once()
+	mockRepo.EXPECT().QueryAlerts(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt *domain.QueryAlertOptions) {
+			opt.Result = []*domain.Alert{firing}
+		}).
+		Return(nil).Once()
+	mockK8S.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return(nil, nil).Once()
+	mockRepo.EXPECT().SaveAlert(mock.Anything, firing).Return(nil).Once()
+
+	svc := newAlertTestService(mockK8S, mockRepo, nil)
+	require.NoError(t, svc.EvaluateAlertRules(context.Background()))
+	assert.Equal(t, domain.AlertStateResolved, firing.State)
+	assert.NotZero(t, firing.ResolvedAt)
+}
+
+func TestEvaluateAlertRulesResolvesRemovedNode(t *testing.T) {
+	mockK8S := domain.NewMockK8SAdapter(t)
+	mockRepo := domain.NewMockRepository(t)
+	mockDM := domain.NewMockDecisionMakerAdapter(t)
+	states := expectAlertStateStore(mockRepo)
+	states["node-b"] = &domain.AlertNodeState{NodeID: "node-b", Pending: map[string]int64{}}
+
+	dm := &domain.DecisionMakerPod{NodeID: "node-a", Host: "10.0.0.1", Port: 8080, State: domain.NodeStateOnline}
+	rule := &domain.AlertRule{
+		BaseEntity: domain.BaseEntity{ID: bson.NewObjectID()},
+		Name:       "queue-too-long",
+		Metric:     domain.AlertMetricNrQueued,
+		Comparator: domain.AlertComparatorGreater,
+		Threshold:  5,
+	}
+	firing := &domain.Alert{
+		ID:       bson.NewObjectID(),
+		RuleID:   rule.ID,
+		RuleName: rule.Name,
+		NodeID:   "node-b",
+		State:    domain.AlertStateFiring,
+		Value:    42,
+	}
+	mockRepo.EXPECT().QueryAlertRules(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt *domain.QueryAlertRuleOptions) {
+			opt.Result = []*domain.AlertRule{rule}
+		}).
+		Return(nil).Once()
+	mockRepo.EXPECT().QueryAlerts(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt *domain.QueryAlertOptions) {
+			opt.Result = []*domain.Alert{firing}
+		}).
+		Return(nil).Once()
+	mockK8S.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).
+		Return([]*domain.DecisionMakerPod{dm}, nil).Once()
+	mockDM.EXPECT().GetMetrics(mock.Anything, dm).
+		Return(&domain.MetricSample{Timestamp: time.Now(), NrQueued: 1}, nil).Once()
+	mockRepo.EXPECT().SaveAlert(mock.Anything, firing).Return(nil).Once()
+
+	svc := newAlertTestService(mockK8S, mockRepo, mockDM)
+	require.NoError(t, svc.EvaluateAlertRules(context.Background()))
+	assert.Equal(t, domain.AlertStateResolved, firing.State)
+	assert.Equal(t, float64(42), firing.Value)
+	assert.NotContains(t, states, "node-b")
+	assert.Contains(t, states, "node-a")
+}
+
+func TestEvaluateAlertRulesSkipsWithoutLease(t *testing.T) {
+	mockK8S := domain.NewMockK8SAdapter(t)
+	mockRepo := domain.NewMockRepository(t)
+	mockRepo.EXPECT().AcquireLease(mock.Anything, alertEvaluationLease, mock.Anything, 45*time.Second).Return(false, nil).Once()
+
+	svc := newAlertTestService(mockK8S, mockRepo, nil)
+	require.NoError(t, svc.EvaluateAlertRules(context.Background()))
+}
+
+func TestAlertValueRate(t *testing.T) {
+	rule := &domain.AlertRule{Metric: domain.AlertMetricNrFailedDispatches, Rate: true}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	first := &domain.MetricSample{Timestamp: start, NrFailedDispatches: 100}
+	state := &domain.AlertNodeState{NodeID: "node-a"}
+
+	_, ok := alertValue(rule, state, first, start, start)
+	assert.False(t, ok, "a rate needs a previous sample")
+
+	state.Previous = first
+	value, ok := alertValue(rule, state, &domain.MetricSample{Timestamp: start.Add(10 * time.Second), NrFailedDispatches: 150}, start, start)
+	require.True(t, ok)
+	assert.Equal(t, float64(5), value)
+
+	_, ok = alertValue(rule, state, &domain.MetricSample{Timestamp: start.Add(20 * time.Second), NrFailedDispatches: 10}, start, start)
+	assert.False(t, ok, "a counter reset has no rate")
+
+	idle := &domain.AlertRule{Metric: domain.AlertMetricUserSchedIdleSeconds}
+	value, ok = alertValue(idle, state, first, start, start.Add(45*time.Second))
+	require.True(t, ok)
+	assert.Equal(t, float64(45), value)
+}
+
+func TestObserveUserSchedRun(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	state := &domain.AlertNodeState{NodeID: "node-a"}
+	sample := &domain.MetricSample{UserSchedLastRunAt: 7}
+
+	assert.WithinDuration(t, start, observeUserSchedRun(state, sample, start), 0)
+	assert.WithinDuration(t, start, observeUserSchedRun(state, sample, start.Add(time.Minute)), 0, "the last run did not advance")
+	advanced := &domain.MetricSample{UserSchedLastRunAt: 8}
+	assert.WithinDuration(t, start.Add(2*time.Minute), observeUserSchedRun(state, advanced, start.Add(2*time.Minute)), 0)
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/pkg/auditstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestCreateAuditLogStreamsEvenWhenStoreFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sink, err := auditstream.NewFileSink(path, 0, 1)
+	require.NoError(t, err)
+	streamer, err := auditstream.NewStreamer(auditstream.Options{SpoolDir: t.TempDir(), FlushInterval: time.Millisecond}, sink)
+	require.NoError(t, err)
+
+	mockRepo := domain.NewMockRepository(t)
+	mockRepo.EXPECT().CreateAuditLog(mock.Anything, mock.Anything).Return(nil).Once()
+	mockRepo.EXPECT().CreateAuditLog(mock.Anything, mock.Anything).Return(errors.New("mongo unavailable")).Once()
+	svc := &Service{Repo: mockRepo, auditStreamer: streamer}
+
+	userID := bson.NewObjectID()
+	logs := []*domain.AuditLog{
+		{UserID: userID, Action: "schedule_strategy.create", Timestamp: 1000, Method: "POST", Path: "/api/v1/strategies", Status: 200,
+			Changes: []domain.AuditChange{{Field: "priority", After: "10"}}},
+		{Action: "DELETE /api/v1/strategies", Timestamp: 2000, Status: 500},
+	}
+	require.NoError(t, svc.CreateAuditLog(context.Background(), logs[0]))
+	require.Error(t, svc.CreateAuditLog(context.Background(), logs[1]))
+	require.NoError(t, streamer.Close(context.Background()))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 2)
+	var first, second map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+	assert.Equal(t, userID.Hex(), first["user_id"])
+	assert.Equal(t, "schedule_strategy.create", first["action"])
+	assert.Equal(t, []any{map[string]any{"field": "priority", "after": "10"}}, first["changes"])
+	assert.Equal(t, "DELETE /api/v1/strategies", second["action"])
+	assert.Equal(t, float64(500), second["status"])
+}
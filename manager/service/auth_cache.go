@@ -0,0 +1,370 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	defaultAuthCacheUserTTL = 30 * time.Second
+	defaultAuthCacheRoleTTL = time.Minute
+	// authCacheVersionName is the shared cache version bumped on every user, role or token change
+	authCacheVersionName = "auth"
+	// maxAuthCacheTokens bounds the access tokens remembered as not revoked
+	maxAuthCacheTokens = 100000
+)
+
+var _ prometheus.Collector = (*AuthCache)(nil)
+
+// AuthCache keeps the users and roles VerifyJWTToken and VerifyAPIKey authorize with, and the IDs of
+// access tokens found not revoked, for short TTLs. Cached entries are shared and must not be modified.
+type AuthCache struct {
+	userTTL time.Duration
+	roleTTL time.Duration
+
+	mu    sync.Mutex
+	users map[bson.ObjectID]authCacheEntry[*domain.User]
+	// roles caches missing roles as nil so that unknown role names don't hit the database either
+	roles map[string]authCacheEntry[*domain.Role]
+	// tokens holds the IDs of access tokens that weren't revoked when they were checked
+	tokens map[string]authCacheEntry[struct{}]
+	// generation changes on every invalidation, lookups started before it don't fill the cache
+	generation uint64
+	// version is the shared cache version the entries were loaded under
+	version int64
+
+	hits   map[string]uint64
+	misses map[string]uint64
+
+	hitsDesc    *prometheus.Desc
+	missesDesc  *prometheus.Desc
+	entriesDesc *prometheus.Desc
+}
+
+type authCacheEntry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// NewAuthCache returns the authorization cache, nil when it's disabled
+func NewAuthCache(cfg config.AuthCacheConfig) *AuthCache {
+	if !cfg.Enable {
+		return nil
+	}
+	cache := &AuthCache{
+		userTTL: time.Duration(cfg.UserTTLSec) * time.Second,
+		roleTTL: time.Duration(cfg.RoleTTLSec) * time.Second,
+		users:   map[bson.ObjectID]authCacheEntry[*domain.User]{},
+		roles:   map[string]authCacheEntry[*domain.Role]{},
+		tokens:  map[string]authCacheEntry[struct{}]{},
+		hits:    map[string]uint64{},
+		misses:  map[string]uint64{},
+		hitsDesc: prometheus.NewDesc("auth_cache_hits_total",
+			"Authorization lookups answered from the cache", []string{"cache"}, nil),
+		missesDesc: prometheus.NewDesc("auth_cache_misses_total",
+			"Authorization lookups loaded from the database", []string{"cache"}, nil),
+		entriesDesc: prometheus.NewDesc("auth_cache_entries",
+			"Entries held by the authorization cache", []string{"cache"}, nil),
+	}
+	if cache.userTTL <= 0 {
+		cache.userTTL = defaultAuthCacheUserTTL
+	}
+	if cache.roleTTL <= 0 {
+		cache.roleTTL = defaultAuthCacheRoleTTL
+	}
+	return cache
+}
+
+func (c *AuthCache) currentGeneration() uint64 {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+func (c *AuthCache) getUser(id bson.ObjectID, now time.Time) (*domain.User, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.users[id]
+	if !ok || !now.Before(entry.expiresAt) {
+		c.misses["user"]++
+		return nil, false
+	}
+	c.hits["user"]++
+	return entry.value, true
+}
+
+// setUser caches user unless the cache was invalidated since generation
+func (c *AuthCache) setUser(user *domain.User, generation uint64, now time.Time) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation != generation {
+		return
+	}
+	c.users[user.ID] = authCacheEntry[*domain.User]{value: user, expiresAt: now.Add(c.userTTL)}
+}
+
+// getRoles returns the cached roles of names, missing lists the names to load
+func (c *AuthCache) getRoles(names []string, now time.Time) (roles []*domain.Role, missing []string) {
+	if c == nil {
+		return nil, names
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, name := range names {
+		entry, ok := c.roles[name]
+		if !ok || !now.Before(entry.expiresAt) {
+			c.misses["role"]++
+			missing = append(missing, name)
+			continue
+		}
+		c.hits["role"]++
+		if entry.value != nil {
+			roles = append(roles, entry.value)
+		}
+	}
+	return roles, missing
+}
+
+// setRoles caches the roles loaded for names unless the cache was invalidated since generation
+func (c *AuthCache) setRoles(names []string, roles []*domain.Role, generation uint64, now time.Time) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation != generation {
+		return
+	}
+	expiresAt := now.Add(c.roleTTL)
+	for _, name := range names {
+		c.roles[name] = authCacheEntry[*domain.Role]{expiresAt: expiresAt}
+	}
+	for _, role := range roles {
+		c.roles[role.Name] = authCacheEntry[*domain.Role]{value: role, expiresAt: expiresAt}
+	}
+}
+
+// tokenNotRevoked reports whether the access token with jti was found not revoked within the user TTL
+func (c *AuthCache) tokenNotRevoked(jti string, now time.Time) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.tokens[jti]
+	if !ok || !now.Before(entry.expiresAt) {
+		c.misses["token"]++
+		return false
+	}
+	c.hits["token"]++
+	return true
+}
+
+// setTokenNotRevoked remembers that the access token with jti isn't revoked unless the cache was
+// invalidated since generation
+func (c *AuthCache) setTokenNotRevoked(jti string, generation uint64, now time.Time) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation != generation {
+		return
+	}
+	if len(c.tokens) >= maxAuthCacheTokens {
+		for id, entry := range c.tokens {
+			if !now.Before(entry.expiresAt) {
+				delete(c.tokens, id)
+			}
+		}
+		if len(c.tokens) >= maxAuthCacheTokens {
+			return
+		}
+	}
+	c.tokens[jti] = authCacheEntry[struct{}]{expiresAt: now.Add(c.userTTL)}
+}
+
+// invalidateToken drops the access token with jti
+func (c *AuthCache) invalidateToken(jti string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	delete(c.tokens, jti)
+}
+
+// invalidate drops the users with ids, and every role when roles is set
+func (c *AuthCache) invalidate(ids []bson.ObjectID, roles bool) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	for _, id := range ids {
+		delete(c.users, id)
+	}
+	if roles {
+		clear(c.roles)
+	}
+}
+
+// sync flushes the cache when the shared version differs from the one the entries were loaded under
+func (c *AuthCache) sync(version int64) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if version == c.version {
+		return false
+	}
+	c.version = version
+	c.generation++
+	clear(c.users)
+	clear(c.roles)
+	clear(c.tokens)
+	return true
+}
+
+// advance records a version bumped by this replica, the cache is flushed anyway when another
+// replica bumped it in between
+func (c *AuthCache) advance(version int64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	if version == c.version+1 {
+		c.version = version
+		c.mu.Unlock()
+		return
+	}
+	c.mu.Unlock()
+	c.sync(version)
+}
+
+// Describe implements prometheus.Collector
+func (c *AuthCache) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hitsDesc
+	ch <- c.missesDesc
+	ch <- c.entriesDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *AuthCache) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, cache := range []string{"user", "role", "token"} {
+		ch <- prometheus.MustNewConstMetric(c.hitsDesc, prometheus.CounterValue, float64(c.hits[cache]), cache)
+		ch <- prometheus.MustNewConstMetric(c.missesDesc, prometheus.CounterValue, float64(c.misses[cache]), cache)
+	}
+	ch <- prometheus.MustNewConstMetric(c.entriesDesc, prometheus.GaugeValue, float64(len(c.users)), "user")
+	ch <- prometheus.MustNewConstMetric(c.entriesDesc, prometheus.GaugeValue, float64(len(c.roles)), "role")
+	ch <- prometheus.MustNewConstMetric(c.entriesDesc, prometheus.GaugeValue, float64(len(c.tokens)), "token")
+}
+
+// getAuthUser returns the user an authenticated request is authorized as, from the cache when enabled
+func (svc *Service) getAuthUser(ctx context.Context, id bson.ObjectID) (*domain.User, error) {
+	now := time.Now()
+	if user, ok := svc.authCache.getUser(id, now); ok {
+		return user, nil
+	}
+	generation := svc.authCache.currentGeneration()
+	user, err := svc.getUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	svc.authCache.setUser(user, generation, now)
+	return user, nil
+}
+
+// getAuthRoles is getRolesByNames answered from the cache when enabled
+func (svc *Service) getAuthRoles(ctx context.Context, names []string) ([]*domain.Role, error) {
+	now := time.Now()
+	roles, missing := svc.authCache.getRoles(names, now)
+	if len(missing) == 0 {
+		return roles, nil
+	}
+	generation := svc.authCache.currentGeneration()
+	loaded, err := svc.getRolesByNames(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	svc.authCache.setRoles(missing, loaded, generation, now)
+	return append(roles, loaded...), nil
+}
+
+// isAccessTokenRevoked reports whether the access token with jti was revoked, tokens found not
+// revoked are cached when enabled
+func (svc *Service) isAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	now := time.Now()
+	if svc.authCache.tokenNotRevoked(jti, now) {
+		return false, nil
+	}
+	generation := svc.authCache.currentGeneration()
+	revoked, err := svc.Repo.IsAccessTokenRevoked(ctx, jti)
+	if err != nil {
+		return false, err
+	}
+	if !revoked {
+		svc.authCache.setTokenNotRevoked(jti, generation, now)
+	}
+	return revoked, nil
+}
+
+// invalidateAuthCache drops changed users, and every role when roles is set, from the cache of this
+// replica and bumps the shared version so that the other replicas flush theirs
+func (svc *Service) invalidateAuthCache(ctx context.Context, roles bool, userIDs ...bson.ObjectID) {
+	if svc.authCache == nil {
+		return
+	}
+	svc.authCache.invalidate(userIDs, roles)
+	version, err := svc.Repo.IncrementCacheVersion(ctx, authCacheVersionName)
+	if err != nil {
+		logger.Logger(ctx).Warn().Err(err).Msg("failed to publish the authorization cache invalidation, other replicas catch up after the cache TTL")
+		return
+	}
+	svc.authCache.advance(version)
+}
+
+// updateUser stores user and invalidates its cached copy
+func (svc *Service) updateUser(ctx context.Context, user *domain.User) error {
+	if err := svc.Repo.UpdateUser(ctx, user); err != nil {
+		return err
+	}
+	svc.invalidateAuthCache(ctx, false, user.ID)
+	return nil
+}
+
+func (svc *Service) SyncAuthCache(ctx context.Context) error {
+	if svc.authCache == nil {
+		return nil
+	}
+	version, err := svc.Repo.GetCacheVersion(ctx, authCacheVersionName)
+	if err != nil {
+		return errors.WithMessage(err, "db: get authorization cache version failed")
+	}
+	if svc.authCache.sync(version) {
+		logger.Logger(ctx).Debug().Msgf("authorization cache flushed at version %d", version)
+	}
+	return nil
+}
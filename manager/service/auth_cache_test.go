@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestAuthCache(t *testing.T) {
+	assert.Nil(t, NewAuthCache(config.AuthCacheConfig{}))
+	cache := NewAuthCache(config.AuthCacheConfig{Enable: true, UserTTLSec: 10, RoleTTLSec: 20})
+	now := time.Now()
+	user := &domain.User{BaseEntity: domain.BaseEntity{ID: bson.NewObjectID()}}
+
+	_, ok := cache.getUser(user.ID, now)
+	assert.False(t, ok)
+	cache.setUser(user, cache.currentGeneration(), now)
+	cached, ok := cache.getUser(user.ID, now.Add(9*time.Second))
+	assert.True(t, ok)
+	assert.Same(t, user, cached)
+	_, ok = cache.getUser(user.ID, now.Add(10*time.Second))
+	assert.False(t, ok, "entries expire after the TTL")
+
+	// missing roles are cached too
+	cache.setRoles([]string{"admin", "ghost"}, []*domain.Role{{Name: "admin"}}, cache.currentGeneration(), now)
+	roles, missing := cache.getRoles([]string{"admin", "ghost", "viewer"}, now)
+	assert.Len(t, roles, 1)
+	assert.Equal(t, []string{"viewer"}, missing)
+
+	// lookups started before an invalidation don't fill the cache
+	generation := cache.currentGeneration()
+	cache.invalidate([]bson.ObjectID{user.ID}, true)
+	cache.setUser(user, generation, now)
+	_, ok = cache.getUser(user.ID, now)
+	assert.False(t, ok)
+	_, missing = cache.getRoles([]string{"admin"}, now)
+	assert.Equal(t, []string{"admin"}, missing)
+
+	cache.setUser(user, cache.currentGeneration(), now)
+	cache.advance(1)
+	_, ok = cache.getUser(user.ID, now)
+	assert.True(t, ok, "the version bumped by this replica keeps the cache")
+	assert.False(t, cache.sync(1))
+	cache.advance(3)
+	_, ok = cache.getUser(user.ID, now)
+	assert.False(t, ok, "a version bumped by another replica in between flushes the cache")
+}
+
+func TestVerifyJWTTokenWithAuthCache(t *testing.T) {
+	svc, store := newSessionTestService(t)
+	ctx := context.Background()
+	svc.authCache = NewAuthCache(config.AuthCacheConfig{Enable: true})
+	mockRepo := svc.Repo.(*domain.MockRepository)
+	var version int64
+	mockRepo.EXPECT().IncrementCacheVersion(mock.Anything, authCacheVersionName).
+		RunAndReturn(func(ctx context.Context, name string) (int64, error) {
+			version++
+			return version, nil
+		}).Maybe()
+	mockRepo.EXPECT().GetCacheVersion(mock.Anything, authCacheVersionName).
+		RunAndReturn(func(ctx context.Context, name string) (int64, error) { return version, nil }).Maybe()
+
+	tokens := loginTokens(t, svc)
+	verify := func() error {
+		_, _, err := svc.VerifyJWTToken(ctx, tokens.AccessToken, domain.ScheduleStrategyRead)
+		return err
+	}
+	require.NoError(t, verify())
+	require.NoError(t, verify())
+	// one query each for the login and the first verification
+	mockRepo.AssertNumberOfCalls(t, "QueryUsers", 2)
+	mockRepo.AssertNumberOfCalls(t, "QueryRoles", 2)
+
+	// changes of this replica apply immediately
+	operator := &domain.Claims{UID: bson.NewObjectID().Hex()}
+	require.NoError(t, svc.RevokeUserSessions(ctx, operator, store.user.ID.Hex()))
+	requireHTTPStatus(t, verify(), http.StatusUnauthorized)
+	require.NoError(t, svc.SyncAuthCache(ctx))
+
+	// changes of other replicas apply on the next sync
+	tokens = loginTokens(t, svc)
+	require.NoError(t, verify())
+	calls := len(mockRepo.Calls)
+	require.NoError(t, verify())
+	assert.Len(t, mockRepo.Calls, calls, "the revocation check is cached too")
+	version++
+	require.NoError(t, svc.SyncAuthCache(ctx))
+	require.NoError(t, verify())
+	mockRepo.AssertNumberOfCalls(t, "QueryRoles", 4)
+
+	// a logout revokes the cached access token at once on this replica
+	claims, _, err := svc.VerifyJWTToken(ctx, tokens.AccessToken, "")
+	require.NoError(t, err)
+	require.NoError(t, svc.Logout(ctx, &claims, ""))
+	requireHTTPStatus(t, verify(), http.StatusUnauthorized)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/Gthulhu/api/manager/domain"
@@ -18,6 +19,9 @@ func (svc *Service) CreateNewUser(ctx context.Context, operator *domain.Claims,
 	if err != nil {
 		return errors.WithMessagef(err, "invalid operator ID %s", operator.UID)
 	}
+	if err := svc.checkPassword(nil, password); err != nil {
+		return err
+	}
 	user := &domain.User{
 		UserName:   username,
 		Password:   domain.EncryptedPassword(password),
@@ -31,27 +35,52 @@ func (svc *Service) CreateNewUser(ctx context.Context, operator *domain.Claims,
 	return nil
 }
 
-func (svc *Service) Login(ctx context.Context, username, password string) (string, error) {
+// Login checks the password of a local user, users with two-factor authentication get a
+// TwoFactorToken to complete the login with at VerifyTwoFactorLogin instead of tokens
+func (svc *Service) Login(ctx context.Context, username, password, clientIP string) (*domain.LoginResult, error) {
+	now := time.Now()
+	if svc.loginThrottle.blocked(clientIP, now) {
+		return nil, errs.NewHTTPStatusError(http.StatusTooManyRequests, "too many failed logins, try again later", fmt.Errorf("client %s exceeded the failed login limit", clientIP))
+	}
 	user, err := svc.getUserByUserName(ctx, username)
 	if err != nil {
-		return "", err
+		svc.loginThrottle.recordFailure(clientIP, now)
+		return nil, err
+	}
+	if user.Locked(now) {
+		svc.loginThrottle.recordFailure(clientIP, now)
+		return nil, errLoginLocked(user)
 	}
 	if user.Status == domain.UserStatusInactive {
-		return "", errs.NewHTTPStatusError(http.StatusUnauthorized, "user is inactive", fmt.Errorf("username %s is inactive", username))
+		return nil, errs.NewHTTPStatusError(http.StatusUnauthorized, "user is inactive", fmt.Errorf("username %s is inactive", username))
+	}
+	if user.IsExternal() {
+		return nil, errs.NewHTTPStatusError(http.StatusUnauthorized, "user must log in with single sign-on", fmt.Errorf("username %s is provisioned by %s", username, user.IdentityProvider))
 	}
 
 	ok, err := user.Password.Cmp(password)
 	if err != nil {
-		return "", errors.WithMessagef(err, "compare password for username %s failed", username)
+		return nil, errors.WithMessagef(err, "compare password for username %s failed", username)
 	}
 	if !ok {
-		return "", errs.NewHTTPStatusError(http.StatusUnauthorized, "invalid password", fmt.Errorf("compare password for username %s not match", username))
+		return nil, svc.recordLoginFailure(ctx, user, clientIP, "invalid password", now)
 	}
-	token, err := svc.genJWTToken(ctx, user)
+	if user.TwoFactorEnabled() {
+		return svc.issueTwoFactorLoginToken(user, now)
+	}
+	roles, err := svc.getRolesByNames(ctx, user.Roles)
 	if err != nil {
-		return "", errors.WithMessage(err, "generate JWT token failed")
+		return nil, errors.WithMessagef(err, "get roles of username %s failed", username)
 	}
-	return token, nil
+	err = svc.Repo.RecordLoginSuccess(ctx, user.ID, domain.LoginAttempt{Time: now.UnixMilli(), IP: clientIP, Success: true})
+	if err != nil {
+		return nil, errors.WithMessagef(err, "db: record login of username %s failed", username)
+	}
+	tokens, err := svc.issueTokens(ctx, user, "")
+	if err != nil {
+		return nil, err
+	}
+	return &domain.LoginResult{Tokens: tokens, TwoFactorEnrollmentRequired: twoFactorEnrollmentRequired(user, roles)}, nil
 }
 
 func (svc *Service) ChangePassword(ctx context.Context, userClaims *domain.Claims, oldPassword, newPassword string) error {
@@ -64,6 +93,9 @@ func (svc *Service) ChangePassword(ctx context.Context, userClaims *domain.Claim
 	if err != nil {
 		return err
 	}
+	if user.IsExternal() {
+		return errExternalUserPassword(user)
+	}
 	ok, err := user.Password.Cmp(oldPassword)
 	if err != nil {
 		return errors.WithMessagef(err, "compare password for uid %s failed", uid)
@@ -71,11 +103,14 @@ func (svc *Service) ChangePassword(ctx context.Context, userClaims *domain.Claim
 	if !ok {
 		return errs.NewHTTPStatusError(http.StatusUnauthorized, "invalid password", fmt.Errorf("change password failed, compare password for uid %s not match", uid))
 	}
+	if err := svc.checkPassword(user, newPassword); err != nil {
+		return err
+	}
 	user.Status = domain.UserStatusActive
-	user.Password = domain.EncryptedPassword(newPassword)
+	svc.setPassword(user, newPassword)
 	user.UpdatedTime = time.Now().UnixMilli()
 	user.UpdaterID = uid
-	err = svc.Repo.UpdateUser(ctx, user)
+	err = svc.updateUser(ctx, user)
 	if err != nil {
 		return err
 	}
@@ -109,15 +144,24 @@ func (svc *Service) UpdateUserPermissions(ctx context.Context, operator *domain.
 		}
 		user.Roles = *opt.Roles
 	}
+	deactivated := false
 	if opt.Status != nil {
+		deactivated = *opt.Status == domain.UserStatusInactive && user.Status != domain.UserStatusInactive
 		user.Status = *opt.Status
 	}
+	if deactivated {
+		// tokens issued before the deactivation must stop working right away
+		user.SessionVersion++
+	}
 	user.UpdatedTime = time.Now().UnixMilli()
 	user.UpdaterID = operatorID
-	err = svc.Repo.UpdateUser(ctx, user)
+	err = svc.updateUser(ctx, user)
 	if err != nil {
 		return err
 	}
+	if deactivated {
+		return svc.revokeRefreshTokensOfUser(ctx, uid)
+	}
 	return nil
 }
 
@@ -135,15 +179,26 @@ func (svc *Service) ResetPassword(ctx context.Context, operator *domain.Claims,
 	if err != nil {
 		return err
 	}
-	user.Password = domain.EncryptedPassword(newPassword)
+	if user.IsExternal() {
+		return errExternalUserPassword(user)
+	}
+	if err := svc.checkPassword(user, newPassword); err != nil {
+		return err
+	}
+	svc.setPassword(user, newPassword)
 	user.Status = domain.UserStatusWaitChangePassword
+	user.SessionVersion++
 	user.UpdatedTime = time.Now().UnixMilli()
 	user.UpdaterID = operatorID
-	err = svc.Repo.UpdateUser(ctx, user)
+	err = svc.updateUser(ctx, user)
 	if err != nil {
 		return err
 	}
-	return nil
+	return svc.revokeRefreshTokensOfUser(ctx, uid)
+}
+
+func errExternalUserPassword(user *domain.User) error {
+	return errs.NewHTTPStatusError(http.StatusBadRequest, "password of single sign-on users is managed by their identity provider", fmt.Errorf("user %s is provisioned by %s", user.ID.Hex(), user.IdentityProvider))
 }
 
 func (svc *Service) QueryUsers(ctx context.Context, opt *domain.QueryUserOptions) error {
@@ -186,8 +241,7 @@ func (svc *Service) getUserByID(ctx context.Context, id bson.ObjectID) (*domain.
 	return users[0], nil
 }
 
-func (svc *Service) genJWTToken(ctx context.Context, user *domain.User) (string, error) {
-	tokenTTL := time.Duration(3) * time.Hour
+func (svc *Service) genJWTToken(ctx context.Context, user *domain.User, jti string, issuedAt, expiresAt time.Time) (string, error) {
 	uid := user.ID.Hex()
 
 	roles := []string{}
@@ -197,17 +251,23 @@ func (svc *Service) genJWTToken(ctx context.Context, user *domain.User) (string,
 	claims := domain.Claims{
 		UID:                uid,
 		NeedChangePassword: user.Status == domain.UserStatusWaitChangePassword,
+		SessionVersion:     user.SessionVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(tokenTTL)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			NotBefore: jwt.NewNumericDate(issuedAt),
 			Issuer:    "bss-api-server",
 			Subject:   uid,
+			ID:        jti,
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	return token.SignedString(svc.jwtPrivateKey)
+	return svc.signJWT(claims)
+}
+
+// isLoginOnlyAudience reports whether audience belongs to a token issued midway through a login
+func isLoginOnlyAudience(audience string) bool {
+	return audience == twoFactorLoginAudience || audience == oidcSessionAudience
 }
 
 func (svc *Service) VerifyJWTToken(ctx context.Context, tokenString string, permissionKey domain.PermissionKey) (domain.Claims, domain.RolePolicy, error) {
@@ -215,56 +275,87 @@ func (svc *Service) VerifyJWTToken(ctx context.Context, tokenString string, perm
 		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return svc.jwtPrivateKey.Public(), nil
+		return svc.jwtKeyFunc(token)
 	})
 	if err != nil {
 		return domain.Claims{}, domain.RolePolicy{}, errors.WithMessage(err, "parse JWT token failed")
 	}
 	claims, ok := token.Claims.(*domain.Claims)
-	if !ok || !token.Valid {
+	if !ok || !token.Valid || claims.ServiceAccount {
 		return domain.Claims{}, domain.RolePolicy{}, errors.New("invalid JWT token claims")
 	}
-	if permissionKey == "" {
-		return *claims, domain.RolePolicy{}, nil
+	// the two-factor login and OIDC session tokens are signed with the same keys but only
+	// complete a login, they must never authorize a request
+	if slices.ContainsFunc(claims.Audience, isLoginOnlyAudience) {
+		return domain.Claims{}, domain.RolePolicy{}, errs.NewHTTPStatusError(http.StatusUnauthorized, "invalid token", fmt.Errorf("token audience %v is not accepted for API access", claims.Audience))
 	}
-	if permissionKey != domain.ChangeUserPermission && claims.NeedChangePassword {
-		return domain.Claims{}, domain.RolePolicy{}, errs.NewHTTPStatusError(http.StatusForbidden, "password change required", fmt.Errorf("user %s need to change password", claims.UID))
+	if claims.ID != "" {
+		revoked, err := svc.isAccessTokenRevoked(ctx, claims.ID)
+		if err != nil {
+			return domain.Claims{}, domain.RolePolicy{}, errors.WithMessage(err, "check token revocation failed")
+		}
+		if revoked {
+			return domain.Claims{}, domain.RolePolicy{}, errs.NewHTTPStatusError(http.StatusUnauthorized, "token revoked", fmt.Errorf("token %s of user %s is revoked", claims.ID, claims.UID))
+		}
 	}
 
 	uid, err := claims.GetBsonObjectUID()
 	if err != nil {
 		return domain.Claims{}, domain.RolePolicy{}, errors.WithMessagef(err, "invalid user ID %s", claims.UID)
 	}
-	user, err := svc.getUserByID(ctx, uid)
+	user, err := svc.getAuthUser(ctx, uid)
 	if err != nil {
 		return domain.Claims{}, domain.RolePolicy{}, errors.WithMessagef(err, "get user by ID %s failed", uid.Hex())
 	}
+	if user.Status == domain.UserStatusInactive {
+		return domain.Claims{}, domain.RolePolicy{}, errs.NewHTTPStatusError(http.StatusUnauthorized, "user is inactive", fmt.Errorf("user %s is inactive", claims.UID))
+	}
+	if claims.SessionVersion != user.SessionVersion {
+		return domain.Claims{}, domain.RolePolicy{}, errs.NewHTTPStatusError(http.StatusUnauthorized, "session revoked", fmt.Errorf("sessions of user %s were revoked", claims.UID))
+	}
+	if permissionKey == "" {
+		return *claims, domain.RolePolicy{}, nil
+	}
+	if permissionKey != domain.ChangeUserPermission && claims.NeedChangePassword {
+		return domain.Claims{}, domain.RolePolicy{}, errs.NewHTTPStatusError(http.StatusForbidden, "password change required", fmt.Errorf("user %s need to change password", claims.UID))
+	}
 
-	roles, err := svc.getRolesByNames(ctx, user.Roles)
+	roles, err := svc.getAuthRoles(ctx, user.Roles)
 	if err != nil {
 		return domain.Claims{}, domain.RolePolicy{}, errors.WithMessage(err, "get roles by IDs failed")
 	}
+	if twoFactorEnrollmentRequired(user, roles) {
+		return domain.Claims{}, domain.RolePolicy{}, errs.NewHTTPStatusError(http.StatusForbidden, "two-factor authentication enrollment required", fmt.Errorf("user %s has a role requiring two-factor authentication", claims.UID))
+	}
+	rolePolicy, err := rolesPolicy("user "+claims.UID, roles, permissionKey)
+	if err != nil {
+		return domain.Claims{}, domain.RolePolicy{}, err
+	}
+	return *claims, rolePolicy, nil
+}
+
+// authorizeRoles returns the policy of the first of roleNames granting permissionKey, subject
+// names the principal in errors
+func (svc *Service) authorizeRoles(ctx context.Context, subject string, roleNames []string, permissionKey domain.PermissionKey) (domain.RolePolicy, error) {
+	roles, err := svc.getAuthRoles(ctx, roleNames)
+	if err != nil {
+		return domain.RolePolicy{}, errors.WithMessage(err, "get roles by IDs failed")
+	}
+	return rolesPolicy(subject, roles, permissionKey)
+}
+
+func rolesPolicy(subject string, roles []*domain.Role, permissionKey domain.PermissionKey) (domain.RolePolicy, error) {
 	if len(roles) == 0 {
-		return domain.Claims{}, domain.RolePolicy{}, errs.NewHTTPStatusError(http.StatusForbidden, "permission denied", fmt.Errorf("user %s has no roles assigned", claims.UID))
+		return domain.RolePolicy{}, errs.NewHTTPStatusError(http.StatusForbidden, "permission denied", fmt.Errorf("%s has no roles assigned", subject))
 	}
-	hasPermission := false
-	rolePolicy := domain.RolePolicy{}
 	for _, role := range roles {
 		for _, policy := range role.Policies {
 			if policy.PermissionKey == permissionKey {
-				hasPermission = true
-				rolePolicy = policy
-				break
+				return policy, nil
 			}
 		}
-		if hasPermission {
-			break
-		}
-	}
-	if !hasPermission {
-		return domain.Claims{}, domain.RolePolicy{}, errs.NewHTTPStatusError(http.StatusForbidden, "permission denied", fmt.Errorf("user %s does not have permission %s", claims.UID, permissionKey))
 	}
-	return *claims, rolePolicy, nil
+	return domain.RolePolicy{}, errs.NewHTTPStatusError(http.StatusForbidden, "permission denied", fmt.Errorf("%s does not have permission %s", subject, permissionKey))
 }
 
 func (svc *Service) CreateAdminUserIfNotExists(ctx context.Context, username, password string) error {
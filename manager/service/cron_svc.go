@@ -46,9 +46,10 @@ func (svc *Service) refreshStaleIntents(ctx context.Context) error {
 
 	for _, strategy := range strategyOpt.Result {
 		queryOpt := &domain.QueryPodsOptions{
-			K8SNamespace:   strategy.K8sNamespace,
-			LabelSelectors: strategy.LabelSelectors,
-			CommandRegex:   strategy.CommandRegex,
+			K8SNamespace:        strategy.K8sNamespace,
+			LabelSelectors:      strategy.LabelSelectors,
+			CommandRegex:        strategy.CommandRegex,
+			CommandMatchTargets: strategy.CommandMatchTargets,
 		}
 		currentPods, err := svc.K8SAdapter.QueryPods(ctx, queryOpt)
 		if err != nil {
@@ -73,15 +74,17 @@ func (svc *Service) refreshStaleIntents(ctx context.Context) error {
 			existingIntentPodIDs[intent.PodID] = intent
 		}
 
-		// Delete stale intents (pod no longer exists in K8S)
+		// Delete stale intents (pod no longer exists in K8S, or its containers were
+		// replaced while the intent matches on container names)
 		staleIntentIDs := make([]bson.ObjectID, 0)
 		stalePodIDs := make([]string, 0)
 		staleNodeIDsMap := make(map[string]struct{})
 		for _, intent := range intentOpt.Result {
-			if _, exists := currentPodIDs[intent.PodID]; !exists {
+			if pod, exists := currentPodIDs[intent.PodID]; !exists || intent.ContainersChanged(pod) {
 				staleIntentIDs = append(staleIntentIDs, intent.ID)
 				stalePodIDs = append(stalePodIDs, intent.PodID)
 				staleNodeIDsMap[intent.NodeID] = struct{}{}
+				delete(existingIntentPodIDs, intent.PodID)
 			}
 		}
 		if len(staleIntentIDs) > 0 {
@@ -89,6 +92,9 @@ func (svc *Service) refreshStaleIntents(ctx context.Context) error {
 				logger.Logger(ctx).Warn().Err(err).Msgf("failed to delete stale intents for strategy %s", strategy.ID.Hex())
 			} else {
 				logger.Logger(ctx).Info().Msgf("deleted %d stale intents for strategy %s (stale pods: %v)", len(staleIntentIDs), strategy.ID.Hex(), stalePodIDs)
+				svc.emitEvent(ctx, domain.EventStaleIntentsRemoved, "", StaleIntentsEventData{
+					StrategyID: strategy.ID.Hex(), PodIDs: stalePodIDs, IntentCount: len(staleIntentIDs),
+				})
 			}
 
 			// Notify decision makers to remove stale pod intents from their in-memory cache
@@ -173,6 +179,9 @@ func (svc *Service) resyncIntentsToDMs(ctx context.Context) error {
 		logger.Logger(ctx).Warn().Msgf("intent merkle mismatch for dm %s: expected=%s actual=%s, re-sending intents", dm, expectedRoot, rootHash)
 
 		nodeIntents := intentsPerNode[dm.NodeID]
+		svc.emitEvent(ctx, domain.EventDMResyncTriggered, "", ResyncEventData{
+			NodeID: dm.NodeID, ExpectedRoot: expectedRoot, ActualRoot: rootHash, IntentCount: len(nodeIntents),
+		})
 		if len(nodeIntents) == 0 {
 			// No intents remain for this node, but DM still has stale data → tell it to clear everything
 			deleteReq := &domain.DeleteIntentsRequest{All: true}
@@ -186,6 +195,9 @@ func (svc *Service) resyncIntentsToDMs(ctx context.Context) error {
 		err = svc.DMAdapter.SendSchedulingIntent(ctx, dm, nodeIntents)
 		if err != nil {
 			logger.Logger(ctx).Warn().Err(err).Msgf("failed to re-send intents to dm %s", dm)
+			svc.emitEvent(ctx, domain.EventIntentsFailed, "", IntentsEventData{
+				NodeID: dm.NodeID, IntentCount: len(nodeIntents), Error: err.Error(),
+			})
 			continue
 		}
 		err = svc.Repo.BatchUpdateIntentsState(ctx, intentIDsPerNode[dm.NodeID], domain.IntentStateSent)
@@ -193,6 +205,7 @@ func (svc *Service) resyncIntentsToDMs(ctx context.Context) error {
 			logger.Logger(ctx).Warn().Err(err).Msgf("failed to update intent states for dm %s", dm)
 		}
 		logger.Logger(ctx).Info().Msgf("re-sent %d intents to dm %s", len(nodeIntents), dm)
+		svc.emitEvent(ctx, domain.EventIntentsSent, "", IntentsEventData{NodeID: dm.NodeID, IntentCount: len(nodeIntents)})
 	}
 	return nil
 }
@@ -301,6 +314,31 @@ func hashScheduleIntent(intent *domain.ScheduleIntent) string {
 		"executionTime=" + strconv.FormatInt(intent.ExecutionTime, 10),
 		"podLabels=" + strings.Join(labels, ","),
 	}, "|")
+	// Match options are only part of the hash when set, so intents created before
+	// they existed keep their hash and do not trigger a resync.
+	if intent.ThreadRegex != "" {
+		serialized += "|threadRegex=" + intent.ThreadRegex
+	}
+	// Intents cached by decision makers before the strategy ID was sent hash differently
+	// and are resent once, which gives the decision maker the ID to label metrics with.
+	if !intent.StrategyID.IsZero() {
+		serialized += "|strategyID=" + intent.StrategyID.Hex()
+	}
+	if len(intent.CommandMatchTargets) > 0 {
+		targets := make([]string, 0, len(intent.CommandMatchTargets))
+		for _, target := range intent.CommandMatchTargets {
+			targets = append(targets, string(target))
+		}
+		serialized += "|commandMatchTargets=" + strings.Join(targets, ",")
+	}
+	if len(intent.ContainerNames) > 0 {
+		containers := make([]string, 0, len(intent.ContainerNames))
+		for id, name := range intent.ContainerNames {
+			containers = append(containers, id+"="+name)
+		}
+		sort.Strings(containers)
+		serialized += "|containerNames=" + strings.Join(containers, ",")
+	}
 	return util.HashStringSHA256Hex(serialized)
 }
 
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/manager/errs"
+	"github.com/Gthulhu/api/pkg/logger"
+	"github.com/Gthulhu/api/pkg/util"
+	"github.com/Gthulhu/api/pkg/webhook"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	// EventTypeHeader carries the event type of a delivery
+	EventTypeHeader = "X-Gthulhu-Event"
+	// EventIDHeader carries the event ID, identical across retries and subscriptions of the same event
+	EventIDHeader = "X-Gthulhu-Event-ID"
+
+	webhookSecretBytes = 32
+
+	eventDeliveryWorkers   = 4
+	eventDeliveryQueueSize = 256
+)
+
+func (svc *Service) CreateWebhookSubscription(ctx context.Context, operator *domain.Claims, sub *domain.WebhookSubscription) error {
+	operatorID, err := operator.GetBsonObjectUID()
+	if err != nil {
+		return errs.NewHTTPStatusError(http.StatusUnauthorized, "unauthorized", fmt.Errorf("invalid user ID"))
+	}
+	if err := sub.Validate(); err != nil {
+		return errs.NewHTTPStatusError(http.StatusBadRequest, err.Error(), err)
+	}
+	if sub.Secret == "" {
+		sub.Secret, err = util.RandomHex(webhookSecretBytes)
+		if err != nil {
+			return fmt.Errorf("generate webhook secret: %w", err)
+		}
+	}
+	sub.BaseEntity = domain.NewBaseEntity(&operatorID, &operatorID)
+	return svc.Repo.CreateWebhookSubscription(ctx, sub)
+}
+
+// UpdateWebhookSubscription replaces the subscription, an empty secret keeps the current one
+func (svc *Service) UpdateWebhookSubscription(ctx context.Context, operator *domain.Claims, subID string, sub *domain.WebhookSubscription) error {
+	operatorID, err := operator.GetBsonObjectUID()
+	if err != nil {
+		return errs.NewHTTPStatusError(http.StatusUnauthorized, "unauthorized", fmt.Errorf("invalid user ID"))
+	}
+	subObjID, err := bson.ObjectIDFromHex(subID)
+	if err != nil {
+		return errs.NewHTTPStatusError(http.StatusBadRequest, "invalid webhook subscription ID", err)
+	}
+	if err := sub.Validate(); err != nil {
+		return errs.NewHTTPStatusError(http.StatusBadRequest, err.Error(), err)
+	}
+
+	queryOpt := &domain.QueryWebhookSubscriptionOptions{IDs: []bson.ObjectID{subObjID}}
+	if err := svc.Repo.QueryWebhookSubscriptions(ctx, queryOpt); err != nil {
+		return err
+	}
+	if len(queryOpt.Result) == 0 {
+		return errs.NewHTTPStatusError(http.StatusNotFound, "webhook subscription not found", nil)
+	}
+	current := queryOpt.Result[0]
+	if sub.Secret == "" {
+		sub.Secret = current.Secret
+	}
+	sub.BaseEntity = current.BaseEntity
+	sub.UpdaterID = operatorID
+	return svc.Repo.UpdateWebhookSubscription(ctx, sub)
+}
+
+func (svc *Service) DeleteWebhookSubscription(ctx context.Context, operator *domain.Claims, subID string) error {
+	subObjID, err := bson.ObjectIDFromHex(subID)
+	if err != nil {
+		return errs.NewHTTPStatusError(http.StatusBadRequest, "invalid webhook subscription ID", err)
+	}
+	err = svc.Repo.DeleteWebhookSubscription(ctx, subObjID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return errs.NewHTTPStatusError(http.StatusNotFound, "webhook subscription not found", err)
+	}
+	return err
+}
+
+func (svc *Service) ListWebhookSubscriptions(ctx context.Context, opt *domain.QueryWebhookSubscriptionOptions) error {
+	return svc.Repo.QueryWebhookSubscriptions(ctx, opt)
+}
+
+// queuedEvent is an event waiting for a delivery worker
+type queuedEvent struct {
+	subscription *domain.WebhookSubscription
+	event        Event
+	headers      map[string]string
+}
+
+// eventDispatcher delivers lifecycle events to the matching webhook subscriptions with a fixed
+// number of workers. Events are emitted by API requests, so a full queue drops the delivery
+// rather than blocking the request.
+type eventDispatcher struct {
+	sender *webhook.Sender
+
+	startWorkers sync.Once
+	queue        chan queuedEvent
+	// deliveries tracks queued and in-flight webhook deliveries
+	deliveries sync.WaitGroup
+	// dropped counts the deliveries dropped because the queue was full
+	dropped atomic.Uint64
+}
+
+// newEventDispatcher returns nil when event webhooks are disabled
+func newEventDispatcher(cfg config.EventWebhookConfig) *eventDispatcher {
+	if !cfg.Enable {
+		return nil
+	}
+	sender := webhook.NewSender(time.Duration(cfg.TimeoutSec) * time.Second)
+	if cfg.MaxAttempts > 0 {
+		sender.MaxAttempts = cfg.MaxAttempts
+	}
+	return &eventDispatcher{sender: sender, queue: make(chan queuedEvent, eventDeliveryQueueSize)}
+}
+
+// enqueue queues the delivery, reporting false when the queue is full
+func (d *eventDispatcher) enqueue(delivery queuedEvent) bool {
+	d.startWorkers.Do(func() {
+		for range eventDeliveryWorkers {
+			go d.deliver()
+		}
+	})
+	d.deliveries.Add(1)
+	select {
+	case d.queue <- delivery:
+		return true
+	default:
+		d.deliveries.Done()
+		d.dropped.Add(1)
+		return false
+	}
+}
+
+// deliver posts queued events until the process exits
+func (d *eventDispatcher) deliver() {
+	ctx := context.Background()
+	for delivery := range d.queue {
+		event := delivery.event
+		if err := d.sender.SendSigned(ctx, delivery.subscription.URL, event, delivery.subscription.Secret, delivery.headers); err != nil {
+			logger.Logger(ctx).Warn().Err(err).Msgf("failed to deliver event %s %s to webhook subscription %s", event.Type, event.ID, delivery.subscription.Name)
+		}
+		d.deliveries.Done()
+	}
+}
+
+// Event is the JSON payload posted to webhook subscriptions
+type Event struct {
+	ID         string           `json:"id"`
+	Type       domain.EventType `json:"type"`
+	OccurredAt time.Time        `json:"occurred_at"`
+	// ActorID is the user that caused the event, empty for events of the reconciler
+	ActorID string `json:"actor_id,omitempty"`
+	Data    any    `json:"data"`
+}
+
+// StrategyEventData is the data of strategy.created, strategy.updated and strategy.deleted
+type StrategyEventData struct {
+	StrategyID        string   `json:"strategy_id"`
+	StrategyNamespace string   `json:"strategy_namespace,omitempty"`
+	K8sNamespaces     []string `json:"k8s_namespaces,omitempty"`
+	LabelSelectors    []string `json:"label_selectors,omitempty"`
+	CommandRegex      string   `json:"command_regex,omitempty"`
+	Priority          int      `json:"priority"`
+	ExecutionTime     int64    `json:"execution_time"`
+	IntentCount       int      `json:"intent_count"`
+}
+
+func newStrategyEventData(strategy *domain.ScheduleStrategy, intentCount int) StrategyEventData {
+	data := StrategyEventData{
+		StrategyID:        strategy.ID.Hex(),
+		StrategyNamespace: strategy.StrategyNamespace,
+		K8sNamespaces:     strategy.K8sNamespace,
+		CommandRegex:      strategy.CommandRegex,
+		Priority:          strategy.Priority,
+		ExecutionTime:     strategy.ExecutionTime,
+		IntentCount:       intentCount,
+	}
+	for _, selector := range strategy.LabelSelectors {
+		data.LabelSelectors = append(data.LabelSelectors, selector.Key+"="+selector.Value)
+	}
+	return data
+}
+
+// IntentsEventData is the data of intents.sent and intents.failed
+type IntentsEventData struct {
+	// StrategyID is empty when the reconciler re-sent every intent of the node
+	StrategyID  string `json:"strategy_id,omitempty"`
+	NodeID      string `json:"node_id"`
+	IntentCount int    `json:"intent_count"`
+	Error       string `json:"error,omitempty"`
+}
+
+// ResyncEventData is the data of dm.resync_triggered
+type ResyncEventData struct {
+	NodeID       string `json:"node_id"`
+	ExpectedRoot string `json:"expected_root"`
+	ActualRoot   string `json:"actual_root"`
+	IntentCount  int    `json:"intent_count"`
+}
+
+// StaleIntentsEventData is the data of intents.stale_removed
+type StaleIntentsEventData struct {
+	StrategyID  string   `json:"strategy_id"`
+	PodIDs      []string `json:"pod_ids"`
+	IntentCount int      `json:"intent_count"`
+}
+
+func actorID(operator *domain.Claims) string {
+	if operator == nil {
+		return ""
+	}
+	return operator.UID
+}
+
+// emitEvent queues the event for every enabled subscription filtering for its type, failures and
+// dropped deliveries are logged and never affect the operation that caused the event
+func (svc *Service) emitEvent(ctx context.Context, eventType domain.EventType, actor string, data any) {
+	dispatcher := svc.eventDispatcher
+	if dispatcher == nil || svc.Repo == nil {
+		return
+	}
+	queryOpt := &domain.QueryWebhookSubscriptionOptions{}
+	if err := svc.Repo.QueryWebhookSubscriptions(ctx, queryOpt); err != nil {
+		logger.Logger(ctx).Warn().Err(err).Msgf("failed to query webhook subscriptions for event %s", eventType)
+		return
+	}
+	event := Event{
+		ID:         bson.NewObjectID().Hex(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		ActorID:    actor,
+		Data:       data,
+	}
+	headers := map[string]string{
+		EventTypeHeader: string(eventType),
+		EventIDHeader:   event.ID,
+	}
+	for _, sub := range queryOpt.Result {
+		if !sub.Matches(eventType) {
+			continue
+		}
+		if !dispatcher.enqueue(queuedEvent{subscription: sub, event: event, headers: headers}) {
+			logger.Logger(ctx).Warn().Msgf("event delivery queue is full, dropped event %s %s to webhook subscription %s (%d dropped in total)",
+				eventType, event.ID, sub.Name, dispatcher.dropped.Load())
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/pkg/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type eventDelivery struct {
+	subscription string
+	event        Event
+	verified     bool
+}
+
+func newEventTestServer(t *testing.T, secrets map[string]string) (*httptest.Server, func() []eventDelivery) {
+	var mu sync.Mutex
+	var deliveries []eventDelivery
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Path[1:]
+		body, _ := io.ReadAll(r.Body)
+		var event Event
+		assert.NoError(t, json.Unmarshal(body, &event))
+		assert.Equal(t, string(event.Type), r.Header.Get(EventTypeHeader))
+		timestamp, _ := strconv.ParseInt(r.Header.Get(webhook.TimestampHeader), 10, 64)
+		mu.Lock()
+		deliveries = append(deliveries, eventDelivery{
+			subscription: name,
+			event:        event,
+			verified:     webhook.Verify(secrets[name], timestamp, body, r.Header.Get(webhook.SignatureHeader)),
+		})
+		mu.Unlock()
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []eventDelivery {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]eventDelivery(nil), deliveries...)
+	}
+}
+
+func TestEmitEventFiltersAndSignsDeliveries(t *testing.T) {
+	secrets := map[string]string{"all": "secret-all", "strategies": "secret-strategies", "disabled": "secret-disabled"}
+	srv, deliveries := newEventTestServer(t, secrets)
+	mockRepo := domain.NewMockRepository(t)
+	mockRepo.EXPECT().QueryWebhookSubscriptions(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt *domain.QueryWebhookSubscriptionOptions) {
+			opt.Result = []*domain.WebhookSubscription{
+				{Name: "all", URL: srv.URL + "/all", Secret: secrets["all"]},
+				{Name: "strategies", URL: srv.URL + "/strategies", Secret: secrets["strategies"], Events: []domain.EventType{domain.EventStrategyCreated}},
+				{Name: "disabled", URL: srv.URL + "/disabled", Secret: secrets["disabled"], Disabled: true},
+			}
+		}).
+		Return(nil).Twice()
+
+	svc := &Service{Repo: mockRepo, eventDispatcher: newEventDispatcher(config.EventWebhookConfig{Enable: true, TimeoutSec: 1})}
+	svc.emitEvent(context.Background(), domain.EventStrategyCreated, "user-1", StrategyEventData{StrategyID: "s1"})
+	svc.emitEvent(context.Background(), domain.EventDMResyncTriggered, "", ResyncEventData{NodeID: "node-a"})
+	svc.eventDispatcher.deliveries.Wait()
+
+	received := map[string][]domain.EventType{}
+	for _, delivery := range deliveries() {
+		assert.True(t, delivery.verified, "delivery to %s is not signed with its secret", delivery.subscription)
+		received[delivery.subscription] = append(received[delivery.subscription], delivery.event.Type)
+		if delivery.event.Type == domain.EventStrategyCreated {
+			assert.Equal(t, "user-1", delivery.event.ActorID)
+		}
+	}
+	assert.ElementsMatch(t, []domain.EventType{domain.EventStrategyCreated, domain.EventDMResyncTriggered}, received["all"])
+	assert.Equal(t, []domain.EventType{domain.EventStrategyCreated}, received["strategies"])
+	assert.Empty(t, received["disabled"])
+}
+
+func TestEmitEventDisabled(t *testing.T) {
+	mockRepo := domain.NewMockRepository(t)
+	svc := &Service{Repo: mockRepo, eventDispatcher: newEventDispatcher(config.EventWebhookConfig{})}
+	svc.emitEvent(context.Background(), domain.EventStrategyDeleted, "", StrategyEventData{})
+	mockRepo.AssertNotCalled(t, "QueryWebhookSubscriptions", mock.Anything, mock.Anything)
+}
+
+func TestEventDispatcherDropsOverflow(t *testing.T) {
+	dispatcher := &eventDispatcher{queue: make(chan queuedEvent, 2)}
+	// no workers drain the queue
+	dispatcher.startWorkers.Do(func() {})
+	for i := range 2 {
+		assert.True(t, dispatcher.enqueue(queuedEvent{event: Event{ID: strconv.Itoa(i)}}))
+	}
+	assert.False(t, dispatcher.enqueue(queuedEvent{event: Event{ID: "2"}}), "a full queue must not block the caller")
+	assert.Equal(t, uint64(1), dispatcher.dropped.Load())
+	assert.Len(t, dispatcher.queue, 2)
+}
+
+func TestWebhookSubscriptionSecrets(t *testing.T) {
+	mockRepo := domain.NewMockRepository(t)
+	svc := &Service{Repo: mockRepo}
+	operator := &domain.Claims{UID: bson.NewObjectID().Hex()}
+
+	mockRepo.EXPECT().CreateWebhookSubscription(mock.Anything, mock.Anything).Return(nil).Once()
+	sub := &domain.WebhookSubscription{Name: "chat", URL: "https://chat.example.com/hook"}
+	require.NoError(t, svc.CreateWebhookSubscription(context.Background(), operator, sub))
+	assert.Len(t, sub.Secret, 2*webhookSecretBytes, "a secret is generated when none is given")
+
+	existing := &domain.WebhookSubscription{
+		BaseEntity: domain.BaseEntity{ID: bson.NewObjectID(), CreatedTime: time.Now().UnixMilli()},
+		Secret:     "current",
+	}
+	mockRepo.EXPECT().QueryWebhookSubscriptions(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt *domain.QueryWebhookSubscriptionOptions) {
+			opt.Result = []*domain.WebhookSubscription{existing}
+		}).
+		Return(nil).Once()
+	mockRepo.EXPECT().UpdateWebhookSubscription(mock.Anything, mock.Anything).Return(nil).Once()
+	update := &domain.WebhookSubscription{Name: "chat", URL: "https://chat.example.com/hook", Events: []domain.EventType{domain.EventStrategyUpdated}}
+	require.NoError(t, svc.UpdateWebhookSubscription(context.Background(), operator, existing.ID.Hex(), update))
+	assert.Equal(t, "current", update.Secret)
+	assert.Equal(t, existing.ID, update.ID)
+
+	err := svc.CreateWebhookSubscription(context.Background(), operator, &domain.WebhookSubscription{
+		Name: "bad", URL: "https://chat.example.com/hook", Events: []domain.EventType{"strategy.renamed"},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/manager/errs"
+	"github.com/Gthulhu/api/pkg/logger"
+)
+
+// ExplainPod reports why a pod is or is not scheduled: how every strategy's selectors
+// evaluate against it, which intents exist for it and how its decision maker resolved them.
+// Failures to reach the decision maker are reported in the result instead of failing the request.
+func (svc *Service) ExplainPod(ctx context.Context, namespace, name string) (*domain.PodExplanation, error) {
+	if svc.K8SAdapter == nil {
+		return nil, domain.ErrNoClient
+	}
+
+	pod, err := svc.K8SAdapter.GetPod(ctx, namespace, name)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, errs.NewHTTPStatusError(http.StatusNotFound, fmt.Sprintf("pod %s/%s not found", namespace, name), err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get pod: %w", err)
+	}
+
+	strategyOpt := &domain.QueryStrategyOptions{}
+	if err := svc.Repo.QueryStrategies(ctx, strategyOpt); err != nil {
+		return nil, fmt.Errorf("query strategies: %w", err)
+	}
+	intentOpt := &domain.QueryIntentOptions{
+		PodIDs: []string{pod.PodID},
+	}
+	if err := svc.Repo.QueryIntents(ctx, intentOpt); err != nil {
+		return nil, fmt.Errorf("query intents: %w", err)
+	}
+
+	result := &domain.PodExplanation{
+		Pod:        pod,
+		Strategies: make([]domain.StrategyMatchExplanation, 0, len(strategyOpt.Result)),
+		Intents:    intentOpt.Result,
+		DecisionMaker: domain.DecisionMakerExplanation{
+			NodeID: pod.NodeID,
+		},
+	}
+	for _, strategy := range strategyOpt.Result {
+		result.Strategies = append(result.Strategies, strategy.ExplainMatch(pod))
+	}
+
+	dmQueryOpt := &domain.QueryDecisionMakerPodsOptions{
+		DecisionMakerLabel: domain.LabelSelector{
+			Key:   "app",
+			Value: "decisionmaker",
+		},
+		NodeIDs: []string{pod.NodeID},
+	}
+	dms, err := svc.K8SAdapter.QueryDecisionMakerPods(ctx, dmQueryOpt)
+	if err != nil {
+		return nil, fmt.Errorf("query decision maker pods: %w", err)
+	}
+	if len(dms) == 0 {
+		result.DecisionMaker.Error = fmt.Sprintf("no decision maker pod found on node %s", pod.NodeID)
+		return result, nil
+	}
+
+	dm := dms[0]
+	result.DecisionMaker.Host = dm.Host
+	result.DecisionMaker.State = dm.State
+	if dm.State != domain.NodeStateOnline {
+		result.DecisionMaker.Error = fmt.Sprintf("decision maker on node %s is not online", pod.NodeID)
+		return result, nil
+	}
+
+	dmResult, err := svc.DMAdapter.ExplainPod(ctx, dm, pod.PodID)
+	if err != nil {
+		logger.Logger(ctx).Warn().Err(err).Msgf("failed to explain pod %s/%s on decision maker %s", namespace, name, dm.Host)
+		result.DecisionMaker.Error = err.Error()
+		return result, nil
+	}
+	result.DecisionMaker.Result = dmResult
+	return result, nil
+}
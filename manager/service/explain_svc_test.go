@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/manager/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestExplainPod(t *testing.T) {
+	ctx := context.Background()
+	mockK8S := domain.NewMockK8SAdapter(t)
+	mockRepo := domain.NewMockRepository(t)
+	mockDM := domain.NewMockDecisionMakerAdapter(t)
+
+	pod := &domain.Pod{
+		Name:         "web-0",
+		K8SNamespace: "default",
+		Labels:       map[string]string{"app": "web", "tier": "frontend"},
+		PodID:        "pod-uid-1",
+		NodeID:       "node-a",
+		Containers: []domain.Container{
+			{Name: "nginx", Command: []string{"/usr/sbin/nginx", "-g", "daemon off;"}},
+		},
+	}
+	matching := &domain.ScheduleStrategy{
+		BaseEntity:     domain.BaseEntity{ID: bson.NewObjectID()},
+		K8sNamespace:   []string{"default"},
+		LabelSelectors: []domain.LabelSelector{{Key: "app", Value: "web"}, {Key: "tier"}},
+		CommandRegex:   "nginx",
+	}
+	wrongNamespace := &domain.ScheduleStrategy{
+		BaseEntity:   domain.BaseEntity{ID: bson.NewObjectID()},
+		K8sNamespace: []string{"kube-system"},
+	}
+	wrongLabels := &domain.ScheduleStrategy{
+		BaseEntity:     domain.BaseEntity{ID: bson.NewObjectID()},
+		LabelSelectors: []domain.LabelSelector{{Key: "app", Value: "db"}, {Key: "team"}},
+	}
+	wrongRegex := &domain.ScheduleStrategy{
+		BaseEntity:          domain.BaseEntity{ID: bson.NewObjectID()},
+		CommandRegex:        "^nginx$",
+		CommandMatchTargets: []domain.CommandMatchTarget{domain.CommandMatchExe},
+	}
+	intent := &domain.ScheduleIntent{
+		BaseEntity: domain.BaseEntity{ID: bson.NewObjectID()},
+		StrategyID: matching.ID,
+		PodID:      pod.PodID,
+		NodeID:     pod.NodeID,
+		State:      domain.IntentStateSent,
+	}
+	dm := &domain.DecisionMakerPod{NodeID: "node-a", Host: "10.0.0.1", Port: 8080, State: domain.NodeStateOnline}
+	dmResult := &domain.DecisionMakerPodExplanation{
+		PodFound:    true,
+		ProcessPIDs: []int{100, 101},
+		Intents: []domain.DecisionMakerIntentExplanation{{
+			CommandRegex: "nginx",
+			Priority:     10,
+			Processes: []domain.ProcessExplanation{
+				{PID: 100, Command: "pause", Outcome: "skippedPause"},
+				{PID: 101, Command: "nginx", Outcome: "matched"},
+			},
+		}},
+	}
+
+	mockK8S.EXPECT().
+		GetPod(mock.Anything, "default", "web-0").
+		Return(pod, nil).
+		Once()
+	mockRepo.EXPECT().
+		QueryStrategies(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, opt *domain.QueryStrategyOptions) {
+			opt.Result = []*domain.ScheduleStrategy{matching, wrongNamespace, wrongLabels, wrongRegex}
+		}).
+		Return(nil).
+		Once()
+	mockRepo.EXPECT().
+		QueryIntents(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, opt *domain.QueryIntentOptions) {
+			assert.Equal(t, []string{pod.PodID}, opt.PodIDs)
+			opt.Result = []*domain.ScheduleIntent{intent}
+		}).
+		Return(nil).
+		Once()
+	mockK8S.EXPECT().
+		QueryDecisionMakerPods(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, opt *domain.QueryDecisionMakerPodsOptions) {
+			assert.Equal(t, []string{"node-a"}, opt.NodeIDs)
+		}).
+		Return([]*domain.DecisionMakerPod{dm}, nil).
+		Once()
+	mockDM.EXPECT().
+		ExplainPod(mock.Anything, dm, pod.PodID).
+		Return(dmResult, nil).
+		Once()
+
+	svc := &Service{
+		K8SAdapter: mockK8S,
+		Repo:       mockRepo,
+		DMAdapter:  mockDM,
+	}
+	result, err := svc.ExplainPod(ctx, "default", "web-0")
+	require.NoError(t, err)
+	assert.Same(t, pod, result.Pod)
+	assert.Equal(t, []*domain.ScheduleIntent{intent}, result.Intents)
+	assert.Equal(t, "10.0.0.1", result.DecisionMaker.Host)
+	assert.Empty(t, result.DecisionMaker.Error)
+	assert.Same(t, dmResult, result.DecisionMaker.Result)
+
+	require.Len(t, result.Strategies, 4)
+	assert.True(t, result.Strategies[0].Matched)
+	assert.Equal(t, []string{"nginx"}, result.Strategies[0].MatchedContainers)
+	assert.Empty(t, result.Strategies[0].Reasons)
+
+	assert.False(t, result.Strategies[1].Matched)
+	assert.False(t, result.Strategies[1].NamespaceMatched)
+	assert.True(t, result.Strategies[1].LabelsMatched)
+	assert.Len(t, result.Strategies[1].Reasons, 1)
+
+	assert.False(t, result.Strategies[2].Matched)
+	assert.True(t, result.Strategies[2].NamespaceMatched)
+	assert.False(t, result.Strategies[2].LabelsMatched)
+	assert.Len(t, result.Strategies[2].Reasons, 2)
+
+	assert.False(t, result.Strategies[3].Matched)
+	assert.False(t, result.Strategies[3].CommandMatched)
+	assert.Len(t, result.Strategies[3].Reasons, 1)
+}
+
+func TestExplainPodNotFound(t *testing.T) {
+	mockK8S := domain.NewMockK8SAdapter(t)
+	mockK8S.EXPECT().
+		GetPod(mock.Anything, "default", "web-0").
+		Return(nil, domain.ErrNotFound).
+		Once()
+
+	svc := &Service{
+		K8SAdapter: mockK8S,
+	}
+	_, err := svc.ExplainPod(context.Background(), "default", "web-0")
+	require.Error(t, err)
+	httpErr, ok := errs.IsHTTPStatusError(err)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+}
+
+func TestExplainPodDecisionMakerUnreachable(t *testing.T) {
+	mockK8S := domain.NewMockK8SAdapter(t)
+	mockRepo := domain.NewMockRepository(t)
+	mockDM := domain.NewMockDecisionMakerAdapter(t)
+	pod := &domain.Pod{Name: "web-0", K8SNamespace: "default", PodID: "pod-uid-1", NodeID: "node-a"}
+	dm := &domain.DecisionMakerPod{NodeID: "node-a", Host: "10.0.0.1", Port: 8080, State: domain.NodeStateOnline}
+
+	mockK8S.EXPECT().GetPod(mock.Anything, "default", "web-0").Return(pod, nil).Once()
+	mockRepo.EXPECT().QueryStrategies(mock.Anything, mock.Anything).Return(nil).Once()
+	mockRepo.EXPECT().QueryIntents(mock.Anything, mock.Anything).Return(nil).Once()
+	mockK8S.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return([]*domain.DecisionMakerPod{dm}, nil).Once()
+	mockDM.EXPECT().ExplainPod(mock.Anything, dm, pod.PodID).Return(nil, errors.New("connection refused")).Once()
+
+	svc := &Service{
+		K8SAdapter: mockK8S,
+		Repo:       mockRepo,
+		DMAdapter:  mockDM,
+	}
+	result, err := svc.ExplainPod(context.Background(), "default", "web-0")
+	require.NoError(t, err)
+	assert.Equal(t, "connection refused", result.DecisionMaker.Error)
+	assert.Nil(t, result.DecisionMaker.Result)
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/manager/errs"
+	"github.com/Gthulhu/api/pkg/logger"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	defaultMaxFailedLogins      = 5
+	defaultLoginLockout         = 15 * time.Minute
+	defaultMaxFailedLoginsPerIP = 20
+	defaultLoginIPWindow        = 15 * time.Minute
+	// maxTrackedLoginClients bounds the memory of the per-IP throttle, expired windows are pruned
+	// once it's reached and the oldest window is evicted when none expired
+	maxTrackedLoginClients = 10000
+)
+
+// loginThrottle counts failed logins per client IP in fixed windows. Its state is local to the
+// manager instance.
+type loginThrottle struct {
+	maxFailures int
+	window      time.Duration
+
+	mu       sync.Mutex
+	failures map[string]*clientLoginFailures
+}
+
+type clientLoginFailures struct {
+	count       int
+	windowStart time.Time
+}
+
+func newLoginThrottle(cfg config.LoginProtectionConfig) *loginThrottle {
+	throttle := &loginThrottle{
+		maxFailures: cfg.MaxFailedAttemptsPerIP,
+		window:      time.Duration(cfg.IPWindowSec) * time.Second,
+		failures:    map[string]*clientLoginFailures{},
+	}
+	if throttle.maxFailures <= 0 {
+		throttle.maxFailures = defaultMaxFailedLoginsPerIP
+	}
+	if throttle.window <= 0 {
+		throttle.window = defaultLoginIPWindow
+	}
+	return throttle
+}
+
+// blocked reports whether ip reached the failure limit of its current window
+func (t *loginThrottle) blocked(ip string, now time.Time) bool {
+	if t == nil || ip == "" {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	failures, ok := t.failures[ip]
+	return ok && now.Sub(failures.windowStart) < t.window && failures.count >= t.maxFailures
+}
+
+func (t *loginThrottle) recordFailure(ip string, now time.Time) {
+	if t == nil || ip == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	failures, ok := t.failures[ip]
+	if !ok || now.Sub(failures.windowStart) >= t.window {
+		if !ok && len(t.failures) >= maxTrackedLoginClients {
+			t.evict(now)
+		}
+		failures = &clientLoginFailures{windowStart: now}
+		t.failures[ip] = failures
+	}
+	failures.count++
+}
+
+// evict deletes the expired windows, or the oldest one when none expired
+func (t *loginThrottle) evict(now time.Time) {
+	var oldestIP string
+	var oldest time.Time
+	for ip, failures := range t.failures {
+		if now.Sub(failures.windowStart) >= t.window {
+			delete(t.failures, ip)
+			continue
+		}
+		if oldestIP == "" || failures.windowStart.Before(oldest) {
+			oldestIP, oldest = ip, failures.windowStart
+		}
+	}
+	if len(t.failures) >= maxTrackedLoginClients {
+		delete(t.failures, oldestIP)
+	}
+}
+
+func (svc *Service) loginLockout(now time.Time) domain.LoginLockout {
+	lockout := domain.LoginLockout{MaxFailedAttempts: svc.loginProtection.MaxFailedAttempts}
+	if lockout.MaxFailedAttempts <= 0 {
+		lockout.MaxFailedAttempts = defaultMaxFailedLogins
+	}
+	duration := time.Duration(svc.loginProtection.LockoutDurationSec) * time.Second
+	if duration <= 0 {
+		duration = defaultLoginLockout
+	}
+	lockout.LockedUntil = now.Add(duration).UnixMilli()
+	return lockout
+}
+
+// recordLoginFailure counts a wrong password or two-factor code against user and returns the error
+// of the attempt, reason is its message
+func (svc *Service) recordLoginFailure(ctx context.Context, user *domain.User, clientIP, reason string, now time.Time) error {
+	svc.loginThrottle.recordFailure(clientIP, now)
+	attempt := domain.LoginAttempt{Time: now.UnixMilli(), IP: clientIP, Reason: reason}
+	updated, err := svc.Repo.RecordLoginFailure(ctx, user.ID, attempt, svc.loginLockout(now))
+	if err != nil {
+		return errors.WithMessagef(err, "db: record failed login of user %s failed", user.UserName)
+	}
+	if updated.Locked(now) {
+		logger.Logger(ctx).Warn().Msgf("user %s locked until %s after failed logins, last from %s", user.UserName, time.UnixMilli(updated.LockedUntil).Format(time.RFC3339), clientIP)
+		return errLoginLocked(user)
+	}
+	return errs.NewHTTPStatusError(http.StatusUnauthorized, reason, fmt.Errorf("login of username %s failed: %s", user.UserName, reason))
+}
+
+func errLoginLocked(user *domain.User) error {
+	return errs.NewHTTPStatusError(http.StatusLocked, "account is temporarily locked after too many failed logins", fmt.Errorf("user %s is locked until %d", user.UserName, user.LockedUntil))
+}
+
+// checkPassword validates password against the password policy, user is nil for new users and
+// otherwise can't reuse its recent passwords
+func (svc *Service) checkPassword(user *domain.User, password string) error {
+	if violations := svc.passwordPolicy.Violations(password); len(violations) > 0 {
+		return errs.NewHTTPStatusError(http.StatusBadRequest, "password "+strings.Join(violations, ", "), errors.New("password violates the password policy"))
+	}
+	if user == nil || svc.passwordPolicy.HistorySize <= 0 {
+		return nil
+	}
+	recent := append([]domain.EncryptedPassword{user.Password}, user.PasswordHistory...)
+	recent = recent[:min(len(recent), svc.passwordPolicy.HistorySize)]
+	for _, hash := range recent {
+		if hash == "" {
+			continue
+		}
+		reused, err := hash.Cmp(password)
+		if err != nil {
+			return errors.WithMessagef(err, "compare password history of user %s failed", user.UserName)
+		}
+		if reused {
+			return errs.NewHTTPStatusError(http.StatusBadRequest, fmt.Sprintf("password can't be one of the last %d passwords", svc.passwordPolicy.HistorySize), errors.New("password reused"))
+		}
+	}
+	return nil
+}
+
+// setPassword replaces the password of user, keeping the previous one in its history
+func (svc *Service) setPassword(user *domain.User, password string) {
+	if keep := svc.passwordPolicy.HistorySize - 1; keep > 0 && user.Password != "" {
+		user.PasswordHistory = append([]domain.EncryptedPassword{user.Password}, user.PasswordHistory...)
+		user.PasswordHistory = user.PasswordHistory[:min(len(user.PasswordHistory), keep)]
+	} else {
+		user.PasswordHistory = nil
+	}
+	user.Password = domain.EncryptedPassword(password)
+}
+
+// UnlockUser lifts the lockout of a user and resets its failed login count
+func (svc *Service) UnlockUser(ctx context.Context, operator *domain.Claims, id string) error {
+	operatorID, err := operator.GetBsonObjectUID()
+	if err != nil {
+		return errors.WithMessagef(err, "invalid operator ID %s", operator.UID)
+	}
+	uid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return errs.NewHTTPStatusError(http.StatusUnprocessableEntity, "invalid user ID", fmt.Errorf("invalid user ID %s: %v", id, err))
+	}
+	user, err := svc.getUserByID(ctx, uid)
+	if err != nil {
+		return err
+	}
+	user.FailedLoginCount = 0
+	user.LockedUntil = 0
+	user.UpdatedTime = time.Now().UnixMilli()
+	user.UpdaterID = operatorID
+	return svc.updateUser(ctx, user)
+}
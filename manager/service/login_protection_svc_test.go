@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// newLoginProtectionTestService returns a service whose mocked repository applies login attempts
+// and updates to user
+func newLoginProtectionTestService(t *testing.T, password string) (*Service, *domain.User) {
+	hash, err := util.CreateArgon2Hash(password)
+	require.NoError(t, err)
+	user := &domain.User{
+		BaseEntity: domain.BaseEntity{ID: bson.NewObjectID()},
+		UserName:   "alice",
+		Password:   domain.EncryptedPassword(hash),
+		Status:     domain.UserStatusActive,
+	}
+	mockRepo := domain.NewMockRepository(t)
+	mockRepo.EXPECT().QueryUsers(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt *domain.QueryUserOptions) {
+			copied := *user
+			opt.Result = []*domain.User{&copied}
+		}).Return(nil).Maybe()
+	mockRepo.EXPECT().UpdateUser(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, updated *domain.User) { *user = *updated }).Return(nil).Maybe()
+	mockRepo.EXPECT().RecordLoginSuccess(mock.Anything, user.ID, mock.Anything).
+		Run(func(ctx context.Context, id bson.ObjectID, attempt domain.LoginAttempt) {
+			user.FailedLoginCount, user.LockedUntil = 0, 0
+			user.LastLoginTime, user.LastLoginIP = attempt.Time, attempt.IP
+			user.LoginHistory = append(user.LoginHistory, attempt)
+		}).Return(nil).Maybe()
+	mockRepo.EXPECT().RecordLoginFailure(mock.Anything, user.ID, mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, id bson.ObjectID, attempt domain.LoginAttempt, lockout domain.LoginLockout) (*domain.User, error) {
+			user.FailedLoginCount++
+			user.LoginHistory = append(user.LoginHistory, attempt)
+			if user.FailedLoginCount >= lockout.MaxFailedAttempts {
+				user.FailedLoginCount, user.LockedUntil = 0, lockout.LockedUntil
+			}
+			copied := *user
+			return &copied, nil
+		}).Maybe()
+	mockRepo.EXPECT().CreateRefreshToken(mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	svc := &Service{
+		Repo:            mockRepo,
+		jwtPrivateKey:   testRSAKey(t),
+		loginProtection: config.LoginProtectionConfig{MaxFailedAttempts: 3, LockoutDurationSec: 60},
+		loginThrottle:   newLoginThrottle(config.LoginProtectionConfig{MaxFailedAttemptsPerIP: 5, IPWindowSec: 60}),
+	}
+	return svc, user
+}
+
+func TestLoginLockout(t *testing.T) {
+	svc, user := newLoginProtectionTestService(t, "Correct-horse-1")
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, err := svc.Login(ctx, "alice", "wrong", "10.0.0.1")
+		requireHTTPStatus(t, err, http.StatusUnauthorized)
+	}
+	_, err := svc.Login(ctx, "alice", "wrong", "10.0.0.2")
+	requireHTTPStatus(t, err, http.StatusLocked)
+	assert.True(t, user.Locked(time.Now()))
+	assert.Len(t, user.LoginHistory, 3)
+	assert.Equal(t, "invalid password", user.LoginHistory[0].Reason)
+
+	// the right password doesn't help during the lockout
+	_, err = svc.Login(ctx, "alice", "Correct-horse-1", "10.0.0.3")
+	requireHTTPStatus(t, err, http.StatusLocked)
+
+	operator := &domain.Claims{UID: bson.NewObjectID().Hex()}
+	require.NoError(t, svc.UnlockUser(ctx, operator, user.ID.Hex()))
+	assert.False(t, user.Locked(time.Now()))
+	_, err = svc.Login(ctx, "alice", "Correct-horse-1", "10.0.0.3")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.3", user.LastLoginIP)
+	assert.NotZero(t, user.LastLoginTime)
+	assert.True(t, user.LoginHistory[len(user.LoginHistory)-1].Success)
+}
+
+func TestLoginThrottlePerIP(t *testing.T) {
+	svc, _ := newLoginProtectionTestService(t, "Correct-horse-1")
+	svc.loginProtection.MaxFailedAttempts = 100
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		_, err := svc.Login(ctx, "alice", "wrong", "10.0.0.1")
+		requireHTTPStatus(t, err, http.StatusUnauthorized)
+	}
+	_, err := svc.Login(ctx, "alice", "Correct-horse-1", "10.0.0.1")
+	requireHTTPStatus(t, err, http.StatusTooManyRequests)
+	_, err = svc.Login(ctx, "alice", "Correct-horse-1", "10.0.0.2")
+	require.NoError(t, err)
+
+	throttle := svc.loginThrottle
+	assert.False(t, throttle.blocked("10.0.0.1", time.Now().Add(time.Minute)), "the window expired")
+}
+
+func TestLoginThrottleCapacity(t *testing.T) {
+	throttle := newLoginThrottle(config.LoginProtectionConfig{})
+	now := time.Now()
+	for i := 0; i < maxTrackedLoginClients; i++ {
+		throttle.recordFailure(fmt.Sprintf("ip-%d", i), now.Add(time.Duration(i)*time.Millisecond))
+	}
+	// spoofed or spread out addresses can't grow the throttle beyond its cap
+	throttle.recordFailure("10.0.0.1", now.Add(time.Second))
+	assert.Len(t, throttle.failures, maxTrackedLoginClients)
+	assert.NotContains(t, throttle.failures, "ip-0", "the oldest window is evicted")
+	assert.Contains(t, throttle.failures, "10.0.0.1")
+
+	// expired windows are evicted first
+	throttle.recordFailure("10.0.0.2", now.Add(defaultLoginIPWindow+500*time.Millisecond))
+	assert.Less(t, len(throttle.failures), maxTrackedLoginClients)
+	assert.NotContains(t, throttle.failures, "ip-500")
+	assert.Contains(t, throttle.failures, "ip-501")
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	policy := domain.PasswordPolicy{MinLength: 10, RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSymbol: true}
+	assert.Empty(t, policy.Violations("Correct-horse-1"))
+	assert.Equal(t, []string{"must be at least 10 characters", "must contain an uppercase letter", "must contain a digit", "must contain a symbol"}, policy.Violations("short"))
+	assert.Equal(t, []string{"must be at least 8 characters"}, domain.PasswordPolicy{}.Violations(""))
+	assert.NotEmpty(t, domain.PasswordPolicy{}.Violations(string(make([]byte, 200))))
+}
+
+func TestChangePasswordEnforcesPolicyAndHistory(t *testing.T) {
+	svc, user := newLoginProtectionTestService(t, "Password-0")
+	svc.passwordPolicy = domain.PasswordPolicy{MinLength: 10, RequireDigit: true, HistorySize: 3}
+	ctx := context.Background()
+	claims := &domain.Claims{UID: user.ID.Hex()}
+
+	err := svc.ChangePassword(ctx, claims, "Password-0", "")
+	requireHTTPStatus(t, err, http.StatusBadRequest)
+	err = svc.ChangePassword(ctx, claims, "Password-0", "no-digits-here")
+	requireHTTPStatus(t, err, http.StatusBadRequest)
+	err = svc.ChangePassword(ctx, claims, "Password-0", "Password-0")
+	requireHTTPStatus(t, err, http.StatusBadRequest)
+
+	// the repository hashes passwords, emulate it so that the history holds hashes
+	change := func(old, new string) error {
+		if err := svc.ChangePassword(ctx, claims, old, new); err != nil {
+			return err
+		}
+		hash, err := util.CreateArgon2Hash(string(user.Password))
+		require.NoError(t, err)
+		user.Password = domain.EncryptedPassword(hash)
+		return nil
+	}
+	require.NoError(t, change("Password-0", "Password-1"))
+	require.NoError(t, change("Password-1", "Password-2"))
+	assert.Len(t, user.PasswordHistory, 2)
+	requireHTTPStatus(t, change("Password-2", "Password-0"), http.StatusBadRequest)
+	require.NoError(t, change("Password-2", "Password-3"))
+	assert.Len(t, user.PasswordHistory, 2)
+	// Password-0 dropped out of the last 3 passwords
+	require.NoError(t, change("Password-3", "Password-0"))
+}
+
+func TestCreateNewUserRejectsWeakPassword(t *testing.T) {
+	svc := &Service{passwordPolicy: domain.PasswordPolicy{MinLength: 12}}
+	operator := &domain.Claims{UID: bson.NewObjectID().Hex()}
+	err := svc.CreateNewUser(context.Background(), operator, "bob", "")
+	requireHTTPStatus(t, err, http.StatusBadRequest)
+	err = svc.CreateNewUser(context.Background(), operator, "bob", "elevenchars")
+	requireHTTPStatus(t, err, http.StatusBadRequest)
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// metricCollectionLease is held by the manager replica storing the metric samples
+const metricCollectionLease = "metric-sample-collection"
+
+// storedSampleTracker remembers the timestamp of the last sample stored per node, so a decision maker
+// whose scheduler stopped reporting does not fill the time series with copies of its last metric set.
+// Only the lease holder collects, so several replicas don't store the same samples.
+type storedSampleTracker struct {
+	mu            sync.Mutex
+	lastTimestamp map[string]time.Time
+
+	collectMu sync.Mutex
+	holder    string
+	leaseTTL  time.Duration
+}
+
+func newStoredSampleTracker(cfg config.MetricStoreConfig) *storedSampleTracker {
+	hostname, _ := os.Hostname()
+	return &storedSampleTracker{
+		lastTimestamp: map[string]time.Time{},
+		holder:        hostname + "-" + bson.NewObjectID().Hex(),
+		// a lease outliving a couple of missed collections avoids flapping between replicas
+		leaseTTL: 3 * cfg.CollectInterval(),
+	}
+}
+
+// reset forgets the stored samples, another replica may have stored newer ones meanwhile
+func (t *storedSampleTracker) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	clear(t.lastTimestamp)
+}
+
+// IsNew reports whether sample was not stored yet for nodeID
+func (t *storedSampleTracker) IsNew(nodeID string, sample *domain.MetricSample) bool {
+	if t == nil {
+		return true
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return !t.lastTimestamp[nodeID].Equal(sample.Timestamp)
+}
+
+// MarkStored records the samples as stored
+func (t *storedSampleTracker) MarkStored(samples []*domain.NodeMetricSample) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, sample := range samples {
+		t.lastTimestamp[sample.NodeID] = sample.Timestamp
+	}
+}
+
+// CollectMetricSamples pulls the current metric set of every online decision maker and appends
+// the ones not stored yet to the metric time series, together with the strategies active on the node.
+// Only the manager replica holding the collection lease collects, the others return right away.
+func (svc *Service) CollectMetricSamples(ctx context.Context) error {
+	if svc.K8SAdapter == nil {
+		return domain.ErrNoClient
+	}
+	tracker := svc.storedSampleTracker
+	if tracker == nil {
+		return errors.New("stored sample tracker is not initialized")
+	}
+	tracker.collectMu.Lock()
+	defer tracker.collectMu.Unlock()
+
+	leader, err := svc.Repo.AcquireLease(ctx, metricCollectionLease, tracker.holder, tracker.leaseTTL)
+	if err != nil {
+		return fmt.Errorf("acquire metric collection lease: %w", err)
+	}
+	if !leader {
+		tracker.reset()
+		return nil
+	}
+
+	dms, err := svc.K8SAdapter.QueryDecisionMakerPods(ctx, &domain.QueryDecisionMakerPodsOptions{
+		DecisionMakerLabel: domain.LabelSelector{
+			Key:   "app",
+			Value: "decisionmaker",
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("query decision maker pods: %w", err)
+	}
+	online := make([]*domain.DecisionMakerPod, 0, len(dms))
+	for _, dm := range decisionMakersByNode(dms) {
+		if dm.State == domain.NodeStateOnline {
+			online = append(online, dm)
+		}
+	}
+
+	strategiesByNode, err := svc.activeStrategiesByNode(ctx)
+	if err != nil {
+		return err
+	}
+
+	samples, _ := svc.fetchMetrics(ctx, online)
+	docs := make([]*domain.NodeMetricSample, 0, len(online))
+	for i, dm := range online {
+		if samples[i] == nil || !tracker.IsNew(dm.NodeID, samples[i]) {
+			continue
+		}
+		docs = append(docs, &domain.NodeMetricSample{
+			NodeID:       dm.NodeID,
+			MetricSample: *samples[i],
+			StrategyIDs:  strategiesByNode[dm.NodeID],
+		})
+	}
+	if err := svc.Repo.InsertMetricSamples(ctx, docs); err != nil {
+		return fmt.Errorf("insert metric samples: %w", err)
+	}
+	tracker.MarkStored(docs)
+	logger.Logger(ctx).Debug().Msgf("stored %d metric samples from %d decision makers", len(docs), len(online))
+	return nil
+}
+
+// activeStrategiesByNode returns the sorted hex IDs of the strategies with intents sent to every node
+func (svc *Service) activeStrategiesByNode(ctx context.Context) (map[string][]string, error) {
+	intentOpt := &domain.QueryIntentOptions{States: []domain.IntentState{domain.IntentStateSent}}
+	if err := svc.Repo.QueryIntents(ctx, intentOpt); err != nil {
+		return nil, fmt.Errorf("query sent intents: %w", err)
+	}
+	result := map[string][]string{}
+	for _, intent := range intentOpt.Result {
+		if intent.StrategyID.IsZero() {
+			continue
+		}
+		strategyID := intent.StrategyID.Hex()
+		if !slices.Contains(result[intent.NodeID], strategyID) {
+			result[intent.NodeID] = append(result[intent.NodeID], strategyID)
+		}
+	}
+	for _, strategyIDs := range result {
+		slices.Sort(strategyIDs)
+	}
+	return result, nil
+}
+
+// QueryMetricSamples queries the persisted metric time series
+func (svc *Service) QueryMetricSamples(ctx context.Context, opt *domain.QueryMetricSampleOptions) error {
+	return svc.Repo.QueryMetricSamples(ctx, opt)
+}
+
+// SetMetricSampleRetention changes how long persisted metric samples are kept
+func (svc *Service) SetMetricSampleRetention(ctx context.Context, retention time.Duration) error {
+	return svc.Repo.SetMetricSampleRetention(ctx, retention)
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestCollectMetricSamples(t *testing.T) {
+	mockK8S := domain.NewMockK8SAdapter(t)
+	mockRepo := domain.NewMockRepository(t)
+	mockDM := domain.NewMockDecisionMakerAdapter(t)
+	dmA := &domain.DecisionMakerPod{NodeID: "node-a", Host: "10.0.0.1", Port: 8080, State: domain.NodeStateOnline}
+	dmB := &domain.DecisionMakerPod{NodeID: "node-b", Host: "10.0.0.2", Port: 8080, State: domain.NodeStateOffline}
+	first := &domain.MetricSample{Timestamp: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), NrQueued: 4}
+	second := &domain.MetricSample{Timestamp: first.Timestamp.Add(15 * time.Second), NrQueued: 6}
+
+	mockK8S.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).
+		Return([]*domain.DecisionMakerPod{dmA, dmB}, nil).Times(3)
+	mockDM.EXPECT().GetMetrics(mock.Anything, dmA).Return(first, nil).Twice()
+	mockDM.EXPECT().GetMetrics(mock.Anything, dmA).Return(second, nil).Once()
+
+	strategyA, strategyB := bson.NewObjectID(), bson.NewObjectID()
+	mockRepo.EXPECT().QueryIntents(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt *domain.QueryIntentOptions) {
+			assert.Equal(t, []domain.IntentState{domain.IntentStateSent}, opt.States)
+			opt.Result = []*domain.ScheduleIntent{
+				{NodeID: "node-a", StrategyID: strategyB},
+				{NodeID: "node-a", StrategyID: strategyA},
+				{NodeID: "node-a", StrategyID: strategyB},
+				{NodeID: "node-b", StrategyID: strategyA},
+			}
+		}).
+		Return(nil).Times(3)
+
+	mockRepo.EXPECT().AcquireLease(mock.Anything, metricCollectionLease, mock.Anything, 45*time.Second).
+		Return(true, nil).Times(3)
+
+	var stored [][]*domain.NodeMetricSample
+	mockRepo.EXPECT().InsertMetricSamples(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, samples []*domain.NodeMetricSample) {
+			stored = append(stored, samples)
+		}).
+		Return(nil).Times(3)
+
+	svc := &Service{
+		K8SAdapter:          mockK8S,
+		Repo:                mockRepo,
+		DMAdapter:           mockDM,
+		storedSampleTracker: newStoredSampleTracker(config.MetricStoreConfig{}),
+	}
+	for i := 0; i < 3; i++ {
+		require.NoError(t, svc.CollectMetricSamples(context.Background()))
+	}
+
+	require.Len(t, stored, 3)
+	require.Len(t, stored[0], 1)
+	assert.Equal(t, "node-a", stored[0][0].NodeID)
+	assert.Equal(t, uint64(4), stored[0][0].NrQueued)
+	expectedStrategies := []string{strategyA.Hex(), strategyB.Hex()}
+	slices.Sort(expectedStrategies)
+	assert.Equal(t, expectedStrategies, stored[0][0].StrategyIDs)
+	assert.Empty(t, stored[1], "an unchanged sample is not stored twice")
+	require.Len(t, stored[2], 1)
+	assert.Equal(t, second.Timestamp, stored[2][0].Timestamp)
+}
+
+func TestCollectMetricSamplesWithoutLease(t *testing.T) {
+	mockK8S := domain.NewMockK8SAdapter(t)
+	mockRepo := domain.NewMockRepository(t)
+	tracker := newStoredSampleTracker(config.MetricStoreConfig{CollectIntervalSec: 10})
+	sample := domain.MetricSample{Timestamp: time.Now()}
+	tracker.MarkStored([]*domain.NodeMetricSample{{NodeID: "node-a", MetricSample: sample}})
+	mockRepo.EXPECT().AcquireLease(mock.Anything, metricCollectionLease, tracker.holder, 30*time.Second).
+		Return(false, nil).Once()
+
+	svc := &Service{K8SAdapter: mockK8S, Repo: mockRepo, storedSampleTracker: tracker}
+	require.NoError(t, svc.CollectMetricSamples(context.Background()))
+	mockK8S.AssertNotCalled(t, "QueryDecisionMakerPods", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "InsertMetricSamples", mock.Anything, mock.Anything)
+	assert.True(t, tracker.IsNew("node-a", &sample), "samples stored by the previous term must not be skipped by the next one")
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/manager/errs"
+	"github.com/Gthulhu/api/pkg/logger"
+)
+
+// GetNodeMetricHistory proxies the metric history retained by the decision maker running on nodeID
+func (svc *Service) GetNodeMetricHistory(ctx context.Context, nodeID string, since time.Time, step time.Duration) (*domain.NodeMetricHistory, error) {
+	if svc.K8SAdapter == nil {
+		return nil, domain.ErrNoClient
+	}
+
+	dms, err := svc.K8SAdapter.QueryDecisionMakerPods(ctx, &domain.QueryDecisionMakerPodsOptions{
+		DecisionMakerLabel: domain.LabelSelector{
+			Key:   "app",
+			Value: "decisionmaker",
+		},
+		NodeIDs: []string{nodeID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query decision maker pods: %w", err)
+	}
+	if len(dms) == 0 {
+		return nil, errs.NewHTTPStatusError(http.StatusNotFound, fmt.Sprintf("no decision maker pod found on node %s", nodeID), nil)
+	}
+	dm := dms[0]
+	if dm.State != domain.NodeStateOnline {
+		return nil, errs.NewHTTPStatusError(http.StatusServiceUnavailable, fmt.Sprintf("decision maker on node %s is not online", nodeID), nil)
+	}
+
+	samples, err := svc.DMAdapter.GetMetricHistory(ctx, dm, since, step)
+	if err != nil {
+		return nil, fmt.Errorf("get metric history from decision maker: %w", err)
+	}
+	return &domain.NodeMetricHistory{
+		NodeID:  nodeID,
+		Samples: samples,
+	}, nil
+}
+
+const (
+	defaultUserSchedStaleAfter = 30 * time.Second
+	clusterMetricsTimeout      = 5 * time.Second
+)
+
+// nodeMetricsState is what the previous collections observed for a node
+type nodeMetricsState struct {
+	userSchedLastRunAt   uint64
+	userSchedLastRunSeen time.Time // when userSchedLastRunAt was first observed
+	failedDispatches     uint64
+}
+
+// nodeMetricsTracker remembers the previous collection of every node to detect values that
+// stopped advancing or keep climbing between GetClusterMetrics calls
+type nodeMetricsTracker struct {
+	mu     sync.Mutex
+	states map[string]nodeMetricsState
+}
+
+func newNodeMetricsTracker() *nodeMetricsTracker {
+	return &nodeMetricsTracker{states: map[string]nodeMetricsState{}}
+}
+
+// Observe records sample for nodeID and returns when UserSchedLastRunAt was first observed at its
+// current value and how much NrFailedDispatches grew since the previous observation
+func (t *nodeMetricsTracker) Observe(nodeID string, now time.Time, sample *domain.MetricSample) (lastRunSeen time.Time, failedDelta uint64) {
+	if t == nil {
+		return now, 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	prev, ok := t.states[nodeID]
+	state := nodeMetricsState{
+		userSchedLastRunAt:   sample.UserSchedLastRunAt,
+		userSchedLastRunSeen: now,
+		failedDispatches:     sample.NrFailedDispatches,
+	}
+	if ok {
+		if prev.userSchedLastRunAt == sample.UserSchedLastRunAt {
+			state.userSchedLastRunSeen = prev.userSchedLastRunSeen
+		}
+		// a decrease means the scheduler restarted and its counters were reset
+		if sample.NrFailedDispatches > prev.failedDispatches {
+			failedDelta = sample.NrFailedDispatches - prev.failedDispatches
+		}
+	}
+	t.states[nodeID] = state
+	return state.userSchedLastRunSeen, failedDelta
+}
+
+// decisionMakersByNode indexes decision makers by node, preferring an online one when a node
+// has several, e.g. during a rollout
+func decisionMakersByNode(dms []*domain.DecisionMakerPod) map[string]*domain.DecisionMakerPod {
+	dmByNode := make(map[string]*domain.DecisionMakerPod, len(dms))
+	for _, dm := range dms {
+		if existing, ok := dmByNode[dm.NodeID]; !ok || existing.State != domain.NodeStateOnline {
+			dmByNode[dm.NodeID] = dm
+		}
+	}
+	return dmByNode
+}
+
+// fetchMetrics concurrently gets the current metrics of the decision makers, the results are
+// indexed like dms and a nil sample without error means the scheduler has not reported yet
+func (svc *Service) fetchMetrics(ctx context.Context, dms []*domain.DecisionMakerPod) ([]*domain.MetricSample, []error) {
+	samples := make([]*domain.MetricSample, len(dms))
+	fetchErrs := make([]error, len(dms))
+	var wg sync.WaitGroup
+	for i, dm := range dms {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dmCtx, cancel := context.WithTimeout(ctx, clusterMetricsTimeout)
+			defer cancel()
+			samples[i], fetchErrs[i] = svc.DMAdapter.GetMetrics(dmCtx, dm)
+			if fetchErrs[i] != nil {
+				logger.Logger(ctx).Warn().Err(fetchErrs[i]).Msgf("failed to get metrics from decision maker %s", dm)
+			}
+		}()
+	}
+	wg.Wait()
+	return samples, fetchErrs
+}
+
+// GetClusterMetrics collects the current scheduler metrics from every online decision maker and joins
+// them with the cluster nodes. Nodes are flagged as outliers when the user scheduler has not run or
+// reported within staleAfter, when failed dispatches climbed since the previous call, or when their
+// decision maker cannot be reached.
+func (svc *Service) GetClusterMetrics(ctx context.Context, staleAfter time.Duration) (*domain.ClusterMetrics, error) {
+	if svc.K8SAdapter == nil {
+		return nil, domain.ErrNoClient
+	}
+	if staleAfter <= 0 {
+		staleAfter = defaultUserSchedStaleAfter
+	}
+
+	nodes, err := svc.K8SAdapter.ListNodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list nodes: %w", err)
+	}
+	dms, err := svc.K8SAdapter.QueryDecisionMakerPods(ctx, &domain.QueryDecisionMakerPodsOptions{
+		DecisionMakerLabel: domain.LabelSelector{
+			Key:   "app",
+			Value: "decisionmaker",
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query decision maker pods: %w", err)
+	}
+	dmByNode := decisionMakersByNode(dms)
+
+	results := make([]domain.NodeMetrics, 0, len(nodes))
+	seen := make(map[string]struct{}, len(nodes))
+	for _, node := range nodes {
+		seen[node.Name] = struct{}{}
+		results = append(results, domain.NodeMetrics{Node: node})
+	}
+	for nodeID := range dmByNode {
+		if _, ok := seen[nodeID]; !ok {
+			results = append(results, domain.NodeMetrics{Node: &domain.Node{Name: nodeID}})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Node.Name < results[j].Node.Name
+	})
+
+	online := make([]*domain.DecisionMakerPod, 0, len(results))
+	onlineResults := make([]*domain.NodeMetrics, 0, len(results))
+	for i := range results {
+		dm, ok := dmByNode[results[i].Node.Name]
+		if !ok {
+			results[i].Error = "no decision maker pod found on node"
+			continue
+		}
+		results[i].DecisionMakerHost = dm.Host
+		results[i].DecisionMakerState = dm.State
+		if dm.State != domain.NodeStateOnline {
+			results[i].Error = "decision maker is not online"
+			continue
+		}
+		online = append(online, dm)
+		onlineResults = append(onlineResults, &results[i])
+	}
+	samples, fetchErrs := svc.fetchMetrics(ctx, online)
+	for i, result := range onlineResults {
+		if fetchErrs[i] != nil {
+			result.Error = fetchErrs[i].Error()
+			continue
+		}
+		result.Sample = samples[i]
+	}
+
+	now := time.Now()
+	clusterMetrics := &domain.ClusterMetrics{
+		CollectedAt: now,
+		Nodes:       results,
+	}
+	for i := range results {
+		result := &results[i]
+		if result.Error != "" {
+			result.Outliers = append(result.Outliers, domain.OutlierUnreachable)
+			continue
+		}
+		if result.Sample == nil {
+			result.Outliers = append(result.Outliers, domain.OutlierNoMetrics)
+			continue
+		}
+		clusterMetrics.Totals.Add(result.Sample)
+
+		lastRunSeen, failedDelta := svc.nodeMetricsTracker.Observe(result.Node.Name, now, result.Sample)
+		result.FailedDispatchesDelta = failedDelta
+		// the scheduler either stopped reporting or keeps reporting the same last run
+		if now.Sub(result.Sample.Timestamp) > staleAfter || now.Sub(lastRunSeen) > staleAfter {
+			result.Outliers = append(result.Outliers, domain.OutlierUserSchedStale)
+		}
+		if failedDelta > 0 {
+			result.Outliers = append(result.Outliers, domain.OutlierFailedDispatchesClimbing)
+		}
+	}
+	return clusterMetrics, nil
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/manager/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNodeMetricHistory(t *testing.T) {
+	mockK8S := domain.NewMockK8SAdapter(t)
+	mockDM := domain.NewMockDecisionMakerAdapter(t)
+	dm := &domain.DecisionMakerPod{NodeID: "node-a", Host: "10.0.0.1", Port: 8080, State: domain.NodeStateOnline}
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	samples := []domain.MetricSample{
+		{Timestamp: since.Add(10 * time.Second), NrQueued: 3},
+		{Timestamp: since.Add(20 * time.Second), NrQueued: 5},
+	}
+
+	mockK8S.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt *domain.QueryDecisionMakerPodsOptions) {
+			assert.Equal(t, []string{"node-a"}, opt.NodeIDs)
+		}).
+		Return([]*domain.DecisionMakerPod{dm}, nil).Once()
+	mockDM.EXPECT().GetMetricHistory(mock.Anything, dm, since, 10*time.Second).Return(samples, nil).Once()
+
+	svc := &Service{K8SAdapter: mockK8S, DMAdapter: mockDM}
+	result, err := svc.GetNodeMetricHistory(context.Background(), "node-a", since, 10*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "node-a", result.NodeID)
+	assert.Equal(t, samples, result.Samples)
+}
+
+func TestGetNodeMetricHistoryDecisionMakerUnavailable(t *testing.T) {
+	mockK8S := domain.NewMockK8SAdapter(t)
+	mockK8S.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return(nil, nil).Once()
+	mockK8S.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).
+		Return([]*domain.DecisionMakerPod{{NodeID: "node-a", State: domain.NodeStateOffline}}, nil).Once()
+
+	svc := &Service{K8SAdapter: mockK8S, DMAdapter: domain.NewMockDecisionMakerAdapter(t)}
+	_, err := svc.GetNodeMetricHistory(context.Background(), "node-a", time.Time{}, 0)
+	httpErr, ok := errs.IsHTTPStatusError(err)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+
+	_, err = svc.GetNodeMetricHistory(context.Background(), "node-a", time.Time{}, 0)
+	httpErr, ok = errs.IsHTTPStatusError(err)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+}
+
+func TestGetClusterMetrics(t *testing.T) {
+	mockK8S := domain.NewMockK8SAdapter(t)
+	mockDM := domain.NewMockDecisionMakerAdapter(t)
+	nodes := []*domain.Node{
+		{Name: "node-c", Status: "Ready"},
+		{Name: "node-a", Status: "Ready"},
+		{Name: "node-b", Status: "NotReady"},
+	}
+	dmA := &domain.DecisionMakerPod{NodeID: "node-a", Host: "10.0.0.1", Port: 8080, State: domain.NodeStateOnline}
+	dmB := &domain.DecisionMakerPod{NodeID: "node-b", Host: "10.0.0.2", Port: 8080, State: domain.NodeStateOffline}
+	dmC := &domain.DecisionMakerPod{NodeID: "node-c", Host: "10.0.0.3", Port: 8080, State: domain.NodeStateOnline}
+
+	mockK8S.EXPECT().ListNodes(mock.Anything).Return(nodes, nil).Twice()
+	mockK8S.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).
+		Return([]*domain.DecisionMakerPod{dmA, dmB, dmC}, nil).Twice()
+	mockDM.EXPECT().GetMetrics(mock.Anything, dmA).
+		Return(&domain.MetricSample{Timestamp: time.Now(), UserSchedLastRunAt: 100, NrQueued: 2, NrOnlineCPUs: 4, NrFailedDispatches: 1}, nil).Once()
+	mockDM.EXPECT().GetMetrics(mock.Anything, dmC).
+		Return(&domain.MetricSample{Timestamp: time.Now().Add(-time.Minute), UserSchedLastRunAt: 50, NrQueued: 3, NrOnlineCPUs: 8}, nil).Once()
+
+	svc := &Service{K8SAdapter: mockK8S, DMAdapter: mockDM, nodeMetricsTracker: newNodeMetricsTracker()}
+	result, err := svc.GetClusterMetrics(context.Background(), 0)
+	require.NoError(t, err)
+	require.Len(t, result.Nodes, 3)
+	assert.Equal(t, "node-a", result.Nodes[0].Node.Name)
+	assert.Empty(t, result.Nodes[0].Outliers)
+	assert.Equal(t, []string{domain.OutlierUnreachable}, result.Nodes[1].Outliers)
+	assert.Equal(t, []string{domain.OutlierUserSchedStale}, result.Nodes[2].Outliers)
+	assert.Equal(t, 2, result.Totals.Nodes)
+	assert.Equal(t, uint64(5), result.Totals.NrQueued)
+	assert.Equal(t, uint64(12), result.Totals.NrOnlineCPUs)
+
+	// failed dispatches climbed on node-a, node-c stopped answering
+	mockDM.EXPECT().GetMetrics(mock.Anything, dmA).
+		Return(&domain.MetricSample{Timestamp: time.Now(), UserSchedLastRunAt: 200, NrFailedDispatches: 4}, nil).Once()
+	mockDM.EXPECT().GetMetrics(mock.Anything, dmC).Return(nil, errors.New("connection refused")).Once()
+
+	result, err = svc.GetClusterMetrics(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), result.Nodes[0].FailedDispatchesDelta)
+	assert.Equal(t, []string{domain.OutlierFailedDispatchesClimbing}, result.Nodes[0].Outliers)
+	assert.Equal(t, "connection refused", result.Nodes[2].Error)
+	assert.Equal(t, []string{domain.OutlierUnreachable}, result.Nodes[2].Outliers)
+	assert.Equal(t, 1, result.Totals.Nodes)
+}
+
+func TestNodeMetricsTrackerDetectsStalledScheduler(t *testing.T) {
+	tracker := newNodeMetricsTracker()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	sample := &domain.MetricSample{UserSchedLastRunAt: 100, NrFailedDispatches: 5}
+
+	seen, delta := tracker.Observe("node-a", start, sample)
+	assert.Equal(t, start, seen)
+	assert.Zero(t, delta)
+
+	seen, _ = tracker.Observe("node-a", start.Add(time.Minute), sample)
+	assert.Equal(t, start, seen, "an unchanged last run keeps its first observation time")
+
+	// counters reset by a scheduler restart are not reported as climbing
+	seen, delta = tracker.Observe("node-a", start.Add(2*time.Minute), &domain.MetricSample{UserSchedLastRunAt: 10, NrFailedDispatches: 1})
+	assert.Equal(t, start.Add(2*time.Minute), seen)
+	assert.Zero(t, delta)
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/manager/errs"
+	"github.com/Gthulhu/api/pkg/logger"
+	"github.com/Gthulhu/api/pkg/oidc"
+	"github.com/Gthulhu/api/pkg/util"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultOIDCLoginTimeout = 10 * time.Minute
+	defaultOIDCGroupsClaim  = "groups"
+	// oidcSessionAudience keeps login sessions from being accepted as access tokens and vice versa
+	oidcSessionAudience = "gthulhu-oidc-session"
+)
+
+// oidcLogin holds the single sign-on configuration and the client of the identity provider
+type oidcLogin struct {
+	cfg      config.OIDCConfig
+	provider *oidc.Provider
+}
+
+// newOIDCLogin returns nil when single sign-on is disabled
+func newOIDCLogin(cfg config.OIDCConfig) *oidcLogin {
+	if !cfg.Enable {
+		return nil
+	}
+	return &oidcLogin{
+		cfg: cfg,
+		provider: oidc.NewProvider(oidc.Config{
+			IssuerURL:    cfg.IssuerURL,
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret.Value(),
+			RedirectURL:  cfg.RedirectURL,
+			Scopes:       cfg.Scopes,
+		}),
+	}
+}
+
+func (o *oidcLogin) loginTimeout() time.Duration {
+	if o.cfg.LoginTimeoutSec > 0 {
+		return time.Duration(o.cfg.LoginTimeoutSec) * time.Second
+	}
+	return defaultOIDCLoginTimeout
+}
+
+// oidcSessionClaims is the signed state of a login between its start and the provider callback
+type oidcSessionClaims struct {
+	State        string `json:"state"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"cv"`
+	jwt.RegisteredClaims
+}
+
+func errOIDCDisabled() error {
+	return errs.NewHTTPStatusError(http.StatusNotFound, "single sign-on is not enabled", nil)
+}
+
+// BeginOIDCLogin creates the authorization request of a single sign-on login
+func (svc *Service) BeginOIDCLogin(ctx context.Context) (*domain.OIDCAuthRequest, error) {
+	if svc.oidcLogin == nil {
+		return nil, errOIDCDisabled()
+	}
+	state, err := oidc.GenerateState()
+	if err != nil {
+		return nil, fmt.Errorf("generate state: %w", err)
+	}
+	nonce, err := oidc.GenerateState()
+	if err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	verifier, err := oidc.GenerateVerifier()
+	if err != nil {
+		return nil, fmt.Errorf("generate code verifier: %w", err)
+	}
+	authURL, err := svc.oidcLogin.provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return nil, errs.NewHTTPStatusError(http.StatusBadGateway, "identity provider unavailable", err)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(svc.oidcLogin.loginTimeout())
+	signed, err := svc.signJWT(oidcSessionClaims{
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{oidcSessionAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sign login session: %w", err)
+	}
+	return &domain.OIDCAuthRequest{AuthURL: authURL, Session: signed, ExpiresAt: expiresAt}, nil
+}
+
+// CompleteOIDCLogin redeems the authorization code of the callback, provisions the user on the
+// first login, syncs the mapped roles and issues a token pair
+func (svc *Service) CompleteOIDCLogin(ctx context.Context, callback domain.OIDCCallback) (*domain.OIDCLogin, error) {
+	if svc.oidcLogin == nil {
+		return nil, errOIDCDisabled()
+	}
+	if callback.Error != "" {
+		return nil, errs.NewHTTPStatusError(http.StatusUnauthorized, "single sign-on failed", fmt.Errorf("identity provider returned %s: %s", callback.Error, callback.ErrorDescription))
+	}
+	session := &oidcSessionClaims{}
+	_, err := jwt.ParseWithClaims(callback.Session, session, svc.jwtKeyFunc, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}), jwt.WithAudience(oidcSessionAudience), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, errs.NewHTTPStatusError(http.StatusUnauthorized, "invalid or expired login session", err)
+	}
+	if callback.State == "" || subtle.ConstantTimeCompare([]byte(callback.State), []byte(session.State)) != 1 {
+		return nil, errs.NewHTTPStatusError(http.StatusUnauthorized, "invalid login state", errors.New("state does not match the login session"))
+	}
+
+	provider := svc.oidcLogin.provider
+	token, err := provider.Exchange(ctx, callback.Code, session.CodeVerifier)
+	if err != nil {
+		return nil, errs.NewHTTPStatusError(http.StatusUnauthorized, "single sign-on failed", err)
+	}
+	idToken, err := provider.VerifyIDToken(ctx, token.IDToken, session.Nonce)
+	if err != nil {
+		return nil, errs.NewHTTPStatusError(http.StatusUnauthorized, "single sign-on failed", err)
+	}
+
+	user, err := svc.provisionOIDCUser(ctx, idToken)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := svc.issueTokens(ctx, user, "")
+	if err != nil {
+		return nil, err
+	}
+	return &domain.OIDCLogin{Tokens: tokens, RedirectURL: svc.oidcLogin.cfg.PostLoginRedirectURL}, nil
+}
+
+// provisionOIDCUser returns the user of idToken, creating it on the first login. The roles of the
+// user are replaced by the roles mapped from the token on every login.
+func (svc *Service) provisionOIDCUser(ctx context.Context, idToken *oidc.IDToken) (*domain.User, error) {
+	roles, err := svc.mapOIDCRoles(ctx, idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	query := &domain.QueryUserOptions{ExternalIDs: []string{idToken.Subject}}
+	if err := svc.Repo.QueryUsers(ctx, query); err != nil {
+		return nil, errors.WithMessagef(err, "db: query user of subject %s failed", idToken.Subject)
+	}
+	for _, user := range query.Result {
+		if user.IdentityProvider != idToken.Issuer {
+			continue
+		}
+		if user.Status == domain.UserStatusInactive {
+			return nil, errs.NewHTTPStatusError(http.StatusUnauthorized, "user is inactive", fmt.Errorf("user %s is inactive", user.ID.Hex()))
+		}
+		if !slices.Equal(user.Roles, roles) {
+			logger.Logger(ctx).Info().Msgf("sync roles of single sign-on user %s from %v to %v", user.UserName, user.Roles, roles)
+			user.Roles = roles
+			user.UpdatedTime = time.Now().UnixMilli()
+			if err := svc.updateUser(ctx, user); err != nil {
+				return nil, errors.WithMessagef(err, "db: update roles of user %s failed", user.UserName)
+			}
+		}
+		return user, nil
+	}
+
+	username := idToken.StringClaim(svc.oidcLogin.cfg.UsernameClaim)
+	if username == "" {
+		username = idToken.Subject
+	}
+	existing := &domain.QueryUserOptions{UserNames: []string{username}}
+	if err := svc.Repo.QueryUsers(ctx, existing); err != nil {
+		return nil, errors.WithMessagef(err, "db: query user %s failed", username)
+	}
+	if len(existing.Result) > 0 {
+		return nil, errs.NewHTTPStatusError(http.StatusConflict, "username is already taken by another user", fmt.Errorf("user %s exists and is not linked to subject %s of %s", username, idToken.Subject, idToken.Issuer))
+	}
+	// the password is never disclosed, it only satisfies the users collection schema
+	password, err := util.RandomHex(32)
+	if err != nil {
+		return nil, fmt.Errorf("generate password: %w", err)
+	}
+	user := &domain.User{
+		BaseEntity:       domain.NewBaseEntity(nil, nil),
+		UserName:         username,
+		Password:         domain.EncryptedPassword(password),
+		Status:           domain.UserStatusActive,
+		Roles:            roles,
+		IdentityProvider: idToken.Issuer,
+		ExternalID:       idToken.Subject,
+	}
+	if err := svc.Repo.CreateUser(ctx, user); err != nil {
+		return nil, errors.WithMessagef(err, "db: create user %s failed", username)
+	}
+	logger.Logger(ctx).Info().Msgf("provisioned single sign-on user %s with roles %v", username, roles)
+	return user, nil
+}
+
+// mapOIDCRoles returns the existing roles mapped from the claims of idToken, DefaultRoles when no
+// mapping matches. A user without roles is denied.
+func (svc *Service) mapOIDCRoles(ctx context.Context, idToken *oidc.IDToken) ([]string, error) {
+	cfg := svc.oidcLogin.cfg
+	var names []string
+	for _, mapping := range cfg.RoleMappings {
+		claim := mapping.Claim
+		if claim == "" {
+			claim = cfg.GroupsClaim
+		}
+		if claim == "" {
+			claim = defaultOIDCGroupsClaim
+		}
+		if !slices.Contains(oidcClaimValues(idToken.Claims, claim), mapping.Value) {
+			continue
+		}
+		for _, role := range mapping.Roles {
+			if !slices.Contains(names, role) {
+				names = append(names, role)
+			}
+		}
+	}
+	if len(names) == 0 {
+		names = slices.Clone(cfg.DefaultRoles)
+	}
+
+	roles := []string{}
+	if len(names) > 0 {
+		query := &domain.QueryRoleOptions{Names: names}
+		if err := svc.Repo.QueryRoles(ctx, query); err != nil {
+			return nil, errors.WithMessage(err, "db: query roles failed")
+		}
+		for _, name := range names {
+			if slices.ContainsFunc(query.Result, func(role *domain.Role) bool { return role.Name == name }) {
+				roles = append(roles, name)
+			} else {
+				logger.Logger(ctx).Warn().Msgf("role %s mapped from single sign-on claims does not exist", name)
+			}
+		}
+	}
+	if len(roles) == 0 {
+		return nil, errs.NewHTTPStatusError(http.StatusForbidden, "no role is mapped to the user", fmt.Errorf("no role mapped for subject %s of %s", idToken.Subject, idToken.Issuer))
+	}
+	return roles, nil
+}
+
+// oidcClaimValues returns the claim as strings, dots in name address nested objects such as
+// realm_access.roles
+func oidcClaimValues(claims map[string]any, name string) []string {
+	value, ok := claims[name]
+	if !ok {
+		parent, child, found := strings.Cut(name, ".")
+		nested, isObject := claims[parent].(map[string]any)
+		if !found || !isObject {
+			return nil
+		}
+		return oidcClaimValues(nested, child)
+	}
+	var values []string
+	var appendValue func(any)
+	appendValue = func(v any) {
+		switch v := v.(type) {
+		case string:
+			values = append(values, v)
+		case bool:
+			values = append(values, strconv.FormatBool(v))
+		case float64:
+			values = append(values, strconv.FormatFloat(v, 'f', -1, 64))
+		case []any:
+			for _, item := range v {
+				appendValue(item)
+			}
+		}
+	}
+	appendValue(value)
+	return values
+}
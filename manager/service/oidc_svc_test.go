@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/pkg/oidc/oidctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type oidcTestEnv struct {
+	svc   *Service
+	idp   *oidctest.Server
+	users []*domain.User
+}
+
+func newOIDCTestEnv(t *testing.T) *oidcTestEnv {
+	idp := oidctest.NewServer("gthulhu", "s3cret")
+	t.Cleanup(idp.Close)
+
+	env := &oidcTestEnv{idp: idp}
+	mockRepo := domain.NewMockRepository(t)
+	mockRepo.EXPECT().QueryUsers(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt *domain.QueryUserOptions) {
+			opt.Result = nil
+			for _, user := range env.users {
+				if slices.Contains(opt.IDs, user.ID) || slices.Contains(opt.UserNames, user.UserName) ||
+					(user.ExternalID != "" && slices.Contains(opt.ExternalIDs, user.ExternalID)) {
+					copied := *user
+					opt.Result = append(opt.Result, &copied)
+				}
+			}
+		}).Return(nil).Maybe()
+	mockRepo.EXPECT().CreateUser(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, user *domain.User) {
+			user.ID = bson.NewObjectID()
+			copied := *user
+			env.users = append(env.users, &copied)
+		}).Return(nil).Maybe()
+	mockRepo.EXPECT().UpdateUser(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, user *domain.User) {
+			for i, existing := range env.users {
+				if existing.ID == user.ID {
+					copied := *user
+					env.users[i] = &copied
+				}
+			}
+		}).Return(nil).Maybe()
+	mockRepo.EXPECT().QueryRoles(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt *domain.QueryRoleOptions) {
+			opt.Result = nil
+			for _, name := range []string{"admin", "viewer"} {
+				if slices.Contains(opt.Names, name) {
+					opt.Result = append(opt.Result, &domain.Role{Name: name, Policies: []domain.RolePolicy{{PermissionKey: domain.ScheduleStrategyRead}}})
+				}
+			}
+		}).Return(nil).Maybe()
+	mockRepo.EXPECT().CreateRefreshToken(mock.Anything, mock.Anything).Return(nil).Maybe()
+	mockRepo.EXPECT().IsAccessTokenRevoked(mock.Anything, mock.Anything).Return(false, nil).Maybe()
+
+	env.svc = &Service{
+		Repo:          mockRepo,
+		jwtPrivateKey: testRSAKey(t),
+		oidcLogin: newOIDCLogin(config.OIDCConfig{
+			Enable:        true,
+			IssuerURL:     idp.Issuer(),
+			ClientID:      "gthulhu",
+			ClientSecret:  "s3cret",
+			RedirectURL:   "http://manager.local/api/v1/auth/oidc/callback",
+			UsernameClaim: "preferred_username",
+			RoleMappings: []config.OIDCRoleMapping{
+				{Value: "gthulhu-admins", Roles: []string{"admin"}},
+				{Claim: "realm_access.roles", Value: "observer", Roles: []string{"viewer", "missing"}},
+			},
+			PostLoginRedirectURL: "/",
+		}),
+	}
+	return env
+}
+
+// login runs the whole authorization code flow for the user with claims
+func (env *oidcTestEnv) login(t *testing.T, claims map[string]any) (*domain.OIDCLogin, error) {
+	env.idp.SetClaims(claims)
+	authRequest, err := env.svc.BeginOIDCLogin(context.Background())
+	require.NoError(t, err)
+	code, state, err := env.idp.Authorize(authRequest.AuthURL)
+	require.NoError(t, err)
+	return env.svc.CompleteOIDCLogin(context.Background(), domain.OIDCCallback{Session: authRequest.Session, State: state, Code: code})
+}
+
+func TestOIDCLoginProvisionsUser(t *testing.T) {
+	env := newOIDCTestEnv(t)
+	login, err := env.login(t, map[string]any{"sub": "42", "preferred_username": "alice", "groups": []string{"gthulhu-admins", "dev"}})
+	require.NoError(t, err)
+	assert.Equal(t, "/", login.RedirectURL)
+
+	require.Len(t, env.users, 1)
+	user := env.users[0]
+	assert.Equal(t, "alice", user.UserName)
+	assert.Equal(t, env.idp.Issuer(), user.IdentityProvider)
+	assert.Equal(t, "42", user.ExternalID)
+	assert.Equal(t, []string{"admin"}, user.Roles)
+	assert.Equal(t, domain.UserStatusActive, user.Status)
+
+	claims, _, err := env.svc.VerifyJWTToken(context.Background(), login.Tokens.AccessToken, domain.ScheduleStrategyRead)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID.Hex(), claims.UID)
+
+	_, err = env.svc.Login(context.Background(), "alice", "anything", "")
+	requireHTTPStatus(t, err, http.StatusUnauthorized)
+}
+
+func TestOIDCLoginSyncsRoles(t *testing.T) {
+	env := newOIDCTestEnv(t)
+	_, err := env.login(t, map[string]any{"sub": "42", "preferred_username": "alice", "groups": "gthulhu-admins"})
+	require.NoError(t, err)
+
+	// the username claim changed but the subject links the same user, missing roles are skipped
+	_, err = env.login(t, map[string]any{"sub": "42", "preferred_username": "alice2", "realm_access": map[string]any{"roles": []string{"observer"}}})
+	require.NoError(t, err)
+	require.Len(t, env.users, 1)
+	assert.Equal(t, "alice", env.users[0].UserName)
+	assert.Equal(t, []string{"viewer"}, env.users[0].Roles)
+}
+
+func TestOIDCLoginDenied(t *testing.T) {
+	env := newOIDCTestEnv(t)
+
+	_, err := env.login(t, map[string]any{"sub": "1", "preferred_username": "bob", "groups": []string{"dev"}})
+	requireHTTPStatus(t, err, http.StatusForbidden)
+	assert.Empty(t, env.users)
+
+	env.users = append(env.users, &domain.User{BaseEntity: domain.BaseEntity{ID: bson.NewObjectID()}, UserName: "bob", Status: domain.UserStatusActive})
+	_, err = env.login(t, map[string]any{"sub": "1", "preferred_username": "bob", "groups": []string{"gthulhu-admins"}})
+	requireHTTPStatus(t, err, http.StatusConflict)
+
+	_, err = env.login(t, map[string]any{"sub": "2", "preferred_username": "carol", "groups": []string{"gthulhu-admins"}})
+	require.NoError(t, err)
+	env.users[len(env.users)-1].Status = domain.UserStatusInactive
+	_, err = env.login(t, map[string]any{"sub": "2", "preferred_username": "carol", "groups": []string{"gthulhu-admins"}})
+	requireHTTPStatus(t, err, http.StatusUnauthorized)
+}
+
+func TestOIDCCallbackValidation(t *testing.T) {
+	env := newOIDCTestEnv(t)
+	ctx := context.Background()
+	env.idp.SetClaims(map[string]any{"sub": "42", "groups": "gthulhu-admins"})
+	authRequest, err := env.svc.BeginOIDCLogin(ctx)
+	require.NoError(t, err)
+	code, state, err := env.idp.Authorize(authRequest.AuthURL)
+	require.NoError(t, err)
+
+	_, err = env.svc.CompleteOIDCLogin(ctx, domain.OIDCCallback{Session: authRequest.Session, State: "forged", Code: code})
+	requireHTTPStatus(t, err, http.StatusUnauthorized)
+	_, err = env.svc.CompleteOIDCLogin(ctx, domain.OIDCCallback{Session: authRequest.Session + "x", State: state, Code: code})
+	requireHTTPStatus(t, err, http.StatusUnauthorized)
+	_, err = env.svc.CompleteOIDCLogin(ctx, domain.OIDCCallback{State: state, Code: code})
+	requireHTTPStatus(t, err, http.StatusUnauthorized)
+	_, err = env.svc.CompleteOIDCLogin(ctx, domain.OIDCCallback{Session: authRequest.Session, State: state, Error: "access_denied"})
+	requireHTTPStatus(t, err, http.StatusUnauthorized)
+
+	// a session started by another browser can't redeem the code
+	other, err := env.svc.BeginOIDCLogin(ctx)
+	require.NoError(t, err)
+	_, err = env.svc.CompleteOIDCLogin(ctx, domain.OIDCCallback{Session: other.Session, State: state, Code: code})
+	requireHTTPStatus(t, err, http.StatusUnauthorized)
+
+	// login sessions are not access tokens
+	_, _, err = env.svc.VerifyJWTToken(ctx, authRequest.Session, "")
+	require.Error(t, err)
+
+	login, err := env.svc.CompleteOIDCLogin(ctx, domain.OIDCCallback{Session: authRequest.Session, State: state, Code: code})
+	require.NoError(t, err)
+	assert.NotEmpty(t, login.Tokens.RefreshToken)
+}
+
+func TestOIDCDisabled(t *testing.T) {
+	svc := &Service{}
+	_, err := svc.BeginOIDCLogin(context.Background())
+	requireHTTPStatus(t, err, http.StatusNotFound)
+	_, err = svc.CompleteOIDCLogin(context.Background(), domain.OIDCCallback{})
+	requireHTTPStatus(t, err, http.StatusNotFound)
+}
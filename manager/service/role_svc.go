@@ -16,8 +16,16 @@ func (svc *Service) CreateRole(ctx context.Context, operator *domain.Claims, rol
 	if err != nil {
 		return errs.NewHTTPStatusError(http.StatusUnauthorized, "unauthorized", fmt.Errorf("invalid user ID"))
 	}
+	if role.RequireTwoFactor && len(svc.twoFactorKey) == 0 {
+		return errTwoFactorDisabled()
+	}
 	role.BaseEntity = domain.NewBaseEntity(&operatorID, &operatorID)
-	return svc.Repo.CreateRole(ctx, role)
+	if err := svc.Repo.CreateRole(ctx, role); err != nil {
+		return err
+	}
+	// unknown role names are cached as missing
+	svc.invalidateAuthCache(ctx, true)
+	return nil
 }
 
 func (svc *Service) UpdateRole(ctx context.Context, operator *domain.Claims, roleID string, opt domain.UpdateRoleOptions) error {
@@ -39,6 +47,12 @@ func (svc *Service) UpdateRole(ctx context.Context, operator *domain.Claims, rol
 	if opt.Description != nil {
 		role.Description = *opt.Description
 	}
+	if opt.RequireTwoFactor != nil {
+		if *opt.RequireTwoFactor && len(svc.twoFactorKey) == 0 {
+			return errTwoFactorDisabled()
+		}
+		role.RequireTwoFactor = *opt.RequireTwoFactor
+	}
 	if opt.Policies != nil {
 		role.Policies = []domain.RolePolicy{}
 		for _, p := range *opt.Policies {
@@ -51,11 +65,50 @@ func (svc *Service) UpdateRole(ctx context.Context, operator *domain.Claims, rol
 		}
 	}
 	role.UpdaterID = operatorID
-	return svc.Repo.UpdateRole(ctx, role)
+	if err := svc.Repo.UpdateRole(ctx, role); err != nil {
+		return err
+	}
+	svc.invalidateAuthCache(ctx, true)
+	return nil
 }
 
 func (svc *Service) DeleteRole(ctx context.Context, operator *domain.Claims, roleID string) error {
-	return fmt.Errorf("not implemented")
+	roleObjID, err := bson.ObjectIDFromHex(roleID)
+	if err != nil {
+		return errs.NewHTTPStatusError(http.StatusBadRequest, "invalid role ID", err)
+	}
+	roles, err := svc.getRolesByIDs(ctx, []string{roleID})
+	if err != nil {
+		return err
+	}
+	if len(roles) == 0 {
+		return errs.NewHTTPStatusError(http.StatusNotFound, "role not found", fmt.Errorf("role with ID %s not found", roleID))
+	}
+	role := roles[0]
+	if role.Name == domain.AdminRole {
+		return errs.NewHTTPStatusError(http.StatusConflict, "the admin role cannot be deleted", fmt.Errorf("role %s is the admin role", roleID))
+	}
+	userQuery := &domain.QueryUserOptions{Roles: []string{role.Name}}
+	if err := svc.Repo.QueryUsers(ctx, userQuery); err != nil {
+		return err
+	}
+	accountQuery := &domain.QueryServiceAccountOptions{Roles: []string{role.Name}}
+	if err := svc.Repo.QueryServiceAccounts(ctx, accountQuery); err != nil {
+		return err
+	}
+	if holders := len(userQuery.Result) + len(accountQuery.Result); holders > 0 {
+		return errs.NewHTTPStatusError(http.StatusConflict, "role is still assigned, unassign it first", fmt.Errorf("role %s is held by %d users and service accounts", role.Name, holders))
+	}
+	err = svc.Repo.DeleteRole(ctx, roleObjID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return errs.NewHTTPStatusError(http.StatusNotFound, "role not found", err)
+	}
+	if err != nil {
+		return err
+	}
+	// cached lookups of the role name must not keep authorizing until RoleTTLSec
+	svc.invalidateAuthCache(ctx, true)
+	return nil
 }
 
 func (svc *Service) QueryRoles(ctx context.Context, opt *domain.QueryRoleOptions) error {
@@ -97,6 +150,6 @@ func (svc *Service) getRolesByIDs(ctx context.Context, roleIDs []string) ([]*dom
 	return opts.Result, nil
 }
 
-func (svc Service) QueryPermissions(ctx context.Context, opt *domain.QueryPermissionOptions) error {
+func (svc *Service) QueryPermissions(ctx context.Context, opt *domain.QueryPermissionOptions) error {
 	return svc.Repo.QueryPermissions(ctx, opt)
 }
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestDeleteRole(t *testing.T) {
+	role := &domain.Role{BaseEntity: domain.BaseEntity{ID: bson.NewObjectID()}, Name: "operator"}
+	holders := []*domain.User{{UserName: "alice", Roles: []string{"operator"}}}
+	mockRepo := domain.NewMockRepository(t)
+	mockRepo.EXPECT().QueryRoles(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt *domain.QueryRoleOptions) {
+			opt.Result = []*domain.Role{role}
+		}).Return(nil).Maybe()
+	mockRepo.EXPECT().QueryUsers(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt *domain.QueryUserOptions) {
+			assert.Equal(t, []string{role.Name}, opt.Roles)
+			opt.Result = holders
+		}).Return(nil).Maybe()
+	mockRepo.EXPECT().QueryServiceAccounts(mock.Anything, mock.Anything).Return(nil).Maybe()
+	mockRepo.EXPECT().IncrementCacheVersion(mock.Anything, authCacheVersionName).Return(1, nil).Maybe()
+	svc := &Service{Repo: mockRepo, authCache: NewAuthCache(config.AuthCacheConfig{Enable: true})}
+	ctx := context.Background()
+	operator := &domain.Claims{UID: bson.NewObjectID().Hex()}
+
+	requireHTTPStatus(t, svc.DeleteRole(ctx, operator, "not-an-id"), http.StatusBadRequest)
+	requireHTTPStatus(t, svc.DeleteRole(ctx, operator, role.ID.Hex()), http.StatusConflict)
+
+	holders = nil
+	now := time.Now()
+	svc.authCache.setRoles([]string{role.Name}, []*domain.Role{role}, svc.authCache.currentGeneration(), now)
+	mockRepo.EXPECT().DeleteRole(mock.Anything, role.ID).Return(nil).Once()
+	require.NoError(t, svc.DeleteRole(ctx, operator, role.ID.Hex()))
+	_, missing := svc.authCache.getRoles([]string{role.Name}, now)
+	assert.Equal(t, []string{role.Name}, missing, "a deleted role must not keep authorizing from the cache")
+	mockRepo.AssertCalled(t, "IncrementCacheVersion", mock.Anything, authCacheVersionName)
+
+	mockRepo.EXPECT().DeleteRole(mock.Anything, role.ID).Return(domain.ErrNotFound).Once()
+	requireHTTPStatus(t, svc.DeleteRole(ctx, operator, role.ID.Hex()), http.StatusNotFound)
+
+	role.Name = domain.AdminRole
+	requireHTTPStatus(t, svc.DeleteRole(ctx, operator, role.ID.Hex()), http.StatusConflict)
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/manager/errs"
+	"github.com/Gthulhu/api/pkg/logger"
+	"github.com/Gthulhu/api/pkg/util"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	// defaultAPIKeyTTL applies to API keys created without an expiry
+	defaultAPIKeyTTL = 90 * 24 * time.Hour
+	apiKeyIDBytes    = 8
+	apiKeySecretByte = 32
+	// apiKeyTouchInterval limits how often the last use of an API key is written
+	apiKeyTouchInterval = time.Minute
+)
+
+func (svc *Service) CreateServiceAccount(ctx context.Context, operator *domain.Claims, account *domain.ServiceAccount) error {
+	operatorID, err := operator.GetBsonObjectUID()
+	if err != nil {
+		return errs.NewHTTPStatusError(http.StatusUnauthorized, "unauthorized", fmt.Errorf("invalid user ID"))
+	}
+	if account.Name == "" {
+		return errs.NewHTTPStatusError(http.StatusBadRequest, "name is required", nil)
+	}
+	if err := svc.checkRolesExist(ctx, account.Roles); err != nil {
+		return err
+	}
+	queryOpt := &domain.QueryServiceAccountOptions{Names: []string{account.Name}}
+	if err := svc.Repo.QueryServiceAccounts(ctx, queryOpt); err != nil {
+		return err
+	}
+	if len(queryOpt.Result) > 0 {
+		return errs.NewHTTPStatusError(http.StatusConflict, "service account name already exists", fmt.Errorf("service account %s exists", account.Name))
+	}
+	account.BaseEntity = domain.NewBaseEntity(&operatorID, &operatorID)
+	return svc.Repo.CreateServiceAccount(ctx, account)
+}
+
+func (svc *Service) UpdateServiceAccount(ctx context.Context, operator *domain.Claims, accountID string, opt domain.UpdateServiceAccountOptions) error {
+	operatorID, err := operator.GetBsonObjectUID()
+	if err != nil {
+		return errs.NewHTTPStatusError(http.StatusUnauthorized, "unauthorized", fmt.Errorf("invalid user ID"))
+	}
+	account, err := svc.getServiceAccountByID(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	if opt.Roles != nil {
+		if err := svc.checkRolesExist(ctx, *opt.Roles); err != nil {
+			return err
+		}
+		account.Roles = *opt.Roles
+	}
+	if opt.Description != nil {
+		account.Description = *opt.Description
+	}
+	if opt.Disabled != nil {
+		account.Disabled = *opt.Disabled
+	}
+	account.UpdaterID = operatorID
+	return svc.Repo.UpdateServiceAccount(ctx, account)
+}
+
+// DeleteServiceAccount deletes the service account together with its API keys
+func (svc *Service) DeleteServiceAccount(ctx context.Context, operator *domain.Claims, accountID string) error {
+	accountObjID, err := bson.ObjectIDFromHex(accountID)
+	if err != nil {
+		return errs.NewHTTPStatusError(http.StatusBadRequest, "invalid service account ID", err)
+	}
+	err = svc.Repo.DeleteServiceAccount(ctx, accountObjID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return errs.NewHTTPStatusError(http.StatusNotFound, "service account not found", err)
+	}
+	if err != nil {
+		return err
+	}
+	// keys left behind by a failure here are unusable since their service account is gone
+	return svc.Repo.DeleteAPIKeysByServiceAccountID(ctx, accountID)
+}
+
+func (svc *Service) QueryServiceAccounts(ctx context.Context, opt *domain.QueryServiceAccountOptions) error {
+	return svc.Repo.QueryServiceAccounts(ctx, opt)
+}
+
+// CreateAPIKey issues a key for key.ServiceAccountID and returns it in plain text, only its hash is stored
+func (svc *Service) CreateAPIKey(ctx context.Context, operator *domain.Claims, key *domain.APIKey) (string, error) {
+	operatorID, err := operator.GetBsonObjectUID()
+	if err != nil {
+		return "", errs.NewHTTPStatusError(http.StatusUnauthorized, "unauthorized", fmt.Errorf("invalid user ID"))
+	}
+	if _, err := svc.getServiceAccountByID(ctx, key.ServiceAccountID); err != nil {
+		return "", err
+	}
+	slices.Sort(key.Scopes)
+	key.Scopes = slices.Compact(key.Scopes)
+	if err := svc.checkPermissionsExist(ctx, key.Scopes); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	if key.ExpiresAt == 0 {
+		key.ExpiresAt = now.Add(defaultAPIKeyTTL).UnixMilli()
+	}
+	if key.ExpiresAt <= now.UnixMilli() {
+		return "", errs.NewHTTPStatusError(http.StatusBadRequest, "expiresAt must be in the future", nil)
+	}
+
+	keyID, err := util.RandomHex(apiKeyIDBytes)
+	if err != nil {
+		return "", fmt.Errorf("generate API key ID: %w", err)
+	}
+	secret, err := util.RandomHex(apiKeySecretByte)
+	if err != nil {
+		return "", fmt.Errorf("generate API key secret: %w", err)
+	}
+	secretHash, err := util.CreateArgon2Hash(secret)
+	if err != nil {
+		return "", fmt.Errorf("hash API key secret: %w", err)
+	}
+	key.KeyID = keyID
+	key.SecretHash = domain.EncryptedPassword(secretHash)
+	key.RevokedAt = 0
+	key.LastUsedAt = 0
+	key.BaseEntity = domain.NewBaseEntity(&operatorID, &operatorID)
+	if err := svc.Repo.CreateAPIKey(ctx, key); err != nil {
+		return "", err
+	}
+	return domain.FormatAPIKey(keyID, secret), nil
+}
+
+func (svc *Service) RevokeAPIKey(ctx context.Context, operator *domain.Claims, keyID string) error {
+	keyObjID, err := bson.ObjectIDFromHex(keyID)
+	if err != nil {
+		return errs.NewHTTPStatusError(http.StatusBadRequest, "invalid API key ID", err)
+	}
+	err = svc.Repo.RevokeAPIKey(ctx, keyObjID, time.Now().UnixMilli())
+	if errors.Is(err, domain.ErrNotFound) {
+		return errs.NewHTTPStatusError(http.StatusNotFound, "API key not found", err)
+	}
+	return err
+}
+
+func (svc *Service) QueryAPIKeys(ctx context.Context, opt *domain.QueryAPIKeyOptions) error {
+	return svc.Repo.QueryAPIKeys(ctx, opt)
+}
+
+// VerifyAPIKey authenticates a service account by one of its API keys and checks both the scopes
+// of the key and the role bindings of the service account grant permissionKey
+func (svc *Service) VerifyAPIKey(ctx context.Context, key string, permissionKey domain.PermissionKey) (domain.Claims, domain.RolePolicy, error) {
+	keyID, secret, err := domain.ParseAPIKey(key)
+	if err != nil {
+		return domain.Claims{}, domain.RolePolicy{}, errs.NewHTTPStatusError(http.StatusUnauthorized, "invalid API key", err)
+	}
+	queryOpt := &domain.QueryAPIKeyOptions{KeyIDs: []string{keyID}}
+	if err := svc.Repo.QueryAPIKeys(ctx, queryOpt); err != nil {
+		return domain.Claims{}, domain.RolePolicy{}, errors.WithMessage(err, "query API key failed")
+	}
+	if len(queryOpt.Result) == 0 {
+		return domain.Claims{}, domain.RolePolicy{}, errs.NewHTTPStatusError(http.StatusUnauthorized, "invalid API key", fmt.Errorf("API key %s not found", keyID))
+	}
+	apiKey := queryOpt.Result[0]
+	ok, err := apiKey.SecretHash.Cmp(secret)
+	if err != nil {
+		return domain.Claims{}, domain.RolePolicy{}, errors.WithMessagef(err, "compare secret of API key %s failed", keyID)
+	}
+	if !ok {
+		return domain.Claims{}, domain.RolePolicy{}, errs.NewHTTPStatusError(http.StatusUnauthorized, "invalid API key", fmt.Errorf("secret of API key %s not match", keyID))
+	}
+	now := time.Now()
+	if !apiKey.Active(now) {
+		return domain.Claims{}, domain.RolePolicy{}, errs.NewHTTPStatusError(http.StatusUnauthorized, "API key is revoked or expired", fmt.Errorf("API key %s is inactive", keyID))
+	}
+	account, err := svc.getServiceAccountByID(ctx, apiKey.ServiceAccountID)
+	if err != nil {
+		return domain.Claims{}, domain.RolePolicy{}, errs.NewHTTPStatusError(http.StatusUnauthorized, "invalid API key", err)
+	}
+	if account.Disabled {
+		return domain.Claims{}, domain.RolePolicy{}, errs.NewHTTPStatusError(http.StatusUnauthorized, "service account is disabled", fmt.Errorf("service account %s is disabled", account.Name))
+	}
+	if now.Sub(time.UnixMilli(apiKey.LastUsedAt)) >= apiKeyTouchInterval {
+		if err := svc.Repo.TouchAPIKey(ctx, apiKey.ID, now.UnixMilli()); err != nil {
+			logger.Logger(ctx).Warn().Err(err).Msgf("failed to record the use of API key %s", keyID)
+		}
+	}
+
+	claims := domain.Claims{
+		UID:            account.ID.Hex(),
+		ServiceAccount: true,
+	}
+	claims.Subject = account.ID.Hex()
+	claims.ID = apiKey.ID.Hex()
+	if permissionKey == "" {
+		return claims, domain.RolePolicy{}, nil
+	}
+	if !apiKey.Allows(permissionKey) {
+		return domain.Claims{}, domain.RolePolicy{}, errs.NewHTTPStatusError(http.StatusForbidden, "permission denied", fmt.Errorf("API key %s is not scoped to %s", keyID, permissionKey))
+	}
+	rolePolicy, err := svc.authorizeRoles(ctx, "service account "+account.Name, account.Roles, permissionKey)
+	if err != nil {
+		return domain.Claims{}, domain.RolePolicy{}, err
+	}
+	return claims, rolePolicy, nil
+}
+
+func (svc *Service) getServiceAccountByID(ctx context.Context, accountID string) (*domain.ServiceAccount, error) {
+	accountObjID, err := bson.ObjectIDFromHex(accountID)
+	if err != nil {
+		return nil, errs.NewHTTPStatusError(http.StatusBadRequest, "invalid service account ID", err)
+	}
+	queryOpt := &domain.QueryServiceAccountOptions{IDs: []bson.ObjectID{accountObjID}}
+	if err := svc.Repo.QueryServiceAccounts(ctx, queryOpt); err != nil {
+		return nil, err
+	}
+	if len(queryOpt.Result) == 0 {
+		return nil, errs.NewHTTPStatusError(http.StatusNotFound, "service account not found", fmt.Errorf("service account %s not found", accountID))
+	}
+	return queryOpt.Result[0], nil
+}
+
+func (svc *Service) checkRolesExist(ctx context.Context, roleNames []string) error {
+	if len(roleNames) == 0 {
+		return nil
+	}
+	roles, err := svc.getRolesByNames(ctx, roleNames)
+	if err != nil {
+		return err
+	}
+	if len(roles) != len(roleNames) {
+		return errs.NewHTTPStatusError(http.StatusBadRequest, "Some roles not found", errors.New("invalid role names"))
+	}
+	return nil
+}
+
+func (svc *Service) checkPermissionsExist(ctx context.Context, keys []domain.PermissionKey) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	queryOpt := &domain.QueryPermissionOptions{}
+	for _, key := range keys {
+		queryOpt.Keys = append(queryOpt.Keys, string(key))
+	}
+	if err := svc.Repo.QueryPermissions(ctx, queryOpt); err != nil {
+		return err
+	}
+	if len(queryOpt.Result) != len(keys) {
+		return errs.NewHTTPStatusError(http.StatusBadRequest, "Some scopes are not permissions", errors.New("invalid scopes"))
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/manager/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func requireHTTPStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	var httpErr *errs.HTTPStatusError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, status, httpErr.StatusCode)
+}
+
+// issueTestAPIKey creates a key through CreateAPIKey and returns the stored key with its plain text
+func issueTestAPIKey(t *testing.T, mockRepo *domain.MockRepository, svc *Service, account *domain.ServiceAccount, scopes ...domain.PermissionKey) (*domain.APIKey, string) {
+	t.Helper()
+	mockRepo.EXPECT().QueryServiceAccounts(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt *domain.QueryServiceAccountOptions) {
+			opt.Result = []*domain.ServiceAccount{account}
+		}).Return(nil).Once()
+	if len(scopes) > 0 {
+		mockRepo.EXPECT().QueryPermissions(mock.Anything, mock.Anything).
+			Run(func(ctx context.Context, opt *domain.QueryPermissionOptions) {
+				opt.Result = nil
+				for _, key := range opt.Keys {
+					opt.Result = append(opt.Result, &domain.Permission{Key: domain.PermissionKey(key)})
+				}
+			}).Return(nil).Once()
+	}
+	var stored *domain.APIKey
+	mockRepo.EXPECT().CreateAPIKey(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, key *domain.APIKey) {
+			key.ID = bson.NewObjectID()
+			stored = key
+		}).Return(nil).Once()
+
+	operator := &domain.Claims{UID: bson.NewObjectID().Hex()}
+	plain, err := svc.CreateAPIKey(context.Background(), operator, &domain.APIKey{
+		ServiceAccountID: account.ID.Hex(),
+		Name:             "deploy",
+		Scopes:           scopes,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	return stored, plain
+}
+
+func TestCreateAPIKeyStoresOnlyTheHash(t *testing.T) {
+	mockRepo := domain.NewMockRepository(t)
+	svc := &Service{Repo: mockRepo}
+	account := &domain.ServiceAccount{BaseEntity: domain.BaseEntity{ID: bson.NewObjectID()}, Name: "ci"}
+
+	stored, plain := issueTestAPIKey(t, mockRepo, svc, account, domain.ScheduleStrategyRead, domain.ScheduleStrategyRead)
+	keyID, secret, err := domain.ParseAPIKey(plain)
+	require.NoError(t, err)
+	assert.Equal(t, stored.KeyID, keyID)
+	assert.NotContains(t, string(stored.SecretHash), secret)
+	ok, err := stored.SecretHash.Cmp(secret)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []domain.PermissionKey{domain.ScheduleStrategyRead}, stored.Scopes)
+	assert.InDelta(t, time.Now().Add(defaultAPIKeyTTL).UnixMilli(), stored.ExpiresAt, float64(time.Minute.Milliseconds()))
+
+	mockRepo.EXPECT().QueryServiceAccounts(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt *domain.QueryServiceAccountOptions) {
+			opt.Result = []*domain.ServiceAccount{account}
+		}).Return(nil).Once()
+	_, err = svc.CreateAPIKey(context.Background(), &domain.Claims{UID: bson.NewObjectID().Hex()}, &domain.APIKey{
+		ServiceAccountID: account.ID.Hex(),
+		ExpiresAt:        time.Now().Add(-time.Hour).UnixMilli(),
+	})
+	requireHTTPStatus(t, err, http.StatusBadRequest)
+}
+
+func TestVerifyAPIKey(t *testing.T) {
+	account := &domain.ServiceAccount{BaseEntity: domain.BaseEntity{ID: bson.NewObjectID()}, Name: "ci", Roles: []string{"deployer"}}
+	role := &domain.Role{Name: "deployer", Policies: []domain.RolePolicy{
+		{PermissionKey: domain.ScheduleStrategyRead},
+		{PermissionKey: domain.ScheduleStrategyCreate, K8SNamespace: "ci"},
+	}}
+
+	tests := []struct {
+		name       string
+		scopes     []domain.PermissionKey
+		mutate     func(key *domain.APIKey, account *domain.ServiceAccount)
+		wrongKey   bool
+		permission domain.PermissionKey
+		wantStatus int
+	}{
+		{name: "granted by role", permission: domain.ScheduleStrategyCreate},
+		{name: "granted by scope and role", scopes: []domain.PermissionKey{domain.ScheduleStrategyCreate}, permission: domain.ScheduleStrategyCreate},
+		{name: "outside of scopes", scopes: []domain.PermissionKey{domain.ScheduleStrategyRead}, permission: domain.ScheduleStrategyCreate, wantStatus: http.StatusForbidden},
+		{name: "not granted by role", permission: domain.ScheduleStrategyDelete, wantStatus: http.StatusForbidden},
+		{name: "wrong secret", wrongKey: true, permission: domain.ScheduleStrategyRead, wantStatus: http.StatusUnauthorized},
+		{name: "revoked", mutate: func(key *domain.APIKey, _ *domain.ServiceAccount) { key.RevokedAt = time.Now().UnixMilli() },
+			permission: domain.ScheduleStrategyRead, wantStatus: http.StatusUnauthorized},
+		{name: "expired", mutate: func(key *domain.APIKey, _ *domain.ServiceAccount) { key.ExpiresAt = time.Now().UnixMilli() - 1 },
+			permission: domain.ScheduleStrategyRead, wantStatus: http.StatusUnauthorized},
+		{name: "disabled service account", mutate: func(_ *domain.APIKey, account *domain.ServiceAccount) { account.Disabled = true },
+			permission: domain.ScheduleStrategyRead, wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := domain.NewMockRepository(t)
+			svc := &Service{Repo: mockRepo}
+			account := *account
+			stored, plain := issueTestAPIKey(t, mockRepo, svc, &account, tt.scopes...)
+			if tt.mutate != nil {
+				tt.mutate(stored, &account)
+			}
+			if tt.wrongKey {
+				plain = domain.FormatAPIKey(stored.KeyID, "0000")
+			}
+
+			mockRepo.EXPECT().QueryAPIKeys(mock.Anything, mock.Anything).
+				Run(func(ctx context.Context, opt *domain.QueryAPIKeyOptions) {
+					assert.Equal(t, []string{stored.KeyID}, opt.KeyIDs)
+					opt.Result = []*domain.APIKey{stored}
+				}).Return(nil).Once()
+			mockRepo.EXPECT().QueryServiceAccounts(mock.Anything, mock.Anything).
+				Run(func(ctx context.Context, opt *domain.QueryServiceAccountOptions) {
+					opt.Result = []*domain.ServiceAccount{&account}
+				}).Return(nil).Maybe()
+			mockRepo.EXPECT().TouchAPIKey(mock.Anything, stored.ID, mock.Anything).Return(nil).Maybe()
+			mockRepo.EXPECT().QueryRoles(mock.Anything, mock.Anything).
+				Run(func(ctx context.Context, opt *domain.QueryRoleOptions) {
+					assert.Equal(t, []string{"deployer"}, opt.Names)
+					opt.Result = []*domain.Role{role}
+				}).Return(nil).Maybe()
+
+			claims, policy, err := svc.VerifyAPIKey(context.Background(), plain, tt.permission)
+			if tt.wantStatus != 0 {
+				requireHTTPStatus(t, err, tt.wantStatus)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, account.ID.Hex(), claims.UID)
+			assert.True(t, claims.ServiceAccount)
+			assert.Equal(t, tt.permission, policy.PermissionKey)
+		})
+	}
+}
+
+func TestVerifyAPIKeyRejectsMalformedKeys(t *testing.T) {
+	svc := &Service{Repo: domain.NewMockRepository(t)}
+	for _, key := range []string{"", "gth_", "gth_abc", "token"} {
+		_, _, err := svc.VerifyAPIKey(context.Background(), key, domain.ScheduleStrategyRead)
+		requireHTTPStatus(t, err, http.StatusUnauthorized)
+	}
+}
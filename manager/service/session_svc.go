@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/manager/errs"
+	"github.com/Gthulhu/api/pkg/logger"
+	"github.com/Gthulhu/api/pkg/util"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	defaultAccessTokenTTL  = 3 * time.Hour
+	defaultRefreshTokenTTL = 7 * 24 * time.Hour
+	tokenIDBytes           = 16
+	refreshTokenBytes      = 32
+)
+
+func (svc *Service) accessTokenTTL() time.Duration {
+	if svc.accessTTL > 0 {
+		return svc.accessTTL
+	}
+	return defaultAccessTokenTTL
+}
+
+func (svc *Service) refreshTokenTTL() time.Duration {
+	if svc.refreshTTL > 0 {
+		return svc.refreshTTL
+	}
+	return defaultRefreshTokenTTL
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// issueTokens signs an access token for user and stores a refresh token in familyID, a new family
+// is started when familyID is empty
+func (svc *Service) issueTokens(ctx context.Context, user *domain.User, familyID string) (*domain.TokenPair, error) {
+	jti, err := util.RandomHex(tokenIDBytes)
+	if err != nil {
+		return nil, fmt.Errorf("generate token ID: %w", err)
+	}
+	now := time.Now()
+	accessExpiresAt := now.Add(svc.accessTokenTTL())
+	accessToken, err := svc.genJWTToken(ctx, user, jti, now, accessExpiresAt)
+	if err != nil {
+		return nil, errors.WithMessage(err, "generate JWT token failed")
+	}
+
+	refreshToken, err := util.RandomHex(refreshTokenBytes)
+	if err != nil {
+		return nil, fmt.Errorf("generate refresh token: %w", err)
+	}
+	if familyID == "" {
+		familyID = bson.NewObjectID().Hex()
+	}
+	refreshExpiresAt := now.Add(svc.refreshTokenTTL())
+	err = svc.Repo.CreateRefreshToken(ctx, &domain.RefreshToken{
+		UserID:        user.ID,
+		TokenHash:     hashRefreshToken(refreshToken),
+		FamilyID:      familyID,
+		AccessTokenID: jti,
+		CreatedTime:   now.UnixMilli(),
+		ExpiresAt:     refreshExpiresAt,
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "db: create refresh token failed")
+	}
+	return &domain.TokenPair{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+// RefreshToken exchanges a refresh token for a new token pair, the presented token can't be used
+// again. Presenting a token that was already rotated revokes every token of its family, since
+// either the client or an attacker holds a stolen copy.
+func (svc *Service) RefreshToken(ctx context.Context, refreshToken string) (*domain.TokenPair, error) {
+	if refreshToken == "" {
+		return nil, errs.NewHTTPStatusError(http.StatusUnauthorized, "invalid refresh token", errors.New("empty refresh token"))
+	}
+	now := time.Now()
+	token, err := svc.Repo.UseRefreshToken(ctx, hashRefreshToken(refreshToken), now.UnixMilli())
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, errs.NewHTTPStatusError(http.StatusUnauthorized, "invalid refresh token", err)
+	}
+	if err != nil {
+		return nil, errors.WithMessage(err, "db: use refresh token failed")
+	}
+	if token.RevokedAt != 0 {
+		logger.Logger(ctx).Warn().Msgf("refresh token of family %s reused, revoking the family of user %s", token.FamilyID, token.UserID.Hex())
+		err := svc.Repo.RevokeRefreshTokens(ctx, domain.RevokeRefreshTokensOptions{FamilyIDs: []string{token.FamilyID}}, now.UnixMilli())
+		if err != nil {
+			return nil, errors.WithMessage(err, "db: revoke refresh token family failed")
+		}
+		return nil, errs.NewHTTPStatusError(http.StatusUnauthorized, "refresh token revoked", fmt.Errorf("refresh token of family %s already used", token.FamilyID))
+	}
+	if !now.Before(token.ExpiresAt) {
+		return nil, errs.NewHTTPStatusError(http.StatusUnauthorized, "refresh token expired", fmt.Errorf("refresh token of family %s expired", token.FamilyID))
+	}
+
+	user, err := svc.getUserByID(ctx, token.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Status == domain.UserStatusInactive {
+		return nil, errs.NewHTTPStatusError(http.StatusUnauthorized, "user is inactive", fmt.Errorf("user %s is inactive", user.ID.Hex()))
+	}
+	return svc.issueTokens(ctx, user, token.FamilyID)
+}
+
+// Logout revokes the access token of claims and its refresh token, refreshToken is optional
+func (svc *Service) Logout(ctx context.Context, claims *domain.Claims, refreshToken string) error {
+	if claims.ServiceAccount {
+		return errs.NewHTTPStatusError(http.StatusBadRequest, "API keys can't log out, revoke the key instead", nil)
+	}
+	uid, err := claims.GetBsonObjectUID()
+	if err != nil {
+		return errs.NewHTTPStatusError(http.StatusUnauthorized, "unauthorized", fmt.Errorf("invalid user ID"))
+	}
+	now := time.Now()
+	if claims.ID != "" {
+		revoked := &domain.RevokedToken{
+			JTI:       claims.ID,
+			UserID:    uid,
+			RevokedAt: now.UnixMilli(),
+			ExpiresAt: now.Add(svc.accessTokenTTL()),
+		}
+		if claims.ExpiresAt != nil {
+			revoked.ExpiresAt = claims.ExpiresAt.Time
+		}
+		if err := svc.Repo.RevokeAccessToken(ctx, revoked); err != nil {
+			return errors.WithMessage(err, "db: revoke access token failed")
+		}
+		svc.authCache.invalidateToken(claims.ID)
+		svc.invalidateAuthCache(ctx, false)
+		err = svc.Repo.RevokeRefreshTokens(ctx, domain.RevokeRefreshTokensOptions{UserID: uid, AccessTokenIDs: []string{claims.ID}}, now.UnixMilli())
+		if err != nil {
+			return errors.WithMessage(err, "db: revoke refresh token failed")
+		}
+	}
+	if refreshToken != "" {
+		err = svc.Repo.RevokeRefreshTokens(ctx, domain.RevokeRefreshTokensOptions{UserID: uid, TokenHashes: []string{hashRefreshToken(refreshToken)}}, now.UnixMilli())
+		if err != nil {
+			return errors.WithMessage(err, "db: revoke refresh token failed")
+		}
+	}
+	return nil
+}
+
+// RevokeUserSessions invalidates every access and refresh token issued to the user
+func (svc *Service) RevokeUserSessions(ctx context.Context, operator *domain.Claims, id string) error {
+	operatorID, err := operator.GetBsonObjectUID()
+	if err != nil {
+		return errors.WithMessagef(err, "invalid operator ID %s", operator.UID)
+	}
+	uid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return errs.NewHTTPStatusError(http.StatusUnprocessableEntity, "invalid user ID", fmt.Errorf("invalid user ID %s: %v", id, err))
+	}
+	user, err := svc.getUserByID(ctx, uid)
+	if err != nil {
+		return err
+	}
+	user.SessionVersion++
+	user.UpdaterID = operatorID
+	if err := svc.updateUser(ctx, user); err != nil {
+		return err
+	}
+	return svc.revokeRefreshTokensOfUser(ctx, uid)
+}
+
+func (svc *Service) revokeRefreshTokensOfUser(ctx context.Context, uid bson.ObjectID) error {
+	err := svc.Repo.RevokeRefreshTokens(ctx, domain.RevokeRefreshTokensOptions{UserID: uid}, time.Now().UnixMilli())
+	if err != nil {
+		return errors.WithMessagef(err, "db: revoke refresh tokens of user %s failed", uid.Hex())
+	}
+	return nil
+}
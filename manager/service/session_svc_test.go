@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// sessionTestStore keeps the users, refresh tokens and revoked tokens of a mocked repository in memory
+type sessionTestStore struct {
+	user          *domain.User
+	refreshTokens map[string]*domain.RefreshToken
+	revoked       map[string]bool
+}
+
+func testRSAKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key
+}
+
+func newSessionTestService(t *testing.T) (*Service, *sessionTestStore) {
+	key := testRSAKey(t)
+	password, err := util.CreateArgon2Hash("password")
+	require.NoError(t, err)
+	store := &sessionTestStore{
+		user: &domain.User{
+			BaseEntity: domain.BaseEntity{ID: bson.NewObjectID()},
+			UserName:   "alice",
+			Password:   domain.EncryptedPassword(password),
+			Status:     domain.UserStatusActive,
+			Roles:      []string{"viewer"},
+		},
+		refreshTokens: map[string]*domain.RefreshToken{},
+		revoked:       map[string]bool{},
+	}
+	mockRepo := domain.NewMockRepository(t)
+	mockRepo.EXPECT().QueryUsers(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt *domain.QueryUserOptions) {
+			user := *store.user
+			opt.Result = []*domain.User{&user}
+		}).Return(nil).Maybe()
+	mockRepo.EXPECT().UpdateUser(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, user *domain.User) { store.user = user }).Return(nil).Maybe()
+	mockRepo.EXPECT().QueryRoles(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt *domain.QueryRoleOptions) {
+			opt.Result = []*domain.Role{{Name: "viewer", Policies: []domain.RolePolicy{{PermissionKey: domain.ScheduleStrategyRead}}}}
+		}).Return(nil).Maybe()
+	mockRepo.EXPECT().RecordLoginSuccess(mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	mockRepo.EXPECT().CreateRefreshToken(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, token *domain.RefreshToken) { store.refreshTokens[token.TokenHash] = token }).Return(nil).Maybe()
+	mockRepo.EXPECT().UseRefreshToken(mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, tokenHash string, usedAt int64) (*domain.RefreshToken, error) {
+			token, ok := store.refreshTokens[tokenHash]
+			if !ok {
+				return nil, domain.ErrNotFound
+			}
+			before := *token
+			if token.RevokedAt == 0 {
+				token.RevokedAt = usedAt
+			}
+			return &before, nil
+		}).Maybe()
+	mockRepo.EXPECT().RevokeRefreshTokens(mock.Anything, mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt domain.RevokeRefreshTokensOptions, revokedAt int64) {
+			for _, token := range store.refreshTokens {
+				if token.RevokedAt != 0 ||
+					(!opt.UserID.IsZero() && token.UserID != opt.UserID) ||
+					(len(opt.FamilyIDs) > 0 && token.FamilyID != opt.FamilyIDs[0]) ||
+					(len(opt.AccessTokenIDs) > 0 && token.AccessTokenID != opt.AccessTokenIDs[0]) ||
+					(len(opt.TokenHashes) > 0 && token.TokenHash != opt.TokenHashes[0]) {
+					continue
+				}
+				token.RevokedAt = revokedAt
+			}
+		}).Return(nil).Maybe()
+	mockRepo.EXPECT().RevokeAccessToken(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, token *domain.RevokedToken) { store.revoked[token.JTI] = true }).Return(nil).Maybe()
+	mockRepo.EXPECT().IsAccessTokenRevoked(mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, jti string) (bool, error) { return store.revoked[jti], nil }).Maybe()
+
+	return &Service{Repo: mockRepo, jwtPrivateKey: key, accessTTL: time.Hour}, store
+}
+
+// loginTokens logs alice in with her password and returns the issued tokens
+func loginTokens(t *testing.T, svc *Service) *domain.TokenPair {
+	result, err := svc.Login(context.Background(), "alice", "password", "10.0.0.1")
+	require.NoError(t, err)
+	require.NotNil(t, result.Tokens)
+	return result.Tokens
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	svc, store := newSessionTestService(t)
+	ctx := context.Background()
+
+	tokens := loginTokens(t, svc)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), tokens.AccessTokenExpiresAt, time.Minute)
+	assert.WithinDuration(t, time.Now().Add(defaultRefreshTokenTTL), tokens.RefreshTokenExpiresAt, time.Minute)
+	claims, _, err := svc.VerifyJWTToken(ctx, tokens.AccessToken, domain.ScheduleStrategyRead)
+	require.NoError(t, err)
+	assert.NotEmpty(t, claims.ID)
+	assert.NotContains(t, store.refreshTokens, tokens.RefreshToken, "refresh tokens must be stored hashed")
+
+	rotated, err := svc.RefreshToken(ctx, tokens.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEqual(t, tokens.RefreshToken, rotated.RefreshToken)
+	assert.Equal(t, store.refreshTokens[hashRefreshToken(tokens.RefreshToken)].FamilyID, store.refreshTokens[hashRefreshToken(rotated.RefreshToken)].FamilyID)
+
+	// replaying the rotated token revokes the whole family, including the newest token
+	_, err = svc.RefreshToken(ctx, tokens.RefreshToken)
+	requireHTTPStatus(t, err, http.StatusUnauthorized)
+	_, err = svc.RefreshToken(ctx, rotated.RefreshToken)
+	requireHTTPStatus(t, err, http.StatusUnauthorized)
+
+	_, err = svc.RefreshToken(ctx, "unknown")
+	requireHTTPStatus(t, err, http.StatusUnauthorized)
+}
+
+func TestRefreshTokenExpired(t *testing.T) {
+	svc, store := newSessionTestService(t)
+	tokens := loginTokens(t, svc)
+	store.refreshTokens[hashRefreshToken(tokens.RefreshToken)].ExpiresAt = time.Now().Add(-time.Second)
+
+	_, err := svc.RefreshToken(context.Background(), tokens.RefreshToken)
+	requireHTTPStatus(t, err, http.StatusUnauthorized)
+}
+
+func TestLogoutRevokesTokens(t *testing.T) {
+	svc, _ := newSessionTestService(t)
+	ctx := context.Background()
+	tokens := loginTokens(t, svc)
+	other := loginTokens(t, svc)
+	claims, _, err := svc.VerifyJWTToken(ctx, tokens.AccessToken, "")
+	require.NoError(t, err)
+
+	require.NoError(t, svc.Logout(ctx, &claims, ""))
+	_, _, err = svc.VerifyJWTToken(ctx, tokens.AccessToken, "")
+	requireHTTPStatus(t, err, http.StatusUnauthorized)
+	_, err = svc.RefreshToken(ctx, tokens.RefreshToken)
+	requireHTTPStatus(t, err, http.StatusUnauthorized)
+
+	// other sessions are unaffected
+	_, _, err = svc.VerifyJWTToken(ctx, other.AccessToken, domain.ScheduleStrategyRead)
+	require.NoError(t, err)
+	_, err = svc.RefreshToken(ctx, other.RefreshToken)
+	require.NoError(t, err)
+}
+
+func TestRevokeUserSessions(t *testing.T) {
+	svc, store := newSessionTestService(t)
+	ctx := context.Background()
+	first := loginTokens(t, svc)
+	second := loginTokens(t, svc)
+
+	operator := &domain.Claims{UID: bson.NewObjectID().Hex()}
+	require.NoError(t, svc.RevokeUserSessions(ctx, operator, store.user.ID.Hex()))
+	for _, tokens := range []*domain.TokenPair{first, second} {
+		_, _, err := svc.VerifyJWTToken(ctx, tokens.AccessToken, domain.ScheduleStrategyRead)
+		requireHTTPStatus(t, err, http.StatusUnauthorized)
+		_, err = svc.RefreshToken(ctx, tokens.RefreshToken)
+		requireHTTPStatus(t, err, http.StatusUnauthorized)
+	}
+
+	// a new login gets a token of the current session version
+	third := loginTokens(t, svc)
+	_, _, err := svc.VerifyJWTToken(ctx, third.AccessToken, domain.ScheduleStrategyRead)
+	require.NoError(t, err)
+}
+
+func TestDeactivatedUserLosesSessions(t *testing.T) {
+	svc, store := newSessionTestService(t)
+	ctx := context.Background()
+	tokens := loginTokens(t, svc)
+
+	inactive := domain.UserStatusInactive
+	operator := &domain.Claims{UID: bson.NewObjectID().Hex()}
+	require.NoError(t, svc.UpdateUserPermissions(ctx, operator, store.user.ID.Hex(), domain.UpdateUserPermissionsOptions{Status: &inactive}))
+
+	_, _, err := svc.VerifyJWTToken(ctx, tokens.AccessToken, "")
+	requireHTTPStatus(t, err, http.StatusUnauthorized)
+	_, err = svc.RefreshToken(ctx, tokens.RefreshToken)
+	requireHTTPStatus(t, err, http.StatusUnauthorized)
+}
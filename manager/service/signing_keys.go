@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"crypto/rsa"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/pkg/logger"
+	"github.com/Gthulhu/api/pkg/oidc"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+)
+
+const (
+	// activeKeyIDFile names the file of the key directory holding the ID of the signing key
+	activeKeyIDFile = "active_kid"
+	// retiredKeyIDsFile names the file of the key directory listing the IDs of keys no longer
+	// accepted, one per line
+	retiredKeyIDsFile = "retired_kids"
+)
+
+// signingKeys holds the keys the manager signs and verifies its JWTs with. The primary key of the
+// configuration is a verification key until the key directory retires it and signs unless the
+// directory activates another one, tokens without a key ID were issued before keys could rotate and
+// are verified with it. A key of the directory named after the primary key ID supersedes the
+// configured one.
+type signingKeys struct {
+	primaryID string
+	primary   *rsa.PrivateKey
+	dir       string
+
+	mu       sync.RWMutex
+	activeID string
+	keys     map[string]*rsa.PrivateKey
+}
+
+func newSigningKeys(primary *rsa.PrivateKey, dir string) (*signingKeys, error) {
+	primaryID, err := oidc.NewRSAJSONWebKey("", &primary.PublicKey).Thumbprint()
+	if err != nil {
+		return nil, err
+	}
+	keys := &signingKeys{
+		primaryID: primaryID,
+		primary:   primary,
+		dir:       dir,
+		activeID:  primaryID,
+		keys:      map[string]*rsa.PrivateKey{primaryID: primary},
+	}
+	if _, err := keys.reload(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// reload re-reads the key directory, the current keys are kept when it's invalid
+func (k *signingKeys) reload() (bool, error) {
+	if k.dir == "" {
+		return false, nil
+	}
+	entries, err := os.ReadDir(k.dir)
+	if err != nil {
+		return false, fmt.Errorf("read key directory %s: %w", k.dir, err)
+	}
+	keys := map[string]*rsa.PrivateKey{}
+	for _, entry := range entries {
+		// Kubernetes mounts secrets through hidden ..data directories
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, "..") || filepath.Ext(name) != ".pem" {
+			continue
+		}
+		kid := strings.TrimSuffix(name, ".pem")
+		pemBytes, err := os.ReadFile(filepath.Join(k.dir, name))
+		if err != nil {
+			return false, fmt.Errorf("read key %s: %w", kid, err)
+		}
+		key, err := initRSAPrivateKey(string(pemBytes))
+		if err != nil {
+			return false, fmt.Errorf("parse key %s: %w", kid, err)
+		}
+		keys[kid] = key
+	}
+	if _, ok := keys[k.primaryID]; !ok {
+		keys[k.primaryID] = k.primary
+	}
+	retiredBytes, err := os.ReadFile(filepath.Join(k.dir, retiredKeyIDsFile))
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("read retired key IDs: %w", err)
+	}
+	// a compromised primary key is retired here, it can't be removed from the configuration
+	// without a restart
+	for _, id := range strings.Fields(string(retiredBytes)) {
+		delete(keys, id)
+	}
+	activeID := k.primaryID
+	activeBytes, err := os.ReadFile(filepath.Join(k.dir, activeKeyIDFile))
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("read active key ID: %w", err)
+	}
+	if id := strings.TrimSpace(string(activeBytes)); id != "" {
+		activeID = id
+	}
+	if _, ok := keys[activeID]; !ok {
+		return false, fmt.Errorf("active key %s not found in %s or retired", activeID, k.dir)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	// a key file replaced under the same name changes the key set too
+	changed := activeID != k.activeID || len(keys) != len(k.keys)
+	for kid, key := range keys {
+		if current, ok := k.keys[kid]; !ok || !current.Equal(key) {
+			changed = true
+		}
+	}
+	k.activeID = activeID
+	k.keys = keys
+	return changed, nil
+}
+
+func (k *signingKeys) signingKey() (string, *rsa.PrivateKey) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.activeID, k.keys[k.activeID]
+}
+
+func (k *signingKeys) publicKey(kid string) (*rsa.PublicKey, bool) {
+	if kid == "" {
+		kid = k.primaryID
+	}
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, false
+	}
+	return &key.PublicKey, true
+}
+
+// jsonWebKeys returns the verification keys, the signing key first
+func (k *signingKeys) jsonWebKeys() []oidc.JSONWebKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	kids := make([]string, 0, len(k.keys))
+	for kid := range k.keys {
+		if kid != k.activeID {
+			kids = append(kids, kid)
+		}
+	}
+	slices.Sort(kids)
+	kids = append([]string{k.activeID}, kids...)
+	jwks := make([]oidc.JSONWebKey, len(kids))
+	for i, kid := range kids {
+		jwks[i] = oidc.NewRSAJSONWebKey(kid, &k.keys[kid].PublicKey)
+	}
+	return jwks
+}
+
+// getSigningKeys returns the key set, a service built without one signs with jwtPrivateKey alone
+func (svc *Service) getSigningKeys() *signingKeys {
+	svc.signingKeysOnce.Do(func() {
+		if svc.signingKeys == nil {
+			// without a key directory only the thumbprint of the primary key can fail
+			svc.signingKeys, _ = newSigningKeys(svc.jwtPrivateKey, "")
+		}
+	})
+	return svc.signingKeys
+}
+
+// signJWT signs claims with the active key and names it in the kid header
+func (svc *Service) signJWT(claims jwt.Claims) (string, error) {
+	kid, key := svc.getSigningKeys().signingKey()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	return token.SignedString(key)
+}
+
+// jwtKeyFunc returns the verification key named by the kid header of token
+func (svc *Service) jwtKeyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := svc.getSigningKeys().publicKey(kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+// JSONWebKeySet returns the public keys manager-issued JWTs are verified with
+func (svc *Service) JSONWebKeySet(ctx context.Context) (*domain.JSONWebKeySet, error) {
+	return &domain.JSONWebKeySet{Keys: svc.getSigningKeys().jsonWebKeys()}, nil
+}
+
+// ReloadSigningKeys re-reads the JWT key directory so that keys rotate without a restart
+func (svc *Service) ReloadSigningKeys(ctx context.Context) error {
+	changed, err := svc.getSigningKeys().reload()
+	if err != nil {
+		return errors.WithMessage(err, "reload JWT signing keys failed")
+	}
+	if changed {
+		kid, _ := svc.getSigningKeys().signingKey()
+		logger.Logger(ctx).Info().Msgf("JWT signing keys reloaded, signing with %s", kid)
+	}
+	return nil
+}
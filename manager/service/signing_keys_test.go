@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestKey(t *testing.T, dir, kid string, key *rsa.PrivateKey) {
+	t.Helper()
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, kid+".pem"), pemBytes, 0o600))
+}
+
+func tokenKeyID(t *testing.T, token string) string {
+	t.Helper()
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &domain.Claims{})
+	require.NoError(t, err)
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
+
+func TestSigningKeyRotation(t *testing.T) {
+	svc, _ := newSessionTestService(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	keys, err := newSigningKeys(svc.jwtPrivateKey, dir)
+	require.NoError(t, err)
+	svc.signingKeys = keys
+
+	oldToken := loginTokens(t, svc).AccessToken
+	assert.Equal(t, keys.primaryID, tokenKeyID(t, oldToken))
+
+	// a published key only verifies until it's activated
+	writeTestKey(t, dir, "2026-10", testRSAKey(t))
+	require.NoError(t, svc.ReloadSigningKeys(ctx))
+	assert.Equal(t, keys.primaryID, tokenKeyID(t, loginTokens(t, svc).AccessToken))
+	set, err := svc.JSONWebKeySet(ctx)
+	require.NoError(t, err)
+	require.Len(t, set.Keys, 2)
+	assert.Equal(t, keys.primaryID, set.Keys[0].KeyID)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, activeKeyIDFile), []byte("2026-10\n"), 0o600))
+	require.NoError(t, svc.ReloadSigningKeys(ctx))
+	newToken := loginTokens(t, svc).AccessToken
+	assert.Equal(t, "2026-10", tokenKeyID(t, newToken))
+	set, err = svc.JSONWebKeySet(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10", set.Keys[0].KeyID)
+	for _, token := range []string{oldToken, newToken} {
+		_, _, err = svc.VerifyJWTToken(ctx, token, domain.ScheduleStrategyRead)
+		require.NoError(t, err)
+	}
+
+	// an invalid directory keeps the current keys
+	require.NoError(t, os.WriteFile(filepath.Join(dir, activeKeyIDFile), []byte("unknown"), 0o600))
+	require.Error(t, svc.ReloadSigningKeys(ctx))
+	assert.Equal(t, "2026-10", tokenKeyID(t, loginTokens(t, svc).AccessToken))
+
+	// tokens of a removed key are rejected
+	require.NoError(t, os.Remove(filepath.Join(dir, activeKeyIDFile)))
+	require.NoError(t, os.Remove(filepath.Join(dir, "2026-10.pem")))
+	require.NoError(t, svc.ReloadSigningKeys(ctx))
+	_, _, err = svc.VerifyJWTToken(ctx, newToken, domain.ScheduleStrategyRead)
+	require.Error(t, err)
+	_, _, err = svc.VerifyJWTToken(ctx, oldToken, domain.ScheduleStrategyRead)
+	require.NoError(t, err)
+}
+
+func TestVerifyTokenWithoutKeyID(t *testing.T) {
+	svc, store := newSessionTestService(t)
+	now := time.Now()
+	// tokens issued before keys rotated carry no kid and are verified with the primary key
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, domain.Claims{
+		UID: store.user.ID.Hex(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		},
+	}).SignedString(svc.jwtPrivateKey)
+	require.NoError(t, err)
+	_, _, err = svc.VerifyJWTToken(context.Background(), token, domain.ScheduleStrategyRead)
+	require.NoError(t, err)
+
+	unknown := jwt.NewWithClaims(jwt.SigningMethodRS256, domain.Claims{UID: store.user.ID.Hex()})
+	unknown.Header["kid"] = "unknown"
+	token, err = unknown.SignedString(svc.jwtPrivateKey)
+	require.NoError(t, err)
+	_, _, err = svc.VerifyJWTToken(context.Background(), token, domain.ScheduleStrategyRead)
+	require.Error(t, err)
+}
+
+func TestSigningKeyReplacement(t *testing.T) {
+	svc, _ := newSessionTestService(t)
+	dir := t.TempDir()
+	keys, err := newSigningKeys(svc.jwtPrivateKey, dir)
+	require.NoError(t, err)
+
+	writeTestKey(t, dir, "2026-10", testRSAKey(t))
+	changed, err := keys.reload()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	changed, err = keys.reload()
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// a key file replaced under the same name is reported as a change
+	writeTestKey(t, dir, "2026-10", testRSAKey(t))
+	changed, err = keys.reload()
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	// a directory key named after the primary key ID supersedes the configured one, tokens without
+	// a kid included
+	replacement := testRSAKey(t)
+	writeTestKey(t, dir, keys.primaryID, replacement)
+	changed, err = keys.reload()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	kid, key := keys.signingKey()
+	assert.Equal(t, keys.primaryID, kid)
+	assert.True(t, replacement.Equal(key))
+	public, ok := keys.publicKey("")
+	require.True(t, ok)
+	assert.True(t, replacement.PublicKey.Equal(public))
+
+	require.NoError(t, os.Remove(filepath.Join(dir, keys.primaryID+".pem")))
+	changed, err = keys.reload()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	public, ok = keys.publicKey("")
+	require.True(t, ok)
+	assert.True(t, svc.jwtPrivateKey.PublicKey.Equal(public))
+}
+
+func TestRetirePrimarySigningKey(t *testing.T) {
+	svc, _ := newSessionTestService(t)
+	dir := t.TempDir()
+	keys, err := newSigningKeys(svc.jwtPrivateKey, dir)
+	require.NoError(t, err)
+	svc.signingKeys = keys
+	legacy := jwt.NewWithClaims(jwt.SigningMethodRS256, &domain.Claims{UID: "user"})
+	legacyToken, err := legacy.SignedString(svc.jwtPrivateKey)
+	require.NoError(t, err)
+	primaryToken, err := svc.signJWT(&domain.Claims{UID: "user"})
+	require.NoError(t, err)
+
+	// the signing key can't be retired
+	retired := filepath.Join(dir, retiredKeyIDsFile)
+	require.NoError(t, os.WriteFile(retired, []byte(keys.primaryID+"\n"), 0o600))
+	_, err = keys.reload()
+	require.Error(t, err)
+
+	writeTestKey(t, dir, "2026-10", testRSAKey(t))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, activeKeyIDFile), []byte("2026-10"), 0o600))
+	changed, err := keys.reload()
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	_, ok := keys.publicKey(keys.primaryID)
+	assert.False(t, ok, "a retired primary key must not verify")
+	_, ok = keys.publicKey("")
+	assert.False(t, ok, "tokens without a kid must not fall back to the retired primary key")
+	for _, token := range []string{legacyToken, primaryToken} {
+		_, err = jwt.ParseWithClaims(token, &domain.Claims{}, svc.jwtKeyFunc)
+		require.Error(t, err)
+	}
+	jwks := keys.jsonWebKeys()
+	require.Len(t, jwks, 1)
+	assert.Equal(t, "2026-10", jwks[0].KeyID)
+
+	// un-retiring the primary key restores it
+	require.NoError(t, os.Remove(retired))
+	_, err = keys.reload()
+	require.NoError(t, err)
+	_, ok = keys.publicKey("")
+	assert.True(t, ok)
+}
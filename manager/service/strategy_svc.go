@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"regexp"
 	"time"
 
 	"github.com/Gthulhu/api/manager/domain"
@@ -18,10 +19,23 @@ func (svc *Service) CreateScheduleStrategy(ctx context.Context, operator *domain
 	if err != nil {
 		return errors.WithMessagef(err, "invalid operator ID %s", operator.UID)
 	}
+	if err := validateStrategyMatchOptions(strategy); err != nil {
+		return err
+	}
+	if !strategy.TeamID.IsZero() {
+		team, err := svc.getTeamByID(ctx, strategy.TeamID.Hex())
+		if err != nil {
+			return err
+		}
+		if !team.HasMember(operatorID) {
+			return errs.NewHTTPStatusError(http.StatusForbidden, "only members can create strategies for a team", fmt.Errorf("user %s is not a member of team %s", operator.UID, team.ID.Hex()))
+		}
+	}
 	queryOpt := &domain.QueryPodsOptions{
-		K8SNamespace:   strategy.K8sNamespace,
-		LabelSelectors: strategy.LabelSelectors,
-		CommandRegex:   strategy.CommandRegex,
+		K8SNamespace:        strategy.K8sNamespace,
+		LabelSelectors:      strategy.LabelSelectors,
+		CommandRegex:        strategy.CommandRegex,
+		CommandMatchTargets: strategy.CommandMatchTargets,
 	}
 	pods, err := svc.K8SAdapter.QueryPods(ctx, queryOpt)
 	if err != nil {
@@ -51,6 +65,7 @@ func (svc *Service) CreateScheduleStrategy(ctx context.Context, operator *domain
 	if err != nil {
 		return fmt.Errorf("insert strategy and intents into repository: %w", err)
 	}
+	svc.emitEvent(ctx, domain.EventStrategyCreated, actorID(operator), newStrategyEventData(strategy, len(intents)))
 
 	dmLabel := domain.LabelSelector{
 		Key:   "app",
@@ -88,6 +103,9 @@ func (svc *Service) CreateScheduleStrategy(ctx context.Context, operator *domain
 		dmPod := nodeIDDMap[host]
 		err = svc.DMAdapter.SendSchedulingIntent(ctx, dmPod, intents)
 		if err != nil {
+			svc.emitEvent(ctx, domain.EventIntentsFailed, actorID(operator), IntentsEventData{
+				StrategyID: strategy.ID.Hex(), NodeID: dmPod.NodeID, IntentCount: len(intents), Error: err.Error(),
+			})
 			return fmt.Errorf("send scheduling intents to decision maker %s: %w", host, err)
 		}
 		err = svc.Repo.BatchUpdateIntentsState(ctx, nodeIDIntentIDsMap[host], domain.IntentStateSent)
@@ -95,6 +113,24 @@ func (svc *Service) CreateScheduleStrategy(ctx context.Context, operator *domain
 			return fmt.Errorf("insert strategy and intents into repository: %w", err)
 		}
 		logger.Logger(ctx).Info().Msgf("sent %d scheduling intents to decision maker %s", len(intents), host)
+		svc.emitEvent(ctx, domain.EventIntentsSent, actorID(operator), IntentsEventData{
+			StrategyID: strategy.ID.Hex(), NodeID: dmPod.NodeID, IntentCount: len(intents),
+		})
+	}
+	return nil
+}
+
+// validateStrategyMatchOptions rejects match targets and thread regexes the decision maker cannot use
+func validateStrategyMatchOptions(strategy *domain.ScheduleStrategy) error {
+	for _, target := range strategy.CommandMatchTargets {
+		if !target.IsValid() {
+			return errs.NewHTTPStatusError(http.StatusBadRequest, fmt.Sprintf("invalid command match target %q", target), nil)
+		}
+	}
+	if strategy.ThreadRegex != "" {
+		if _, err := regexp.Compile(strategy.ThreadRegex); err != nil {
+			return errs.NewHTTPStatusError(http.StatusBadRequest, "invalid thread regex", err)
+		}
 	}
 	return nil
 }
@@ -117,25 +153,25 @@ func (svc *Service) UpdateScheduleStrategy(ctx context.Context, operator *domain
 	if err != nil {
 		return errors.WithMessagef(err, "invalid operator ID %s", operator.UID)
 	}
+	if err := validateStrategyMatchOptions(strategy); err != nil {
+		return err
+	}
 
 	// Validate ownership and load existing strategy
-	queryOpt := &domain.QueryStrategyOptions{
-		IDs:        []bson.ObjectID{strategyObjID},
-		CreatorIDs: []bson.ObjectID{operatorID},
-	}
-	if err := svc.Repo.QueryStrategies(ctx, queryOpt); err != nil {
+	currentStrategy, err := svc.getManageableStrategy(ctx, operatorID, strategyObjID)
+	if err != nil {
 		return err
 	}
-	if len(queryOpt.Result) == 0 {
+	if currentStrategy == nil {
 		return errs.NewHTTPStatusError(http.StatusNotFound, "strategy not found or you don't have permission to update it", nil)
 	}
-	currentStrategy := queryOpt.Result[0]
 
 	// Query pods based on new strategy criteria before making changes
 	queryPodsOpt := &domain.QueryPodsOptions{
-		K8SNamespace:   strategy.K8sNamespace,
-		LabelSelectors: strategy.LabelSelectors,
-		CommandRegex:   strategy.CommandRegex,
+		K8SNamespace:        strategy.K8sNamespace,
+		LabelSelectors:      strategy.LabelSelectors,
+		CommandRegex:        strategy.CommandRegex,
+		CommandMatchTargets: strategy.CommandMatchTargets,
 	}
 	pods, err := svc.K8SAdapter.QueryPods(ctx, queryPodsOpt)
 	if err != nil {
@@ -158,6 +194,7 @@ func (svc *Service) UpdateScheduleStrategy(ctx context.Context, operator *domain
 	strategy.ID = strategyObjID
 	strategy.CreatedTime = currentStrategy.CreatedTime
 	strategy.CreatorID = currentStrategy.CreatorID
+	strategy.TeamID = currentStrategy.TeamID
 	strategy.UpdaterID = operatorID
 	strategy.UpdatedTime = now
 
@@ -185,6 +222,7 @@ func (svc *Service) UpdateScheduleStrategy(ctx context.Context, operator *domain
 	if err := svc.Repo.InsertIntents(ctx, intents); err != nil {
 		return fmt.Errorf("insert intents into repository: %w", err)
 	}
+	svc.emitEvent(ctx, domain.EventStrategyUpdated, actorID(operator), newStrategyEventData(strategy, len(intents)))
 
 	// Notify decision makers to remove old intents
 	if len(oldIntentQuery.Result) > 0 {
@@ -258,6 +296,9 @@ func (svc *Service) UpdateScheduleStrategy(ctx context.Context, operator *domain
 		dmPod := nodeIDDMap[host]
 		err = svc.DMAdapter.SendSchedulingIntent(ctx, dmPod, intents)
 		if err != nil {
+			svc.emitEvent(ctx, domain.EventIntentsFailed, actorID(operator), IntentsEventData{
+				StrategyID: strategy.ID.Hex(), NodeID: dmPod.NodeID, IntentCount: len(intents), Error: err.Error(),
+			})
 			return fmt.Errorf("send scheduling intents to decision maker %s: %w", host, err)
 		}
 		err = svc.Repo.BatchUpdateIntentsState(ctx, nodeIDIntentIDsMap[host], domain.IntentStateSent)
@@ -265,6 +306,9 @@ func (svc *Service) UpdateScheduleStrategy(ctx context.Context, operator *domain
 			return fmt.Errorf("update intents state: %w", err)
 		}
 		logger.Logger(ctx).Info().Msgf("sent %d scheduling intents to decision maker %s", len(intents), host)
+		svc.emitEvent(ctx, domain.EventIntentsSent, actorID(operator), IntentsEventData{
+			StrategyID: strategy.ID.Hex(), NodeID: dmPod.NodeID, IntentCount: len(intents),
+		})
 	}
 
 	logger.Logger(ctx).Info().Msgf("updated strategy %s and regenerated intents", strategyID)
@@ -282,16 +326,12 @@ func (svc *Service) DeleteScheduleStrategy(ctx context.Context, operator *domain
 		return errors.WithMessagef(err, "invalid operator ID %s", operator.UID)
 	}
 
-	// Check if strategy exists and belongs to the operator
-	queryOpt := &domain.QueryStrategyOptions{
-		IDs:        []bson.ObjectID{strategyObjID},
-		CreatorIDs: []bson.ObjectID{operatorID},
-	}
-	err = svc.Repo.QueryStrategies(ctx, queryOpt)
+	// Check if strategy exists and belongs to the operator or one of its teams
+	strategy, err := svc.getManageableStrategy(ctx, operatorID, strategyObjID)
 	if err != nil {
 		return err
 	}
-	if len(queryOpt.Result) == 0 {
+	if strategy == nil {
 		return errs.NewHTTPStatusError(http.StatusNotFound, "strategy not found or you don't have permission to delete it", nil)
 	}
 
@@ -331,6 +371,7 @@ func (svc *Service) DeleteScheduleStrategy(ctx context.Context, operator *domain
 	if err != nil {
 		return fmt.Errorf("delete strategy: %w", err)
 	}
+	svc.emitEvent(ctx, domain.EventStrategyDeleted, actorID(operator), newStrategyEventData(strategy, len(intentQueryOpt.Result)))
 
 	// Notify decision makers to remove intents from their in-memory cache
 	if len(nodeIDs) > 0 && len(podIDs) > 0 {
@@ -380,10 +421,16 @@ func (svc *Service) DeleteScheduleIntents(ctx context.Context, operator *domain.
 		intentObjIDs = append(intentObjIDs, objID)
 	}
 
-	// Check if intents exist and belong to the operator
+	teamIDs, err := svc.teamIDsOf(ctx, operatorID)
+	if err != nil {
+		return err
+	}
+
+	// Check if intents exist and belong to the operator or one of its teams
 	queryOpt := &domain.QueryIntentOptions{
 		IDs:        intentObjIDs,
 		CreatorIDs: []bson.ObjectID{operatorID},
+		TeamIDs:    teamIDs,
 	}
 	err = svc.Repo.QueryIntents(ctx, queryOpt)
 	if err != nil {
@@ -391,7 +438,7 @@ func (svc *Service) DeleteScheduleIntents(ctx context.Context, operator *domain.
 	}
 
 	// Verify that all requested intents exist, are returned by the query,
-	// and are owned by the current operator.
+	// and are owned by the current operator or one of its teams.
 	if len(queryOpt.Result) == 0 {
 		return errs.NewHTTPStatusError(http.StatusNotFound, "one or more intents not found or you don't have permission to delete them", nil)
 	}
@@ -404,8 +451,8 @@ func (svc *Service) DeleteScheduleIntents(ctx context.Context, operator *domain.
 
 	matchedCount := 0
 	for _, intent := range queryOpt.Result {
-		// Ensure the intent belongs to the operator.
-		if intent.CreatorID != operatorID {
+		// Ensure the intent belongs to the operator or one of its teams.
+		if !intent.ManageableBy(operatorID, teamIDs) {
 			return errs.NewHTTPStatusError(http.StatusNotFound, "one or more intents not found or you don't have permission to delete them", nil)
 		}
 
@@ -470,6 +517,78 @@ func (svc *Service) DeleteScheduleIntents(ctx context.Context, operator *domain.
 	return nil
 }
 
+// TransferScheduleStrategy hands a strategy and its intents over to another creator and team, e.g.
+// when its creator leaves
+func (svc *Service) TransferScheduleStrategy(ctx context.Context, operator *domain.Claims, strategyID string, opt domain.TransferStrategyOptions) error {
+	strategyObjID, err := bson.ObjectIDFromHex(strategyID)
+	if err != nil {
+		return errs.NewHTTPStatusError(http.StatusBadRequest, "invalid strategy ID", err)
+	}
+	if opt.TeamID.IsZero() {
+		return errs.NewHTTPStatusError(http.StatusBadRequest, "team ID is required", nil)
+	}
+	operatorID, err := operator.GetBsonObjectUID()
+	if err != nil {
+		return errors.WithMessagef(err, "invalid operator ID %s", operator.UID)
+	}
+
+	queryOpt := &domain.QueryStrategyOptions{IDs: []bson.ObjectID{strategyObjID}}
+	if err := svc.Repo.QueryStrategies(ctx, queryOpt); err != nil {
+		return err
+	}
+	if len(queryOpt.Result) == 0 {
+		return errs.NewHTTPStatusError(http.StatusNotFound, "strategy not found", fmt.Errorf("strategy %s not found", strategyID))
+	}
+	strategy := queryOpt.Result[0]
+	if !opt.CreatorID.IsZero() {
+		if err := svc.checkUsersExist(ctx, []bson.ObjectID{opt.CreatorID}); err != nil {
+			return err
+		}
+	}
+	if _, err := svc.getTeamByID(ctx, opt.TeamID.Hex()); err != nil {
+		return err
+	}
+	if !opt.CreatorID.IsZero() {
+		strategy.CreatorID = opt.CreatorID
+	}
+	strategy.TeamID = opt.TeamID
+	strategy.UpdaterID = operatorID
+	strategy.UpdatedTime = time.Now().UnixMilli()
+
+	if err := svc.Repo.UpdateStrategy(ctx, strategy); err != nil {
+		return fmt.Errorf("update strategy: %w", err)
+	}
+	if err := svc.Repo.UpdateIntentsOwner(ctx, strategy); err != nil {
+		return fmt.Errorf("update owner of intents: %w", err)
+	}
+	logger.Logger(ctx).Info().Msgf("transferred strategy %s to creator %s and team %s", strategyID, strategy.CreatorID.Hex(), strategy.TeamID.Hex())
+	return nil
+}
+
+// getManageableStrategy returns the strategy with strategyID when the user with operatorID owns it,
+// personally or through one of its teams, and nil otherwise
+func (svc *Service) getManageableStrategy(ctx context.Context, operatorID, strategyID bson.ObjectID) (*domain.ScheduleStrategy, error) {
+	queryOpt := &domain.QueryStrategyOptions{IDs: []bson.ObjectID{strategyID}}
+	if err := svc.Repo.QueryStrategies(ctx, queryOpt); err != nil {
+		return nil, err
+	}
+	if len(queryOpt.Result) == 0 {
+		return nil, nil
+	}
+	strategy := queryOpt.Result[0]
+	if strategy.CreatorID == operatorID {
+		return strategy, nil
+	}
+	teamIDs, err := svc.teamIDsOf(ctx, operatorID)
+	if err != nil {
+		return nil, err
+	}
+	if !strategy.ManageableBy(operatorID, teamIDs) {
+		return nil, nil
+	}
+	return strategy, nil
+}
+
 func (svc *Service) GetPodPIDMapping(ctx context.Context, nodeID string) (*domain.PodPIDMappingResponse, error) {
 	if svc.K8SAdapter == nil {
 		return nil, domain.ErrNoClient
@@ -4,23 +4,37 @@ import (
 	"context"
 	"crypto/rsa"
 	"crypto/x509"
+	"encoding/json"
 	"encoding/pem"
-	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/Gthulhu/api/config"
 	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/pkg/auditstream"
+	"github.com/Gthulhu/api/pkg/logger"
+	"github.com/Gthulhu/api/pkg/webhook"
 	"go.uber.org/fx"
 )
 
 type Params struct {
 	fx.In
-	Repo          domain.Repository
-	KeyConfig     config.KeyConfig
-	AccountConfig config.AccountConfig
-	K8SAdapter    domain.K8SAdapter
-	DMAdapter     domain.DecisionMakerAdapter
+	Repo               domain.Repository
+	KeyConfig          config.KeyConfig
+	AuthConfig         config.AuthConfig
+	OIDCConfig         config.OIDCConfig
+	PasswordPolicy     config.PasswordPolicyConfig
+	LoginProtection    config.LoginProtectionConfig
+	TwoFactor          config.TwoFactorConfig
+	AccountConfig      config.AccountConfig
+	K8SAdapter         domain.K8SAdapter
+	DMAdapter          domain.DecisionMakerAdapter
+	MetricStoreConfig  config.MetricStoreConfig
+	AlertingConfig     config.AlertingConfig
+	EventWebhookConfig config.EventWebhookConfig
+	AuditStreamer      *auditstream.Streamer
+	AuthCache          *AuthCache
 }
 
 func NewService(params Params) (domain.Service, error) {
@@ -28,12 +42,36 @@ func NewService(params Params) (domain.Service, error) {
 	if err != nil {
 		return nil, fmt.Errorf("initialize RSA private key: %w", err)
 	}
+	signingKeys, err := newSigningKeys(jwtPrivateKey, params.KeyConfig.JWTKeyDir)
+	if err != nil {
+		return nil, fmt.Errorf("initialize JWT signing keys: %w", err)
+	}
+	twoFactorKey, err := decodeTwoFactorKey(params.TwoFactor)
+	if err != nil {
+		return nil, fmt.Errorf("initialize two-factor encryption key: %w", err)
+	}
 
 	svc := &Service{
-		K8SAdapter:    params.K8SAdapter,
-		DMAdapter:     params.DMAdapter,
-		Repo:          params.Repo,
-		jwtPrivateKey: jwtPrivateKey,
+		K8SAdapter:          params.K8SAdapter,
+		DMAdapter:           params.DMAdapter,
+		Repo:                params.Repo,
+		jwtPrivateKey:       jwtPrivateKey,
+		signingKeys:         signingKeys,
+		accessTTL:           time.Duration(params.AuthConfig.AccessTokenTTLSec) * time.Second,
+		refreshTTL:          time.Duration(params.AuthConfig.RefreshTokenTTLSec) * time.Second,
+		oidcLogin:           newOIDCLogin(params.OIDCConfig),
+		passwordPolicy:      domain.PasswordPolicy(params.PasswordPolicy),
+		loginProtection:     params.LoginProtection,
+		loginThrottle:       newLoginThrottle(params.LoginProtection),
+		twoFactorIssuer:     params.TwoFactor.Issuer,
+		twoFactorKey:        twoFactorKey,
+		nodeMetricsTracker:  newNodeMetricsTracker(),
+		storedSampleTracker: newStoredSampleTracker(params.MetricStoreConfig),
+		alertEvaluator:      newAlertEvaluator(params.AlertingConfig),
+		webhookSender:       newWebhookSender(params.AlertingConfig),
+		eventDispatcher:     newEventDispatcher(params.EventWebhookConfig),
+		auditStreamer:       params.AuditStreamer,
+		authCache:           params.AuthCache,
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -47,10 +85,49 @@ func NewService(params Params) (domain.Service, error) {
 }
 
 type Service struct {
-	K8SAdapter    domain.K8SAdapter
-	DMAdapter     domain.DecisionMakerAdapter
-	Repo          domain.Repository
-	jwtPrivateKey *rsa.PrivateKey
+	K8SAdapter domain.K8SAdapter
+	DMAdapter  domain.DecisionMakerAdapter
+	Repo       domain.Repository
+	// jwtPrivateKey is the primary key of the configuration, signingKeys adds the rotated keys of
+	// the key directory and is built from jwtPrivateKey alone when nil
+	jwtPrivateKey   *rsa.PrivateKey
+	signingKeys     *signingKeys
+	signingKeysOnce sync.Once
+	// accessTTL and refreshTTL are the lifetimes of issued tokens, zero uses the defaults
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	// oidcLogin enables single sign-on, nil disables it
+	oidcLogin *oidcLogin
+	// passwordPolicy constrains new passwords, the zero value only enforces the default lengths
+	passwordPolicy  domain.PasswordPolicy
+	loginProtection config.LoginProtectionConfig
+	// loginThrottle rejects clients with too many failed logins, nil disables it
+	loginThrottle *loginThrottle
+	// twoFactorIssuer names the manager in authenticator apps, twoFactorKey encrypts TOTP secrets
+	// and is nil when two-factor authentication is disabled
+	twoFactorIssuer string
+	twoFactorKey    []byte
+	// nodeMetricsTracker keeps the previous GetClusterMetrics observations, nil disables trend detection
+	nodeMetricsTracker *nodeMetricsTracker
+	// storedSampleTracker skips metric samples already persisted and holds the collection lease
+	storedSampleTracker *storedSampleTracker
+	alertEvaluator      *alertEvaluator
+	// webhookSender delivers alert notifications, nil disables them
+	webhookSender *webhook.Sender
+	// eventDispatcher delivers lifecycle events to webhook subscriptions, nil disables them
+	eventDispatcher *eventDispatcher
+	// auditStreamer exports audit logs to external sinks, nil disables it
+	auditStreamer *auditstream.Streamer
+	// authCache caches the users and roles requests are authorized with, nil disables it
+	authCache *AuthCache
+}
+
+func newWebhookSender(cfg config.AlertingConfig) *webhook.Sender {
+	sender := webhook.NewSender(time.Duration(cfg.WebhookTimeoutSec) * time.Second)
+	if cfg.WebhookMaxAttempts > 0 {
+		sender.MaxAttempts = cfg.WebhookMaxAttempts
+	}
+	return sender
 }
 
 func initRSAPrivateKey(pemStr string) (*rsa.PrivateKey, error) {
@@ -75,6 +152,65 @@ func initRSAPrivateKey(pemStr string) (*rsa.PrivateKey, error) {
 	return key, nil
 }
 
-func (svc Service) ListAuditLogs(ctx context.Context, opt *domain.QueryAuditLogOptions) error {
-	return errors.New("not implemented")
+func (svc *Service) CreateAuditLog(ctx context.Context, log *domain.AuditLog) error {
+	err := svc.Repo.CreateAuditLog(ctx, log)
+	// stream even when MongoDB is unavailable, the sinks are the copy that survives it
+	svc.streamAuditLog(ctx, log)
+	return err
+}
+
+// auditStreamRecord is the JSON representation of an audit log delivered to the audit stream sinks
+type auditStreamRecord struct {
+	ID         string               `json:"id,omitempty"`
+	UserID     string               `json:"user_id,omitempty"`
+	Action     string               `json:"action"`
+	RequestID  string               `json:"request_id,omitempty"`
+	Timestamp  int64                `json:"timestamp"`
+	IP         string               `json:"ip,omitempty"`
+	Method     string               `json:"method,omitempty"`
+	Path       string               `json:"path,omitempty"`
+	ResourceID string               `json:"resource_id,omitempty"`
+	Status     int                  `json:"status,omitempty"`
+	Changes    []domain.AuditChange `json:"changes,omitempty"`
+}
+
+func (svc *Service) streamAuditLog(ctx context.Context, log *domain.AuditLog) {
+	if svc.auditStreamer == nil {
+		return
+	}
+	record := auditStreamRecord{
+		Action:     log.Action,
+		RequestID:  log.RequestID,
+		Timestamp:  log.Timestamp,
+		IP:         log.IP,
+		Method:     log.Method,
+		Path:       log.Path,
+		ResourceID: log.ResourceID,
+		Status:     log.Status,
+		Changes:    log.Changes,
+	}
+	if !log.ID.IsZero() {
+		record.ID = log.ID.Hex()
+	}
+	if !log.UserID.IsZero() {
+		record.UserID = log.UserID.Hex()
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		logger.Logger(ctx).Warn().Err(err).Msg("failed to encode audit log for streaming")
+		return
+	}
+	err = svc.auditStreamer.Publish(auditstream.Record{
+		Time:   time.UnixMilli(log.Timestamp),
+		Action: log.Action,
+		Data:   data,
+	})
+	if err != nil {
+		logger.Logger(ctx).Error().Err(err).Msgf("failed to spool audit log %s for streaming", log.Action)
+	}
+}
+
+// ListAuditLogs returns the matching audit logs, most recent first
+func (svc *Service) ListAuditLogs(ctx context.Context, opt *domain.QueryAuditLogOptions) error {
+	return svc.Repo.QueryAuditLogs(ctx, opt)
 }
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/manager/errs"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func (svc *Service) CreateTeam(ctx context.Context, operator *domain.Claims, team *domain.Team) error {
+	operatorID, err := operator.GetBsonObjectUID()
+	if err != nil {
+		return errs.NewHTTPStatusError(http.StatusUnauthorized, "unauthorized", fmt.Errorf("invalid user ID"))
+	}
+	if team.Name == "" {
+		return errs.NewHTTPStatusError(http.StatusBadRequest, "name is required", nil)
+	}
+	if err := svc.checkTeamNameAvailable(ctx, team.Name); err != nil {
+		return err
+	}
+	team.MemberIDs = compactObjectIDs(team.MemberIDs)
+	if err := svc.checkUsersExist(ctx, team.MemberIDs); err != nil {
+		return err
+	}
+	team.BaseEntity = domain.NewBaseEntity(&operatorID, &operatorID)
+	return svc.Repo.CreateTeam(ctx, team)
+}
+
+func (svc *Service) UpdateTeam(ctx context.Context, operator *domain.Claims, teamID string, opt domain.UpdateTeamOptions) error {
+	operatorID, err := operator.GetBsonObjectUID()
+	if err != nil {
+		return errs.NewHTTPStatusError(http.StatusUnauthorized, "unauthorized", fmt.Errorf("invalid user ID"))
+	}
+	team, err := svc.getTeamByID(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	if opt.Name != nil && *opt.Name != team.Name {
+		if *opt.Name == "" {
+			return errs.NewHTTPStatusError(http.StatusBadRequest, "name is required", nil)
+		}
+		if err := svc.checkTeamNameAvailable(ctx, *opt.Name); err != nil {
+			return err
+		}
+		team.Name = *opt.Name
+	}
+	if opt.Description != nil {
+		team.Description = *opt.Description
+	}
+	if opt.MemberIDs != nil {
+		memberIDs := compactObjectIDs(*opt.MemberIDs)
+		if err := svc.checkUsersExist(ctx, memberIDs); err != nil {
+			return err
+		}
+		team.MemberIDs = memberIDs
+	}
+	team.UpdaterID = operatorID
+	return svc.Repo.UpdateTeam(ctx, team)
+}
+
+// DeleteTeam deletes a team that no longer owns strategies, they have to be transferred first so
+// that none is left without an owner
+func (svc *Service) DeleteTeam(ctx context.Context, operator *domain.Claims, teamID string) error {
+	teamObjID, err := bson.ObjectIDFromHex(teamID)
+	if err != nil {
+		return errs.NewHTTPStatusError(http.StatusBadRequest, "invalid team ID", err)
+	}
+	strategyQuery := &domain.QueryStrategyOptions{TeamIDs: []bson.ObjectID{teamObjID}}
+	if err := svc.Repo.QueryStrategies(ctx, strategyQuery); err != nil {
+		return err
+	}
+	if len(strategyQuery.Result) > 0 {
+		return errs.NewHTTPStatusError(http.StatusConflict, "team still owns strategies, transfer them first", fmt.Errorf("team %s owns %d strategies", teamID, len(strategyQuery.Result)))
+	}
+	err = svc.Repo.DeleteTeam(ctx, teamObjID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return errs.NewHTTPStatusError(http.StatusNotFound, "team not found", err)
+	}
+	return err
+}
+
+func (svc *Service) QueryTeams(ctx context.Context, opt *domain.QueryTeamOptions) error {
+	return svc.Repo.QueryTeams(ctx, opt)
+}
+
+func (svc *Service) getTeamByID(ctx context.Context, teamID string) (*domain.Team, error) {
+	teamObjID, err := bson.ObjectIDFromHex(teamID)
+	if err != nil {
+		return nil, errs.NewHTTPStatusError(http.StatusBadRequest, "invalid team ID", err)
+	}
+	queryOpt := &domain.QueryTeamOptions{IDs: []bson.ObjectID{teamObjID}}
+	if err := svc.Repo.QueryTeams(ctx, queryOpt); err != nil {
+		return nil, err
+	}
+	if len(queryOpt.Result) == 0 {
+		return nil, errs.NewHTTPStatusError(http.StatusNotFound, "team not found", fmt.Errorf("team %s not found", teamID))
+	}
+	return queryOpt.Result[0], nil
+}
+
+// teamIDsOf returns the teams the user with userID is a member of
+func (svc *Service) teamIDsOf(ctx context.Context, userID bson.ObjectID) ([]bson.ObjectID, error) {
+	queryOpt := &domain.QueryTeamOptions{MemberIDs: []bson.ObjectID{userID}}
+	if err := svc.Repo.QueryTeams(ctx, queryOpt); err != nil {
+		return nil, errors.WithMessagef(err, "query teams of user %s failed", userID.Hex())
+	}
+	teamIDs := make([]bson.ObjectID, len(queryOpt.Result))
+	for i, team := range queryOpt.Result {
+		teamIDs[i] = team.ID
+	}
+	return teamIDs, nil
+}
+
+func (svc *Service) checkTeamNameAvailable(ctx context.Context, name string) error {
+	queryOpt := &domain.QueryTeamOptions{Names: []string{name}}
+	if err := svc.Repo.QueryTeams(ctx, queryOpt); err != nil {
+		return err
+	}
+	if len(queryOpt.Result) > 0 {
+		return errs.NewHTTPStatusError(http.StatusConflict, "team name already exists", fmt.Errorf("team %s exists", name))
+	}
+	return nil
+}
+
+func (svc *Service) checkUsersExist(ctx context.Context, userIDs []bson.ObjectID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	queryOpt := &domain.QueryUserOptions{IDs: userIDs}
+	if err := svc.Repo.QueryUsers(ctx, queryOpt); err != nil {
+		return err
+	}
+	if len(queryOpt.Result) != len(userIDs) {
+		return errs.NewHTTPStatusError(http.StatusBadRequest, "Some users not found", errors.New("invalid user IDs"))
+	}
+	return nil
+}
+
+func compactObjectIDs(ids []bson.ObjectID) []bson.ObjectID {
+	ids = slices.Clone(ids)
+	slices.SortFunc(ids, func(a, b bson.ObjectID) int { return bytes.Compare(a[:], b[:]) })
+	return slices.Compact(ids)
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// newTeamTestService returns a service whose repository holds strategy, owned by team, and team
+func newTeamTestService(t *testing.T, strategy *domain.ScheduleStrategy, team *domain.Team) (*Service, *domain.MockRepository) {
+	mockRepo := domain.NewMockRepository(t)
+	mockRepo.EXPECT().QueryStrategies(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt *domain.QueryStrategyOptions) {
+			opt.Result = nil
+			if len(opt.IDs) > 0 && opt.IDs[0] != strategy.ID {
+				return
+			}
+			if len(opt.TeamIDs) > 0 && opt.TeamIDs[0] != strategy.TeamID {
+				return
+			}
+			opt.Result = []*domain.ScheduleStrategy{strategy}
+		}).Return(nil).Maybe()
+	mockRepo.EXPECT().QueryTeams(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt *domain.QueryTeamOptions) {
+			opt.Result = nil
+			if len(opt.IDs) > 0 && opt.IDs[0] != team.ID {
+				return
+			}
+			if len(opt.Names) > 0 && opt.Names[0] != team.Name {
+				return
+			}
+			if len(opt.MemberIDs) > 0 && !team.HasMember(opt.MemberIDs[0]) {
+				return
+			}
+			opt.Result = []*domain.Team{team}
+		}).Return(nil).Maybe()
+	return &Service{Repo: mockRepo}, mockRepo
+}
+
+func TestDeleteTeamStrategy(t *testing.T) {
+	creator, member, outsider := bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()
+	team := &domain.Team{BaseEntity: domain.BaseEntity{ID: bson.NewObjectID()}, Name: "platform", MemberIDs: []bson.ObjectID{creator, member}}
+	strategy := &domain.ScheduleStrategy{BaseEntity: domain.BaseEntity{ID: bson.NewObjectID(), CreatorID: creator}, TeamID: team.ID}
+	svc, mockRepo := newTeamTestService(t, strategy, team)
+	ctx := context.Background()
+
+	err := svc.DeleteScheduleStrategy(ctx, &domain.Claims{UID: outsider.Hex()}, strategy.ID.Hex())
+	requireHTTPStatus(t, err, http.StatusNotFound)
+
+	mockRepo.EXPECT().QueryIntents(mock.Anything, mock.Anything).Return(nil).Once()
+	mockRepo.EXPECT().DeleteIntentsByStrategyID(mock.Anything, strategy.ID).Return(nil).Once()
+	mockRepo.EXPECT().DeleteStrategy(mock.Anything, strategy.ID).Return(nil).Once()
+	require.NoError(t, svc.DeleteScheduleStrategy(ctx, &domain.Claims{UID: member.Hex()}, strategy.ID.Hex()))
+}
+
+func TestTransferScheduleStrategy(t *testing.T) {
+	creator, successor := bson.NewObjectID(), bson.NewObjectID()
+	team := &domain.Team{BaseEntity: domain.BaseEntity{ID: bson.NewObjectID()}, Name: "platform", MemberIDs: []bson.ObjectID{successor}}
+	strategy := &domain.ScheduleStrategy{BaseEntity: domain.BaseEntity{ID: bson.NewObjectID(), CreatorID: creator}}
+	svc, mockRepo := newTeamTestService(t, strategy, team)
+	ctx := context.Background()
+	operator := &domain.Claims{UID: bson.NewObjectID().Hex()}
+
+	mockRepo.EXPECT().QueryUsers(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt *domain.QueryUserOptions) {
+			opt.Result = []*domain.User{{BaseEntity: domain.BaseEntity{ID: successor}}}
+		}).Return(nil).Once()
+	mockRepo.EXPECT().UpdateStrategy(mock.Anything, strategy).Return(nil).Once()
+	mockRepo.EXPECT().UpdateIntentsOwner(mock.Anything, strategy).Return(nil).Once()
+	err := svc.TransferScheduleStrategy(ctx, operator, strategy.ID.Hex(), domain.TransferStrategyOptions{CreatorID: successor, TeamID: team.ID})
+	require.NoError(t, err)
+	assert.Equal(t, successor, strategy.CreatorID)
+	assert.Equal(t, team.ID, strategy.TeamID)
+
+	err = svc.TransferScheduleStrategy(ctx, operator, strategy.ID.Hex(), domain.TransferStrategyOptions{TeamID: bson.NewObjectID()})
+	requireHTTPStatus(t, err, http.StatusNotFound)
+	err = svc.TransferScheduleStrategy(ctx, operator, bson.NewObjectID().Hex(), domain.TransferStrategyOptions{TeamID: team.ID})
+	requireHTTPStatus(t, err, http.StatusNotFound)
+	err = svc.TransferScheduleStrategy(ctx, operator, strategy.ID.Hex(), domain.TransferStrategyOptions{CreatorID: creator})
+	requireHTTPStatus(t, err, http.StatusBadRequest)
+	assert.Equal(t, team.ID, strategy.TeamID, "a rejected transfer leaves the strategy alone")
+}
+
+func TestDeleteTeamOwningStrategies(t *testing.T) {
+	team := &domain.Team{BaseEntity: domain.BaseEntity{ID: bson.NewObjectID()}, Name: "platform"}
+	strategy := &domain.ScheduleStrategy{BaseEntity: domain.BaseEntity{ID: bson.NewObjectID(), CreatorID: bson.NewObjectID()}, TeamID: team.ID}
+	svc, mockRepo := newTeamTestService(t, strategy, team)
+	ctx := context.Background()
+	operator := &domain.Claims{UID: bson.NewObjectID().Hex()}
+
+	err := svc.DeleteTeam(ctx, operator, team.ID.Hex())
+	requireHTTPStatus(t, err, http.StatusConflict)
+
+	strategy.TeamID = bson.ObjectID{}
+	mockRepo.EXPECT().DeleteTeam(mock.Anything, team.ID).Return(nil).Once()
+	require.NoError(t, svc.DeleteTeam(ctx, operator, team.ID.Hex()))
+	mockRepo.EXPECT().DeleteTeam(mock.Anything, team.ID).Return(domain.ErrNotFound).Once()
+	requireHTTPStatus(t, svc.DeleteTeam(ctx, operator, team.ID.Hex()), http.StatusNotFound)
+}
+
+func TestCreateTeamValidation(t *testing.T) {
+	team := &domain.Team{BaseEntity: domain.BaseEntity{ID: bson.NewObjectID()}, Name: "platform"}
+	svc, mockRepo := newTeamTestService(t, &domain.ScheduleStrategy{}, team)
+	ctx := context.Background()
+	operator := &domain.Claims{UID: bson.NewObjectID().Hex()}
+
+	requireHTTPStatus(t, svc.CreateTeam(ctx, operator, &domain.Team{}), http.StatusBadRequest)
+	requireHTTPStatus(t, svc.CreateTeam(ctx, operator, &domain.Team{Name: "platform"}), http.StatusConflict)
+
+	mockRepo.EXPECT().QueryUsers(mock.Anything, mock.Anything).Return(nil).Once()
+	member := bson.NewObjectID()
+	err := svc.CreateTeam(ctx, operator, &domain.Team{Name: "sre", MemberIDs: []bson.ObjectID{member, member}})
+	requireHTTPStatus(t, err, http.StatusBadRequest)
+}
@@ -0,0 +1,404 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/manager/errs"
+	"github.com/Gthulhu/api/pkg/logger"
+	"github.com/Gthulhu/api/pkg/totp"
+	"github.com/Gthulhu/api/pkg/util"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	// twoFactorLoginAudience keeps two-factor login tokens from being accepted as access tokens
+	twoFactorLoginAudience = "gthulhu-2fa-login"
+	twoFactorLoginTTL      = 5 * time.Minute
+	defaultTwoFactorIssuer = "Gthulhu"
+	recoveryCodeCount      = 10
+	recoveryCodeBytes      = 5
+	twoFactorKeySize       = 32
+)
+
+// twoFactorLoginClaims identify a user whose password was verified and who still has to present a
+// second factor
+type twoFactorLoginClaims struct {
+	SessionVersion int `json:"sv,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// decodeTwoFactorKey returns the key TOTP secrets are encrypted with, nil when two-factor
+// authentication is disabled. The key is never derived from another secret, so that rotating the
+// JWT signing keys can't make the stored secrets undecryptable.
+func decodeTwoFactorKey(cfg config.TwoFactorConfig) ([]byte, error) {
+	if !cfg.Enable {
+		return nil, nil
+	}
+	encoded := cfg.EncryptionKey.Value()
+	if encoded == "" {
+		return nil, errors.New("encryption_key is required when two-factor authentication is enabled")
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode base64: %w", err)
+	}
+	if len(key) != twoFactorKeySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", twoFactorKeySize, len(key))
+	}
+	return key, nil
+}
+
+func errTwoFactorDisabled() error {
+	return errs.NewHTTPStatusError(http.StatusForbidden, "two-factor authentication is disabled", errors.New("two_factor is not enabled in the manager configuration"))
+}
+
+func (svc *Service) encryptTOTPSecret(secret string) (string, error) {
+	if len(svc.twoFactorKey) == 0 {
+		return "", errTwoFactorDisabled()
+	}
+	return util.EncryptAESGCM(svc.twoFactorKey, []byte(secret))
+}
+
+func (svc *Service) decryptTOTPSecret(encrypted string) (string, error) {
+	if len(svc.twoFactorKey) == 0 {
+		return "", errTwoFactorDisabled()
+	}
+	secret, err := util.DecryptAESGCM(svc.twoFactorKey, encrypted)
+	if err != nil {
+		return "", errors.WithMessage(err, "decrypt TOTP secret failed")
+	}
+	return string(secret), nil
+}
+
+// twoFactorEnrollmentRequired reports whether one of roles requires two-factor authentication user
+// hasn't enabled, users of an identity provider authenticate there
+func twoFactorEnrollmentRequired(user *domain.User, roles []*domain.Role) bool {
+	if user.IsExternal() || user.TwoFactorEnabled() {
+		return false
+	}
+	for _, role := range roles {
+		if role.RequireTwoFactor {
+			return true
+		}
+	}
+	return false
+}
+
+func (svc *Service) issueTwoFactorLoginToken(user *domain.User, now time.Time) (*domain.LoginResult, error) {
+	expiresAt := now.Add(twoFactorLoginTTL)
+	token, err := svc.signJWT(twoFactorLoginClaims{
+		SessionVersion: user.SessionVersion,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.ID.Hex(),
+			Audience:  jwt.ClaimStrings{twoFactorLoginAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "sign two-factor login token failed")
+	}
+	return &domain.LoginResult{TwoFactorToken: token, TwoFactorTokenExpiresAt: expiresAt}, nil
+}
+
+// VerifyTwoFactorLogin completes the login of a user with two-factor authentication, code is a
+// TOTP code or an unused recovery code. Wrong codes count as failed logins.
+func (svc *Service) VerifyTwoFactorLogin(ctx context.Context, twoFactorToken, code, clientIP string) (*domain.TokenPair, error) {
+	now := time.Now()
+	if svc.loginThrottle.blocked(clientIP, now) {
+		return nil, errs.NewHTTPStatusError(http.StatusTooManyRequests, "too many failed logins, try again later", fmt.Errorf("client %s exceeded the failed login limit", clientIP))
+	}
+	claims := &twoFactorLoginClaims{}
+	_, err := jwt.ParseWithClaims(twoFactorToken, claims, svc.jwtKeyFunc, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}), jwt.WithAudience(twoFactorLoginAudience), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, errs.NewHTTPStatusError(http.StatusUnauthorized, "invalid two-factor token", err)
+	}
+	uid, err := bson.ObjectIDFromHex(claims.Subject)
+	if err != nil {
+		return nil, errs.NewHTTPStatusError(http.StatusUnauthorized, "invalid two-factor token", err)
+	}
+	user, err := svc.getUserByID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if user.Locked(now) {
+		svc.loginThrottle.recordFailure(clientIP, now)
+		return nil, errLoginLocked(user)
+	}
+	if user.Status == domain.UserStatusInactive {
+		return nil, errs.NewHTTPStatusError(http.StatusUnauthorized, "user is inactive", fmt.Errorf("user %s is inactive", claims.Subject))
+	}
+	if !user.TwoFactorEnabled() || claims.SessionVersion != user.SessionVersion {
+		return nil, errs.NewHTTPStatusError(http.StatusUnauthorized, "invalid two-factor token", fmt.Errorf("two-factor login of user %s is no longer valid", claims.Subject))
+	}
+
+	ok, err := svc.verifySecondFactor(ctx, user, code, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, svc.recordLoginFailure(ctx, user, clientIP, "invalid two-factor code", now)
+	}
+	err = svc.Repo.RecordLoginSuccess(ctx, user.ID, domain.LoginAttempt{Time: now.UnixMilli(), IP: clientIP, Success: true})
+	if err != nil {
+		return nil, errors.WithMessagef(err, "db: record login of username %s failed", user.UserName)
+	}
+	return svc.issueTokens(ctx, user, "")
+}
+
+// verifySecondFactor consumes code if it's a valid TOTP code or an unused recovery code of user
+func (svc *Service) verifySecondFactor(ctx context.Context, user *domain.User, code string, now time.Time) (bool, error) {
+	code = strings.TrimSpace(code)
+	if !isTOTPCode(code) {
+		used, err := svc.Repo.UseRecoveryCode(ctx, user.ID, hashRecoveryCode(code))
+		if err != nil {
+			return false, errors.WithMessagef(err, "db: use recovery code of user %s failed", user.UserName)
+		}
+		if used {
+			logger.Logger(ctx).Warn().Msgf("user %s logged in with a recovery code, %d left", user.UserName, len(user.TwoFactor.RecoveryCodeHashes)-1)
+		}
+		return used, nil
+	}
+	secret, err := svc.decryptTOTPSecret(user.TwoFactor.Secret)
+	if err != nil {
+		return false, err
+	}
+	step, ok := totp.Validate(secret, code, now)
+	if !ok {
+		return false, nil
+	}
+	used, err := svc.Repo.UseTwoFactorStep(ctx, user.ID, step)
+	if err != nil {
+		return false, errors.WithMessagef(err, "db: use two-factor code of user %s failed", user.UserName)
+	}
+	return used, nil
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != totp.Digits {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// generateRecoveryCodes returns new recovery codes formatted as xxxxx-xxxxx and their hashes
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		raw, err := util.RandomHex(recoveryCodeBytes)
+		if err != nil {
+			return nil, nil, fmt.Errorf("generate recovery code: %w", err)
+		}
+		code := raw[:len(raw)/2] + "-" + raw[len(raw)/2:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode ignores case, spaces and dashes so that codes can be typed as they're displayed
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+func (svc *Service) getLocalUser(ctx context.Context, claims *domain.Claims) (*domain.User, error) {
+	uid, err := claims.GetBsonObjectUID()
+	if err != nil {
+		return nil, errors.WithMessagef(err, "invalid user ID %s", claims.UID)
+	}
+	user, err := svc.getUserByID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsExternal() {
+		return nil, errs.NewHTTPStatusError(http.StatusBadRequest, "two-factor authentication of single sign-on users is managed by the identity provider", fmt.Errorf("user %s is provisioned by %s", user.UserName, user.IdentityProvider))
+	}
+	return user, nil
+}
+
+func errTwoFactorNotEnabled(user *domain.User) error {
+	return errs.NewHTTPStatusError(http.StatusBadRequest, "two-factor authentication is not enabled", fmt.Errorf("user %s has no two-factor authentication", user.UserName))
+}
+
+func errInvalidTwoFactorCode(user *domain.User) error {
+	return errs.NewHTTPStatusError(http.StatusUnauthorized, "invalid two-factor code", fmt.Errorf("two-factor code of user %s not match", user.UserName))
+}
+
+// BeginTwoFactorEnrollment generates a TOTP secret for the user, it's enabled once a code of it is
+// confirmed with ConfirmTwoFactorEnrollment
+func (svc *Service) BeginTwoFactorEnrollment(ctx context.Context, claims *domain.Claims) (*domain.TwoFactorEnrollment, error) {
+	user, err := svc.getLocalUser(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled() {
+		return nil, errs.NewHTTPStatusError(http.StatusConflict, "two-factor authentication is already enabled", fmt.Errorf("user %s already enabled two-factor authentication", user.UserName))
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("generate TOTP secret: %w", err)
+	}
+	encrypted, err := svc.encryptTOTPSecret(secret)
+	if err != nil {
+		return nil, errors.WithMessage(err, "encrypt TOTP secret failed")
+	}
+	user.TwoFactor = &domain.TwoFactor{PendingSecret: encrypted}
+	user.UpdatedTime = time.Now().UnixMilli()
+	user.UpdaterID = user.ID
+	if err := svc.updateUser(ctx, user); err != nil {
+		return nil, errors.WithMessagef(err, "db: update user %s failed", user.UserName)
+	}
+	issuer := svc.twoFactorIssuer
+	if issuer == "" {
+		issuer = defaultTwoFactorIssuer
+	}
+	return &domain.TwoFactorEnrollment{Secret: secret, URI: totp.URI(issuer, user.UserName, secret)}, nil
+}
+
+// ConfirmTwoFactorEnrollment enables the pending TOTP secret of the user if code matches it, the
+// returned recovery codes are only shown this once
+func (svc *Service) ConfirmTwoFactorEnrollment(ctx context.Context, claims *domain.Claims, code string) ([]string, error) {
+	user, err := svc.getLocalUser(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled() {
+		return nil, errs.NewHTTPStatusError(http.StatusConflict, "two-factor authentication is already enabled", fmt.Errorf("user %s already enabled two-factor authentication", user.UserName))
+	}
+	if user.TwoFactor == nil || user.TwoFactor.PendingSecret == "" {
+		return nil, errs.NewHTTPStatusError(http.StatusBadRequest, "no two-factor enrollment in progress", fmt.Errorf("user %s has no pending TOTP secret", user.UserName))
+	}
+	secret, err := svc.decryptTOTPSecret(user.TwoFactor.PendingSecret)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	step, ok := totp.Validate(secret, strings.TrimSpace(code), now)
+	if !ok {
+		return nil, errInvalidTwoFactorCode(user)
+	}
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	user.TwoFactor = &domain.TwoFactor{
+		Enabled:            true,
+		Secret:             user.TwoFactor.PendingSecret,
+		RecoveryCodeHashes: hashes,
+		LastUsedStep:       step,
+		EnabledTime:        now.UnixMilli(),
+	}
+	user.UpdatedTime = now.UnixMilli()
+	user.UpdaterID = user.ID
+	if err := svc.updateUser(ctx, user); err != nil {
+		return nil, errors.WithMessagef(err, "db: update user %s failed", user.UserName)
+	}
+	return codes, nil
+}
+
+// DisableTwoFactor turns off two-factor authentication of the user, it takes both the password and
+// a TOTP or recovery code
+func (svc *Service) DisableTwoFactor(ctx context.Context, claims *domain.Claims, password, code string) error {
+	user, err := svc.getLocalUser(ctx, claims)
+	if err != nil {
+		return err
+	}
+	if !user.TwoFactorEnabled() {
+		return errTwoFactorNotEnabled(user)
+	}
+	ok, err := user.Password.Cmp(password)
+	if err != nil {
+		return errors.WithMessagef(err, "compare password for uid %s failed", claims.UID)
+	}
+	if !ok {
+		return errs.NewHTTPStatusError(http.StatusUnauthorized, "invalid password", fmt.Errorf("disable two-factor failed, compare password for uid %s not match", claims.UID))
+	}
+	now := time.Now()
+	ok, err = svc.verifySecondFactor(ctx, user, code, now)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errInvalidTwoFactorCode(user)
+	}
+	user.TwoFactor = nil
+	user.UpdatedTime = now.UnixMilli()
+	user.UpdaterID = user.ID
+	return svc.updateUser(ctx, user)
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the user after checking a TOTP or
+// recovery code
+func (svc *Service) RegenerateRecoveryCodes(ctx context.Context, claims *domain.Claims, code string) ([]string, error) {
+	user, err := svc.getLocalUser(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TwoFactorEnabled() {
+		return nil, errTwoFactorNotEnabled(user)
+	}
+	now := time.Now()
+	ok, err := svc.verifySecondFactor(ctx, user, code, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errInvalidTwoFactorCode(user)
+	}
+	// reload the user so that the step or recovery code just used isn't restored
+	user, err = svc.getUserByID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	user.TwoFactor.RecoveryCodeHashes = hashes
+	user.UpdatedTime = now.UnixMilli()
+	user.UpdaterID = user.ID
+	if err := svc.updateUser(ctx, user); err != nil {
+		return nil, errors.WithMessagef(err, "db: update user %s failed", user.UserName)
+	}
+	return codes, nil
+}
+
+// ResetTwoFactor removes the authenticator and recovery codes of a user who lost them, the user can
+// enroll again after logging in with the password
+func (svc *Service) ResetTwoFactor(ctx context.Context, operator *domain.Claims, id string) error {
+	operatorID, err := operator.GetBsonObjectUID()
+	if err != nil {
+		return errors.WithMessagef(err, "invalid operator ID %s", operator.UID)
+	}
+	uid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return errs.NewHTTPStatusError(http.StatusUnprocessableEntity, "invalid user ID", fmt.Errorf("invalid user ID %s: %v", id, err))
+	}
+	user, err := svc.getUserByID(ctx, uid)
+	if err != nil {
+		return err
+	}
+	user.TwoFactor = nil
+	user.UpdatedTime = time.Now().UnixMilli()
+	user.UpdaterID = operatorID
+	return svc.updateUser(ctx, user)
+}
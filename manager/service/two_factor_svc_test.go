@@ -0,0 +1,201 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/pkg/totp"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// newTwoFactorTestService extends the login protection test service with the two-factor repository
+// methods and an admin role requiring two-factor authentication
+func newTwoFactorTestService(t *testing.T) (*Service, *domain.User) {
+	svc, user := newLoginProtectionTestService(t, "Correct-horse-1")
+	user.Roles = []string{"admin"}
+	mockRepo := svc.Repo.(*domain.MockRepository)
+	mockRepo.EXPECT().UseTwoFactorStep(mock.Anything, user.ID, mock.Anything).
+		RunAndReturn(func(ctx context.Context, id bson.ObjectID, step int64) (bool, error) {
+			if !user.TwoFactorEnabled() || user.TwoFactor.LastUsedStep >= step {
+				return false, nil
+			}
+			user.TwoFactor.LastUsedStep = step
+			return true, nil
+		}).Maybe()
+	mockRepo.EXPECT().UseRecoveryCode(mock.Anything, user.ID, mock.Anything).
+		RunAndReturn(func(ctx context.Context, id bson.ObjectID, codeHash string) (bool, error) {
+			if !user.TwoFactorEnabled() || !slices.Contains(user.TwoFactor.RecoveryCodeHashes, codeHash) {
+				return false, nil
+			}
+			user.TwoFactor.RecoveryCodeHashes = slices.DeleteFunc(user.TwoFactor.RecoveryCodeHashes, func(h string) bool { return h == codeHash })
+			return true, nil
+		}).Maybe()
+	mockRepo.EXPECT().QueryRoles(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, opt *domain.QueryRoleOptions) {
+			opt.Result = []*domain.Role{{Name: "admin", RequireTwoFactor: true, Policies: []domain.RolePolicy{{PermissionKey: domain.ScheduleStrategyUpdate}}}}
+		}).Return(nil).Maybe()
+	mockRepo.EXPECT().IsAccessTokenRevoked(mock.Anything, mock.Anything).Return(false, nil).Maybe()
+	svc.twoFactorIssuer = "Gthulhu"
+	svc.twoFactorKey = bytes.Repeat([]byte{1}, twoFactorKeySize)
+	return svc, user
+}
+
+// enrollTwoFactor enables two-factor authentication of user and returns its secret and recovery codes
+func enrollTwoFactor(t *testing.T, svc *Service, user *domain.User) (string, []string) {
+	ctx := context.Background()
+	claims := &domain.Claims{UID: user.ID.Hex()}
+	enrollment, err := svc.BeginTwoFactorEnrollment(ctx, claims)
+	require.NoError(t, err)
+	assert.Contains(t, enrollment.URI, "otpauth://totp/Gthulhu:alice?")
+	assert.NotContains(t, user.TwoFactor.PendingSecret, enrollment.Secret, "secrets must be stored encrypted")
+
+	_, err = svc.ConfirmTwoFactorEnrollment(ctx, claims, "000000")
+	requireHTTPStatus(t, err, http.StatusUnauthorized)
+	code, err := totp.Code(enrollment.Secret, totp.Step(time.Now()))
+	require.NoError(t, err)
+	recoveryCodes, err := svc.ConfirmTwoFactorEnrollment(ctx, claims, code)
+	require.NoError(t, err)
+	require.True(t, user.TwoFactorEnabled())
+	return enrollment.Secret, recoveryCodes
+}
+
+func TestTwoFactorLogin(t *testing.T) {
+	svc, user := newTwoFactorTestService(t)
+	ctx := context.Background()
+	secret, recoveryCodes := enrollTwoFactor(t, svc, user)
+	assert.Len(t, recoveryCodes, recoveryCodeCount)
+
+	result, err := svc.Login(ctx, "alice", "Correct-horse-1", "10.0.0.1")
+	require.NoError(t, err)
+	assert.Nil(t, result.Tokens)
+	require.NotEmpty(t, result.TwoFactorToken)
+	_, _, err = svc.VerifyJWTToken(ctx, result.TwoFactorToken, "")
+	require.Error(t, err, "two-factor tokens are not access tokens")
+	// rejected for the audience alone, even when carrying the claims of an access token
+	forged, err := svc.signJWT(domain.Claims{
+		UID:              user.ID.Hex(),
+		SessionVersion:   user.SessionVersion,
+		RegisteredClaims: jwt.RegisteredClaims{Audience: jwt.ClaimStrings{twoFactorLoginAudience}, ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))},
+	})
+	require.NoError(t, err)
+	_, _, err = svc.VerifyJWTToken(ctx, forged, "")
+	requireHTTPStatus(t, err, http.StatusUnauthorized)
+
+	_, err = svc.VerifyTwoFactorLogin(ctx, result.TwoFactorToken, "000000", "10.0.0.1")
+	requireHTTPStatus(t, err, http.StatusUnauthorized)
+	assert.Equal(t, "invalid two-factor code", user.LoginHistory[len(user.LoginHistory)-1].Reason)
+	assert.Equal(t, 1, user.FailedLoginCount)
+
+	// the code of the enrollment step was used, the next step is still within the skew
+	code, err := totp.Code(secret, totp.Step(time.Now())+1)
+	require.NoError(t, err)
+	tokens, err := svc.VerifyTwoFactorLogin(ctx, result.TwoFactorToken, code, "10.0.0.1")
+	require.NoError(t, err)
+	assert.Zero(t, user.FailedLoginCount)
+	_, _, err = svc.VerifyJWTToken(ctx, tokens.AccessToken, domain.ScheduleStrategyUpdate)
+	require.NoError(t, err)
+
+	_, err = svc.VerifyTwoFactorLogin(ctx, result.TwoFactorToken, code, "10.0.0.1")
+	requireHTTPStatus(t, err, http.StatusUnauthorized)
+
+	// recovery codes work once, in any case and without the dash
+	recoveryCode := recoveryCodes[0][:5] + recoveryCodes[0][6:]
+	_, err = svc.VerifyTwoFactorLogin(ctx, result.TwoFactorToken, recoveryCode, "10.0.0.1")
+	require.NoError(t, err)
+	assert.Len(t, user.TwoFactor.RecoveryCodeHashes, recoveryCodeCount-1)
+	_, err = svc.VerifyTwoFactorLogin(ctx, result.TwoFactorToken, recoveryCodes[0], "10.0.0.1")
+	requireHTTPStatus(t, err, http.StatusUnauthorized)
+}
+
+func TestTwoFactorRequiredByRole(t *testing.T) {
+	svc, user := newTwoFactorTestService(t)
+	ctx := context.Background()
+
+	result, err := svc.Login(ctx, "alice", "Correct-horse-1", "10.0.0.1")
+	require.NoError(t, err)
+	assert.True(t, result.TwoFactorEnrollmentRequired)
+	_, _, err = svc.VerifyJWTToken(ctx, result.Tokens.AccessToken, domain.ScheduleStrategyUpdate)
+	requireHTTPStatus(t, err, http.StatusForbidden)
+	// enrolling only needs an authenticated user
+	_, _, err = svc.VerifyJWTToken(ctx, result.Tokens.AccessToken, "")
+	require.NoError(t, err)
+
+	enrollTwoFactor(t, svc, user)
+	_, _, err = svc.VerifyJWTToken(ctx, result.Tokens.AccessToken, domain.ScheduleStrategyUpdate)
+	require.NoError(t, err)
+}
+
+func TestDisableAndResetTwoFactor(t *testing.T) {
+	svc, user := newTwoFactorTestService(t)
+	ctx := context.Background()
+	claims := &domain.Claims{UID: user.ID.Hex()}
+
+	err := svc.DisableTwoFactor(ctx, claims, "Correct-horse-1", "000000")
+	requireHTTPStatus(t, err, http.StatusBadRequest)
+
+	secret, recoveryCodes := enrollTwoFactor(t, svc, user)
+	_, err = svc.BeginTwoFactorEnrollment(ctx, claims)
+	requireHTTPStatus(t, err, http.StatusConflict)
+
+	codes, err := svc.RegenerateRecoveryCodes(ctx, claims, recoveryCodes[0])
+	require.NoError(t, err)
+	assert.Len(t, user.TwoFactor.RecoveryCodeHashes, recoveryCodeCount)
+	assert.NotContains(t, user.TwoFactor.RecoveryCodeHashes, hashRecoveryCode(recoveryCodes[1]))
+
+	code, err := totp.Code(secret, totp.Step(time.Now())+1)
+	require.NoError(t, err)
+	err = svc.DisableTwoFactor(ctx, claims, "wrong", code)
+	requireHTTPStatus(t, err, http.StatusUnauthorized)
+	require.NoError(t, svc.DisableTwoFactor(ctx, claims, "Correct-horse-1", codes[0]))
+	assert.False(t, user.TwoFactorEnabled())
+
+	enrollTwoFactor(t, svc, user)
+	operator := &domain.Claims{UID: bson.NewObjectID().Hex()}
+	require.NoError(t, svc.ResetTwoFactor(ctx, operator, user.ID.Hex()))
+	assert.Nil(t, user.TwoFactor)
+}
+
+func TestTwoFactorNotForExternalUsers(t *testing.T) {
+	svc, user := newTwoFactorTestService(t)
+	user.IdentityProvider = "https://idp.example.com"
+	_, err := svc.BeginTwoFactorEnrollment(context.Background(), &domain.Claims{UID: user.ID.Hex()})
+	requireHTTPStatus(t, err, http.StatusBadRequest)
+	assert.False(t, twoFactorEnrollmentRequired(user, []*domain.Role{{RequireTwoFactor: true}}))
+}
+
+func TestTwoFactorEncryptionKey(t *testing.T) {
+	key, err := decodeTwoFactorKey(config.TwoFactorConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, key)
+	// the key isn't derived from the JWT signing key, rotating that would lock users out
+	_, err = decodeTwoFactorKey(config.TwoFactorConfig{Enable: true})
+	require.Error(t, err)
+	_, err = decodeTwoFactorKey(config.TwoFactorConfig{Enable: true, EncryptionKey: "c2hvcnQ="})
+	require.Error(t, err)
+	encoded := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, twoFactorKeySize))
+	key, err = decodeTwoFactorKey(config.TwoFactorConfig{Enable: true, EncryptionKey: config.SecretValue(encoded)})
+	require.NoError(t, err)
+	assert.Len(t, key, twoFactorKeySize)
+}
+
+func TestTwoFactorDisabled(t *testing.T) {
+	svc, user := newTwoFactorTestService(t)
+	svc.twoFactorKey = nil
+	ctx := context.Background()
+	_, err := svc.BeginTwoFactorEnrollment(ctx, &domain.Claims{UID: user.ID.Hex()})
+	requireHTTPStatus(t, err, http.StatusForbidden)
+	operator := &domain.Claims{UID: bson.NewObjectID().Hex()}
+	err = svc.CreateRole(ctx, operator, &domain.Role{Name: "admin", RequireTwoFactor: true})
+	requireHTTPStatus(t, err, http.StatusForbidden)
+}
@@ -31,7 +31,7 @@ func (svc *Service) DeleteUser(ctx context.Context, operator domain.Claims, user
 	}
 	updateUser.ID = userID
 	updateUser.DeletedTime = time.Now().UnixMilli()
-	err = svc.Repo.UpdateUser(ctx, updateUser)
+	err = svc.updateUser(ctx, updateUser)
 	if err != nil {
 		return err
 	}
@@ -45,7 +45,7 @@ func (svc *Service) UpdateUser(ctx context.Context, operator domain.Claims, user
 	}
 	user.UpdaterID = operatorID
 	user.UpdatedTime = time.Now().UnixMilli()
-	err = svc.Repo.UpdateUser(ctx, user)
+	err = svc.updateUser(ctx, user)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,126 @@
+package auditstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	DefaultFileMaxSizeBytes = 100 << 20
+	DefaultFileMaxBackups   = 5
+)
+
+// FileSink appends records as JSON lines to a file, rotating it to <path>.1 ... <path>.<maxBackups>
+// once it exceeds maxSizeBytes
+type FileSink struct {
+	path         string
+	maxSizeBytes int64
+	maxBackups   int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+func NewFileSink(path string, maxSizeBytes int64, maxBackups int) (*FileSink, error) {
+	if path == "" {
+		return nil, errors.New("audit stream file path is required")
+	}
+	if maxSizeBytes <= 0 {
+		maxSizeBytes = DefaultFileMaxSizeBytes
+	}
+	if maxBackups < 0 {
+		maxBackups = DefaultFileMaxBackups
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("create audit stream directory: %w", err)
+	}
+	sink := &FileSink{path: path, maxSizeBytes: maxSizeBytes, maxBackups: maxBackups}
+	if err := sink.open(); err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+func (f *FileSink) Name() string {
+	return "file"
+}
+
+func (f *FileSink) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return fmt.Errorf("open audit stream file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("stat audit stream file: %w", err)
+	}
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// rotate shifts the backups, dropping the oldest, and starts a new file
+func (f *FileSink) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("close audit stream file: %w", err)
+	}
+	f.file = nil
+	if f.maxBackups == 0 {
+		if err := os.Remove(f.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove audit stream file: %w", err)
+		}
+		return f.open()
+	}
+	for i := f.maxBackups - 1; i >= 1; i-- {
+		err := os.Rename(fmt.Sprintf("%s.%d", f.path, i), fmt.Sprintf("%s.%d", f.path, i+1))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("rotate audit stream file: %w", err)
+		}
+	}
+	if err := os.Rename(f.path, f.path+".1"); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("rotate audit stream file: %w", err)
+	}
+	return f.open()
+}
+
+func (f *FileSink) Write(ctx context.Context, records []Record) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		// a previous rotation failed half-way
+		if err := f.open(); err != nil {
+			return err
+		}
+	}
+	for _, record := range records {
+		line := append(append([]byte(nil), record.Data...), '\n')
+		if f.size > 0 && f.size+int64(len(line)) > f.maxSizeBytes {
+			if err := f.rotate(); err != nil {
+				return err
+			}
+		}
+		n, err := f.file.Write(line)
+		f.size += int64(n)
+		if err != nil {
+			return fmt.Errorf("write audit stream file: %w", err)
+		}
+	}
+	return f.file.Sync()
+}
+
+func (f *FileSink) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
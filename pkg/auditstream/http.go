@@ -0,0 +1,57 @@
+package auditstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Gthulhu/api/pkg/webhook"
+)
+
+// HTTPSink posts every batch as a JSON array of records to a collector
+type HTTPSink struct {
+	url     string
+	headers map[string]string
+	sender  *webhook.Sender
+}
+
+func NewHTTPSink(url string, headers map[string]string, timeout time.Duration) (*HTTPSink, error) {
+	if url == "" {
+		return nil, fmt.Errorf("audit stream HTTP collector URL is required")
+	}
+	sender := webhook.NewSender(timeout)
+	// the streamer retries failed batches itself
+	sender.MaxAttempts = 1
+	return &HTTPSink{url: url, headers: headers, sender: sender}, nil
+}
+
+func (h *HTTPSink) Name() string {
+	return "http"
+}
+
+func (h *HTTPSink) Write(ctx context.Context, records []Record) error {
+	batch := make([]json.RawMessage, len(records))
+	for i, record := range records {
+		batch[i] = record.Data
+	}
+	err := h.sender.Send(ctx, h.url, batch, h.headers)
+	var statusErr *webhook.StatusError
+	if errors.As(err, &statusErr) && permanentStatus(statusErr.StatusCode) {
+		return &PermanentError{Err: err}
+	}
+	return err
+}
+
+// permanentStatus reports whether the collector will answer the same batch with the same client
+// error, timeouts and rate limits are retried
+func permanentStatus(code int) bool {
+	return code >= http.StatusBadRequest && code < http.StatusInternalServerError &&
+		code != http.StatusRequestTimeout && code != http.StatusTooManyRequests
+}
+
+func (h *HTTPSink) Close() error {
+	return nil
+}
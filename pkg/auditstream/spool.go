@@ -0,0 +1,340 @@
+package auditstream
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultSegmentBytes = 16 << 20
+	segmentSuffix       = ".jsonl"
+	cursorFile          = "cursor"
+)
+
+// spoolRecord is the line a record is stored as
+type spoolRecord struct {
+	Time   time.Time       `json:"time"`
+	Action string          `json:"action"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// spoolPosition is the segment and byte offset of the next record to deliver
+type spoolPosition struct {
+	seq    uint64
+	offset int64
+}
+
+// spool is a durable FIFO of the records of a sink. Records are appended and synced to numbered
+// segment files and a cursor file remembers the first record not delivered yet, so that records
+// survive a restart or crash of the manager until the sink accepted them. Records are read by a
+// single worker.
+type spool struct {
+	dir          string
+	segmentBytes int64
+	notify       chan struct{}
+
+	mu        sync.Mutex
+	writer    *os.File
+	writeSeq  uint64
+	writeSize int64
+	// pending is the number of lines after the cursor
+	pending int
+
+	// read is only used by the worker
+	read spoolPosition
+}
+
+func openSpool(dir string, segmentBytes int64) (*spool, error) {
+	if segmentBytes <= 0 {
+		segmentBytes = DefaultSegmentBytes
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create audit stream spool %s: %w", dir, err)
+	}
+	s := &spool{dir: dir, segmentBytes: segmentBytes, notify: make(chan struct{}, 1)}
+	segments, err := s.segments()
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := s.readCursor()
+	if err != nil {
+		return nil, err
+	}
+	if cursor == nil {
+		cursor = &spoolPosition{seq: 1}
+		if len(segments) > 0 {
+			cursor.seq = segments[0]
+		}
+	}
+	s.read = *cursor
+	s.writeSeq = cursor.seq
+	for _, seq := range segments {
+		if seq < cursor.seq {
+			_ = os.Remove(s.segmentPath(seq))
+			continue
+		}
+		s.writeSeq = seq
+		lines, err := countLines(s.segmentPath(seq))
+		if err != nil {
+			return nil, err
+		}
+		s.pending += lines
+	}
+	if cursor.offset > 0 {
+		skipped, err := countLinesBefore(s.segmentPath(cursor.seq), cursor.offset)
+		if err != nil {
+			return nil, err
+		}
+		s.pending -= skipped
+	}
+	if err := s.openWriter(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *spool) segmentPath(seq uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d%s", seq, segmentSuffix))
+}
+
+// segments returns the sequence numbers of the segment files in ascending order
+func (s *spool) segments() ([]uint64, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("read audit stream spool %s: %w", s.dir, err)
+	}
+	var seqs []uint64
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), segmentSuffix)
+		if !ok {
+			continue
+		}
+		if seq, err := strconv.ParseUint(name, 10, 64); err == nil {
+			seqs = append(seqs, seq)
+		}
+	}
+	slices.Sort(seqs)
+	return seqs, nil
+}
+
+func (s *spool) readCursor() (*spoolPosition, error) {
+	content, err := os.ReadFile(filepath.Join(s.dir, cursorFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read audit stream spool cursor: %w", err)
+	}
+	var pos spoolPosition
+	if _, err := fmt.Sscanf(string(content), "%d %d", &pos.seq, &pos.offset); err != nil {
+		return nil, fmt.Errorf("parse audit stream spool cursor %q: %w", content, err)
+	}
+	return &pos, nil
+}
+
+func (s *spool) writeCursor(pos spoolPosition) error {
+	tmp := filepath.Join(s.dir, cursorFile+".tmp")
+	if err := writeFileSync(tmp, fmt.Appendf(nil, "%d %d\n", pos.seq, pos.offset)); err != nil {
+		return fmt.Errorf("write audit stream spool cursor: %w", err)
+	}
+	return os.Rename(tmp, filepath.Join(s.dir, cursorFile))
+}
+
+func writeFileSync(path string, content []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// openWriter opens the last segment for appending, a line torn by a crash is terminated so that
+// the next record starts on its own line
+func (s *spool) openWriter() error {
+	file, err := os.OpenFile(s.segmentPath(s.writeSeq), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("open audit stream spool segment: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("stat audit stream spool segment: %w", err)
+	}
+	size := info.Size()
+	if size > 0 {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, size-1); err != nil {
+			file.Close()
+			return fmt.Errorf("read audit stream spool segment: %w", err)
+		}
+		if last[0] != '\n' {
+			if _, err := file.Write([]byte{'\n'}); err != nil {
+				file.Close()
+				return fmt.Errorf("repair audit stream spool segment: %w", err)
+			}
+			size++
+			s.pending++
+		}
+	}
+	s.writer = file
+	s.writeSize = size
+	return nil
+}
+
+// append stores the record durably before returning
+func (s *spool) append(record Record) error {
+	line, err := json.Marshal(spoolRecord{Time: record.Time, Action: record.Action, Data: record.Data})
+	if err != nil {
+		return fmt.Errorf("encode audit record: %w", err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.writer == nil {
+		return errors.New("audit stream spool is closed")
+	}
+	if s.writeSize > 0 && s.writeSize+int64(len(line)) > s.segmentBytes {
+		if err := s.writer.Close(); err != nil {
+			return fmt.Errorf("close audit stream spool segment: %w", err)
+		}
+		s.writer = nil
+		s.writeSeq++
+		if err := s.openWriter(); err != nil {
+			return err
+		}
+	}
+	n, err := s.writer.Write(line)
+	s.writeSize += int64(n)
+	if err != nil {
+		return fmt.Errorf("write audit stream spool: %w", err)
+	}
+	if err := s.writer.Sync(); err != nil {
+		return fmt.Errorf("sync audit stream spool: %w", err)
+	}
+	s.pending++
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// len returns the number of records not delivered yet
+func (s *spool) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pending
+}
+
+// next reads up to max records after the read position. It returns the position after them and
+// the number of lines read, which includes lines that can't be decoded and are skipped.
+func (s *spool) next(max int) ([]Record, spoolPosition, int, error) {
+	s.mu.Lock()
+	writeSeq := s.writeSeq
+	s.mu.Unlock()
+
+	pos := s.read
+	var records []Record
+	lines := 0
+	for len(records) < max {
+		file, err := os.Open(s.segmentPath(pos.seq))
+		if errors.Is(err, os.ErrNotExist) && pos.seq < writeSeq {
+			pos = spoolPosition{seq: pos.seq + 1}
+			continue
+		}
+		if err != nil {
+			return records, pos, lines, fmt.Errorf("open audit stream spool segment: %w", err)
+		}
+		if _, err := file.Seek(pos.offset, io.SeekStart); err != nil {
+			file.Close()
+			return records, pos, lines, fmt.Errorf("seek audit stream spool segment: %w", err)
+		}
+		reader := bufio.NewReader(file)
+		for len(records) < max {
+			line, err := reader.ReadBytes('\n')
+			if err != nil {
+				// EOF, a line without its newline is still being appended
+				break
+			}
+			pos.offset += int64(len(line))
+			lines++
+			var stored spoolRecord
+			if err := json.Unmarshal(bytes.TrimSpace(line), &stored); err != nil {
+				continue
+			}
+			records = append(records, Record{Time: stored.Time, Action: stored.Action, Data: stored.Data})
+		}
+		file.Close()
+		if len(records) >= max || pos.seq >= writeSeq {
+			break
+		}
+		// the segment is complete once a later one exists
+		pos = spoolPosition{seq: pos.seq + 1}
+	}
+	return records, pos, lines, nil
+}
+
+// commit moves the cursor past records that were delivered and removes the segments before it
+func (s *spool) commit(pos spoolPosition, lines int) error {
+	if err := s.writeCursor(pos); err != nil {
+		return err
+	}
+	for seq := s.read.seq; seq < pos.seq; seq++ {
+		_ = os.Remove(s.segmentPath(seq))
+	}
+	s.read = pos
+	s.mu.Lock()
+	s.pending -= lines
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *spool) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.writer == nil {
+		return nil
+	}
+	err := s.writer.Close()
+	s.writer = nil
+	return err
+}
+
+func countLines(path string) (int, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("read audit stream spool segment: %w", err)
+	}
+	return bytes.Count(content, []byte{'\n'}), nil
+}
+
+func countLinesBefore(path string, offset int64) (int, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read audit stream spool segment: %w", err)
+	}
+	return bytes.Count(content[:min(offset, int64(len(content)))], []byte{'\n'}), nil
+}
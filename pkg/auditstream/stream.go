@@ -0,0 +1,364 @@
+// Package auditstream streams audit records to external sinks such as JSON-lines files, syslog
+// servers and HTTP collectors. Records are spooled to disk per sink and a batch is retried until
+// the sink accepts it, so every published record is delivered at least once, also across restarts.
+package auditstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Gthulhu/api/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	DefaultBatchSize     = 100
+	DefaultFlushInterval = time.Second
+	DefaultMaxBackoff    = 30 * time.Second
+	initialBackoff       = 100 * time.Millisecond
+)
+
+// Record is an audit record to export
+type Record struct {
+	Time time.Time
+	// Action identifies the kind of record, e.g. the syslog MSGID
+	Action string
+	// Data is the JSON encoding of the record
+	Data json.RawMessage
+}
+
+// Sink writes batches of records to a destination. Write must return an error unless every
+// record of the batch was accepted, the batch is then written again unless the error is a
+// PermanentError.
+type Sink interface {
+	Name() string
+	Write(ctx context.Context, records []Record) error
+	Close() error
+}
+
+// PermanentError is returned by a sink that will never accept the batch, for example because a
+// collector rejected it with a 4xx status. The batch is counted as rejected and skipped.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+type Options struct {
+	// SpoolDir keeps the records not delivered yet, in a subdirectory per sink
+	SpoolDir string
+	// SegmentBytes is the size spool files are rotated at
+	SegmentBytes int64
+	// BatchSize is the maximum number of records written to a sink at once
+	BatchSize int
+	// FlushInterval is how long a partial batch waits for more records
+	FlushInterval time.Duration
+	// MaxBackoff caps the delay between retries of a failed batch
+	MaxBackoff time.Duration
+}
+
+func (o *Options) setDefaults() {
+	if o.BatchSize <= 0 {
+		o.BatchSize = DefaultBatchSize
+	}
+	if o.FlushInterval <= 0 {
+		o.FlushInterval = DefaultFlushInterval
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = DefaultMaxBackoff
+	}
+}
+
+// SinkStats is the delivery health of a sink
+type SinkStats struct {
+	Sink        string
+	Buffered    int
+	Published   uint64
+	Delivered   uint64
+	Rejected    uint64
+	Failures    uint64
+	LastSuccess time.Time
+	LastError   string
+}
+
+// Healthy reports whether the last write attempt of the sink succeeded
+func (s SinkStats) Healthy() bool {
+	return s.LastError == ""
+}
+
+type sinkWorker struct {
+	sink  Sink
+	spool *spool
+
+	published atomic.Uint64
+	delivered atomic.Uint64
+	rejected  atomic.Uint64
+	failures  atomic.Uint64
+
+	mu          sync.Mutex
+	lastSuccess time.Time
+	lastError   string
+}
+
+var _ prometheus.Collector = (*Streamer)(nil)
+
+// Streamer fans records out to its sinks in the background
+type Streamer struct {
+	opts    Options
+	workers []*sinkWorker
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+	closed  atomic.Bool
+
+	bufferedDesc    *prometheus.Desc
+	publishedDesc   *prometheus.Desc
+	deliveredDesc   *prometheus.Desc
+	rejectedDesc    *prometheus.Desc
+	failuresDesc    *prometheus.Desc
+	lastSuccessDesc *prometheus.Desc
+	healthyDesc     *prometheus.Desc
+}
+
+// NewStreamer opens the spools of sinks and starts delivering the records they hold, Close must be
+// called to release them
+func NewStreamer(opts Options, sinks ...Sink) (*Streamer, error) {
+	if opts.SpoolDir == "" {
+		return nil, errors.New("audit stream spool directory is required")
+	}
+	opts.setDefaults()
+	labels := []string{"sink"}
+	s := &Streamer{
+		opts:   opts,
+		stopCh: make(chan struct{}),
+		bufferedDesc: prometheus.NewDesc("audit_stream_buffered_records",
+			"number of spooled audit records waiting to be delivered to the sink", labels, nil),
+		publishedDesc: prometheus.NewDesc("audit_stream_published_records_total",
+			"number of audit records published to the sink", labels, nil),
+		deliveredDesc: prometheus.NewDesc("audit_stream_delivered_records_total",
+			"number of audit records delivered to the sink", labels, nil),
+		rejectedDesc: prometheus.NewDesc("audit_stream_rejected_records_total",
+			"number of audit records the sink permanently rejected", labels, nil),
+		failuresDesc: prometheus.NewDesc("audit_stream_write_failures_total",
+			"number of failed batch writes to the sink", labels, nil),
+		lastSuccessDesc: prometheus.NewDesc("audit_stream_last_success_timestamp_seconds",
+			"unix time of the last successful batch write to the sink", labels, nil),
+		healthyDesc: prometheus.NewDesc("audit_stream_healthy",
+			"1 when the last batch write to the sink succeeded, 0 otherwise", labels, nil),
+	}
+	for _, sink := range sinks {
+		spool, err := openSpool(filepath.Join(opts.SpoolDir, sink.Name()), opts.SegmentBytes)
+		if err != nil {
+			for _, worker := range s.workers {
+				_ = worker.spool.close()
+			}
+			return nil, err
+		}
+		s.workers = append(s.workers, &sinkWorker{sink: sink, spool: spool})
+	}
+	for _, worker := range s.workers {
+		s.wg.Add(1)
+		go s.run(worker)
+	}
+	return s, nil
+}
+
+// Publish spools the record for every sink, it returns once the record is on disk
+func (s *Streamer) Publish(record Record) error {
+	if s == nil {
+		return nil
+	}
+	if s.closed.Load() {
+		return errors.New("audit streamer is closed")
+	}
+	var err error
+	for _, worker := range s.workers {
+		if appendErr := worker.spool.append(record); appendErr != nil {
+			err = errors.Join(err, fmt.Errorf("sink %s: %w", worker.sink.Name(), appendErr))
+			continue
+		}
+		worker.published.Add(1)
+	}
+	return err
+}
+
+// Close stops accepting records and delivers the spooled ones until ctx is done or a sink fails,
+// the rest is delivered after the next start
+func (s *Streamer) Close(ctx context.Context) error {
+	if s == nil || !s.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+	close(s.stopCh)
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	for _, worker := range s.workers {
+		err = errors.Join(err, worker.sink.Close(), worker.spool.close())
+	}
+	return err
+}
+
+// run delivers the spooled records of a sink in batches until the streamer is closed
+func (s *Streamer) run(worker *sinkWorker) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.opts.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-worker.spool.notify:
+			// a partial batch waits for more records until the next tick
+			if worker.spool.len() < s.opts.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+		case <-s.stopCh:
+			// a single attempt per batch since the manager is stopping, the spool keeps the rest
+			s.flush(worker)
+			return
+		}
+		if !s.flush(worker) {
+			return
+		}
+	}
+}
+
+// flush delivers batches until the spool is empty, reporting false when the streamer was closed
+// before a batch was delivered
+func (s *Streamer) flush(worker *sinkWorker) bool {
+	for {
+		batch, next, lines, err := worker.spool.next(s.opts.BatchSize)
+		if err != nil {
+			logger.Logger(context.Background()).Error().Err(err).Msgf("failed to read the audit stream spool of sink %s", worker.sink.Name())
+			return true
+		}
+		if lines == 0 {
+			return true
+		}
+		if len(batch) > 0 && !s.deliver(worker, batch) {
+			return false
+		}
+		if err := worker.spool.commit(next, lines); err != nil {
+			logger.Logger(context.Background()).Error().Err(err).Msgf("failed to commit the audit stream spool of sink %s", worker.sink.Name())
+			return true
+		}
+	}
+}
+
+// deliver writes the batch until it succeeds or is rejected, reporting false when the streamer was
+// closed first
+func (s *Streamer) deliver(worker *sinkWorker, batch []Record) bool {
+	backoff := initialBackoff
+	for !s.write(worker, batch) {
+		select {
+		case <-s.stopCh:
+			// one last attempt, Close bounds how long it may take
+			return s.write(worker, batch)
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, s.opts.MaxBackoff)
+	}
+	return true
+}
+
+// write reports whether the batch is done with, it was either accepted or permanently rejected
+func (s *Streamer) write(worker *sinkWorker, batch []Record) bool {
+	err := worker.sink.Write(context.Background(), batch)
+	worker.mu.Lock()
+	defer worker.mu.Unlock()
+	var permanent *PermanentError
+	if errors.As(err, &permanent) {
+		worker.failures.Add(1)
+		worker.rejected.Add(uint64(len(batch)))
+		worker.lastError = err.Error()
+		logger.Logger(context.Background()).Error().Err(err).Msgf("sink %s rejected %d audit records, skipping them", worker.sink.Name(), len(batch))
+		return true
+	}
+	if err != nil {
+		worker.failures.Add(1)
+		if worker.lastError == "" {
+			logger.Logger(context.Background()).Warn().Err(err).Msgf("failed to write %d audit records to sink %s, retrying", len(batch), worker.sink.Name())
+		}
+		worker.lastError = err.Error()
+		return false
+	}
+	if worker.lastError != "" {
+		logger.Logger(context.Background()).Info().Msgf("audit stream sink %s recovered", worker.sink.Name())
+	}
+	worker.delivered.Add(uint64(len(batch)))
+	worker.lastSuccess = time.Now()
+	worker.lastError = ""
+	return true
+}
+
+// Stats returns the delivery health of every sink
+func (s *Streamer) Stats() []SinkStats {
+	if s == nil {
+		return nil
+	}
+	stats := make([]SinkStats, 0, len(s.workers))
+	for _, worker := range s.workers {
+		worker.mu.Lock()
+		stats = append(stats, SinkStats{
+			Sink:        worker.sink.Name(),
+			Buffered:    worker.spool.len(),
+			Published:   worker.published.Load(),
+			Delivered:   worker.delivered.Load(),
+			Rejected:    worker.rejected.Load(),
+			Failures:    worker.failures.Load(),
+			LastSuccess: worker.lastSuccess,
+			LastError:   worker.lastError,
+		})
+		worker.mu.Unlock()
+	}
+	return stats
+}
+
+func (s *Streamer) Describe(ch chan<- *prometheus.Desc) {
+	ch <- s.bufferedDesc
+	ch <- s.publishedDesc
+	ch <- s.deliveredDesc
+	ch <- s.rejectedDesc
+	ch <- s.failuresDesc
+	ch <- s.lastSuccessDesc
+	ch <- s.healthyDesc
+}
+
+func (s *Streamer) Collect(ch chan<- prometheus.Metric) {
+	for _, stat := range s.Stats() {
+		ch <- prometheus.MustNewConstMetric(s.bufferedDesc, prometheus.GaugeValue, float64(stat.Buffered), stat.Sink)
+		ch <- prometheus.MustNewConstMetric(s.publishedDesc, prometheus.CounterValue, float64(stat.Published), stat.Sink)
+		ch <- prometheus.MustNewConstMetric(s.deliveredDesc, prometheus.CounterValue, float64(stat.Delivered), stat.Sink)
+		ch <- prometheus.MustNewConstMetric(s.rejectedDesc, prometheus.CounterValue, float64(stat.Rejected), stat.Sink)
+		ch <- prometheus.MustNewConstMetric(s.failuresDesc, prometheus.CounterValue, float64(stat.Failures), stat.Sink)
+		var lastSuccess float64
+		if !stat.LastSuccess.IsZero() {
+			lastSuccess = float64(stat.LastSuccess.UnixMilli()) / 1000
+		}
+		ch <- prometheus.MustNewConstMetric(s.lastSuccessDesc, prometheus.GaugeValue, lastSuccess, stat.Sink)
+		var healthy float64
+		if stat.Healthy() {
+			healthy = 1
+		}
+		ch <- prometheus.MustNewConstMetric(s.healthyDesc, prometheus.GaugeValue, healthy, stat.Sink)
+	}
+}
@@ -0,0 +1,387 @@
+package auditstream
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type flakySink struct {
+	mu       sync.Mutex
+	failures int
+	// reject fails the batches containing it permanently
+	reject  string
+	records []Record
+	block   chan struct{}
+}
+
+func (f *flakySink) Name() string { return "flaky" }
+
+func (f *flakySink) Write(ctx context.Context, records []Record) error {
+	if f.block != nil {
+		<-f.block
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failures > 0 {
+		f.failures--
+		return errors.New("collector unavailable")
+	}
+	for _, record := range records {
+		if f.reject != "" && string(record.Data) == f.reject {
+			return &PermanentError{Err: errors.New("collector rejected the batch")}
+		}
+	}
+	f.records = append(f.records, records...)
+	return nil
+}
+
+func (f *flakySink) Close() error { return nil }
+
+func (f *flakySink) delivered() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.records)
+}
+
+func testRecord(i int) Record {
+	return Record{Time: time.Now(), Action: "strategy.create", Data: json.RawMessage(fmt.Sprintf(`{"seq":%d}`, i))}
+}
+
+func newTestStreamer(t *testing.T, dir string, sink Sink) *Streamer {
+	t.Helper()
+	streamer, err := NewStreamer(Options{SpoolDir: dir, BatchSize: 4, FlushInterval: 5 * time.Millisecond, MaxBackoff: 10 * time.Millisecond}, sink)
+	if err != nil {
+		t.Fatalf("new streamer: %v", err)
+	}
+	return streamer
+}
+
+func publish(t *testing.T, streamer *Streamer, from, to int) {
+	t.Helper()
+	for i := from; i < to; i++ {
+		if err := streamer.Publish(testRecord(i)); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+}
+
+func waitDelivered(sink *flakySink, n int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for sink.delivered() < n && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestStreamerRetriesUntilDelivered(t *testing.T) {
+	sink := &flakySink{failures: 3}
+	streamer := newTestStreamer(t, t.TempDir(), sink)
+	publish(t, streamer, 0, 10)
+
+	waitDelivered(sink, 10)
+	if err := streamer.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if sink.delivered() != 10 {
+		t.Fatalf("expected 10 delivered records, got %d", sink.delivered())
+	}
+	for i, record := range sink.records {
+		if string(record.Data) != fmt.Sprintf(`{"seq":%d}`, i) {
+			t.Fatalf("record %d out of order: %s", i, record.Data)
+		}
+	}
+	stats := streamer.Stats()
+	if len(stats) != 1 || stats[0].Delivered != 10 || stats[0].Published != 10 || stats[0].Failures != 3 || !stats[0].Healthy() {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestStreamerSpoolSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	sink := &flakySink{failures: 1 << 30}
+	streamer := newTestStreamer(t, dir, sink)
+	publish(t, streamer, 0, 6)
+	if err := streamer.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if err := streamer.Publish(testRecord(6)); err == nil {
+		t.Fatalf("closed streamer must not accept records")
+	}
+	if sink.delivered() != 0 {
+		t.Fatalf("expected nothing delivered to the failing sink, got %d", sink.delivered())
+	}
+
+	// the records of the previous run are delivered once the sink recovered
+	sink.failures = 0
+	streamer = newTestStreamer(t, dir, sink)
+	if buffered := streamer.Stats()[0].Buffered; buffered != 6 {
+		t.Fatalf("expected 6 spooled records, got %d", buffered)
+	}
+	publish(t, streamer, 6, 8)
+	waitDelivered(sink, 8)
+	if err := streamer.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	for i, record := range sink.records {
+		if string(record.Data) != fmt.Sprintf(`{"seq":%d}`, i) {
+			t.Fatalf("record %d out of order: %s", i, record.Data)
+		}
+	}
+	if len(sink.records) != 8 {
+		t.Fatalf("expected 8 delivered records, got %d", len(sink.records))
+	}
+
+	// delivered records aren't delivered again
+	streamer = newTestStreamer(t, dir, sink)
+	if buffered := streamer.Stats()[0].Buffered; buffered != 0 {
+		t.Fatalf("expected an empty spool, got %d records", buffered)
+	}
+	if err := streamer.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+}
+
+func TestSpoolRotatesSegmentsAndRepairsTornLines(t *testing.T) {
+	dir := t.TempDir()
+	spool, err := openSpool(dir, 64)
+	if err != nil {
+		t.Fatalf("open spool: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := spool.append(testRecord(i)); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	if segments, _ := spool.segments(); len(segments) < 2 {
+		t.Fatalf("expected rotated segments, got %v", segments)
+	}
+	records, next, lines, err := spool.next(3)
+	if err != nil || len(records) != 3 || lines != 3 {
+		t.Fatalf("next: %d records, %d lines, %v", len(records), lines, err)
+	}
+	if err := spool.commit(next, lines); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	// a crash tore the last line
+	if _, err := spool.writer.Write([]byte(`{"time":`)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := spool.close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	spool, err = openSpool(dir, 64)
+	if err != nil {
+		t.Fatalf("reopen spool: %v", err)
+	}
+	defer spool.close()
+	if err := spool.append(testRecord(5)); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	records, _, lines, err = spool.next(10)
+	if err != nil {
+		t.Fatalf("next: %v", err)
+	}
+	var got []string
+	for _, record := range records {
+		got = append(got, string(record.Data))
+	}
+	if strings.Join(got, ",") != `{"seq":3},{"seq":4},{"seq":5}` || lines != 4 || spool.len() != 4 {
+		t.Fatalf("unexpected records %v, %d lines, %d pending", got, lines, spool.len())
+	}
+}
+
+func TestStreamerSkipsRejectedBatches(t *testing.T) {
+	sink := &flakySink{reject: `{"seq":1}`}
+	streamer, err := NewStreamer(Options{SpoolDir: t.TempDir(), BatchSize: 2, FlushInterval: 5 * time.Millisecond, MaxBackoff: 10 * time.Millisecond}, sink)
+	if err != nil {
+		t.Fatalf("new streamer: %v", err)
+	}
+	publish(t, streamer, 0, 6)
+	waitDelivered(sink, 4)
+	if err := streamer.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	stats := streamer.Stats()[0]
+	if sink.delivered() != 4 || stats.Rejected != 2 || stats.Delivered != 4 || stats.Buffered != 0 {
+		t.Fatalf("unexpected stats %+v, %d delivered", stats, sink.delivered())
+	}
+}
+
+func TestFileSinkRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "audit.log")
+	sink, err := NewFileSink(path, 30, 2)
+	if err != nil {
+		t.Fatalf("new file sink: %v", err)
+	}
+	defer sink.Close()
+	// every line is 10 bytes, so a file holds 3 of them
+	for i := 0; i < 10; i++ {
+		if err := sink.Write(context.Background(), []Record{testRecord(i)}); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	expected := map[string][]string{
+		path:        {`{"seq":9}`},
+		path + ".1": {`{"seq":6}`, `{"seq":7}`, `{"seq":8}`},
+		path + ".2": {`{"seq":3}`, `{"seq":4}`, `{"seq":5}`},
+	}
+	for file, lines := range expected {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("read %s: %v", file, err)
+		}
+		if got := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n"); strings.Join(got, ",") != strings.Join(lines, ",") {
+			t.Fatalf("unexpected content of %s: %v", file, got)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected only 2 backups, stat err %v", err)
+	}
+}
+
+func TestSyslogSinkUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer conn.Close()
+	sink, err := NewSyslogSink("udp", conn.LocalAddr().String(), "local0", "gthulhu", time.Second)
+	if err != nil {
+		t.Fatalf("new syslog sink: %v", err)
+	}
+	defer sink.Close()
+
+	record := Record{Time: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), Action: "POST /api/v1/roles", Data: json.RawMessage(`{"user_id":"u1"}`)}
+	if err := sink.Write(context.Background(), []Record{record}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	buf := make([]byte, 1024)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	prefix := "<134>1 2026-01-02T03:04:05.000000Z "
+	suffix := fmt.Sprintf(" gthulhu %d POST_/api/v1/roles - {\"user_id\":\"u1\"}", os.Getpid())
+	if msg := string(buf[:n]); !strings.HasPrefix(msg, prefix) || !strings.HasSuffix(msg, suffix) {
+		t.Fatalf("unexpected syslog message %q", msg)
+	}
+}
+
+func TestSyslogSinkTCPOctetCounting(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+	messages := make(chan string, 2)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for i := 0; i < 2; i++ {
+			length, err := reader.ReadString(' ')
+			if err != nil {
+				return
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(length))
+			msg := make([]byte, n)
+			if _, err := io.ReadFull(reader, msg); err != nil {
+				return
+			}
+			messages <- string(msg)
+		}
+	}()
+
+	sink, err := NewSyslogSink("tcp", listener.Addr().String(), "auth", "", time.Second)
+	if err != nil {
+		t.Fatalf("new syslog sink: %v", err)
+	}
+	defer sink.Close()
+	if err := sink.Write(context.Background(), []Record{testRecord(1), testRecord(2)}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	for i := 1; i <= 2; i++ {
+		select {
+		case msg := <-messages:
+			if !strings.HasPrefix(msg, "<38>1 ") || !strings.HasSuffix(msg, fmt.Sprintf(`strategy.create - {"seq":%d}`, i)) {
+				t.Fatalf("unexpected syslog message %q", msg)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for message %d", i)
+		}
+	}
+}
+
+func TestHTTPSink(t *testing.T) {
+	var mu sync.Mutex
+	var received []map[string]int
+	fail := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if fail {
+			fail = false
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("missing authorization header")
+		}
+		var batch []map[string]int
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		received = append(received, batch...)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sink, err := NewHTTPSink(server.URL, map[string]string{"Authorization": "Bearer token"}, time.Second)
+	if err != nil {
+		t.Fatalf("new http sink: %v", err)
+	}
+	records := []Record{testRecord(1), testRecord(2)}
+	if err := sink.Write(context.Background(), records); err == nil {
+		t.Fatalf("expected the unavailable collector to fail the batch")
+	}
+	if err := sink.Write(context.Background(), records); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	mu.Lock()
+	if len(received) != 2 || received[0]["seq"] != 1 || received[1]["seq"] != 2 {
+		t.Fatalf("unexpected batch %v", received)
+	}
+	mu.Unlock()
+
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer rejecting.Close()
+	sink, err = NewHTTPSink(rejecting.URL, nil, time.Second)
+	if err != nil {
+		t.Fatalf("new http sink: %v", err)
+	}
+	var permanent *PermanentError
+	if err := sink.Write(context.Background(), records); !errors.As(err, &permanent) {
+		t.Fatalf("expected a 400 to reject the batch permanently, got %v", err)
+	}
+}
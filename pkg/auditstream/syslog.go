@@ -0,0 +1,159 @@
+package auditstream
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultSyslogAppName = "gthulhu-manager"
+	DefaultSyslogTimeout = 5 * time.Second
+	// syslogSeverityInfo is the RFC 5424 severity of audit records
+	syslogSeverityInfo = 6
+	syslogTimeFormat   = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11, "ntp": 12, "security": 13, "console": 14,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19, "local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// SyslogSink sends records as RFC 5424 messages whose MSG is the JSON record. Over TCP messages
+// are framed with octet counting (RFC 6587), over UDP every message is a datagram.
+type SyslogSink struct {
+	network  string
+	address  string
+	facility int
+	hostname string
+	appName  string
+	procID   string
+	timeout  time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func NewSyslogSink(network, address, facility, appName string, timeout time.Duration) (*SyslogSink, error) {
+	switch network {
+	case "tcp", "udp":
+	case "":
+		network = "udp"
+	default:
+		return nil, fmt.Errorf("unsupported syslog network %q, expected tcp or udp", network)
+	}
+	if address == "" {
+		return nil, fmt.Errorf("syslog address is required")
+	}
+	if facility == "" {
+		facility = "auth"
+	}
+	facilityCode, ok := syslogFacilities[strings.ToLower(facility)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported syslog facility %q", facility)
+	}
+	if appName == "" {
+		appName = DefaultSyslogAppName
+	}
+	if timeout <= 0 {
+		timeout = DefaultSyslogTimeout
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	return &SyslogSink{
+		network:  network,
+		address:  address,
+		facility: facilityCode,
+		hostname: syslogHeaderField(hostname, 255),
+		appName:  syslogHeaderField(appName, 48),
+		procID:   strconv.Itoa(os.Getpid()),
+		timeout:  timeout,
+	}, nil
+}
+
+func (s *SyslogSink) Name() string {
+	return "syslog"
+}
+
+// syslogHeaderField makes value a valid RFC 5424 header field: printable US-ASCII without
+// spaces, at most maxLen characters, "-" when empty
+func syslogHeaderField(value string, maxLen int) string {
+	var b strings.Builder
+	for _, r := range value {
+		if b.Len() >= maxLen {
+			break
+		}
+		switch {
+		case r == ' ':
+			b.WriteByte('_')
+		case r > 32 && r < 127:
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 {
+		return "-"
+	}
+	return b.String()
+}
+
+// Format returns the RFC 5424 message of record
+func (s *SyslogSink) Format(record Record) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "<%d>1 %s %s %s %s %s - ",
+		s.facility*8+syslogSeverityInfo,
+		record.Time.UTC().Format(syslogTimeFormat),
+		s.hostname,
+		s.appName,
+		s.procID,
+		syslogHeaderField(record.Action, 32),
+	)
+	b.Write(record.Data)
+	return b.Bytes()
+}
+
+func (s *SyslogSink) Write(ctx context.Context, records []Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		dialer := net.Dialer{Timeout: s.timeout}
+		conn, err := dialer.DialContext(ctx, s.network, s.address)
+		if err != nil {
+			return fmt.Errorf("dial syslog %s %s: %w", s.network, s.address, err)
+		}
+		s.conn = conn
+	}
+	for _, record := range records {
+		msg := s.Format(record)
+		if s.network == "tcp" {
+			msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+		}
+		_ = s.conn.SetWriteDeadline(time.Now().Add(s.timeout))
+		if _, err := s.conn.Write(msg); err != nil {
+			// reconnect on the next attempt, the whole batch is sent again
+			_ = s.conn.Close()
+			s.conn = nil
+			return fmt.Errorf("write syslog message: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
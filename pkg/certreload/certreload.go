@@ -0,0 +1,246 @@
+// Package certreload keeps a TLS certificate, its key and the CA bundle verifying peers in sync
+// with their files, so that rotated certificates, for example mounted cert-manager secrets, are
+// used by new connections without a restart.
+package certreload
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ prometheus.Collector = (*Reloader)(nil)
+
+// DefaultExpiryWarning is the window ExpiryWarning uses when none is configured
+const DefaultExpiryWarning = 4 * time.Hour
+
+// ErrInvalidCA is returned when the CA bundle holds no PEM certificate
+var ErrInvalidCA = errors.New("no certificates found in CA bundle")
+
+// Source locates the PEM certificate, key and CA bundle, a file takes precedence over the inline
+// PEM and is re-read by Reload
+type Source struct {
+	CertPEM  string
+	KeyPEM   string
+	CAPEM    string
+	CertFile string
+	KeyFile  string
+	CAFile   string
+	// CAOptional allows an empty CA bundle, for servers that don't verify client certificates
+	CAOptional bool
+}
+
+// Reloader serves the current certificate and CA pool of a Source
+type Reloader struct {
+	name string
+	src  Source
+
+	mu       sync.RWMutex
+	contents []byte
+	cert     *tls.Certificate
+	caPool   *x509.CertPool
+
+	reloadErrors     atomic.Uint64
+	expiryDesc       *prometheus.Desc
+	reloadErrorsDesc *prometheus.Desc
+}
+
+// New loads the certificate of src, name labels its metrics so that the collectors of several
+// certificates can be registered together
+func New(name string, src Source) (*Reloader, error) {
+	labels := prometheus.Labels{"name": name}
+	r := &Reloader{
+		name: name,
+		src:  src,
+		expiryDesc: prometheus.NewDesc("tls_certificate_expiry_timestamp_seconds",
+			"Unix time the TLS certificate expires at", nil, labels),
+		reloadErrorsDesc: prometheus.NewDesc("tls_certificate_reload_errors_total",
+			"Failed reloads of the TLS certificate files", nil, labels),
+	}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload re-reads the files of the source and reports whether they changed, the current
+// certificate is kept when they're invalid, for example while a secret is half updated
+func (r *Reloader) Reload() (bool, error) {
+	changed, err := r.reload()
+	if err != nil {
+		r.reloadErrors.Add(1)
+	}
+	return changed, err
+}
+
+func (r *Reloader) reload() (bool, error) {
+	certPEM, err := read(r.src.CertFile, r.src.CertPEM)
+	if err != nil {
+		return false, fmt.Errorf("read certificate: %w", err)
+	}
+	keyPEM, err := read(r.src.KeyFile, r.src.KeyPEM)
+	if err != nil {
+		return false, fmt.Errorf("read key: %w", err)
+	}
+	caPEM, err := read(r.src.CAFile, r.src.CAPEM)
+	if err != nil {
+		return false, fmt.Errorf("read CA certificate: %w", err)
+	}
+	contents := bytes.Join([][]byte{certPEM, keyPEM, caPEM}, []byte{0})
+	r.mu.RLock()
+	unchanged := r.cert != nil && bytes.Equal(contents, r.contents)
+	r.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return false, fmt.Errorf("load certificate: %w", err)
+	}
+	if cert.Leaf == nil {
+		if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return false, fmt.Errorf("parse certificate: %w", err)
+		}
+	}
+	var caPool *x509.CertPool
+	if len(caPEM) > 0 {
+		caPool = x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(caPEM) {
+			return false, ErrInvalidCA
+		}
+	} else if !r.src.CAOptional {
+		return false, errors.New("CA certificate is empty")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.contents = contents
+	r.cert = &cert
+	r.caPool = caPool
+	return true, nil
+}
+
+func read(file, inline string) ([]byte, error) {
+	if file == "" {
+		return []byte(inline), nil
+	}
+	return os.ReadFile(file)
+}
+
+// Certificate returns the current certificate
+func (r *Reloader) Certificate() *tls.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert
+}
+
+// CAPool returns the current CA pool peers are verified with
+func (r *Reloader) CAPool() *x509.CertPool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.caPool
+}
+
+// NotAfter returns when the current certificate expires
+func (r *Reloader) NotAfter() time.Time {
+	return r.Certificate().Leaf.NotAfter
+}
+
+// ExpiryWarning describes the current certificate when it expires within window, an empty
+// string is returned otherwise
+func (r *Reloader) ExpiryWarning(now time.Time, window time.Duration) string {
+	if window <= 0 {
+		window = DefaultExpiryWarning
+	}
+	notAfter := r.NotAfter()
+	if remaining := notAfter.Sub(now); remaining < 0 {
+		return fmt.Sprintf("%s mTLS certificate expired at %s", r.name, notAfter.UTC().Format(time.RFC3339))
+	} else if remaining < window {
+		return fmt.Sprintf("%s mTLS certificate expires at %s", r.name, notAfter.UTC().Format(time.RFC3339))
+	}
+	return ""
+}
+
+// ServerTLSConfig returns a server configuration presenting the current certificate and verifying
+// client certificates with the current CA pool as clientAuth requires
+func (r *Reloader) ServerTLSConfig(clientAuth tls.ClientAuthType) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert := r.Certificate()
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				ClientAuth:   clientAuth,
+				ClientCAs:    r.CAPool(),
+			}, nil
+		},
+	}
+}
+
+// ClientTLSConfig returns a client configuration presenting the current certificate and verifying
+// servers with the current CA pool. The pool is only known per connection, so the standard
+// verification is replaced by an equivalent VerifyConnection.
+func (r *Reloader) ClientTLSConfig(serverName string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return r.Certificate(), nil
+		},
+		InsecureSkipVerify: true,
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return errors.New("server presented no certificate")
+			}
+			opts := x509.VerifyOptions{
+				DNSName:       cs.ServerName,
+				Roots:         r.CAPool(),
+				Intermediates: x509.NewCertPool(),
+			}
+			for _, cert := range cs.PeerCertificates[1:] {
+				opts.Intermediates.AddCert(cert)
+			}
+			_, err := cs.PeerCertificates[0].Verify(opts)
+			return err
+		},
+	}
+}
+
+// Run reloads the source every interval until stop is closed, onReload is called with the
+// outcome of every reload that changed the certificate or failed
+func (r *Reloader) Run(stop <-chan struct{}, interval time.Duration, onReload func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if changed, err := r.Reload(); changed || err != nil {
+				onReload(err)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Describe implements prometheus.Collector
+func (r *Reloader) Describe(ch chan<- *prometheus.Desc) {
+	ch <- r.expiryDesc
+	ch <- r.reloadErrorsDesc
+}
+
+// Collect implements prometheus.Collector
+func (r *Reloader) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(r.expiryDesc, prometheus.GaugeValue, float64(r.NotAfter().Unix()))
+	ch <- prometheus.MustNewConstMetric(r.reloadErrorsDesc, prometheus.CounterValue, float64(r.reloadErrors.Load()))
+}
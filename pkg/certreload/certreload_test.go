@@ -0,0 +1,181 @@
+package certreload
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPKI is a CA and a leaf certificate it signed, PEM encoded
+type testPKI struct {
+	caPEM   []byte
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func newTestPKI(t *testing.T, notAfter time.Time) testPKI {
+	t.Helper()
+	notBefore := time.Now().Add(-time.Hour)
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter.Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "test-leaf"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, caCert, &leafKey.PublicKey, caKey)
+	require.NoError(t, err)
+	leafKeyDER, err := x509.MarshalECPrivateKey(leafKey)
+	require.NoError(t, err)
+
+	return testPKI{
+		caPEM:   pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafDER}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: leafKeyDER}),
+	}
+}
+
+func (p testPKI) write(t *testing.T, dir string) Source {
+	t.Helper()
+	src := Source{
+		CertFile: filepath.Join(dir, "tls.crt"),
+		KeyFile:  filepath.Join(dir, "tls.key"),
+		CAFile:   filepath.Join(dir, "ca.crt"),
+	}
+	require.NoError(t, os.WriteFile(src.CertFile, p.certPEM, 0o600))
+	require.NoError(t, os.WriteFile(src.KeyFile, p.keyPEM, 0o600))
+	require.NoError(t, os.WriteFile(src.CAFile, p.caPEM, 0o600))
+	return src
+}
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	firstExpiry := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	src := newTestPKI(t, firstExpiry).write(t, dir)
+	r, err := New("test", src)
+	require.NoError(t, err)
+	assert.True(t, r.NotAfter().Equal(firstExpiry))
+
+	changed, err := r.Reload()
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	secondExpiry := firstExpiry.Add(24 * time.Hour)
+	newTestPKI(t, secondExpiry).write(t, dir)
+	changed, err = r.Reload()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.True(t, r.NotAfter().Equal(secondExpiry))
+
+	// a half written secret keeps the current certificate
+	require.NoError(t, os.WriteFile(src.KeyFile, []byte("invalid"), 0o600))
+	_, err = r.Reload()
+	require.Error(t, err)
+	assert.True(t, r.NotAfter().Equal(secondExpiry))
+	assert.EqualValues(t, 1, r.reloadErrors.Load())
+}
+
+func TestNewInvalidSource(t *testing.T) {
+	pki := newTestPKI(t, time.Now().Add(time.Hour))
+	_, err := New("test", Source{CertPEM: string(pki.certPEM), KeyPEM: string(pki.keyPEM)})
+	require.Error(t, err)
+	_, err = New("test", Source{CertPEM: string(pki.certPEM), KeyPEM: string(pki.keyPEM), CAPEM: "invalid"})
+	require.ErrorIs(t, err, ErrInvalidCA)
+	_, err = New("test", Source{CertFile: filepath.Join(t.TempDir(), "missing.crt")})
+	require.Error(t, err)
+
+	// servers that don't verify client certificates have no CA bundle
+	r, err := New("test", Source{CertPEM: string(pki.certPEM), KeyPEM: string(pki.keyPEM), CAOptional: true})
+	require.NoError(t, err)
+	assert.Nil(t, r.CAPool())
+}
+
+func TestExpiryWarning(t *testing.T) {
+	pki := newTestPKI(t, time.Now().Add(2*time.Hour))
+	r, err := New("test", Source{CertPEM: string(pki.certPEM), KeyPEM: string(pki.keyPEM), CAPEM: string(pki.caPEM)})
+	require.NoError(t, err)
+
+	assert.Empty(t, r.ExpiryWarning(time.Now(), time.Hour))
+	assert.Contains(t, r.ExpiryWarning(time.Now(), 0), "test mTLS certificate expires at")
+	assert.Contains(t, r.ExpiryWarning(time.Now().Add(3*time.Hour), time.Hour), "test mTLS certificate expired at")
+}
+
+func TestMutualTLSAfterRotation(t *testing.T) {
+	serverDir, clientDir := t.TempDir(), t.TempDir()
+	pki := newTestPKI(t, time.Now().Add(time.Hour))
+	serverCerts, err := New("server", pki.write(t, serverDir))
+	require.NoError(t, err)
+	clientCerts, err := New("client", pki.write(t, clientDir))
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	server.TLS = serverCerts.ServerTLSConfig(tls.RequireAndVerifyClientCert)
+	server.StartTLS()
+	defer server.Close()
+
+	get := func() error {
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: clientCerts.ClientTLSConfig("")}}
+		defer client.CloseIdleConnections()
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+	require.NoError(t, get())
+
+	// both sides move to a new CA, connections verify again once both reloaded
+	rotated := newTestPKI(t, time.Now().Add(2*time.Hour))
+	rotated.write(t, serverDir)
+	_, err = serverCerts.Reload()
+	require.NoError(t, err)
+	require.Error(t, get())
+
+	rotated.write(t, clientDir)
+	_, err = clientCerts.Reload()
+	require.NoError(t, err)
+	require.NoError(t, get())
+
+	// a server certificate of another CA is rejected
+	clientCerts.src = Source{CertPEM: string(rotated.certPEM), KeyPEM: string(rotated.keyPEM), CAPEM: string(pki.caPEM)}
+	_, err = clientCerts.Reload()
+	require.NoError(t, err)
+	require.Error(t, get())
+}
@@ -0,0 +1,58 @@
+package certreload
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Gthulhu/api/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/fx"
+)
+
+// DefaultReloadInterval is how often RegisterAndRun re-reads the files when no interval is configured
+const DefaultReloadInterval = time.Minute
+
+// RegisterAndRun exports the metrics of r while lc runs and, when its source has files, re-reads
+// them every interval so that rotated certificates are used without a restart. kind names the
+// certificate in the logs, e.g. "mTLS".
+func RegisterAndRun(lc fx.Lifecycle, r *Reloader, kind string, interval time.Duration) error {
+	if err := prometheus.Register(r); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if !errors.As(err, &alreadyRegistered) {
+			return err
+		}
+	}
+	if interval <= 0 {
+		interval = DefaultReloadInterval
+	}
+	reload := r.src.CertFile != "" || r.src.KeyFile != "" || r.src.CAFile != ""
+	stopCh := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if !reload {
+				return nil
+			}
+			go func() {
+				bgCtx := context.Background()
+				logger.Logger(bgCtx).Info().Msgf("%s certificate reload starting, interval %s", kind, interval)
+				r.Run(stopCh, interval, func(err error) {
+					if err != nil {
+						logger.Logger(bgCtx).Warn().Err(err).Msgf("%s certificate reload failed, keeping the current certificate", kind)
+						return
+					}
+					logger.Logger(bgCtx).Info().Msgf("%s certificate reloaded, expires at %s", kind, r.NotAfter().UTC().Format(time.RFC3339))
+				})
+				logger.Logger(bgCtx).Info().Msgf("%s certificate reload stopped", kind)
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stopCh)
+			prometheus.Unregister(r)
+			return nil
+		},
+	})
+	return nil
+}
@@ -0,0 +1,38 @@
+package certreload
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestRegisterAndRun(t *testing.T) {
+	dir := t.TempDir()
+	firstExpiry := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	r, err := New("test-lifecycle", newTestPKI(t, firstExpiry).write(t, dir))
+	require.NoError(t, err)
+
+	lc := fxtest.NewLifecycle(t)
+	require.NoError(t, RegisterAndRun(lc, r, "test", 10*time.Millisecond))
+	require.NoError(t, lc.Start(context.Background()))
+	expiry, err := testutil.GatherAndCount(prometheus.DefaultGatherer, "tls_certificate_expiry_timestamp_seconds")
+	require.NoError(t, err)
+	assert.Equal(t, 1, expiry, "the expiry is served by the default registry")
+	assert.True(t, prometheus.DefaultRegisterer.Unregister(r), "the metrics are registered while running")
+	require.NoError(t, prometheus.Register(r))
+
+	secondExpiry := firstExpiry.Add(24 * time.Hour)
+	newTestPKI(t, secondExpiry).write(t, dir)
+	assert.Eventually(t, func() bool {
+		return r.NotAfter().Equal(secondExpiry)
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, lc.Stop(context.Background()))
+	assert.False(t, prometheus.DefaultRegisterer.Unregister(r), "the metrics are unregistered on stop")
+}
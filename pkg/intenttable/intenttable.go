@@ -0,0 +1,165 @@
+// Package intenttable implements the binary file the decision maker publishes its resolved
+// scheduling intents to, so the sched_ext userspace scheduler on the same host can read the
+// PID to priority/time slice table without going through HTTP and JWT validation.
+//
+// The file is little endian and memory-mappable:
+//
+//	header (32 bytes)
+//	  0  [4]byte magic "GTIT"
+//	  4  uint16  format version (Version)
+//	  6  uint16  entry size in bytes (EntrySize)
+//	  8  uint32  number of entries
+//	  12 uint32  reserved, zero
+//	  16 uint64  generation, incremented on every published change
+//	  24 int64   publish time in unix nanoseconds
+//	entries (EntrySize bytes each, sorted by PID then TID)
+//	  0  int32   PID
+//	  4  int32   TID, 0 when the whole process is targeted
+//	  8  int32   priority
+//	  12 uint32  reserved, zero
+//	  16 uint64  execution time (time slice) in nanoseconds
+//
+// WriteFile replaces the file atomically with a rename, so a reader that has the file mapped
+// keeps a consistent snapshot; readers pick up a new snapshot by re-opening the path when its
+// inode (or the generation in a fresh mapping) changes.
+package intenttable
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	Version    = 1
+	HeaderSize = 32
+	EntrySize  = 24
+)
+
+var magic = [4]byte{'G', 'T', 'I', 'T'}
+
+// Entry is a single resolved scheduling intent
+type Entry struct {
+	PID           int32
+	TID           int32
+	Priority      int32
+	ExecutionTime uint64
+}
+
+// Table is a snapshot of resolved scheduling intents
+type Table struct {
+	Generation uint64
+	UpdatedAt  int64
+	Entries    []Entry
+}
+
+// SortEntries orders entries by PID then TID, the order readers may binary search on
+func SortEntries(entries []Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].PID != entries[j].PID {
+			return entries[i].PID < entries[j].PID
+		}
+		return entries[i].TID < entries[j].TID
+	})
+}
+
+// Encode serializes the table, entries must already be sorted with SortEntries
+func Encode(table *Table) []byte {
+	buf := make([]byte, HeaderSize+len(table.Entries)*EntrySize)
+	copy(buf[0:4], magic[:])
+	binary.LittleEndian.PutUint16(buf[4:6], Version)
+	binary.LittleEndian.PutUint16(buf[6:8], EntrySize)
+	binary.LittleEndian.PutUint32(buf[8:12], uint32(len(table.Entries)))
+	binary.LittleEndian.PutUint64(buf[16:24], table.Generation)
+	binary.LittleEndian.PutUint64(buf[24:32], uint64(table.UpdatedAt))
+	for i, entry := range table.Entries {
+		b := buf[HeaderSize+i*EntrySize:]
+		binary.LittleEndian.PutUint32(b[0:4], uint32(entry.PID))
+		binary.LittleEndian.PutUint32(b[4:8], uint32(entry.TID))
+		binary.LittleEndian.PutUint32(b[8:12], uint32(entry.Priority))
+		binary.LittleEndian.PutUint64(b[16:24], entry.ExecutionTime)
+	}
+	return buf
+}
+
+// Decode parses a table produced by Encode
+func Decode(data []byte) (*Table, error) {
+	if len(data) < HeaderSize {
+		return nil, errors.New("intent table too short")
+	}
+	if [4]byte(data[0:4]) != magic {
+		return nil, errors.New("invalid intent table magic")
+	}
+	if version := binary.LittleEndian.Uint16(data[4:6]); version != Version {
+		return nil, fmt.Errorf("unsupported intent table version %d", version)
+	}
+	entrySize := int(binary.LittleEndian.Uint16(data[6:8]))
+	if entrySize < EntrySize {
+		return nil, fmt.Errorf("invalid intent table entry size %d", entrySize)
+	}
+	count := int(binary.LittleEndian.Uint32(data[8:12]))
+	if len(data) < HeaderSize+count*entrySize {
+		return nil, fmt.Errorf("intent table truncated: %d entries need %d bytes, got %d", count, HeaderSize+count*entrySize, len(data))
+	}
+	table := &Table{
+		Generation: binary.LittleEndian.Uint64(data[16:24]),
+		UpdatedAt:  int64(binary.LittleEndian.Uint64(data[24:32])),
+		Entries:    make([]Entry, count),
+	}
+	for i := range table.Entries {
+		b := data[HeaderSize+i*entrySize:]
+		table.Entries[i] = Entry{
+			PID:           int32(binary.LittleEndian.Uint32(b[0:4])),
+			TID:           int32(binary.LittleEndian.Uint32(b[4:8])),
+			Priority:      int32(binary.LittleEndian.Uint32(b[8:12])),
+			ExecutionTime: binary.LittleEndian.Uint64(b[16:24]),
+		}
+	}
+	return table, nil
+}
+
+// WriteFile atomically replaces path with the encoded table by writing a temporary file
+// in the same directory and renaming it over path
+func WriteFile(path string, table *Table) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create intent table directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create temporary intent table: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(Encode(table)); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write intent table: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("sync intent table: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close intent table: %w", err)
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return fmt.Errorf("chmod intent table: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("rename intent table: %w", err)
+	}
+	return nil
+}
+
+// ReadFile reads and decodes the table at path
+func ReadFile(path string) (*Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(data)
+}
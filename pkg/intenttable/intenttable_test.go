@@ -0,0 +1,60 @@
+package intenttable
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWriteAndReadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "intents.bin")
+	entries := []Entry{
+		{PID: 300, Priority: 1, ExecutionTime: 5000},
+		{PID: 100, TID: 102, Priority: 10, ExecutionTime: 20000},
+		{PID: 100, TID: 101, Priority: 10, ExecutionTime: 20000},
+	}
+	SortEntries(entries)
+	table := &Table{Generation: 7, UpdatedAt: 1700000000000000000, Entries: entries}
+	if err := WriteFile(path, table); err != nil {
+		t.Fatalf("write table: %v", err)
+	}
+
+	got, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("read table: %v", err)
+	}
+	if !reflect.DeepEqual(table, got) {
+		t.Fatalf("unexpected table %+v, want %+v", got, table)
+	}
+	if got.Entries[0].TID != 101 || got.Entries[2].PID != 300 {
+		t.Fatalf("entries not sorted by pid and tid: %+v", got.Entries)
+	}
+
+	matches, err := filepath.Glob(filepath.Join(filepath.Dir(path), ".*"))
+	if err != nil {
+		t.Fatalf("glob: %v", err)
+	}
+	if len(matches) != 0 {
+		t.Fatalf("temporary files left behind: %v", matches)
+	}
+}
+
+func TestDecodeRejectsInvalidData(t *testing.T) {
+	valid := Encode(&Table{Entries: []Entry{{PID: 1}}})
+
+	badMagic := append([]byte{}, valid...)
+	badMagic[0] = 'X'
+	badVersion := append([]byte{}, valid...)
+	badVersion[4] = Version + 1
+
+	for name, data := range map[string][]byte{
+		"short":     valid[:HeaderSize-1],
+		"magic":     badMagic,
+		"version":   badVersion,
+		"truncated": valid[:len(valid)-1],
+	} {
+		if _, err := Decode(data); err == nil {
+			t.Fatalf("%s: expected decode error", name)
+		}
+	}
+}
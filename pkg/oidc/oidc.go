@@ -0,0 +1,355 @@
+// Package oidc implements the relying party side of the OpenID Connect authorization code flow
+// with PKCE: provider discovery, authorization URLs, code exchange and ID token verification.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	DefaultTimeout = 10 * time.Second
+	// clockSkew is tolerated when validating the time based claims of ID tokens
+	clockSkew = time.Minute
+	// minKeyRefreshInterval limits refetching the key set when ID tokens name unknown key IDs
+	minKeyRefreshInterval = 10 * time.Second
+)
+
+// Config describes the client registration at an OpenID provider
+type Config struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	// Scopes are requested in addition to "openid"
+	Scopes []string
+	// HTTPClient sends discovery, token and key set requests, nil uses a client with DefaultTimeout
+	HTTPClient *http.Client
+}
+
+// Metadata is the subset of the provider configuration document used by the flow
+type Metadata struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+// Token is the response of the token endpoint
+type Token struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// IDToken is a verified ID token
+type IDToken struct {
+	Issuer   string
+	Subject  string
+	Audience []string
+	Expiry   time.Time
+	IssuedAt time.Time
+	Nonce    string
+	// Claims holds every claim of the token, including the registered ones
+	Claims map[string]any
+}
+
+// StringClaim returns the claim name when it is a string
+func (t *IDToken) StringClaim(name string) string {
+	value, _ := t.Claims[name].(string)
+	return value
+}
+
+// StringsClaim returns the claim name as a list, a string claim is a list of one element
+func (t *IDToken) StringsClaim(name string) []string {
+	switch value := t.Claims[name].(type) {
+	case string:
+		return []string{value}
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// Provider talks to an OpenID provider, the configuration document is discovered on first use
+// and the key set is cached until an ID token names a key it doesn't contain
+type Provider struct {
+	cfg    Config
+	client *http.Client
+
+	mu            sync.Mutex
+	metadata      *Metadata
+	keys          map[string]any
+	keysFetchedAt time.Time
+}
+
+func NewProvider(cfg Config) *Provider {
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	cfg.IssuerURL = strings.TrimSuffix(cfg.IssuerURL, "/")
+	return &Provider{cfg: cfg, client: client}
+}
+
+// Metadata returns the provider configuration, discovering it from the issuer when it wasn't yet
+func (p *Provider) Metadata(ctx context.Context) (*Metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.metadataLocked(ctx)
+}
+
+func (p *Provider) metadataLocked(ctx context.Context) (*Metadata, error) {
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+	var metadata Metadata
+	if err := p.getJSON(ctx, p.cfg.IssuerURL+"/.well-known/openid-configuration", &metadata); err != nil {
+		return nil, fmt.Errorf("discover provider %s: %w", p.cfg.IssuerURL, err)
+	}
+	if strings.TrimSuffix(metadata.Issuer, "/") != p.cfg.IssuerURL {
+		return nil, fmt.Errorf("provider issuer %q does not match %q", metadata.Issuer, p.cfg.IssuerURL)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, fmt.Errorf("provider %s configuration lacks required endpoints", p.cfg.IssuerURL)
+	}
+	if len(metadata.CodeChallengeMethodsSupported) > 0 && !slices.Contains(metadata.CodeChallengeMethodsSupported, "S256") {
+		return nil, fmt.Errorf("provider %s does not support the S256 code challenge method", p.cfg.IssuerURL)
+	}
+	p.metadata = &metadata
+	return p.metadata, nil
+}
+
+// AuthCodeURL returns the authorization endpoint URL the user agent is redirected to, the
+// code challenge is derived from codeVerifier
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	metadata, err := p.Metadata(ctx)
+	if err != nil {
+		return "", err
+	}
+	authURL, err := url.Parse(metadata.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("parse authorization endpoint: %w", err)
+	}
+	scopes := []string{"openid"}
+	for _, scope := range p.cfg.Scopes {
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.cfg.ClientID)
+	query.Set("redirect_uri", p.cfg.RedirectURL)
+	query.Set("scope", strings.Join(scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", S256Challenge(codeVerifier))
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+	return authURL.String(), nil
+}
+
+// Exchange redeems an authorization code at the token endpoint
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier string) (*Token, error) {
+	metadata, err := p.Metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+	basicAuth := p.cfg.ClientSecret != "" && (len(metadata.TokenEndpointAuthMethodsSupported) == 0 ||
+		slices.Contains(metadata.TokenEndpointAuthMethodsSupported, "client_secret_basic"))
+	if !basicAuth {
+		form.Set("client_id", p.cfg.ClientID)
+		if p.cfg.ClientSecret != "" {
+			form.Set("client_secret", p.cfg.ClientSecret)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if basicAuth {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+	var token Token
+	if err := p.doJSON(req, &token); err != nil {
+		return nil, fmt.Errorf("exchange authorization code: %w", err)
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("token response contains no ID token")
+	}
+	return &token, nil
+}
+
+// VerifyIDToken checks the signature, issuer, audience, lifetime and nonce of an ID token
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*IDToken, error) {
+	metadata, err := p.Metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(metadata.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkew),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("verify ID token: %w", err)
+	}
+
+	idToken := &IDToken{Claims: claims}
+	idToken.Issuer, _ = claims.GetIssuer()
+	idToken.Subject, _ = claims.GetSubject()
+	idToken.Audience, _ = claims.GetAudience()
+	if exp, _ := claims.GetExpirationTime(); exp != nil {
+		idToken.Expiry = exp.Time
+	}
+	if iat, _ := claims.GetIssuedAt(); iat != nil {
+		idToken.IssuedAt = iat.Time
+	}
+	idToken.Nonce = idToken.StringClaim("nonce")
+	if idToken.Subject == "" {
+		return nil, errors.New("ID token has no subject")
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("ID token nonce does not match")
+	}
+	if len(idToken.Audience) > 1 {
+		if azp := idToken.StringClaim("azp"); azp != p.cfg.ClientID {
+			return nil, fmt.Errorf("ID token authorized party %q is not the client", azp)
+		}
+	}
+	return idToken, nil
+}
+
+// key returns the verification key kid of the provider key set, an empty kid selects the only key
+func (p *Provider) key(ctx context.Context, kid string) (any, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key, ok := lookupKey(p.keys, kid); ok {
+		return key, nil
+	}
+	if p.keys != nil && time.Since(p.keysFetchedAt) < minKeyRefreshInterval {
+		return nil, fmt.Errorf("key %q not found in provider key set", kid)
+	}
+	metadata, err := p.metadataLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var set jsonWebKeySet
+	if err := p.getJSON(ctx, metadata.JWKSURI, &set); err != nil {
+		return nil, fmt.Errorf("fetch provider key set: %w", err)
+	}
+	p.keys = set.publicKeys()
+	p.keysFetchedAt = time.Now()
+	if key, ok := lookupKey(p.keys, kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("key %q not found in provider key set", kid)
+}
+
+func lookupKey(keys map[string]any, kid string) (any, bool) {
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, true
+		}
+	}
+	key, ok := keys[kid]
+	return key, ok
+}
+
+func (p *Provider) getJSON(ctx context.Context, url string, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	return p.doJSON(req, dst)
+}
+
+func (p *Provider) doJSON(req *http.Request, dst any) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s returned status %d: %s", req.Method, req.URL.Redacted(), resp.StatusCode, truncate(string(body), 512))
+	}
+	if err := json.Unmarshal(body, dst); err != nil {
+		return fmt.Errorf("decode response of %s: %w", req.URL.Redacted(), err)
+	}
+	return nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
+
+// GenerateVerifier returns a random PKCE code verifier
+func GenerateVerifier() (string, error) {
+	return randomString(32)
+}
+
+// GenerateState returns a random value for the state and nonce parameters
+func GenerateState() (string, error) {
+	return randomString(24)
+}
+
+func randomString(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// S256Challenge returns the S256 PKCE code challenge of verifier
+func S256Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
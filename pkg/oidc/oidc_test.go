@@ -0,0 +1,161 @@
+package oidc_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Gthulhu/api/pkg/oidc"
+	"github.com/Gthulhu/api/pkg/oidc/oidctest"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newProvider(t *testing.T) (*oidctest.Server, *oidc.Provider) {
+	t.Helper()
+	idp := oidctest.NewServer("gthulhu", "s3cret")
+	t.Cleanup(idp.Close)
+	provider := oidc.NewProvider(oidc.Config{
+		IssuerURL:    idp.Issuer(),
+		ClientID:     "gthulhu",
+		ClientSecret: "s3cret",
+		RedirectURL:  "http://manager.local/callback",
+		Scopes:       []string{"profile", "groups"},
+	})
+	return idp, provider
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	idp, provider := newProvider(t)
+	idp.SetClaims(map[string]any{"sub": "42", "preferred_username": "alice", "groups": []string{"sre", "dev"}})
+	ctx := context.Background()
+
+	verifier, err := oidc.GenerateVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", verifier)
+	if err != nil {
+		t.Fatalf("auth code URL: %v", err)
+	}
+	parsed, _ := url.Parse(authURL)
+	if got := parsed.Query().Get("scope"); got != "openid profile groups" {
+		t.Fatalf("unexpected scope %q", got)
+	}
+	if parsed.Query().Get("code_challenge") != oidc.S256Challenge(verifier) {
+		t.Fatalf("code challenge is not derived from the verifier")
+	}
+
+	code, state, err := idp.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	if state != "state-1" {
+		t.Fatalf("unexpected state %q", state)
+	}
+	token, err := provider.Exchange(ctx, code, verifier)
+	if err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+	idToken, err := provider.VerifyIDToken(ctx, token.IDToken, "nonce-1")
+	if err != nil {
+		t.Fatalf("verify ID token: %v", err)
+	}
+	if idToken.Subject != "42" || idToken.StringClaim("preferred_username") != "alice" {
+		t.Fatalf("unexpected ID token %+v", idToken)
+	}
+	if groups := idToken.StringsClaim("groups"); len(groups) != 2 || groups[0] != "sre" || groups[1] != "dev" {
+		t.Fatalf("unexpected groups %v", groups)
+	}
+
+	if _, err := provider.Exchange(ctx, code, verifier); err == nil {
+		t.Fatalf("authorization code was redeemed twice")
+	}
+}
+
+func TestExchangeRequiresCodeVerifier(t *testing.T) {
+	idp, provider := newProvider(t)
+	ctx := context.Background()
+	verifier, _ := oidc.GenerateVerifier()
+	authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _, err := idp.Authorize(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, _ := oidc.GenerateVerifier()
+	if _, err := provider.Exchange(ctx, code, other); err == nil {
+		t.Fatalf("code was exchanged with the wrong verifier")
+	}
+}
+
+func TestVerifyIDTokenRejectsInvalidTokens(t *testing.T) {
+	idp, provider := newProvider(t)
+	ctx := context.Background()
+	now := time.Now()
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{"iss": idp.Issuer(), "aud": "gthulhu", "sub": "42", "nonce": "n", "iat": now.Unix(), "exp": now.Add(time.Minute).Unix()}
+	}
+	sign := func(claims jwt.MapClaims) string {
+		raw, err := idp.SignIDToken(claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return raw
+	}
+	if _, err := provider.VerifyIDToken(ctx, sign(valid()), "n"); err != nil {
+		t.Fatalf("valid token rejected: %v", err)
+	}
+
+	cases := map[string]func(jwt.MapClaims){
+		"wrong issuer":   func(c jwt.MapClaims) { c["iss"] = "https://evil.example" },
+		"wrong audience": func(c jwt.MapClaims) { c["aud"] = "other" },
+		"expired":        func(c jwt.MapClaims) { c["exp"] = now.Add(-time.Hour).Unix() },
+		"no expiry":      func(c jwt.MapClaims) { delete(c, "exp") },
+		"wrong nonce":    func(c jwt.MapClaims) { c["nonce"] = "other" },
+		"no subject":     func(c jwt.MapClaims) { delete(c, "sub") },
+		"foreign azp":    func(c jwt.MapClaims) { c["aud"] = []string{"gthulhu", "other"}; c["azp"] = "other" },
+	}
+	for name, mutate := range cases {
+		t.Run(name, func(t *testing.T) {
+			claims := valid()
+			mutate(claims)
+			if _, err := provider.VerifyIDToken(ctx, sign(claims), "n"); err == nil {
+				t.Fatalf("token accepted")
+			}
+		})
+	}
+
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, valid())
+	raw, _ := forged.SignedString([]byte("s3cret"))
+	if _, err := provider.VerifyIDToken(ctx, raw, "n"); err == nil {
+		t.Fatalf("HMAC signed token accepted")
+	}
+}
+
+func TestDiscoveryRejectsIssuerMismatch(t *testing.T) {
+	idp := oidctest.NewServer("gthulhu", "")
+	defer idp.Close()
+	provider := oidc.NewProvider(oidc.Config{IssuerURL: idp.Issuer() + "/other", ClientID: "gthulhu"})
+	if _, err := provider.Metadata(context.Background()); err == nil {
+		t.Fatalf("metadata of another issuer accepted")
+	}
+}
+
+func TestJSONWebKeyThumbprint(t *testing.T) {
+	// example of RFC 7638 section 3.1
+	jwk := oidc.JSONWebKey{
+		KeyType: "RSA",
+		N:       "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+		E:       "AQAB",
+	}
+	thumbprint, err := jwk.Thumbprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if thumbprint != "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs" {
+		t.Fatalf("unexpected thumbprint %s", thumbprint)
+	}
+}
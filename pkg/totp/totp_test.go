@@ -0,0 +1,60 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRFC6238Vectors checks the SHA1 test vectors of RFC 6238 appendix B, truncated to 6 digits
+func TestRFC6238Vectors(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, want := range vectors {
+		got, err := Code(secret, Step(time.Unix(unix, 0)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("code at %d: got %s, want %s", unix, got, want)
+		}
+	}
+}
+
+func TestValidateAcceptsSkew(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	current := Step(now)
+	for delta := int64(-2); delta <= 2; delta++ {
+		code, _ := Code(secret, current+delta)
+		step, ok := Validate(secret, code, now)
+		wantOK := delta >= -Skew && delta <= Skew
+		if ok != wantOK {
+			t.Errorf("delta %d: ok %v, want %v", delta, ok, wantOK)
+		}
+		if ok && step != current+delta {
+			t.Errorf("delta %d: matched step %d, want %d", delta, step, current+delta)
+		}
+	}
+	if _, ok := Validate(secret, "12345", now); ok {
+		t.Errorf("short code accepted")
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("Gthulhu", "alice@example.com", "JBSWY3DPEHPK3PXP")
+	if !strings.HasPrefix(uri, "otpauth://totp/Gthulhu:alice@example.com?") || !strings.Contains(uri, "secret=JBSWY3DPEHPK3PXP") {
+		t.Fatalf("unexpected URI %s", uri)
+	}
+}
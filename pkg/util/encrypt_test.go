@@ -93,3 +93,17 @@ X6m7Mp9nAMhRyXhULslO3trWFbFCa2dbQkDSyBRvsb2HZtztoLVyo1mtUg==
 	require.NotNil(t, publicKey)
 	t.Log(publicKey)
 }
+
+func TestAESGCM(t *testing.T) {
+	key := make([]byte, 32)
+	ciphertext, err := EncryptAESGCM(key, []byte("JBSWY3DPEHPK3PXP"))
+	require.NoError(t, err)
+
+	plaintext, err := DecryptAESGCM(key, ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", string(plaintext))
+
+	key[0] = 1
+	_, err = DecryptAESGCM(key, ciphertext)
+	assert.Error(t, err, "a different key must not open the ciphertext")
+}
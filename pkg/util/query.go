@@ -0,0 +1,34 @@
+package util
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ParseTimeParam parses an optional query parameter given as RFC3339 or unix seconds
+func ParseTimeParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if unix, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(unix, 0), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// ParseMetricHistoryQuery parses the since and step query parameters of the metric history endpoints,
+// since accepts RFC3339 or unix seconds and step a Go duration; both are optional
+func ParseMetricHistoryQuery(query url.Values) (since time.Time, step time.Duration, err error) {
+	if since, err = ParseTimeParam(query.Get("since")); err != nil {
+		return time.Time{}, 0, fmt.Errorf("invalid since %q, expected RFC3339 or unix seconds", query.Get("since"))
+	}
+	if value := query.Get("step"); value != "" {
+		step, err = time.ParseDuration(value)
+		if err != nil || step < 0 {
+			return time.Time{}, 0, fmt.Errorf("invalid step %q, expected a non-negative duration such as 10s", value)
+		}
+	}
+	return since, step, nil
+}
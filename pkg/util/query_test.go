@@ -0,0 +1,39 @@
+package util
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimeParam(t *testing.T) {
+	ts, err := ParseTimeParam("")
+	require.NoError(t, err)
+	assert.True(t, ts.IsZero())
+
+	ts, err = ParseTimeParam("1735689600")
+	require.NoError(t, err)
+	assert.True(t, ts.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	ts, err = ParseTimeParam("2025-01-01T08:00:00+08:00")
+	require.NoError(t, err)
+	assert.True(t, ts.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	_, err = ParseTimeParam("yesterday")
+	require.Error(t, err)
+}
+
+func TestParseMetricHistoryQuery(t *testing.T) {
+	since, step, err := ParseMetricHistoryQuery(url.Values{"since": {"1735689600"}, "step": {"10s"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1735689600), since.Unix())
+	assert.Equal(t, 10*time.Second, step)
+
+	_, _, err = ParseMetricHistoryQuery(url.Values{"since": {"yesterday"}})
+	require.Error(t, err)
+	_, _, err = ParseMetricHistoryQuery(url.Values{"step": {"-1s"}})
+	require.Error(t, err)
+}
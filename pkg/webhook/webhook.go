@@ -0,0 +1,155 @@
+// Package webhook delivers JSON payloads to HTTP endpoints, retrying failed deliveries with
+// exponential backoff and optionally signing them with HMAC-SHA256.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultMaxAttempts    = 5
+	DefaultInitialBackoff = time.Second
+	DefaultTimeout        = 10 * time.Second
+	maxBackoff            = time.Minute
+
+	// SignatureHeader carries "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>"
+	SignatureHeader = "X-Gthulhu-Signature"
+	// TimestampHeader carries the unix seconds the signature was computed at, receivers should
+	// reject old timestamps to prevent replays
+	TimestampHeader = "X-Gthulhu-Timestamp"
+)
+
+// Sender posts payloads to webhooks
+type Sender struct {
+	Client         *http.Client
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	UserAgent      string
+}
+
+// NewSender returns a Sender with the default retry policy whose requests time out after timeout
+func NewSender(timeout time.Duration) *Sender {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Sender{
+		Client:         &http.Client{Timeout: timeout},
+		MaxAttempts:    DefaultMaxAttempts,
+		InitialBackoff: DefaultInitialBackoff,
+		UserAgent:      "gthulhu-webhook",
+	}
+}
+
+// StatusError is returned when the webhook answered with a non-2xx status
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("webhook returned status %d: %s", e.StatusCode, e.Body)
+}
+
+// retryable reports whether a delivery that failed with err may succeed later
+func retryable(err error) bool {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return true
+	}
+	return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= http.StatusInternalServerError
+}
+
+// Send posts payload as JSON to url, retrying network errors, 429 and 5xx responses until
+// MaxAttempts deliveries were made or ctx is done
+func (s *Sender) Send(ctx context.Context, url string, payload any, headers map[string]string) error {
+	return s.SendSigned(ctx, url, payload, "", headers)
+}
+
+// SendSigned is Send with the SignatureHeader and TimestampHeader of every attempt computed
+// with secret, an empty secret sends the payload unsigned
+func (s *Sender) SendSigned(ctx context.Context, url string, payload any, secret string, headers map[string]string) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal webhook payload: %w", err)
+	}
+	maxAttempts := s.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	backoff := s.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		err = s.post(ctx, url, body, secret, headers)
+		if err == nil {
+			return nil
+		}
+		if attempt >= maxAttempts || !retryable(err) {
+			return fmt.Errorf("deliver webhook after %d attempts: %w", attempt, err)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("deliver webhook: %w, last error: %v", ctx.Err(), err)
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// Sign returns the SignatureHeader value of body sent at timestamp
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the SignatureHeader value of body sent at timestamp
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+func (s *Sender) post(ctx context.Context, url string, body []byte, secret string, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.UserAgent != "" {
+		req.Header.Set("User-Agent", s.UserAgent)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	if secret != "" {
+		timestamp := time.Now().Unix()
+		req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+		req.Header.Set(SignatureHeader, Sign(secret, timestamp, body))
+	}
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &StatusError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,87 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSendRetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var payload map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload["status"] != "firing" {
+			t.Errorf("unexpected payload %v, err %v", payload, err)
+		}
+		if r.Header.Get("X-Test") != "1" {
+			t.Errorf("missing custom header")
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sender := NewSender(time.Second)
+	sender.InitialBackoff = time.Millisecond
+	err := sender.Send(context.Background(), server.URL, map[string]string{"status": "firing"}, map[string]string{"X-Test": "1"})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls.Load())
+	}
+}
+
+func TestSendDoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	sender := NewSender(time.Second)
+	sender.InitialBackoff = time.Millisecond
+	err := sender.Send(context.Background(), server.URL, map[string]string{}, nil)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status error 400, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected a single attempt, got %d", calls.Load())
+	}
+}
+
+func TestSendSignedAddsVerifiableSignature(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, err := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+		if err != nil {
+			t.Errorf("invalid timestamp header: %v", err)
+		}
+		signature := r.Header.Get(SignatureHeader)
+		if !Verify("s3cret", timestamp, body, signature) {
+			t.Errorf("signature %q does not verify", signature)
+		}
+		if Verify("other", timestamp, body, signature) {
+			t.Errorf("signature verifies with the wrong secret")
+		}
+	}))
+	defer server.Close()
+
+	sender := NewSender(time.Second)
+	err := sender.SendSigned(context.Background(), server.URL, map[string]string{"type": "strategy.created"}, "s3cret", nil)
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+}
@@ -78,6 +78,8 @@ export default function StrategiesCard() {
       priority: strategy.Priority || 0,
       executionTime: strategy.ExecutionTime || 0,
       commandRegex: strategy.CommandRegex || '',
+      commandMatchTargets: strategy.CommandMatchTargets || [],
+      threadRegex: strategy.ThreadRegex || '',
       k8sNamespace: strategy.K8sNamespace ? strategy.K8sNamespace.join(', ') : '',
       selectors
     });
@@ -292,6 +294,14 @@ export default function StrategiesCard() {
       payload.commandRegex = editStrategy.commandRegex.trim();
     }
 
+    if (editStrategy.commandMatchTargets.length > 0) {
+      payload.commandMatchTargets = editStrategy.commandMatchTargets;
+    }
+
+    if (editStrategy.threadRegex.trim()) {
+      payload.threadRegex = editStrategy.threadRegex.trim();
+    }
+
     if (editStrategy.k8sNamespace.trim()) {
       payload.k8sNamespace = editStrategy.k8sNamespace.split(',').map(ns => ns.trim()).filter(ns => ns);
     }
@@ -9,6 +9,9 @@ export default function LoginModal() {
   const [password, setPassword] = useState('');
   const [loading, setLoading] = useState(false);
   const [error, setError] = useState('');
+  // twoFactorToken is set once the password was accepted for a user with two-factor authentication
+  const [twoFactorToken, setTwoFactorToken] = useState('');
+  const [code, setCode] = useState('');
   const emailRef = useRef(null);
 
   // Auto-show login modal when not authenticated
@@ -49,6 +52,8 @@ export default function LoginModal() {
     setIsOpen(false);
     setEmail('');
     setPassword('');
+    setTwoFactorToken('');
+    setCode('');
     setError('');
     setLoading(false);
   };
@@ -63,8 +68,8 @@ export default function LoginModal() {
   const handleSubmit = async (e) => {
     e.preventDefault();
     
-    if (!email || !password) {
-      setError('Please enter both email and password');
+    if (twoFactorToken ? !code : !email || !password) {
+      setError(twoFactorToken ? 'Please enter the authentication code' : 'Please enter both email and password');
       return;
     }
     
@@ -72,17 +77,28 @@ export default function LoginModal() {
     setError('');
     
     try {
-      const response = await fetch(getApiUrl('/api/v1/auth/login'), {
-        method: 'POST',
-        headers: {
-          'Content-Type': 'application/json',
-        },
-        body: JSON.stringify({ username: email, password })
-      });
+      const response = twoFactorToken
+        ? await fetch(getApiUrl('/api/v1/auth/2fa'), {
+            method: 'POST',
+            headers: {
+              'Content-Type': 'application/json',
+            },
+            body: JSON.stringify({ twoFactorToken, code })
+          })
+        : await fetch(getApiUrl('/api/v1/auth/login'), {
+            method: 'POST',
+            headers: {
+              'Content-Type': 'application/json',
+            },
+            body: JSON.stringify({ username: email, password })
+          });
       
       const data = await response.json();
       
-      if (response.ok && data.success && data.data && data.data.token) {
+      if (response.ok && data.success && data.data && data.data.twoFactorRequired) {
+        setTwoFactorToken(data.data.twoFactorToken);
+        setCode('');
+      } else if (response.ok && data.success && data.data && data.data.token) {
         login(data.data.token);
         handleClose();
         showToast('success', 'Authentication successful!');
@@ -117,48 +133,71 @@ export default function LoginModal() {
         </div>
         <div className="modal-body">
           <form onSubmit={handleSubmit}>
-            <div className="input-group">
-              <label htmlFor="email">
-                <span className="label-icon"><Mail size={14} /></span>
-                Email Address
-              </label>
-              <input 
-                type="email" 
-                id="email" 
-                name="email" 
-                placeholder="admin@gthulhu.io" 
-                required 
-                autoComplete="email"
-                ref={emailRef}
-                value={email}
-                onChange={(e) => setEmail(e.target.value)}
-              />
-              <div className="input-glow"></div>
-            </div>
-            <div className="input-group">
-              <label htmlFor="password">
-                <span className="label-icon"><Key size={14} /></span>
-                Password
-              </label>
-              <input 
-                type="password" 
-                id="password" 
-                name="password" 
-                placeholder="••••••••" 
-                required 
-                autoComplete="current-password"
-                value={password}
-                onChange={(e) => setPassword(e.target.value)}
-              />
-              <div className="input-glow"></div>
-            </div>
+            {twoFactorToken ? (
+              <div className="input-group">
+                <label htmlFor="code">
+                  <span className="label-icon"><Shield size={14} /></span>
+                  Authentication Code
+                </label>
+                <input 
+                  type="text" 
+                  id="code" 
+                  name="code" 
+                  placeholder="6-digit code or recovery code" 
+                  required 
+                  autoFocus
+                  autoComplete="one-time-code"
+                  value={code}
+                  onChange={(e) => setCode(e.target.value)}
+                />
+                <div className="input-glow"></div>
+              </div>
+            ) : (
+              <>
+                <div className="input-group">
+                  <label htmlFor="email">
+                    <span className="label-icon"><Mail size={14} /></span>
+                    Email Address
+                  </label>
+                  <input 
+                    type="email" 
+                    id="email" 
+                    name="email" 
+                    placeholder="admin@gthulhu.io" 
+                    required 
+                    autoComplete="email"
+                    ref={emailRef}
+                    value={email}
+                    onChange={(e) => setEmail(e.target.value)}
+                  />
+                  <div className="input-glow"></div>
+                </div>
+                <div className="input-group">
+                  <label htmlFor="password">
+                    <span className="label-icon"><Key size={14} /></span>
+                    Password
+                  </label>
+                  <input 
+                    type="password" 
+                    id="password" 
+                    name="password" 
+                    placeholder="••••••••" 
+                    required 
+                    autoComplete="current-password"
+                    value={password}
+                    onChange={(e) => setPassword(e.target.value)}
+                  />
+                  <div className="input-glow"></div>
+                </div>
+              </>
+            )}
             <div className="form-actions">
               <button 
                 type="submit" 
                 className={`submit-btn ${loading ? 'loading' : ''}`}
                 disabled={loading}
               >
-                <span className="btn-text">Login</span>
+                <span className="btn-text">{twoFactorToken ? 'Verify' : 'Login'}</span>
                 <span className="btn-loader"></span>
               </button>
             </div>